@@ -15,6 +15,12 @@ func GetPrompt() (string, error) {
 	return string(b), err
 }
 
+// GetStandupPrompt returns the prompt template for standup summaries
+func GetStandupPrompt() (string, error) {
+	b, err := Files.ReadFile("prompts/standup_prompt.txt")
+	return string(b), err
+}
+
 // GetOllamaWarning returns the Ollama warning message
 func GetOllamaWarning() (string, error) {
 	b, err := Files.ReadFile("messages/ollama_warning.txt")
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+// Staged changes within these bounds are small enough to offer amending them
+// into HEAD instead of committing them separately
+const (
+	amendMaxFiles = 3
+	amendMaxLines = 20
+)
+
+// canAmend reports whether the staged changes are tiny and HEAD is a commit
+// no remote branch contains, so it can be amended safely
+func canAmend(ctx context.Context, changes []*parser.Change) bool {
+	lines := 0
+	for _, change := range changes {
+		lines += change.Added + change.Removed
+	}
+	if len(changes) > amendMaxFiles || lines > amendMaxLines {
+		return false
+	}
+	// Merge commits are not amended
+	if _, err := parser.ResolveRevision(ctx, "HEAD"); err != nil {
+		return false
+	}
+	if _, err := parser.ResolveRevision(ctx, "HEAD^2"); err == nil {
+		return false
+	}
+	pushed, err := parser.IsPushed(ctx, "HEAD")
+	return err == nil && !pushed
+}
+
+// amendAnalysis analyzes the changes of HEAD combined with the staged ones, or
+// returns nil when together they change nothing
+func amendAnalysis(ctx context.Context, cfg *config.Config, branchName string) (*analyzer.CommitMessage, error) {
+	gitParser := parser.NewGitParser()
+	changes, err := gitParser.ParseAmendChanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, commitMessage, err := analyzeChanges(ctx, cfg, gitParser, changes, branchName)
+	return commitMessage, err
+}
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/changelog"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/formatter"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/stats"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	analyzeJSONFlag   bool
+	analyzeCSVFlag    bool
+	analyzeSinceFlag  string
+	analyzeUntilFlag  string
+	analyzeAuthorFlag string
+	analyzeTopFlag    int
+	analyzeMaxFlag    int
+	analyzeWeeksFlag  int
+	analyzeFollowFlag bool
+	analyzeByDirFlag  bool
+	analyzeNoMerges   bool
+	analyzeAllAuthors bool
+
+	analyzeCmd = &cobra.Command{
+		Use:   "analyze",
+		Short: "Show statistics of the commit history",
+		Long: `Read the commits reachable from HEAD and show how many there are, their
+conventional commit types, who works on what and in which scopes, the most
+active files and the recent activity. Each author and conventional scope is
+listed with its commits per type, which shows whether fixes cluster in some
+scopes.
+
+A compliance report shows the share of commits following the conventions: a
+conventional type prefix with a known type, the subject length limit and the
+configured message rules. The most common violations are listed, with the
+compliance per month to follow a gradual adoption.
+
+Commits of bots such as dependabot and renovate are left out, along with the
+authors in analyze.ignoreAuthors, unless --all-authors is given; --no-merges
+leaves out merge commits.
+
+With --follow renamed files are detected, so files keep their commits from
+before a rename under their current path; --by-dir counts commits per
+directory instead of per file.
+
+Recent activity is drawn as a sparkline of commits per week, with the rolling
+velocity over 4 weeks and the average commits per day of the week.
+
+--since and --until limit the commits to a time range and take any date git
+understands, such as "2024-01-01" or "3 months ago", and --author to the
+authors whose name or email matches a pattern. With --json or --csv the
+statistics are printed in a form dashboards and scripts can read.
+
+The history is read in a single pass without keeping the commits, so large
+repositories can be analyzed; --max-commits limits it to the most recent ones.`,
+		Example: `  gitmit analyze
+  gitmit analyze --since "3 months ago" --json
+  gitmit analyze --author alice@example.com
+  gitmit analyze --since 2024-01-01 --until 2024-06-30 --csv > stats.csv`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runAnalyze,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(analyzeCmd)
+	analyzeCmd.Flags().BoolVar(&analyzeJSONFlag, "json", false, "Print the statistics as JSON")
+	analyzeCmd.Flags().BoolVar(&analyzeCSVFlag, "csv", false, "Print the statistics as CSV rows of section, name and commits")
+	analyzeCmd.Flags().StringVar(&analyzeSinceFlag, "since", "", `Only count commits after this date, e.g. "2024-01-01" or "3 months ago"`)
+	analyzeCmd.Flags().StringVar(&analyzeUntilFlag, "until", "", "Only count commits before this date")
+	analyzeCmd.Flags().StringVar(&analyzeAuthorFlag, "author", "", "Only count commits of authors whose name or email matches this pattern")
+	analyzeCmd.Flags().IntVar(&analyzeTopFlag, "top", 10, "Number of authors, scopes and files listed (0 for all)")
+	analyzeCmd.Flags().IntVar(&analyzeMaxFlag, "max-commits", 0, "Only count this many of the most recent commits (0 for all)")
+	analyzeCmd.Flags().IntVar(&analyzeWeeksFlag, "weeks", 12, "Number of weeks in the activity sparkline")
+	analyzeCmd.Flags().BoolVar(&analyzeFollowFlag, "follow", false, "Count the commits of renamed files under their current path")
+	analyzeCmd.Flags().BoolVar(&analyzeByDirFlag, "by-dir", false, "List the most active directories instead of files")
+	analyzeCmd.Flags().BoolVar(&analyzeNoMerges, "no-merges", false, "Leave out merge commits")
+	analyzeCmd.Flags().BoolVar(&analyzeAllAuthors, "all-authors", false, "Count the commits of ignored authors and bots too")
+	analyzeCmd.MarkFlagsMutuallyExclusive("json", "csv")
+}
+
+func runAnalyze(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	collector := stats.NewCollector(stats.Options{
+		Top:   analyzeTopFlag,
+		Now:   time.Now(),
+		Weeks: analyzeWeeksFlag,
+		Check: complianceCheck(cfg),
+
+		Directories: analyzeByDirFlag,
+	})
+	ignored := 0
+	err = parser.StreamHistory(ctx, parser.HistoryOptions{
+		Since:      analyzeSinceFlag,
+		Until:      analyzeUntilFlag,
+		Author:     analyzeAuthorFlag,
+		MaxCommits: analyzeMaxFlag,
+		Renames:    analyzeFollowFlag,
+		NoMerges:   analyzeNoMerges,
+	}, func(commit *parser.Commit) error {
+		if !analyzeAllAuthors && cfg.Analyze.IgnoresAuthor(commit.Author, commit.Email) {
+			ignored++
+			return nil
+		}
+		collector.Add(changelog.ParseCommit(commit))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s := collector.Stats()
+	s.Ignored = ignored
+	s.Since, s.Until, s.Author = analyzeSinceFlag, analyzeUntilFlag, analyzeAuthorFlag
+
+	switch {
+	case analyzeJSONFlag:
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error encoding statistics: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	case analyzeCSVFlag:
+		return s.WriteCSV(os.Stdout)
+	}
+
+	if s.Commits == 0 {
+		ui.Warn("No commits found.")
+		return nil
+	}
+	printStats(s, analyzeByDirFlag)
+	return nil
+}
+
+// printStats shows the statistics as colored text, with directories rather than
+// files when byDir is set
+func printStats(s *stats.Stats, byDir bool) {
+	ui.Heading("📊 Commit statistics")
+	ui.Printf("Commits:      %d (%s to %s)\n", s.Commits, s.First.Format("2006-01-02"), s.Last.Format("2006-01-02"))
+	ui.Printf("Conventional: %d%%\n", s.ConventionalPercent())
+	if s.Ignored > 0 {
+		ui.Printf("Ignored:      %d commits of bots and ignored authors\n", s.Ignored)
+	}
+
+	ui.Accent("\nCommit types:")
+	for _, c := range s.Types {
+		ui.Printf("  %-10s %5d %s\n", c.Name, c.Commits, bar(c.Commits, s.Commits, 30))
+	}
+
+	ui.Accent("\nTop authors:")
+	for _, b := range s.Authors {
+		ui.Printf("  %5d  %s %s\n", b.Commits, b.Name, ui.MutedString("(%s)", typeSummary(b.Types)))
+	}
+
+	if len(s.Scopes) > 0 {
+		ui.Accent("\nScopes:")
+		for _, b := range s.Scopes {
+			line := fmt.Sprintf("  %5d  %-16s %s", b.Commits, b.Name, ui.MutedString("(%s)", typeSummary(b.Types)))
+			if fixes := b.TypeCommits("fix"); fixes > 0 {
+				line += fmt.Sprintf("  %d%% fixes", fixes*100/b.Commits)
+			}
+			ui.Println(line)
+		}
+	}
+
+	if byDir {
+		ui.Accent("\nMost active directories:")
+	} else {
+		ui.Accent("\nMost active files:")
+	}
+	for _, c := range s.Files {
+		ui.Printf("  %5d  %s\n", c.Commits, c.Name)
+	}
+
+	if c := s.Compliance; c != nil {
+		ui.Accent("\nCompliance:")
+		ui.Printf("  %d%% of commits follow the conventions\n", c.Percent(s.Commits))
+		if len(c.Violations) > 0 {
+			ui.Println("  Most common violations:")
+			for _, v := range c.Violations {
+				ui.Printf("  %5d  %s\n", v.Commits, ruleName(v.Name))
+			}
+		}
+		if trend := c.RecentTrend(); len(trend) > 1 {
+			ui.Println("  Trend:")
+			for _, p := range trend {
+				ui.Printf("    %s %4d%% %s\n", p.Name, p.Percent(), bar(p.Compliant, p.Commits, 20))
+			}
+		}
+	}
+
+	ui.Accent("\nRecent activity:")
+	ui.Printf("  %d commits in the last 7 days, %d in the last 30\n", s.LastWeek, s.LastMonth)
+	if len(s.Weekly) > 0 {
+		peak := 0
+		for _, week := range s.Weekly {
+			peak = max(peak, week.Commits)
+		}
+		ui.Printf("  Weekly:    %s %s\n", ui.SuccessString("%s", stats.Sparkline(s.Weekly)),
+			ui.MutedString("(%d weeks from %s, peak %d)", len(s.Weekly), s.Weekly[0].Name, peak))
+
+		velocity := fmt.Sprintf("  Velocity:  %.1f commits/week", s.Velocity[len(s.Velocity)-1])
+		if change, ok := s.VelocityChange(); ok {
+			switch {
+			case change > 0:
+				velocity += ui.SuccessString(" ↑ %d%%", change)
+			case change < 0:
+				velocity += ui.ErrorString(" ↓ %d%%", -change)
+			default:
+				velocity += " →"
+			}
+			velocity += " over the previous 4 weeks"
+		}
+		ui.Println(velocity)
+	}
+	var days []string
+	for _, d := range s.Weekdays {
+		days = append(days, fmt.Sprintf("%s %.1f", d.Day, d.Average))
+	}
+	if len(days) > 0 {
+		ui.Printf("  Per day:   %s\n", strings.Join(days, "  "))
+	}
+}
+
+// complianceRules describes the rules complianceCheck adds to the configured ones
+var complianceRules = map[string]string{
+	"conventional":     "no conventional type prefix",
+	"type":             "unknown commit type",
+	"maxSubjectLength": "subject longer than the limit",
+}
+
+// complianceCheck returns the rules a commit breaks: a conventional type prefix
+// with a known type, the subject length limit and the configured message rules
+func complianceCheck(cfg *config.Config) func(*changelog.Entry) []string {
+	f := formatter.NewFormatter(cfg.MaxSubjectLength, cfg.MaxBodyLength)
+	f.Rules = &cfg.Rules
+	return func(entry *changelog.Entry) []string {
+		if entry.Type == "" {
+			return []string{"conventional"}
+		}
+		var rules []string
+		if changelog.TypeTitle(entry.Type) == "" {
+			rules = append(rules, "type")
+		}
+		if cfg.MaxSubjectLength > 0 && utf8.RuneCountInString(entry.Commit.Subject) > cfg.MaxSubjectLength {
+			rules = append(rules, "maxSubjectLength")
+		}
+		for _, v := range f.Check(entry.Commit.Subject) {
+			rules = append(rules, v.Rule)
+		}
+		return rules
+	}
+}
+
+// ruleName shows a rule with its description, if it has one
+func ruleName(rule string) string {
+	if description, ok := complianceRules[rule]; ok {
+		return fmt.Sprintf("%s %s", rule, ui.MutedString("(%s)", description))
+	}
+	return rule
+}
+
+// typeSummary lists commits per type, as in "feat 5, fix 2"
+func typeSummary(types []stats.Count) string {
+	var parts []string
+	for _, c := range types {
+		parts = append(parts, fmt.Sprintf("%s %d", c.Name, c.Commits))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// bar draws n out of total as a bar of at most width characters
+func bar(n, total, width int) string {
+	if n == 0 || total == 0 {
+		return ""
+	}
+	return ui.SuccessString(strings.Repeat("█", max(n*width/total, 1)))
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	autosquashDryRunFlag bool
+	autosquashYesFlag    bool
+
+	autosquashCmd = &cobra.Command{
+		Use:   "autosquash",
+		Short: "Fold fixup commits into the commits they fix",
+		Long: `Find the fixup!, squash! and amend! commits among the commits no remote branch
+contains, such as those made with the fixup action of propose, and fold each
+into the commit it names with git rebase -i --autosquash. Uncommitted changes
+are stashed for the rebase and restored afterwards.
+
+Rewriting changes the hashes of the commits since the oldest fixed commit.
+Fixups of commits that were already pushed are left alone.`,
+		Example: `  gitmit autosquash --dry-run
+  gitmit autosquash --yes`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runAutosquash,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(autosquashCmd)
+	autosquashCmd.Flags().BoolVar(&autosquashDryRunFlag, "dry-run", false, "List the fixups without rewriting commits")
+	autosquashCmd.Flags().BoolVarP(&autosquashYesFlag, "yes", "y", false, "Rewrite without asking for confirmation")
+}
+
+// maxAutosquashCommits caps the unpushed commits searched for fixups
+const maxAutosquashCommits = 200
+
+// fixupPrefixes mark commits git rebase --autosquash folds into another
+var fixupPrefixes = []string{"fixup! ", "squash! ", "amend! "}
+
+// squash is a fixup commit and the commit it folds into
+type squash struct {
+	Fixup  *parser.Commit
+	Target *parser.Commit
+}
+
+func runAutosquash(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	commits, err := parser.UnpushedCommits(ctx, maxAutosquashCommits)
+	if err != nil {
+		return err
+	}
+	squashes, orphans := matchFixups(commits)
+	for _, orphan := range orphans {
+		ui.Warn("⚠ %s %q fixes no unpushed commit and is left alone", orphan.ShortHash(), orphan.Subject)
+	}
+	if len(squashes) == 0 {
+		fmt.Println("No fixup commits to squash.")
+		return nil
+	}
+
+	ui.Heading("Fixups to fold:")
+	oldest := squashes[0].Target
+	for _, s := range squashes {
+		fmt.Printf("  %s %s\n", s.Fixup.ShortHash(), s.Fixup.Subject)
+		fmt.Printf("    %s %s %s\n", ui.MutedString("→"), s.Target.ShortHash(), s.Target.Subject)
+		if isOlder(s.Target, oldest, commits) {
+			oldest = s.Target
+		}
+	}
+	fmt.Println()
+	if autosquashDryRunFlag {
+		return nil
+	}
+
+	if !autosquashYesFlag {
+		fmt.Printf("Rewrite the commits since %s? [y/N]: ", oldest.ShortHash())
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		fmt.Println()
+		if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+			ui.Warn("❌ Autosquash cancelled.")
+			return nil
+		}
+	}
+	return runAutosquashRebase(ctx, oldest)
+}
+
+// matchFixups pairs each fixup commit with the commit it names, found by subject
+// or hash among the older commits. Commits are listed newest first; fixups whose
+// target is not among them are returned separately.
+func matchFixups(commits []*parser.Commit) (squashes []squash, orphans []*parser.Commit) {
+	for i, commit := range commits {
+		subject, ok := fixupSubject(commit.Subject)
+		if !ok {
+			continue
+		}
+		var target *parser.Commit
+		for _, older := range commits[i+1:] {
+			if _, isFixup := fixupSubject(older.Subject); isFixup {
+				continue
+			}
+			if older.Subject == subject || (len(subject) >= 4 && strings.HasPrefix(older.Hash, subject)) {
+				target = older
+				break
+			}
+		}
+		if target == nil {
+			orphans = append(orphans, commit)
+			continue
+		}
+		squashes = append(squashes, squash{Fixup: commit, Target: target})
+	}
+	return squashes, orphans
+}
+
+// fixupSubject strips the fixup markers of a subject, reporting whether it had any
+func fixupSubject(subject string) (string, bool) {
+	found := false
+	for {
+		stripped := false
+		for _, prefix := range fixupPrefixes {
+			if strings.HasPrefix(subject, prefix) {
+				subject = strings.TrimPrefix(subject, prefix)
+				found, stripped = true, true
+			}
+		}
+		if !stripped {
+			return subject, found
+		}
+	}
+}
+
+// isOlder reports whether a comes after b in a newest-first list of commits
+func isOlder(a, b *parser.Commit, commits []*parser.Commit) bool {
+	for _, commit := range commits {
+		switch commit.Hash {
+		case a.Hash:
+			return false
+		case b.Hash:
+			return true
+		}
+	}
+	return false
+}
+
+// runAutosquashRebase rebases the commits since the parent of the oldest target
+// with --autosquash, accepting the todo list git prepares
+func runAutosquashRebase(ctx context.Context, oldest *parser.Commit) error {
+	current, err := parser.ResolveRevision(ctx, "HEAD")
+	if err != nil {
+		return err
+	}
+
+	rebaseArgs := []string{"rebase", "--interactive", "--autosquash", "--autostash"}
+	if base, err := parser.ResolveRevision(ctx, oldest.Hash+"^"); err == nil {
+		if out, err := gitcmd.Output(ctx, "", "rev-list", "--merges", base+"..HEAD"); err != nil {
+			return fmt.Errorf("error listing merge commits: %w", err)
+		} else if strings.TrimSpace(out) != "" {
+			return fmt.Errorf("the commits since %s contain merge commits, which squashing would flatten", oldest.ShortHash())
+		}
+		rebaseArgs = append(rebaseArgs, base)
+	} else {
+		rebaseArgs = append(rebaseArgs, "--root")
+	}
+
+	// The todo list git prepares with --autosquash is already the one wanted,
+	// so the sequence editor leaves it as is
+	rebaseCmd := gitcmd.LongCommand(ctx, rebaseArgs...)
+	rebaseCmd.Env = append(os.Environ(), "GIT_SEQUENCE_EDITOR=true")
+	rebaseCmd.Stdin = os.Stdin
+	rebaseCmd.Stdout = os.Stdout
+	rebaseCmd.Stderr = os.Stderr
+	if err := rebaseCmd.Run(); err != nil {
+		return fmt.Errorf("error squashing fixup commits (git rebase --abort restores %s): %w", current[:7], err)
+	}
+	ui.Success("✅ Fixup commits squashed. The previous tip was %s.", current[:7])
+	return nil
+}
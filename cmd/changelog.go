@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/changelog"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/gitcmd"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+// addChangelogEntry adds the entry of a feature, fix or breaking change to the
+// Unreleased section of the configured Keep a Changelog file and stages it, so
+// it is committed with the message. An interactive run asks first; reader is
+// nil otherwise, and the entry is added without asking.
+func addChangelogEntry(ctx context.Context, cfg *config.Config, reader *bufio.Reader, message string) error {
+	if !cfg.Changelog.Enabled {
+		return nil
+	}
+	subject, body, _ := strings.Cut(message, "\n")
+	section, text := changelog.UnreleasedEntry(changelog.ParseCommit(&parser.Commit{Subject: subject, Body: strings.TrimSpace(body)}))
+	if section == "" {
+		return nil
+	}
+
+	root, err := parser.RepoRoot(ctx, "")
+	if err != nil {
+		return err
+	}
+	file := cfg.Changelog.File
+	path := filepath.Join(root, file)
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		slog.Info("no changelog to add the entry to", "file", file)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading changelog: %w", err)
+	}
+	// Changes to the changelog are the user's own entries, and staging the
+	// entry would commit unstaged ones with it
+	if status, err := gitcmd.Output(ctx, root, "status", "--porcelain", "--", file); err != nil || strings.TrimSpace(status) != "" {
+		slog.Info("changelog left as it is, as it has changes of its own", "file", file, "error", err)
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading changelog: %w", err)
+	}
+	updated, added := changelog.AddUnreleased(string(content), section, text)
+	if !added {
+		return nil
+	}
+
+	if reader != nil {
+		ui.Printf("Add \"- %s\" to %s under Unreleased → %s? [Y/n]: ", text, file, section)
+		answer, _ := reader.ReadString('\n')
+		ui.Println()
+		if answer := strings.ToLower(strings.TrimSpace(answer)); answer != "" && answer != "y" {
+			return nil
+		}
+	}
+	if err := os.WriteFile(path, []byte(updated), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("error writing changelog: %w", err)
+	}
+	if _, err := gitcmd.Output(ctx, root, "add", "--", file); err != nil {
+		return fmt.Errorf("error staging changelog: %w", err)
+	}
+	ui.Success("📝 Added the entry to %s under Unreleased → %s.", file, section)
+	return nil
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/templater"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	configGlobalFlag bool
+
+	configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Read and write gitmit configuration values",
+		Long: `Read and write individual configuration keys without hand-editing the config file.
+
+Values are validated against the known keys and their types before being written.
+"set" and "unset" write to the local config file by default, or to the global one
+with --global, keeping the comments of YAML and TOML files.
+"get" and "list" show the effective values after merging all config levels.`,
+		Example: `  gitmit config list
+  gitmit config validate
+  gitmit config get maxSubjectLength
+  gitmit config set engine ollama --global
+  gitmit config set topicMappings.internal/api api
+  gitmit config unset engine --global`,
+	}
+
+	configGetCmd = &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the effective value of a config key",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runConfigGet,
+	}
+
+	configSetCmd = &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a config key in the local or global config file",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runConfigSet,
+	}
+
+	configUnsetCmd = &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Remove a config key from the local or global config file",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runConfigUnset,
+	}
+
+	configListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List all effective config values",
+		Args:  cobra.NoArgs,
+		RunE:  runConfigList,
+	}
+
+	configValidateCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Check config files and templates for problems",
+		Long: `Check every config file and the merged configuration for problems that would
+silently degrade suggestions: parse errors, unknown keys, badly typed values,
+regex-looking mapping patterns, invalid template references and missing template actions.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runConfigValidate,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd, configSetCmd, configUnsetCmd, configListCmd, configValidateCmd)
+	configSetCmd.Flags().BoolVar(&configGlobalFlag, "global", false, "Write to the global config in $XDG_CONFIG_HOME/gitmit")
+	configUnsetCmd.Flags().BoolVar(&configGlobalFlag, "global", false, "Write to the global config in $XDG_CONFIG_HOME/gitmit")
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	value, err := config.GetValue(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(config.FormatValue(value))
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	configPath, err := configFileToWrite(cmd.Context())
+	if err != nil {
+		return err
+	}
+	if err := config.SetFileValue(configPath, args[0], args[1]); err != nil {
+		return err
+	}
+
+	ui.Success("✅ Set %s = %s in %s", args[0], args[1], configPath)
+	return nil
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	configPath, err := configFileToWrite(cmd.Context())
+	if err != nil {
+		return err
+	}
+	if err := config.UnsetFileValue(configPath, args[0]); err != nil {
+		return err
+	}
+
+	ui.Success("✅ Unset %s in %s", args[0], configPath)
+	return nil
+}
+
+// configFileToWrite returns the config file "set" and "unset" write: the local
+// one, or with --global the global one, a new JSON file if there is none
+func configFileToWrite(ctx context.Context) (string, error) {
+	if !configGlobalFlag {
+		return config.LocalConfigFile(ctx), nil
+	}
+	if path := config.GlobalConfigFile(); path != "" {
+		return path, nil
+	}
+	return config.GlobalConfigPath(config.FormatJSON)
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	entries, err := config.ListValues(cfg)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		fmt.Printf("%s = %s\n", entry[0], entry[1])
+	}
+	return nil
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	var issues []config.Issue
+
+	files := config.ConfigFiles(ctx)
+	for _, path := range files {
+		issues = append(issues, config.ValidateFile(path)...)
+	}
+
+	// Type errors already reported per file also make loading fail, so only
+	// check the merged config when it loads cleanly
+	if cfg, err := config.LoadConfig(ctx); err == nil {
+		issues = append(issues, config.Validate(cfg)...)
+	} else if len(issues) == 0 {
+		issues = append(issues, config.Issue{Source: "merged", Message: err.Error()})
+	}
+
+	// Loading the templater checks the template file for missing actions and bad placeholders
+	templateFiles := []string{"templates.json"}
+	if cfg, err := config.LoadConfig(ctx); err == nil {
+		if cfg.TemplateFile != "" {
+			templateFiles = append(templateFiles, cfg.TemplateFile)
+		}
+		for _, override := range cfg.Paths {
+			if override.TemplateFile != "" {
+				templateFiles = append(templateFiles, override.TemplateFile)
+			}
+		}
+		for _, policy := range cfg.BranchPolicies {
+			if policy.Templates != "" {
+				templateFiles = append(templateFiles, policy.Templates)
+			}
+		}
+	}
+	cfg, cfgErr := config.LoadConfig(ctx)
+	for _, templateFile := range templateFiles {
+		t, err := templater.NewTemplater(templateFile, &history.CommitHistory{})
+		if err == nil && cfgErr == nil {
+			err = t.AddTemplates(cfg.Templates)
+		}
+		if err != nil {
+			issues = append(issues, config.Issue{Source: templateFile, Message: err.Error()})
+		}
+	}
+
+	if len(files) == 0 {
+		ui.Heading("No config files found; using built-in defaults.")
+	} else {
+		ui.Heading("Checked: %v", files)
+	}
+
+	if len(issues) == 0 {
+		ui.Success("✅ Configuration is valid.")
+		return nil
+	}
+
+	ui.Error("\n❌ Found %d configuration problem(s):", len(issues))
+	for _, issue := range issues {
+		fmt.Printf("  - %s\n", issue)
+	}
+	return fmt.Errorf("configuration is invalid")
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var describeCmd = &cobra.Command{
+	Use:   "describe <commit>",
+	Short: "Explain an existing commit and suggest a message for it",
+	Long: `Analyze the diff of any commit, the same way staged changes are analyzed,
+and print a suggested conventional commit message with a plain explanation of
+what changed: the files touched, where, the functions and types defined and
+the kind of change it looks like.
+
+Useful for reviewing commits whose messages say little about them.`,
+	Example: `  gitmit describe HEAD
+  gitmit describe 3f257fd`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runDescribe,
+}
+
+func init() {
+	rootCmd.AddCommand(describeCmd)
+}
+
+func runDescribe(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	hist, err := history.LoadHistory(ctx)
+	if err != nil {
+		return err
+	}
+
+	hash, err := parser.ResolveRevision(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	commits, err := parser.ParseCommitList(ctx, []string{hash})
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("commit %s not found", args[0])
+	}
+	commit := commits[0]
+
+	gitParser := parser.NewGitParser()
+	changes, err := gitParser.ParseCommitChanges(ctx, hash)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return fmt.Errorf("commit %s changes no files", commit.ShortHash())
+	}
+
+	// The current branch says nothing about an arbitrary commit, so it is left
+	// out of the analysis
+	cfg, commitMessage, err := analyzeChanges(ctx, cfg, gitParser, changes, "")
+	if err != nil {
+		return err
+	}
+	suggestion, err := templateMessage(ctx, cfg, hist, commitMessage, "")
+	if err != nil {
+		return err
+	}
+
+	ui.Heading("📝 Commit %s: %s\n", commit.ShortHash(), commit.Subject)
+	if suggestion != "" {
+		f, _ := newFormatters(ctx, cfg, "", "", learnRepoStyle(cfg, hist))
+		ui.Accent("\nSuggested message:")
+		ui.Success("%s\n", f.FormatMessage(suggestion, commitMessage.IsMajor))
+	}
+
+	ui.Accent("\nWhat changed:")
+	for _, sentence := range analyzer.NewAnalyzer(changes, cfg).Explain(commitMessage) {
+		fmt.Printf("  %s\n", sentence)
+	}
+
+	ui.Accent("\nFiles:")
+	for _, change := range changes {
+		fmt.Printf("  %s %s %s\n", change.Action, change.File, ui.MutedString("(+%d −%d)", change.Added, change.Removed))
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/changelog"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/style"
+)
+
+var (
+	describeRangeBaseFlag string
+	describeRangeLastFlag int
+	describeRangeJSONFlag bool
+
+	describeRangeCmd = &cobra.Command{
+		Use:   "describe-range [range]",
+		Short: "Suggest a message for every commit of a range, without changing anything",
+		Long: `Analyze the diff of each commit of a range, the same way staged changes are
+analyzed, and print a conventional message suggested for it next to its
+current subject. Nothing is rewritten and nothing is asked, so bots can audit
+or annotate existing history; gitmit reword applies such messages instead.
+
+The commits are given as a range (base..head), with --last N, or default to
+the commits of the current branch since its base. Merge commits are skipped.
+
+With --json every commit is printed as an object with its hash, current
+subject, whether that subject is conventional, the suggested message, its type
+and scope, and the files and lines it changes. The message is empty for
+commits that change no files.`,
+		Example: `  gitmit describe-range main..HEAD
+  gitmit describe-range v1.2.0..v1.3.0 --json
+  gitmit describe-range --last 20 --json | jq '.[] | select(.conventional | not)'`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE:         runDescribeRange,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(describeRangeCmd)
+	describeRangeCmd.Flags().StringVar(&describeRangeBaseFlag, "base", "", "Describe the commits since this branch or commit (default: origin's default branch, main or master)")
+	describeRangeCmd.Flags().IntVar(&describeRangeLastFlag, "last", 0, "Describe the last N commits")
+	describeRangeCmd.Flags().BoolVar(&describeRangeJSONFlag, "json", false, "Print the suggestions as JSON")
+}
+
+// commitDescription is the message suggested for a commit of a range
+type commitDescription struct {
+	Hash         string `json:"hash"`
+	Subject      string `json:"subject"`      // Current subject
+	Conventional bool   `json:"conventional"` // Whether the current subject follows the conventional format
+	Message      string `json:"message"`      // Suggested message; empty when the commit changes no files
+	Type         string `json:"type,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+	Files        int    `json:"files"`
+	Added        int    `json:"added"`
+	Removed      int    `json:"removed"`
+}
+
+func runDescribeRange(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	hist, err := history.LoadHistory(ctx)
+	if err != nil {
+		return err
+	}
+
+	base, head, err := resolveRange(ctx, args, describeRangeBaseFlag, describeRangeLastFlag)
+	if err != nil {
+		return err
+	}
+	commits, err := parser.ParseCommits(ctx, base, head)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits between %s and %s", base, head)
+	}
+
+	f, _ := newFormatters(ctx, cfg, "", "", learnRepoStyle(cfg, hist))
+	descriptions := make([]*commitDescription, 0, len(commits))
+	for i, entry := range changelog.Parse(commits) {
+		d := &commitDescription{Hash: commits[i].Hash, Subject: commits[i].Subject, Conventional: entry.Type != ""}
+		descriptions = append(descriptions, d)
+
+		// Each commit gets its own parser, which totals the lines of its diff
+		gitParser := parser.NewGitParser()
+		changes, err := gitParser.ParseShowChanges(ctx, commits[i].Hash)
+		if err != nil {
+			return err
+		}
+		d.Files, d.Added, d.Removed = len(changes), gitParser.TotalAdded, gitParser.TotalRemoved
+
+		// The current branch says nothing about earlier commits, so it is left
+		// out of the analysis, as in describe
+		commitCfg, commitMessage, err := analyzeChanges(ctx, cfg, gitParser, changes, "")
+		if err != nil {
+			return err
+		}
+		if commitMessage == nil {
+			continue
+		}
+		suggestion, err := templateMessage(ctx, commitCfg, hist, commitMessage, "")
+		if err != nil {
+			return err
+		}
+		if suggestion != "" {
+			d.Message = f.FormatMessage(suggestion, commitMessage.IsMajor)
+			d.Type, d.Scope = commitMessage.Action, commitMessage.Scope
+		}
+	}
+
+	if describeRangeJSONFlag {
+		data, err := json.MarshalIndent(descriptions, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error encoding descriptions: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	rewordings := make([]*rewording, 0, len(descriptions))
+	for i, d := range descriptions {
+		rewordings = append(rewordings, &rewording{Commit: commits[i], Message: d.Message, Apply: d.Message != "" && style.Subject(d.Message) != d.Subject})
+	}
+	printRewordings(rewordings)
+	return nil
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+const (
+	// maxStatFiles is the number of files listed in the diffstat, the others
+	// are summed up in a line
+	maxStatFiles = 10
+
+	// maxStatPath and maxStatBar are the widths of the path column and of the
+	// longest bar of the diffstat
+	maxStatPath = 50
+	maxStatBar  = 40
+)
+
+// printDiffStat prints a summary of the changes like git diff --stat: a line
+// per file with its changed lines and a bar of its insertions and deletions,
+// then the totals. Bars are scaled to the file with the most changes.
+func printDiffStat(changes []*parser.Change) {
+	if len(changes) == 0 {
+		return
+	}
+
+	listed := changes[:min(len(changes), maxStatFiles)]
+	pathWidth, mostChanged := 0, 0
+	for _, change := range listed {
+		pathWidth = max(pathWidth, utf8.RuneCountInString(statPath(change)))
+		mostChanged = max(mostChanged, change.Added+change.Removed)
+	}
+	countWidth := len(fmt.Sprint(mostChanged))
+	for _, change := range listed {
+		if binaryChange(change) {
+			countWidth = max(countWidth, len("Bin"))
+		}
+	}
+
+	added, removed := 0, 0
+	for _, change := range changes {
+		added += change.Added
+		removed += change.Removed
+	}
+
+	ui.Println()
+	for _, change := range listed {
+		path := statPath(change)
+		padding := strings.Repeat(" ", pathWidth-utf8.RuneCountInString(path))
+		count := fmt.Sprint(change.Added + change.Removed)
+		if binaryChange(change) {
+			count = "Bin"
+		}
+		plus, minus := statBar(change.Added, change.Removed, mostChanged)
+		ui.Printf(" %s%s | %*s %s%s\n", path, padding, countWidth, count,
+			ui.SuccessString("%s", strings.Repeat("+", plus)), ui.ErrorString("%s", strings.Repeat("-", minus)))
+	}
+	if rest := changes[len(listed):]; len(rest) > 0 {
+		restAdded, restRemoved := 0, 0
+		for _, change := range rest {
+			restAdded += change.Added
+			restRemoved += change.Removed
+		}
+		ui.Muted(" … and %s more (+%d -%d)", ui.Plural(len(rest), "file", "files"), restAdded, restRemoved)
+	}
+	ui.Printf(" %s changed, %s(+), %s(-)\n", ui.Plural(len(changes), "file", "files"),
+		ui.Plural(added, "insertion", "insertions"), ui.Plural(removed, "deletion", "deletions"))
+}
+
+// statPath returns the path of a change as git diff --stat shows it, with
+// renames and copies as "old => new", cut from the left to maxStatPath
+func statPath(change *parser.Change) string {
+	path := change.File
+	if (change.IsRename || change.IsCopy) && change.Source != "" {
+		path = change.Source + " => " + change.Target
+	}
+	if runes := []rune(path); len(runes) > maxStatPath {
+		path = "…" + string(runes[len(runes)-maxStatPath+1:])
+	}
+	return path
+}
+
+// binaryChange reports whether a change is to a binary file, which has no lines
+func binaryChange(change *parser.Change) bool {
+	return change.Added+change.Removed == 0 && strings.Contains(change.Diff, "Binary files")
+}
+
+// statBar returns the lengths of the insertion and deletion bars of a file,
+// scaled to maxStatBar for the file with the most changes, keeping a mark for
+// any change
+func statBar(added, removed, mostChanged int) (int, int) {
+	if mostChanged <= maxStatBar {
+		return added, removed
+	}
+	scale := func(n int) int {
+		if n == 0 {
+			return 0
+		}
+		return max(n*maxStatBar/mostChanged, 1)
+	}
+	return scale(added), scale(removed)
+}
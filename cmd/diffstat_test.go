@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestStatPath(t *testing.T) {
+	long := strings.Repeat("a/", 30) + "main.go"
+	tests := []struct {
+		name   string
+		change *parser.Change
+		want   string
+	}{
+		{name: "file", change: &parser.Change{File: "cmd/root.go"}, want: "cmd/root.go"},
+		{name: "rename", change: &parser.Change{File: "cmd/run.go", IsRename: true, Source: "cmd/root.go", Target: "cmd/run.go"}, want: "cmd/root.go => cmd/run.go"},
+		{name: "copy", change: &parser.Change{File: "b.go", IsCopy: true, Source: "a.go", Target: "b.go"}, want: "a.go => b.go"},
+		{name: "rename without source", change: &parser.Change{File: "b.go", IsRename: true}, want: "b.go"},
+		{name: "long path cut from the left", change: &parser.Change{File: long}, want: "…" + long[len(long)-maxStatPath+1:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statPath(tt.change); got != tt.want {
+				t.Errorf("statPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatBar(t *testing.T) {
+	tests := []struct {
+		name                        string
+		added, removed, mostChanged int
+		wantPlus, wantMinus         int
+	}{
+		{name: "fits", added: 3, removed: 2, mostChanged: 5, wantPlus: 3, wantMinus: 2},
+		{name: "fits at the width", added: 30, removed: 10, mostChanged: maxStatBar, wantPlus: 30, wantMinus: 10},
+		{name: "scaled", added: 150, removed: 50, mostChanged: 200, wantPlus: 30, wantMinus: 10},
+		{name: "small change keeps a mark", added: 1, removed: 0, mostChanged: 1000, wantPlus: 1, wantMinus: 0},
+		{name: "no changes", added: 0, removed: 0, mostChanged: 1000, wantPlus: 0, wantMinus: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plus, minus := statBar(tt.added, tt.removed, tt.mostChanged)
+			if plus != tt.wantPlus || minus != tt.wantMinus {
+				t.Errorf("statBar(%d, %d, %d) = %d, %d, want %d, %d", tt.added, tt.removed, tt.mostChanged, plus, minus, tt.wantPlus, tt.wantMinus)
+			}
+		})
+	}
+}
+
+func TestBinaryChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		change *parser.Change
+		want   bool
+	}{
+		{name: "binary", change: &parser.Change{File: "logo.png", Diff: "Binary files /dev/null and b/logo.png differ"}, want: true},
+		{name: "text", change: &parser.Change{File: "main.go", Added: 1, Diff: "+package main"}, want: false},
+		{name: "empty file", change: &parser.Change{File: "empty.txt"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := binaryChange(tt.change); got != tt.want {
+				t.Errorf("binaryChange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
+	"github.com/andev0x/gitmit/internal/prompt"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+// showStagedDiff pages through the staged diff a file at a time, colored like
+// git diff, so it can be checked against the message before committing. When
+// amending, the diff is the one of the amended commit.
+func showStagedDiff(ctx context.Context, reader *bufio.Reader, amending bool) error {
+	args := []string{"diff", "--cached", "--no-color", "--no-ext-diff", "--find-renames"}
+	if amending {
+		args = append(args, "HEAD^")
+	}
+	out, err := gitcmd.Output(ctx, "", args...)
+	if err != nil {
+		return fmt.Errorf("error reading the staged diff: %w", err)
+	}
+	pages := diffPages(out)
+	if len(pages) == 0 {
+		ui.Warn("⚠ Nothing staged to show.\n")
+		return nil
+	}
+	return prompt.NewStdin(reader).ShowPages(pages, diffLineStyle)
+}
+
+// diffPages splits a diff into a page per file, titled with its path and the
+// lines it adds and removes
+func diffPages(diff string) []prompt.Page {
+	var pages []prompt.Page
+	added, removed := 0, 0
+	title := func() {
+		if len(pages) > 0 {
+			pages[len(pages)-1].Title += fmt.Sprintf("  +%d −%d", added, removed)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			title()
+			path := strings.TrimPrefix(line, "diff --git ")
+			if i := strings.LastIndex(path, " b/"); i >= 0 {
+				path = path[i+3:]
+			}
+			pages = append(pages, prompt.Page{Title: path})
+			added, removed = 0, 0
+		}
+		if len(pages) == 0 {
+			continue
+		}
+		page := &pages[len(pages)-1]
+		page.Lines = append(page.Lines, line)
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			added++
+		case strings.HasPrefix(line, "-"):
+			removed++
+		}
+	}
+	title()
+	return pages
+}
+
+// diffLineStyle colors a line of a diff the way git diff does
+func diffLineStyle(line string) string {
+	switch {
+	case strings.HasPrefix(line, "diff --git"), strings.HasPrefix(line, "index "),
+		strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		return ui.HeadingString("%s", line)
+	case strings.HasPrefix(line, "@@"):
+		return ui.AccentString("%s", line)
+	case strings.HasPrefix(line, "+"):
+		return ui.SuccessString("%s", line)
+	case strings.HasPrefix(line, "-"):
+		return ui.ErrorString("%s", line)
+	}
+	return line
+}
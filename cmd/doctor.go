@@ -0,0 +1,367 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/ai"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/gitcmd"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/plugin"
+	"github.com/andev0x/gitmit/internal/templater"
+	"github.com/andev0x/gitmit/internal/ticket"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	doctorOfflineFlag bool
+
+	doctorCmd = &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the environment gitmit runs in and suggest fixes",
+		Long: `Check what gitmit depends on and print each check as passed, a warning or
+failed, with how to fix it:
+
+  git          git is installed and recent enough
+  repository   the working directory is inside a git repository
+  config       which config files are merged, in order, and whether they are valid
+  templates    where the template pack is loaded from: the working directory,
+               the user template directory, the executable's directory or the
+               embedded templates
+  history      the suggestion history store can be read and written
+  api keys     the token of the ticket tracker and the gh CLI for GitHub issues
+  ai           the Ollama daemon answers and has the configured model
+
+The exit status is 1 when a check failed, so doctor can run in scripts.`,
+		Example: `  gitmit doctor
+  gitmit doctor --offline`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runDoctor,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().BoolVar(&doctorOfflineFlag, "offline", false, "Skip the checks that need the network")
+}
+
+// doctorAITimeout bounds the reachability check of the AI model
+const doctorAITimeout = 5 * time.Second
+
+// Outcomes of a doctor check
+const (
+	checkPassed = iota
+	checkWarning
+	checkFailed
+)
+
+// check is the outcome of one doctor check
+type check struct {
+	name   string
+	status int
+	detail string
+	fix    string   // What to do about a warning or failure
+	notes  []string // Further details, such as the files considered
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	ui.Heading("🩺 gitmit doctor")
+
+	cfg, cfgErr := config.LoadConfig(ctx)
+	if cfgErr != nil {
+		cfg = config.DefaultConfig()
+	}
+	checks := []check{
+		checkGit(ctx),
+		checkRepository(ctx),
+		checkConfig(ctx, cfgErr),
+		checkTemplates(ctx, cfg),
+		checkHistory(),
+		checkPlugins(cfg),
+	}
+	checks = append(checks, checkAPIKeys(cfg)...)
+	checks = append(checks, checkAI(ctx, cfg))
+
+	failed, warned := 0, 0
+	for _, c := range checks {
+		printCheck(c)
+		switch c.status {
+		case checkFailed:
+			failed++
+		case checkWarning:
+			warned++
+		}
+	}
+	switch {
+	case failed > 0:
+		return fmt.Errorf("%d doctor check(s) failed", failed)
+	case warned > 0:
+		ui.Success("\n✅ gitmit can run; the warnings above only limit optional features.")
+	default:
+		ui.Success("\n✅ Everything gitmit needs is in place.")
+	}
+	return nil
+}
+
+// printCheck shows a check with its status, notes and fix
+func printCheck(c check) {
+	line := fmt.Sprintf("%-12s %s", c.name, c.detail)
+	switch c.status {
+	case checkPassed:
+		ui.Success("✅ %s", line)
+	case checkWarning:
+		ui.Warn("⚠ %s", line)
+	default:
+		ui.Error("❌ %s", line)
+	}
+	for _, note := range c.notes {
+		ui.Muted("   %s", note)
+	}
+	if c.fix != "" && c.status != checkPassed {
+		ui.Muted("   fix: %s", c.fix)
+	}
+}
+
+// checkGit checks that git is installed and at least gitcmd.MinVersion
+func checkGit(ctx context.Context) check {
+	c := check{name: "git"}
+	version, err := gitcmd.Version(ctx)
+	switch {
+	case err != nil:
+		c.status, c.detail = checkFailed, err.Error()
+		c.fix = "Install git from https://git-scm.com and make sure it is on your PATH."
+	case !gitcmd.VersionAtLeast(version, gitcmd.MinVersion):
+		c.status, c.detail = checkWarning, fmt.Sprintf("%s is older than %s", version, gitcmd.MinVersion)
+		c.fix = fmt.Sprintf("Upgrade git to %s or newer; some commands may fail.", gitcmd.MinVersion)
+	default:
+		c.detail = version
+	}
+	return c
+}
+
+// checkRepository checks that the working directory is inside a repository
+func checkRepository(ctx context.Context) check {
+	c := check{name: "repository"}
+	out, err := gitcmd.Output(ctx, "", "rev-parse", "--show-toplevel")
+	if err != nil {
+		c.status, c.detail = checkFailed, err.Error()
+		if errors.Is(err, gitcmd.ErrNotARepo) {
+			c.detail = "not inside a git repository"
+		}
+		c.fix = Hint(gitcmd.ErrNotARepo)
+		return c
+	}
+	c.detail = strings.TrimSpace(out)
+	if branch, err := parser.NewGitParser().GetCurrentBranch(ctx); err == nil && branch != "" {
+		c.notes = append(c.notes, "branch "+branch)
+	}
+	return c
+}
+
+// checkConfig lists the config files in the order they are merged and
+// validates them
+func checkConfig(ctx context.Context, cfgErr error) check {
+	c := check{name: "config"}
+	files := config.ConfigFiles(ctx)
+	var issues []config.Issue
+	for _, path := range files {
+		issues = append(issues, config.ValidateFile(path)...)
+	}
+	if cfgErr == nil {
+		if cfg, err := config.LoadConfig(ctx); err == nil {
+			issues = append(issues, config.Validate(cfg)...)
+		}
+	}
+
+	if len(files) == 0 {
+		c.detail = "no config files, using the built-in defaults"
+	} else {
+		c.detail = fmt.Sprintf("%d file(s), merged in this order", len(files))
+		for i, path := range files {
+			c.notes = append(c.notes, fmt.Sprintf("%d. %s", i+1, path))
+		}
+	}
+	if overrides := config.EnvOverrides(); len(overrides) > 0 {
+		c.notes = append(c.notes, "overridden by "+strings.Join(overrides, ", "))
+	}
+
+	switch {
+	case cfgErr != nil:
+		c.status, c.detail = checkFailed, cfgErr.Error()
+		c.fix = "Fix the config file, then check it with gitmit config validate."
+	case len(issues) > 0:
+		c.status = checkWarning
+		c.detail = fmt.Sprintf("%d problem(s)", len(issues))
+		for _, issue := range issues {
+			c.notes = append(c.notes, issue.String())
+		}
+		c.fix = "Correct the keys above; gitmit config validate shows the same list."
+	}
+	return c
+}
+
+// checkTemplates checks that the template pack of the current branch loads, and
+// where it is loaded from
+func checkTemplates(ctx context.Context, cfg *config.Config) check {
+	c := check{name: "templates"}
+	branch, _ := parser.NewGitParser().GetCurrentBranch(ctx)
+	templateFile := selectTemplateFile(cfg, cfg.BranchPolicy(branch), "")
+	templates, source, err := templater.LoadTemplates(templateFile)
+	if err == nil {
+		err = templates.Merge(cfg.Templates).Validate()
+	}
+	if err != nil {
+		c.status, c.detail = checkFailed, err.Error()
+		c.fix = fmt.Sprintf("Check %s with gitmit templates validate; it is looked up in the working directory, the user template directory, the executable's directory and the embedded templates.", templateFile)
+		return c
+	}
+	c.detail = fmt.Sprintf("%s from the %s", templateFile, templateLocation(source))
+	if !strings.HasPrefix(source, "embedded:") {
+		c.notes = append(c.notes, source)
+	}
+	return c
+}
+
+// templateLocation names where templater.ReadTemplateFile found a template file
+func templateLocation(source string) string {
+	if strings.HasPrefix(source, "embedded:") {
+		return "embedded templates"
+	}
+	dir := filepath.Dir(source)
+	if wd, err := os.Getwd(); err == nil && dir == wd {
+		return "working directory"
+	}
+	if userDir, err := templater.UserTemplateDir(); err == nil && dir == userDir {
+		return "user template directory"
+	}
+	if execPath, err := os.Executable(); err == nil && dir == filepath.Dir(execPath) {
+		return "executable's directory"
+	}
+	return "file " + source
+}
+
+// checkHistory checks that the suggestion history store can be read and written
+func checkHistory() check {
+	c := check{name: "history"}
+	health, err := history.CheckStore()
+	switch {
+	case health != nil && health.Corrupt:
+		c.status, c.detail = checkWarning, err.Error()
+		c.fix = "The next gitmit run moves the store aside and starts a new history; restore an export with gitmit history import."
+		return c
+	case err != nil:
+		c.status, c.detail = checkFailed, err.Error()
+		c.fix = "Make $XDG_STATE_HOME/gitmit readable and writable, or point XDG_STATE_HOME elsewhere."
+		return c
+	}
+	if health.Exists {
+		c.detail = fmt.Sprintf("%s (%s)", health.Path, ui.Plural(health.Repositories, "repository", "repositories"))
+	} else {
+		c.detail = health.Path + " (nothing saved yet)"
+	}
+	if len(health.Backups) > 0 {
+		c.status = checkWarning
+		c.notes = append(c.notes, health.Backups...)
+		c.fix = "A corrupt history was reset; import what you need from the backups above and delete them."
+	}
+	return c
+}
+
+// checkPlugins lists the plugins found in the plugins directory
+func checkPlugins(cfg *config.Config) check {
+	c := check{name: "plugins"}
+	dir, err := plugin.Dir()
+	if err != nil {
+		c.status, c.detail = checkWarning, err.Error()
+		return c
+	}
+	found, err := plugin.Discover(dir)
+	if err != nil {
+		c.status, c.detail = checkWarning, err.Error()
+		c.fix = "Make " + dir + " readable, or turn plugins off with gitmit config set plugins.enabled false."
+		return c
+	}
+	if len(found) == 0 {
+		c.detail = "none in " + dir
+		return c
+	}
+	running := 0
+	for _, p := range found {
+		switch {
+		case !cfg.Plugins.Enabled:
+			c.notes = append(c.notes, p.Path+" (plugins are turned off)")
+		case cfg.Plugins.IsDisabled(p.Name):
+			c.notes = append(c.notes, p.Path+" (disabled)")
+		default:
+			running++
+			c.notes = append(c.notes, p.Path)
+		}
+	}
+	c.detail = fmt.Sprintf("%s of %d run", ui.Plural(running, "plugin", "plugins"), len(found))
+	return c
+}
+
+// checkAPIKeys checks the token of the configured ticket tracker and the gh
+// CLI used to look up GitHub issues
+func checkAPIKeys(cfg *config.Config) []check {
+	var checks []check
+	if cfg.Tickets.Enabled() {
+		c := check{name: "api keys", detail: cfg.Tickets.Provider + " token found"}
+		if _, err := ticket.NewClient(cfg.Tickets); err != nil {
+			c.status, c.detail = checkFailed, err.Error()
+			c.fix = "Set the token in the environment rather than in a config file you commit."
+		}
+		checks = append(checks, c)
+	}
+	if cfg.Tickets.GitHubIssues {
+		c := check{name: "github", detail: "gh CLI found for GitHub issues"}
+		if _, err := exec.LookPath("gh"); err != nil {
+			c.status, c.detail = checkWarning, "gh CLI not found; referenced GitHub issues are not looked up"
+			c.fix = "Install gh from https://cli.github.com and run gh auth login, or set tickets.githubIssues to false."
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// checkAI checks that the Ollama daemon answers and has the configured model.
+// Problems only fail the check when the ollama engine is configured; otherwise
+// the model is optional.
+func checkAI(ctx context.Context, cfg *config.Config) check {
+	c := check{name: "ai"}
+	if doctorOfflineFlag {
+		c.status, c.detail = checkWarning, "skipped (--offline)"
+		return c
+	}
+	problem := checkWarning
+	if cfg.Engine == "ollama" {
+		problem = checkFailed
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, doctorAITimeout)
+	defer cancel()
+	models, err := ai.NewOllamaClient(cfg.Ollama).Models(ctx)
+	switch {
+	case err != nil:
+		c.status, c.detail = problem, err.Error()
+		c.fix = fmt.Sprintf("Start Ollama with ollama serve, or set ollama.url; currently %s.", cfg.Ollama.URL)
+	case !ai.HasModel(models, cfg.Ollama.Model):
+		c.status, c.detail = problem, fmt.Sprintf("model %s is not pulled at %s", cfg.Ollama.Model, cfg.Ollama.URL)
+		c.fix = "Run ollama pull " + cfg.Ollama.Model + ", or set ollama.model to one of: " + strings.Join(models, ", ")
+	default:
+		c.detail = fmt.Sprintf("%s at %s", cfg.Ollama.Model, cfg.Ollama.URL)
+	}
+	return c
+}
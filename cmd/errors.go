@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
+	"github.com/andev0x/gitmit/internal/hook"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/templater"
+)
+
+// Hint returns what to do about an error of a command, or "" when its
+// message says it all
+func Hint(err error) string {
+	switch {
+	case errors.Is(err, gitcmd.ErrNotARepo):
+		return "Run gitmit inside a git repository, or create one with git init."
+	case errors.Is(err, parser.ErrNoStagedChanges):
+		return "Stage changes with git add, or let gitmit stage them with gitmit propose --add or --add-all."
+	case errors.Is(err, parser.ErrEmptyPatch):
+		return "Pass a unified diff, such as the output of git diff or git format-patch."
+	case errors.Is(err, templater.ErrTemplateInvalid):
+		return "Check the templates with gitmit templates validate [file]."
+	case hook.IsVeto(err):
+		return "Deal with what the hook reported; the hooks section of the config sets which commands run."
+	}
+	return ""
+}
+
+// errorStatus returns the HTTP status answering a request that failed with err
+func errorStatus(err error) int {
+	if errors.Is(err, gitcmd.ErrNotARepo) {
+		return http.StatusBadRequest
+	}
+	return http.StatusUnprocessableEntity
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+const (
+	// maxListedFiles is the number of changed files listed in full; beyond it
+	// each directory lists maxGroupFiles files and sums up the others
+	maxListedFiles = 20
+	maxGroupFiles  = 5
+
+	// maxBreakdownFiles is the number of files listed in the weight breakdown
+	// unless full is set
+	maxBreakdownFiles = 10
+)
+
+// fullFlag lists every changed file instead of collapsing long lists
+var fullFlag bool
+
+// fileGroup is the changed files under a top-level directory
+type fileGroup struct {
+	Dir     string
+	Changes []*parser.Change
+	Added   int
+	Removed int
+}
+
+// groupFiles groups changes by top-level directory, in order, with the files
+// at the root of the repository first
+func groupFiles(changes []*parser.Change) []*fileGroup {
+	groups := make(map[string]*fileGroup)
+	var dirs []string
+	for _, change := range changes {
+		dir := "./"
+		if i := strings.Index(change.File, "/"); i >= 0 {
+			dir = change.File[:i+1]
+		}
+		group, ok := groups[dir]
+		if !ok {
+			group = &fileGroup{Dir: dir}
+			groups[dir] = group
+			dirs = append(dirs, dir)
+		}
+		group.Changes = append(group.Changes, change)
+		group.Added += change.Added
+		group.Removed += change.Removed
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		if (dirs[i] == "./") != (dirs[j] == "./") {
+			return dirs[i] == "./"
+		}
+		return dirs[i] < dirs[j]
+	})
+
+	result := make([]*fileGroup, len(dirs))
+	for i, dir := range dirs {
+		group := groups[dir]
+		sort.SliceStable(group.Changes, func(i, j int) bool { return group.Changes[i].File < group.Changes[j].File })
+		result[i] = group
+	}
+	return result
+}
+
+// printFileGroups lists the changed files under their top-level directories,
+// each with its count of files and lines. Unless full is set, long lists show
+// the first files of each directory and the number of the others.
+func printFileGroups(changes []*parser.Change, full bool) {
+	groups := groupFiles(changes)
+	ui.Accent("\nFiles: %s in %s", ui.Plural(len(changes), "file", "files"), ui.Plural(len(groups), "directory", "directories"))
+
+	collapsed := !full && len(changes) > maxListedFiles
+	hidden := 0
+	for _, group := range groups {
+		ui.Printf("  %s %s\n", group.Dir, ui.MutedString("(%s, +%d −%d)", ui.Plural(len(group.Changes), "file", "files"), group.Added, group.Removed))
+		listed := group.Changes
+		if collapsed && len(listed) > maxGroupFiles {
+			listed = listed[:maxGroupFiles]
+		}
+		for _, change := range listed {
+			ui.Printf("    %s %s %s\n", change.Action, strings.TrimPrefix(change.File, group.Dir), ui.MutedString("(+%d −%d)", change.Added, change.Removed))
+		}
+		if rest := len(group.Changes) - len(listed); rest > 0 {
+			ui.Muted("    … and %d more", rest)
+			hidden += rest
+		}
+	}
+	if hidden > 0 {
+		ui.Muted("  Pass --full to list all %d files.", len(changes))
+	}
+}
+
+// printBreakdown lists how much each file counts toward the message, heaviest
+// first, with the commit type, topic and purpose it suggests on its own
+func printBreakdown(weights []analyzer.FileWeight, full bool) {
+	if len(weights) < 2 {
+		return
+	}
+	listed := weights
+	if !full && len(listed) > maxBreakdownFiles {
+		listed = listed[:maxBreakdownFiles]
+	}
+	fileWidth, actionWidth, topicWidth := 0, 0, 0
+	for _, w := range listed {
+		fileWidth = max(fileWidth, utf8.RuneCountInString(w.File))
+		actionWidth = max(actionWidth, len(w.Type))
+		topicWidth = max(topicWidth, utf8.RuneCountInString(w.Topic))
+	}
+
+	ui.Accent("\nWeights:")
+	for _, w := range listed {
+		ui.Printf("  %3.0f%%  %-*s  %-*s  %-*s  %s\n", w.Share*100, fileWidth, w.File, actionWidth, w.Type, topicWidth, w.Topic, ui.MutedString("%s", w.Purpose))
+	}
+	if rest := len(weights) - len(listed); rest > 0 {
+		ui.Muted("  … and %d more", rest)
+	}
+}
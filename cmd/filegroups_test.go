@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestGroupFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  string // Each group as dir(files:added)
+	}{
+		{name: "none", want: ""},
+		{name: "root files first", files: []string{"internal/a.go", "go.mod", "cmd/root.go", "README.md"}, want: "./(README.md go.mod:2) cmd/(cmd/root.go:1) internal/(internal/a.go:1)"},
+		{name: "files sorted within a directory", files: []string{"cmd/z.go", "cmd/sub/a.go", "cmd/a.go"}, want: "cmd/(cmd/a.go cmd/sub/a.go cmd/z.go:3)"},
+		{name: "directories sorted", files: []string{"web/app.js", "api/user.go", "docs/guide.md"}, want: "api/(api/user.go:1) docs/(docs/guide.md:1) web/(web/app.js:1)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var changes []*parser.Change
+			for _, file := range tt.files {
+				changes = append(changes, &parser.Change{File: file, Added: 1, Removed: 2})
+			}
+			var got []string
+			for _, group := range groupFiles(changes) {
+				var files []string
+				for _, change := range group.Changes {
+					files = append(files, change.File)
+				}
+				if group.Removed != 2*len(files) {
+					t.Errorf("group %s removed = %d, want %d", group.Dir, group.Removed, 2*len(files))
+				}
+				got = append(got, fmt.Sprintf("%s(%s:%d)", group.Dir, strings.Join(files, " "), group.Added))
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("groupFiles() = %s, want %s", strings.Join(got, " "), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"context"
+	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+// maxFixupCandidates caps the unpushed commits compared with the staged changes
+const maxFixupCandidates = 20
+
+// fixupTarget returns the unpushed commit the staged changes most likely
+// belong to, or nil when none stands out. HEAD is left out when the changes
+// can be amended into it instead.
+func fixupTarget(ctx context.Context, changes []*parser.Change, amendable bool) *analyzer.Fixup {
+	commits, err := parser.UnpushedCommits(ctx, maxFixupCandidates)
+	if err != nil {
+		return nil
+	}
+	var candidates []analyzer.FixupCandidate
+	for i, commit := range commits {
+		if i == 0 && amendable {
+			continue
+		}
+		hunks, err := parser.CommitHunks(ctx, commit.Hash)
+		if err != nil {
+			return nil
+		}
+		candidates = append(candidates, analyzer.FixupCandidate{Commit: commit, Hunks: hunks})
+	}
+	return analyzer.FindFixupTarget(changes, candidates)
+}
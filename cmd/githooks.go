@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/githooks"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	hookFrameworkFlag string
+	hookWriteFlag     bool
+
+	hookCmd = &cobra.Command{
+		Use:   "hook",
+		Short: "Run gitmit from git's prepare-commit-msg and commit-msg hooks",
+		Long: `Wire gitmit into plain "git commit": prepare-commit-msg fills in a suggested
+message for the editor, and commit-msg refuses messages that break the commit
+rules of the config.
+
+For hooks kept in .git/hooks, call the commands from the hook scripts:
+
+  gitmit hook prepare-commit-msg "$@"
+  gitmit hook commit-msg "$1"
+
+For husky, lefthook or pre-commit, "gitmit hook generate" writes their
+configuration instead.`,
+		Args: cobra.NoArgs,
+	}
+
+	hookGenerateCmd = &cobra.Command{
+		Use:   "generate",
+		Short: "Print the husky, lefthook or pre-commit configuration running gitmit's hooks",
+		Long: `Print the configuration wiring gitmit into the prepare-commit-msg and
+commit-msg hooks of a hook manager. Without --framework, the one whose files
+the repository has is used.
+
+  husky       .husky/prepare-commit-msg and .husky/commit-msg
+  lefthook    a snippet for lefthook.yml
+  pre-commit  a snippet for .pre-commit-config.yaml; install the hooks with
+              pre-commit install
+
+With --write the files are created, and the husky scripts get the commands
+appended when they exist. Existing lefthook and pre-commit files are left for
+you to merge the snippet into.`,
+		Example: `  gitmit hook generate --framework husky --write
+  gitmit hook generate --framework lefthook >> lefthook.yml
+  gitmit hook generate --framework pre-commit`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runHookGenerate,
+	}
+
+	hookPrepareCmd = &cobra.Command{
+		Use:   "prepare-commit-msg <file> [source] [commit]",
+		Short: "Fill in a suggested message for git commit",
+		Long: `Run as git's prepare-commit-msg hook: when git commit opens the editor
+without a message, a suggestion for the staged changes is written above git's
+comments. Squash and merge messages are rewritten as gitmit squash --hook does,
+and messages given with -m, -F, -c or a commit template are left alone.
+
+pre-commit passes only the file, with the source in PRE_COMMIT_COMMIT_MSG_SOURCE.`,
+		Args:         cobra.RangeArgs(1, 3),
+		SilenceUsage: true,
+		RunE:         runHookPrepare,
+	}
+
+	hookCheckCmd = &cobra.Command{
+		Use:   "commit-msg <file>",
+		Short: "Refuse commit messages that break the commit rules",
+		Long: `Run as git's commit-msg hook: check the message against the commit rules of
+the config and the checks of plugins, and fail listing the violations so git
+aborts the commit. Merge, revert, fixup!, squash! and amend! messages are not
+checked.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runHookCheck,
+	}
+)
+
+// uncheckedPrefixes start the messages git or gitmit make for merges,
+// reverts and fixups, which the commit rules do not apply to
+var uncheckedPrefixes = append([]string{"Merge ", "Revert \""}, fixupPrefixes...)
+
+func init() {
+	rootCmd.AddCommand(hookCmd)
+	hookCmd.AddCommand(hookGenerateCmd)
+	hookCmd.AddCommand(hookPrepareCmd)
+	hookCmd.AddCommand(hookCheckCmd)
+	hookGenerateCmd.Flags().StringVar(&hookFrameworkFlag, "framework", "", "Hook manager: "+strings.Join(githooks.Frameworks, ", ")+" (default: the one the repository uses)")
+	hookGenerateCmd.Flags().BoolVar(&hookWriteFlag, "write", false, "Write the files instead of printing them")
+}
+
+func runHookGenerate(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	root, err := parser.RepoRoot(ctx, ".")
+	if err != nil {
+		return err
+	}
+
+	framework := hookFrameworkFlag
+	if framework == "" {
+		framework = githooks.Detect(func(path string) bool {
+			_, err := os.Stat(filepath.Join(root, path))
+			return err == nil
+		})
+		if framework == "" {
+			return fmt.Errorf("no husky, lefthook or pre-commit configuration found; choose one with --framework")
+		}
+	}
+	files, err := githooks.Generate(framework)
+	if err != nil {
+		return err
+	}
+
+	if !hookWriteFlag {
+		for i, file := range files {
+			if len(files) > 1 {
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Printf("# %s\n", file.Path)
+			}
+			fmt.Print(file.Content)
+		}
+		return nil
+	}
+	for _, file := range files {
+		if err := writeHookFile(filepath.Join(root, file.Path), file); err != nil {
+			return err
+		}
+	}
+	if framework == "pre-commit" {
+		ui.Muted("  Run pre-commit install to install the prepare-commit-msg and commit-msg hooks.")
+	}
+	return nil
+}
+
+// writeHookFile creates a file of a hook manager's configuration, or appends
+// to a script that does not run gitmit yet. Configuration snippets are not
+// merged into existing files.
+func writeHookFile(path string, file githooks.File) error {
+	existing, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("error creating %s: %w", filepath.Dir(path), err)
+		}
+	case err != nil:
+		return fmt.Errorf("error reading %s: %w", path, err)
+	case githooks.IsWired(string(existing)):
+		ui.Muted("  %s runs gitmit already.", file.Path)
+		return nil
+	case file.Snippet:
+		return fmt.Errorf("%s exists; merge the output of gitmit hook generate into it", file.Path)
+	}
+
+	content := file.Content
+	if len(existing) > 0 {
+		content = strings.TrimRight(string(existing), "\n") + "\n" + content
+	}
+	mode := os.FileMode(0644)
+	if file.Executable {
+		mode = 0755
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	if file.Executable {
+		// WriteFile keeps the mode of files that exist
+		if err := os.Chmod(path, mode); err != nil {
+			return fmt.Errorf("error making %s executable: %w", path, err)
+		}
+	}
+	ui.Success("✅ Wrote %s", file.Path)
+	return nil
+}
+
+func runHookPrepare(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	source := os.Getenv("PRE_COMMIT_COMMIT_MSG_SOURCE")
+	if len(args) > 1 {
+		source = args[1]
+	}
+	switch source {
+	case "squash", "merge":
+		return runSquashHook(ctx, cfg, []string{args[0], source})
+	case "":
+		return prepareMessage(ctx, cfg, args[0])
+	}
+	return nil
+}
+
+// prepareMessage writes a suggestion for the staged changes above the
+// comments of the message file git prepared for the editor
+func prepareMessage(ctx context.Context, cfg *config.Config, path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading message file: %w", err)
+	}
+	if stripComments(string(content), parser.CommentChar(ctx)) != "" {
+		return nil
+	}
+
+	hist, err := history.LoadHistory(ctx)
+	if err != nil {
+		return err
+	}
+	gitParser := parser.NewGitParser()
+	changes, err := gitParser.ParseStagedChanges(ctx)
+	if err != nil {
+		return err
+	}
+	suggestion, err := heuristicSuggestion(ctx, cfg, hist, gitParser, changes)
+	if err != nil || suggestion == "" {
+		return err
+	}
+
+	branchName, _ := gitParser.GetCurrentBranch(ctx)
+	f, _ := newFormatters(ctx, cfg, branchTicketPrefix(cfg, cfg.BranchPolicy(branchName), branchName), "", learnRepoStyle(cfg, hist))
+	message := f.FormatMessage(suggestion, false)
+	if err := os.WriteFile(path, []byte(message+"\n"+string(content)), 0644); err != nil {
+		return fmt.Errorf("error writing message file: %w", err)
+	}
+	return nil
+}
+
+func runHookCheck(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("error reading message file: %w", err)
+	}
+	message := stripComments(string(content), parser.CommentChar(ctx))
+	if message == "" || slices.ContainsFunc(uncheckedPrefixes, func(prefix string) bool { return strings.HasPrefix(message, prefix) }) {
+		return nil
+	}
+
+	f, _ := newFormatters(ctx, cfg, "", "", nil)
+	violations := f.Check(message)
+	if len(violations) == 0 {
+		return nil
+	}
+	printViolations(violations)
+	return fmt.Errorf("message breaks %d commit rule(s); edit it and commit again", len(violations))
+}
+
+// stripComments returns a message file without the comment lines and what
+// follows git's scissors line, trimmed, as git commit keeps it
+func stripComments(content, commentChar string) string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, commentChar+" ------------------------ >8") {
+			break
+		}
+		if !strings.HasPrefix(line, commentChar) {
+			lines = append(lines, strings.TrimRight(line, " \t\r"))
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	historyAllFlag    bool
+	historyYesFlag    bool
+	historyOutputFlag string
+
+	historyCmd = &cobra.Command{
+		Use:   "history",
+		Short: "Prune, clear, export and import the suggestion history",
+		Long: `Manage the suggestion history gitmit keeps in $XDG_STATE_HOME/gitmit/history.json.
+
+The history records recent suggestions, so they are not proposed twice, and what
+was done with each template, so preferred templates rank higher. How much is
+kept is set by history.maxEntries and history.maxAge in the config. Commands act
+on the current repository unless --all is given.`,
+		Example: `  gitmit history prune --all
+  gitmit history clear
+  gitmit history export --all -o history-backup.json
+  gitmit history import history-backup.json`,
+	}
+
+	historyPruneCmd = &cobra.Command{
+		Use:   "prune",
+		Short: "Drop history entries beyond history.maxEntries or older than history.maxAge",
+		Args:  cobra.NoArgs,
+		RunE:  runHistoryPrune,
+	}
+
+	historyClearCmd = &cobra.Command{
+		Use:   "clear",
+		Short: "Remove the history and template statistics",
+		Args:  cobra.NoArgs,
+		RunE:  runHistoryClear,
+	}
+
+	historyExportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Write the history as JSON to stdout or a file",
+		Args:  cobra.NoArgs,
+		RunE:  runHistoryExport,
+	}
+
+	historyImportCmd = &cobra.Command{
+		Use:   "import <file|->",
+		Short: "Merge a history export into the history",
+		Long: `Merge a file written by "gitmit history export" into the history, or read it
+from stdin with -. Entries already present are skipped, so importing the same
+file twice changes nothing. Repositories are matched by their normalized origin
+URL, so history moves between clones of a project.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runHistoryImport,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyPruneCmd, historyClearCmd, historyExportCmd, historyImportCmd)
+	for _, c := range []*cobra.Command{historyPruneCmd, historyClearCmd, historyExportCmd} {
+		c.Flags().BoolVar(&historyAllFlag, "all", false, "Act on the history of every repository")
+	}
+	historyClearCmd.Flags().BoolVarP(&historyYesFlag, "yes", "y", false, "Clear without asking for confirmation")
+	historyExportCmd.Flags().StringVarP(&historyOutputFlag, "output", "o", "", "Write to a file instead of stdout")
+}
+
+// historyTarget describes what --all selects, for messages
+func historyTarget() string {
+	if historyAllFlag {
+		return "every repository"
+	}
+	return "this repository"
+}
+
+// historyEntries counts history entries for messages
+func historyEntries(n int) string {
+	if n == 1 {
+		return "1 history entry"
+	}
+	return fmt.Sprintf("%d history entries", n)
+}
+
+func runHistoryPrune(cmd *cobra.Command, args []string) error {
+	dropped, err := history.PruneStore(cmd.Context(), historyAllFlag)
+	if err != nil {
+		return err
+	}
+	ui.Success("✅ Pruned %s of %s", historyEntries(dropped), historyTarget())
+	return nil
+}
+
+func runHistoryClear(cmd *cobra.Command, args []string) error {
+	if !historyYesFlag {
+		fmt.Printf("Clear the suggestion history and template statistics of %s? [y/N]: ", historyTarget())
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		fmt.Println()
+		if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+			ui.Warn("❌ Clear cancelled.")
+			return nil
+		}
+	}
+
+	removed, err := history.ClearStore(cmd.Context(), historyAllFlag)
+	if err != nil {
+		return err
+	}
+	ui.Success("✅ Cleared %s of %s", historyEntries(removed), historyTarget())
+	return nil
+}
+
+func runHistoryExport(cmd *cobra.Command, args []string) error {
+	if historyOutputFlag == "" {
+		return history.ExportStore(cmd.Context(), os.Stdout, historyAllFlag)
+	}
+
+	f, err := os.Create(historyOutputFlag)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", historyOutputFlag, err)
+	}
+	if err := history.ExportStore(cmd.Context(), f, historyAllFlag); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error writing %s: %w", historyOutputFlag, err)
+	}
+	ui.Success("✅ Exported the history of %s to %s", historyTarget(), historyOutputFlag)
+	return nil
+}
+
+func runHistoryImport(cmd *cobra.Command, args []string) error {
+	var r io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("error opening %s: %w", args[0], err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	added, err := history.ImportStore(r)
+	if err != nil {
+		return err
+	}
+	ui.Success("✅ Imported %s", historyEntries(added))
+	return nil
+}
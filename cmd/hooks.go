@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/gitcmd"
+	"github.com/andev0x/gitmit/internal/hook"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+// hookRunner runs the configured hooks around the suggestion and commit of a
+// command; hooks that are not set leave everything as it is
+type hookRunner struct {
+	hooks   config.HooksConfig
+	timeout time.Duration
+	branch  string
+	quiet   bool // Print nothing, for servers owning stdout; git's errors are still returned
+}
+
+func newHookRunner(cfg *config.Config, branchName string) *hookRunner {
+	return &hookRunner{hooks: cfg.Hooks, timeout: cfg.Timeouts.HookTimeout(), branch: branchName}
+}
+
+// run runs a hook command when it is set
+func (h *hookRunner) run(ctx context.Context, command string, payload hook.Payload) (hook.Payload, error) {
+	if command == "" {
+		return payload, nil
+	}
+	payload.Branch = h.branch
+	slog.Info("running hook", "hook", payload.Hook, "command", command)
+	return hook.Run(ctx, command, h.timeout, payload)
+}
+
+// preAnalyze keeps the staged changes the pre-analyze hook leaves, with the
+// line counts of gitParser adjusted to them
+func (h *hookRunner) preAnalyze(ctx context.Context, gitParser *parser.GitParser, changes []*parser.Change) ([]*parser.Change, error) {
+	if h.hooks.PreAnalyze == "" {
+		return changes, nil
+	}
+	files := make([]hook.File, 0, len(changes))
+	for _, change := range changes {
+		files = append(files, hook.File{Path: change.File, Action: change.Action, Added: change.Added, Removed: change.Removed})
+	}
+	out, err := h.run(ctx, h.hooks.PreAnalyze, hook.Payload{Hook: hook.PreAnalyze, Files: files})
+	if err != nil {
+		return nil, err
+	}
+
+	keep := make(map[string]bool, len(out.Files))
+	for _, file := range out.Files {
+		keep[file.Path] = true
+	}
+	var kept []*parser.Change
+	gitParser.TotalAdded, gitParser.TotalRemoved = 0, 0
+	for _, change := range changes {
+		if keep[change.File] {
+			kept = append(kept, change)
+			gitParser.TotalAdded += change.Added
+			gitParser.TotalRemoved += change.Removed
+		}
+	}
+	if len(kept) == 0 {
+		return nil, fmt.Errorf("the pre-analyze hook left no staged files to analyze")
+	}
+	return kept, nil
+}
+
+// postSuggest passes a suggestion through the post-suggest hook
+func (h *hookRunner) postSuggest(ctx context.Context, message, source string, commitMessage *analyzer.CommitMessage) (string, error) {
+	if h.hooks.PostSuggest == "" {
+		return message, nil
+	}
+	analysis := &hook.Analysis{
+		Action:   commitMessage.Action,
+		Topic:    commitMessage.Topic,
+		Scope:    commitMessage.Scope,
+		Item:     commitMessage.Item,
+		Purpose:  commitMessage.Purpose,
+		Patterns: commitMessage.ChangePatterns,
+		Breaking: commitMessage.IsMajor,
+	}
+	out, err := h.run(ctx, h.hooks.PostSuggest, hook.Payload{Hook: hook.PostSuggest, Analysis: analysis, Message: message, Source: source})
+	if err != nil {
+		return "", err
+	}
+	return out.Message, nil
+}
+
+// commit commits the staged changes with a message, extra git commit arguments
+// such as --amend, and the pre-commit and post-commit hooks around it. It
+// returns the message committed, which the pre-commit hook may have changed.
+func (h *hookRunner) commit(ctx context.Context, message string, args ...string) (string, error) {
+	out, err := h.run(ctx, h.hooks.PreCommit, hook.Payload{Hook: hook.PreCommit, Message: message})
+	if err != nil {
+		return "", err
+	}
+	if out.Message != message {
+		message = out.Message
+		if !h.quiet {
+			ui.Success("✓ The pre-commit hook changed the message:")
+			ui.Printf("%s\n\n", message)
+		}
+	}
+
+	gitArgs := []string{"commit", "-m", message}
+	if h.quiet {
+		gitArgs = append(gitArgs, "--quiet")
+	}
+	commitCmd := gitcmd.LongCommand(ctx, append(gitArgs, args...)...)
+	// Quietly, the error carries what git and its hooks wrote to stderr
+	if !h.quiet {
+		commitCmd.Stdout = os.Stdout
+		commitCmd.Stderr = os.Stderr
+	}
+	if err := commitCmd.Run(); err != nil {
+		return "", fmt.Errorf("error committing changes: %w", err)
+	}
+
+	// The commit is made, so a failing post-commit hook is only reported
+	if h.hooks.PostCommit != "" {
+		hash, _ := gitcmd.Output(ctx, "", "rev-parse", "HEAD")
+		if _, err := h.run(ctx, h.hooks.PostCommit, hook.Payload{Hook: hook.PostCommit, Message: message, Commit: strings.TrimSpace(hash)}); err != nil {
+			if h.quiet {
+				slog.Warn("post-commit hook failed", "error", err)
+			} else {
+				ui.Warn("⚠ %v", err)
+			}
+		}
+	}
+	return message, nil
+}
@@ -1,19 +1,20 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"github.com/andev0x/gitmit/assets"
 	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/ui"
 )
 
 var (
 	globalFlag bool
+	formatFlag string
 
 	initCmd = &cobra.Command{
 		Use:   "init",
@@ -21,126 +22,115 @@ var (
 		Long: `Generate a sample .gitmit.json configuration file with basic heuristic rules.
 
 This allows you to customize gitmit's behavior without modifying source code.
-You can create either a local config (in the current directory) or a global config (in your home directory).`,
-		Example: `  gitmit init              # Create local .gitmit.json in current directory
-  gitmit init --global    # Create global ~/.gitmit.json in home directory`,
+You can create either a local config (in the current directory) or a global config
+(in $XDG_CONFIG_HOME/gitmit, usually ~/.config/gitmit).
+Use --format to write YAML or TOML instead; those formats include comments explaining each field.`,
+		Example: `  gitmit init                  # Create local .gitmit.json in current directory
+  gitmit init --global        # Create global ~/.config/gitmit/config.json
+  gitmit init --format yaml   # Create a commented .gitmit.yaml
+  gitmit init --format toml   # Create a commented .gitmit.toml`,
 		RunE: runInit,
 	}
 )
 
 func init() {
 	rootCmd.AddCommand(initCmd)
-	initCmd.Flags().BoolVar(&globalFlag, "global", false, "Create global config in home directory (~/.gitmit.json)")
+	initCmd.Flags().BoolVar(&globalFlag, "global", false, "Create global config in $XDG_CONFIG_HOME/gitmit")
+	initCmd.Flags().StringVar(&formatFlag, "format", config.FormatJSON, "Config file format: json, yaml or toml")
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	fileName, err := config.FileNameForFormat(formatFlag)
+	if err != nil {
+		return err
+	}
+
 	// Detect project type automatically
-	projectType := config.DetectProjectType()
-
-	// Create sample configuration
-	sampleConfig := config.Config{
-		ProjectType:       projectType,
-		DiffStatThreshold: 0.5,
-		TopicMappings: map[string]string{
-			"internal/api":      "api",
-			"internal/database": "db",
-			"internal/auth":     "auth",
-			"internal/config":   "config",
-			"cmd":               "cli",
-			"pkg":               "core",
-			"docs":              "docs",
+	projectType := config.DetectProjectType(ctx)
+
+	// Create sample configuration on top of the built-in defaults
+	sampleConfig := config.DefaultConfig()
+	sampleConfig.ProjectType = projectType
+	sampleConfig.TopicMappings = map[string]string{
+		"internal/api":      "api",
+		"internal/database": "db",
+		"internal/auth":     "auth",
+		"internal/config":   "config",
+		"cmd":               "cli",
+		"pkg":               "core",
+		"docs":              "docs",
+	}
+	sampleConfig.KeywordMappings = map[string]string{
+		"authentication": "auth",
+		"database":       "db",
+		"configuration":  "config",
+	}
+	sampleConfig.Keywords = map[string]map[string]int{
+		"feat": {
+			"func":      3,
+			"class":     2,
+			"new":       2,
+			"add":       2,
+			"implement": 2,
 		},
-		KeywordMappings: map[string]string{
-			"authentication": "auth",
-			"database":       "db",
-			"configuration":  "config",
+		"fix": {
+			"bug":     3,
+			"fix":     3,
+			"error":   2,
+			"issue":   2,
+			"resolve": 2,
+			"if err":  2,
+			"try":     1,
+			"catch":   1,
 		},
-		Keywords: map[string]map[string]int{
-			"feat": {
-				"func":      3,
-				"class":     2,
-				"new":       2,
-				"add":       2,
-				"implement": 2,
-			},
-			"fix": {
-				"bug":     3,
-				"fix":     3,
-				"error":   2,
-				"issue":   2,
-				"resolve": 2,
-				"if err":  2,
-				"try":     1,
-				"catch":   1,
-			},
-			"refactor": {
-				"refactor":    3,
-				"restructure": 2,
-				"rename":      2,
-				"move":        2,
-			},
-			"test": {
-				"test":   3,
-				"Test":   3,
-				"assert": 2,
-				"expect": 2,
-				"mock":   2,
-			},
-			"docs": {
-				"docs":          3,
-				"documentation": 3,
-				"//":            1,
-				"comment":       2,
-			},
+		"refactor": {
+			"refactor":    3,
+			"restructure": 2,
+			"rename":      2,
+			"move":        2,
+		},
+		"test": {
+			"test":   3,
+			"Test":   3,
+			"assert": 2,
+			"expect": 2,
+			"mock":   2,
+		},
+		"docs": {
+			"docs":          3,
+			"documentation": 3,
+			"//":            1,
+			"comment":       2,
 		},
-		Templates: map[string]map[string]string{},
-	}
-
-	// Add language-specific keywords based on detected project type
-	switch projectType {
-	case "go":
-		sampleConfig.Keywords["feat"]["type"] = 2
-		sampleConfig.Keywords["feat"]["struct"] = 2
-		sampleConfig.Keywords["feat"]["interface"] = 2
-		sampleConfig.Keywords["fix"]["if err != nil"] = 3
-		sampleConfig.Keywords["fix"]["panic"] = 2
-	case "nodejs":
-		sampleConfig.Keywords["feat"]["export"] = 2
-		sampleConfig.Keywords["feat"]["const"] = 1
-		sampleConfig.Keywords["fix"]["throw"] = 2
-	case "python":
-		sampleConfig.Keywords["feat"]["def"] = 3
-		sampleConfig.Keywords["feat"]["async def"] = 3
-		sampleConfig.Keywords["fix"]["except"] = 2
-		sampleConfig.Keywords["fix"]["raise"] = 2
 	}
 
 	// Determine file path
-	var configPath string
+	configPath := filepath.Join(config.LocalDir(ctx), fileName)
 	if globalFlag {
-		homeDir, err := os.UserHomeDir()
+		configPath, err = config.GlobalConfigPath(formatFlag)
 		if err != nil {
-			return fmt.Errorf("error getting home directory: %w", err)
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+			return fmt.Errorf("error creating config directory: %w", err)
 		}
-		configPath = homeDir + "/.gitmit.json"
-	} else {
-		configPath = ".gitmit.json"
 	}
 
 	// Check if config file already exists
 	if _, err := os.Stat(configPath); err == nil {
-		color.Yellow("⚠ Config file already exists: %s", configPath)
-		fmt.Print("Overwrite? [y/N]: ")
+		ui.Warn("⚠ Config file already exists: %s", configPath)
+		ui.Print("Overwrite? [y/N]: ")
 		var answer string
 		fmt.Scanln(&answer)
 		if answer != "y" && answer != "Y" {
-			color.Yellow("❌ Cancelled.")
+			ui.Warn("❌ Cancelled.")
 			return nil
 		}
 	}
 
-	// Marshal to JSON with indentation
-	data, err := json.MarshalIndent(sampleConfig, "", "  ")
+	// Marshal in the requested format
+	data, err := config.Marshal(sampleConfig, formatFlag)
 	if err != nil {
 		return fmt.Errorf("error marshaling config: %w", err)
 	}
@@ -151,11 +141,14 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error writing config file: %w", err)
 	}
 
-	color.Green("✅ Created config file: %s", configPath)
-	color.Blue("\n📝 Detected project type: %s", projectType)
-	
+	ui.Success("✅ Created config file: %s", configPath)
+	ui.Heading("\n📝 Detected project type: %s", projectType)
+	if projectType != "generic" {
+		ui.Muted("Its language pack adds keywords and topic mappings for %s code; extend it under \"languages\".", projectType)
+	}
+
 	msg, _ := assets.GetInitSuccess()
-	fmt.Println(msg)
+	ui.Println(msg)
 
 	return nil
 }
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/style"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Run a Model Context Protocol server for AI coding agents",
+	Long: `Serve gitmit as a Model Context Protocol (MCP) server over standard input and
+output, so coding agents can analyze staged changes, get commit messages and
+commit as tool calls instead of reconstructing the git analysis themselves.
+
+Tools:
+
+  analyze_changes   the analysis of the staged changes: type, topic, scope,
+                    files and the signals that decided the type
+  propose_message   the best conventional commit message for the staged
+                    changes, with ranked alternatives
+  commit            commit the staged changes with a message
+
+Every tool takes an optional "repo" path, the working directory by default.
+Register the server in the agent's MCP configuration with the command
+"gitmit mcp".`,
+	Example: `  claude mcp add gitmit -- gitmit mcp
+  {"mcpServers": {"gitmit": {"command": "gitmit", "args": ["mcp"]}}}`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runMCP,
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
+
+// mcpProtocolVersion is the MCP revision the server implements
+const mcpProtocolVersion = "2024-11-05"
+
+// mcpTool describes a tool in the tools/list response
+type mcpTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+// mcpToolResult is the result of a tools/call request
+type mcpToolResult struct {
+	Content []mcpContent `json:"content"`
+	IsError bool         `json:"isError,omitempty"`
+}
+
+type mcpContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// mcpCallParams are the params of a tools/call request
+type mcpCallParams struct {
+	Name      string `json:"name"`
+	Arguments struct {
+		Repo    string `json:"repo"`
+		Max     int    `json:"max"`
+		Message string `json:"message"`
+	} `json:"arguments"`
+}
+
+// mcpRepoProperty is the repo argument every tool takes
+var mcpRepoProperty = map[string]interface{}{
+	"type":        "string",
+	"description": "Path of the git repository (default: the server's working directory)",
+}
+
+var mcpTools = []mcpTool{
+	{
+		Name:        "analyze_changes",
+		Description: "Analyze the staged git changes: the conventional commit type they look like, with the signals behind it and plausible alternatives, the topic, scope, files and line counts.",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"repo": mcpRepoProperty},
+		},
+	},
+	{
+		Name:        "propose_message",
+		Description: "Suggest a conventional commit message for the staged git changes, following the repository's config, templates and commit style, with ranked alternatives and why each was suggested.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"repo": mcpRepoProperty,
+				"max":  map[string]interface{}{"type": "integer", "description": "Number of ranked suggestions (default: 3)"},
+			},
+		},
+	},
+	{
+		Name:        "commit",
+		Description: "Commit the staged git changes with the given message, and return the new commit's hash and subject.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"repo":    mcpRepoProperty,
+				"message": map[string]interface{}{"type": "string", "description": "Commit message, subject line first"},
+			},
+			"required": []string{"message"},
+		},
+	},
+}
+
+// mcpDefaultSuggestions is how many suggestions propose_message ranks by default
+const mcpDefaultSuggestions = 3
+
+func runMCP(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	home, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error reading the working directory: %w", err)
+	}
+	return serveMCP(ctx, newSuggestServer(home, ""), os.Stdin, os.Stdout)
+}
+
+// serveMCP answers the newline-delimited JSON-RPC messages of an MCP client
+// until its input ends
+func serveMCP(ctx context.Context, s *suggestServer, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	encoder := json.NewEncoder(out)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var req rpcRequest
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			encoder.Encode(rpcResponse{JSONRPC: "2.0", Error: &rpcError{Code: rpcParseError, Message: "parse error: " + err.Error()}, ID: nullID(nil)})
+			continue
+		}
+		result, rpcErr := s.handleMCP(ctx, req)
+		// Notifications such as notifications/initialized get no response
+		if len(req.ID) == 0 {
+			continue
+		}
+		response := rpcResponse{JSONRPC: "2.0", Result: result, Error: rpcErr, ID: req.ID}
+		if err := encoder.Encode(response); err != nil {
+			return fmt.Errorf("error writing MCP response: %w", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading MCP requests: %w", err)
+	}
+	return nil
+}
+
+// handleMCP answers one MCP request
+func (s *suggestServer) handleMCP(ctx context.Context, req rpcRequest) (interface{}, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": mcpProtocolVersion,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]string{"name": "gitmit", "version": version},
+		}, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": mcpTools}, nil
+	case "tools/call":
+		var params mcpCallParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: "invalid params: " + err.Error()}
+		}
+		return s.callTool(ctx, params)
+	}
+	if strings.HasPrefix(req.Method, "notifications/") {
+		return nil, nil
+	}
+	return nil, &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("method %q not found", req.Method)}
+}
+
+// callTool runs a tool. Failures of the tool itself, such as a commit hook
+// rejecting the commit, are results the agent can read rather than errors.
+func (s *suggestServer) callTool(ctx context.Context, params mcpCallParams) (interface{}, *rpcError) {
+	args := params.Arguments
+	var result interface{}
+	var err error
+	switch params.Name {
+	case "analyze_changes":
+		result, err = s.analyze(ctx, serveRequest{Repo: args.Repo})
+	case "propose_message":
+		max := args.Max
+		if max <= 0 {
+			max = mcpDefaultSuggestions
+		}
+		result, err = s.suggest(ctx, serveRequest{Repo: args.Repo, Max: max})
+	case "commit":
+		result, err = s.commit(ctx, args.Repo, args.Message)
+	default:
+		return nil, &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("unknown tool %q", params.Name)}
+	}
+	if err != nil {
+		return mcpToolResult{Content: []mcpContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	text, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, &rpcError{Code: rpcServerError, Message: err.Error()}
+	}
+	return mcpToolResult{Content: []mcpContent{{Type: "text", Text: string(text)}}}, nil
+}
+
+// commitResponse is the result of the commit tool
+type commitResponse struct {
+	Hash    string `json:"hash"`
+	Subject string `json:"subject"`
+}
+
+// commit commits the staged changes of a repository with a message
+func (s *suggestServer) commit(ctx context.Context, repo, message string) (*commitResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if strings.TrimSpace(message) == "" {
+		return nil, fmt.Errorf("the commit message is empty")
+	}
+	ctx, state, err := s.enter(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	// gitmit's commit hooks run as for propose, so they cannot be bypassed
+	branchName, _ := parser.NewGitParser().GetCurrentBranch(ctx)
+	hooks := newHookRunner(state.cfg, branchName)
+	hooks.quiet = true
+	if message, err = hooks.commit(ctx, message); err != nil {
+		return nil, err
+	}
+	hash, err := parser.ResolveRevision(ctx, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	return &commitResponse{Hash: hash, Subject: style.Subject(message)}, nil
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// mcpSession sends requests to an MCP server and returns its responses by id
+func mcpSession(t *testing.T, s *suggestServer, requests ...string) map[string]rpcResponse {
+	t.Helper()
+	var out bytes.Buffer
+	if err := serveMCP(context.Background(), s, strings.NewReader(strings.Join(requests, "\n")), &out); err != nil {
+		t.Fatal(err)
+	}
+	responses := map[string]rpcResponse{}
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var resp struct {
+			rpcResponse
+			Result json.RawMessage `json:"result"`
+		}
+		if err := decoder.Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		resp.rpcResponse.Result = resp.Result
+		responses[string(resp.ID)] = resp.rpcResponse
+	}
+	return responses
+}
+
+// toolResult decodes the result of a tools/call response
+func toolResult(t *testing.T, resp rpcResponse) mcpToolResult {
+	t.Helper()
+	if resp.Error != nil {
+		t.Fatalf("tools/call error: %+v", resp.Error)
+	}
+	var result mcpToolResult
+	if err := json.Unmarshal(resp.Result.(json.RawMessage), &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Content) != 1 {
+		t.Fatalf("tools/call content = %+v, want one item", result.Content)
+	}
+	return result
+}
+
+func TestMCPListTools(t *testing.T) {
+	responses := mcpSession(t, newSuggestServer(t.TempDir(), ""),
+		`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {}}`,
+		`{"jsonrpc": "2.0", "method": "notifications/initialized"}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "resources/list"}`,
+	)
+	if len(responses) != 3 {
+		t.Fatalf("got %d responses, want 3: the notification gets none", len(responses))
+	}
+
+	var list struct {
+		Tools []mcpTool `json:"tools"`
+	}
+	if err := json.Unmarshal(responses["2"].Result.(json.RawMessage), &list); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+		if tool.InputSchema["type"] != "object" {
+			t.Errorf("tool %s input schema type = %v, want object", tool.Name, tool.InputSchema["type"])
+		}
+	}
+	if got, want := strings.Join(names, ","), "analyze_changes,propose_message,commit"; got != want {
+		t.Errorf("tools = %s, want %s", got, want)
+	}
+
+	if err := responses["3"].Error; err == nil || err.Code != rpcMethodNotFound {
+		t.Errorf("unknown method error = %+v, want code %d", err, rpcMethodNotFound)
+	}
+}
+
+func TestMCPProposeMessage(t *testing.T) {
+	repo := testRepo(t)
+	responses := mcpSession(t, newSuggestServer(t.TempDir(), ""),
+		`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "propose_message", "arguments": {"repo": `+strconvQuote(repo)+`, "max": 2}}}`,
+	)
+	result := toolResult(t, responses["1"])
+	if result.IsError {
+		t.Fatalf("propose_message failed: %s", result.Content[0].Text)
+	}
+	var got suggestResponse
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Message == "" || got.Files != 1 {
+		t.Errorf("suggestion = %+v, want a message for one staged file", got)
+	}
+	if len(got.Suggestions) == 0 || len(got.Suggestions) > 2 {
+		t.Errorf("got %d suggestions, want 1 or 2", len(got.Suggestions))
+	}
+}
+
+func TestMCPCommitErrors(t *testing.T) {
+	repo := testRepo(t)
+	head := git(t, repo, "rev-parse", "HEAD")
+
+	tests := []struct {
+		name      string
+		arguments string
+		want      string
+	}{
+		{name: "empty message", arguments: `{"repo": ` + strconvQuote(repo) + `, "message": "  "}`, want: "empty"},
+		{name: "no message", arguments: `{"repo": ` + strconvQuote(repo) + `}`, want: "empty"},
+		{name: "not a repository", arguments: `{"repo": ` + strconvQuote(t.TempDir()) + `, "message": "feat: add login"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := mcpSession(t, newSuggestServer(t.TempDir(), ""),
+				`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "commit", "arguments": `+tt.arguments+`}}`,
+			)
+			// Failed commits are tool results the agent reads, not protocol errors
+			result := toolResult(t, responses["1"])
+			if !result.IsError {
+				t.Errorf("commit succeeded: %s", result.Content[0].Text)
+			}
+			if !strings.Contains(result.Content[0].Text, tt.want) {
+				t.Errorf("error = %q, want it to mention %q", result.Content[0].Text, tt.want)
+			}
+		})
+	}
+	if now := git(t, repo, "rev-parse", "HEAD"); now != head {
+		t.Errorf("HEAD moved from %s to %s", head, now)
+	}
+
+	responses := mcpSession(t, newSuggestServer(t.TempDir(), ""),
+		`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "push", "arguments": {}}}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": "nope"}`,
+	)
+	for id, resp := range responses {
+		if resp.Error == nil || resp.Error.Code != rpcInvalidParams {
+			t.Errorf("request %s error = %+v, want code %d", id, resp.Error, rpcInvalidParams)
+		}
+	}
+}
+
+func TestMCPCommit(t *testing.T) {
+	repo := testRepo(t)
+	responses := mcpSession(t, newSuggestServer(t.TempDir(), ""),
+		`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "commit", "arguments": {"repo": `+strconvQuote(repo)+`, "message": "feat(auth): add login\n\nFirst cut."}}}`,
+	)
+	result := toolResult(t, responses["1"])
+	if result.IsError {
+		t.Fatalf("commit failed: %s", result.Content[0].Text)
+	}
+	var got commitResponse
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &got); err != nil {
+		t.Fatal(err)
+	}
+	if head := git(t, repo, "rev-parse", "HEAD"); got.Hash != head {
+		t.Errorf("hash = %s, want HEAD %s", got.Hash, head)
+	}
+	if got.Subject != "feat(auth): add login" {
+		t.Errorf("subject = %q", got.Subject)
+	}
+	if subject := git(t, repo, "log", "-1", "--format=%s"); subject != got.Subject {
+		t.Errorf("committed subject = %q, want %q", subject, got.Subject)
+	}
+}
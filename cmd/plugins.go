@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/formatter"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/plugin"
+)
+
+// loadPlugins returns the plugins that are not turned off in the config, or
+// nil when there are none to run
+func loadPlugins(cfg *config.Config) *plugin.Set {
+	if !cfg.Plugins.Enabled {
+		return nil
+	}
+	dir, err := plugin.Dir()
+	if err != nil {
+		slog.Debug("no plugins directory", "error", err)
+		return nil
+	}
+	found, err := plugin.Discover(dir)
+	if err != nil {
+		slog.Warn("plugins not loaded", "error", err)
+		return nil
+	}
+	set := &plugin.Set{Timeout: cfg.Timeouts.PluginTimeout()}
+	for _, p := range found {
+		if !cfg.Plugins.IsDisabled(p.Name) {
+			set.Plugins = append(set.Plugins, p)
+		}
+	}
+	if len(set.Plugins) == 0 {
+		return nil
+	}
+	return set
+}
+
+// runAnalyzePlugins asks the plugins about the staged changes. Their scopes are
+// added to the topic mappings of a copy of cfg, where the config's own
+// mappings win, and their hints are returned for the change patterns.
+func runAnalyzePlugins(ctx context.Context, cfg *config.Config, changes []*parser.Change, branchName string) (*config.Config, []string) {
+	set := loadPlugins(cfg)
+	if set == nil {
+		return cfg, nil
+	}
+	files := make([]plugin.File, 0, len(changes))
+	for _, change := range changes {
+		files = append(files, plugin.File{Path: change.File, Action: change.Action, Added: change.Added, Removed: change.Removed, Diff: change.Diff})
+	}
+	resp, err := set.Analyze(ctx, branchName, files)
+	if err != nil {
+		slog.Warn("plugin hints and scopes left out", "error", err)
+	}
+	if len(resp.Scopes) > 0 {
+		copied := *cfg
+		copied.TopicMappings = make(map[string]string, len(cfg.TopicMappings)+len(resp.Scopes))
+		for path, scope := range resp.Scopes {
+			copied.TopicMappings[path] = scope
+		}
+		for path, topic := range cfg.TopicMappings {
+			copied.TopicMappings[path] = topic
+		}
+		cfg = &copied
+	}
+	return cfg, resp.Hints
+}
+
+// addPluginHints adds the hints of plugins to the change patterns of an analysis
+func addPluginHints(commitMessage *analyzer.CommitMessage, hints []string) {
+	for _, hint := range hints {
+		if !slices.Contains(commitMessage.ChangePatterns, hint) {
+			commitMessage.ChangePatterns = append(commitMessage.ChangePatterns, hint)
+		}
+	}
+}
+
+// pluginChecks returns the check of messages on the current branch against the
+// rules of the plugins, for a formatter's extra checks, or nil when there are
+// no plugins
+func pluginChecks(ctx context.Context, cfg *config.Config) []func(string) []formatter.Violation {
+	set := loadPlugins(cfg)
+	if set == nil {
+		return nil
+	}
+	branchName, err := parser.NewGitParser().GetCurrentBranch(ctx)
+	if err != nil {
+		slog.Debug("plugin rules checked without a branch", "error", err)
+	}
+	return []func(string) []formatter.Violation{func(message string) []formatter.Violation {
+		found, err := set.Check(ctx, branchName, message)
+		if err != nil {
+			slog.Warn("plugin rules left out", "error", err)
+		}
+		violations := make([]formatter.Violation, 0, len(found))
+		for _, v := range found {
+			violations = append(violations, formatter.Violation{Rule: v.Rule, Message: v.Message})
+		}
+		return violations
+	}}
+}
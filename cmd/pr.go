@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/changelog"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/gitcmd"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/pr"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	prBaseFlag     string
+	prCreateFlag   bool
+	prDraftFlag    bool
+	prProviderFlag string
+
+	prCmd = &cobra.Command{
+		Use:   "pr",
+		Short: "Generate a pull request title and description for the current branch",
+		Long: `Analyze the commits and the cumulative diff between the current branch and its
+base, then print a pull request title and a markdown description with a summary,
+the changes grouped by area, breaking changes and notes on testing.
+
+The title is the subject of the most significant conventional commit (breaking
+changes, then features, then fixes, ...), or a suggestion for the cumulative
+diff when no commit uses a conventional type.
+
+The base defaults to the default branch of origin, then main or master. With
+--create the pull request is opened with the GitHub CLI (gh) or, for GitLab
+remotes, the GitLab CLI (glab).`,
+		Example: `  gitmit pr
+  gitmit pr --base develop
+  gitmit pr --create --draft`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runPR,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(prCmd)
+	prCmd.Flags().StringVar(&prBaseFlag, "base", "", "Branch the pull request targets (default: origin's default branch, main or master)")
+	prCmd.Flags().BoolVar(&prCreateFlag, "create", false, "Open the pull request with gh or glab")
+	prCmd.Flags().BoolVar(&prDraftFlag, "draft", false, "Open the pull request as a draft (with --create)")
+	prCmd.Flags().StringVar(&prProviderFlag, "provider", "auto", "CLI used by --create: auto, github or gitlab")
+}
+
+func runPR(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	base := prBaseFlag
+	if base == "" {
+		if base, err = parser.DefaultBase(ctx); err != nil {
+			return err
+		}
+	}
+	mergeBase, err := parser.MergeBase(ctx, base, "HEAD")
+	if err != nil {
+		return err
+	}
+
+	commits, err := parser.ParseCommits(ctx, mergeBase, "HEAD")
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits between %s and HEAD", base)
+	}
+
+	gitParser := parser.NewGitParser()
+	changes, err := gitParser.ParseRangeChanges(ctx, mergeBase, "HEAD")
+	if err != nil {
+		return err
+	}
+
+	title, err := prTitle(ctx, cfg, gitParser, commits, changes)
+	if err != nil {
+		return err
+	}
+	request := pr.Describe(title, commits, changes, cfg.Tests.Patterns)
+
+	ui.Heading("📝 Pull request against %s (%d commits):\n", base, len(commits))
+	ui.Success("%s\n", request.Title)
+	fmt.Println(request.Body)
+
+	if !prCreateFlag {
+		return nil
+	}
+	return createPR(ctx, request, strings.TrimPrefix(base, "origin/"))
+}
+
+// prTitle uses the subject of the most significant conventional commit, or a
+// suggestion for the cumulative diff when no commit follows the convention
+func prTitle(ctx context.Context, cfg *config.Config, gitParser *parser.GitParser, commits []*parser.Commit, changes []*parser.Change) (string, error) {
+	if entry := changelog.MostSignificant(changelog.Parse(commits)); entry != nil {
+		return entry.Commit.Subject, nil
+	}
+	if len(commits) == 1 {
+		return commits[0].Subject, nil
+	}
+
+	hist, err := history.LoadHistory(ctx)
+	if err != nil {
+		return "", err
+	}
+	suggestion, err := heuristicSuggestion(ctx, cfg, hist, gitParser, changes)
+	if err != nil || suggestion == "" {
+		return commits[len(commits)-1].Subject, err
+	}
+
+	// Titles are single lines, so the subject is never wrapped into a body
+	f, _ := newFormatters(ctx, cfg, "", "", learnRepoStyle(cfg, hist))
+	f.ShortenSubject = true
+	subject, _, _ := strings.Cut(f.FormatMessage(suggestion, false), "\n")
+	return subject, nil
+}
+
+// createPR opens the pull request with the CLI of the hosting provider
+func createPR(ctx context.Context, request *pr.PullRequest, base string) error {
+	provider := prProviderFlag
+	if provider == "auto" {
+		provider = "github"
+		if remote, err := gitcmd.Output(ctx, "", "remote", "get-url", "origin"); err == nil && strings.Contains(remote, "gitlab") {
+			provider = "gitlab"
+		}
+	}
+
+	var name string
+	var args []string
+	switch provider {
+	case "github":
+		name = "gh"
+		args = []string{"pr", "create", "--title", request.Title, "--body", request.Body, "--base", base}
+	case "gitlab":
+		name = "glab"
+		args = []string{"mr", "create", "--title", request.Title, "--description", request.Body, "--target-branch", base, "--yes"}
+	default:
+		return fmt.Errorf("unknown provider %q (expected auto, github or gitlab)", provider)
+	}
+	if prDraftFlag {
+		args = append(args, "--draft")
+	}
+
+	if _, err := exec.LookPath(name); err != nil {
+		return fmt.Errorf("%s is not installed; install it or copy the description above", name)
+	}
+	createCmd := exec.CommandContext(ctx, name, args...)
+	createCmd.Stdin = os.Stdin
+	createCmd.Stdout = os.Stdout
+	createCmd.Stderr = os.Stderr
+	if err := createCmd.Run(); err != nil {
+		return fmt.Errorf("error creating pull request with %s: %w", name, err)
+	}
+	return nil
+}
@@ -2,32 +2,45 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
-	"os/exec"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"github.com/andev0x/gitmit/assets"
-	"github.com/andev0x/gitmit/internal/analyzer"
 	"github.com/andev0x/gitmit/internal/ai"
+	"github.com/andev0x/gitmit/internal/analyzer"
 	"github.com/andev0x/gitmit/internal/config"
 	"github.com/andev0x/gitmit/internal/formatter"
+	"github.com/andev0x/gitmit/internal/gitcmd"
 	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/logging"
 	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/safety"
+	"github.com/andev0x/gitmit/internal/spell"
+	"github.com/andev0x/gitmit/internal/style"
 	"github.com/andev0x/gitmit/internal/templater"
+	"github.com/andev0x/gitmit/internal/ticket"
+	"github.com/andev0x/gitmit/internal/ui"
 )
 
 var (
-	stagedFlag     bool
-	summaryFlag    bool
-	autoFlag       bool
-	dryRunFlag     bool
-	debugFlag      bool
-	contextFlag    bool
-	maxSuggestions int
+	stagedFlag       bool
+	summaryFlag      bool
+	autoFlag         bool
+	dryRunFlag       bool
+	debugFlag        bool
+	contextFlag      bool
+	maxSuggestions   int
+	templateFileFlag string
+	allowLeftovers   bool
+	explainFlag      bool
+	noStatFlag       bool
 
 	proposeCmd = &cobra.Command{
 		Use:   "propose",
@@ -37,12 +50,30 @@ var (
 When using --interactive (-i) or --suggestions (-s), multiple suggestions will be shown
 ranked by how well they match the context (file types, changes, purposes).
 
-The --context flag shows what was analyzed to help understand the suggestions.`,
+The --context flag shows what was analyzed to help understand the suggestions,
+and --explain the full decision trail behind the suggested message: the files,
+keywords and diff hints that decided its type, the template selected and how
+its score adds up.
+
+With --stdin or --patch-file the changes are read from a unified diff, such
+as the output of git diff or git format-patch, instead of the index. The
+suggestion is only printed, so this works without a checkout, for example
+in code review bots.
+
+With --tag the new commit is tagged with an annotated tag whose message lists
+the changes since the previous tag. Without it, committing a release or
+version bump such as "chore(release): v1.3.0" offers to tag it.`,
 		Example: `  gitmit propose              # Get best suggestion
   gitmit propose -i          # Choose from multiple suggestions
   gitmit propose -s          # Show ranked suggestions
   gitmit propose --context   # Show what was analyzed
-  gitmit propose --auto      # Auto-commit with best suggestion`,
+  gitmit propose --explain   # Show why this message was suggested
+  gitmit propose --auto      # Auto-commit with best suggestion
+  gitmit propose --add-all   # Stage everything, then suggest
+  gitmit propose --auto --tag v1.3.0
+  git diff main | gitmit propose --stdin --summary
+  gitmit propose --patch-file pr-1234.diff
+  gitmit propose --template-file ~/team-templates.json`,
 		RunE: runPropose,
 	}
 )
@@ -55,268 +86,1279 @@ func init() {
 	proposeCmd.Flags().BoolVar(&autoFlag, "auto", false, "Auto-commit with the generated message")
 	proposeCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "Preview without committing")
 	proposeCmd.Flags().BoolVar(&debugFlag, "debug", false, "Print debug info (analyzer output + chosen templates)")
+	proposeCmd.Flags().MarkDeprecated("debug", "use --context for the analysis and -vv for debug logs")
 	proposeCmd.Flags().BoolVar(&contextFlag, "context", false, "Show what was analyzed to generate suggestions")
+	proposeCmd.Flags().BoolVar(&fullFlag, "full", false, "List every changed file with --context instead of collapsing long lists")
 	proposeCmd.Flags().IntVar(&maxSuggestions, "max-suggestions", 5, "Maximum number of suggestions to show")
+	proposeCmd.Flags().StringVar(&templateFileFlag, "template-file", "", "Template file to use instead of templates.json")
+	proposeCmd.Flags().BoolVar(&allowLeftovers, "allow-leftovers", false, "Let --auto commit debug statements and conflict markers")
+	proposeCmd.Flags().BoolVar(&addFlag, "add", false, "Stage the changes to tracked files first")
+	proposeCmd.Flags().BoolVar(&addAllFlag, "add-all", false, "Stage all changes first, including untracked files")
+	proposeCmd.Flags().StringVar(&tagFlag, "tag", "", "Create an annotated tag summarizing the changes since the previous tag after committing")
+	proposeCmd.Flags().BoolVar(&explainFlag, "explain", false, "Show the decision trail behind the suggested message")
+	proposeCmd.Flags().BoolVar(&noStatFlag, "no-stat", false, "Do not show the summary of the changed files above the suggestion")
+	proposeCmd.Flags().BoolVar(&stdinFlag, "stdin", false, "Suggest a message for the unified diff read from stdin instead of the staged changes")
+	proposeCmd.Flags().StringVar(&patchFileFlag, "patch-file", "", "Suggest a message for the unified diff in a file instead of the staged changes")
+	proposeCmd.MarkFlagsMutuallyExclusive("stdin", "patch-file")
+	for _, flag := range []string{"auto", "add", "add-all", "tag"} {
+		proposeCmd.MarkFlagsMutuallyExclusive("stdin", flag)
+		proposeCmd.MarkFlagsMutuallyExclusive("patch-file", flag)
+	}
 }
 
 func runPropose(cmd *cobra.Command, args []string) error {
-	cfg, err := config.LoadConfig()
+	ctx := cmd.Context()
+	// --debug predates --verbose: it shows the analysis and every log detail
+	if debugFlag {
+		contextFlag = true
+		logging.Setup(os.Stderr, 2)
+	}
+
+	cfg, err := config.LoadConfig(ctx)
 	if err != nil {
 		return err
 	}
 
-	history, err := history.LoadHistory()
+	hist, err := history.LoadHistory(ctx)
 	if err != nil {
 		return err
 	}
 
-	gitParser := parser.NewGitParser()
-	changes, err := gitParser.ParseStagedChanges()
+	p, err := analyzeProposal(ctx, cfg, hist)
 	if err != nil {
 		return err
 	}
+	if err := p.prepare(ctx); err != nil {
+		return err
+	}
+	if err := p.suggest(ctx); err != nil {
+		return err
+	}
+	p.printAnalysis()
 
-	if len(changes) == 0 {
-		return fmt.Errorf("⚠️ no staged changes")
+	if !summaryFlag && !autoFlag && !dryRunFlag {
+		return p.interact(ctx)
 	}
+	return p.finish(ctx)
+}
+
+// maxRegenerations limits how many times a suggestion is regenerated
+const maxRegenerations = 10
+
+// proposal is the state of a propose run: the analysis of the staged changes,
+// what turns it into messages and checks them, and the current suggestion
+type proposal struct {
+	cfg           *config.Config
+	hist          *history.CommitHistory
+	changes       []*parser.Change
+	branchName    string
+	hooks         *hookRunner
+	analyzer      *analyzer.Analyzer
+	commitMessage *analyzer.CommitMessage
+	missingTests  []analyzer.MissingTest
+	risk          *analyzer.Risk
+	body          string // Body of every suggestion, such as a release's list of commits
+	ticket        *ticket.Ticket
+
+	templater     *templater.Templater
+	repoStyle     *style.Style
+	f             *formatter.Formatter // Formats suggestions
+	editFormatter *formatter.Formatter // Formats the user's edits
+	checker       *spell.Checker
+	leftovers     []safety.Finding
+
+	// The heuristic suggestion, which is always available, and its template
+	formattedHeuristic string
+	heuristicTemplate  string
+
+	// The current suggestion, the template behind it (empty for AI suggestions)
+	// and whether the user edited it, to learn template preferences from outcomes
+	finalMessage    string
+	currentTemplate string
+	usingAI         bool
+	edited          bool
+
+	// The suggestions made in the interactive loop, which regenerating skips
+	used          map[string]bool
+	regenerations int
+
+	// Tiny changes on top of an unpushed HEAD may be amended into it, with a
+	// message for both; the analysis of the staged changes alone is kept to
+	// switch back
+	amendable      bool
+	amending       bool
+	stagedAnalysis *analyzer.CommitMessage
+}
 
-	analyzer := analyzer.NewAnalyzer(changes, cfg)
-	branchName, _ := gitParser.GetCurrentBranch()
-	commitMessage := analyzer.AnalyzeChanges(gitParser.TotalAdded, gitParser.TotalRemoved, branchName)
+// analyzeProposal reads the staged changes, or the patch of --stdin and
+// --patch-file, and analyzes them
+func analyzeProposal(ctx context.Context, cfg *config.Config, hist *history.CommitHistory) (*proposal, error) {
+	// A patch has nothing staged to commit, so its suggestion is only printed
+	gitParser := parser.NewGitParser()
+	var changes []*parser.Change
+	var err error
+	if readingPatch() {
+		dryRunFlag = !summaryFlag
+		changes, err = patchChanges(gitParser)
+	} else {
+		changes, err = stagedChanges(ctx, gitParser, !autoFlag && !summaryFlag && !dryRunFlag)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The pre-analyze hook may leave files, such as generated code, out of the analysis
+	branchName, _ := gitParser.GetCurrentBranch(ctx)
+	hooks := newHookRunner(cfg, branchName)
+	changes, err = hooks.preAnalyze(ctx, gitParser, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	// A tag that cannot be created is reported before anything is committed
+	if tagFlag != "" {
+		if err := parser.CheckNewTag(ctx, tagFlag); err != nil {
+			return nil, err
+		}
+	}
+
+	// Directory overrides apply when most staged files belong to one configured path
+	var files []string
+	for _, change := range changes {
+		files = append(files, change.File)
+	}
+	cfg, _, err = cfg.ForFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	// Source files staged without their tests are pointed out, and noted in the message if configured
+	var missingTests []analyzer.MissingTest
+	if cfg.Tests.Nudge || cfg.Tests.AddNote || cfg.Risk.Enabled {
+		missingTests = analyzer.MissingTests(changes, cfg.Tests.Mappings)
+	}
+
+	// Risky changes are flagged, and may need confirming before --auto commits them
+	var risk *analyzer.Risk
+	if cfg.Risk.Enabled {
+		risk = analyzer.AssessRisk(changes, missingTests, cfg.Risk)
+	}
+
+	// Plugins may map paths to scopes and point out patterns of the changes
+	cfg, pluginHints := runAnalyzePlugins(ctx, cfg, changes, branchName)
+
+	// A merge in progress is described by what it merges and the conflicts it
+	// resolves, as its staged changes combine everything the merge brings in
+	var merge *parser.Merge
+	if !readingPatch() {
+		if merge, err = parser.CurrentMerge(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	// A cherry-pick or rebase in progress applies a commit again, whose message
+	// is offered as it is or improved rather than replaced
+	var pick *parser.Pick
+	if merge == nil && !readingPatch() {
+		if pick, err = parser.CurrentPick(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	// Changes undoing a recent commit are its revert, whatever else they look like
+	var revert *analyzer.Revert
+	if merge == nil && pick == nil && !readingPatch() {
+		revert = revertedCommit(ctx, changes)
+	}
+
+	var commitMessage *analyzer.CommitMessage
+	changeAnalyzer := analyzer.NewAnalyzer(changes, cfg)
+	switch {
+	case merge != nil:
+		commitMessage = changeAnalyzer.AnalyzeMerge(merge, gitParser.TotalAdded, gitParser.TotalRemoved)
+	case revert != nil:
+		commitMessage = changeAnalyzer.AnalyzeRevert(revert, gitParser.TotalAdded, gitParser.TotalRemoved)
+	default:
+		commitMessage = changeAnalyzer.AnalyzeChanges(ctx, gitParser.TotalAdded, gitParser.TotalRemoved, branchName)
+	}
 	if commitMessage == nil {
-		return fmt.Errorf("could not analyze changes")
+		return nil, fmt.Errorf("could not analyze changes")
 	}
+	addPluginHints(commitMessage, pluginHints)
+	commitMessage.Pick = pick
 
-	templater, err := templater.NewTemplater("templates.json", history)
+	// A version bump is a release, whose body lists the commits since the latest
+	// tag, a merge's body lists the files whose conflicts it resolves, a
+	// revert's names the reverted commit and a picked commit keeps its body
+	body := releaseBody(ctx, commitMessage)
+	switch {
+	case commitMessage.Merge != nil:
+		body = commitMessage.Merge.Body()
+	case commitMessage.Revert != nil:
+		body = commitMessage.Revert.Body()
+	case commitMessage.Pick != nil:
+		body = commitMessage.Pick.Body()
+	}
+
+	return &proposal{
+		cfg:           cfg,
+		hist:          hist,
+		changes:       changes,
+		branchName:    branchName,
+		hooks:         hooks,
+		analyzer:      changeAnalyzer,
+		commitMessage: commitMessage,
+		missingTests:  missingTests,
+		risk:          risk,
+		body:          body,
+	}, nil
+}
+
+// prepare sets up what the suggestions go through: the templates, the ticket
+// prefix and footer, the formatters and the spelling and leftover checks
+func (p *proposal) prepare(ctx context.Context) error {
+	cfg := p.cfg
+
+	// A branch policy may select another template pack and require a ticket prefix
+	policy := cfg.BranchPolicy(p.branchName)
+	t, err := templater.NewTemplater(selectTemplateFile(cfg, policy, templateFileFlag), p.hist)
 	if err != nil {
 		return err
 	}
+	if err := t.AddTemplates(cfg.Templates); err != nil {
+		return err
+	}
+	t.RestrictTypes(cfg.Types)
+	p.templater = t
 
-	f := formatter.NewFormatter(cfg.MaxSubjectLength, cfg.MaxBodyLength)
+	ticketPrefix, ticketFooters, err := p.resolveTickets(ctx, policy)
+	if err != nil {
+		return err
+	}
+
+	footer := ""
+	if cfg.Codeowners.MentionOwners && len(p.commitMessage.Owners) > 0 {
+		footer = "Owners: " + strings.Join(p.commitMessage.Owners, ", ")
+	}
+	if cfg.Tests.AddNote && len(p.missingTests) > 0 {
+		footer = strings.TrimPrefix(footer+"\nNote: no tests updated", "\n")
+	}
+	for _, ticketFooter := range ticketFooters {
+		footer = strings.TrimPrefix(footer+"\n"+ticketFooter, "\n")
+	}
+
+	p.repoStyle = learnRepoStyle(cfg, p.hist)
+	p.f, p.editFormatter = newFormatters(ctx, cfg, ticketPrefix, footer, p.repoStyle)
+	useCommitTemplate(ctx, cfg, p.f, p.editFormatter)
+	// Repeats of recent commits are told apart as the messages would be committed
+	t.FormatWith(func(message string) string { return p.f.FormatMessage(message, p.commitMessage.IsMajor) })
 
+	// Typos in the subject are flagged against a commit vocabulary plus the project's dictionary
+	if cfg.Spellcheck.Enabled {
+		p.checker = spell.NewChecker(cfg.Spellcheck.Words)
+	}
+
+	// Leftover debug statements and conflict markers need confirmation before committing
+	if cfg.Safety.Enabled {
+		scanner, err := safety.NewScanner(cfg.Safety.DebugPatterns, cfg.Safety.Ignore)
+		if err != nil {
+			return err
+		}
+		p.leftovers = scanner.Scan(p.changes)
+	}
+	return nil
+}
+
+// resolveTickets returns the subject prefix a branch policy or
+// rules.requireTicket requires and the footers referring to the ticket of the
+// branch and the GitHub issues the branch or diff refer to. Their titles give
+// the AI and the {issue} placeholder context.
+func (p *proposal) resolveTickets(ctx context.Context, policy *config.BranchPolicy) (string, []string, error) {
+	prefix, err := resolveTicketPrefix(p.cfg, policy, p.branchName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var footers []string
+	ticket, err := branchTicket(ctx, p.cfg, p.branchName)
+	if err != nil && !summaryFlag {
+		ui.Warn("⚠ Could not fetch ticket %s: %v", ticket.ID, err)
+	}
+	if ticket != nil {
+		if ticket.Title != "" {
+			p.commitMessage.References = append(p.commitMessage.References, ticket.ID+": "+ticket.Title)
+			p.commitMessage.IssueTitle = ticket.Title
+		}
+		if ref := p.cfg.Tickets.FooterFor(ticket.ID); ref != "" {
+			footers = append(footers, ref)
+		}
+	}
+	p.ticket = ticket
+
+	issues, issueFooters := referencedIssues(ctx, p.cfg, p.branchName, p.changes)
+	for _, issue := range issues {
+		p.commitMessage.References = append(p.commitMessage.References, issue.Context())
+		if p.commitMessage.IssueTitle == "" {
+			p.commitMessage.IssueTitle = issue.Title
+		}
+	}
+	return prefix, append(footers, issueFooters...), nil
+}
+
+// suggest makes the first suggestion: the message picked in smart, the local
+// AI's when it is the engine and answers with a new conventional commit, or
+// the heuristic one
+func (p *proposal) suggest(ctx context.Context) error {
 	// Calculate Heuristic Suggestion (Always available)
-	heuristicMsg, err := templater.GetMessage(commitMessage)
+	heuristicMsg, err := p.templater.GetMessage(p.commitMessage)
 	if err != nil {
 		return err
 	}
-	formattedHeuristic := f.FormatMessage(heuristicMsg, commitMessage.IsMajor)
-
-	var aiMsg string
-	var finalMessage string
-	var usingAI bool
-
-	// AI Engine Logic
-	if cfg.Engine == "ollama" {
-		prompt, err := ai.RenderPrompt(commitMessage, cfg.ProjectType, branchName)
-		if err == nil {
-			client := ai.NewOllamaClient(cfg.Ollama)
-			aiResponse, err := client.Generate(prompt)
-			if err == nil && ai.IsValidCommitMessage(aiResponse) {
-				aiMsg = f.FormatMessage(strings.TrimSpace(aiResponse), commitMessage.IsMajor)
-				usingAI = true
-				finalMessage = aiMsg
-			}
+	if p.body != "" {
+		heuristicMsg += "\n\n" + p.body
+	}
+	p.formattedHeuristic = p.f.FormatMessage(heuristicMsg, p.commitMessage.IsMajor)
+	p.heuristicTemplate = p.templater.TemplateFor(heuristicMsg)
+	p.useHeuristic()
+
+	// AI Engine Logic (version bumps, merges, reverts and picked commits get their fixed messages instead)
+	msg := p.commitMessage
+	if p.cfg.Engine == "ollama" && smartSeed == "" && msg.Version == "" && msg.Merge == nil && msg.Revert == nil && msg.Pick == nil {
+		aiResponse, err := p.generateAI(ctx)
+		switch {
+		case err != nil:
+			slog.Info("using the heuristic engine: AI suggestion failed", "error", err)
+		case !ai.IsValidCommitMessage(aiResponse):
+			slog.Info("using the heuristic engine: AI suggestion is not a conventional commit", "response", aiResponse)
+		case p.hist.IsRecentCommit(aiResponse):
+			slog.Info("using the heuristic engine: AI suggestion repeats a recent commit", "response", aiResponse)
+		default:
+			p.useAI(aiResponse)
 		}
 	}
 
-	if !usingAI {
-		finalMessage = formattedHeuristic
+	// A message picked in smart is reviewed instead of a new suggestion
+	if smartSeed != "" {
+		p.finalMessage = p.f.FormatMessage(smartSeed, msg.IsMajor)
+		p.currentTemplate = p.templater.TemplateFor(smartSeed)
+	}
+
+	// The post-suggest hook may change or veto each suggestion
+	p.finalMessage, err = p.hooks.postSuggest(ctx, p.finalMessage, suggestionSource(p.usingAI), msg)
+	return err
+}
+
+// generateAI asks the local AI for a message for the analysis
+func (p *proposal) generateAI(ctx context.Context) (string, error) {
+	prompt, err := ai.RenderPrompt(ctx, p.commitMessage, p.cfg.ProjectType, p.branchName, p.repoStyle)
+	if err != nil {
+		return "", err
 	}
+	return ai.NewOllamaClient(p.cfg.Ollama).Generate(ctx, prompt)
+}
+
+// useAI makes a response of the local AI the suggestion
+func (p *proposal) useAI(response string) {
+	p.finalMessage = p.f.FormatMessage(strings.TrimSpace(response), p.commitMessage.IsMajor)
+	p.currentTemplate = ""
+	p.usingAI = true
+	p.edited = false
+}
+
+// useHeuristic makes the heuristic message for the staged changes the suggestion
+func (p *proposal) useHeuristic() {
+	p.finalMessage = p.formattedHeuristic
+	p.currentTemplate = p.heuristicTemplate
+	p.usingAI = false
+	p.edited = false
+}
+
+// useEdit makes a message the user changed the suggestion
+func (p *proposal) useEdit(message string) {
+	p.finalMessage = message
+	p.used[message] = true
+	p.edited = true
+}
+
+// recordOutcome records what became of the suggestion, to learn from
+func (p *proposal) recordOutcome(outcome string) {
+	p.hist.RecordOutcome(p.finalMessage, p.currentTemplate, outcome)
+	p.hist.RecordSourceOutcome(suggestionSource(p.usingAI), outcome)
+	recordSmartOutcome(p.hist, outcome)
+}
+
+// printAnalysis shows what was analyzed, as the flags ask, and the changed
+// files above the suggestion
+func (p *proposal) printAnalysis() {
+	msg := p.commitMessage
 
 	// Show analysis context if requested
-	if contextFlag || debugFlag {
-		color.Blue("\n📊 Analysis Context:")
-		fmt.Printf("Action: %s\n", commitMessage.Action)
-		fmt.Printf("Topic:  %s\n", commitMessage.Topic)
-		if commitMessage.Item != "" {
-			fmt.Printf("Item:   %s\n", commitMessage.Item)
+	if contextFlag {
+		ui.Heading("\n📊 Analysis Context:")
+		ui.Printf("Action: %s\n", msg.Action)
+		ui.Printf("Topic:  %s\n", msg.Topic)
+		if msg.Item != "" {
+			ui.Printf("Item:   %s\n", msg.Item)
 		}
-		if commitMessage.Purpose != "" {
-			fmt.Printf("Purpose: %s\n", commitMessage.Purpose)
+		if msg.Purpose != "" {
+			ui.Printf("Purpose: %s\n", msg.Purpose)
 		}
-		if commitMessage.Scope != "" {
-			fmt.Printf("Scope:  %s\n", commitMessage.Scope)
+		if msg.Scope != "" {
+			ui.Printf("Scope:  %s\n", msg.Scope)
 		}
-		fmt.Printf("Files:  +%d -%d\n", commitMessage.TotalAdded, commitMessage.TotalRemoved)
-		if len(commitMessage.FileExtensions) > 0 {
-			fmt.Printf("Types:  %v\n", commitMessage.FileExtensions)
+		if len(msg.FileExtensions) > 0 {
+			ui.Printf("Types:  %v\n", msg.FileExtensions)
 		}
-		fmt.Println()
+		printFileGroups(p.changes, fullFlag)
+		printBreakdown(msg.Breakdown, fullFlag)
+		ui.Println()
+		if p.cfg.Tests.Nudge {
+			printMissingTests(p.missingTests)
+		}
+	}
+
+	if p.risk != nil && !summaryFlag {
+		printRisk(p.risk, contextFlag)
 	}
 
-	if suggestionsFlag && !usingAI {
+	if explainFlag {
+		printExplanation(p.analyzer.Trail(), msg, p.templater, p.currentTemplate, p.usingAI)
+	}
+
+	if suggestionsFlag && !p.usingAI {
 		// Show ranked suggestions only for Heuristic
-		color.Blue("\n💡 Ranked Suggestions:")
-		suggestions, _ := templater.GetSuggestions(commitMessage, maxSuggestions)
-		for i, msg := range suggestions {
-			fmt.Printf("%d. %s\n", i+1, f.FormatMessage(msg, commitMessage.IsMajor))
-		}
-		fmt.Println()
+		ui.Heading("\n💡 Ranked Suggestions:")
+		suggestions, _ := p.templater.GetSuggestions(msg, maxSuggestions)
+		printSuggestionTable(suggestions, func(message string) string {
+			return p.f.FormatMessage(message, msg.IsMajor)
+		})
+		ui.Println()
 	}
 
-	// Interactive Mode logic
-	if !summaryFlag && !autoFlag && !dryRunFlag {
-		usedSuggestions := map[string]bool{finalMessage: true}
-		regenerationCount := 0
-		const maxRegenerations = 10
-
-		for {
-			fmt.Println()
-			if usingAI {
-				color.Cyan("Generated via: Local AI Engine [%s]", cfg.Ollama.Model)
-			} else {
-				color.Blue("Generated via: Heuristic Engine [Matrix Scored]")
+	// The changed files and lines, like git diff --stat, above the suggestion
+	if !summaryFlag && !noStatFlag {
+		printDiffStat(p.changes)
+	}
+	if !summaryFlag && len(msg.Mixed) > 0 {
+		printMixedConcerns(msg.Mixed)
+	}
+}
+
+// interact offers the suggestion for review, editing and regenerating until it
+// is committed or rejected
+func (p *proposal) interact(ctx context.Context) error {
+	p.used = map[string]bool{p.finalMessage: true}
+	hooked := map[string]bool{p.finalMessage: true}
+	// A picked commit's message is suggested with its body, so its subject is
+	// marked used for regenerating to improve on it
+	if p.commitMessage.Pick != nil {
+		p.used[p.commitMessage.Pick.Commit.Subject] = true
+	}
+	p.amendable = canAmend(ctx, p.changes)
+	p.stagedAnalysis = p.commitMessage
+
+	// Changes touching the lines an earlier unpushed commit changed may be a
+	// fixup of it, to fold in later with gitmit autosquash
+	fixup := fixupTarget(ctx, p.changes, p.amendable)
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		// Suggestions made in the loop pass the post-suggest hook too; edits do not
+		if !p.edited && !hooked[p.finalMessage] {
+			message, err := p.hooks.postSuggest(ctx, p.finalMessage, suggestionSource(p.usingAI), p.commitMessage)
+			if err != nil {
+				return err
+			}
+			p.finalMessage = message
+			hooked[message] = true
+		}
+
+		violations := p.f.Check(p.finalMessage)
+		typos := checkSpelling(p.checker, p.finalMessage)
+		p.printReview(violations, typos, fixup)
+
+		input, _ := reader.ReadString('\n')
+		choice := strings.TrimSpace(strings.ToLower(input))
+		ui.Println()
+
+		switch choice {
+		case "y", "":
+			committed, err := p.commitReviewed(ctx, reader, violations)
+			if err != nil || committed {
+				return err
 			}
 
-			color.Green("\n💡 Suggested commit message:")
-			fmt.Printf("%s\n\n", finalMessage)
+		case "n":
+			ui.Warn("❌ Commit cancelled.")
+			p.recordOutcome(history.OutcomeRejected)
+			return p.hist.SaveHistory(ctx)
 
-			color.Blue("Actions:")
-			fmt.Println("  y - Accept and commit")
-			fmt.Println("  n - Reject and exit")
-			fmt.Println("  e - Edit message manually")
+		case "e":
+			ui.Heading("📝 Edit the commit message:")
+			ui.Printf("Current: %s\n", p.finalMessage)
+			ui.Print("New message: ")
 
-			if usingAI {
-				fmt.Println("  r - Regenerate an alternative AI suggestion")
-				fmt.Println("  h - Fallback to classic Heuristic suggestion")
-			} else {
-				fmt.Println("  r - Regenerate different suggestion (Heuristic)")
-				fmt.Println("  a - Upgrade suggestion with Local AI (Ollama)")
+			editedMessage, _ := reader.ReadString('\n')
+			editedMessage = strings.TrimSpace(editedMessage)
+			if editedMessage == "" {
+				ui.Warn("⚠ No changes made. Keeping current message.\n")
+				continue
+			}
+			p.useEdit(p.editFormatter.FormatMessage(editedMessage, p.commitMessage.IsMajor))
+			ui.Success("\n✓ Updated commit message:")
+
+		case "s":
+			scope, ok := promptScope(reader, p.repoStyle, style.SubjectScope(p.finalMessage))
+			if !ok {
+				continue
 			}
-			fmt.Printf("\nChoice [y/n/e/r/%s]: ", map[bool]string{true: "h", false: "a"}[usingAI])
-
-			reader := bufio.NewReader(os.Stdin)
-			input, _ := reader.ReadString('\n')
-			choice := strings.TrimSpace(strings.ToLower(input))
-			fmt.Println()
-
-			switch choice {
-			case "y", "":
-				// Commit the message
-				commitCmd := exec.Command("git", "commit", "-m", finalMessage)
-				commitCmd.Stdout = os.Stdout
-				commitCmd.Stderr = os.Stderr
-				err := commitCmd.Run()
-				if err != nil {
-					return fmt.Errorf("error committing changes: %w", err)
-				}
-				color.Green("✅ Changes committed successfully.")
-				history.AddEntry(finalMessage, "") // Save to history
-				if err := history.SaveHistory(); err != nil {
-					return err
-				}
-				return nil
-
-			case "n":
-				color.Yellow("❌ Commit cancelled.")
-				return nil
-
-			case "e":
-				color.Blue("📝 Edit the commit message:")
-				fmt.Printf("Current: %s\n", finalMessage)
-				fmt.Print("New message: ")
-
-				editedMessage, _ := reader.ReadString('\n')
-				editedMessage = strings.TrimSpace(editedMessage)
-
-				if editedMessage != "" {
-					finalMessage = f.FormatMessage(editedMessage, commitMessage.IsMajor)
-					usedSuggestions[finalMessage] = true
-					color.Green("\n✓ Updated commit message:")
-				} else {
-					color.Yellow("⚠ No changes made. Keeping current message.\n")
-				}
+			updated := style.SetScope(p.finalMessage, scope)
+			if updated == p.finalMessage {
+				ui.Warn("⚠ Message has no conventional type prefix to scope.\n")
 				continue
+			}
+			p.useEdit(p.editFormatter.FormatMessage(updated, false))
+			ui.Success("\n✓ Updated commit message:")
 
-			case "r":
-				if regenerationCount >= maxRegenerations {
-					color.Yellow("⚠ Maximum regeneration attempts reached.\n")
-					continue
-				}
-
-				if usingAI {
-					prompt, err := ai.RenderPrompt(commitMessage, cfg.ProjectType, branchName)
-					if err == nil {
-						client := ai.NewOllamaClient(cfg.Ollama)
-						aiResponse, err := client.Generate(prompt)
-						if err == nil && ai.IsValidCommitMessage(aiResponse) {
-							finalMessage = f.FormatMessage(strings.TrimSpace(aiResponse), commitMessage.IsMajor)
-							regenerationCount++
-						}
-					}
-				} else {
-					newSuggestion, err := templater.GetAlternativeSuggestion(commitMessage, usedSuggestions)
-					if err == nil && newSuggestion != "" {
-						finalMessage = f.FormatMessage(newSuggestion, commitMessage.IsMajor)
-						regenerationCount++
-					}
-				}
-				usedSuggestions[finalMessage] = true
+		case "o":
+			options := scopeOptions(p.cfg, p.repoStyle, p.commitMessage, style.SubjectScope(p.finalMessage))
+			render := func(scope string) string {
+				return wizardPreview(p.editFormatter.FormatMessage(style.SetScope(p.finalMessage, scope), false), p.cfg.MaxSubjectLength)
+			}
+			scope, ok := pickScope(reader, options, allowCustomScope(p.repoStyle), p.finalMessage, render)
+			if !ok {
 				continue
+			}
+			p.useEdit(p.editFormatter.FormatMessage(style.SetScope(p.finalMessage, scope), false))
+			ui.Success("\n✓ Updated commit message:")
+
+		case "d":
+			if err := showStagedDiff(ctx, reader, p.amending); err != nil {
+				ui.Error("❌ %v", err)
+			}
 
-			case "a":
-				if usingAI {
-					continue
-				}
-				// Try to connect to Ollama
-				prompt, err := ai.RenderPrompt(commitMessage, cfg.ProjectType, branchName)
-				if err == nil {
-					client := ai.NewOllamaClient(cfg.Ollama)
-					aiResponse, err := client.Generate(prompt)
-					if err == nil && ai.IsValidCommitMessage(aiResponse) {
-						aiMsg = f.FormatMessage(strings.TrimSpace(aiResponse), commitMessage.IsMajor)
-						finalMessage = aiMsg
-						usingAI = true
-					} else {
-						warning, _ := assets.RenderOllamaWarning(cfg.Ollama.URL, cfg.Ollama.Model)
-						color.Red("\n%s", warning)
-					}
-				}
+		case "f":
+			if !hasFixable(violations) {
+				ui.Warn("⚠ Nothing to fix automatically.\n")
 				continue
+			}
+			p.useEdit(p.f.Fix(p.finalMessage))
+			ui.Success("✓ Fixed rule violations:")
 
-			case "h":
-				if !usingAI {
-					continue
-				}
-				usingAI = false
-				finalMessage = formattedHeuristic
+		case "c":
+			if len(typos) == 0 {
+				ui.Warn("⚠ No typos found.\n")
 				continue
+			}
+			corrected := correctSpelling(ctx, reader, p.checker, p.finalMessage, typos)
+			if corrected == p.finalMessage {
+				continue
+			}
+			p.useEdit(corrected)
+			ui.Success("\n✓ Corrected spelling:")
+
+		case "r":
+			p.regenerate(ctx)
+
+		case "a":
+			p.upgradeToAI(ctx)
+
+		case "m":
+			p.toggleAmend(ctx)
 
-			default:
-				color.Yellow("⚠ Invalid choice. Please select a valid option.\n")
+		case "x":
+			if fixup == nil || p.amending {
+				ui.Warn("⚠ Invalid choice. Please select a valid option.\n")
 				continue
 			}
+			return commitFixup(ctx, fixup)
+
+		case "p":
+			committed, err := p.split(ctx, reader)
+			if err != nil || committed {
+				return err
+			}
+
+		case "h":
+			if p.usingAI {
+				p.useHeuristic()
+			}
+
+		default:
+			ui.Warn("⚠ Invalid choice. Please select a valid option.\n")
+		}
+	}
+}
+
+// printReview shows the suggestion under review, what is wrong with it and the
+// actions it allows
+func (p *proposal) printReview(violations []formatter.Violation, typos []spell.Typo, fixup *analyzer.Fixup) {
+	ui.Println()
+	if p.usingAI {
+		ui.Accent("Generated via: Local AI Engine [%s]", p.cfg.Ollama.Model)
+	} else {
+		ui.Heading("Generated via: Heuristic Engine [Matrix Scored]")
+	}
+
+	ui.Success("\n💡 Suggested commit message:")
+	ui.Printf("%s\n\n", p.finalMessage)
+
+	printViolations(violations)
+	printTypos(typos)
+	printLeftovers(p.leftovers)
+
+	ui.Heading("Actions:")
+	ui.Println("  y - Accept and commit")
+	ui.Println("  n - Reject and exit")
+	ui.Println("  e - Edit message manually")
+	ui.Println("  s - Change scope")
+	ui.Println("  o - Pick scope from suggestions")
+	ui.Println("  d - View the staged diff")
+	if hasFixable(violations) {
+		ui.Println("  f - Fix rule violations")
+	}
+	if len(typos) > 0 {
+		ui.Println("  c - Correct spelling")
+	}
+
+	if p.usingAI {
+		ui.Println("  r - Regenerate an alternative AI suggestion")
+		ui.Println("  h - Fallback to classic Heuristic suggestion")
+	} else {
+		ui.Println("  r - Regenerate different suggestion (Heuristic)")
+		ui.Println("  a - Upgrade suggestion with Local AI (Ollama)")
+	}
+	if p.amending {
+		ui.Println("  m - Make a new commit instead of amending")
+	} else if p.amendable {
+		ui.Println("  m - Amend into the previous commit (not pushed yet)")
+	}
+	if fixup != nil && !p.amending {
+		ui.Printf("  x - Fix up %s %q (git commit --fixup)\n", fixup.Commit.ShortHash(), fixup.Commit.Subject)
+	}
+	if len(p.commitMessage.Mixed) > 0 && !p.amending {
+		ui.Printf("  p - Plan a split into %d commits by concern\n", len(p.commitMessage.Mixed))
+	}
+	choices := "y/n/e/r/" + map[bool]string{true: "h", false: "a"}[p.usingAI]
+	if p.amending || p.amendable {
+		choices += "/m"
+	}
+	ui.Printf("\nChoice [%s]: ", choices)
+}
+
+// commitReviewed commits the suggestion, or amends the previous commit with
+// it, once the rules it breaks and the leftovers in the changes are confirmed.
+// It reports whether it committed.
+func (p *proposal) commitReviewed(ctx context.Context, reader *bufio.Reader, violations []formatter.Violation) (bool, error) {
+	if missingTicket(p.cfg, violations) {
+		ui.Warn("✗ Not committing: the subject must start with a ticket matching %s (rules.requireTicket).\n", p.cfg.Rules.TicketPattern)
+		return false, nil
+	}
+	if len(violations) > 0 {
+		ui.Printf("Message breaks %d commit rule(s). Commit anyway? [y/N]: ", len(violations))
+		answer, _ := reader.ReadString('\n')
+		if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+			ui.Println()
+			return false, nil
+		}
+	}
+	if len(p.leftovers) > 0 {
+		ui.Printf("Staged changes contain %d leftover(s). Type 'yes' to commit anyway: ", len(p.leftovers))
+		answer, _ := reader.ReadString('\n')
+		if strings.ToLower(strings.TrimSpace(answer)) != "yes" {
+			ui.Println()
+			return false, nil
+		}
+	}
+
+	var commitArgs []string
+	if p.amending {
+		commitArgs = append(commitArgs, "--amend")
+	}
+	if err := addChangelogEntry(ctx, p.cfg, reader, p.finalMessage); err != nil {
+		return false, err
+	}
+	committed, err := p.hooks.commit(ctx, p.finalMessage, commitArgs...)
+	if err != nil {
+		return false, err
+	}
+	if p.amending {
+		ui.Success("✅ Previous commit amended successfully.")
+	} else {
+		ui.Success("✅ Changes committed successfully.")
+	}
+	outcome := history.OutcomeAccepted
+	if p.edited {
+		outcome = history.OutcomeEdited
+	}
+	p.recordOutcome(outcome)
+	if err := p.hist.SaveHistory(ctx); err != nil {
+		return true, err
+	}
+	transitionTicket(ctx, p.cfg, p.ticket)
+	return true, tagAfterCommit(ctx, reader, committed)
+}
+
+// regenerate replaces the suggestion with another one from the local AI, or
+// with the next template message not suggested yet
+func (p *proposal) regenerate(ctx context.Context) {
+	if p.regenerations >= maxRegenerations {
+		ui.Warn("⚠ Maximum regeneration attempts reached.\n")
+		return
+	}
+
+	p.recordOutcome(history.OutcomeRegenerated)
+	p.edited = false
+	if p.usingAI {
+		aiResponse, err := p.generateAI(ctx)
+		if err == nil && ai.IsValidCommitMessage(aiResponse) && !p.hist.IsRecentCommit(aiResponse) {
+			p.finalMessage = p.f.FormatMessage(strings.TrimSpace(aiResponse), p.commitMessage.IsMajor)
+			p.regenerations++
+		}
+	} else {
+		newSuggestion, err := p.templater.GetAlternativeSuggestion(p.commitMessage, p.used)
+		if err == nil && newSuggestion != "" {
+			p.currentTemplate = p.templater.TemplateFor(newSuggestion)
+			// Improvements on a picked commit's message keep its body and conflict notes
+			if p.commitMessage.Pick != nil && p.body != "" {
+				p.used[newSuggestion] = true
+				newSuggestion += "\n\n" + p.body
+			}
+			p.finalMessage = p.f.FormatMessage(newSuggestion, p.commitMessage.IsMajor)
+			p.regenerations++
 		}
 	}
+	p.used[p.finalMessage] = true
+}
 
-	// Handle non-interactive cases (summary, auto, dry-run)
+// upgradeToAI replaces the heuristic suggestion with the local AI's, or shows
+// how to set up Ollama when it gives none
+func (p *proposal) upgradeToAI(ctx context.Context) {
+	if p.usingAI {
+		return
+	}
+	aiResponse, err := p.generateAI(ctx)
+	if err != nil || !ai.IsValidCommitMessage(aiResponse) {
+		warning, _ := assets.RenderOllamaWarning(p.cfg.Ollama.URL, p.cfg.Ollama.Model)
+		ui.Error("\n%s", warning)
+		return
+	}
+	recordSmartOutcome(p.hist, history.OutcomeRegenerated)
+	p.useAI(aiResponse)
+}
+
+// toggleAmend switches between committing the staged changes on their own and
+// amending them into the previous commit, with a message for both
+func (p *proposal) toggleAmend(ctx context.Context) {
+	if p.amending {
+		p.amending = false
+		p.commitMessage = p.stagedAnalysis
+		p.useHeuristic()
+		ui.Success("✓ Back to a new commit:")
+		return
+	}
+	if !p.amendable {
+		ui.Warn("⚠ Invalid choice. Please select a valid option.\n")
+		return
+	}
+	combined, err := amendAnalysis(ctx, p.cfg, p.branchName)
+	if err != nil || combined == nil {
+		ui.Warn("⚠ Could not analyze the previous commit with the staged changes.\n")
+		return
+	}
+	combinedMsg, err := p.templater.GetMessage(combined)
+	if err != nil {
+		ui.Warn("⚠ Could not suggest a message for the amended commit: %v\n", err)
+		return
+	}
+	p.amending = true
+	p.commitMessage = combined
+	p.finalMessage = p.f.FormatMessage(combinedMsg, combined.IsMajor)
+	p.currentTemplate = p.templater.TemplateFor(combinedMsg)
+	p.usingAI = false
+	p.edited = false
+	p.used[p.finalMessage] = true
+	ui.Success("✓ Message for the previous commit amended with the staged changes:")
+}
+
+// split plans commits by concern for mixed changes and makes them once
+// confirmed. It reports whether it committed.
+func (p *proposal) split(ctx context.Context, reader *bufio.Reader) (bool, error) {
+	if len(p.commitMessage.Mixed) == 0 || p.amending {
+		ui.Warn("⚠ Invalid choice. Please select a valid option.\n")
+		return false, nil
+	}
+	plan, err := planSplit(ctx, p.cfg, p.hist, p.f, p.changes, p.commitMessage.Mixed)
+	if err != nil {
+		ui.Warn("⚠ Could not plan the split: %v\n", err)
+		return false, nil
+	}
+	printSplitPlan(plan)
+	ui.Printf("Make these %d commits? [y/N]: ", len(plan))
+	answer, _ := reader.ReadString('\n')
+	ui.Println()
+	if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+		return false, nil
+	}
+	if err := commitSplit(ctx, p.hooks, plan); err != nil {
+		return false, err
+	}
+	ui.Success("✅ Changes committed in %d commits.", len(plan))
+	return true, nil
+}
+
+// commitFixup commits the staged changes as a fixup of an earlier commit
+func commitFixup(ctx context.Context, fixup *analyzer.Fixup) error {
+	commitCmd := gitcmd.LongCommand(ctx, "commit", "--fixup", fixup.Commit.Hash)
+	commitCmd.Stdout = os.Stdout
+	commitCmd.Stderr = os.Stderr
+	if err := commitCmd.Run(); err != nil {
+		return fmt.Errorf("error committing fixup: %w", err)
+	}
+	ui.Success("✅ Fixup of %s committed. Run gitmit autosquash to fold it in.", fixup.Commit.ShortHash())
+	return nil
+}
+
+// finish prints the suggestion for --summary and --dry-run, and commits it for
+// --auto once it breaks no rules
+func (p *proposal) finish(ctx context.Context) error {
 	if summaryFlag {
-		fmt.Println(finalMessage)
+		fmt.Println(p.finalMessage)
 		return nil
 	}
 
-	color.Green("\n💡 Suggested commit message:")
-	fmt.Printf("%s\n\n", finalMessage)
+	ui.Success("\n💡 Suggested commit message:")
+	ui.Printf("%s\n\n", p.finalMessage)
 
+	violations := p.f.Check(p.finalMessage)
+	printViolations(violations)
+	printTypos(checkSpelling(p.checker, p.finalMessage))
+	printLeftovers(p.leftovers)
 
+	if dryRunFlag || !autoFlag {
+		if !readingPatch() {
+			ui.Println("\n(Dry run: no changes committed)")
+		}
+		return nil
+	}
 
-	// Handle auto-commit and dry-run cases
-	if autoFlag && !dryRunFlag {
-		commitCmd := exec.Command("git", "commit", "-m", finalMessage)
-		commitCmd.Stdout = os.Stdout
-		commitCmd.Stderr = os.Stderr
-		err := commitCmd.Run()
+	if len(violations) > 0 {
+		return fmt.Errorf("not committing: message breaks %d commit rule(s)", len(violations))
+	}
+	if len(p.leftovers) > 0 && !allowLeftovers {
+		return fmt.Errorf("not committing: staged changes contain %d leftover(s); remove them or pass --allow-leftovers", len(p.leftovers))
+	}
+	if p.risk != nil && p.risk.IsHigh() && p.cfg.Risk.ConfirmAuto {
+		ui.Printf("Staged changes are %s risk. Type 'yes' to commit anyway: ", p.risk)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.ToLower(strings.TrimSpace(answer)) != "yes" {
+			return fmt.Errorf("not committing: staged changes are %s risk", p.risk)
+		}
+	}
+	if err := addChangelogEntry(ctx, p.cfg, nil, p.finalMessage); err != nil {
+		return err
+	}
+	committed, err := p.hooks.commit(ctx, p.finalMessage)
+	if err != nil {
+		return err
+	}
+	ui.Success("✅ Changes committed successfully.")
+	p.recordOutcome(history.OutcomeAccepted)
+	if err := p.hist.SaveHistory(ctx); err != nil {
+		return err
+	}
+	transitionTicket(ctx, p.cfg, p.ticket)
+	return tagAfterCommit(ctx, nil, committed)
+}
+
+// printViolations lists the commit rules a message breaks
+func printViolations(violations []formatter.Violation) {
+	if len(violations) == 0 {
+		return
+	}
+	ui.Warn("⚠ Commit rule violations:")
+	for _, v := range violations {
+		ui.Printf("  - %s\n", v)
+	}
+	ui.Println()
+}
+
+// printLeftovers lists the debug statements and conflict markers in the staged changes
+func printLeftovers(findings []safety.Finding) {
+	if len(findings) == 0 {
+		return
+	}
+	ui.Warn("⚠ Leftovers in staged changes:")
+	for _, f := range findings {
+		ui.Printf("  - %s\n", f)
+	}
+	ui.Println()
+}
+
+// printMissingTests lists the source files staged without any of their tests
+func printMissingTests(missing []analyzer.MissingTest) {
+	if len(missing) == 0 {
+		return
+	}
+	ui.Warn("⚠ No tests updated for %d source file(s):", len(missing))
+	for _, m := range missing {
+		ui.Printf("  - %s %s\n", m.File, ui.MutedString("(expected %s)", strings.Join(m.Expected, " or ")))
+	}
+	ui.Println()
+}
+
+// printRisk shows the risk score of the staged changes, with the factors behind
+// it when detailed
+func printRisk(risk *analyzer.Risk, detailed bool) {
+	var names []string
+	for _, factor := range risk.Factors {
+		names = append(names, factor.Name)
+	}
+	line := fmt.Sprintf("Risk: %s", risk)
+	if len(names) > 0 {
+		line += " — " + strings.Join(names, ", ")
+	}
+	switch risk.Level {
+	case analyzer.RiskHigh:
+		ui.Error("%s", line)
+	case analyzer.RiskMedium:
+		ui.Warn("%s", line)
+	default:
+		ui.Success("%s", line)
+	}
+	if detailed {
+		for _, factor := range risk.Factors {
+			ui.Printf("  - %s (+%d): %s\n", factor.Name, factor.Points, factor.Detail)
+		}
+	}
+}
+
+// suggestionSource names where a suggestion came from, for the history
+func suggestionSource(usingAI bool) string {
+	if usingAI {
+		return history.SourceAI
+	}
+	return history.SourceTemplate
+}
+
+// printExplanation shows the decision trail behind the suggested message: the
+// steps that decided its type and the score breakdown of its template
+func printExplanation(trail []analyzer.Step, msg *analyzer.CommitMessage, t *templater.Templater, template string, usingAI bool) {
+	ui.Heading("\n🔎 Why this message:")
+	for _, step := range trail {
+		ui.Printf("  %-14s %s\n", step.Stage, step.Detail)
+	}
+	ui.Printf("  %-14s %s\n", "action", msg.Action)
+
+	switch {
+	case usingAI:
+		ui.Println("\nWritten by the AI model, not from a template.")
+	case template == "":
+		ui.Println("\nNot produced from a template.")
+	default:
+		explanation := t.ExplainTemplate(msg, template)
+		ui.Printf("\nTemplate %s %s\n", template, ui.MutedString("(%s/%s)", explanation.Group, explanation.Topic))
+		for _, term := range explanation.Terms {
+			ui.Printf("  %+5.1f %s\n", term.Points, term.Reason)
+		}
+		ui.Printf("  %5.1f total, plus up to 0.5 of random variety\n", explanation.Score)
+	}
+	ui.Println()
+}
+
+// printSuggestionTable prints ranked suggestions as a table of their type,
+// scope, template score and strongest reason, each followed by its message
+func printSuggestionTable(suggestions []templater.Suggestion, format func(string) string) {
+	headers := []string{"#", "type", "scope", "score", "why"}
+	rows := make([][]string, 0, len(suggestions))
+	messages := make([]string, 0, len(suggestions))
+	for i, suggestion := range suggestions {
+		message := format(suggestion.Message)
+		commitType, scope, _, _ := style.ParseSubject(style.Subject(message))
+		if commitType == "" {
+			commitType, scope = suggestion.Type, suggestion.Scope
+		}
+		if scope == "" {
+			scope = "-"
+		}
+		rows = append(rows, []string{strconv.Itoa(i + 1), commitType, scope, fmt.Sprintf("%.1f", suggestion.Score), suggestion.TopReason})
+		messages = append(messages, message)
+	}
+
+	widths := make([]int, len(headers))
+	for _, row := range append([][]string{headers}, rows...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	line := func(row []string) string {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			if i == 0 || i == 3 {
+				cells[i] = pad + cell // Numbers align right
+			} else {
+				cells[i] = cell + pad
+			}
+		}
+		return strings.TrimRight(strings.Join(cells, "  "), " ")
+	}
+
+	ui.Printf("%s\n", ui.MutedString("%s", line(headers)))
+	indent := strings.Repeat(" ", widths[0]+2)
+	for i, row := range rows {
+		ui.Printf("%s\n", line(row))
+		ui.Printf("%s%s\n", indent, strings.ReplaceAll(messages[i], "\n", "\n"+indent))
+	}
+}
+
+// hasFixable reports whether any violation can be fixed automatically
+func hasFixable(violations []formatter.Violation) bool {
+	for _, v := range violations {
+		if v.Fixable {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSpelling returns the likely typos in the description of a message's subject,
+// or nil when the spellcheck is disabled
+func checkSpelling(checker *spell.Checker, message string) []spell.Typo {
+	if checker == nil {
+		return nil
+	}
+	_, description := style.SplitSubject(strings.SplitN(message, "\n", 2)[0])
+	return checker.Check(description)
+}
+
+// printTypos lists the likely typos in a message with their suggested corrections
+func printTypos(typos []spell.Typo) {
+	if len(typos) == 0 {
+		return
+	}
+	ui.Warn("⚠ Possible typos:")
+	for _, typo := range typos {
+		ui.Printf("  - %s -> %s\n", typo.Word, typo.Suggestion)
+	}
+	ui.Println()
+}
+
+// correctSpelling offers each suggested correction in turn. Words the user marks
+// as correct are added to the dictionary of the local config.
+func correctSpelling(ctx context.Context, reader *bufio.Reader, checker *spell.Checker, message string, typos []spell.Typo) string {
+	for _, typo := range typos {
+		ui.Printf("Replace %q with %q? [Y/n/a=add to dictionary]: ", typo.Word, typo.Suggestion)
+		answer, _ := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "":
+			message = spell.Correct(message, typo)
+		case "a":
+			configPath := config.LocalConfigFile(ctx)
+			if err := config.AddSpellingWord(configPath, typo.Word); err != nil {
+				ui.Error("❌ %v", err)
+				continue
+			}
+			checker.Add(typo.Word)
+			ui.Success("✓ Added %q to the spellcheck dictionary in %s", typo.Word, configPath)
+		}
+	}
+	return message
+}
+
+// promptScope asks for a new scope, offering the known scopes by number and
+// autocompleting typed prefixes. An empty answer removes the scope. It returns
+// false when the answer is rejected.
+func promptScope(reader *bufio.Reader, repoStyle *style.Style, current string) (string, bool) {
+	const maxListedScopes = 15
+
+	scopes := repoStyle.Scopes
+	if len(scopes) > maxListedScopes {
+		scopes = scopes[:maxListedScopes]
+	}
+	if len(scopes) > 0 {
+		ui.Heading("🏷  Known scopes:")
+		for i, scope := range scopes {
+			ui.Printf("  %2d. %s\n", i+1, scope)
+		}
+	}
+	if current != "" {
+		ui.Printf("Current: %s\n", current)
+	}
+	ui.Print("Scope (number, name or prefix; empty to remove): ")
+
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", true
+	}
+	if n, err := strconv.Atoi(input); err == nil && n >= 1 && n <= len(scopes) {
+		return scopes[n-1], true
+	}
+	if scope, ok := style.CompleteScope(input, repoStyle.Scopes); ok {
+		return scope, true
+	}
+	if repoStyle.StrictScopes && len(repoStyle.Scopes) > 0 {
+		ui.Warn("⚠ %q is not a known scope (strictScopes is enabled).\n", input)
+		return "", false
+	}
+	return input, true
+}
+
+// learnRepoStyle learns the conventions of the repository's log so suggestions
+// blend in. Scopes already used in the log are offered in interactive mode and
+// to the LLM, unless the config pins an explicit list.
+func learnRepoStyle(cfg *config.Config, hist *history.CommitHistory) *style.Style {
+	repoStyle := style.Analyze(hist.RecentSubjects())
+	if !cfg.LearnStyle {
+		repoStyle = &style.Style{Scopes: repoStyle.Scopes}
+	}
+	if len(cfg.Scopes) > 0 {
+		repoStyle.Scopes = cfg.Scopes
+	}
+	repoStyle.StrictScopes = cfg.StrictScopes
+	repoStyle.Types = cfg.Types
+	return repoStyle
+}
+
+// newFormatters creates the formatter for suggestions, which applies the learned
+// style, and the one for manual edits, which keeps the user's wording and only
+// wraps it. Both add the ticket prefix and footer and check the configured rules.
+func newFormatters(ctx context.Context, cfg *config.Config, ticketPrefix, footer string, repoStyle *style.Style) (*formatter.Formatter, *formatter.Formatter) {
+	f := formatter.NewFormatter(cfg.MaxSubjectLength, cfg.MaxBodyLength)
+	f.TicketPrefix = ticketPrefix
+	f.Footer = footer
+	f.Rules = &cfg.Rules
+	f.Imperative = cfg.ImperativeMood
+	f.ShortenSubject = cfg.SubjectOverflow == "shorten"
+	f.ScopeAliases = cfg.ScopeAliases
+	f.Style = repoStyle
+	f.Checks = pluginChecks(ctx, cfg)
+
+	editFormatter := formatter.NewFormatter(cfg.MaxSubjectLength, cfg.MaxBodyLength)
+	editFormatter.TicketPrefix = ticketPrefix
+	editFormatter.Footer = footer
+	editFormatter.Rules = &cfg.Rules
+	editFormatter.ScopeAliases = cfg.ScopeAliases
+	return f, editFormatter
+}
+
+// useCommitTemplate completes the messages of formatters with the sections of
+// the repository's commit.template, unless the config turns that off
+func useCommitTemplate(ctx context.Context, cfg *config.Config, formatters ...*formatter.Formatter) {
+	if !cfg.CommitTemplate {
+		return
+	}
+	content, err := parser.CommitTemplate(ctx)
+	if err != nil {
+		slog.Warn("commit.template left out", "error", err)
+		return
+	}
+	template := formatter.ParseCommitTemplate(content, parser.CommentChar(ctx))
+	for _, f := range formatters {
+		f.Template = template
+	}
+}
+
+// selectTemplateFile picks the template pack: the flag wins over the branch
+// policy, which wins over the config, which wins over templates.json
+func selectTemplateFile(cfg *config.Config, policy *config.BranchPolicy, flag string) string {
+	templateFile := "templates.json"
+	if cfg.TemplateFile != "" {
+		templateFile = cfg.TemplateFile
+	}
+	if policy != nil && policy.Templates != "" {
+		templateFile = policy.Templates
+	}
+	if flag != "" {
+		templateFile = flag
+	}
+	return templateFile
+}
+
+// resolveTicketPrefix returns the subject prefix required by a branch policy or
+// by rules.requireTicket. The ticket is taken from the branch name; if one is
+// required and the branch has none, it is asked for interactively.
+func resolveTicketPrefix(cfg *config.Config, policy *config.BranchPolicy, branchName string) (string, error) {
+	if policy != nil {
+		ticket, err := policy.Ticket(branchName)
 		if err != nil {
-			return fmt.Errorf("error committing changes: %w", err)
+			return "", err
 		}
-		color.Green("✅ Changes committed successfully.")
-		history.AddEntry(finalMessage, "") // Save to history
-		if err := history.SaveHistory(); err != nil {
-			return err
+		if ticket == "" && policy.RequireTicket {
+			if autoFlag || summaryFlag || dryRunFlag {
+				return "", fmt.Errorf("branch policy %q requires a ticket, but none was found in branch %q", policy.Pattern, branchName)
+			}
+			ui.Warn("🎫 Branch policy %q requires a ticket reference.", policy.Pattern)
+			ui.Print("Ticket: ")
+			input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if ticket = strings.TrimSpace(input); ticket == "" {
+				return "", fmt.Errorf("a ticket is required on branch %q", branchName)
+			}
+		}
+		if ticket != "" {
+			return policy.TicketPrefix(ticket), nil
 		}
-	} else if dryRunFlag {
-		fmt.Println("\n(Dry run: no changes committed)")
 	}
 
-	return nil
+	rules := cfg.Rules
+	if !rules.RequireTicket || rules.TicketPattern == "" {
+		return "", nil
+	}
+	// The ticket is prefixed in the format of the branch policy, if any
+	format := ""
+	if policy != nil {
+		format = policy.TicketFormat
+	}
+	ticket := rules.Ticket(branchName)
+	if ticket == "" {
+		if autoFlag || summaryFlag || dryRunFlag {
+			return "", fmt.Errorf("rules.requireTicket needs a ticket matching %s, but none was found in branch %q", rules.TicketPattern, branchName)
+		}
+		ui.Warn("🎫 Commits must start with a ticket matching %s, and branch %q has none.", rules.TicketPattern, branchName)
+		reader := bufio.NewReader(os.Stdin)
+		for !rules.IsTicket(ticket) {
+			if ticket != "" {
+				ui.Warn("⚠ %q does not match %s.", ticket, rules.TicketPattern)
+			}
+			ui.Print("Ticket: ")
+			input, err := reader.ReadString('\n')
+			if ticket = strings.TrimSpace(input); ticket == "" || (err != nil && !rules.IsTicket(ticket)) {
+				return "", fmt.Errorf("not committing: a ticket matching %s is required", rules.TicketPattern)
+			}
+		}
+	}
+	return config.TicketPrefix(format, ticket), nil
+}
+
+// branchTicketPrefix returns the subject prefix of the ticket found in the
+// branch name, required by a branch policy or rules.requireTicket, without
+// asking for one
+func branchTicketPrefix(cfg *config.Config, policy *config.BranchPolicy, branchName string) string {
+	if policy != nil {
+		if ticket, err := policy.Ticket(branchName); err == nil && ticket != "" {
+			return policy.TicketPrefix(ticket)
+		}
+	}
+	if ticket := cfg.Rules.Ticket(branchName); cfg.Rules.RequireTicket && ticket != "" {
+		if policy != nil {
+			return policy.TicketPrefix(ticket)
+		}
+		return config.TicketPrefix("", ticket)
+	}
+	return ""
+}
+
+// missingTicket reports whether rules.requireTicket refuses a message for
+// lacking the ticket, which no confirmation overrides
+func missingTicket(cfg *config.Config, violations []formatter.Violation) bool {
+	if !cfg.Rules.RequireTicket {
+		return false
+	}
+	for _, v := range violations {
+		if v.Rule == "ticketPattern" {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+// resolveRange resolves the commits a command works on to a base and head
+// revision: a base..head argument, the last N commits, or the commits of the
+// current branch since it left baseFlag (by default the repository's base branch)
+func resolveRange(ctx context.Context, args []string, baseFlag string, last int) (string, string, error) {
+	switch {
+	case len(args) == 1:
+		base, head, ok := strings.Cut(args[0], "..")
+		if !ok || base == "" {
+			return "", "", fmt.Errorf("invalid range %q (expected base..head)", args[0])
+		}
+		if head == "" {
+			head = "HEAD"
+		}
+		return base, head, nil
+	case last > 0:
+		base, err := parser.ResolveRevision(ctx, fmt.Sprintf("HEAD~%d", last))
+		if err != nil {
+			return "", "", fmt.Errorf("there are fewer than %d commits before HEAD", last)
+		}
+		return base, "HEAD", nil
+	}
+
+	base := baseFlag
+	if base == "" {
+		var err error
+		if base, err = parser.DefaultBase(ctx); err != nil {
+			return "", "", err
+		}
+	}
+	mergeBase, err := parser.MergeBase(ctx, base, "HEAD")
+	if err != nil {
+		return "", "", err
+	}
+	return mergeBase, "HEAD", nil
+}
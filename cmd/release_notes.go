@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/changelog"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+var (
+	releaseNotesTitleFlag  string
+	releaseNotesAllFlag    bool
+	releaseNotesOutputFlag string
+	releaseNotesRemoteFlag string
+
+	releaseNotesCmd = &cobra.Command{
+		Use:   "release-notes <from>..<to>",
+		Short: "Generate markdown release notes for a range of commits",
+		Long: `Read the conventional commits between two revisions, usually tags, and print
+markdown release notes with a section for breaking changes followed by
+Features, Bug Fixes, Performance, Security and Reverts.
+
+Commit hashes link to the repository on GitHub or GitLab, found from the
+origin remote, and issue references in footers such as "Closes #12" or
+"Refs: ABC-123" are listed with their commit. With --all every commit type is
+listed, including chores, tests and CI changes.`,
+		Example: `  gitmit release-notes v1.1.0..v1.2.0
+  gitmit release-notes v1.2.0.. --title "v1.3.0" --output NOTES.md`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runReleaseNotes,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(releaseNotesCmd)
+	releaseNotesCmd.Flags().StringVar(&releaseNotesTitleFlag, "title", "", "Heading of the notes (default: the <to> revision, or Unreleased for HEAD)")
+	releaseNotesCmd.Flags().BoolVar(&releaseNotesAllFlag, "all", false, "List every commit type")
+	releaseNotesCmd.Flags().StringVarP(&releaseNotesOutputFlag, "output", "o", "", "Write the notes to a file instead of standard output")
+	releaseNotesCmd.Flags().StringVar(&releaseNotesRemoteFlag, "remote", "origin", "Remote whose web URL is used for links")
+}
+
+func runReleaseNotes(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	from, to, err := resolveRange(ctx, args, "", 0)
+	if err != nil {
+		return err
+	}
+	commits, err := parser.ParseCommits(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	title := releaseNotesTitleFlag
+	if title == "" {
+		title = to
+		if to == "HEAD" {
+			title = "Unreleased"
+		}
+	}
+
+	// Links are a nicety, so a missing or local remote only drops them
+	repoURL, _ := parser.RemoteWebURL(ctx, releaseNotesRemoteFlag)
+	notes := changelog.ReleaseNotes(changelog.Parse(commits), changelog.NotesOptions{
+		Title:   title,
+		RepoURL: repoURL,
+		All:     releaseNotesAllFlag,
+	})
+
+	if releaseNotesOutputFlag == "" {
+		fmt.Print(notes)
+		return nil
+	}
+	if err := os.WriteFile(releaseNotesOutputFlag, []byte(notes), 0644); err != nil {
+		return fmt.Errorf("error writing release notes: %w", err)
+	}
+	fmt.Printf("Wrote release notes for %d commits to %s\n", len(commits), releaseNotesOutputFlag)
+	return nil
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+// maxRevertCandidates caps the recent commits compared with the staged changes
+const maxRevertCandidates = 20
+
+// revertedCommit returns the recent commit the staged changes undo, or nil
+// when they undo none. A revert git revert --no-commit left to commit names
+// the commit itself.
+func revertedCommit(ctx context.Context, changes []*parser.Change) *analyzer.Revert {
+	if hash, err := parser.ResolveRevision(ctx, "REVERT_HEAD"); err == nil {
+		commits, err := parser.ParseCommitList(ctx, []string{hash})
+		if err == nil && len(commits) == 1 {
+			return &analyzer.Revert{Commit: commits[0], Similarity: 1, InProgress: true}
+		}
+	}
+
+	commits, err := parser.RecentCommits(ctx, maxRevertCandidates)
+	if err != nil {
+		return nil
+	}
+	staged := make(map[string]bool)
+	for _, change := range changes {
+		staged[change.File] = true
+		if change.Source != "" {
+			staged[change.Source] = true
+		}
+	}
+
+	// Only commits changing the staged files are diffed
+	var candidates []analyzer.RevertCandidate
+	for _, commit := range commits {
+		if !touchesAny(commit.Files, staged) {
+			continue
+		}
+		commitChanges, err := parser.NewGitParser().ParseShowChanges(ctx, commit.Hash)
+		if err != nil {
+			return nil
+		}
+		candidates = append(candidates, analyzer.RevertCandidate{Commit: commit, Changes: commitChanges})
+	}
+	return analyzer.FindRevertedCommit(changes, candidates)
+}
+
+// touchesAny reports whether any of the files is in the set
+func touchesAny(files []string, set map[string]bool) bool {
+	for _, file := range files {
+		if set[file] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/changelog"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/gitcmd"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/prompt"
+	"github.com/andev0x/gitmit/internal/style"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	rewordBaseFlag   string
+	rewordLastFlag   int
+	rewordAllFlag    bool
+	rewordDryRunFlag bool
+	rewordYesFlag    bool
+
+	rewordCmd = &cobra.Command{
+		Use:   "reword [range]",
+		Short: "Regenerate the messages of earlier commits from their diffs",
+		Long: `Walk the commits of a range, suggest a new message for each from its own diff,
+and show the old and new messages side by side for review. The approved
+messages are applied with git rebase -i, which rewrites the commits.
+
+The commits are given as a range (base..head), with --last N, or default to
+the commits of the current branch since its base. Commits that already follow
+the conventional format keep their message unless --all is given. Trailers such
+as "Refs:" of the old messages are kept.
+
+Rewriting changes the hashes of the commits, so only reword commits that have
+not been shared, or force-push afterwards.`,
+		Example: `  gitmit reword
+  gitmit reword --last 5 --dry-run
+  gitmit reword main..HEAD --all`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE:         runReword,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(rewordCmd)
+	rewordCmd.Flags().StringVar(&rewordBaseFlag, "base", "", "Reword the commits since this branch or commit (default: origin's default branch, main or master)")
+	rewordCmd.Flags().IntVar(&rewordLastFlag, "last", 0, "Reword the last N commits")
+	rewordCmd.Flags().BoolVar(&rewordAllFlag, "all", false, "Also reword commits that already follow the conventional format")
+	rewordCmd.Flags().BoolVar(&rewordDryRunFlag, "dry-run", false, "Show the new messages without rewriting commits")
+	rewordCmd.Flags().BoolVarP(&rewordYesFlag, "yes", "y", false, "Apply every new message without reviewing them")
+}
+
+// rewording is the new message proposed for a commit
+type rewording struct {
+	Commit  *parser.Commit
+	Message string
+	Apply   bool
+}
+
+func runReword(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	hist, err := history.LoadHistory(ctx)
+	if err != nil {
+		return err
+	}
+
+	base, head, err := resolveRange(ctx, args, rewordBaseFlag, rewordLastFlag)
+	if err != nil {
+		return err
+	}
+	commits, err := parser.ParseCommits(ctx, base, head)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		return fmt.Errorf("no commits between %s and %s", base, head)
+	}
+
+	f, _ := newFormatters(ctx, cfg, "", "", learnRepoStyle(cfg, hist))
+	var rewordings []*rewording
+	for i, entry := range changelog.Parse(commits) {
+		r := &rewording{Commit: commits[i]}
+		rewordings = append(rewordings, r)
+		if entry.Type != "" && !rewordAllFlag {
+			continue
+		}
+
+		// Each commit gets its own parser, which totals the lines of its diff
+		gitParser := parser.NewGitParser()
+		changes, err := gitParser.ParseCommitChanges(ctx, commits[i].Hash)
+		if err != nil {
+			return err
+		}
+		suggestion, err := heuristicSuggestion(ctx, cfg, hist, gitParser, changes)
+		if err != nil {
+			return err
+		}
+		if suggestion == "" {
+			continue
+		}
+		r.Message = f.FormatMessage(suggestion, false)
+		if len(entry.Footers) > 0 {
+			r.Message += "\n\n" + strings.Join(entry.Footers, "\n")
+		}
+		r.Apply = style.Subject(r.Message) != commits[i].Subject
+	}
+
+	printRewordings(rewordings)
+	if countApplied(rewordings) == 0 {
+		ui.Warn("No commit messages to change.")
+		return nil
+	}
+	if rewordDryRunFlag {
+		return nil
+	}
+
+	if !rewordYesFlag {
+		if err := reviewRewordings(prompt.New(), rewordings); err != nil {
+			if errors.Is(err, prompt.ErrInterrupted) {
+				ui.Warn("Reword cancelled.")
+				return nil
+			}
+			return err
+		}
+		if countApplied(rewordings) == 0 {
+			ui.Warn("Reword cancelled.")
+			return nil
+		}
+	}
+	return applyRewordings(ctx, base, head, rewordings)
+}
+
+// printRewordings shows the old and new subject of every commit
+func printRewordings(rewordings []*rewording) {
+	width := 0
+	for _, r := range rewordings {
+		width = max(width, utf8.RuneCountInString(r.Commit.Subject))
+	}
+	width = min(width, 50)
+
+	for i, r := range rewordings {
+		old := r.Commit.Subject
+		if utf8.RuneCountInString(old) > width {
+			old = string([]rune(old)[:width-1]) + "…"
+		}
+		padding := strings.Repeat(" ", width-utf8.RuneCountInString(old))
+		if r.Apply {
+			fmt.Printf("%2d. %s  %s%s → %s\n", i+1, ui.WarnString("%s", r.Commit.ShortHash()), old, padding, ui.SuccessString("%s", style.Subject(r.Message)))
+		} else {
+			fmt.Printf("%2d. %s  %s%s   %s\n", i+1, ui.WarnString("%s", r.Commit.ShortHash()), old, padding, ui.MutedString("(unchanged)"))
+		}
+	}
+}
+
+// reviewRewordings asks whether to apply the new messages, all at once or one by one
+func reviewRewordings(p *prompt.Prompter, rewordings []*rewording) error {
+	choice, err := p.Select(fmt.Sprintf("Reword %d commit(s)?", countApplied(rewordings)), []prompt.Option{
+		{Value: "all", Description: "Apply every new message"},
+		{Value: "review", Description: "Review each new message"},
+		{Value: "cancel", Description: "Keep the current messages"},
+	}, 0, nil)
+	if err != nil {
+		return err
+	}
+	switch choice {
+	case 0:
+		return nil
+	case 2:
+		for _, r := range rewordings {
+			r.Apply = false
+		}
+		return nil
+	}
+
+	for _, r := range rewordings {
+		if !r.Apply {
+			continue
+		}
+		fmt.Printf("\n%s %s\n%s\n", ui.WarnString("%s", r.Commit.ShortHash()), r.Commit.Subject, ui.SuccessString("%s", r.Message))
+		action, err := p.Select("Use the new message?", []prompt.Option{
+			{Value: "yes", Description: "Use the new message"},
+			{Value: "edit", Description: "Edit the new subject"},
+			{Value: "no", Description: "Keep the current message"},
+		}, 0, nil)
+		if err != nil {
+			return err
+		}
+		switch action {
+		case 1:
+			subject, err := p.Input("Subject", style.Subject(r.Message), nil)
+			if err != nil {
+				return err
+			}
+			_, rest, _ := strings.Cut(r.Message, "\n")
+			r.Message = strings.TrimRight(subject+"\n"+rest, "\n")
+		case 2:
+			r.Apply = false
+		}
+	}
+	return nil
+}
+
+// applyRewordings rewrites the commits with an interactive rebase whose todo
+// list amends each reworded commit with its new message
+func applyRewordings(ctx context.Context, base, head string, rewordings []*rewording) error {
+	current, err := checkRewritable(ctx, base, head)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "gitmit-reword")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var todo strings.Builder
+	for i, r := range rewordings {
+		fmt.Fprintf(&todo, "pick %s %s\n", r.Commit.Hash, r.Commit.Subject)
+		if !r.Apply {
+			continue
+		}
+		if err := amendTodo(&todo, dir, i, r.Message); err != nil {
+			return err
+		}
+	}
+	if err := runRebaseTodo(ctx, base, dir, todo.String()); err != nil {
+		return fmt.Errorf("error rewording commits (git rebase --abort restores %s): %w", current[:7], err)
+	}
+	ui.Success("✅ Reworded %d commit(s). The previous tip was %s.", countApplied(rewordings), current[:7])
+	return nil
+}
+
+// checkRewritable returns the hash of HEAD when the commits base..head can be
+// rewritten by a rebase: the range ends at HEAD and holds no merge commits
+func checkRewritable(ctx context.Context, base, head string) (string, error) {
+	current, err := parser.ResolveRevision(ctx, "HEAD")
+	if err != nil {
+		return "", err
+	}
+	if tip, err := parser.ResolveRevision(ctx, head); err != nil || tip != current {
+		return "", fmt.Errorf("only commits up to HEAD can be rewritten")
+	}
+	if out, err := gitcmd.Output(ctx, "", "rev-list", "--merges", base+".."+head); err != nil {
+		return "", fmt.Errorf("error listing merge commits: %w", err)
+	} else if strings.TrimSpace(out) != "" {
+		return "", fmt.Errorf("the range contains merge commits, which rewriting would flatten")
+	}
+	return current, nil
+}
+
+// amendTodo adds a step to a rebase todo list that gives the commit just
+// picked a new message, written to a file in dir
+func amendTodo(todo *strings.Builder, dir string, i int, message string) error {
+	file := filepath.Join(dir, fmt.Sprintf("message-%d", i))
+	if err := os.WriteFile(file, []byte(message+"\n"), 0644); err != nil {
+		return fmt.Errorf("error writing message file: %w", err)
+	}
+	fmt.Fprintf(todo, "exec git commit --amend --allow-empty --quiet --file %s\n", shellQuote(file))
+	return nil
+}
+
+// runRebaseTodo rebases the commits since base with a prepared todo list
+func runRebaseTodo(ctx context.Context, base, dir, todo string) error {
+	todoFile := filepath.Join(dir, "todo")
+	if err := os.WriteFile(todoFile, []byte(todo), 0644); err != nil {
+		return fmt.Errorf("error writing rebase todo list: %w", err)
+	}
+
+	// git runs the sequence editor with the path of its todo list, which the
+	// prepared list replaces
+	rebaseCmd := gitcmd.LongCommand(ctx, "rebase", "--interactive", base)
+	rebaseCmd.Env = append(os.Environ(), "GIT_SEQUENCE_EDITOR=cp "+shellQuote(todoFile))
+	rebaseCmd.Stdout = os.Stdout
+	rebaseCmd.Stderr = os.Stderr
+	return rebaseCmd.Run()
+}
+
+// countApplied counts the commits that get a new message
+func countApplied(rewordings []*rewording) int {
+	n := 0
+	for _, r := range rewordings {
+		if r.Apply {
+			n++
+		}
+	}
+	return n
+}
+
+// shellQuote quotes a string for sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
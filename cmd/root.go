@@ -1,16 +1,39 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/ai"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/gitcmd"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/logging"
+	"github.com/andev0x/gitmit/internal/templater"
+	"github.com/andev0x/gitmit/internal/ui"
+	"github.com/andev0x/gitmit/internal/update"
 )
 
 var (
 	version = "1.0.6"
 	// Global flags
-	interactiveFlag bool
-	suggestionsFlag bool
+	interactiveFlag   bool
+	suggestionsFlag   bool
+	noColorFlag       bool
+	asciiFlag         bool
+	verboseFlag       int
+	seedFlag          int64
+	deterministicFlag bool
+
+	// updateNotice tells about a newer release found by the background
+	// check, when the config enables it
+	updateNotice func() string
 
 	rootCmd = &cobra.Command{
 		Use:   "gitmit",
@@ -25,11 +48,16 @@ Examples:
   gitmit propose -s       # Show multiple suggestions
   gitmit propose --auto   # Auto-commit with best suggestion`,
 		Version: version,
+		// main prints the error, with a hint for known kinds
+		SilenceErrors: true,
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
 			// Add global validation or setup here
 			if suggestionsFlag {
 				interactiveFlag = true // -s implies -i
 			}
+			// The flags parsed, so errors from here on are not about usage
+			cmd.SilenceUsage = true
+			setupRun(cmd.Context())
 		},
 	}
 )
@@ -38,12 +66,88 @@ func init() {
 	// Global flags available to all commands
 	rootCmd.PersistentFlags().BoolVarP(&interactiveFlag, "interactive", "i", false, "Interactive mode with multiple suggestions")
 	rootCmd.PersistentFlags().BoolVarP(&suggestionsFlag, "suggestions", "s", false, "Show multiple ranked suggestions")
+	rootCmd.PersistentFlags().BoolVar(&noColorFlag, "no-color", false, "Disable colored output (also set by the NO_COLOR environment variable)")
+	rootCmd.PersistentFlags().CountVarP(&verboseFlag, "verbose", "v", "Log the steps behind the output to stderr (-vv for every detail, such as git commands and template scores)")
+	rootCmd.PersistentFlags().BoolVar(&asciiFlag, "ascii", false, "Plain ASCII output: [OK] and [WARN] labels instead of emoji, no box drawing")
+	rootCmd.PersistentFlags().Int64Var(&seedFlag, "seed", 0, "Seed the variety in template choices, so the same changes get the same suggestions")
+	rootCmd.PersistentFlags().BoolVar(&deterministicFlag, "deterministic", false, "Leave the variety out of template choices, breaking ties lexically")
+}
+
+// setupRun applies --verbose, --no-color, --ascii, --seed and --deterministic,
+// and the theme, timeouts, history retention, randomness and update check of
+// the configuration. A config that fails to load keeps the defaults; the command
+// reports the error.
+func setupRun(ctx context.Context) {
+	logging.Setup(os.Stderr, verboseFlag)
+	if noColorFlag {
+		ui.DisableColor()
+	}
+	cfg := config.DefaultConfig()
+	if loaded, err := config.LoadConfig(ctx); err == nil {
+		cfg = loaded
+	}
+	if asciiFlag {
+		cfg.Theme.ASCII = true
+	}
+	if seedFlag != 0 {
+		cfg.Seed = seedFlag
+	}
+	if deterministicFlag {
+		cfg.Deterministic = true
+	}
+	ui.SetTheme(cfg.Theme)
+	gitcmd.SetTimeout(cfg.Timeouts.GitTimeout())
+	ai.SetTimeout(cfg.Timeouts.AITimeout())
+	history.SetRetention(cfg.History.MaxEntries, cfg.History.MaxAgeDuration())
+	if cfg.Seed != 0 {
+		templater.SetSeed(cfg.Seed)
+	}
+	templater.SetDeterministic(cfg.Deterministic)
+	if cfg.UpdateCheck {
+		updateNotice = update.StartCheck(context.Background(), version)
+	}
 }
 
+// interruptGrace is how long a command may take to stop after Ctrl+C before
+// gitmit exits anyway, as it must when waiting for input. Commands that shut
+// down servers allow more.
+var interruptGrace = time.Second
+
+// exitInterrupted is the exit status of a run stopped by Ctrl+C or SIGTERM
+const exitInterrupted = 130
+
+// Execute runs the command line. Ctrl+C or SIGTERM cancels the context of the
+// command, which stops git and AI requests in flight; a second Ctrl+C exits
+// at once.
 func Execute() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		cancel()
+		signal.Stop(signals)
+		time.Sleep(interruptGrace)
+		os.Exit(exitInterrupted)
+	}()
+
+	var err error
 	// ✅ Added: if no subcommand provided, fallback to "propose"
 	if len(os.Args) == 1 {
-		return proposeCmd.RunE(rootCmd, nil)
+		setupRun(ctx)
+		rootCmd.SetContext(ctx)
+		err = proposeCmd.RunE(rootCmd, nil)
+	} else {
+		err = rootCmd.ExecuteContext(ctx)
+	}
+	if ctx.Err() != nil {
+		os.Exit(exitInterrupted)
+	}
+	if updateNotice != nil {
+		if notice := updateNotice(); notice != "" {
+			fmt.Fprintln(os.Stderr, ui.MutedString("%s", notice))
+		}
 	}
-	return rootCmd.Execute()
+	return err
 }
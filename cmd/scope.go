@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/prompt"
+	"github.com/andev0x/gitmit/internal/style"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+// selectScope asks for one of the scope options, no scope or, when allowCustom
+// is set, a typed one. The preview shows the message with the highlighted scope.
+func selectScope(p *prompt.Prompter, options []prompt.Option, allowCustom bool, preview func(scope string) string) (string, error) {
+	const (
+		noScope     = "(none)"
+		customScope = "(custom)"
+	)
+
+	options = append(options, prompt.Option{Value: noScope, Description: "no scope"})
+	if allowCustom {
+		options = append(options, prompt.Option{Value: customScope, Description: "type a scope"})
+	}
+
+	scopeOf := func(i int) string {
+		if v := options[i].Value; v != noScope && v != customScope {
+			return v
+		}
+		return ""
+	}
+	i, err := p.Select("Scope", options, 0, func(i int) string {
+		return preview(scopeOf(i))
+	})
+	if err != nil {
+		return "", err
+	}
+	if options[i].Value != customScope {
+		return scopeOf(i), nil
+	}
+
+	scope, err := p.Input("Custom scope", "", preview)
+	if err != nil {
+		return "", err
+	}
+	if strings.ContainsAny(scope, "()") {
+		ui.Warn("⚠ Parentheses are not allowed in a scope; removing them.")
+		scope = strings.NewReplacer("(", "", ")", "").Replace(scope)
+	}
+	return scope, nil
+}
+
+// allowCustomScope reports whether scopes other than the known ones may be used
+func allowCustomScope(repoStyle *style.Style) bool {
+	return !repoStyle.StrictScopes || len(repoStyle.Scopes) == 0
+}
+
+// scopeOptions returns the scopes offered for a message, each once: its current
+// scope, the scopes detected in the changes, the scopes used before or
+// configured, then the canonical scopes of the scope aliases. With strictScopes
+// only known scopes are offered.
+func scopeOptions(cfg *config.Config, repoStyle *style.Style, commitMessage *analyzer.CommitMessage, current string) []prompt.Option {
+	var options []prompt.Option
+	seen := make(map[string]bool)
+	add := func(scope, description string) {
+		key := strings.ToLower(scope)
+		if scope == "" || seen[key] {
+			return
+		}
+		if !allowCustomScope(repoStyle) && !knownScope(scope, repoStyle.Scopes) {
+			return
+		}
+		seen[key] = true
+		options = append(options, prompt.Option{Value: scope, Description: description})
+	}
+
+	add(current, "current")
+	if commitMessage != nil {
+		add(commitMessage.Scope, "detected")
+		for _, scope := range commitMessage.ScopeCandidates {
+			add(scope, "detected")
+		}
+	}
+	known := "used before"
+	if len(cfg.Scopes) > 0 {
+		known = "configured"
+	}
+	for _, scope := range repoStyle.Scopes {
+		add(scope, known)
+	}
+
+	aliases := make(map[string][]string)
+	for alias, scope := range cfg.ScopeAliases {
+		aliases[scope] = append(aliases[scope], alias)
+	}
+	for _, scope := range sortedScopes(aliases) {
+		sort.Strings(aliases[scope])
+		add(scope, "alias of "+strings.Join(aliases[scope], ", "))
+	}
+	return options
+}
+
+// pickScope asks for another scope for a message among scopeOptions, showing
+// the message re-rendered with each. It returns false when the message has no
+// conventional type to scope or the picker is cancelled.
+func pickScope(reader *bufio.Reader, options []prompt.Option, allowCustom bool, message string, render func(scope string) string) (string, bool) {
+	if commitType, _, _, _ := style.ParseSubject(style.Subject(message)); commitType == "" {
+		ui.Warn("⚠ Message has no conventional type prefix to scope.\n")
+		return "", false
+	}
+	scope, err := selectScope(prompt.NewStdin(reader), options, allowCustom, render)
+	if errors.Is(err, prompt.ErrInterrupted) {
+		ui.Println()
+		return "", false
+	}
+	if err != nil {
+		ui.Error("❌ %v", err)
+		return "", false
+	}
+	return scope, true
+}
+
+// knownScope reports whether scope is one of the known scopes, in any case
+func knownScope(scope string, known []string) bool {
+	for _, k := range known {
+		if strings.EqualFold(k, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedScopes returns the keys of a map of scopes in order
+func sortedScopes(scopes map[string][]string) []string {
+	keys := make([]string, 0, len(scopes))
+	for scope := range scopes {
+		keys = append(keys, scope)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,500 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/gitcmd"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/templater"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	serveAddrFlag string
+
+	serveCmd = &cobra.Command{
+		Use:   "serve",
+		Short: "Serve suggestions over HTTP and JSON-RPC for editor plugins",
+		Long: `Run a long-lived server that suggests messages for the staged changes of any
+repository, so editor plugins get suggestions without starting gitmit for
+every request. The configuration, commit history and template packs of each
+repository stay in memory and are reloaded when a config file changes or a
+new commit is made.
+
+Endpoints, all taking and returning JSON:
+
+  POST /suggest   {"repo": "/path/to/repo", "max": 3}
+                  the best message and, with max > 1, ranked suggestions
+  POST /analyze   {"repo": "/path/to/repo"}
+                  the analysis behind the suggestion: type, topic, scope,
+                  files and the signals that decided the type
+  POST /rpc       JSON-RPC 2.0 with the methods suggest and analyze, whose
+                  params are the bodies above
+  GET  /health    {"status": "ok", "version": "..."}
+
+Requests without "repo" use the repository the server was started in.
+Suggestions always come from the heuristic engine, which answers in
+milliseconds; the server listens on localhost only unless --addr says
+otherwise.
+
+So web pages cannot use the server, requests must name a loopback host or
+the host of --addr, must not carry an Origin header, and must POST
+Content-Type: application/json.`,
+		Example: `  gitmit serve
+  gitmit serve --addr 127.0.0.1:9000
+  curl -s localhost:7823/suggest -H 'Content-Type: application/json' -d '{"repo": "'$PWD'", "max": 3}'`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runServe,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVar(&serveAddrFlag, "addr", "127.0.0.1:7823", "Address to listen on")
+}
+
+// serveShutdownTimeout bounds how long requests in flight may finish on Ctrl+C
+const serveShutdownTimeout = 5 * time.Second
+
+func runServe(cmd *cobra.Command, args []string) error {
+	home, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error reading the working directory: %w", err)
+	}
+	s := newSuggestServer(home, serveAddrFlag)
+	httpServer := &http.Server{Addr: serveAddrFlag, Handler: s.handler()}
+
+	// Ctrl+C cancels the context of the command; requests in flight may finish
+	ctx, stop := context.WithCancel(cmd.Context())
+	defer stop()
+	interruptGrace = serveShutdownTimeout
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	ui.Success("🛰  gitmit %s serving on http://%s (Ctrl+C to stop)", version, serveAddrFlag)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("error serving on %s: %w", serveAddrFlag, err)
+	}
+	return nil
+}
+
+// suggestServer answers suggestion requests for any repository, keeping what
+// it loaded for each in memory
+type suggestServer struct {
+	// The states of the repositories are loaded and used by one request at a time
+	mu    sync.Mutex
+	home  string // Repository of requests naming none
+	addr  string // Address served on, whose host requests may name besides loopback ones
+	repos map[string]*repoState
+}
+
+// repoState is what the server keeps in memory for a repository
+type repoState struct {
+	root       string // Top of the working tree
+	stamp      string // Config files and HEAD the state was loaded from
+	cfg        *config.Config
+	hist       *history.CommitHistory
+	templaters map[string]*templater.Templater // By template file
+}
+
+func newSuggestServer(home, addr string) *suggestServer {
+	return &suggestServer{home: home, addr: addr, repos: make(map[string]*repoState)}
+}
+
+// serveRequest is the body of /suggest and /analyze and the params of their
+// JSON-RPC methods
+type serveRequest struct {
+	Repo string `json:"repo"`
+	Max  int    `json:"max"` // Suggestions to rank; only the best message when below 2
+}
+
+type suggestResponse struct {
+	Repo        string               `json:"repo"`
+	Branch      string               `json:"branch"`
+	Files       int                  `json:"files"` // Staged files; 0 when nothing is staged and there is no message
+	Message     string               `json:"message"`
+	Suggestions []suggestionResponse `json:"suggestions,omitempty"`
+}
+
+type suggestionResponse struct {
+	Message string `json:"message"`
+	Reason  string `json:"reason"`
+}
+
+type analyzeResponse struct {
+	Repo         string                `json:"repo"`
+	Branch       string                `json:"branch"`
+	Files        []string              `json:"files"`
+	Added        int                   `json:"added"`
+	Removed      int                   `json:"removed"`
+	Action       string                `json:"action,omitempty"`
+	Topic        string                `json:"topic,omitempty"`
+	Item         string                `json:"item,omitempty"`
+	Purpose      string                `json:"purpose,omitempty"`
+	Scope        string                `json:"scope,omitempty"`
+	IsMajor      bool                  `json:"isMajor"`
+	Reasons      []string              `json:"reasons,omitempty"`
+	Alternatives []alternativeResponse `json:"alternatives,omitempty"`
+}
+
+type alternativeResponse struct {
+	Action  string   `json:"action"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// serveMethods are the methods of the server, by endpoint and JSON-RPC name
+func (s *suggestServer) serveMethods() map[string]func(context.Context, serveRequest) (interface{}, error) {
+	return map[string]func(context.Context, serveRequest) (interface{}, error){
+		"suggest": func(ctx context.Context, req serveRequest) (interface{}, error) { return s.suggest(ctx, req) },
+		"analyze": func(ctx context.Context, req serveRequest) (interface{}, error) { return s.analyze(ctx, req) },
+	}
+}
+
+// handler returns the endpoints of the server, guarded against web pages
+func (s *suggestServer) handler() http.Handler {
+	return s.guard(s.routes())
+}
+
+// guard rejects requests a web page could make: naming a host other than a
+// loopback one or that of the address served on, as after DNS rebinding, with
+// an Origin header, which browsers send on requests of pages, or posting other
+// content than JSON, which pages can send without asking the server first
+func (s *suggestServer) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.allowedHost(r.Host) {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": fmt.Sprintf("host %q not allowed: use localhost or the host of --addr", r.Host)})
+			return
+		}
+		if r.Header.Get("Origin") != "" {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": "requests from web pages are not allowed"})
+			return
+		}
+		if r.Method == http.MethodPost {
+			if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+				writeJSON(w, http.StatusUnsupportedMediaType, map[string]string{"error": "use Content-Type: application/json"})
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowedHost reports whether the Host of a request names a loopback address
+// or the host the server was told to listen on
+func (s *suggestServer) allowedHost(host string) bool {
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		host = name
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return true
+	}
+	served, _, err := net.SplitHostPort(s.addr)
+	served = strings.Trim(served, "[]")
+	return err == nil && served != "" && strings.EqualFold(host, served)
+}
+
+// routes returns the endpoints of the server, unguarded
+func (s *suggestServer) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "version": version})
+	})
+	for name, method := range s.serveMethods() {
+		method := method
+		mux.HandleFunc("/"+name, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST with a JSON body"})
+				return
+			}
+			var req serveRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body: " + err.Error()})
+				return
+			}
+			result, err := method(r.Context(), req)
+			if err != nil {
+				writeJSON(w, errorStatus(err), map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, result)
+		})
+	}
+	mux.HandleFunc("/rpc", s.handleRPC)
+	return mux
+}
+
+// JSON-RPC 2.0 error codes
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcServerError    = -32000
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// handleRPC answers a JSON-RPC 2.0 request; notifications, which have no id,
+// get no response body
+func (s *suggestServer) handleRPC(w http.ResponseWriter, r *http.Request) {
+	fail := func(id json.RawMessage, code int, message string) {
+		writeJSON(w, http.StatusOK, rpcResponse{JSONRPC: "2.0", Error: &rpcError{Code: code, Message: message}, ID: nullID(id)})
+	}
+
+	var req rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		fail(nil, rpcParseError, "parse error: "+err.Error())
+		return
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		fail(req.ID, rpcInvalidRequest, `invalid request: expected "jsonrpc": "2.0" and a method`)
+		return
+	}
+	method, ok := s.serveMethods()[req.Method]
+	if !ok {
+		fail(req.ID, rpcMethodNotFound, fmt.Sprintf("method %q not found", req.Method))
+		return
+	}
+	var params serveRequest
+	if len(req.Params) > 0 && string(req.Params) != "null" {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			fail(req.ID, rpcInvalidParams, "invalid params: "+err.Error())
+			return
+		}
+	}
+
+	result, err := method(r.Context(), params)
+	if len(req.ID) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		fail(req.ID, rpcServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, rpcResponse{JSONRPC: "2.0", Result: result, ID: req.ID})
+}
+
+// nullID returns the id of a response, null when the request had none
+func nullID(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// enter returns the context of a request, which runs git in its repository,
+// and the repository's state, loading the state again when its config files
+// or HEAD changed
+func (s *suggestServer) enter(ctx context.Context, repo string) (context.Context, *repoState, error) {
+	if repo == "" {
+		repo = s.home
+	}
+	root, err := parser.RepoRoot(ctx, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx = gitcmd.WithDir(ctx, root)
+
+	stamp := repoStamp(ctx)
+	if state, ok := s.repos[root]; ok && state.stamp == stamp {
+		return ctx, state, nil
+	}
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	hist, err := history.LoadHistory(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	state := &repoState{root: root, stamp: stamp, cfg: cfg, hist: hist, templaters: make(map[string]*templater.Templater)}
+	s.repos[root] = state
+	return ctx, state, nil
+}
+
+// repoStamp identifies the config files and commit the state of the current
+// repository depends on
+func repoStamp(ctx context.Context) string {
+	head, _ := parser.ResolveRevision(ctx, "HEAD")
+	parts := []string{head}
+	for _, file := range config.ConfigFiles(ctx) {
+		if info, err := os.Stat(file); err == nil {
+			parts = append(parts, fmt.Sprintf("%s@%d", file, info.ModTime().UnixNano()))
+		}
+	}
+	return strings.Join(parts, "|")
+}
+
+// templater returns the templater of a template pack, loading it once. A pack
+// named relative to the working directory is looked up in the repository.
+func (state *repoState) templater(templateFile string) (*templater.Templater, error) {
+	if t, ok := state.templaters[templateFile]; ok {
+		return t, nil
+	}
+	path := templateFile
+	if !filepath.IsAbs(templateFile) {
+		if _, err := os.Stat(filepath.Join(state.root, templateFile)); err == nil {
+			path = filepath.Join(state.root, templateFile)
+		}
+	}
+	t, err := templater.NewTemplater(path, state.hist)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.AddTemplates(state.cfg.Templates); err != nil {
+		return nil, err
+	}
+	t.RestrictTypes(state.cfg.Types)
+	state.templaters[templateFile] = t
+	return t, nil
+}
+
+// stagedAnalysis analyzes the staged changes of the current repository, with
+// the config applying to them; the message is nil when nothing is staged
+func stagedAnalysis(ctx context.Context, state *repoState) (*config.Config, *analyzer.CommitMessage, string, error) {
+	gitParser := parser.NewGitParser()
+	changes, err := gitParser.ParseStagedChanges(ctx)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	branchName, _ := gitParser.GetCurrentBranch(ctx)
+	cfg, commitMessage, err := analyzeChanges(ctx, state.cfg, gitParser, changes, branchName)
+	return cfg, commitMessage, branchName, err
+}
+
+func (s *suggestServer) suggest(ctx context.Context, req serveRequest) (*suggestResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx, state, err := s.enter(ctx, req.Repo)
+	if err != nil {
+		return nil, err
+	}
+	cfg, commitMessage, branchName, err := stagedAnalysis(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	response := &suggestResponse{Repo: state.root, Branch: branchName}
+	if commitMessage == nil {
+		return response, nil
+	}
+	response.Files = len(commitMessage.Files)
+
+	policy := cfg.BranchPolicy(branchName)
+	t, err := state.templater(selectTemplateFile(cfg, policy, ""))
+	if err != nil {
+		return nil, err
+	}
+	// A required ticket cannot be asked for, so only one found in the branch is prefixed
+	f, _ := newFormatters(ctx, cfg, branchTicketPrefix(cfg, policy, branchName), "", learnRepoStyle(cfg, state.hist))
+	useCommitTemplate(ctx, cfg, f)
+	// Repeats of recent commits are told apart as the messages would be committed
+	t.FormatWith(func(message string) string { return f.FormatMessage(message, commitMessage.IsMajor) })
+
+	release := releaseBody(ctx, commitMessage)
+	message, err := t.GetMessage(commitMessage)
+	if err != nil {
+		return nil, err
+	}
+	if release != "" {
+		message += "\n\n" + release
+	}
+	response.Message = f.FormatMessage(message, commitMessage.IsMajor)
+
+	if req.Max > 1 {
+		suggestions, err := t.GetSuggestions(commitMessage, req.Max)
+		if err != nil {
+			return nil, err
+		}
+		for _, suggestion := range suggestions {
+			response.Suggestions = append(response.Suggestions, suggestionResponse{
+				Message: f.FormatMessage(suggestion.Message, commitMessage.IsMajor),
+				Reason:  suggestion.Reason,
+			})
+		}
+	}
+	return response, nil
+}
+
+func (s *suggestServer) analyze(ctx context.Context, req serveRequest) (*analyzeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx, state, err := s.enter(ctx, req.Repo)
+	if err != nil {
+		return nil, err
+	}
+	_, commitMessage, branchName, err := stagedAnalysis(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	response := &analyzeResponse{Repo: state.root, Branch: branchName, Files: []string{}}
+	if commitMessage == nil {
+		return response, nil
+	}
+	response.Files = commitMessage.Files
+	response.Added = commitMessage.TotalAdded
+	response.Removed = commitMessage.TotalRemoved
+	response.Action = commitMessage.Action
+	response.Topic = commitMessage.Topic
+	response.Item = commitMessage.Item
+	response.Purpose = commitMessage.Purpose
+	response.Scope = commitMessage.Scope
+	response.IsMajor = commitMessage.IsMajor
+	response.Reasons = commitMessage.ActionReasons
+	for _, alternative := range commitMessage.Alternatives {
+		response.Alternatives = append(response.Alternatives, alternativeResponse{Action: alternative.Action, Reasons: alternative.Reasons})
+	}
+	return response, nil
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testRepo creates a repository with one commit and a staged new file, with
+// gitmit's config, state and git's global config kept out of the test
+func testRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, "cache"))
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir := t.TempDir()
+	git(t, dir, "init", "-q", "-b", "main")
+	writeFile(t, filepath.Join(dir, "README.md"), "# demo\n")
+	git(t, dir, "add", "README.md")
+	git(t, dir, "commit", "-q", "-m", "docs: add readme")
+	writeFile(t, filepath.Join(dir, "internal", "auth", "login.go"), "package auth\n\nfunc Login() error {\n\treturn nil\n}\n")
+	git(t, dir, "add", ".")
+	return dir
+}
+
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServeGuard(t *testing.T) {
+	s := newSuggestServer(t.TempDir(), "gitmit.test:7823")
+	handler := s.guard(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name        string
+		method      string
+		host        string
+		origin      string
+		contentType string
+		want        int
+	}{
+		{name: "localhost", method: http.MethodGet, host: "localhost:7823", want: http.StatusOK},
+		{name: "loopback IPv4", method: http.MethodGet, host: "127.0.0.1:7823", want: http.StatusOK},
+		{name: "loopback IPv6", method: http.MethodGet, host: "[::1]:7823", want: http.StatusOK},
+		{name: "host of --addr", method: http.MethodGet, host: "gitmit.test:7823", want: http.StatusOK},
+		{name: "other host", method: http.MethodGet, host: "attacker.example:7823", want: http.StatusForbidden},
+		{name: "private address", method: http.MethodGet, host: "192.168.1.5:7823", want: http.StatusForbidden},
+		{name: "origin", method: http.MethodGet, host: "localhost:7823", origin: "https://attacker.example", want: http.StatusForbidden},
+		{name: "json", method: http.MethodPost, host: "localhost:7823", contentType: "application/json; charset=utf-8", want: http.StatusOK},
+		{name: "form", method: http.MethodPost, host: "localhost:7823", contentType: "application/x-www-form-urlencoded", want: http.StatusUnsupportedMediaType},
+		{name: "no content type", method: http.MethodPost, host: "localhost:7823", want: http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/suggest", strings.NewReader("{}"))
+			req.Host = tt.host
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestServeSuggestLeavesWorkingDirectory(t *testing.T) {
+	repos := []string{testRepo(t), testRepo(t)}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(newSuggestServer(wd, "").handler())
+	defer server.Close()
+
+	// Requests for different repositories run side by side
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		repo := repos[i%len(repos)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body := strings.NewReader(`{"repo": ` + strconvQuote(repo) + `}`)
+			resp, err := http.Post(server.URL+"/suggest", "application/json", body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer resp.Body.Close()
+			var got suggestResponse
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil || resp.StatusCode != http.StatusOK {
+				t.Errorf("POST /suggest = %d, %v", resp.StatusCode, err)
+				return
+			}
+			if root, _ := filepath.EvalSymlinks(repo); got.Repo != root && got.Repo != repo {
+				t.Errorf("repo = %q, want %q", got.Repo, repo)
+			}
+			if got.Files != 1 || got.Branch != "main" || got.Message == "" {
+				t.Errorf("suggestion = %+v, want one staged file on main", got)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if now, _ := os.Getwd(); now != wd {
+		t.Errorf("working directory changed from %s to %s", wd, now)
+	}
+}
+
+func strconvQuote(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/templater"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+// maxSmartSuggestions is the number of messages smart recommends
+const maxSmartSuggestions = 5
+
+// smartConfidence is the expected confidence of the suggestions of each rule, in
+// percent, before outcomes are recorded
+var smartConfidence = map[string]int{
+	"template":    80,
+	"branch":      65,
+	"alternative": 50,
+}
+
+var (
+	smartCommitFlag bool
+	smartPrintFlag  bool
+
+	// smartSeed is the message picked in smart, which propose reviews instead of
+	// its own suggestion, and smartRule the rule that suggested it
+	smartSeed string
+	smartRule string
+
+	smartCmd = &cobra.Command{
+		Use:   "smart",
+		Short: "Analyze staged changes in depth and recommend commit messages",
+		Long: `Explain what the staged changes do, with their risk and the tests they
+miss, and recommend commit messages from several rules with a confidence for
+each: the best matching template, the branch name and other templates.
+Confidences are calibrated with what happened to earlier recommendations of
+each rule in the repository, accepted, edited or rejected, so that 80% means
+about 8 in 10 of the rule's recommendations are committed as they are.
+
+Pick a recommendation to review it with the same prompt as propose, where it
+can be accepted, edited, rescoped or regenerated before committing. With
+--commit the top recommendation is committed right away, under the same checks
+as propose --auto, and with --print nothing is committed.`,
+		Example: `  gitmit smart
+  gitmit smart --commit
+  gitmit smart --print
+  gitmit smart --full`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runSmart,
+	}
+)
+
+// SmartSuggestion is a commit message smart recommends, with the rule that produced it
+type SmartSuggestion struct {
+	Rule       string // Rule that produced the message: template, branch or alternative
+	Message    string // Unformatted message
+	Confidence int    // Estimated chance that the message is accepted, in percent
+	Outcomes   int    // Recorded outcomes of the rule the confidence is calibrated with
+	Reason     string
+}
+
+func init() {
+	rootCmd.AddCommand(smartCmd)
+	smartCmd.Flags().BoolVar(&smartCommitFlag, "commit", false, "Commit the top recommendation without prompting")
+	smartCmd.Flags().BoolVar(&smartPrintFlag, "print", false, "Only print the analysis and recommendations")
+	smartCmd.MarkFlagsMutuallyExclusive("commit", "print")
+	smartCmd.Flags().BoolVar(&fullFlag, "full", false, "List every changed file instead of collapsing long lists")
+	smartCmd.Flags().BoolVar(&addFlag, "add", false, "Stage the changes to tracked files first")
+	smartCmd.Flags().BoolVar(&addAllFlag, "add-all", false, "Stage all changes first, including untracked files")
+}
+
+func runSmart(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	hist, err := history.LoadHistory(ctx)
+	if err != nil {
+		return err
+	}
+
+	gitParser := parser.NewGitParser()
+	changes, err := stagedChanges(ctx, gitParser, !smartCommitFlag && !smartPrintFlag)
+	if err != nil {
+		return err
+	}
+
+	branchName, _ := gitParser.GetCurrentBranch(ctx)
+	cfg, commitMessage, err := analyzeChanges(ctx, cfg, gitParser, changes, branchName)
+	if err != nil {
+		return err
+	}
+	if commitMessage == nil {
+		return fmt.Errorf("could not analyze changes")
+	}
+	t, err := templater.NewTemplater(selectTemplateFile(cfg, cfg.BranchPolicy(branchName), ""), hist)
+	if err != nil {
+		return err
+	}
+	if err := t.AddTemplates(cfg.Templates); err != nil {
+		return err
+	}
+	t.RestrictTypes(cfg.Types)
+	f, _ := newFormatters(ctx, cfg, "", "", learnRepoStyle(cfg, hist))
+	// Repeats of recent commits are told apart as the messages would be committed
+	t.FormatWith(func(message string) string { return f.FormatMessage(message, commitMessage.IsMajor) })
+
+	suggestions := smartSuggestions(t, hist, commitMessage, branchName)
+	if len(suggestions) == 0 {
+		return fmt.Errorf("no commit message could be suggested for the staged changes")
+	}
+
+	displaySmartAnalysis(cfg, changes, commitMessage, fullFlag)
+
+	ui.Success("💡 Recommendations:")
+	for i, s := range suggestions {
+		subject := strings.SplitN(f.FormatMessage(s.Message, commitMessage.IsMajor), "\n", 2)[0]
+		ui.Printf("%d. %s %s\n", i+1, subject, ui.AccentString("[%d%%]", s.Confidence))
+		reason := fmt.Sprintf("%s: %s", s.Rule, s.Reason)
+		if s.Outcomes > 0 {
+			reason += fmt.Sprintf(" (calibrated from %d outcomes)", s.Outcomes)
+		}
+		ui.Printf("   %s\n", ui.MutedString("%s", reason))
+	}
+
+	if smartPrintFlag {
+		return nil
+	}
+
+	pick := 0
+	if !smartCommitFlag {
+		ui.Printf("\nReview a recommendation [1-%d, Enter for 1, q to quit]: ", len(suggestions))
+		input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		input = strings.TrimSpace(strings.ToLower(input))
+		switch n, err := strconv.Atoi(input); {
+		case input == "":
+		case input == "q" || input == "n":
+			ui.Warn("❌ Nothing committed.")
+			hist.RecordRuleOutcome(suggestions[0].Rule, history.OutcomeRejected)
+			return hist.SaveHistory(ctx)
+		case err != nil || n < 1 || n > len(suggestions):
+			return fmt.Errorf("invalid choice %q", input)
+		default:
+			pick = n - 1
+		}
+	}
+
+	// Passing over the top recommendation counts against its rule
+	if top := suggestions[0].Rule; top != suggestions[pick].Rule {
+		hist.RecordRuleOutcome(top, history.OutcomeRejected)
+		if err := hist.SaveHistory(ctx); err != nil {
+			return err
+		}
+	}
+
+	// The pick goes through propose, so it gets the same review prompt and checks
+	smartSeed = suggestions[pick].Message
+	smartRule = suggestions[pick].Rule
+	autoFlag = smartCommitFlag
+	return runPropose(cmd, nil)
+}
+
+// smartSuggestions returns the messages the rules suggest for an analysis, most
+// confident first and without duplicates. Confidences are calibrated with the
+// outcomes recorded for each rule.
+func smartSuggestions(t *templater.Templater, hist *history.CommitHistory, msg *analyzer.CommitMessage, branchName string) []SmartSuggestion {
+	var suggestions []SmartSuggestion
+	seen := make(map[string]bool)
+	add := func(rule, message, reason string) {
+		if message == "" || seen[message] {
+			return
+		}
+		seen[message] = true
+		acceptance, outcomes := hist.RuleAcceptance(rule, float64(smartConfidence[rule])/100)
+		suggestions = append(suggestions, SmartSuggestion{
+			Rule:       rule,
+			Message:    message,
+			Confidence: int(math.Round(acceptance * 100)),
+			Outcomes:   outcomes,
+			Reason:     reason,
+		})
+	}
+
+	if message, err := t.GetMessage(msg); err == nil {
+		add("template", message, fmt.Sprintf("best template for %s on %s", msg.Action, msg.Topic))
+	}
+	if commitType, description := analyzer.BranchDescription(branchName); description != "" {
+		prefix := commitType
+		if msg.Scope != "" {
+			prefix += "(" + msg.Scope + ")"
+		}
+		add("branch", prefix+": "+description, "named after branch "+branchName)
+	}
+	alternatives, _ := t.GetSuggestions(msg, maxSmartSuggestions)
+	for _, suggestion := range alternatives {
+		add("alternative", suggestion.Message, suggestion.Reason)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Confidence > suggestions[j].Confidence
+	})
+	if len(suggestions) > maxSmartSuggestions {
+		suggestions = suggestions[:maxSmartSuggestions]
+	}
+	return suggestions
+}
+
+// recordSmartOutcome records what the user did with the message picked in smart
+// for the rule that suggested it, once
+func recordSmartOutcome(hist *history.CommitHistory, outcome string) {
+	if smartRule == "" {
+		return
+	}
+	hist.RecordRuleOutcome(smartRule, outcome)
+	smartRule = ""
+}
+
+// displaySmartAnalysis explains the staged changes: their kind, what they do, the
+// files they touch by directory, their risk and the tests they miss. Long file
+// lists are collapsed unless full is set.
+func displaySmartAnalysis(cfg *config.Config, changes []*parser.Change, msg *analyzer.CommitMessage, full bool) {
+	ui.Heading("🧠 Smart analysis")
+	ui.Printf("Type:   %s\n", msg.Action)
+	if msg.Scope != "" {
+		ui.Printf("Scope:  %s\n", msg.Scope)
+	}
+	ui.Printf("Topic:  %s\n", msg.Topic)
+
+	if sentences := analyzer.NewAnalyzer(changes, cfg).Explain(msg); len(sentences) > 0 {
+		ui.Accent("\nWhat changed:")
+		for _, sentence := range sentences {
+			ui.Printf("  %s\n", sentence)
+		}
+	}
+
+	printFileGroups(changes, full)
+	ui.Println()
+
+	var missingTests []analyzer.MissingTest
+	if cfg.Tests.Nudge || cfg.Risk.Enabled {
+		missingTests = analyzer.MissingTests(changes, cfg.Tests.Mappings)
+	}
+	if cfg.Risk.Enabled {
+		printRisk(analyzer.AssessRisk(changes, missingTests, cfg.Risk), true)
+		ui.Println()
+	}
+	if cfg.Tests.Nudge {
+		printMissingTests(missingTests)
+	}
+}
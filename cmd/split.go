@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/formatter"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+// splitCommit is a commit of the staged changes split by concern
+type splitCommit struct {
+	Concern analyzer.Concern
+	Changes []*parser.Change
+	Message string
+}
+
+// printMixedConcerns warns that the staged changes mix concerns
+func printMixedConcerns(concerns []analyzer.Concern) {
+	var parts []string
+	for _, concern := range concerns {
+		parts = append(parts, fmt.Sprintf("%s (%s)", concern.Name, ui.Plural(len(concern.Files), "file", "files")))
+	}
+	ui.Warn("⚠ The staged changes mix %d concerns: %s.", len(concerns), strings.Join(parts, ", "))
+	ui.Muted("  Atomic commits are easier to review and revert; choose p to split them.")
+}
+
+// planSplit suggests a commit for the changes of each concern, in the order
+// of the concerns
+func planSplit(ctx context.Context, cfg *config.Config, hist *history.CommitHistory, f *formatter.Formatter, changes []*parser.Change, concerns []analyzer.Concern) ([]splitCommit, error) {
+	byFile := make(map[string]*parser.Change)
+	for _, change := range changes {
+		byFile[change.File] = change
+	}
+
+	var plan []splitCommit
+	for _, concern := range concerns {
+		commit := splitCommit{Concern: concern}
+		gitParser := parser.NewGitParser()
+		for _, file := range concern.Files {
+			change := byFile[file]
+			commit.Changes = append(commit.Changes, change)
+			gitParser.TotalAdded += change.Added
+			gitParser.TotalRemoved += change.Removed
+		}
+		message, err := heuristicSuggestion(ctx, cfg, hist, gitParser, commit.Changes)
+		if err != nil {
+			return nil, fmt.Errorf("error suggesting a message for the %s changes: %w", concern.Name, err)
+		}
+		commit.Message = f.FormatMessage(message, false)
+		plan = append(plan, commit)
+	}
+	return plan, nil
+}
+
+// printSplitPlan lists the commits of a split with their files
+func printSplitPlan(plan []splitCommit) {
+	ui.Heading("✂ Split plan:")
+	for i, commit := range plan {
+		ui.Printf("%d. %s\n", i+1, strings.SplitN(commit.Message, "\n", 2)[0])
+		for _, change := range commit.Changes {
+			ui.Printf("   %s\n", ui.MutedString("%s", change.File))
+		}
+	}
+	ui.Println()
+}
+
+// commitSplit commits the staged changes as planned, a concern at a time. The
+// staged changes are kept as a tree, so that changes staged in part stay so
+// and whatever is left uncommitted after a failure is staged again.
+func commitSplit(ctx context.Context, hooks *hookRunner, plan []splitCommit) error {
+	tree, err := parser.IndexTree(ctx)
+	if err != nil {
+		return err
+	}
+	for i, commit := range plan {
+		var paths []string
+		for _, change := range commit.Changes {
+			paths = append(paths, change.File)
+			if change.Source != "" && change.Source != change.File {
+				paths = append(paths, change.Source)
+			}
+		}
+		err := parser.StageTreePaths(ctx, tree, paths)
+		if err == nil {
+			_, err = hooks.commit(ctx, commit.Message)
+		}
+		if err != nil {
+			if restoreErr := parser.RestoreIndex(ctx, tree); restoreErr != nil {
+				return restoreErr
+			}
+			return fmt.Errorf("error making commit %d of %d, the changes left are staged again: %w", i+1, len(plan), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/changelog"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/gitcmd"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	squashBaseFlag  string
+	squashLastFlag  int
+	squashApplyFlag bool
+	squashHookFlag  bool
+
+	squashCmd = &cobra.Command{
+		Use:   "squash [range]",
+		Short: "Synthesize one commit message for several commits",
+		Long: `Summarize a run of commits and their combined diff into one conventional
+commit message, instead of the concatenated list git offers when squashing.
+
+The most significant commit (breaking changes, then features, then fixes, ...)
+becomes the subject, the other commits become bullet points, and breaking
+changes and trailers such as "Refs:" are kept as footers. Fixups, "wip" and
+review touch-ups are left out. When no commit uses a conventional type, the
+subject is suggested from the combined diff.
+
+The commits are given as a range (base..head), with --last N, or default to
+the commits of the current branch since its base. With --apply they are
+squashed into one commit carrying the message.
+
+With --hook gitmit runs as a prepare-commit-msg hook: for "git merge --squash"
+and merges it rewrites the message file git prepared, and leaves other
+commits alone. Add it to .git/hooks/prepare-commit-msg:
+
+  gitmit squash --hook "$@"`,
+		Example: `  gitmit squash
+  gitmit squash --last 3 --apply
+  gitmit squash main..feature/export`,
+		Args:         cobra.MaximumNArgs(3),
+		SilenceUsage: true,
+		RunE:         runSquash,
+	}
+)
+
+// squashCommitRegex matches the header of a commit in the message git prepares
+// for "git merge --squash"
+var squashCommitRegex = regexp.MustCompile(`(?m)^commit ([0-9a-f]{7,})$`)
+
+func init() {
+	rootCmd.AddCommand(squashCmd)
+	squashCmd.Flags().StringVar(&squashBaseFlag, "base", "", "Squash the commits since this branch or commit (default: origin's default branch, main or master)")
+	squashCmd.Flags().IntVar(&squashLastFlag, "last", 0, "Squash the last N commits")
+	squashCmd.Flags().BoolVar(&squashApplyFlag, "apply", false, "Squash the commits into one commit with the message")
+	squashCmd.Flags().BoolVar(&squashHookFlag, "hook", false, "Run as a prepare-commit-msg hook: gitmit squash --hook <file> [source] [commit]")
+}
+
+func runSquash(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if squashHookFlag {
+		return runSquashHook(ctx, cfg, args)
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("expected at most one range, got %d arguments", len(args))
+	}
+
+	base, head, err := resolveRange(ctx, args, squashBaseFlag, squashLastFlag)
+	if err != nil {
+		return err
+	}
+	commits, err := parser.ParseCommits(ctx, base, head)
+	if err != nil {
+		return err
+	}
+	if len(commits) < 2 {
+		return fmt.Errorf("need at least two commits to squash, found %d", len(commits))
+	}
+
+	gitParser := parser.NewGitParser()
+	changes, err := gitParser.ParseRangeChanges(ctx, base, head)
+	if err != nil {
+		return err
+	}
+	message, err := squashMessage(ctx, cfg, gitParser, commits, changes)
+	if err != nil {
+		return err
+	}
+
+	ui.Heading("📝 Squashed message for %d commits:\n", len(commits))
+	fmt.Println(message)
+
+	if !squashApplyFlag {
+		return nil
+	}
+	return applySquash(ctx, cfg, base, head, message)
+}
+
+// squashMessage synthesizes the message for commits with the combined changes,
+// falling back to a suggestion for the changes when no commit is conventional
+func squashMessage(ctx context.Context, cfg *config.Config, gitParser *parser.GitParser, commits []*parser.Commit, changes []*parser.Change) (string, error) {
+	hist, err := history.LoadHistory(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	entries := changelog.Parse(commits)
+	fallback := commits[len(commits)-1].Subject
+	if changelog.MostSignificant(entries) == nil {
+		suggestion, err := heuristicSuggestion(ctx, cfg, hist, gitParser, changes)
+		if err != nil {
+			return "", err
+		}
+		if subject, _, _ := strings.Cut(suggestion, "\n"); subject != "" {
+			fallback = subject
+		}
+	}
+
+	f, _ := newFormatters(ctx, cfg, "", "", learnRepoStyle(cfg, hist))
+	useCommitTemplate(ctx, cfg, f)
+	return f.FormatMessage(changelog.Squash(entries, fallback), false), nil
+}
+
+// applySquash replaces the commits after base with one commit carrying message
+func applySquash(ctx context.Context, cfg *config.Config, base, head, message string) error {
+	current, err := parser.ResolveRevision(ctx, "HEAD")
+	if err != nil {
+		return err
+	}
+	if tip, err := parser.ResolveRevision(ctx, head); err != nil || tip != current {
+		return fmt.Errorf("--apply only squashes commits up to HEAD")
+	}
+	// git diff --quiet exits with 1 when there are differences
+	if err := gitcmd.Command(ctx, "diff", "--cached", "--quiet").Run(); gitcmd.ExitCode(err) == 1 {
+		return fmt.Errorf("the index has staged changes; commit or unstage them before squashing")
+	} else if err != nil {
+		return fmt.Errorf("error checking the index: %w", err)
+	}
+
+	fmt.Print("Squash these commits into one? (y/N): ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+		ui.Warn("Squash cancelled.")
+		return nil
+	}
+
+	if _, err := gitcmd.Output(ctx, "", "reset", "--soft", base); err != nil {
+		return fmt.Errorf("error resetting to %s: %w", base, err)
+	}
+	branchName, _ := parser.NewGitParser().GetCurrentBranch(ctx)
+	if _, err := newHookRunner(cfg, branchName).commit(ctx, message); err != nil {
+		return fmt.Errorf("error committing squashed changes (restore with git reset --soft %s): %w", current[:7], err)
+	}
+	ui.Success("✅ Commits squashed. The previous tip was %s.", current[:7])
+	return nil
+}
+
+// runSquashHook rewrites the message git prepared for a squash or merge commit.
+// Other commits are left alone so the hook can be installed unconditionally.
+func runSquashHook(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("--hook needs the message file git passes to prepare-commit-msg")
+	}
+	source := ""
+	if len(args) > 1 {
+		source = args[1]
+	}
+
+	content, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("error reading message file: %w", err)
+	}
+
+	gitParser := parser.NewGitParser()
+	var commits []*parser.Commit
+	var changes []*parser.Change
+	switch source {
+	case "squash":
+		// git merge --squash lists the squashed commits newest first
+		var revs []string
+		for _, m := range squashCommitRegex.FindAllStringSubmatch(string(content), -1) {
+			revs = append([]string{m[1]}, revs...)
+		}
+		if commits, err = parser.ParseCommitList(ctx, revs); err != nil {
+			return err
+		}
+		if changes, err = gitParser.ParseStagedChanges(ctx); err != nil {
+			return err
+		}
+	case "merge":
+		if _, err := parser.ResolveRevision(ctx, "MERGE_HEAD"); err != nil {
+			return nil
+		}
+		if commits, err = parser.ParseCommits(ctx, "HEAD", "MERGE_HEAD"); err != nil {
+			return err
+		}
+		if changes, err = gitParser.ParseRangeChanges(ctx, "HEAD", "MERGE_HEAD"); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+	if len(commits) == 0 {
+		return nil
+	}
+
+	message, err := squashMessage(ctx, cfg, gitParser, commits, changes)
+	if err != nil {
+		return err
+	}
+
+	// Keep git's comment lines, which explain how the message is used; they
+	// start with core.commentChar, and other lines are content
+	commentChar := parser.CommentChar(ctx)
+	var comments []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, commentChar) {
+			comments = append(comments, line)
+		}
+	}
+	if len(comments) > 0 {
+		message += "\n\n" + strings.Join(comments, "\n")
+	}
+	if err := os.WriteFile(args[0], []byte(message+"\n"), 0644); err != nil {
+		return fmt.Errorf("error writing message file: %w", err)
+	}
+	return nil
+}
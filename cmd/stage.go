@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	addFlag       bool
+	addAllFlag    bool
+	stdinFlag     bool
+	patchFileFlag string
+)
+
+// maxListedUnstaged caps the files listed when offering to stage them
+const maxListedUnstaged = 10
+
+// stagedChanges returns the staged changes. With --add or --add-all the working
+// tree changes are staged first; otherwise, when nothing is staged and the run
+// is interactive, it offers to stage them.
+func stagedChanges(ctx context.Context, gitParser *parser.GitParser, interactive bool) ([]*parser.Change, error) {
+	if addFlag || addAllFlag {
+		if err := parser.StageFiles(ctx, addAllFlag); err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := gitParser.ParseStagedChanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 && interactive {
+		staged, err := offerStaging(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if staged {
+			if changes, err = gitParser.ParseStagedChanges(ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(changes) == 0 {
+		return nil, parser.ErrNoStagedChanges
+	}
+	return changes, nil
+}
+
+// readingPatch reports whether the changes come from a patch instead of the index
+func readingPatch() bool {
+	return stdinFlag || patchFileFlag != ""
+}
+
+// patchChanges returns the changes of the unified diff read with --stdin or
+// --patch-file, where "-" is stdin as well
+func patchChanges(gitParser *parser.GitParser) ([]*parser.Change, error) {
+	var r io.Reader = os.Stdin
+	if patchFileFlag != "" && patchFileFlag != "-" {
+		file, err := os.Open(patchFileFlag)
+		if err != nil {
+			return nil, fmt.Errorf("error opening patch: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+	return gitParser.ParsePatch(r)
+}
+
+// offerStaging lists the unstaged and untracked files and asks whether to stage
+// them, reporting whether anything was staged
+func offerStaging(ctx context.Context) (bool, error) {
+	modified, untracked, err := parser.UnstagedFiles(ctx)
+	if err != nil {
+		return false, err
+	}
+	if len(modified) == 0 && len(untracked) == 0 {
+		return false, nil
+	}
+
+	ui.Warn("Nothing is staged.")
+	listUnstaged("Modified", modified)
+	listUnstaged("Untracked", untracked)
+
+	options := "[m]odified files"
+	if len(modified) == 0 {
+		options = "[a]ll files"
+	} else if len(untracked) > 0 {
+		options += ", [a]ll files"
+	}
+	ui.Printf("\nStage %s, or [N]othing? ", options)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	ui.Println()
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "m":
+		if len(modified) == 0 {
+			return false, nil
+		}
+		return true, parser.StageFiles(ctx, false)
+	case "a":
+		return true, parser.StageFiles(ctx, true)
+	}
+	return false, nil
+}
+
+// listUnstaged prints a heading and the first files of a list
+func listUnstaged(heading string, files []string) {
+	if len(files) == 0 {
+		return
+	}
+	ui.Printf("%s:\n", heading)
+	for i, file := range files {
+		if i == maxListedUnstaged {
+			ui.Printf("  %s\n", ui.MutedString("… and %d more", len(files)-maxListedUnstaged))
+			break
+		}
+		ui.Printf("  %s\n", file)
+	}
+}
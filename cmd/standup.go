@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/ai"
+	"github.com/andev0x/gitmit/internal/changelog"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/standup"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	standupSinceFlag  string
+	standupAuthorFlag string
+	standupReposFlag  []string
+	standupFormatFlag string
+	standupGroupFlag  string
+	standupAIFlag     bool
+
+	standupCmd = &cobra.Command{
+		Use:   "standup",
+		Short: "Summarize your recent commits for a standup",
+		Long: `Collect your commits since a given time on every local branch, in this
+repository or several, group them by area or by type, and print a short
+summary for a standup as text or markdown.
+
+The time is anything git understands, such as "yesterday", "last monday" or
+"2 days ago". It defaults to yesterday, or to Friday on Mondays. Commits are
+matched by your git user.email unless --author is given. Fixups and other
+touch-up commits are left out.
+
+With --ai the summary is written by the configured Ollama model.`,
+		Example: `  gitmit standup
+  gitmit standup --since "last monday" --format markdown
+  gitmit standup --repo ~/src/api --repo ~/src/web --group type`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runStandup,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(standupCmd)
+	standupCmd.Flags().StringVar(&standupSinceFlag, "since", "", `Include commits since this time, e.g. "yesterday" or "last monday" (default: yesterday, Friday on Mondays)`)
+	standupCmd.Flags().StringVar(&standupAuthorFlag, "author", "", "Author to include, matched against name and email (default: git user.email)")
+	standupCmd.Flags().StringSliceVar(&standupReposFlag, "repo", []string{"."}, "Repository to include; repeat for several")
+	standupCmd.Flags().StringVar(&standupFormatFlag, "format", "text", "Output format: text or markdown")
+	standupCmd.Flags().StringVar(&standupGroupFlag, "group", "area", "Group changes by area or type")
+	standupCmd.Flags().BoolVar(&standupAIFlag, "ai", false, "Write the summary with the configured Ollama model")
+}
+
+func runStandup(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if standupFormatFlag != "text" && standupFormatFlag != "markdown" {
+		return fmt.Errorf("unknown format %q (expected text or markdown)", standupFormatFlag)
+	}
+	if standupGroupFlag != "area" && standupGroupFlag != "type" {
+		return fmt.Errorf("unknown grouping %q (expected area or type)", standupGroupFlag)
+	}
+
+	since := standupSinceFlag
+	if since == "" {
+		since = "yesterday"
+		if time.Now().Weekday() == time.Monday {
+			since = "last friday"
+		}
+	}
+
+	var repos []*standup.Repo
+	total := 0
+	for _, dir := range standupReposFlag {
+		root, err := parser.RepoRoot(ctx, dir)
+		if err != nil {
+			return err
+		}
+		author := standupAuthorFlag
+		if author == "" {
+			if author, err = parser.UserEmail(ctx, root); err != nil {
+				return fmt.Errorf("%s: %w; pass --author", root, err)
+			}
+		}
+		commits, err := parser.ParseAuthorCommits(ctx, root, since, author)
+		if err != nil {
+			return fmt.Errorf("%s: %w", root, err)
+		}
+		total += len(commits)
+		repos = append(repos, &standup.Repo{Name: filepath.Base(root), Entries: changelog.Parse(commits)})
+	}
+
+	if total == 0 {
+		ui.Warn("No commits since %s.", since)
+		return nil
+	}
+
+	byType := standupGroupFlag == "type"
+	if standupAIFlag {
+		summary, err := aiStandup(ctx, since, repos)
+		if err == nil {
+			fmt.Println(summary)
+			return nil
+		}
+		ui.Warn("⚠ Could not summarize with the AI model (%v); using the built-in summary.", err)
+	}
+
+	if standupFormatFlag == "markdown" {
+		fmt.Printf("## Standup (since %s)\n\n", since)
+		fmt.Print(standup.Markdown(repos, byType))
+		return nil
+	}
+	ui.Heading("📝 Since %s:", since)
+	fmt.Println(standup.Text(repos, byType))
+	return nil
+}
+
+// aiStandup asks the configured Ollama model for a summary of the work
+func aiStandup(ctx context.Context, since string, repos []*standup.Repo) (string, error) {
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	prompt, err := ai.RenderStandupPrompt("since "+since, standup.Log(repos))
+	if err != nil {
+		return "", err
+	}
+	response, err := ai.NewOllamaClient(cfg.Ollama).Generate(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+	if response = strings.TrimSpace(response); response == "" {
+		return "", fmt.Errorf("empty response")
+	}
+	return response, nil
+}
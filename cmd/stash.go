@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/style"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	stashUntrackedFlag bool
+	stashDryRunFlag    bool
+	stashAllFlag       bool
+
+	stashCmd = &cobra.Command{
+		Use:   "stash",
+		Short: "Stash the working tree with a message describing the changes",
+		Long: `Analyze the staged and unstaged changes, the way propose analyzes staged ones,
+and stash them with the suggested subject as message instead of git's
+"WIP on main: ...", so the stash list says what each stash holds.
+
+Use "gitmit stash label" to describe stashes made with plain git stash.`,
+		Example: `  gitmit stash
+  gitmit stash --include-untracked
+  gitmit stash label --dry-run`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runStash,
+	}
+
+	stashLabelCmd = &cobra.Command{
+		Use:   "label",
+		Short: "Replace the WIP messages of existing stashes with descriptive ones",
+		Long: `Analyze the content of each stash still named "WIP on <branch>: ..." and
+relabel it "On <branch>: <suggested subject>". With --all every stash is
+relabeled.
+
+git cannot rename a stash in place, so the stashes are dropped and stored
+again in the same order; their content and hashes do not change.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runStashLabel,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(stashCmd)
+	stashCmd.AddCommand(stashLabelCmd)
+	stashCmd.Flags().BoolVarP(&stashUntrackedFlag, "include-untracked", "u", false, "Also stash untracked files")
+	stashCmd.PersistentFlags().BoolVar(&stashDryRunFlag, "dry-run", false, "Show the messages without stashing or relabeling")
+	stashLabelCmd.Flags().BoolVar(&stashAllFlag, "all", false, "Relabel every stash, not only those with git's WIP message")
+}
+
+func runStash(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	hist, err := history.LoadHistory(ctx)
+	if err != nil {
+		return err
+	}
+
+	gitParser := parser.NewGitParser()
+	changes, err := gitParser.ParseWorkingTreeChanges(ctx, stashUntrackedFlag)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return fmt.Errorf("⚠️ no local changes to stash")
+	}
+	branchName, _ := gitParser.GetCurrentBranch(ctx)
+	message, err := stashMessage(ctx, cfg, hist, gitParser, changes, branchName)
+	if err != nil {
+		return err
+	}
+	if message == "" {
+		return fmt.Errorf("could not describe the changes; use git stash push --message instead")
+	}
+
+	if stashDryRunFlag {
+		fmt.Println(message)
+		return nil
+	}
+	if err := parser.PushStash(ctx, message, stashUntrackedFlag); err != nil {
+		return err
+	}
+	ui.Success("✅ Stashed %d file(s) as %q", len(changes), message)
+	return nil
+}
+
+func runStashLabel(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	hist, err := history.LoadHistory(ctx)
+	if err != nil {
+		return err
+	}
+
+	stashes, err := parser.ListStashes(ctx)
+	if err != nil {
+		return err
+	}
+	messages := make(map[string]string)
+	for _, stash := range stashes {
+		if !stash.IsWIP() && !stashAllFlag {
+			continue
+		}
+		gitParser := parser.NewGitParser()
+		changes, err := gitParser.ParseStashChanges(ctx, stash.Hash)
+		if err != nil {
+			return err
+		}
+		// The branch a stash was made on is its context, not the current one
+		branchName := stash.StashBranch()
+		message, err := stashMessage(ctx, cfg, hist, gitParser, changes, branchName)
+		if err != nil {
+			return err
+		}
+		if message == "" {
+			ui.Warn("⚠ %s: could not describe its changes, left as is", stash.Ref)
+			continue
+		}
+		label := fmt.Sprintf("On %s: %s", branchName, message)
+		if label == stash.Message {
+			continue
+		}
+		messages[stash.Hash] = label
+		fmt.Printf("%s %s\n", stash.Ref, ui.MutedString("%s", stash.Message))
+		fmt.Printf("  %s %s\n", ui.MutedString("→"), label)
+	}
+
+	if len(messages) == 0 {
+		fmt.Println("No stashes to relabel.")
+		return nil
+	}
+	if stashDryRunFlag {
+		return nil
+	}
+	if err := parser.RelabelStashes(ctx, stashes, messages); err != nil {
+		return err
+	}
+	ui.Success("✅ Relabeled %d stash(es).", len(messages))
+	return nil
+}
+
+// stashMessage returns the formatted subject suggested for stashed changes,
+// or "" when there is none
+func stashMessage(ctx context.Context, cfg *config.Config, hist *history.CommitHistory, gitParser *parser.GitParser, changes []*parser.Change, branchName string) (string, error) {
+	cfg, commitMessage, err := analyzeChanges(ctx, cfg, gitParser, changes, branchName)
+	if err != nil {
+		return "", err
+	}
+	suggestion, err := templateMessage(ctx, cfg, hist, commitMessage, branchName)
+	if err != nil || suggestion == "" {
+		return "", err
+	}
+	f, _ := newFormatters(ctx, cfg, "", "", learnRepoStyle(cfg, hist))
+	return style.Subject(f.FormatMessage(suggestion, commitMessage.IsMajor)), nil
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	statsAllFlag  bool
+	statsJSONFlag bool
+	statsTopFlag  int
+
+	statsCmd = &cobra.Command{
+		Use:   "stats",
+		Short: "Show how often suggestions are accepted, edited or rejected",
+		Long: `Report what was done with gitmit's suggestions, from the outcomes recorded in
+the suggestion history: committed unmodified, edited before committing,
+rejected, or regenerated for another suggestion.
+
+Outcomes are broken down by source (templates or the AI model), by template
+group (the conventional type a template produces), by template and by smart
+rule, so users and template authors can see which suggestions work. The
+acceptance is the share committed unmodified.`,
+		Example: `  gitmit stats
+  gitmit stats --all --top 20
+  gitmit stats --json`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runStats,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().BoolVar(&statsAllFlag, "all", false, "Add up the outcomes of every repository")
+	statsCmd.Flags().BoolVar(&statsJSONFlag, "json", false, "Print the statistics as JSON")
+	statsCmd.Flags().IntVar(&statsTopFlag, "top", 10, "Number of templates listed (0 for all)")
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	report, err := history.LoadReport(cmd.Context(), statsAllFlag)
+	if err != nil {
+		return err
+	}
+	if statsTopFlag > 0 && len(report.Templates) > statsTopFlag {
+		report.Templates = report.Templates[:statsTopFlag]
+	}
+
+	if statsJSONFlag {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error encoding statistics: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if report.Empty() {
+		ui.Warn("No suggestion outcomes recorded yet.")
+		return nil
+	}
+	if statsAllFlag && report.Repositories > 1 {
+		ui.Heading("📈 Suggestion outcomes of %d repositories", report.Repositories)
+	} else {
+		ui.Heading("📈 Suggestion outcomes")
+	}
+	printOutcomes("By source:", report.Sources)
+	printOutcomes("By template group:", report.Groups)
+	printOutcomes("Templates:", report.Templates)
+	printOutcomes("Smart rules:", report.Rules)
+	return nil
+}
+
+// printOutcomes shows a section of outcome counts, ending each row with a bar
+// of the acceptance
+func printOutcomes(title string, rows []history.OutcomeRow) {
+	if len(rows) == 0 {
+		return
+	}
+	ui.Accent("\n%s", title)
+	ui.Println(ui.MutedString("  %5s %5s %5s %5s %5s  %10s  %s", "total", "acc", "edit", "rej", "regen", "acceptance", "name"))
+	for _, row := range rows {
+		line := fmt.Sprintf("  %5d %5d %5d %5d %5d  %9.0f%%  %s %s", row.Total, row.Accepted, row.Edited, row.Rejected, row.Regenerated,
+			row.Acceptance*100, row.Name, bar(row.Accepted, row.Total, 10))
+		ui.Println(strings.TrimRight(line, " "))
+	}
+}
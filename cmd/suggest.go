@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/templater"
+)
+
+// heuristicSuggestion analyzes changes parsed by gitParser and returns the best
+// template message for them, unformatted, or "" when they cannot be analyzed
+func heuristicSuggestion(ctx context.Context, cfg *config.Config, hist *history.CommitHistory, gitParser *parser.GitParser, changes []*parser.Change) (string, error) {
+	branchName, _ := gitParser.GetCurrentBranch(ctx)
+	cfg, commitMessage, err := analyzeChanges(ctx, cfg, gitParser, changes, branchName)
+	if err != nil || commitMessage == nil {
+		return "", err
+	}
+	return templateMessage(ctx, cfg, hist, commitMessage, branchName)
+}
+
+// analyzeChanges runs the analyzer on changes parsed by gitParser, with the
+// configuration that applies to the changed files and the hints and scopes of
+// plugins. The commit message is nil when there are no changes.
+func analyzeChanges(ctx context.Context, cfg *config.Config, gitParser *parser.GitParser, changes []*parser.Change, branchName string) (*config.Config, *analyzer.CommitMessage, error) {
+	if len(changes) == 0 {
+		return cfg, nil, nil
+	}
+
+	var files []string
+	for _, change := range changes {
+		files = append(files, change.File)
+	}
+	cfg, _, err := cfg.ForFiles(files)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg, hints := runAnalyzePlugins(ctx, cfg, changes, branchName)
+	commitMessage := analyzer.NewAnalyzer(changes, cfg).AnalyzeChanges(ctx, gitParser.TotalAdded, gitParser.TotalRemoved, branchName)
+	if commitMessage != nil {
+		addPluginHints(commitMessage, hints)
+	}
+	return cfg, commitMessage, nil
+}
+
+// templateMessage returns the best template message for an analysis, unformatted
+func templateMessage(ctx context.Context, cfg *config.Config, hist *history.CommitHistory, commitMessage *analyzer.CommitMessage, branchName string) (string, error) {
+	if commitMessage == nil {
+		return "", nil
+	}
+	t, err := templater.NewTemplater(selectTemplateFile(cfg, cfg.BranchPolicy(branchName), ""), hist)
+	if err != nil {
+		return "", err
+	}
+	if err := t.AddTemplates(cfg.Templates); err != nil {
+		return "", err
+	}
+	t.RestrictTypes(cfg.Types)
+	f, _ := newFormatters(ctx, cfg, "", "", learnRepoStyle(cfg, hist))
+	// Repeats of recent commits are told apart as the messages would be committed
+	t.FormatWith(func(message string) string { return f.FormatMessage(message, commitMessage.IsMajor) })
+	return t.GetMessage(commitMessage)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/changelog"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+// tagFlag is the annotated tag created after committing
+var tagFlag string
+
+// tagAfterCommit creates the annotated tag asked for with --tag on the new
+// commit. Without it, an interactive run offers to tag a commit whose message
+// bumps the version; reader is nil otherwise.
+func tagAfterCommit(ctx context.Context, reader *bufio.Reader, message string) error {
+	previous := parser.LatestTag(ctx, "HEAD")
+	name := tagFlag
+	if name == "" && reader != nil {
+		subject := strings.SplitN(message, "\n", 2)[0]
+		version := changelog.VersionBump(subject, previous)
+		if version == "" || parser.CheckNewTag(ctx, version) != nil {
+			return nil
+		}
+		fmt.Printf("This looks like a release commit. Create annotated tag %s? [y/N]: ", version)
+		answer, _ := reader.ReadString('\n')
+		fmt.Println()
+		if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+			return nil
+		}
+		name = version
+	}
+	if name == "" {
+		return nil
+	}
+
+	commits, err := parser.ParseCommits(ctx, previous, "HEAD")
+	if err != nil {
+		return err
+	}
+	if err := parser.CreateTag(ctx, name, changelog.TagMessage(name, previous, changelog.Parse(commits))); err != nil {
+		return err
+	}
+	since := "in the history"
+	if previous != "" {
+		since = "since " + previous
+	}
+	ui.Success("🏷  Tagged %s with %d commit(s) %s.", name, len(commits), since)
+	return nil
+}
+
+// releaseBody returns the body of a release commit: the commits since the
+// latest tag, by type. The version of the analysis loses its v prefix when the
+// latest tag has none, so the tag offered after committing matches the others.
+// It returns "" for changes that do not bump the version.
+func releaseBody(ctx context.Context, commitMessage *analyzer.CommitMessage) string {
+	if commitMessage == nil || commitMessage.Version == "" {
+		return ""
+	}
+	previous := parser.LatestTag(ctx, "HEAD")
+	if previous != "" && !strings.HasPrefix(previous, "v") {
+		commitMessage.Version = strings.TrimPrefix(commitMessage.Version, "v")
+		commitMessage.Item = commitMessage.Version
+		commitMessage.Purpose = "release " + commitMessage.Version
+	}
+
+	commits, err := parser.ParseCommits(ctx, previous, "HEAD")
+	if err != nil || len(commits) == 0 {
+		slog.Info("release commit without a body", "since", previous, "error", err)
+		return ""
+	}
+	return changelog.ReleaseBody(previous, changelog.Parse(commits))
+}
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/templater"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	templatesFileFlag    string
+	templatesNameFlag    string
+	templatesVersionFlag string
+
+	templatesCmd = &cobra.Command{
+		Use:   "templates",
+		Short: "Inspect, validate and preview commit message templates",
+		Long: `Inspect the template pack gitmit uses for suggestions.
+
+The pack is chosen the same way as for "propose": --template-file, then the
+current branch policy, then templateFile from the config, then templates.json.
+Names are looked up in the current directory, the user template directory
+($XDG_CONFIG_HOME/gitmit/templates), the executable's directory and finally
+the embedded templates.`,
+		Example: `  gitmit templates list
+  gitmit templates show A api
+  gitmit templates validate ./my-templates.json
+  gitmit templates test --template-file ./my-templates.json
+  gitmit templates install acme/gitmit-templates@v1.2.0
+  gitmit templates update`,
+	}
+
+	templatesListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the actions and topics defined by the template pack",
+		Args:  cobra.NoArgs,
+		RunE:  runTemplatesList,
+	}
+
+	templatesShowCmd = &cobra.Command{
+		Use:   "show <action> [topic]",
+		Short: "Print the templates for an action, or for one of its topics",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE:  runTemplatesShow,
+	}
+
+	templatesValidateCmd = &cobra.Command{
+		Use:          "validate [file]",
+		Short:        "Check a template file for missing actions and bad placeholders",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE:         runTemplatesValidate,
+	}
+
+	templatesInstallCmd = &cobra.Command{
+		Use:   "install <url|org/repo>",
+		Short: "Download a template pack into the user template directory",
+		Long: `Download a template pack, validate it and save it as <name>.json in the user
+template directory, where --template-file <name>.json or the templateFile config
+key selects it.
+
+The source is a URL to a JSON file, a git remote, a local git repository or a
+GitHub org/repo. Git sources must contain templates.json at their root; pin a
+tag, branch or commit with --version or org/repo@version.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runTemplatesInstall,
+	}
+
+	templatesUpdateCmd = &cobra.Command{
+		Use:          "update [name...]",
+		Short:        "Re-download installed template packs at their pinned versions",
+		SilenceUsage: true,
+		RunE:         runTemplatesUpdate,
+	}
+
+	templatesTestCmd = &cobra.Command{
+		Use:   "test",
+		Short: "Preview how the templates resolve for the staged changes",
+		Long: `Analyze the staged changes and show every candidate template for them, with
+placeholders resolved and ranked by score, without committing anything.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runTemplatesTest,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(templatesCmd)
+	templatesCmd.AddCommand(templatesListCmd, templatesShowCmd, templatesValidateCmd, templatesTestCmd, templatesInstallCmd, templatesUpdateCmd)
+	templatesCmd.PersistentFlags().StringVar(&templatesFileFlag, "template-file", "", "Template file to use instead of templates.json")
+	templatesInstallCmd.Flags().StringVar(&templatesNameFlag, "name", "", "Name to install the pack as (default: derived from the source)")
+	templatesInstallCmd.Flags().StringVar(&templatesVersionFlag, "version", "", "Tag, branch or commit to pin a git source to")
+}
+
+// loadTemplatePack loads the template pack selected for the current branch, with
+// the custom templates from the config applied
+func loadTemplatePack(ctx context.Context) (templater.Templates, string, error) {
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	branchName, _ := parser.NewGitParser().GetCurrentBranch(ctx)
+	templates, source, err := templater.LoadTemplates(selectTemplateFile(cfg, cfg.BranchPolicy(branchName), templatesFileFlag))
+	if err != nil || len(cfg.Templates) == 0 {
+		return templates, source, err
+	}
+
+	templates = templates.Merge(cfg.Templates)
+	if err := templates.Validate(); err != nil {
+		return nil, source, fmt.Errorf("error applying custom templates from config: %w", err)
+	}
+	return templates, source + " + config templates", nil
+}
+
+func runTemplatesList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	templates, source, err := loadTemplatePack(ctx)
+	if err != nil {
+		return err
+	}
+
+	ui.Heading("Templates from %s", source)
+	for _, action := range slices.Sorted(maps.Keys(templates)) {
+		topics := templates[action]
+		ui.Accent("\n%s (%d topics)", action, len(topics))
+		for _, topic := range slices.Sorted(maps.Keys(topics)) {
+			fmt.Printf("  %-20s %d\n", topic, len(topics[topic]))
+		}
+	}
+
+	packs, err := templater.LoadPacks()
+	if err != nil {
+		return err
+	}
+	if len(packs) > 0 {
+		ui.Heading("\nInstalled packs:")
+		for _, name := range slices.Sorted(maps.Keys(packs)) {
+			printPack(packs[name])
+		}
+	}
+	return nil
+}
+
+func runTemplatesShow(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	templates, _, err := loadTemplatePack(ctx)
+	if err != nil {
+		return err
+	}
+
+	topics, ok := templates[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown action %q (expected one of %v)", args[0], slices.Sorted(maps.Keys(templates)))
+	}
+
+	names := slices.Sorted(maps.Keys(topics))
+	if len(args) == 2 {
+		if _, ok := topics[args[1]]; !ok {
+			return fmt.Errorf("action %q has no topic %q", args[0], args[1])
+		}
+		names = []string{args[1]}
+	}
+	for _, topic := range names {
+		ui.Accent("%s/%s", args[0], topic)
+		for _, tmpl := range topics[topic] {
+			fmt.Printf("  %s\n", tmpl)
+		}
+	}
+	return nil
+}
+
+func runTemplatesValidate(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	var (
+		source string
+		err    error
+	)
+	if len(args) == 1 {
+		_, source, err = templater.LoadTemplates(args[0])
+	} else {
+		_, source, err = loadTemplatePack(ctx)
+	}
+	if err != nil {
+		ui.Error("❌ %v", err)
+		return fmt.Errorf("templates are invalid")
+	}
+
+	ui.Success("✅ Templates in %s are valid.", source)
+	return nil
+}
+
+func runTemplatesInstall(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	pack, err := templater.InstallPack(ctx, args[0], templatesNameFlag, templatesVersionFlag)
+	if err != nil {
+		return err
+	}
+
+	ui.Success("✅ Installed template pack %s", pack.Name)
+	printPack(pack)
+	fmt.Printf("Use it with --template-file %s or: gitmit config set templateFile %s\n", pack.File, pack.File)
+	return nil
+}
+
+func runTemplatesUpdate(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	packs, err := templater.UpdatePacks(ctx, args)
+	for _, pack := range packs {
+		ui.Success("✅ Updated template pack %s", pack.Name)
+		printPack(pack)
+	}
+	if err != nil {
+		return err
+	}
+	if len(packs) == 0 {
+		ui.Heading("No template packs installed.")
+	}
+	return nil
+}
+
+// printPack prints an installed pack with its source and pinned version
+func printPack(pack *templater.Pack) {
+	version := pack.Version
+	if version == "" {
+		version = "latest"
+	}
+	fmt.Printf("  %-20s %s@%s", pack.File, pack.Source, version)
+	if pack.Commit != "" {
+		fmt.Printf(" (%.12s)", pack.Commit)
+	}
+	fmt.Println()
+}
+
+func runTemplatesTest(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	gitParser := parser.NewGitParser()
+	changes, err := gitParser.ParseStagedChanges(ctx)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return parser.ErrNoStagedChanges
+	}
+
+	branchName, _ := gitParser.GetCurrentBranch(ctx)
+	cfg, commitMessage, err := analyzeChanges(ctx, cfg, gitParser, changes, branchName)
+	if err != nil {
+		return err
+	}
+	if commitMessage == nil {
+		return fmt.Errorf("could not analyze changes")
+	}
+
+	templateFile := selectTemplateFile(cfg, cfg.BranchPolicy(branchName), templatesFileFlag)
+	t, err := templater.NewTemplater(templateFile, &history.CommitHistory{})
+	if err != nil {
+		return err
+	}
+	if err := t.AddTemplates(cfg.Templates); err != nil {
+		return err
+	}
+	t.RestrictTypes(cfg.Types)
+
+	ui.Heading("📊 Placeholders:")
+	fmt.Printf("%-10s %s\n", "action", commitMessage.Action)
+	placeholders := templater.Placeholders(commitMessage)
+	for i := 0; i < len(placeholders); i += 2 {
+		fmt.Printf("%-10s %s\n", placeholders[i], placeholders[i+1])
+	}
+
+	actionKey, previews := t.Preview(commitMessage)
+	if len(previews) == 0 {
+		return fmt.Errorf("no templates found for action: %s", actionKey)
+	}
+
+	ui.Heading("\n📝 Candidates for %s/%s from %s:", actionKey, commitMessage.Topic, templateFile)
+	for i, preview := range previews {
+		fmt.Printf("%2d. [%5.2f] %s\n", i+1, preview.Score, preview.Message)
+		ui.Muted("             %s", preview.Template)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/ticket"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+// branchTicket returns the ticket named in the branch when a tracker is
+// configured, or nil. A ticket the tracker could not return is still returned
+// with its ID alone, so the message can reference it, along with the error.
+func branchTicket(ctx context.Context, cfg *config.Config, branchName string) (*ticket.Ticket, error) {
+	if !cfg.Tickets.Enabled() {
+		return nil, nil
+	}
+	id := cfg.Tickets.Ticket(branchName)
+	if id == "" {
+		return nil, nil
+	}
+	client, err := ticket.NewClient(cfg.Tickets)
+	if err != nil {
+		return &ticket.Ticket{ID: id}, err
+	}
+	t, err := client.Fetch(ctx, id)
+	if err != nil {
+		return &ticket.Ticket{ID: id}, err
+	}
+	return t, nil
+}
+
+// transitionTicket moves the ticket of the branch to the configured state
+// after committing. The commit is made by then, so failures are only reported.
+func transitionTicket(ctx context.Context, cfg *config.Config, t *ticket.Ticket) {
+	state := cfg.Tickets.TransitionTo
+	if t == nil || state == "" || strings.EqualFold(t.Status, state) {
+		return
+	}
+	client, err := ticket.NewClient(cfg.Tickets)
+	if err == nil {
+		err = client.Transition(ctx, t.ID, state)
+	}
+	if err != nil {
+		ui.Warn("⚠ Could not move %s to %s: %v", t.ID, state, err)
+		return
+	}
+	ui.Success("🎫 Moved %s to %s.", t.ID, state)
+}
+
+// referencedIssues returns the GitHub issues the branch name or the added lines
+// refer to, as gh reports them, with the footers linking them. Issues gh cannot
+// show, for example because it is not installed, are left out.
+func referencedIssues(ctx context.Context, cfg *config.Config, branchName string, changes []*parser.Change) ([]*ticket.Issue, []string) {
+	if !cfg.Tickets.GitHubIssues {
+		return nil, nil
+	}
+	var diffs []string
+	for _, change := range changes {
+		diffs = append(diffs, change.Diff)
+	}
+
+	var issues []*ticket.Issue
+	var footers []string
+	for _, ref := range ticket.FindIssueRefs(branchName, diffs) {
+		issue, err := ticket.FetchIssue(ctx, ref.Number)
+		if err != nil {
+			continue
+		}
+		issues = append(issues, issue)
+		footers = append(footers, issue.Footer(ref.Closes))
+	}
+	return issues, footers
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/ui"
+	"github.com/andev0x/gitmit/internal/update"
+)
+
+var (
+	updateCheckFlag bool
+	updateForceFlag bool
+
+	updateCmd = &cobra.Command{
+		Use:   "update",
+		Short: "Update gitmit to the latest release",
+		Long: `Download the latest release of gitmit from GitHub, verify it against the
+SHA-256 checksums published with the release and replace the running binary.
+
+Binaries installed by a package manager, such as Homebrew or Nix, are left for
+the package manager to update. Set updateCheck in the config to be told about
+new releases: gitmit then checks once a day in the background and prints a
+one-line notice.`,
+		Example: `  gitmit update
+  gitmit update --check`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runUpdate,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+	updateCmd.Flags().BoolVar(&updateCheckFlag, "check", false, "Only report whether a newer release exists")
+	updateCmd.Flags().BoolVar(&updateForceFlag, "force", false, "Install the latest release even if it is not newer, e.g. over a development build")
+}
+
+// managedPaths mark binaries installed by a package manager
+var managedPaths = []string{"/Cellar/", "/nix/store/", "/snap/"}
+
+func runUpdate(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	// The notice of the background check would repeat what update says
+	updateNotice = nil
+
+	release, err := update.Latest(ctx)
+	if err != nil {
+		return err
+	}
+	latest := release.Version()
+	if !update.Newer(latest, version) && !updateForceFlag {
+		ui.Success("✅ gitmit %s is up to date.", version)
+		return nil
+	}
+	if updateCheckFlag {
+		ui.Accent("gitmit %s is available (you have %s): %s", latest, version, release.URL)
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("error finding the gitmit binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	for _, managed := range managedPaths {
+		if strings.Contains(filepath.ToSlash(exe), managed) {
+			return fmt.Errorf("%s was installed by a package manager; update it with that package manager", exe)
+		}
+	}
+	// Windows leaves the binary replaced by the last update behind
+	os.Remove(exe + ".old")
+
+	ui.Printf("Updating gitmit %s → %s...\n", version, latest)
+	if err := update.Install(ctx, release, runtime.GOOS, runtime.GOARCH, exe); err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return fmt.Errorf("%w; rerun with permission to write %s, or install with go install github.com/andev0x/gitmit@latest", err, filepath.Dir(exe))
+		}
+		return err
+	}
+	ui.Success("✅ Updated %s to gitmit %s.", exe, latest)
+	return nil
+}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	watchDebounceFlag time.Duration
+	watchServeFlag    string
+	watchMaxFlag      int
+
+	watchCmd = &cobra.Command{
+		Use:   "watch",
+		Short: "Keep a suggestion for the staged changes up to date as they change",
+		Long: `Watch the git index and suggest a message again whenever the staged changes
+change, so the message is ready by the time you commit. Each new suggestion
+is printed as it is made.
+
+With --serve the latest suggestion is also served over HTTP, alongside the
+endpoints of gitmit serve:
+
+  GET /latest   the suggestion for the current staged changes, in the
+                format of POST /suggest
+
+Writes to the index are reported by the file system rather than polled for,
+so the watch costs nothing while the staged changes stay the same. The
+suggestion is made once the index has gone unwritten for --debounce.`,
+		Example: `  gitmit watch
+  gitmit watch --max 3
+  gitmit watch --serve 127.0.0.1:7823`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runWatch,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.Flags().DurationVar(&watchDebounceFlag, "debounce", 100*time.Millisecond, "How long the index must go unwritten before suggesting again")
+	watchCmd.Flags().StringVar(&watchServeFlag, "serve", "", "Also serve the latest suggestion over HTTP on this address")
+	watchCmd.Flags().IntVar(&watchMaxFlag, "max", 1, "Number of ranked suggestions to keep")
+}
+
+// suggestionWatch keeps the latest suggestion for the staged changes
+type suggestionWatch struct {
+	mu     sync.Mutex
+	latest *suggestResponse
+	err    error
+}
+
+func (w *suggestionWatch) set(latest *suggestResponse, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.latest, w.err = latest, err
+}
+
+// handleLatest answers GET /latest with the latest suggestion
+func (w *suggestionWatch) handleLatest(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(rw, http.StatusMethodNotAllowed, map[string]string{"error": "use GET"})
+		return
+	}
+	w.mu.Lock()
+	latest, err := w.latest, w.err
+	w.mu.Unlock()
+	switch {
+	case err != nil:
+		writeJSON(rw, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+	case latest == nil:
+		writeJSON(rw, http.StatusServiceUnavailable, map[string]string{"error": "no suggestion yet"})
+	default:
+		writeJSON(rw, http.StatusOK, latest)
+	}
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if watchDebounceFlag < 0 {
+		return fmt.Errorf("--debounce must not be negative")
+	}
+	home, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error reading the working directory: %w", err)
+	}
+	root, err := parser.RepoRoot(ctx, home)
+	if err != nil {
+		return err
+	}
+	index, err := parser.IndexFile(ctx, root)
+	if err != nil {
+		return err
+	}
+
+	// Ctrl+C cancels the context of the command, as a server error does
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+	interruptGrace = serveShutdownTimeout
+
+	s := newSuggestServer(root, watchServeFlag)
+	watch := &suggestionWatch{}
+	serveErr := make(chan error, 1)
+	if watchServeFlag != "" {
+		mux := s.routes()
+		mux.HandleFunc("/latest", watch.handleLatest)
+		httpServer := &http.Server{Addr: watchServeFlag, Handler: s.guard(mux)}
+		go func() {
+			if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serveErr <- fmt.Errorf("error serving on %s: %w", watchServeFlag, err)
+				stop()
+			}
+		}()
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+			defer cancel()
+			httpServer.Shutdown(shutdownCtx)
+		}()
+		ui.Success("👀 Watching %s, serving the latest suggestion on http://%s/latest (Ctrl+C to stop)", root, watchServeFlag)
+	} else {
+		ui.Success("👀 Watching %s (Ctrl+C to stop)", root)
+	}
+
+	// Git replaces the index through index.lock, so its directory is watched
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("error watching the index: %w", err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add(filepath.Dir(index)); err != nil {
+		return fmt.Errorf("error watching %s: %w", filepath.Dir(index), err)
+	}
+
+	var shown string // Key of the last suggestion printed
+	suggest := func() {
+		latest, err := s.suggest(ctx, serveRequest{Max: watchMaxFlag})
+		watch.set(latest, err)
+		// Ranked alternatives can trade places between runs, so only a new
+		// message or error is printed, unless the watch is stopping
+		if key := watchKey(latest, err); key != shown && ctx.Err() == nil {
+			shown = key
+			fmt.Printf("\n%s %s", ui.MutedString("%s", time.Now().Format("15:04:05")), watchText(latest, err))
+		}
+	}
+	suggest()
+
+	if err := watchIndex(ctx, watcher.Events, watcher.Errors, index, watchDebounceFlag, suggest); err != nil {
+		return err
+	}
+	fmt.Println()
+	select {
+	case err := <-serveErr:
+		return err
+	default:
+		return nil
+	}
+}
+
+// watchIndex calls changed each time writes to the index settle, until ctx is
+// done or the watcher stops. A git command can write the index several times
+// in a row, so changed waits until the index has gone unwritten for debounce.
+func watchIndex(ctx context.Context, events <-chan fsnotify.Event, errs <-chan error, index string, debounce time.Duration, changed func()) error {
+	var settled <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if isIndexWrite(event, index) {
+				settled = time.After(debounce)
+			}
+		case err, ok := <-errs:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("error watching the index: %w", err)
+		case <-settled:
+			settled = nil
+			changed()
+		}
+	}
+}
+
+// isIndexWrite reports whether a file system event in the index's directory
+// changed the index. Git writes index.lock and renames it over the index, so
+// the events of the lock file and of permission changes are skipped.
+func isIndexWrite(event fsnotify.Event, index string) bool {
+	return filepath.Base(event.Name) == filepath.Base(index) && !event.Has(fsnotify.Chmod)
+}
+
+// watchKey identifies the suggestion or error printed for the staged changes
+func watchKey(latest *suggestResponse, err error) string {
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return fmt.Sprintf("%d %s", latest.Files, latest.Message)
+}
+
+// watchText describes a suggestion for the terminal
+func watchText(latest *suggestResponse, err error) string {
+	if err != nil {
+		return ui.ErrorString("❌ %v", err) + "\n"
+	}
+	if latest.Files == 0 {
+		return ui.MutedString("Nothing is staged.") + "\n"
+	}
+	files := "files"
+	if latest.Files == 1 {
+		files = "file"
+	}
+	text := fmt.Sprintf("%d %s staged on %s:\n  %s\n", latest.Files, files, latest.Branch, ui.AccentString("%s", latest.Message))
+	for _, suggestion := range latest.Suggestions {
+		if suggestion.Message == latest.Message {
+			continue
+		}
+		text += fmt.Sprintf("  %s %s\n", suggestion.Message, ui.MutedString("(%s)", suggestion.Reason))
+	}
+	return text
+}
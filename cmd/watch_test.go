@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestIsIndexWrite(t *testing.T) {
+	index := filepath.Join("repo", ".git", "index")
+	tests := []struct {
+		name  string
+		event fsnotify.Event
+		want  bool
+	}{
+		{name: "rename over the index", event: fsnotify.Event{Name: index, Op: fsnotify.Create}, want: true},
+		{name: "write", event: fsnotify.Event{Name: index, Op: fsnotify.Write}, want: true},
+		{name: "remove", event: fsnotify.Event{Name: index, Op: fsnotify.Remove}, want: true},
+		{name: "chmod", event: fsnotify.Event{Name: index, Op: fsnotify.Chmod}, want: false},
+		{name: "lock file", event: fsnotify.Event{Name: index + ".lock", Op: fsnotify.Create}, want: false},
+		{name: "other file", event: fsnotify.Event{Name: filepath.Join("repo", ".git", "HEAD"), Op: fsnotify.Write}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isIndexWrite(tt.event, index); got != tt.want {
+				t.Errorf("isIndexWrite(%v) = %v, want %v", tt.event, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchIndexDebounces(t *testing.T) {
+	const debounce = 50 * time.Millisecond
+	index := filepath.Join("repo", ".git", "index")
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan fsnotify.Event)
+	changed := make(chan struct{}, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- watchIndex(ctx, events, nil, index, debounce, func() { changed <- struct{}{} })
+	}()
+
+	count := func(wait time.Duration) int {
+		n := 0
+		timeout := time.After(wait)
+		for {
+			select {
+			case <-changed:
+				n++
+			case <-timeout:
+				return n
+			}
+		}
+	}
+
+	// A burst of writes is one change
+	for i := 0; i < 3; i++ {
+		events <- fsnotify.Event{Name: index + ".lock", Op: fsnotify.Create}
+		events <- fsnotify.Event{Name: index, Op: fsnotify.Create}
+	}
+	if n := count(4 * debounce); n != 1 {
+		t.Errorf("burst of writes: changed %d times, want 1", n)
+	}
+
+	// Events that are not writes to the index change nothing
+	events <- fsnotify.Event{Name: index, Op: fsnotify.Chmod}
+	events <- fsnotify.Event{Name: index + ".lock", Op: fsnotify.Write}
+	if n := count(4 * debounce); n != 0 {
+		t.Errorf("other events: changed %d times, want 0", n)
+	}
+
+	// Writes far enough apart are separate changes
+	events <- fsnotify.Event{Name: index, Op: fsnotify.Write}
+	count(4 * debounce)
+	events <- fsnotify.Event{Name: index, Op: fsnotify.Write}
+	if n := count(4 * debounce); n != 1 {
+		t.Errorf("second write: changed %d times, want 1", n)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("watchIndex() after cancel = %v, want nil", err)
+	}
+}
+
+func TestWatchIndexError(t *testing.T) {
+	errs := make(chan error, 1)
+	errs <- errors.New("queue overflow")
+	err := watchIndex(context.Background(), nil, errs, "index", time.Millisecond, func() {})
+	if err == nil || err.Error() != "error watching the index: queue overflow" {
+		t.Errorf("watchIndex() = %v, want the watcher's error", err)
+	}
+
+	events := make(chan fsnotify.Event)
+	close(events)
+	if err := watchIndex(context.Background(), events, nil, "index", time.Millisecond, func() {}); err != nil {
+		t.Errorf("watchIndex() with the watcher closed = %v, want nil", err)
+	}
+}
@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/prompt"
+	"github.com/andev0x/gitmit/internal/style"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	wipAllFlag      bool
+	wipNoVerifyFlag bool
+
+	unwipBaseFlag   string
+	unwipLastFlag   int
+	unwipRewordFlag bool
+	unwipDryRunFlag bool
+	unwipYesFlag    bool
+
+	wipCmd = &cobra.Command{
+		Use:   "wip",
+		Short: "Commit a quick checkpoint with a wip: message, without questions",
+		Long: `Commit the staged changes at once as "wip: <summary of the changes>". When
+nothing is staged, the changes to tracked files are staged first; --all stages
+untracked files too.
+
+No suggestion loop, rule checks or changelog entry get in the way; the hooks
+of the config still run, and git's own hooks unless --no-verify is given. The
+checkpoint is flagged in gitmit's history, so that "gitmit unwip" can fold the
+checkpoints into real commits before they are pushed.`,
+		Example: `  gitmit wip
+  gitmit wip --all --no-verify`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runWip,
+	}
+
+	unwipCmd = &cobra.Command{
+		Use:   "unwip [range]",
+		Short: "Fold the wip checkpoints of a branch into real commits before pushing",
+		Long: `Find the checkpoints of a range, the commits made by gitmit wip or whose
+subject starts with "wip", and fold each run of consecutive checkpoints into
+one commit with a message suggested from their combined diff. With --reword
+every checkpoint is kept and only gets a suggested message.
+
+The commits are given as a range (base..head), with --last N, or default to
+the commits of the current branch since its base. The plan is shown for
+review and applied with git rebase -i, which rewrites the commits, so pushed
+checkpoints are refused.`,
+		Example: `  gitmit unwip
+  gitmit unwip --dry-run
+  gitmit unwip --last 10 --reword`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE:         runUnwip,
+	}
+)
+
+// wipSubjectRegex matches the subjects of checkpoints, whether made by gitmit
+// wip or by hand: "wip: ...", "WIP ..." or "[WIP] ..."
+var wipSubjectRegex = regexp.MustCompile(`(?i)^(wip\b|\[wip\])`)
+
+func init() {
+	rootCmd.AddCommand(wipCmd)
+	rootCmd.AddCommand(unwipCmd)
+	wipCmd.Flags().BoolVarP(&wipAllFlag, "all", "a", false, "Stage all changes first, including untracked files")
+	wipCmd.Flags().BoolVarP(&wipNoVerifyFlag, "no-verify", "n", false, "Skip git's pre-commit and commit-msg hooks")
+	unwipCmd.Flags().StringVar(&unwipBaseFlag, "base", "", "Fold the checkpoints since this branch or commit (default: origin's default branch, main or master)")
+	unwipCmd.Flags().IntVar(&unwipLastFlag, "last", 0, "Fold the checkpoints among the last N commits")
+	unwipCmd.Flags().BoolVar(&unwipRewordFlag, "reword", false, "Keep every checkpoint and only give it a suggested message")
+	unwipCmd.Flags().BoolVar(&unwipDryRunFlag, "dry-run", false, "Show the plan without rewriting commits")
+	unwipCmd.Flags().BoolVarP(&unwipYesFlag, "yes", "y", false, "Apply the plan without asking")
+}
+
+func runWip(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	hist, err := history.LoadHistory(ctx)
+	if err != nil {
+		return err
+	}
+
+	gitParser := parser.NewGitParser()
+	changes, err := gitParser.ParseStagedChanges(ctx)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 || wipAllFlag {
+		if err := parser.StageFiles(ctx, wipAllFlag); err != nil {
+			return err
+		}
+		gitParser = parser.NewGitParser()
+		if changes, err = gitParser.ParseStagedChanges(ctx); err != nil {
+			return err
+		}
+	}
+	if len(changes) == 0 {
+		return fmt.Errorf("⚠️ no changes to checkpoint")
+	}
+
+	suggestion, err := heuristicSuggestion(ctx, cfg, hist, gitParser, changes)
+	if err != nil {
+		return err
+	}
+	branchName, _ := gitParser.GetCurrentBranch(ctx)
+	var commitArgs []string
+	if wipNoVerifyFlag {
+		commitArgs = append(commitArgs, "--no-verify")
+	}
+	message, err := newHookRunner(cfg, branchName).commit(ctx, wipMessage(suggestion, len(changes)), commitArgs...)
+	if err != nil {
+		return err
+	}
+
+	hash, err := parser.ResolveRevision(ctx, "HEAD")
+	if err != nil {
+		return err
+	}
+	hist.AddWIP(hash)
+	if err := hist.SaveHistory(ctx); err != nil {
+		return err
+	}
+	ui.Success("✅ Checkpoint %s %q", hash[:7], style.Subject(message))
+	ui.Muted("  Run gitmit unwip to fold the checkpoints into real commits before pushing.")
+	return nil
+}
+
+// wipMessage turns the suggestion for the changes into a checkpoint message,
+// keeping its description
+func wipMessage(suggestion string, files int) string {
+	_, _, _, description := style.ParseSubject(style.Subject(suggestion))
+	if description = strings.TrimSpace(description); description == "" {
+		description = "update " + ui.Plural(files, "file", "files")
+	}
+	return "wip: " + description
+}
+
+// isCheckpoint reports whether a commit is a checkpoint to fold
+func isCheckpoint(hist *history.CommitHistory, commit *parser.Commit) bool {
+	return hist.IsWIP(commit.Hash) || wipSubjectRegex.MatchString(commit.Subject)
+}
+
+// checkpointRun is a run of consecutive checkpoints folded into one commit,
+// oldest first
+type checkpointRun struct {
+	Commits []*parser.Commit
+	Message string
+}
+
+// checkpointRuns groups the checkpoints among commits into runs of
+// consecutive ones, or makes a run of each when reword is set
+func checkpointRuns(hist *history.CommitHistory, commits []*parser.Commit, reword bool) (runs []*checkpointRun, runOf map[string]*checkpointRun) {
+	runOf = make(map[string]*checkpointRun)
+	for i, commit := range commits {
+		if !isCheckpoint(hist, commit) {
+			continue
+		}
+		if prev := runOf[previousHash(commits, i)]; prev != nil && !reword {
+			prev.Commits = append(prev.Commits, commit)
+			runOf[commit.Hash] = prev
+			continue
+		}
+		run := &checkpointRun{Commits: []*parser.Commit{commit}}
+		runs = append(runs, run)
+		runOf[commit.Hash] = run
+	}
+	return runs, runOf
+}
+
+// previousHash returns the hash of the commit before the i-th, or "" for the first
+func previousHash(commits []*parser.Commit, i int) string {
+	if i == 0 {
+		return ""
+	}
+	return commits[i-1].Hash
+}
+
+func runUnwip(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	hist, err := history.LoadHistory(ctx)
+	if err != nil {
+		return err
+	}
+
+	base, head, err := resolveRange(ctx, args, unwipBaseFlag, unwipLastFlag)
+	if err != nil {
+		return err
+	}
+	commits, err := parser.ParseCommits(ctx, base, head)
+	if err != nil {
+		return err
+	}
+	runs, runOf := checkpointRuns(hist, commits, unwipRewordFlag)
+	if len(runs) == 0 {
+		ui.Warn("No wip checkpoints between %s and %s.", base, head)
+		return nil
+	}
+
+	// A remote branch containing the oldest checkpoint may contain them all
+	if pushed, err := parser.IsPushed(ctx, runs[0].Commits[0].Hash); err != nil {
+		return err
+	} else if pushed {
+		return fmt.Errorf("checkpoint %s is pushed already; folding it would need a force-push", runs[0].Commits[0].ShortHash())
+	}
+
+	branchName, _ := parser.NewGitParser().GetCurrentBranch(ctx)
+	f, _ := newFormatters(ctx, cfg, branchTicketPrefix(cfg, cfg.BranchPolicy(branchName), branchName), "", learnRepoStyle(cfg, hist))
+	for _, run := range runs {
+		if err := suggestRunMessage(ctx, cfg, hist, run); err != nil {
+			return err
+		}
+		run.Message = f.FormatMessage(run.Message, false)
+	}
+
+	printCheckpointRuns(runs)
+	if unwipDryRunFlag {
+		return nil
+	}
+	if !unwipYesFlag {
+		question := fmt.Sprintf("Fold %s into %s?", ui.Plural(len(runOf), "checkpoint", "checkpoints"), ui.Plural(len(runs), "commit", "commits"))
+		if unwipRewordFlag {
+			question = fmt.Sprintf("Reword %s?", ui.Plural(len(runs), "checkpoint", "checkpoints"))
+		}
+		apply, err := prompt.New().Confirm(question, true)
+		if err != nil && !errors.Is(err, prompt.ErrInterrupted) {
+			return err
+		}
+		if !apply {
+			ui.Warn("Unwip cancelled.")
+			return nil
+		}
+	}
+
+	if err := foldCheckpoints(ctx, base, head, commits, runOf); err != nil {
+		return err
+	}
+	for hash := range runOf {
+		hist.ForgetWIP(hash)
+	}
+	return hist.SaveHistory(ctx)
+}
+
+// suggestRunMessage suggests the message of a run from the combined diff of
+// its checkpoints, or keeps the description of the latest one when there is
+// nothing to describe
+func suggestRunMessage(ctx context.Context, cfg *config.Config, hist *history.CommitHistory, run *checkpointRun) error {
+	first, last := run.Commits[0], run.Commits[len(run.Commits)-1]
+	gitParser := parser.NewGitParser()
+	changes, err := gitParser.ParseRangeChanges(ctx, first.Hash+"^", last.Hash)
+	if err != nil {
+		return err
+	}
+	suggestion, err := heuristicSuggestion(ctx, cfg, hist, gitParser, changes)
+	if err != nil {
+		return err
+	}
+	if suggestion == "" {
+		suggestion = "chore: " + strings.TrimSpace(wipSubjectRegex.ReplaceAllString(last.Subject, ""))
+	}
+	run.Message = suggestion
+	return nil
+}
+
+// printCheckpointRuns lists the commit each run becomes with its checkpoints
+func printCheckpointRuns(runs []*checkpointRun) {
+	ui.Heading("🧹 Checkpoints:")
+	for i, run := range runs {
+		ui.Printf("%d. %s\n", i+1, ui.SuccessString("%s", style.Subject(run.Message)))
+		for _, commit := range run.Commits {
+			ui.Printf("   %s %s\n", ui.WarnString("%s", commit.ShortHash()), ui.MutedString("%s", commit.Subject))
+		}
+	}
+	ui.Println()
+}
+
+// foldCheckpoints rewrites the commits with an interactive rebase whose todo
+// list folds the later checkpoints of each run into its first and gives the
+// result the message of the run
+func foldCheckpoints(ctx context.Context, base, head string, commits []*parser.Commit, runOf map[string]*checkpointRun) error {
+	current, err := checkRewritable(ctx, base, head)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "gitmit-unwip")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var todo strings.Builder
+	folded := 0
+	for i, commit := range commits {
+		run := runOf[commit.Hash]
+		if run != nil && commit != run.Commits[0] {
+			fmt.Fprintf(&todo, "fixup %s %s\n", commit.Hash, commit.Subject)
+			folded++
+		} else {
+			fmt.Fprintf(&todo, "pick %s %s\n", commit.Hash, commit.Subject)
+		}
+		if run != nil && commit == run.Commits[len(run.Commits)-1] {
+			if err := amendTodo(&todo, dir, i, run.Message); err != nil {
+				return err
+			}
+		}
+	}
+	if err := runRebaseTodo(ctx, base, dir, todo.String()); err != nil {
+		return fmt.Errorf("error folding checkpoints (git rebase --abort restores %s): %w", current[:7], err)
+	}
+	ui.Success("✅ Rewrote %s, folding %d. The previous tip was %s.", ui.Plural(len(runOf), "checkpoint", "checkpoints"), folded, current[:7])
+	return nil
+}
@@ -0,0 +1,394 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/formatter"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/prompt"
+	"github.com/andev0x/gitmit/internal/spell"
+	"github.com/andev0x/gitmit/internal/style"
+	"github.com/andev0x/gitmit/internal/templater"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+var (
+	wizardDryRunFlag       bool
+	wizardTemplateFileFlag string
+
+	wizardCmd = &cobra.Command{
+		Use:   "wizard",
+		Short: "Build a commit message step by step",
+		Long: `Walk through the parts of a conventional commit message one at a time: type,
+scope, subject, body, breaking change and footers.
+
+Every answer is pre-filled from the analysis of the staged changes, so pressing
+enter keeps the suggestion. Types and scopes are chosen with the arrow keys and
+a preview of the message is updated as you answer. When the input is not a
+terminal, answers are read line by line.`,
+		Example: `  gitmit wizard
+  gitmit wizard --dry-run`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runWizard,
+	}
+)
+
+// commitTypeOptions are the conventional commit types offered by the wizard
+var commitTypeOptions = []prompt.Option{
+	{Value: "feat", Description: "A new feature"},
+	{Value: "fix", Description: "A bug fix"},
+	{Value: "docs", Description: "Documentation only changes"},
+	{Value: "style", Description: "Formatting that does not change the meaning of the code"},
+	{Value: "refactor", Description: "A change that neither fixes a bug nor adds a feature"},
+	{Value: "perf", Description: "A change that improves performance"},
+	{Value: "test", Description: "Adding or correcting tests"},
+	{Value: "build", Description: "Changes to the build system or dependencies"},
+	{Value: "ci", Description: "Changes to CI configuration and scripts"},
+	{Value: "chore", Description: "Other changes that don't touch source or tests"},
+	{Value: "security", Description: "A fix for a security issue"},
+	{Value: "revert", Description: "Reverts a previous commit"},
+}
+
+// typeOptions returns the commit types the wizard offers, those allowed by the
+// config when it narrows them
+func typeOptions(types []string) []prompt.Option {
+	if len(types) == 0 {
+		return commitTypeOptions
+	}
+	var options []prompt.Option
+	for _, option := range commitTypeOptions {
+		if slices.Contains(types, option.Value) {
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+func init() {
+	rootCmd.AddCommand(wizardCmd)
+	wizardCmd.Flags().BoolVar(&wizardDryRunFlag, "dry-run", false, "Print the message without committing")
+	wizardCmd.Flags().StringVar(&wizardTemplateFileFlag, "template-file", "", "Template file to use instead of templates.json")
+}
+
+// wizardAnswers holds the parts of the message built by the wizard
+type wizardAnswers struct {
+	Type     string
+	Scope    string
+	Subject  string
+	Body     []string
+	Breaking bool
+	Change   string // Description of the breaking change for its footer
+	Footers  []string
+}
+
+// String assembles the conventional commit message from the answers
+func (a *wizardAnswers) String() string {
+	header := a.Type
+	if a.Scope != "" {
+		header += "(" + a.Scope + ")"
+	}
+	if a.Breaking {
+		header += "!"
+	}
+	sections := []string{header + ": " + a.Subject}
+
+	if len(a.Body) > 0 {
+		sections = append(sections, strings.Join(a.Body, "\n"))
+	}
+	footers := a.Footers
+	if a.Breaking && a.Change != "" {
+		footers = append([]string{"BREAKING CHANGE: " + a.Change}, footers...)
+	}
+	if len(footers) > 0 {
+		sections = append(sections, strings.Join(footers, "\n"))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+func runWizard(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	hist, err := history.LoadHistory(ctx)
+	if err != nil {
+		return err
+	}
+
+	gitParser := parser.NewGitParser()
+	changes, err := gitParser.ParseStagedChanges(ctx)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return parser.ErrNoStagedChanges
+	}
+
+	branchName, _ := gitParser.GetCurrentBranch(ctx)
+	hooks := newHookRunner(cfg, branchName)
+	changes, err = hooks.preAnalyze(ctx, gitParser, changes)
+	if err != nil {
+		return err
+	}
+	cfg, commitMessage, err := analyzeChanges(ctx, cfg, gitParser, changes, branchName)
+	if err != nil {
+		return err
+	}
+	if commitMessage == nil {
+		return fmt.Errorf("could not analyze changes")
+	}
+
+	policy := cfg.BranchPolicy(branchName)
+	t, err := templater.NewTemplater(selectTemplateFile(cfg, policy, wizardTemplateFileFlag), hist)
+	if err != nil {
+		return err
+	}
+	if err := t.AddTemplates(cfg.Templates); err != nil {
+		return err
+	}
+	t.RestrictTypes(cfg.Types)
+	ticketPrefix, err := resolveTicketPrefix(cfg, policy, branchName)
+	if err != nil {
+		return err
+	}
+
+	ownersFooter := ""
+	if cfg.Codeowners.MentionOwners && len(commitMessage.Owners) > 0 {
+		ownersFooter = "Owners: " + strings.Join(commitMessage.Owners, ", ")
+	}
+	repoStyle := learnRepoStyle(cfg, hist)
+	f, editFormatter := newFormatters(ctx, cfg, ticketPrefix, ownersFooter, repoStyle)
+	useCommitTemplate(ctx, cfg, f, editFormatter)
+	// Repeats of recent commits are told apart as the messages would be committed
+	t.FormatWith(func(message string) string { return f.FormatMessage(message, commitMessage.IsMajor) })
+
+	// The heuristic suggestion pre-fills every answer; a version bump also the
+	// body, with the commits since the latest tag
+	release := releaseBody(ctx, commitMessage)
+	suggestion, err := t.GetMessage(commitMessage)
+	if err != nil {
+		return err
+	}
+	if release != "" {
+		suggestion += "\n\n" + release
+	}
+	template := t.TemplateFor(suggestion)
+	if suggestion, err = hooks.postSuggest(ctx, suggestion, history.SourceTemplate, commitMessage); err != nil {
+		return err
+	}
+	suggested := suggestedAnswers(suggestion, cfg)
+
+	p := prompt.New()
+	answers, err := askWizard(p, suggested, repoStyle, editFormatter)
+	if errors.Is(err, prompt.ErrInterrupted) {
+		ui.Warn("❌ Commit cancelled.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	message := editFormatter.FormatMessage(answers.String(), false)
+	ui.Success("\n💡 Commit message:")
+	fmt.Printf("%s\n\n", message)
+
+	violations := f.Check(message)
+	printViolations(violations)
+	if hasFixable(violations) {
+		if fix, err := p.Confirm("Fix rule violations?", true); err == nil && fix {
+			message = f.Fix(message)
+			ui.Success("✓ Fixed rule violations:")
+			fmt.Printf("%s\n\n", message)
+		}
+	}
+
+	if cfg.Spellcheck.Enabled {
+		checker := spell.NewChecker(cfg.Spellcheck.Words)
+		if typos := checkSpelling(checker, message); len(typos) > 0 {
+			printTypos(typos)
+			message = correctSpelling(ctx, p.Reader(), checker, message, typos)
+		}
+	}
+
+	if wizardDryRunFlag {
+		fmt.Println("(Dry run: no changes committed)")
+		return nil
+	}
+	if missingTicket(cfg, f.Check(message)) {
+		return fmt.Errorf("not committing: the subject must start with a ticket matching %s (rules.requireTicket)", cfg.Rules.TicketPattern)
+	}
+
+	commit, err := p.Confirm("Commit with this message?", true)
+	if err != nil && !errors.Is(err, prompt.ErrInterrupted) {
+		return err
+	}
+	if !commit {
+		ui.Warn("❌ Commit cancelled.")
+		hist.RecordOutcome(message, template, history.OutcomeRejected)
+		hist.RecordSourceOutcome(history.SourceTemplate, history.OutcomeRejected)
+		return hist.SaveHistory(ctx)
+	}
+
+	if err := addChangelogEntry(ctx, cfg, p.Reader(), message); err != nil {
+		return err
+	}
+	if _, err := hooks.commit(ctx, message); err != nil {
+		return err
+	}
+	ui.Success("✅ Changes committed successfully.")
+
+	outcome := history.OutcomeEdited
+	if answers.String() == suggested.String() {
+		outcome = history.OutcomeAccepted
+	}
+	hist.RecordOutcome(message, template, outcome)
+	hist.RecordSourceOutcome(history.SourceTemplate, outcome)
+	return hist.SaveHistory(ctx)
+}
+
+// suggestedAnswers splits a heuristic suggestion into pre-filled wizard answers
+func suggestedAnswers(suggestion string, cfg *config.Config) *wizardAnswers {
+	subject, body, _ := strings.Cut(suggestion, "\n")
+	subject = style.AliasScope(strings.TrimSpace(subject), cfg.ScopeAliases)
+
+	commitType, scope, breaking, description := style.ParseSubject(subject)
+	if commitType == "" {
+		commitType = "chore"
+	}
+	commitType = style.NearestType(commitType, cfg.Types)
+	if cfg.ImperativeMood {
+		description = style.Imperative(description)
+	}
+
+	answers := &wizardAnswers{Type: commitType, Scope: scope, Subject: description, Breaking: breaking}
+	if body = strings.TrimSpace(body); body != "" {
+		answers.Body = strings.Split(body, "\n")
+	}
+	return answers
+}
+
+// askWizard walks through each part of the message, starting from the suggested
+// answers and previewing the message formatted as it will be committed
+func askWizard(p *prompt.Prompter, suggested *wizardAnswers, repoStyle *style.Style, f *formatter.Formatter) (*wizardAnswers, error) {
+	answers := *suggested
+	preview := func(a wizardAnswers) string {
+		return wizardPreview(f.FormatMessage(a.String(), false), f.MaxSubjectLength)
+	}
+
+	// Type
+	options := typeOptions(repoStyle.Types)
+	initial := 0
+	for i, option := range options {
+		if option.Value == answers.Type {
+			initial = i
+		}
+	}
+	i, err := p.Select("Type of change", options, initial, func(i int) string {
+		a := answers
+		a.Type = options[i].Value
+		return preview(a)
+	})
+	if err != nil {
+		return nil, err
+	}
+	answers.Type = options[i].Value
+
+	// Scope
+	if answers.Scope, err = askScope(p, answers, repoStyle, preview); err != nil {
+		return nil, err
+	}
+
+	// Subject
+	for {
+		answers.Subject, err = p.Input("Subject", answers.Subject, func(text string) string {
+			a := answers
+			a.Subject = text
+			return preview(a)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if answers.Subject != "" {
+			break
+		}
+		ui.Warn("⚠ The subject is required.")
+	}
+
+	// Body
+	keep := false
+	if len(answers.Body) > 0 {
+		fmt.Printf("Suggested body:\n%s\n", strings.Join(answers.Body, "\n"))
+		if keep, err = p.Confirm("Keep the suggested body?", true); err != nil {
+			return nil, err
+		}
+	}
+	if !keep {
+		if answers.Body, err = p.Lines("Body: explain what changed and why (optional)"); err != nil {
+			return nil, err
+		}
+	}
+
+	// Breaking change
+	if answers.Breaking, err = p.Confirm("Is this a breaking change?", answers.Breaking); err != nil {
+		return nil, err
+	}
+	if answers.Breaking {
+		answers.Change, err = p.Input("Describe the breaking change", "", func(text string) string {
+			a := answers
+			a.Change = text
+			return preview(a)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Footers
+	if answers.Footers, err = p.Lines(`Footers, e.g. "Closes #12" or "Refs: ABC-123" (optional)`); err != nil {
+		return nil, err
+	}
+	return &answers, nil
+}
+
+// askScope offers the suggested scope, the known scopes, no scope and a custom one
+func askScope(p *prompt.Prompter, answers wizardAnswers, repoStyle *style.Style, preview func(wizardAnswers) string) (string, error) {
+	var options []prompt.Option
+	if answers.Scope != "" {
+		options = append(options, prompt.Option{Value: answers.Scope, Description: "suggested"})
+	}
+	for _, scope := range repoStyle.Scopes {
+		if scope != answers.Scope {
+			options = append(options, prompt.Option{Value: scope})
+		}
+	}
+	return selectScope(p, options, allowCustomScope(repoStyle), func(scope string) string {
+		a := answers
+		a.Scope = scope
+		return preview(a)
+	})
+}
+
+// wizardPreview formats a message preview with the length of its subject
+func wizardPreview(message string, maxSubjectLength int) string {
+	subject, _, _ := strings.Cut(message, "\n")
+	lines := []string{"Preview:"}
+	for _, line := range strings.Split(message, "\n") {
+		lines = append(lines, "  │ "+line)
+	}
+	length := fmt.Sprintf("  %d characters", utf8.RuneCountInString(subject))
+	if maxSubjectLength > 0 {
+		length = fmt.Sprintf("  %d/%d characters", utf8.RuneCountInString(subject), maxSubjectLength)
+	}
+	return strings.Join(append(lines, length), "\n")
+}
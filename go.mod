@@ -5,8 +5,12 @@ go 1.23.0
 toolchain go1.24.5
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/fatih/color v1.16.0
+	github.com/fsnotify/fsnotify v1.9.0
 	github.com/spf13/cobra v1.8.0
+	golang.org/x/sys v0.35.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -14,5 +18,4 @@ require (
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
-	golang.org/x/sys v0.35.0 // indirect
 )
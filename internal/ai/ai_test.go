@@ -1,10 +1,14 @@
 package ai
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/config"
 )
 
 func TestRenderPrompt(t *testing.T) {
@@ -15,9 +19,10 @@ func TestRenderPrompt(t *testing.T) {
 		DetectedFunctions: []string{"Login", "Logout"},
 		TotalAdded: 50,
 		TotalRemoved: 10,
+		References: []string{"AUTH-12: Let users sign in with email"},
 	}
 
-	prompt, err := RenderPrompt(msg, "go", "feature/auth-implementation")
+	prompt, err := RenderPrompt(context.Background(), msg, "go", "feature/auth-implementation", nil)
 	if err != nil {
 		t.Fatalf("RenderPrompt failed: %v", err)
 	}
@@ -29,6 +34,7 @@ func TestRenderPrompt(t *testing.T) {
 		"internal/auth/login.go",
 		"[func] Login",
 		"Added/Deleted Line Ratio: 0.83",
+		"Related Ticket or Issue: AUTH-12: Let users sign in with email",
 		"Recent Commit History",
 	}
 
@@ -59,3 +65,37 @@ func TestIsValidCommitMessage(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderStandupPrompt(t *testing.T) {
+	prompt, err := RenderStandupPrompt("since yesterday", "- [gitmit] feat: add standup command\n")
+	if err != nil {
+		t.Fatalf("RenderStandupPrompt() error = %v", err)
+	}
+	if !strings.Contains(prompt, "commits since yesterday") || !strings.Contains(prompt, "- [gitmit] feat: add standup command") {
+		t.Errorf("prompt is missing the period or the log:\n%s", prompt)
+	}
+}
+
+func TestModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"models": [{"name": "qwen2.5-coder:1.5b"}, {"name": "llama3:latest"}]}`))
+	}))
+	defer server.Close()
+
+	models, err := NewOllamaClient(config.OllamaConfig{URL: server.URL}).Models(context.Background())
+	if err != nil {
+		t.Fatalf("Models() = %v", err)
+	}
+	if !HasModel(models, "qwen2.5-coder:1.5b") || !HasModel(models, "llama3") || HasModel(models, "mistral") {
+		t.Errorf("HasModel() is wrong for %v", models)
+	}
+
+	server.Close()
+	if _, err := NewOllamaClient(config.OllamaConfig{URL: server.URL}).Models(context.Background()); err == nil || !strings.Contains(err.Error(), "unreachable") {
+		t.Errorf("Models() of a stopped daemon = %v, want it unreachable", err)
+	}
+}
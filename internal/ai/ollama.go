@@ -2,9 +2,14 @@ package ai
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/andev0x/gitmit/internal/config"
@@ -30,13 +35,24 @@ type OllamaClient struct {
 	config config.OllamaConfig
 }
 
+// DefaultTimeout bounds each request to the AI model until SetTimeout changes it
+const DefaultTimeout = 60 * time.Second
+
+var timeout = DefaultTimeout
+
+// SetTimeout sets how long each request to the AI model may take; 0 means no limit
+func SetTimeout(d time.Duration) {
+	timeout = d
+}
+
 // NewOllamaClient creates a new OllamaClient
 func NewOllamaClient(cfg config.OllamaConfig) *OllamaClient {
 	return &OllamaClient{config: cfg}
 }
 
-// Generate sends a prompt to Ollama and returns the generated response
-func (c *OllamaClient) Generate(prompt string) (string, error) {
+// Generate sends a prompt to Ollama and returns the generated response. The
+// request stops when ctx is done, such as on Ctrl+C.
+func (c *OllamaClient) Generate(ctx context.Context, prompt string) (string, error) {
 	reqBody := OllamaRequest{
 		Model:       c.config.Model,
 		Prompt:      prompt,
@@ -52,11 +68,26 @@ func (c *OllamaClient) Generate(prompt string) (string, error) {
 	url := fmt.Sprintf("%s/api/generate", c.config.URL)
 	
 	client := &http.Client{
-		Timeout: 30 * time.Second,
+		Timeout: timeout,
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("error creating ollama request: %w", err)
 	}
+	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	slog.Info("requesting ollama", "url", url, "model", c.config.Model)
+	slog.Debug("ollama prompt", "prompt", prompt)
+	start := time.Now()
+	resp, err := client.Do(req)
 	if err != nil {
+		var netErr net.Error
+		switch {
+		case ctx.Err() != nil:
+			return "", fmt.Errorf("ollama request stopped: %w", ctx.Err())
+		case errors.As(err, &netErr) && netErr.Timeout():
+			return "", fmt.Errorf("ollama did not answer within %s (set timeouts.ai to allow longer): %w", timeout, err)
+		}
 		return "", fmt.Errorf("ollama daemon unreachable at %s: %w", url, err)
 	}
 	defer resp.Body.Close()
@@ -73,5 +104,49 @@ func (c *OllamaClient) Generate(prompt string) (string, error) {
 		return "", fmt.Errorf("error decoding ollama response: %w", err)
 	}
 
+	slog.Debug("ollama response", "response", ollamaResp.Response, "took", time.Since(start).Round(time.Millisecond))
 	return ollamaResp.Response, nil
 }
+
+// Models returns the names of the models pulled into the Ollama daemon, which
+// also tells whether the daemon is reachable
+func (c *OllamaClient) Models(ctx context.Context) ([]string, error) {
+	url := fmt.Sprintf("%s/api/tags", c.config.URL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating ollama request: %w", err)
+	}
+	resp, err := (&http.Client{Timeout: timeout}).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama daemon unreachable at %s: %w", c.config.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama returned status code: %d", resp.StatusCode)
+	}
+
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("error decoding ollama models: %w", err)
+	}
+	models := make([]string, 0, len(tags.Models))
+	for _, model := range tags.Models {
+		models = append(models, model.Name)
+	}
+	return models, nil
+}
+
+// HasModel reports whether a model is among the pulled models; a model named
+// without a tag matches its latest tag
+func HasModel(models []string, model string) bool {
+	for _, m := range models {
+		if m == model || (!strings.Contains(model, ":") && m == model+":latest") {
+			return true
+		}
+	}
+	return false
+}
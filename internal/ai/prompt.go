@@ -2,6 +2,7 @@ package ai
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 	"text/template"
@@ -9,6 +10,7 @@ import (
 	"github.com/andev0x/gitmit/assets"
 	"github.com/andev0x/gitmit/internal/analyzer"
 	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/style"
 )
 
 // PromptContext represents the data structure passed to the prompt template
@@ -22,6 +24,8 @@ type PromptContext struct {
 	DiffSummary     DiffSummary
 	DiffContent     string
 	RecentCommits   []string
+	StyleGuide      []string
+	References      []string
 }
 
 // DiffSummary contains ratio of changes
@@ -29,8 +33,9 @@ type DiffSummary struct {
 	Ratio float64
 }
 
-// RenderPrompt generates the prompt string using the provided context
-func RenderPrompt(msg *analyzer.CommitMessage, projectType, branchName string) (string, error) {
+// RenderPrompt generates the prompt string using the provided context. The
+// repository style, if known, is turned into style instructions for the model.
+func RenderPrompt(ctx context.Context, msg *analyzer.CommitMessage, projectType, branchName string, repoStyle *style.Style) (string, error) {
 	promptTemplate, err := assets.GetPrompt()
 	if err != nil {
 		return "", fmt.Errorf("error loading prompt template: %w", err)
@@ -64,12 +69,12 @@ func RenderPrompt(msg *analyzer.CommitMessage, projectType, branchName string) (
 	}
 
 	// Fetch recent commits for style reference
-	recentCommits, _ := history.GetRecentCommits(5)
+	recentCommits, _ := history.GetRecentCommits(ctx, 5)
 
-	ctx := PromptContext{
+	promptCtx := PromptContext{
 		ProjectType:     projectType,
 		CurrentBranch:   branchName,
-		RecommendedType: msg.Action,
+		RecommendedType: recommendedType(msg.Action, repoStyle),
 		Files:           msg.Files,
 		CodeSymbols:     codeSymbols,
 		DependencyAlert: depAlert,
@@ -78,16 +83,27 @@ func RenderPrompt(msg *analyzer.CommitMessage, projectType, branchName string) (
 		},
 		DiffContent:   msg.FullDiff,
 		RecentCommits: recentCommits,
+		StyleGuide:    repoStyle.Guidelines(),
+		References:    msg.References,
 	}
 
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, ctx); err != nil {
+	if err := tmpl.Execute(&buf, promptCtx); err != nil {
 		return "", fmt.Errorf("error executing prompt template: %w", err)
 	}
 
 	return buf.String(), nil
 }
 
+// recommendedType returns the type of the analysis, or the nearest type the
+// repository allows
+func recommendedType(action string, repoStyle *style.Style) string {
+	if repoStyle == nil {
+		return action
+	}
+	return style.NearestType(action, repoStyle.Types)
+}
+
 // IsValidCommitMessage checks if the AI output follows the Conventional Commits format
 func IsValidCommitMessage(msg string) bool {
 	// Simple regex check for <type>(<scope>): <description> or <type>: <description>
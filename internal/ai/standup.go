@@ -0,0 +1,29 @@
+package ai
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/andev0x/gitmit/assets"
+)
+
+// RenderStandupPrompt generates the prompt asking for a standup summary of a commit
+// log. Since describes the period, e.g. "since yesterday".
+func RenderStandupPrompt(since, log string) (string, error) {
+	promptTemplate, err := assets.GetStandupPrompt()
+	if err != nil {
+		return "", fmt.Errorf("error loading standup prompt template: %w", err)
+	}
+
+	tmpl, err := template.New("standup").Parse(promptTemplate)
+	if err != nil {
+		return "", fmt.Errorf("error parsing standup prompt template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct{ Since, Log string }{since, log}); err != nil {
+		return "", fmt.Errorf("error executing standup prompt template: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -2,11 +2,15 @@ package analyzer
 
 import (
 	"bufio"
+	"context"
 	"fmt"
-	"path/filepath"
+	"log/slog"
+	"path"
 	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/andev0x/gitmit/internal/codeowners"
 	"github.com/andev0x/gitmit/internal/config"
 	"github.com/andev0x/gitmit/internal/history"
 	"github.com/andev0x/gitmit/internal/parser"
@@ -34,12 +38,29 @@ type CommitMessage struct {
 	DetectedMethods   []string
 	ChangePatterns    []string
 	FullDiff          string
+	Owners            []string         // CODEOWNERS entries owning the changed files
+	ActionReasons     []string         // Signals behind Action, strongest first
+	Alternatives      []Interpretation // Other plausible actions, most likely first
+	References        []string         // Tickets and issues the changes are for, e.g. "PROJ-123: Add login", given to the AI as context
+	IssueTitle        string           // Title of the ticket or issue the changes are for, the {issue} placeholder
+	Version           string           // Version the changes bump to when they only bump version fields, e.g. v1.2.3
+	Move              *DirectoryMove   // Directory the changes move files out of and into, when they mostly move files
+	FallbackRule      string           // Name of the fallback rule that decided the message before scoring
+	Merge             *MergeSummary    // Merge in progress the changes complete
+	Revert            *Revert          // Earlier commit the changes undo
+	Pick              *parser.Pick     // Commit a cherry-pick or rebase in progress is applying again
+	Mixed             []Concern        // Concerns of changes that mix several, to split them by
+	ScopeCandidates   []string         // Other scopes the changed files point to, most likely first
+	Breakdown         []FileWeight     // How much each changed file counts toward the message, heaviest first
 }
 
 // Analyzer is responsible for analyzing git changes and generating commit message components
 type Analyzer struct {
 	changes []*parser.Change
 	config  *config.Config
+	trail   []Step         // Decision trail of the last analysis
+	workers int            // Goroutines analyzing files; GOMAXPROCS when 0
+	files   []fileAnalysis // Analysis of each changed file in the last analysis
 }
 
 // NewAnalyzer creates a new Analyzer
@@ -48,10 +69,95 @@ func NewAnalyzer(changes []*parser.Change, cfg *config.Config) *Analyzer {
 }
 
 // AnalyzeChanges analyzes the git changes and returns a CommitMessage
-func (a *Analyzer) AnalyzeChanges(totalAdded, totalRemoved int, branchName string) *CommitMessage {
+func (a *Analyzer) AnalyzeChanges(ctx context.Context, totalAdded, totalRemoved int, branchName string) *CommitMessage {
+	commitMessage := a.analyzeChanges(ctx, totalAdded, totalRemoved, branchName)
+	if commitMessage == nil {
+		return nil
+	}
+
+	// Changes mixing concerns, such as code with unrelated docs and CI, are
+	// pointed out to split them into atomic commits
+	if mixed := MixedConcerns(commitMessage.Breakdown); mixed != nil {
+		commitMessage.Mixed = mixed
+		var names []string
+		for _, concern := range mixed {
+			names = append(names, concern.Name)
+		}
+		a.note("mixed", "the changes mix %s", joinWords(names))
+	}
+
+	// A branch policy's commit type overrides every other signal
+	if policy := a.config.BranchPolicy(branchName); policy != nil && policy.Type != "" {
+		commitMessage.Action = policy.Type
+		a.note("branch policy", "branch %s requires %s", branchName, policy.Type)
+	}
+
+	// CODEOWNERS names the owning area more reliably than directory names,
+	// but explicit topic mappings and dependency updates take precedence
+	if a.config != nil && a.config.Codeowners.Enabled {
+		area, owners := a.codeownersArea(ctx)
+		commitMessage.Owners = owners
+		mainFile := a.changes[0].File
+		if len(commitMessage.Breakdown) > 0 {
+			mainFile = commitMessage.Breakdown[0].File
+		}
+		if area != "" && commitMessage.Scope != "deps" && !a.hasTopicMapping(mainFile) {
+			commitMessage.Topic = area
+			commitMessage.Scope = area
+		}
+	}
+	commitMessage.ScopeCandidates = a.scopeCandidates(commitMessage.Scope)
+	slog.Info("analyzed changes", "action", commitMessage.Action, "topic", commitMessage.Topic, "scope", commitMessage.Scope, "item", commitMessage.Item)
+	return commitMessage
+}
+
+// codeownersArea maps the changed files through CODEOWNERS and returns the team
+// area owning most of them, along with all owners of the changed files
+func (a *Analyzer) codeownersArea(ctx context.Context) (string, []string) {
+	file, err := codeowners.Find(ctx)
+	if err != nil || file == nil {
+		return "", nil
+	}
+
+	areas := make(map[string]int)
+	var owners []string
+	for _, change := range a.changes {
+		fileOwners := file.Owners(change.File)
+		owners = append(owners, fileOwners...)
+		if area := codeowners.Area(fileOwners); area != "" {
+			areas[area]++
+		}
+	}
+
+	best := ""
+	for area, count := range areas {
+		if count > areas[best] || (count == areas[best] && area < best) {
+			best = area
+		}
+	}
+	// Only use an area that owns most of the change
+	if areas[best]*2 <= len(a.changes) {
+		best = ""
+	}
+	return best, uniqueStrings(owners)
+}
+
+// hasTopicMapping reports whether a configured topic mapping matches the path
+func (a *Analyzer) hasTopicMapping(path string) bool {
+	for pattern := range a.config.TopicMappings {
+		if strings.Contains(path, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeChanges combines all signals into a CommitMessage
+func (a *Analyzer) analyzeChanges(ctx context.Context, totalAdded, totalRemoved int, branchName string) *CommitMessage {
 	if len(a.changes) == 0 {
 		return nil
 	}
+	a.trail = nil
 
 	commitMessage := &CommitMessage{
 		TotalAdded:   totalAdded,
@@ -68,7 +174,9 @@ func (a *Analyzer) AnalyzeChanges(totalAdded, totalRemoved int, branchName strin
 	var allMethods []string
 	var allPatterns []string
 
-	for _, change := range a.changes {
+	a.files = a.analyzeFiles()
+	for i, change := range a.changes {
+		file := a.files[i]
 		allFiles = append(allFiles, change.File)
 		if change.IsRename {
 			commitMessage.RenamedFiles = append(commitMessage.RenamedFiles, change)
@@ -81,23 +189,17 @@ func (a *Analyzer) AnalyzeChanges(totalAdded, totalRemoved int, branchName strin
 		}
 
 		allFileExtensions = append(allFileExtensions, change.FileExtension)
-		allTopics = append(allTopics, a.determineTopic(change.File))
-		allPurposes = append(allPurposes, a.determinePurpose(change.Diff))
-		allItems = append(allItems, a.determineItem(change.File))
+		allTopics = append(allTopics, file.topic)
+		allPurposes = append(allPurposes, file.purpose)
+		allItems = append(allItems, file.item)
 
 		// Detect code structures
-		funcs := a.detectFunctions(change.Diff)
-		allFunctions = append(allFunctions, funcs...)
-
-		structs := a.detectStructs(change.Diff)
-		allStructs = append(allStructs, structs...)
-
-		methods := a.detectMethods(change.Diff)
-		allMethods = append(allMethods, methods...)
+		allFunctions = append(allFunctions, file.functions...)
+		allStructs = append(allStructs, file.structs...)
+		allMethods = append(allMethods, file.methods...)
 
 		// Detect change patterns
-		patterns := a.detectChangePatterns(change)
-		allPatterns = append(allPatterns, patterns...)
+		allPatterns = append(allPatterns, file.patterns...)
 	}
 
 	commitMessage.Files = uniqueStrings(allFiles)
@@ -109,9 +211,9 @@ func (a *Analyzer) AnalyzeChanges(totalAdded, totalRemoved int, branchName strin
 
 	// Collect summarized diff for AI
 	var diffSummary strings.Builder
-	for _, change := range a.changes {
+	for i, change := range a.changes {
 		diffSummary.WriteString(fmt.Sprintf("File: %s\n", change.File))
-		diffSummary.WriteString(a.summarizeDiff(change.Diff))
+		diffSummary.WriteString(a.files[i].summary)
 		diffSummary.WriteString("\n")
 	}
 	commitMessage.FullDiff = diffSummary.String()
@@ -121,25 +223,45 @@ func (a *Analyzer) AnalyzeChanges(totalAdded, totalRemoved int, branchName strin
 	commitMessage.IsConfigOnly = a.isConfigOnly()
 	commitMessage.IsDepsOnly = a.isDepsOnly()
 
+	// Files count by the lines they change and their importance, so the
+	// order they were staged in does not matter
+	commitMessage.Breakdown = a.fileWeights()
+
 	// Apply smart fallback logic
 	if msg := a.applySmartFallback(commitMessage); msg != nil {
+		if msg.FallbackRule != "" {
+			a.note("shortcut", "%s (%s) decided before scoring by the %s rule", msg.Action, msg.Purpose, msg.FallbackRule)
+		} else {
+			a.note("shortcut", "%s (%s) decided before scoring", msg.Action, msg.Purpose)
+		}
+		msg.Breakdown = commitMessage.Breakdown
+		msg.Files, msg.FileExtensions = commitMessage.Files, commitMessage.FileExtensions
+		msg.TotalAdded, msg.TotalRemoved = commitMessage.TotalAdded, commitMessage.TotalRemoved
 		return msg
 	}
 
 	// Determine the recommended action (type) using scoring
+	a.noteSignals()
 	if a.config.NormalizeScoring {
 		commitMessage.Action = a.calculateNormalizedAction(totalAdded, totalRemoved, branchName, commitMessage)
 	} else {
 		commitMessage.Action = a.calculateAdditiveAction(totalAdded, totalRemoved, branchName, commitMessage)
 	}
 
-	// Default analysis based on the first change if no specific fallback applies
-	firstChange := a.changes[0]
-
-	// Determine other components
-	commitMessage.Topic = a.determineTopic(firstChange.File)
-	commitMessage.Item = a.determineItem(firstChange.File)
-	commitMessage.Purpose = a.determinePurpose(firstChange.Diff)
+	// The topic is the one of the files weighing most, the item its heaviest
+	// file and the purpose the one weighing most among them
+	topic := func(w FileWeight) string { return w.Topic }
+	commitMessage.Topic = dominant(commitMessage.Breakdown, topic)
+	commitMessage.Item = heaviest(commitMessage.Breakdown, topic, commitMessage.Topic).Item
+	commitMessage.Purpose = dominant(commitMessage.Breakdown, func(w FileWeight) string {
+		if w.Purpose == "general update" {
+			return ""
+		}
+		return w.Purpose
+	})
+	if commitMessage.Purpose == "" {
+		commitMessage.Purpose = "general update"
+	}
 
 	// Enhanced scope detection for multiple modules
 	if len(a.changes) > 1 {
@@ -153,6 +275,9 @@ func (a *Analyzer) AnalyzeChanges(totalAdded, totalRemoved int, branchName strin
 	newDeps := a.detectNewDependencies()
 	if len(newDeps) > 0 {
 		commitMessage.Action = "chore"
+		commitMessage.ActionReasons = []string{"new dependencies"}
+		commitMessage.Alternatives = nil
+		a.note("dependencies", "new dependencies %s make it chore(deps)", strings.Join(newDeps, ", "))
 		commitMessage.Scope = "deps"
 		commitMessage.Item = strings.Join(newDeps, ", ")
 		commitMessage.Purpose = "update dependencies"
@@ -160,7 +285,7 @@ func (a *Analyzer) AnalyzeChanges(totalAdded, totalRemoved int, branchName strin
 	}
 
 	// NEW: Learning from recent commit history (Commit History Consistency)
-	if historyScope := a.analyzeHistoryScopes(); historyScope != "" {
+	if historyScope := a.analyzeHistoryScopes(ctx); historyScope != "" {
 		// Only override if scope is empty or "core"
 		if commitMessage.Scope == "" || commitMessage.Scope == "core" {
 			commitMessage.Scope = historyScope
@@ -170,7 +295,7 @@ func (a *Analyzer) AnalyzeChanges(totalAdded, totalRemoved int, branchName strin
 	// Use commit history context to suggest consistent topics
 	if commitMessage.Topic == "" || commitMessage.Topic == "core" {
 		// Try to get topic from recent commit history
-		if recentTopic := a.getRecentCommitTopic(); recentTopic != "" {
+		if recentTopic := a.getRecentCommitTopic(ctx); recentTopic != "" {
 			commitMessage.Topic = recentTopic
 		}
 	}
@@ -178,28 +303,21 @@ func (a *Analyzer) AnalyzeChanges(totalAdded, totalRemoved int, branchName strin
 	return commitMessage
 }
 
-// calculateKeywordScores analyzes git diff content and returns a map of scores for each action
+// calculateKeywordScores adds up the keywords found in the diff of each file
+// into a score for each action
 func (a *Analyzer) calculateKeywordScores() map[string]int {
 	actionScores := make(map[string]int)
 	if len(a.config.Keywords) == 0 {
 		return actionScores
 	}
 
-	// Concatenate all diffs
-	var allDiffs strings.Builder
-	for _, change := range a.changes {
-		allDiffs.WriteString(change.Diff)
-		allDiffs.WriteString("\n")
-	}
-	diffContent := strings.ToLower(allDiffs.String())
-
 	for action, keywords := range a.config.Keywords {
 		score := 0
-		for keyword, weight := range keywords {
-			keywordLower := strings.ToLower(keyword)
-			// Count occurrences and multiply by weight
-			occurrences := strings.Count(diffContent, keywordLower)
-			score += occurrences * weight
+		for _, file := range a.files {
+			for keyword, weight := range keywords {
+				// Count occurrences and multiply by weight
+				score += file.keywordHits[action][keyword] * weight
+			}
 		}
 		actionScores[action] = score
 	}
@@ -220,9 +338,9 @@ func (a *Analyzer) detectIntelligentScope() string {
 		topic := a.determineTopic(change.File)
 		topics[topic]++
 
-		dir := filepath.Dir(change.File)
+		dir := path.Dir(change.File)
 		if dir != "." {
-			parts := strings.Split(dir, string(filepath.Separator))
+			parts := strings.Split(dir, "/")
 			if len(parts) > 0 {
 				// Count first-level directory
 				directories[parts[0]]++
@@ -265,7 +383,7 @@ func (a *Analyzer) detectIntelligentScope() string {
 	maxCount := 0
 	mostCommonTopic := "core"
 	for topic, count := range topics {
-		if count > maxCount {
+		if count > maxCount || (count == maxCount && topic < mostCommonTopic) {
 			maxCount = count
 			mostCommonTopic = topic
 		}
@@ -299,7 +417,7 @@ func (a *Analyzer) detectMultiFilePatterns() []string {
 			deletedFiles++
 		}
 
-		if strings.HasSuffix(change.File, "_test.go") {
+		if a.isTestFile(change.File) {
 			testFiles++
 		}
 
@@ -392,7 +510,7 @@ func (a *Analyzer) determineAction(change *parser.Change) string {
 	switch change.Action {
 	case "A":
 		// Enhanced rule: detect added tests
-		if strings.HasSuffix(change.File, "_test.go") {
+		if a.isTestFile(change.File) {
 			return "test"
 		}
 		// Detect new API endpoints
@@ -433,7 +551,7 @@ func (a *Analyzer) determineAction(change *parser.Change) string {
 		}
 
 		// Check for test updates
-		if strings.HasSuffix(change.File, "_test.go") {
+		if a.isTestFile(change.File) {
 			return "test"
 		}
 
@@ -454,15 +572,20 @@ func (a *Analyzer) determineAction(change *parser.Change) string {
 	}
 }
 
-func (a *Analyzer) determineTopic(path string) string {
-	// Apply custom topic mappings from config first
-	for pattern, topic := range a.config.TopicMappings {
-		if strings.Contains(path, pattern) {
-			return topic
+func (a *Analyzer) determineTopic(file string) string {
+	// Apply custom topic mappings from config first, the most specific first
+	for _, pattern := range specificFirst(a.config.TopicMappings) {
+		if strings.Contains(file, pattern) {
+			return a.config.TopicMappings[pattern]
 		}
 	}
 
-	parts := strings.Split(filepath.Dir(path), string(filepath.Separator))
+	// Special files such as a Dockerfile or CI workflows have a scope of their own
+	if special := SpecialFileOf(file); special != nil {
+		return special.Scope
+	}
+
+	parts := strings.Split(path.Dir(file), "/")
 	if len(parts) > 0 {
 		// Prioritize "internal" or "pkg" subdirectories
 		for i, p := range parts {
@@ -484,128 +607,111 @@ func (a *Analyzer) determineTopic(path string) string {
 	return "core"
 }
 
-func (a *Analyzer) determineItem(path string) string {
-	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+func (a *Analyzer) determineItem(file string) string {
+	return strings.TrimSuffix(path.Base(file), path.Ext(file))
 }
 
+// removalWeight is how many times more lines a file must lose than gain for
+// the purpose of its removed lines to win over that of its added lines
+const removalWeight = 2
+
+// determinePurpose returns the purpose of the changes of a file from the words
+// of its added lines. Words of removed lines only count when the file mostly
+// loses lines or nothing added has a purpose, as in "remove logging", so
+// deleting logging code is not taken for adding it.
 func (a *Analyzer) determinePurpose(diff string) string {
-	// Apply custom keyword mappings from config
-	for keyword, purpose := range a.config.KeywordMappings {
-		if strings.Contains(strings.ToLower(diff), strings.ToLower(keyword)) {
+	var added, removed strings.Builder
+	addedLines, removedLines := 0, 0
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			added.WriteString(line[1:] + "\n")
+			addedLines++
+		case strings.HasPrefix(line, "-"):
+			removed.WriteString(line[1:] + "\n")
+			removedLines++
+		}
+	}
+	// Text without diff markers is matched whole
+	if addedLines == 0 && removedLines == 0 {
+		if purpose := a.matchPurpose(diff); purpose != "" {
 			return purpose
 		}
+		return "general update"
 	}
 
-	keywords := map[string]string{
-		"login":       "authentication",
-		"auth":        "authentication",
-		"user":        "user management",
-		"validate":    "validation",
-		"validation":  "validation",
-		"query":       "database query",
-		"database":    "database operations",
-		"cache":       "caching",
-		"caching":     "caching",
-		"refactor":    "code restructuring",
-		"logging":     "logging",
-		"logger":      "logging",
-		"docs":        "documentation",
-		"readme":      "documentation",
-		"middleware":  "middleware",
-		"test":        "testing",
-		"tests":       "testing",
-		"config":      "configuration",
-		"ci":          "ci/cd",
-		"log":         "logging",
-		"sql":         "database logic",
-		"gorm":        "database logic",
-		"feat":        "new feature",
-		"bug":         "bug fix",
-		"fix":         "bug fix",
-		"hotfix":      "bug fix",
-		"cleanup":     "code cleanup",
-		"perf":        "performance improvement",
-		"performance": "performance improvement",
-		"security":    "security update",
-		"dep":         "dependency update",
-		"dependency":  "dependency update",
-		"build":       "build system",
-		"style":       "code style",
-		"serialize":   "serialization",
-		"deserialize": "deserialization",
-		"json":        "data handling",
-		"xml":         "data handling",
-		"async":       "asynchronous operations",
-		"await":       "asynchronous operations",
-		"concurrent":  "concurrency",
-		"parallel":    "parallel processing",
-		"api":         "api endpoints",
-		"endpoint":    "api endpoints",
-		"route":       "routing",
-		"ui":          "user interface",
-		"frontend":    "user interface",
-		"backend":     "backend logic",
-		"server":      "server logic",
-		"client":      "client logic",
-		"docker":      "docker configuration",
-		"kubernetes":  "kubernetes configuration",
-		"k8s":         "kubernetes configuration",
-		"aws":         "aws integration",
-		"gcp":         "gcp integration",
-		"azure":       "azure integration",
-		"error":       "error handling",
-		"exception":   "error handling",
-	}
-
-	for keyword, purpose := range keywords {
-		if strings.Contains(strings.ToLower(diff), keyword) {
-			return purpose
-		}
+	addedPurpose := a.matchPurpose(added.String())
+	if addedPurpose != "" && removedLines <= removalWeight*addedLines {
+		return addedPurpose
+	}
+	if removedPurpose := a.matchPurpose(removed.String()); removedPurpose != "" && removedPurpose != addedPurpose {
+		return "remove " + removedPurpose
+	}
+	if addedPurpose != "" {
+		return addedPurpose
 	}
 	return "general update"
 }
 
-func (a *Analyzer) applySmartFallback(msg *CommitMessage) *CommitMessage {
-	// If a new file is created, suggest "feat"
-	if len(a.changes) == 1 && a.changes[0].Action == "A" {
-		return &CommitMessage{Action: "feat", Topic: a.determineTopic(a.changes[0].File), Item: a.determineItem(a.changes[0].File), Purpose: "initial implementation"}
-	}
-
-	// If a file is deleted, suggest "chore" or "refactor"
-	if len(a.changes) == 1 && a.changes[0].Action == "D" {
-		return &CommitMessage{Action: "chore", Topic: a.determineTopic(a.changes[0].File), Item: a.determineItem(a.changes[0].File), Purpose: "remove unused file"}
+// matchPurpose returns the purpose of the first keyword found in text: from
+// the configured keyword mappings, then the vocabulary of the project's
+// language, then the generic one, the most specific first. It returns "" when
+// no keyword is found.
+func (a *Analyzer) matchPurpose(text string) string {
+	text = strings.ToLower(text)
+	vocabularies := append([]map[string]string{a.config.KeywordMappings}, a.config.PurposeVocabularies()...)
+	for _, vocabulary := range vocabularies {
+		for _, keyword := range specificFirst(vocabulary) {
+			if strings.Contains(text, strings.ToLower(keyword)) {
+				return vocabulary[keyword]
+			}
+		}
 	}
+	return ""
+}
 
-	// If a test file is modified, suggest "test"
-	if len(a.changes) == 1 && strings.HasSuffix(a.changes[0].File, "_test.go") {
-		return &CommitMessage{Action: "test", Topic: a.determineTopic(a.changes[0].File), Item: a.determineItem(a.changes[0].File), Purpose: "update tests"}
-	}
+// specificFirst returns the keys of a mapping longest first, so a diff
+// mentioning "validation" is not taken for "ci", and lexically among equals,
+// so the same diff always gets the same purpose
+func specificFirst(mapping map[string]string) []string {
+	keys := make([]string, 0, len(mapping))
+	for key := range mapping {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
 
-	// If more than 5 files are both added and deleted -> suggest “refactor(core): restructure project”.
-	if len(a.changes) > 5 && msg.TotalAdded > 0 && msg.TotalRemoved > 0 && (float64(msg.TotalAdded+msg.TotalRemoved)/float64(len(a.changes))) > 10 { // Heuristic for significant changes across many files
-		return &CommitMessage{Action: "refactor", Topic: "core", Purpose: "restructure project"}
+func (a *Analyzer) applySmartFallback(msg *CommitMessage) *CommitMessage {
+	// If the changes only change whitespace -> style(parser): fix whitespace in git.go
+	if IsWhitespaceOnly(a.changes) {
+		return a.whitespaceMessage(msg.Breakdown)
 	}
 
-	// If .env, .yml, or Dockerfile is changed -> use ci(config): update build configuration.
-	for _, ext := range msg.FileExtensions {
-		if ext == "env" || ext == "yml" || ext == "yaml" || ext == "Dockerfile" {
-			return &CommitMessage{Action: "ci", Topic: "config", Purpose: "update build configuration"}
-		}
+	// If the changes only bump a version field -> chore(release): v1.2.3
+	if version := DetectVersionBump(a.changes); version != "" {
+		return &CommitMessage{Action: "chore", Topic: "release", Scope: "release", Item: version, Purpose: "release " + version, Version: version}
 	}
 
-	// If only markdown or documentation files changed -> use docs: update documentation.
-	if msg.IsDocsOnly {
-		return &CommitMessage{Action: "docs", Topic: "", Purpose: "update documentation"}
+	// If the changes mostly move a directory -> refactor: move parser package under internal/
+	if move := DetectDirectoryMove(a.changes); move != nil {
+		return &CommitMessage{Action: "refactor", Topic: a.determineTopic(move.Target + "/"), Item: path.Base(move.Source), Purpose: move.Phrase(), RenamedFiles: msg.RenamedFiles, Move: move}
 	}
 
-	// If dependencies changed in go.mod -> use chore(deps): update dependencies.
-	for _, change := range a.changes {
-		if change.File == "go.mod" {
-			return &CommitMessage{Action: "chore", Topic: "deps", Purpose: "update dependencies"}
-		}
+	// If only special files such as a Dockerfile, a Makefile or CI workflows
+	// changed -> ci(github): update ci.yml or build(docker): update Dockerfile
+	if special, file := a.specialChanges(msg.Breakdown); special != nil {
+		return &CommitMessage{Action: special.Type, Topic: special.Scope, Scope: special.Scope, Item: path.Base(file), Purpose: special.Purpose}
 	}
 
-	return nil
+	// Configured rules, such as a single new file -> feat or go.mod -> chore(deps)
+	return a.applyFallbackRules(msg)
 }
 
 func (a *Analyzer) isDocsOnly() bool {
@@ -625,7 +731,7 @@ func (a *Analyzer) isConfigOnly() bool {
 		return false
 	}
 	for _, change := range a.changes {
-		if !strings.Contains(change.File, "config") && change.FileExtension != "json" && change.FileExtension != "yaml" && change.FileExtension != "yml" && change.FileExtension != "env" && change.File != "Dockerfile" {
+		if !strings.Contains(change.File, "config") && change.FileExtension != "json" && change.FileExtension != "yaml" && change.FileExtension != "yml" && change.FileExtension != "env" && SpecialFileOf(change.File) == nil {
 			return false
 		}
 	}
@@ -726,20 +832,28 @@ func uniqueStrings(s []string) []string {
 	return result
 }
 
+// functionPatterns is the regex registry for function declarations, compiled
+// once for all files and tried in order
+var functionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`func\s+(?:\([^)]*\)\s+)?([A-Z][A-Za-z0-9]*)`),                                                // Go
+	regexp.MustCompile(`(?:function\s+([a-zA-Z0-9]*)|const\s+([a-zA-Z0-9]*)\s*=\s*(?:\([^)]*\)|[a-zA-Z0-9]*)\s*=>)`), // TypeScript and JavaScript
+	regexp.MustCompile(`def\s+([a-zA-Z0-9_]+)\s*\(`),                                                                 // Python
+	regexp.MustCompile(`(?:public|private|protected|static)\s+(?:[\w<>[\]]+\s+)+([a-zA-Z0-9_]+)\s*\(`),               // Java
+}
+
+// structPatterns is the regex registry for struct and class declarations
+var structPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`type\s+([A-Z][A-Za-z0-9]*)\s+(?:struct|interface)`),                // Go
+	regexp.MustCompile(`class\s+([a-zA-Z0-9]*)`),                                           // TypeScript and JavaScript
+	regexp.MustCompile(`class\s+([a-zA-Z0-9_]+)\s*(?:\(|:)`),                               // Python
+	regexp.MustCompile(`(?:public|private|protected|abstract)?\s*class\s+([a-zA-Z0-9_]+)`), // Java
+}
+
 // detectFunctions extracts function names from diff using language-aware regex
 func (a *Analyzer) detectFunctions(diff string) []string {
 	var functions []string
 	scanner := bufio.NewScanner(strings.NewReader(diff))
 
-	// Regex registry for functions
-	patterns := map[string]*regexp.Regexp{
-		"go":     regexp.MustCompile(`func\s+(?:\([^)]*\)\s+)?([A-Z][A-Za-z0-9]*)`),
-		"ts":     regexp.MustCompile(`(?:function\s+([a-zA-Z0-9]*)|const\s+([a-zA-Z0-9]*)\s*=\s*(?:\([^)]*\)|[a-zA-Z0-9]*)\s*=>)`),
-		"js":     regexp.MustCompile(`(?:function\s+([a-zA-Z0-9]*)|const\s+([a-zA-Z0-9]*)\s*=\s*(?:\([^)]*\)|[a-zA-Z0-9]*)\s*=>)`),
-		"python": regexp.MustCompile(`def\s+([a-zA-Z0-9_]+)\s*\(`),
-		"java":   regexp.MustCompile(`(?:public|private|protected|static)\s+(?:[\w<>[\]]+\s+)+([a-zA-Z0-9_]+)\s*\(`),
-	}
-
 	for scanner.Scan() {
 		line := scanner.Text()
 		if !strings.HasPrefix(line, "+") || strings.HasPrefix(line, "+++") {
@@ -748,7 +862,7 @@ func (a *Analyzer) detectFunctions(diff string) []string {
 
 		cleanLine := strings.TrimPrefix(line, "+")
 
-		for _, re := range patterns {
+		for _, re := range functionPatterns {
 			matches := re.FindStringSubmatch(cleanLine)
 			if len(matches) > 0 {
 				// The first captured group (that is not empty) is the function name
@@ -769,15 +883,6 @@ func (a *Analyzer) detectStructs(diff string) []string {
 	var structs []string
 	scanner := bufio.NewScanner(strings.NewReader(diff))
 
-	// Regex registry for structs/classes
-	patterns := map[string]*regexp.Regexp{
-		"go":     regexp.MustCompile(`type\s+([A-Z][A-Za-z0-9]*)\s+(?:struct|interface)`),
-		"ts":     regexp.MustCompile(`class\s+([a-zA-Z0-9]*)`),
-		"js":     regexp.MustCompile(`class\s+([a-zA-Z0-9]*)`),
-		"python": regexp.MustCompile(`class\s+([a-zA-Z0-9_]+)\s*(?:\(|:)`),
-		"java":   regexp.MustCompile(`(?:public|private|protected|abstract)?\s*class\s+([a-zA-Z0-9_]+)`),
-	}
-
 	for scanner.Scan() {
 		line := scanner.Text()
 		if !strings.HasPrefix(line, "+") || strings.HasPrefix(line, "+++") {
@@ -786,7 +891,7 @@ func (a *Analyzer) detectStructs(diff string) []string {
 
 		cleanLine := strings.TrimPrefix(line, "+")
 
-		for _, re := range patterns {
+		for _, re := range structPatterns {
 			matches := re.FindStringSubmatch(cleanLine)
 			if len(matches) > 1 && matches[1] != "" {
 				structs = append(structs, matches[1])
@@ -836,8 +941,8 @@ func (a *Analyzer) detectChangePatterns(change *parser.Change) []string {
 	}
 
 	// Detect test additions
-	if strings.HasSuffix(change.File, "_test.go") {
-		if strings.Contains(diff, "+func Test") {
+	if a.isTestFile(change.File) {
+		if addsTestDeclaration(diff) {
 			patterns = append(patterns, "test-addition")
 		}
 	}
@@ -949,8 +1054,8 @@ func (a *Analyzer) detectChangePatterns(change *parser.Change) []string {
 
 // getRecentCommitTopic retrieves the topic/scope from the most recent commit
 // This helps maintain consistency in commit history
-func (a *Analyzer) getRecentCommitTopic() string {
-	_, scope, err := history.GetRecentCommitContext()
+func (a *Analyzer) getRecentCommitTopic(ctx context.Context) string {
+	_, scope, err := history.GetRecentCommitContext(ctx)
 	if err != nil || scope == "" {
 		return ""
 	}
@@ -1003,13 +1108,13 @@ func (a *Analyzer) detectNewDependencies() []string {
 	var newDeps []string
 	depFiles := map[string]*regexp.Regexp{
 		"go.mod":           regexp.MustCompile(`^\+\s+([^\s]+)\s+v`),
-		"package.json":    regexp.MustCompile(`^\+\s+"([^"]+)":`),
+		"package.json":     regexp.MustCompile(`^\+\s+"([^"]+)":`),
 		"requirements.txt": regexp.MustCompile(`^\+([a-zA-Z0-9\-_]+)==`),
-		"Cargo.toml":      regexp.MustCompile(`^\+([a-zA-Z0-9\-_]+)\s+=`),
+		"Cargo.toml":       regexp.MustCompile(`^\+([a-zA-Z0-9\-_]+)\s+=`),
 	}
 
 	for _, change := range a.changes {
-		fileName := filepath.Base(change.File)
+		fileName := path.Base(change.File)
 		if re, ok := depFiles[fileName]; ok {
 			scanner := bufio.NewScanner(strings.NewReader(change.Diff))
 			for scanner.Scan() {
@@ -1038,31 +1143,7 @@ func (a *Analyzer) parseBranchName(branch string) (string, string) {
 	branchType := strings.ToLower(parts[0])
 	description := parts[1]
 
-	action := ""
-	switch branchType {
-	case "feature", "feat":
-		action = "feat"
-	case "bugfix", "fix":
-		action = "fix"
-	case "hotfix":
-		action = "fix"
-	case "refactor":
-		action = "refactor"
-	case "chore":
-		action = "chore"
-	case "docs":
-		action = "docs"
-	case "style":
-		action = "style"
-	case "perf":
-		action = "perf"
-	case "test":
-		action = "test"
-	case "ci":
-		action = "ci"
-	case "build":
-		action = "build"
-	}
+	action := branchTypes[branchType]
 
 	scope := ""
 	// Try to extract scope from description: scope-description or scope_description
@@ -1078,8 +1159,8 @@ func (a *Analyzer) parseBranchName(branch string) (string, string) {
 }
 
 // analyzeHistoryScopes analyzes the last 5 commits for common scopes
-func (a *Analyzer) analyzeHistoryScopes() string {
-	commits, err := history.GetRecentCommits(5)
+func (a *Analyzer) analyzeHistoryScopes(ctx context.Context) string {
+	commits, err := history.GetRecentCommits(ctx, 5)
 	if err != nil || len(commits) == 0 {
 		return ""
 	}
@@ -1088,12 +1169,12 @@ func (a *Analyzer) analyzeHistoryScopes() string {
 
 // calculateAdditiveAction implements the legacy additive scoring logic
 func (a *Analyzer) calculateAdditiveAction(totalAdded, totalRemoved int, branchName string, commitMessage *CommitMessage) string {
-	scoreMap := make(map[string]int)
+	scores := newActionScores()
 
 	if branchName != "" {
 		branchAction, branchScope := a.parseBranchName(branchName)
 		if branchAction != "" {
-			scoreMap[branchAction] += 3
+			scores.add(branchAction, 3, "branch "+branchName)
 		}
 		if branchScope != "" {
 			commitMessage.Scope = branchScope
@@ -1102,41 +1183,51 @@ func (a *Analyzer) calculateAdditiveAction(totalAdded, totalRemoved int, branchN
 
 	statAction := a.analyzeDiffStat(totalAdded, totalRemoved)
 	if statAction != "" {
-		scoreMap[statAction] += 2
+		scores.add(statAction, 2, diffStatReason(totalAdded, totalRemoved))
 	}
 
 	keywordScores := a.calculateKeywordScores()
 	for action, score := range keywordScores {
-		scoreMap[action] += score
+		scores.add(action, float64(score), fmt.Sprintf("%s (%d)", a.keywordReason(action), score))
 	}
 
 	multiPatterns := a.detectMultiFilePatterns()
 	for _, p := range multiPatterns {
 		switch p {
 		case "feature-addition":
-			scoreMap["feat"] += 4
+			scores.add("feat", 4, patternReason(p))
 		case "bug-fix-cascade":
-			scoreMap["fix"] += 4
+			scores.add("fix", 4, patternReason(p))
 		case "refactor-sweep":
-			scoreMap["refactor"] += 3
+			scores.add("refactor", 3, patternReason(p))
 		case "test-suite-update":
-			scoreMap["test"] += 4
+			scores.add("test", 4, patternReason(p))
 		}
 	}
 
-	bestAction := ""
-	maxScore := -1
-	for action, score := range scoreMap {
-		if score > maxScore {
-			maxScore = score
-			bestAction = action
-		}
-	}
-
-	if bestAction != "" {
+	a.noteScores(scores)
+	if bestAction, _ := scores.best(); bestAction != "" {
+		commitMessage.ActionReasons = scores.reasons[bestAction]
+		commitMessage.Alternatives = scores.alternatives(bestAction)
 		return bestAction
 	}
-	return a.determineAction(a.changes[0])
+	return a.fallbackAction(commitMessage)
+}
+
+// fallbackAction classifies the changes by the actions their files suggest on
+// their own, weighted, when no signal points to an action
+func (a *Analyzer) fallbackAction(commitMessage *CommitMessage) string {
+	weights := commitMessage.Breakdown
+	if len(weights) == 0 {
+		weights = a.fileWeights()
+	}
+	byAction := func(w FileWeight) string { return w.Action }
+	action := dominant(weights, byAction)
+	main := heaviest(weights, byAction, action)
+	change := a.changes[main.index]
+	commitMessage.ActionReasons = []string{"kind of change to " + change.File}
+	a.note("fallback", "no signal strong enough; %s %s → %s", actionVerbs[change.Action], change.File, action)
+	return action
 }
 
 // calculateNormalizedAction implements the new weighted average scoring logic
@@ -1197,24 +1288,23 @@ func (a *Analyzer) calculateNormalizedAction(totalAdded, totalRemoved int, branc
 	}
 
 	// 4. Multi-file pattern signal (binary: 0 or 1)
-	multiPatterns := a.detectMultiFilePatterns()
-	for _, p := range multiPatterns {
-		switch p {
-		case "feature-addition":
-			signals["patterns"]["feat"] = 1.0
-		case "bug-fix-cascade":
-			signals["patterns"]["fix"] = 1.0
-		case "refactor-sweep":
-			signals["patterns"]["refactor"] = 1.0
-		case "test-suite-update":
-			signals["patterns"]["test"] = 1.0
-		case "config-update":
-			signals["patterns"]["ci"] = 1.0
+	patternActions := map[string]string{
+		"feature-addition":  "feat",
+		"bug-fix-cascade":   "fix",
+		"refactor-sweep":    "refactor",
+		"test-suite-update": "test",
+		"config-update":     "ci",
+	}
+	patternReasons := make(map[string]string)
+	for _, p := range a.detectMultiFilePatterns() {
+		if action, ok := patternActions[p]; ok {
+			signals["patterns"][action] = 1.0
+			patternReasons[action] = patternReason(p)
 		}
 	}
 
 	// Compute final weighted scores
-	finalScores := make(map[string]float64)
+	finalScores := newActionScores()
 	weights := a.config.SignalWeights
 	if weights == nil {
 		weights = map[string]float64{
@@ -1236,24 +1326,25 @@ func (a *Analyzer) calculateNormalizedAction(totalAdded, totalRemoved int, branc
 	maxFinalScore := -1.0
 
 	for action := range allActions {
-		score := 0.0
-		score += signals["branch"][action] * weights["branch"]
-		score += signals["diffStat"][action] * weights["diffStat"]
-		score += signals["keywords"][action] * weights["keywords"]
-		score += signals["patterns"][action] * weights["patterns"]
-		finalScores[action] = score
-
-		if score > maxFinalScore {
-			maxFinalScore = score
-			bestAction = action
-		}
+		finalScores.add(action, signals["branch"][action]*weights["branch"], "branch "+branchName)
+		finalScores.add(action, signals["diffStat"][action]*weights["diffStat"], diffStatReason(totalAdded, totalRemoved))
+		finalScores.add(action, signals["keywords"][action]*weights["keywords"], a.keywordReason(action))
+		finalScores.add(action, signals["patterns"][action]*weights["patterns"], patternReasons[action])
+	}
+	a.noteScores(finalScores)
+	if action, score := finalScores.best(); action != "" {
+		bestAction, maxFinalScore = action, score
 	}
 
 	// Fallback: If top action score is too low, use file-based heuristics
 	if maxFinalScore < 0.35 {
-		return a.determineAction(a.changes[0])
+		action := a.fallbackAction(commitMessage)
+		commitMessage.Alternatives = finalScores.alternatives(action)
+		return action
 	}
 
+	commitMessage.ActionReasons = finalScores.reasons[bestAction]
+	commitMessage.Alternatives = finalScores.alternatives(bestAction)
 	return bestAction
 }
 
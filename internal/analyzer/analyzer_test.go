@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"context"
 	"github.com/andev0x/gitmit/internal/config"
 	"github.com/andev0x/gitmit/internal/parser"
 	"testing"
@@ -245,7 +246,7 @@ func TestCrossScoringMatrix(t *testing.T) {
 		// branch "feat/new-ui" adds 3 to feat
 		// "error" keyword adds 4 to fix
 		// fix (4) > feat (3) -> fix
-		msg := a.AnalyzeChanges(1, 0, "feat/new-ui")
+		msg := a.AnalyzeChanges(context.Background(), 1, 0, "feat/new-ui")
 		if msg.Action != "fix" {
 			t.Errorf("Expected action fix, got %s", msg.Action)
 		}
@@ -261,9 +262,100 @@ func TestCrossScoringMatrix(t *testing.T) {
 		// branch "feature/cool" adds 3 to feat
 		// ratio 1.0 adds 2 to feat (added > 30)
 		// total feat = 5
-		msg := a.AnalyzeChanges(40, 0, "feature/cool")
+		msg := a.AnalyzeChanges(context.Background(), 40, 0, "feature/cool")
 		if msg.Action != "feat" {
 			t.Errorf("Expected action feat, got %s", msg.Action)
 		}
 	})
 }
+
+func TestBranchPolicyForcesType(t *testing.T) {
+	cfg := &config.Config{
+		Keywords: map[string]map[string]int{
+			"feat": {"func": 4},
+		},
+		BranchPolicies: []config.BranchPolicy{{Pattern: "hotfix/*", Type: "fix"}},
+	}
+	a := &Analyzer{
+		config: cfg,
+		changes: []*parser.Change{
+			{File: "auth.go", Diff: "+ func Login() {", Added: 40, Removed: 0},
+		},
+	}
+
+	msg := a.AnalyzeChanges(context.Background(), 40, 0, "hotfix/login-crash")
+	if msg.Action != "fix" {
+		t.Errorf("Expected action fix on hotfix branch, got %s", msg.Action)
+	}
+}
+
+func TestDetermineTopicAndItem(t *testing.T) {
+	a := NewAnalyzer(nil, config.DefaultConfig())
+	tests := []struct {
+		file  string
+		topic string
+		item  string
+	}{
+		{"internal/analyzer/analyzer.go", "analyzer", "analyzer"},
+		{"pkg/auth/token/jwt.go", "auth", "jwt"},
+		{"web/src/components/Button.tsx", "components", "Button"},
+		{"src/index.ts", "src", "index"},
+		{"README.md", "core", "README"},
+	}
+	for _, tt := range tests {
+		if got := a.determineTopic(tt.file); got != tt.topic {
+			t.Errorf("determineTopic(%q) = %q, want %q", tt.file, got, tt.topic)
+		}
+		if got := a.determineItem(tt.file); got != tt.item {
+			t.Errorf("determineItem(%q) = %q, want %q", tt.file, got, tt.item)
+		}
+	}
+}
+
+func TestDeterminePurposePrefersSpecificKeywords(t *testing.T) {
+	a := &Analyzer{config: &config.Config{KeywordMappings: map[string]string{"api": "api work", "api client": "client work"}}}
+	for i := 0; i < 20; i++ {
+		if got := a.determinePurpose("+ // the api client retries"); got != "client work" {
+			t.Fatalf("determinePurpose() = %q, want the longer mapping", got)
+		}
+		if got := (&Analyzer{config: &config.Config{}}).determinePurpose("+ func deserialize(b []byte)"); got != "deserialization" {
+			t.Fatalf("determinePurpose() = %q, want deserialization over serialize", got)
+		}
+	}
+}
+
+func TestDeterminePurposeFromAddedLines(t *testing.T) {
+	a := &Analyzer{config: &config.Config{}}
+	tests := []struct {
+		name string
+		diff string
+		want string
+	}{
+		{"added", "@@ -1,0 +1,2 @@\n+func validate(input string) error {\n+}", "validation"},
+		{"context ignored", "@@ -1,2 +1,3 @@\n logger.Info(\"start\")\n+func validate(input string) error {\n }", "validation"},
+		{"added wins", "@@ -1,2 +1,2 @@\n-logger.Debug(\"x\")\n+cache.Set(key, value)", "caching"},
+		{"mostly removed", "@@ -1,4 +1,1 @@\n-logger.Debug(\"a\")\n-logger.Debug(\"b\")\n-logger.Debug(\"c\")\n+cache.Set(key, value)", "remove logging"},
+		{"only removed", "@@ -1,2 +0,0 @@\n-logger.Debug(\"a\")\n-logger.Debug(\"b\")", "remove logging"},
+		{"file headers ignored", "--- a/cache.go\n+++ b/cache.go\n@@ -1 +1 @@\n-x := 1\n+x := 2", "general update"},
+	}
+	for _, tt := range tests {
+		if got := a.determinePurpose(tt.diff); got != tt.want {
+			t.Errorf("%s: determinePurpose() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDeterminePurposeUsesLanguageVocabulary(t *testing.T) {
+	diff := "+class AddIndexToUsers(migrations.Migration):\n+    database = 'default'"
+	if got := (&Analyzer{config: &config.Config{ProjectType: "python"}}).determinePurpose(diff); got != "database migration" {
+		t.Errorf("determinePurpose() = %q, want the python purpose database migration", got)
+	}
+	if got := (&Analyzer{config: &config.Config{ProjectType: "go"}}).determinePurpose(diff); got != "database operations" {
+		t.Errorf("determinePurpose() = %q, want the generic purpose database operations", got)
+	}
+
+	cfg := &config.Config{ProjectType: "python", Languages: map[string]config.LanguageConfig{"python": {Purposes: map[string]string{"migration": "schema change"}}}}
+	if got := (&Analyzer{config: cfg}).determinePurpose(diff); got != "schema change" {
+		t.Errorf("determinePurpose() = %q, want the configured purpose", got)
+	}
+}
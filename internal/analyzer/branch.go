@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// branchTypes maps branch name prefixes to conventional commit types
+var branchTypes = map[string]string{
+	"feature":  "feat",
+	"feat":     "feat",
+	"bugfix":   "fix",
+	"fix":      "fix",
+	"hotfix":   "fix",
+	"refactor": "refactor",
+	"chore":    "chore",
+	"docs":     "docs",
+	"style":    "style",
+	"perf":     "perf",
+	"test":     "test",
+	"ci":       "ci",
+	"build":    "build",
+}
+
+// branchTicketRegex matches a ticket at the start of a branch description, e.g. ABC-12-
+var branchTicketRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*-[0-9]+([-_]|$)`)
+
+// BranchDescription reads a branch named like feature/ABC-12-add-user-export as
+// a commit type and description, here feat and "add user export". Both are empty
+// unless the branch has a known type prefix and a description of two words or more.
+func BranchDescription(branch string) (commitType, description string) {
+	prefix, rest, ok := strings.Cut(branch, "/")
+	if !ok {
+		return "", ""
+	}
+	commitType = branchTypes[strings.ToLower(prefix)]
+	if commitType == "" {
+		return "", ""
+	}
+
+	rest = branchTicketRegex.ReplaceAllString(rest[strings.LastIndex(rest, "/")+1:], "")
+	words := strings.FieldsFunc(strings.ToLower(rest), func(r rune) bool {
+		return r == '-' || r == '_' || r == ' '
+	})
+	if len(words) < 2 {
+		return "", ""
+	}
+	return commitType, strings.Join(words, " ")
+}
@@ -0,0 +1,22 @@
+package analyzer
+
+import "testing"
+
+func TestBranchDescription(t *testing.T) {
+	tests := []struct {
+		branch, commitType, description string
+	}{
+		{"feature/ABC-12-add-user-export", "feat", "add user export"},
+		{"bugfix/handle_nil_user", "fix", "handle nil user"},
+		{"hotfix/api/retry-on-timeout", "fix", "retry on timeout"},
+		{"feature/auth", "", ""},
+		{"main", "", ""},
+		{"wip/add-stuff", "", ""},
+	}
+	for _, tt := range tests {
+		commitType, description := BranchDescription(tt.branch)
+		if commitType != tt.commitType || description != tt.description {
+			t.Errorf("BranchDescription(%q) = %q, %q, want %q, %q", tt.branch, commitType, description, tt.commitType, tt.description)
+		}
+	}
+}
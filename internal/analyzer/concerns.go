@@ -0,0 +1,107 @@
+package analyzer
+
+import (
+	"path"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+const (
+	// minConcernShare is the share of the weight a concern needs to count
+	// toward mixing concerns, so a one-line README fix does not
+	minConcernShare = 0.1
+
+	// minMixedConcerns is the number of concerns that make changes mix
+	// concerns strongly enough to split them
+	minMixedConcerns = 3
+)
+
+// concernOrder lists the concerns in the order their commits are made when
+// changes are split, so that each commit builds on the ones before
+var concernOrder = []string{"dependencies", "build", "code", "CI", "docs"}
+
+// concernNames are the concerns of commit types; the other types are code
+var concernNames = map[string]string{"chore": "dependencies", "build": "build", "ci": "CI", "docs": "docs"}
+
+// dependencyFiles are the manifests and lock files dependency updates change
+var dependencyFiles = map[string]bool{
+	"go.mod": true, "package.json": true, "Cargo.toml": true, "requirements.txt": true,
+	"Gemfile": true, "composer.json": true, "pyproject.toml": true,
+}
+
+// Concern is a group of changed files of one kind, such as code, docs or CI
+// workflows, which could be committed on its own
+type Concern struct {
+	Name  string   // code, docs, CI, build or dependencies
+	Type  string   // Commit type of the heaviest files
+	Files []string // Changed files, heaviest first
+	Share float64  // Share of the weight of all the changes, from 0 to 1
+}
+
+// fileType returns the commit type of a changed file on its own: what the
+// file holds decides before what the diff says, so a workflow is ci and a
+// README docs whatever their lines mention
+func (a *Analyzer) fileType(change *parser.Change, action string) string {
+	base := path.Base(change.File)
+	switch special := SpecialFileOf(change.File); {
+	case special != nil:
+		return special.Type
+	case a.isTestFile(change.File):
+		return "test"
+	case dependencyFiles[base] || generatedNames[base]:
+		return "chore"
+	case path.Ext(base) == ".md" || path.Ext(base) == ".rst" || path.Ext(base) == ".txt" || strings.HasPrefix(change.File, "docs/"):
+		return "docs"
+	case change.Action == "M" && whitespaceOnlyDiff(change.Diff):
+		return "style"
+	}
+	return action
+}
+
+// Concerns groups the changed files by concern, in the order they would be
+// committed when split, with the commit type of the heaviest files of each
+func Concerns(weights []FileWeight) []Concern {
+	byName := make(map[string]*Concern)
+	types := make(map[string][]FileWeight)
+	for _, w := range weights {
+		name, ok := concernNames[w.Type]
+		if !ok {
+			name = "code"
+		}
+		concern, ok := byName[name]
+		if !ok {
+			concern = &Concern{Name: name}
+			byName[name] = concern
+		}
+		concern.Files = append(concern.Files, w.File)
+		concern.Share += w.Share
+		types[name] = append(types[name], w)
+	}
+
+	var concerns []Concern
+	for _, name := range concernOrder {
+		if concern, ok := byName[name]; ok {
+			concern.Type = dominant(types[name], func(w FileWeight) string { return w.Type })
+			concerns = append(concerns, *concern)
+		}
+	}
+	return concerns
+}
+
+// MixedConcerns returns the concerns of changes that mix at least
+// minMixedConcerns of them, each with a fair share of the weight, such as
+// feature code with unrelated docs and CI workflows, or nil
+func MixedConcerns(weights []FileWeight) []Concern {
+	concerns := Concerns(weights)
+	strong := 0
+	for _, concern := range concerns {
+		if concern.Share >= minConcernShare {
+			strong++
+		}
+	}
+	if strong < minMixedConcerns {
+		return nil
+	}
+	return concerns
+}
@@ -0,0 +1,73 @@
+package analyzer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestFileType(t *testing.T) {
+	a := NewAnalyzer(nil, config.DefaultConfig())
+	tests := []struct {
+		change *parser.Change
+		action string
+		want   string
+	}{
+		{&parser.Change{File: ".github/workflows/ci.yml", Action: "M"}, "perf", "ci"},
+		{&parser.Change{File: "Dockerfile", Action: "M"}, "feat", "build"},
+		{&parser.Change{File: "internal/api/api_test.go", Action: "M"}, "fix", "test"},
+		{&parser.Change{File: "docs/guide.md", Action: "M"}, "docs", "docs"},
+		{&parser.Change{File: "README.md", Action: "A"}, "feat", "docs"},
+		{&parser.Change{File: "go.mod", Action: "M"}, "refactor", "chore"},
+		{&parser.Change{File: "requirements.txt", Action: "M"}, "refactor", "chore"},
+		{&parser.Change{File: "internal/api/api.go", Action: "M", Diff: "@@ -3 +3 @@\n-  return nil\n+\treturn nil\n"}, "fix", "style"},
+		{&parser.Change{File: "internal/api/api.go", Action: "M", Diff: "@@ -3 +3 @@\n-\treturn nil\n+\treturn err\n"}, "fix", "fix"},
+	}
+	for _, tt := range tests {
+		if got := a.fileType(tt.change, tt.action); got != tt.want {
+			t.Errorf("fileType(%s) = %q, want %q", tt.change.File, got, tt.want)
+		}
+	}
+}
+
+func TestMixedConcerns(t *testing.T) {
+	code := FileWeight{File: "internal/api/login.go", Type: "feat", Weight: 40, Share: 0.5}
+	test := FileWeight{File: "internal/api/login_test.go", Type: "test", Weight: 16, Share: 0.2}
+	docs := FileWeight{File: "docs/guide.md", Type: "docs", Weight: 8, Share: 0.1}
+	ci := FileWeight{File: ".github/workflows/ci.yml", Type: "ci", Weight: 16, Share: 0.2}
+	readme := FileWeight{File: "README.md", Type: "docs", Weight: 1, Share: 0.02}
+
+	want := []Concern{
+		{Name: "code", Type: "feat", Files: []string{code.File, test.File}, Share: 0.7},
+		{Name: "CI", Type: "ci", Files: []string{ci.File}, Share: 0.2},
+		{Name: "docs", Type: "docs", Files: []string{docs.File}, Share: 0.1},
+	}
+	if got := MixedConcerns([]FileWeight{code, test, ci, docs}); !reflect.DeepEqual(got, want) {
+		t.Errorf("MixedConcerns() of code, docs and CI = %+v, want %+v", got, want)
+	}
+	if got := MixedConcerns([]FileWeight{code, test, ci, readme}); got != nil {
+		t.Errorf("MixedConcerns() with a small README change = %+v, want nil", got)
+	}
+	if got := MixedConcerns([]FileWeight{code, test, docs}); got != nil {
+		t.Errorf("MixedConcerns() of code with its tests and docs = %+v, want nil", got)
+	}
+}
+
+func TestAnalyzeMixedChanges(t *testing.T) {
+	changes := []*parser.Change{
+		{File: "internal/api/logout.go", Action: "A", Added: 6, Diff: "+// Logout signs a user out\n+func Logout() error {"},
+		{File: "docs/guide.md", Action: "M", Added: 12, Diff: "+Install with brew."},
+		{File: ".github/workflows/ci.yml", Action: "M", Added: 5, Diff: "+      - run: go test ./..."},
+	}
+	msg := NewAnalyzer(changes, config.DefaultConfig()).AnalyzeChanges(context.Background(), 23, 0, "")
+	var names []string
+	for _, concern := range msg.Mixed {
+		names = append(names, concern.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"code", "CI", "docs"}) {
+		t.Errorf("concerns of mixed changes = %v, want code, CI and docs", names)
+	}
+}
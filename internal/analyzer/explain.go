@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+// actionVerbs describes git status letters in explanations
+var actionVerbs = map[string]string{
+	"A": "adds",
+	"M": "modifies",
+	"D": "deletes",
+	"R": "renames",
+	"C": "copies",
+}
+
+// Explain describes in plain sentences what the analyzed changes do: which files
+// they touch and how, where, the code they define and the kind of change they
+// look like. The message is the analysis of the changes, for its scope and owners.
+func (a *Analyzer) Explain(msg *CommitMessage) []string {
+	changes := a.changes
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var sentences []string
+
+	counts := map[string]int{}
+	added, removed := 0, 0
+	var functions, methods, structs, patterns []string
+	for _, change := range changes {
+		counts[change.Action]++
+		added += change.Added
+		removed += change.Removed
+		functions = append(functions, a.detectFunctions(change.Diff)...)
+		methods = append(methods, a.detectMethods(change.Diff)...)
+		structs = append(structs, a.detectStructs(change.Diff)...)
+		patterns = append(patterns, a.detectChangePatterns(change)...)
+	}
+	var parts []string
+	for _, action := range []string{"A", "M", "D", "R", "C"} {
+		if n := counts[action]; n > 0 {
+			noun := "file"
+			if n > 1 {
+				noun = "files"
+			}
+			if len(parts) > 0 {
+				parts = append(parts, fmt.Sprintf("%s %d", actionVerbs[action], n))
+			} else {
+				parts = append(parts, fmt.Sprintf("%s %d %s", actionVerbs[action], n, noun))
+			}
+		}
+	}
+	if len(parts) > 0 {
+		summary := joinWords(parts)
+		sentences = append(sentences, fmt.Sprintf("%s%s (+%d −%d lines).", strings.ToUpper(summary[:1]), summary[1:], added, removed))
+	}
+
+	if msg != nil && msg.Move != nil {
+		from, to := msg.Move.Source+"/", msg.Move.Target+"/"
+		if msg.Move.Source == "" {
+			from = "the repository root"
+		}
+		if msg.Move.Target == "" {
+			to = "the repository root"
+		}
+		sentences = append(sentences, fmt.Sprintf("Moves %d files from %s to %s.", msg.Move.Files, from, to))
+	}
+	for _, change := range changes {
+		if change.IsRename && (msg == nil || msg.Move == nil) {
+			sentences = append(sentences, fmt.Sprintf("Renames %s to %s.", change.Source, change.Target))
+		}
+	}
+
+	switch {
+	case a.isDocsOnly():
+		sentences = append(sentences, "Only documentation changes.")
+	case a.isDepsOnly():
+		sentences = append(sentences, "Only dependencies change.")
+	case a.isConfigOnly():
+		sentences = append(sentences, "Only configuration changes.")
+	}
+
+	if dir := mainDirectory(changes); dir != "" {
+		where := "Most changes are in " + dir
+		if msg != nil && msg.Scope != "" && msg.Scope != path.Base(dir) {
+			where += fmt.Sprintf(" (the %s scope)", msg.Scope)
+		}
+		sentences = append(sentences, where+".")
+	}
+
+	for _, code := range []struct {
+		label string
+		names []string
+	}{
+		{"Functions", uniqueStrings(functions)},
+		{"Methods", uniqueStrings(methods)},
+		{"Types", uniqueStrings(structs)},
+	} {
+		if len(code.names) > 0 {
+			sentences = append(sentences, fmt.Sprintf("%s added or changed: %s.", code.label, strings.Join(limitNames(code.names, 6), ", ")))
+		}
+	}
+
+	if patterns = uniqueStrings(patterns); len(patterns) > 0 {
+		var kinds []string
+		for _, pattern := range patterns {
+			kinds = append(kinds, strings.ReplaceAll(pattern, "-", " "))
+		}
+		sentences = append(sentences, fmt.Sprintf("Touches %s.", joinWords(limitNames(kinds, 5))))
+	}
+
+	if msg != nil && len(msg.Owners) > 0 {
+		sentences = append(sentences, fmt.Sprintf("Owned by %s.", joinWords(msg.Owners)))
+	}
+	return sentences
+}
+
+// mainDirectory returns the directory with the most changed files, or "" when
+// most files are at the root
+func mainDirectory(changes []*parser.Change) string {
+	counts := map[string]int{}
+	for _, change := range changes {
+		counts[path.Dir(change.File)]++
+	}
+	var dirs []string
+	for dir := range counts {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		if counts[dirs[i]] != counts[dirs[j]] {
+			return counts[dirs[i]] > counts[dirs[j]]
+		}
+		return dirs[i] < dirs[j]
+	})
+	if dirs[0] == "." {
+		return ""
+	}
+	return dirs[0]
+}
+
+// limitNames keeps the first n names and counts the others
+func limitNames(names []string, n int) []string {
+	if len(names) <= n {
+		return names
+	}
+	return append(names[:n:n], fmt.Sprintf("%d more", len(names)-n))
+}
+
+// joinWords joins words as in "a, b and c"
+func joinWords(words []string) string {
+	if len(words) <= 1 {
+		return strings.Join(words, "")
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " and " + words[len(words)-1]
+}
@@ -0,0 +1,33 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestExplain(t *testing.T) {
+	changes := []*parser.Change{
+		{File: "internal/auth/login.go", Action: "A", Added: 38, Diff: "+func Login(user string) error {\n+\tif err != nil {\n+\t\treturn err\n+\t}\n"},
+		{File: "internal/auth/session.go", Action: "M", Added: 2, Removed: 3},
+		{File: "internal/auth/token.go", Action: "M"},
+		{File: "internal/auth/token.go", Action: "R", IsRename: true, Source: "internal/auth/jwt.go", Target: "internal/auth/token.go"},
+	}
+	msg := &CommitMessage{Scope: "security"}
+
+	want := []string{
+		"Adds 1 file, modifies 2 and renames 1 (+40 −3 lines).",
+		"Renames internal/auth/jwt.go to internal/auth/token.go.",
+		"Most changes are in internal/auth (the security scope).",
+		"Functions added or changed: Login.",
+		"Touches error handling and validation.",
+	}
+	if got := NewAnalyzer(changes, nil).Explain(msg); !reflect.DeepEqual(got, want) {
+		t.Errorf("Explain() =\n%q\nwant\n%q", got, want)
+	}
+
+	if got := NewAnalyzer(nil, nil).Explain(msg); got != nil {
+		t.Errorf("Explain() without changes = %q, want nil", got)
+	}
+}
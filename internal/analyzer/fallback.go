@@ -0,0 +1,83 @@
+package analyzer
+
+import (
+	"github.com/andev0x/gitmit/internal/codeowners"
+	"github.com/andev0x/gitmit/internal/config"
+)
+
+// fallbackRules returns the configured fallback rules, or the built-in ones
+// when the config has none
+func (a *Analyzer) fallbackRules() []config.FallbackRule {
+	if a.config == nil || a.config.Fallbacks == nil {
+		return config.DefaultFallbackRules
+	}
+	return a.config.Fallbacks
+}
+
+// applyFallbackRules returns the message of the first enabled rule whose
+// conditions the changes meet, or nil
+func (a *Analyzer) applyFallbackRules(msg *CommitMessage) *CommitMessage {
+	for _, rule := range a.fallbackRules() {
+		if rule.Disabled || !a.meetsRule(rule, msg) {
+			continue
+		}
+		heaviest := a.changes[0].File
+		if len(msg.Breakdown) > 0 {
+			heaviest = msg.Breakdown[0].File
+		}
+		result := &CommitMessage{Action: rule.Action, Topic: rule.Topic, Item: rule.Item, Purpose: rule.Purpose, FallbackRule: rule.Name}
+		if result.Topic == "{topic}" {
+			result.Topic = a.determineTopic(heaviest)
+		}
+		if result.Item == "{item}" {
+			result.Item = a.determineItem(heaviest)
+		}
+		return result
+	}
+	return nil
+}
+
+// meetsRule reports whether the changes meet every condition of a rule
+func (a *Analyzer) meetsRule(rule config.FallbackRule, msg *CommitMessage) bool {
+	files := len(a.changes)
+	switch {
+	case files < rule.MinFiles, rule.MaxFiles > 0 && files > rule.MaxFiles:
+		return false
+	case msg.TotalAdded < rule.MinAdded, msg.TotalRemoved < rule.MinRemoved:
+		return false
+	case rule.LinesPerFile > 0 && float64(msg.TotalAdded+msg.TotalRemoved)/float64(files) <= rule.LinesPerFile:
+		return false
+	}
+
+	if rule.Status != "" {
+		for _, change := range a.changes {
+			if change.Action != rule.Status {
+				return false
+			}
+		}
+	}
+
+	if len(rule.Files) == 0 && !rule.Tests {
+		return true
+	}
+	matched := 0
+	for _, change := range a.changes {
+		if rule.Tests && a.isTestFile(change.File) || matchesAny(rule.Files, change.File) {
+			matched++
+		}
+	}
+	if rule.Match == "all" {
+		return matched == files
+	}
+	return matched > 0
+}
+
+// matchesAny reports whether a file matches any of the gitignore-style patterns
+func matchesAny(patterns []string, file string) bool {
+	for _, pattern := range patterns {
+		if pattern != "" && codeowners.Match(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
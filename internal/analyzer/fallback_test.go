@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestFallbackRules(t *testing.T) {
+	workflow := []*parser.Change{
+		{File: "deploy/values.yaml", Action: "M", FileExtension: "yaml", Added: 2, Removed: 2, Diff: "+replicas: 3\n-replicas: 2"},
+		{File: "internal/api/server.go", Action: "M", FileExtension: "go", Added: 6, Removed: 1, Diff: "+\treplicas := cfg.Replicas"},
+	}
+	migration := []*parser.Change{
+		{File: "db/migrations/0042_add_users.sql", Action: "A", FileExtension: "sql", Added: 12, Diff: "+CREATE TABLE users ("},
+	}
+	spec := []*parser.Change{
+		{File: "src/app.spec.ts", Action: "A", FileExtension: "ts", Added: 9, Diff: "+describe('app', () => {"},
+	}
+	disabled := append([]config.FallbackRule(nil), config.DefaultFallbackRules...)
+	for i := range disabled {
+		if disabled[i].Name == "build-config" {
+			disabled[i].Disabled = true
+		}
+	}
+	custom := append([]config.FallbackRule{
+		{Name: "migrations", Files: []string{"migrations/"}, Match: "all", Action: "chore", Topic: "db", Item: "{item}", Purpose: "add migration"},
+	}, config.DefaultFallbackRules...)
+
+	tests := []struct {
+		name    string
+		rules   []config.FallbackRule
+		changes []*parser.Change
+		rule    string
+		action  string
+		topic   string
+		item    string
+	}{
+		{"built-in yaml rule", nil, workflow, "build-config", "ci", "config", ""},
+		{"disabled rule", disabled, workflow, "", "", "", ""},
+		{"built-in new file rule", nil, migration, "new-file", "feat", "migrations", "0042_add_users"},
+		{"built-in test file rule before new file", nil, spec, "test-file", "test", "src", "app.spec"},
+		{"custom rule first", custom, migration, "migrations", "chore", "db", "0042_add_users"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Analyzer{config: &config.Config{Fallbacks: tt.rules}, changes: tt.changes}
+			msg := a.AnalyzeChanges(context.Background(), 0, 0, "")
+			if msg.FallbackRule != tt.rule {
+				t.Fatalf("FallbackRule = %q, want %q", msg.FallbackRule, tt.rule)
+			}
+			if tt.rule != "" && (msg.Action != tt.action || msg.Topic != tt.topic || msg.Item != tt.item) {
+				t.Errorf("action, topic, item = %q, %q, %q, want %q, %q, %q", msg.Action, msg.Topic, msg.Item, tt.action, tt.topic, tt.item)
+			}
+		})
+	}
+}
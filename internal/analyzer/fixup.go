@@ -0,0 +1,57 @@
+package analyzer
+
+import (
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+// fixupDistance is how many lines apart a staged hunk and a hunk of an earlier
+// commit may be and still be the same edit
+const fixupDistance = 3
+
+// FixupCandidate is an earlier commit the staged changes may belong to, with
+// the lines it changed in each file
+type FixupCandidate struct {
+	Commit *parser.Commit
+	Hunks  map[string][]parser.Hunk
+}
+
+// Fixup is the earlier commit the staged changes most likely belong to
+type Fixup struct {
+	Commit *parser.Commit
+	Files  int // Staged files the commit also changed
+	Hunks  int // Staged hunks next to lines the commit changed
+}
+
+// FindFixupTarget returns the candidate whose changes the staged ones touch
+// most, or nil when none does convincingly. A candidate must have changed most
+// of the staged files and lines next to at least one staged hunk; candidates
+// are listed newest first, and the newest wins ties.
+func FindFixupTarget(changes []*parser.Change, candidates []FixupCandidate) *Fixup {
+	var best *Fixup
+	bestScore := 0
+	for _, candidate := range candidates {
+		fixup := &Fixup{Commit: candidate.Commit}
+		for _, change := range changes {
+			hunks, ok := candidate.Hunks[change.File]
+			if !ok || change.Action == "A" {
+				continue
+			}
+			fixup.Files++
+			for _, staged := range parser.OldHunks(change.Diff) {
+				for _, hunk := range hunks {
+					if staged.Near(hunk, fixupDistance) {
+						fixup.Hunks++
+						break
+					}
+				}
+			}
+		}
+		if fixup.Hunks == 0 || fixup.Files*2 <= len(changes) {
+			continue
+		}
+		if score := 2*fixup.Hunks + fixup.Files; score > bestScore {
+			best, bestScore = fixup, score
+		}
+	}
+	return best
+}
@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestFindFixupTarget(t *testing.T) {
+	changes := []*parser.Change{
+		{File: "a.go", Action: "M", Diff: "@@ -12 +12 @@\n-x\n+y\n@@ -40,0 +41 @@\n+z\n"},
+		{File: "b.go", Action: "M", Diff: "@@ -5 +5 @@\n-x\n+y\n"},
+	}
+	older := &parser.Commit{Hash: "older", Subject: "feat: add a"}
+	newer := &parser.Commit{Hash: "newer", Subject: "feat: add b"}
+
+	tests := []struct {
+		name       string
+		candidates []FixupCandidate
+		want       string
+		hunks      int
+	}{
+		{
+			name: "most overlapping hunks",
+			candidates: []FixupCandidate{
+				{Commit: newer, Hunks: map[string][]parser.Hunk{"a.go": {{Start: 10, Lines: 2}}, "c.go": {{Start: 1, Lines: 5}}}},
+				{Commit: older, Hunks: map[string][]parser.Hunk{"a.go": {{Start: 11, Lines: 1}, {Start: 38, Lines: 2}}, "b.go": {{Start: 4, Lines: 3}}}},
+			},
+			want:  "older",
+			hunks: 3,
+		},
+		{
+			name: "newest wins ties",
+			candidates: []FixupCandidate{
+				{Commit: newer, Hunks: map[string][]parser.Hunk{"a.go": {{Start: 12, Lines: 1}}, "b.go": {{Start: 100, Lines: 1}}}},
+				{Commit: older, Hunks: map[string][]parser.Hunk{"a.go": {{Start: 12, Lines: 1}}, "b.go": {{Start: 100, Lines: 1}}}},
+			},
+			want:  "newer",
+			hunks: 1,
+		},
+		{
+			name: "same files but distant lines",
+			candidates: []FixupCandidate{
+				{Commit: newer, Hunks: map[string][]parser.Hunk{"a.go": {{Start: 100, Lines: 1}}, "b.go": {{Start: 100, Lines: 1}}}},
+			},
+		},
+		{
+			name: "too few of the staged files",
+			candidates: []FixupCandidate{
+				{Commit: newer, Hunks: map[string][]parser.Hunk{"a.go": {{Start: 12, Lines: 1}}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindFixupTarget(changes, tt.candidates)
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("FindFixupTarget() = %s, want none", got.Commit.Hash)
+				}
+				return
+			}
+			if got == nil || got.Commit.Hash != tt.want || got.Hunks != tt.hunks {
+				t.Errorf("FindFixupTarget() = %+v, want %s with %d hunks", got, tt.want, tt.hunks)
+			}
+		})
+	}
+}
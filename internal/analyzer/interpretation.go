@@ -0,0 +1,150 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// plausibleRatio is how close to the best score another action must be to be
+// a plausible reading of the changes
+const plausibleRatio = 0.5
+
+// maxAlternatives caps the alternative actions kept for a message
+const maxAlternatives = 3
+
+// maxReasonKeywords caps the keywords named in a reason
+const maxReasonKeywords = 3
+
+// Interpretation is another plausible action for the changes, with the signals
+// that point to it
+type Interpretation struct {
+	Action  string
+	Reasons []string
+}
+
+// actionScores collects the scores of candidate actions and the signals
+// behind them
+type actionScores struct {
+	scores  map[string]float64
+	reasons map[string][]string  // Strongest first
+	points  map[string][]float64 // Points of each reason
+}
+
+func newActionScores() *actionScores {
+	return &actionScores{scores: make(map[string]float64), reasons: make(map[string][]string), points: make(map[string][]float64)}
+}
+
+// add adds to the score of an action; signals adding nothing are not reasons
+func (s *actionScores) add(action string, score float64, reason string) {
+	s.scores[action] += score
+	if score <= 0 {
+		return
+	}
+	points := s.points[action]
+	i := sort.Search(len(points), func(i int) bool { return points[i] < score })
+	s.points[action] = slices.Insert(points, i, score)
+	s.reasons[action] = slices.Insert(s.reasons[action], i, reason)
+}
+
+// ranked returns the scored actions, best first and by name on ties
+func (s *actionScores) ranked() []string {
+	actions := make([]string, 0, len(s.scores))
+	for action := range s.scores {
+		actions = append(actions, action)
+	}
+	sort.Slice(actions, func(i, j int) bool {
+		if s.scores[actions[i]] != s.scores[actions[j]] {
+			return s.scores[actions[i]] > s.scores[actions[j]]
+		}
+		return actions[i] < actions[j]
+	})
+	return actions
+}
+
+// best returns the action with the highest score, or "" when none scored
+func (s *actionScores) best() (string, float64) {
+	ranked := s.ranked()
+	if len(ranked) == 0 {
+		return "", 0
+	}
+	return ranked[0], s.scores[ranked[0]]
+}
+
+// alternatives returns the actions other than the chosen one scoring close to
+// the best, most likely first
+func (s *actionScores) alternatives(chosen string) []Interpretation {
+	_, top := s.best()
+	var alternatives []Interpretation
+	for _, action := range s.ranked() {
+		if action == chosen || s.scores[action] <= 0 || s.scores[action] < top*plausibleRatio {
+			continue
+		}
+		alternatives = append(alternatives, Interpretation{Action: action, Reasons: s.reasons[action]})
+		if len(alternatives) == maxAlternatives {
+			break
+		}
+	}
+	return alternatives
+}
+
+// diffStatReason describes the balance of added and removed lines
+func diffStatReason(totalAdded, totalRemoved int) string {
+	ratio := 0.5
+	if totalAdded+totalRemoved > 0 {
+		ratio = float64(totalAdded) / float64(totalAdded+totalRemoved)
+	}
+	switch {
+	case ratio < 0.2:
+		return fmt.Sprintf("mostly deletions (+%d −%d)", totalAdded, totalRemoved)
+	case ratio > 0.8:
+		return fmt.Sprintf("mostly additions (+%d −%d)", totalAdded, totalRemoved)
+	default:
+		return fmt.Sprintf("balanced diff (+%d −%d)", totalAdded, totalRemoved)
+	}
+}
+
+// patternReason describes a multi-file pattern such as "feature-addition"
+func patternReason(pattern string) string {
+	return strings.ReplaceAll(pattern, "-", " ") + " across files"
+}
+
+// keywordReason names the configured keywords of an action found in the diff,
+// the ones adding the most to its score first
+func (a *Analyzer) keywordReason(action string) string {
+	points := make(map[string]int)
+	for _, file := range a.files {
+		for keyword, n := range file.keywordHits[action] {
+			points[keyword] += n * a.config.Keywords[action][keyword]
+		}
+	}
+	var keywords []string
+	for keyword, p := range points {
+		if p > 0 {
+			keywords = append(keywords, keyword)
+		}
+	}
+	if len(keywords) == 0 {
+		return "keywords in the diff"
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if points[keywords[i]] != points[keywords[j]] {
+			return points[keywords[i]] > points[keywords[j]]
+		}
+		return keywords[i] < keywords[j]
+	})
+
+	noun := "keyword"
+	if len(keywords) > 1 {
+		noun = "keywords"
+	}
+	if len(keywords) > maxReasonKeywords {
+		keywords = keywords[:maxReasonKeywords]
+	}
+	quoted := make([]string, len(keywords))
+	for i, keyword := range keywords {
+		quoted[i] = fmt.Sprintf("%q", keyword)
+	}
+	return fmt.Sprintf("%s %s in the diff", noun, strings.Join(quoted, ", "))
+}
@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+// maxMergeAreas is the number of areas with conflicts a merge subject names
+const maxMergeAreas = 3
+
+// MergeSummary is what a merge commit merges and where it resolves conflicts
+type MergeSummary struct {
+	Subject   string   // Message git prepared, e.g. "Merge branch 'feature/login' into main"
+	Source    string   // What is merged
+	Target    string   // Branch merged into, "" when unknown
+	Conflicts []string // Files that had conflicts
+	Areas     []string // Topics of the files that had conflicts, in order
+}
+
+// AnalyzeMerge describes a merge in progress by what it merges and the
+// conflicts it resolves. The staged changes of a merge combine everything
+// the merged branch brings in, which the regular analysis would take for
+// one change of its own.
+func (a *Analyzer) AnalyzeMerge(merge *parser.Merge, totalAdded, totalRemoved int) *CommitMessage {
+	a.trail = nil
+	summary := &MergeSummary{Subject: merge.Subject, Source: merge.Source, Target: merge.Target, Conflicts: merge.Conflicts}
+	for _, file := range merge.Conflicts {
+		if area := a.determineTopic(file); !contains(summary.Areas, area) {
+			summary.Areas = append(summary.Areas, area)
+		}
+	}
+
+	msg := &CommitMessage{Action: "merge", Item: merge.Source, TotalAdded: totalAdded, TotalRemoved: totalRemoved, Merge: summary}
+	for _, change := range a.changes {
+		msg.Files = append(msg.Files, change.File)
+	}
+	if len(summary.Areas) > 0 {
+		msg.Topic = summary.Areas[0]
+		msg.Purpose = "resolve conflicts in " + joinWords(limitNames(summary.Areas, maxMergeAreas))
+		a.note("merge", "merging %s with conflicts in %s", merge.Source, strings.Join(merge.Conflicts, ", "))
+	} else {
+		msg.Purpose = merge.Source
+		if merge.Target != "" {
+			msg.Purpose += " into " + merge.Target
+		}
+		a.note("merge", "merging %s without conflicts", merge.Source)
+	}
+	return msg
+}
+
+// Body returns the message body of the merge: the message git prepared and
+// the files whose conflicts it resolves
+func (m *MergeSummary) Body() string {
+	notes := parser.ConflictNotes(m.Conflicts)
+	switch {
+	case m.Subject == "":
+		return notes
+	case notes == "":
+		return m.Subject
+	}
+	return m.Subject + "\n\n" + notes
+}
@@ -0,0 +1,33 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestAnalyzeMerge(t *testing.T) {
+	changes := []*parser.Change{
+		{File: "internal/parser/git.go", Action: "M", Added: 40, Removed: 2},
+		{File: "internal/templater/templater.go", Action: "M", Added: 3, Removed: 3},
+		{File: "README.md", Action: "M", Added: 1},
+	}
+	a := NewAnalyzer(changes, config.DefaultConfig())
+
+	merge := &parser.Merge{Source: "feature/login", Conflicts: []string{"internal/parser/git.go", "internal/parser/patch.go", "internal/templater/templater.go"}}
+	msg := a.AnalyzeMerge(merge, 44, 5)
+	if msg.Action != "merge" || msg.Purpose != "resolve conflicts in parser and templater" {
+		t.Errorf("AnalyzeMerge() = %s: %s, want merge: resolve conflicts in parser and templater", msg.Action, msg.Purpose)
+	}
+	want := "Conflicts resolved in:\n- internal/parser/git.go\n- internal/parser/patch.go\n- internal/templater/templater.go"
+	if body := msg.Merge.Body(); body != want {
+		t.Errorf("Body() = %q, want %q", body, want)
+	}
+
+	merge = &parser.Merge{Subject: "Merge branch 'fix/typo' into develop", Source: "fix/typo", Target: "develop"}
+	msg = a.AnalyzeMerge(merge, 44, 5)
+	if msg.Purpose != "fix/typo into develop" || msg.Merge.Body() != merge.Subject {
+		t.Errorf("AnalyzeMerge() without conflicts = %q with body %q, want what it merges", msg.Purpose, msg.Merge.Body())
+	}
+}
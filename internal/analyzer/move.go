@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"path"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+// Limits of a directory move: at least minMovedFiles renames, which make up
+// most of the changed files, and at most maxMoveLines changed lines per file
+// on average, such as updated imports
+const (
+	minMovedFiles = 2
+	maxMoveLines  = 10
+)
+
+// DirectoryMove is a directory whose files were moved to another directory
+// with their layout below it kept
+type DirectoryMove struct {
+	Source string // Directory the files were in, "" for the repository root
+	Target string // Directory the files are now in
+	Files  int    // Number of files moved
+	Kind   string // "package" when Go files moved, "directory" otherwise
+}
+
+// Phrase describes the move for a commit message, such as "move parser
+// package under internal/" or "rename pkg/util/ to pkg/helpers/"
+func (m *DirectoryMove) Phrase() string {
+	switch {
+	case m.Source == "":
+		return "move root files under " + m.Target + "/"
+	case m.Target == "":
+		return "move " + m.Source + "/ to the repository root"
+	case path.Base(m.Source) == path.Base(m.Target) && path.Dir(m.Target) == ".":
+		return "move " + path.Base(m.Source) + " " + m.Kind + " to the repository root"
+	case path.Base(m.Source) == path.Base(m.Target):
+		return "move " + path.Base(m.Source) + " " + m.Kind + " under " + path.Dir(m.Target) + "/"
+	case path.Dir(m.Source) == path.Dir(m.Target):
+		return "rename " + m.Source + "/ to " + m.Target + "/"
+	}
+	return "move " + m.Source + "/ to " + m.Target + "/"
+}
+
+// DetectDirectoryMove returns the move when the changes mostly move files from
+// one directory to another and change little of their content, or nil. Each
+// moved file must keep its path below the directories, so that scattered
+// renames are not taken for a move.
+func DetectDirectoryMove(changes []*parser.Change) *DirectoryMove {
+	var renames []*parser.Change
+	lines := 0
+	for _, change := range changes {
+		if change.IsRename {
+			renames = append(renames, change)
+		}
+		lines += change.Added + change.Removed
+	}
+	if len(renames) < minMovedFiles || len(renames)*2 <= len(changes) || lines > maxMoveLines*len(changes) {
+		return nil
+	}
+
+	sources := make([]string, len(renames))
+	targets := make([]string, len(renames))
+	for i, change := range renames {
+		sources[i], targets[i] = change.Source, change.Target
+	}
+	source, target := commonDir(sources), commonDir(targets)
+
+	if source == target || !sameBelow(renames, source, target) {
+		return nil
+	}
+
+	kind := "directory"
+	for _, change := range renames {
+		if path.Ext(change.Target) == ".go" {
+			kind = "package"
+			break
+		}
+	}
+	return &DirectoryMove{Source: source, Target: target, Files: len(renames), Kind: kind}
+}
+
+// commonDir returns the deepest directory containing all the files, "" for the
+// repository root
+func commonDir(files []string) string {
+	dir := path.Dir(files[0])
+	for _, file := range files[1:] {
+		for dir != "." && !strings.HasPrefix(file, dir+"/") {
+			dir = path.Dir(dir)
+		}
+	}
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+// sameBelow reports whether every rename keeps the path of its file below the
+// source and target directories
+func sameBelow(renames []*parser.Change, source, target string) bool {
+	for _, change := range renames {
+		if below(change.Source, source) != below(change.Target, target) {
+			return false
+		}
+	}
+	return true
+}
+
+// below returns the path of a file relative to a directory, "" for the root
+func below(file, dir string) string {
+	if dir == "" || dir == "." {
+		return file
+	}
+	return strings.TrimPrefix(file, dir+"/")
+}
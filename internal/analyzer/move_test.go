@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestDetectDirectoryMove(t *testing.T) {
+	rename := func(source, target string, lines int) *parser.Change {
+		return &parser.Change{File: target, Action: "R", IsRename: true, Source: source, Target: target, Added: lines, Removed: lines}
+	}
+	modify := func(file string, lines int) *parser.Change {
+		return &parser.Change{File: file, Action: "M", Added: lines, Removed: lines}
+	}
+
+	tests := []struct {
+		name    string
+		changes []*parser.Change
+		want    string
+	}{
+		{"package under internal", []*parser.Change{
+			rename("parser/git.go", "internal/parser/git.go", 0),
+			rename("parser/patch.go", "internal/parser/patch.go", 1),
+			rename("parser/testdata/a.diff", "internal/parser/testdata/a.diff", 0),
+			modify("cmd/root.go", 1),
+		}, "move parser package under internal/"},
+		{"renamed directory", []*parser.Change{
+			rename("web/util/dates.ts", "web/helpers/dates.ts", 0),
+			rename("web/util/strings.ts", "web/helpers/strings.ts", 0),
+		}, "rename web/util/ to web/helpers/"},
+		{"moved elsewhere", []*parser.Change{
+			rename("scripts/a.sh", "tools/release/a.sh", 0),
+			rename("scripts/b.sh", "tools/release/b.sh", 0),
+		}, "move scripts/ to tools/release/"},
+		{"root files", []*parser.Change{
+			rename("main.go", "cmd/gitmit/main.go", 0),
+			rename("flags.go", "cmd/gitmit/flags.go", 0),
+		}, "move root files under cmd/gitmit/"},
+		{"layout changed", []*parser.Change{
+			rename("parser/git.go", "internal/git/parser.go", 0),
+			rename("parser/patch.go", "internal/patch/parser.go", 0),
+		}, ""},
+		{"content rewritten", []*parser.Change{
+			rename("parser/git.go", "internal/parser/git.go", 40),
+			rename("parser/patch.go", "internal/parser/patch.go", 30),
+		}, ""},
+		{"mostly edits", []*parser.Change{
+			rename("parser/git.go", "internal/parser/git.go", 0),
+			rename("parser/patch.go", "internal/parser/patch.go", 0),
+			modify("cmd/root.go", 1), modify("cmd/propose.go", 1),
+		}, ""},
+		{"single rename", []*parser.Change{rename("parser/git.go", "internal/parser/git.go", 0)}, ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if move := DetectDirectoryMove(tt.changes); move != nil {
+			got = move.Phrase()
+		}
+		if got != tt.want {
+			t.Errorf("DetectDirectoryMove() of %s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+// minParallelFiles is the smallest number of changed files analyzed
+// concurrently; fewer are analyzed faster than goroutines start
+const minParallelFiles = 8
+
+// fileAnalysis is what the analysis of one changed file finds
+type fileAnalysis struct {
+	action      string // Action the file suggests on its own
+	topic       string
+	item        string
+	purpose     string
+	functions   []string
+	structs     []string
+	methods     []string
+	patterns    []string
+	summary     string                    // Diff summary for the AI
+	keywordHits map[string]map[string]int // Occurrences of each configured keyword, by action
+}
+
+// analyzeFiles analyzes every changed file, concurrently with a bounded
+// number of workers for large changes. The results are in the order of the
+// changes, so aggregating them gives the same analysis however they ran.
+func (a *Analyzer) analyzeFiles() []fileAnalysis {
+	files := make([]fileAnalysis, len(a.changes))
+	workers := a.workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(a.changes) {
+		workers = len(a.changes)
+	}
+	if workers <= 1 || len(a.changes) < minParallelFiles {
+		for i, change := range a.changes {
+			files[i] = a.analyzeFile(change)
+		}
+		return files
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each worker writes only the results of the files it takes
+			for i := range indexes {
+				files[i] = a.analyzeFile(a.changes[i])
+			}
+		}()
+	}
+	for i := range a.changes {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return files
+}
+
+// analyzeFile analyzes one changed file. It only reads the analyzer, so files
+// can be analyzed concurrently.
+func (a *Analyzer) analyzeFile(change *parser.Change) fileAnalysis {
+	return fileAnalysis{
+		action:      a.determineAction(change),
+		topic:       a.determineTopic(change.File),
+		item:        a.determineItem(change.File),
+		purpose:     a.determinePurpose(change.Diff),
+		functions:   a.detectFunctions(change.Diff),
+		structs:     a.detectStructs(change.Diff),
+		methods:     a.detectMethods(change.Diff),
+		patterns:    a.detectChangePatterns(change),
+		summary:     a.summarizeDiff(change.Diff),
+		keywordHits: a.keywordHits(change.Diff),
+	}
+}
+
+// keywordHits counts the occurrences of each configured keyword in a diff
+func (a *Analyzer) keywordHits(diff string) map[string]map[string]int {
+	if a.config == nil || len(a.config.Keywords) == 0 {
+		return nil
+	}
+	diff = strings.ToLower(diff)
+	hits := make(map[string]map[string]int, len(a.config.Keywords))
+	for action, keywords := range a.config.Keywords {
+		hits[action] = make(map[string]int, len(keywords))
+		for keyword := range keywords {
+			hits[action][keyword] = strings.Count(diff, strings.ToLower(keyword))
+		}
+	}
+	return hits
+}
@@ -0,0 +1,90 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+// manyChanges returns changes to n Go files, each adding a function and a struct
+func manyChanges(n int) []*parser.Change {
+	changes := make([]*parser.Change, n)
+	for i := range changes {
+		var diff strings.Builder
+		fmt.Fprintf(&diff, "@@ -1,0 +1,%d @@\n", 40)
+		fmt.Fprintf(&diff, "+type Parser%d struct {}\n", i)
+		fmt.Fprintf(&diff, "+func Parse%d() int {\n", i)
+		for line := 0; line < 36; line++ {
+			fmt.Fprintf(&diff, "+\tx := %d // step %d\n", i, line)
+		}
+		diff.WriteString("+}\n")
+		changes[i] = &parser.Change{
+			File:          fmt.Sprintf("internal/pkg%d/parse%d.go", i%5, i),
+			Action:        "M",
+			Added:         39,
+			FileExtension: "go",
+			Diff:          diff.String(),
+		}
+	}
+	return changes
+}
+
+func TestAnalyzeFilesConcurrently(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Keywords = map[string]map[string]int{"refactor": {"step": 1}, "feat": {"func": 2}}
+	changes := manyChanges(3 * minParallelFiles)
+
+	sequential := &Analyzer{changes: changes, config: cfg, workers: 1}
+	parallel := &Analyzer{changes: changes, config: cfg, workers: 4}
+	want := sequential.AnalyzeChanges(context.Background(), 0, 0, "")
+	got := parallel.AnalyzeChanges(context.Background(), 0, 0, "")
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("concurrent analysis = %+v, want %+v", got, want)
+	}
+	if !reflect.DeepEqual(parallel.Trail(), sequential.Trail()) {
+		t.Errorf("concurrent trail = %+v, want %+v", parallel.Trail(), sequential.Trail())
+	}
+	if len(want.DetectedFunctions) != len(changes) || want.DetectedFunctions[0] != "Parse0" {
+		t.Errorf("DetectedFunctions = %v, want one function per file in order", want.DetectedFunctions)
+	}
+}
+
+func TestCalculateKeywordScores(t *testing.T) {
+	a := &Analyzer{
+		config: &config.Config{Keywords: map[string]map[string]int{"fix": {"Error": 2, "panic": 3}, "docs": {"readme": 1}}},
+		changes: []*parser.Change{
+			{File: "a.go", Diff: "+ return error\n+ // ERROR"},
+			{File: "b.go", Diff: "+ panic(err)"},
+		},
+	}
+	a.files = a.analyzeFiles()
+	want := map[string]int{"fix": 2*2 + 3, "docs": 0}
+	if got := a.calculateKeywordScores(); !reflect.DeepEqual(got, want) {
+		t.Errorf("calculateKeywordScores() = %v, want %v", got, want)
+	}
+}
+
+func BenchmarkAnalyzeFiles(b *testing.B) {
+	cfg := config.DefaultConfig()
+	changes := manyChanges(200)
+	for _, bench := range []struct {
+		name    string
+		workers int
+	}{
+		{"sequential", 1},
+		{"parallel", 0},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			a := &Analyzer{changes: changes, config: cfg, workers: bench.workers}
+			for i := 0; i < b.N; i++ {
+				a.analyzeFiles()
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+// minRevertSimilarity is the share of changed lines the staged changes and a
+// reversed earlier commit must have in common for the changes to revert it
+const minRevertSimilarity = 0.8
+
+// RevertCandidate is an earlier commit the staged changes may revert, with
+// the changes it made
+type RevertCandidate struct {
+	Commit  *parser.Commit
+	Changes []*parser.Change
+}
+
+// Revert is the earlier commit the staged changes revert
+type Revert struct {
+	Commit     *parser.Commit
+	Similarity float64 // Share of changed lines the changes and the reversed commit have in common
+	InProgress bool    // Named by git revert --no-commit rather than found by comparing lines
+}
+
+// Body returns the body git revert gives the message of a revert
+func (r *Revert) Body() string {
+	return fmt.Sprintf("This reverts commit %s.", r.Commit.Hash)
+}
+
+// FindRevertedCommit returns the candidate the staged changes undo, or nil
+// when none is undone exactly or mostly: the lines the changes add must be
+// the lines the candidate removed from the same files and the other way
+// round. Candidates are listed newest first, and the newest wins ties.
+func FindRevertedCommit(changes []*parser.Change, candidates []RevertCandidate) *Revert {
+	var best *Revert
+	for _, candidate := range candidates {
+		similarity := revertSimilarity(changes, candidate.Changes)
+		if similarity >= minRevertSimilarity && (best == nil || similarity > best.Similarity) {
+			best = &Revert{Commit: candidate.Commit, Similarity: similarity}
+		}
+	}
+	return best
+}
+
+// revertSimilarity returns the share of changed lines of the staged changes
+// and an earlier commit that the staged changes reverse
+func revertSimilarity(changes, commit []*parser.Change) float64 {
+	reversed := make(map[string]*parser.Change)
+	for _, change := range commit {
+		reversed[change.File] = change
+		if change.IsRename {
+			reversed[change.Source] = change
+		}
+	}
+
+	matched, total := 0, 0
+	for _, change := range changes {
+		added, removed := changedLines(change.Diff)
+		total += len(added) + len(removed)
+		original := reversed[change.File]
+		if original == nil && change.IsRename {
+			original = reversed[change.Source]
+		}
+		if original == nil {
+			continue
+		}
+		delete(reversed, original.File)
+		delete(reversed, original.Source)
+		originalAdded, originalRemoved := changedLines(original.Diff)
+		total += len(originalAdded) + len(originalRemoved)
+		matched += 2 * (commonLines(added, originalRemoved) + commonLines(removed, originalAdded))
+	}
+	for file, change := range reversed {
+		if file == change.File {
+			added, removed := changedLines(change.Diff)
+			total += len(added) + len(removed)
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(matched) / float64(total)
+}
+
+// changedLines returns the lines a diff adds and removes, by content and count
+func changedLines(diff string) (added, removed map[string]int) {
+	added, removed = make(map[string]int), make(map[string]int)
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ "), strings.HasPrefix(line, "--- "):
+		case strings.HasPrefix(line, "+"):
+			added[strings.TrimSpace(line[1:])]++
+		case strings.HasPrefix(line, "-"):
+			removed[strings.TrimSpace(line[1:])]++
+		}
+	}
+	return added, removed
+}
+
+// commonLines returns how many lines two sets of lines have in common
+func commonLines(a, b map[string]int) int {
+	common := 0
+	for line, count := range a {
+		common += min(count, b[line])
+	}
+	return common
+}
+
+// AnalyzeRevert describes changes that undo an earlier commit as its revert,
+// named by the subject of the reverted commit rather than what the changes do
+func (a *Analyzer) AnalyzeRevert(revert *Revert, totalAdded, totalRemoved int) *CommitMessage {
+	a.trail = nil
+	msg := &CommitMessage{Action: "revert", Item: revert.Commit.ShortHash(), Purpose: revert.Commit.Subject, TotalAdded: totalAdded, TotalRemoved: totalRemoved, Revert: revert}
+	for _, change := range a.changes {
+		msg.Files = append(msg.Files, change.File)
+	}
+	if revert.InProgress {
+		a.note("revert", "git revert is reverting commit %s %q", revert.Commit.ShortHash(), revert.Commit.Subject)
+	} else {
+		a.note("revert", "the changes undo %.0f%% of commit %s %q", 100*revert.Similarity, revert.Commit.ShortHash(), revert.Commit.Subject)
+	}
+	return msg
+}
@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestFindRevertedCommit(t *testing.T) {
+	caching := RevertCandidate{
+		Commit: &parser.Commit{Hash: "0788eb289d43e3dfe9ea0c31f02102da69445d11", Subject: "feat(cache): add response caching"},
+		Changes: []*parser.Change{
+			{File: "cache.go", Action: "M", Diff: "@@ -4,0 +5,4 @@\n+// B adds caching\n+func B() int {\n+\treturn 2\n+}\n"},
+			{File: "cache.yml", Action: "A", Diff: "@@ -0,0 +1 @@\n+cache: true\n"},
+		},
+	}
+	logging := RevertCandidate{
+		Commit:  &parser.Commit{Hash: "5b58c8f", Subject: "feat(log): log requests"},
+		Changes: []*parser.Change{{File: "log.go", Action: "M", Diff: "@@ -1,0 +2 @@\n+log.Println(r)\n"}},
+	}
+	candidates := []RevertCandidate{logging, caching}
+
+	tests := []struct {
+		name    string
+		changes []*parser.Change
+		want    string
+	}{
+		{"exact revert", []*parser.Change{
+			{File: "cache.go", Action: "M", Diff: "--- a/cache.go\n+++ b/cache.go\n@@ -5,4 +4,0 @@\n-// B adds caching\n-func B() int {\n-\treturn 2\n-}\n"},
+			{File: "cache.yml", Action: "D", Diff: "@@ -1 +0,0 @@\n-cache: true\n"},
+		}, "0788eb289d43e3dfe9ea0c31f02102da69445d11"},
+		{"mostly reverted", []*parser.Change{
+			{File: "cache.go", Action: "M", Diff: "@@ -5,4 +4,0 @@\n-// B adds caching\n-func B() int {\n-\treturn 2\n-}\n"},
+		}, "0788eb289d43e3dfe9ea0c31f02102da69445d11"},
+		{"new change", []*parser.Change{
+			{File: "cache.go", Action: "M", Diff: "@@ -6,1 +6,1 @@\n-\treturn 2\n+\treturn 3\n"},
+		}, ""},
+		{"reapplied", []*parser.Change{
+			{File: "log.go", Action: "M", Diff: "@@ -1,0 +2 @@\n+log.Println(r)\n"},
+		}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revert := FindRevertedCommit(tt.changes, candidates)
+			switch {
+			case tt.want == "" && revert != nil:
+				t.Errorf("FindRevertedCommit() = %s (%.2f), want none", revert.Commit.Hash, revert.Similarity)
+			case tt.want != "" && (revert == nil || revert.Commit.Hash != tt.want):
+				t.Errorf("FindRevertedCommit() = %+v, want %s", revert, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRevert(t *testing.T) {
+	changes := []*parser.Change{{File: "cache.go", Action: "M", Removed: 4}}
+	revert := &Revert{Commit: &parser.Commit{Hash: "0788eb289d43e3dfe9ea0c31f02102da69445d11", Subject: "feat(cache): add response caching"}, Similarity: 0.8}
+	msg := NewAnalyzer(changes, config.DefaultConfig()).AnalyzeRevert(revert, 0, 4)
+	if msg.Action != "revert" || msg.Purpose != "feat(cache): add response caching" {
+		t.Errorf("AnalyzeRevert() = %s: %s, want the subject of the reverted commit", msg.Action, msg.Purpose)
+	}
+	if body := revert.Body(); body != "This reverts commit 0788eb289d43e3dfe9ea0c31f02102da69445d11." {
+		t.Errorf("Body() = %q, want git revert's body", body)
+	}
+}
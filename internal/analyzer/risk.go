@@ -0,0 +1,143 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/codeowners"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+// Risk levels of staged changes
+const (
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+// riskMedium is the score from which changes are of medium risk
+const riskMedium = 30
+
+// securityLineRegex matches added lines dealing with secrets, crypto or code execution
+var securityLineRegex = regexp.MustCompile(`(?i)\b(password|passwd|secret|api[_-]?key|private[_-]?key|token|jwt|crypto|md5|sha1)\b|InsecureSkipVerify|exec\.Command|\beval\(|dangerouslySetInnerHTML|subprocess\.|os\.system\(`)
+
+// securityPathRegex matches files whose names suggest security-sensitive code
+var securityPathRegex = regexp.MustCompile(`(?i)(^|/|_|-)(auth|security|crypto|permissions?|secrets?|session|login|oauth)(/|_|-|\.|$)`)
+
+// RiskFactor is one reason staged changes are risky
+type RiskFactor struct {
+	Name   string
+	Points int
+	Detail string
+}
+
+// Risk is the assessed risk of staged changes, scored from 0 to 100
+type Risk struct {
+	Score   int
+	Level   string
+	Factors []RiskFactor
+}
+
+// String summarizes the risk as in "high (72/100)"
+func (r *Risk) String() string {
+	return fmt.Sprintf("%s (%d/100)", r.Level, r.Score)
+}
+
+// IsHigh reports whether the risk is high
+func (r *Risk) IsHigh() bool {
+	return r.Level == RiskHigh
+}
+
+// AssessRisk scores the risk of changes from the critical paths they touch, their
+// size, the source files changed without their tests and the security-related
+// code they add
+func AssessRisk(changes []*parser.Change, missingTests []MissingTest, cfg config.RiskConfig) *Risk {
+	risk := &Risk{}
+	add := func(name string, points int, detail string) {
+		if points > 0 {
+			risk.Factors = append(risk.Factors, RiskFactor{Name: name, Points: points, Detail: detail})
+			risk.Score += points
+		}
+	}
+
+	var critical []string
+	for _, change := range changes {
+		for _, pattern := range cfg.CriticalPaths {
+			if pattern != "" && codeowners.Match(pattern, change.File) {
+				critical = append(critical, change.File)
+				break
+			}
+		}
+	}
+	if len(critical) > 0 {
+		add("critical paths", min(30+5*(len(critical)-1), 40), strings.Join(limitNames(critical, 3), ", "))
+	}
+
+	lines := 0
+	var deleted []string
+	for _, change := range changes {
+		lines += change.Added + change.Removed
+		if change.Action == "D" {
+			deleted = append(deleted, change.File)
+		}
+	}
+	switch {
+	case lines >= 1000:
+		add("large diff", 25, fmt.Sprintf("%d lines changed", lines))
+	case lines >= 400:
+		add("large diff", 15, fmt.Sprintf("%d lines changed", lines))
+	case lines >= 150:
+		add("large diff", 8, fmt.Sprintf("%d lines changed", lines))
+	}
+	if len(changes) >= 20 {
+		add("many files", 10, fmt.Sprintf("%d files changed", len(changes)))
+	}
+
+	if len(missingTests) > 0 {
+		var files []string
+		for _, m := range missingTests {
+			files = append(files, m.File)
+		}
+		add("no tests", min(10+2*(len(missingTests)-1), 20), strings.Join(limitNames(files, 3), ", "))
+	}
+
+	if hints := securityHints(changes); len(hints) > 0 {
+		add("security", 20, strings.Join(limitNames(hints, 3), ", "))
+	}
+
+	if len(deleted) > 0 {
+		add("deletions", 10, strings.Join(limitNames(deleted, 3), ", "))
+	}
+
+	risk.Score = min(risk.Score, 100)
+	switch {
+	case risk.Score >= cfg.HighThreshold:
+		risk.Level = RiskHigh
+	case risk.Score >= riskMedium:
+		risk.Level = RiskMedium
+	default:
+		risk.Level = RiskLow
+	}
+	return risk
+}
+
+// securityHints returns the files whose path or added lines look security-related
+func securityHints(changes []*parser.Change) []string {
+	var files []string
+	for _, change := range changes {
+		if securityPathRegex.MatchString(path.Base(change.File)) || securityPathRegex.MatchString(path.Dir(change.File)) {
+			files = append(files, change.File)
+			continue
+		}
+		for _, line := range strings.Split(change.Diff, "\n") {
+			if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") && securityLineRegex.MatchString(line) {
+				files = append(files, change.File)
+				break
+			}
+		}
+	}
+	return files
+}
@@ -0,0 +1,58 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestAssessRisk(t *testing.T) {
+	cfg := config.RiskConfig{CriticalPaths: []string{"migrations/", "*.tf"}, HighThreshold: 60}
+
+	low := AssessRisk([]*parser.Change{
+		{File: "README.md", Action: "M", Added: 3, Removed: 1, Diff: "+Install with go get"},
+	}, nil, cfg)
+	if low.Level != RiskLow || low.Score != 0 || len(low.Factors) != 0 {
+		t.Errorf("docs change = %s %+v, want low with no factors", low, low.Factors)
+	}
+
+	changes := []*parser.Change{
+		{File: "db/migrations/0042_users.sql", Action: "A", Added: 120},
+		{File: "internal/api/user.go", Action: "M", Added: 200, Removed: 90, Diff: "+\ttoken := r.Header.Get(\"Authorization\")"},
+		{File: "internal/api/legacy.go", Action: "D", Removed: 40},
+	}
+	missing := []MissingTest{{File: "internal/api/user.go"}}
+	high := AssessRisk(changes, missing, cfg)
+
+	points := map[string]int{}
+	for _, f := range high.Factors {
+		points[f.Name] = f.Points
+	}
+	want := map[string]int{"critical paths": 30, "large diff": 15, "no tests": 10, "security": 20, "deletions": 10}
+	for name, p := range want {
+		if points[name] != p {
+			t.Errorf("factor %q = %d points, want %d (factors %+v)", name, points[name], p, high.Factors)
+		}
+	}
+	if high.Score != 85 || !high.IsHigh() {
+		t.Errorf("risky change = %s, want high (85/100)", high)
+	}
+
+	cfg.HighThreshold = 90
+	if r := AssessRisk(changes, missing, cfg); r.Level != RiskMedium {
+		t.Errorf("with threshold 90 = %s, want medium", r)
+	}
+}
+
+func TestSecurityHints(t *testing.T) {
+	changes := []*parser.Change{
+		{File: "internal/auth/session.go", Diff: "+func Refresh() {}"},
+		{File: "pkg/client.go", Diff: "+\tcfg := &tls.Config{InsecureSkipVerify: true}"},
+		{File: "pkg/author.go", Diff: "-\tpassword := \"\"\n+\tname := \"\""},
+	}
+	got := securityHints(changes)
+	if len(got) != 2 || got[0] != "internal/auth/session.go" || got[1] != "pkg/client.go" {
+		t.Errorf("securityHints() = %v", got)
+	}
+}
@@ -0,0 +1,45 @@
+package analyzer
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// maxScopeCandidates caps the other scopes detected for a message
+const maxScopeCandidates = 5
+
+// scopeCandidates returns the scopes the changed files point to other than
+// scope: the topics of the files, then their top-level directories, each by
+// how many files they cover
+func (a *Analyzer) scopeCandidates(scope string) []string {
+	counts := make(map[string]int)
+	var order []string
+	count := func(candidate string) {
+		if candidate == "" || candidate == "." || strings.EqualFold(candidate, scope) {
+			return
+		}
+		if counts[candidate] == 0 {
+			order = append(order, candidate)
+		}
+		counts[candidate]++
+	}
+	for _, change := range a.changes {
+		count(a.determineTopic(change.File))
+	}
+	topics := len(order)
+	for _, change := range a.changes {
+		if dir := path.Dir(change.File); dir != "." {
+			count(strings.Split(dir, "/")[0])
+		}
+	}
+
+	// Topics stay ahead of directories, which only fill in
+	sort.SliceStable(order[:topics], func(i, j int) bool { return counts[order[i]] > counts[order[j]] })
+	directories := order[topics:]
+	sort.SliceStable(directories, func(i, j int) bool { return counts[directories[i]] > counts[directories[j]] })
+	if len(order) > maxScopeCandidates {
+		order = order[:maxScopeCandidates]
+	}
+	return order
+}
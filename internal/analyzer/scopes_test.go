@@ -0,0 +1,30 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestScopeCandidates(t *testing.T) {
+	changes := []*parser.Change{
+		{File: "internal/auth/login.go"},
+		{File: "internal/auth/token.go"},
+		{File: "internal/db/users.go"},
+		{File: "web/src/login.tsx"},
+	}
+	a := NewAnalyzer(changes, config.DefaultConfig())
+
+	// The topics come first, by files, then the top-level directories; web is
+	// both, so it counts twice
+	want := []string{"web", "db", "internal"}
+	if got := a.scopeCandidates("auth"); !reflect.DeepEqual(got, want) {
+		t.Errorf("scopeCandidates(auth) = %v, want %v", got, want)
+	}
+	want = []string{"auth", "web", "db", "internal"}
+	if got := a.scopeCandidates("api"); !reflect.DeepEqual(got, want) {
+		t.Errorf("scopeCandidates(api) = %v, want %v", got, want)
+	}
+}
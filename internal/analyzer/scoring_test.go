@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"context"
 	"github.com/andev0x/gitmit/internal/config"
 	"github.com/andev0x/gitmit/internal/parser"
 	"testing"
@@ -30,7 +31,7 @@ func TestNormalizedScoring(t *testing.T) {
 		// branch "feat/new-ui" -> feat: 0.35 * 1.0 = 0.35
 		// keyword "error" -> fix: 0.25 * 1.0 = 0.25
 		// feat should win
-		msg := a.AnalyzeChanges(1, 0, "feat/new-ui")
+		msg := a.AnalyzeChanges(context.Background(), 1, 0, "feat/new-ui")
 		if msg.Action != "feat" {
 			t.Errorf("Expected action feat, got %s", msg.Action)
 		}
@@ -46,7 +47,7 @@ func TestNormalizedScoring(t *testing.T) {
 		// keyword "error" -> fix: 0.25 * 1.0 = 0.25
 		// 0.25 < 0.35 (fallback threshold)
 		// So it should fallback to determineAction which for Action: "M" is refactor
-		msg := a.AnalyzeChanges(1, 0, "")
+		msg := a.AnalyzeChanges(context.Background(), 1, 0, "")
 		if msg.Action != "refactor" {
 			t.Errorf("Expected action refactor (fallback), got %s", msg.Action)
 		}
@@ -62,7 +63,7 @@ func TestNormalizedScoring(t *testing.T) {
 		// branch "feature/cool" -> feat: 0.35
 		// ratio 1.0 -> feat: 0.25 * 1.0 = 0.25
 		// total feat = 0.60
-		msg := a.AnalyzeChanges(40, 0, "feature/cool")
+		msg := a.AnalyzeChanges(context.Background(), 40, 0, "feature/cool")
 		if msg.Action != "feat" {
 			t.Errorf("Expected action feat, got %s", msg.Action)
 		}
@@ -81,9 +82,75 @@ func TestNormalizedScoring(t *testing.T) {
 		// branch "feat/new-ui" -> feat: 3
 		// keyword "error" -> fix: 4
 		// fix should win
-		msg := a.AnalyzeChanges(1, 0, "feat/new-ui")
+		msg := a.AnalyzeChanges(context.Background(), 1, 0, "feat/new-ui")
 		if msg.Action != "fix" {
 			t.Errorf("Expected action fix, got %s", msg.Action)
 		}
 	})
 }
+
+func TestScoringAlternatives(t *testing.T) {
+	a := &Analyzer{
+		config: &config.Config{},
+		changes: []*parser.Change{
+			{File: "login.go", Action: "M", Diff: "+ a\n- b", Added: 10, Removed: 10},
+			{File: "session.go", Action: "M", Diff: "+ c\n- d"},
+		},
+	}
+	// branch "fix/login" -> fix: 3
+	// balanced diff -> refactor: 2, close enough to be plausible
+	msg := a.AnalyzeChanges(context.Background(), 10, 10, "fix/login")
+	if msg.Action != "fix" {
+		t.Fatalf("Expected action fix, got %s", msg.Action)
+	}
+	if len(msg.ActionReasons) != 1 || msg.ActionReasons[0] != "branch fix/login" {
+		t.Errorf("ActionReasons = %v", msg.ActionReasons)
+	}
+	if len(msg.Alternatives) != 1 || msg.Alternatives[0].Action != "refactor" {
+		t.Fatalf("Alternatives = %+v, want refactor", msg.Alternatives)
+	}
+	if reasons := msg.Alternatives[0].Reasons; len(reasons) != 1 || reasons[0] != "balanced diff (+10 −10)" {
+		t.Errorf("refactor reasons = %v", reasons)
+	}
+
+	// A far weaker signal is not an alternative
+	scores := newActionScores()
+	scores.add("feat", 10, "branch feat/x")
+	scores.add("fix", 2, "keywords in the diff (2)")
+	scores.add("chore", 0, "keywords in the diff (0)")
+	if alternatives := scores.alternatives("feat"); len(alternatives) != 0 {
+		t.Errorf("alternatives = %+v, want none", alternatives)
+	}
+}
+
+func TestTrail(t *testing.T) {
+	a := &Analyzer{
+		config: &config.Config{Keywords: map[string]map[string]int{"fix": {"error": 2}}},
+		changes: []*parser.Change{
+			{File: "main.go", Action: "M", Diff: "+ return error\n+ // error", Added: 2, Removed: 0},
+		},
+	}
+	a.AnalyzeChanges(context.Background(), 2, 0, "fix/login")
+
+	want := []Step{
+		{Stage: "file", Detail: "modifies main.go (+2 −0) → refactor"},
+		{Stage: "keywords", Detail: `"error" ×2 in main.go → fix +4`},
+		{Stage: "score", Detail: `fix 7.00: keyword "error" in the diff (4), branch fix/login`},
+	}
+	got := a.Trail()
+	if len(got) != len(want) {
+		t.Fatalf("Trail() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("step %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// A shortcut rule decides before any signal is scored
+	a.changes = []*parser.Change{{File: "README.md", Action: "M", FileExtension: "md"}}
+	a.AnalyzeChanges(context.Background(), 1, 0, "")
+	if got := a.Trail(); len(got) != 1 || got[0].Stage != "shortcut" {
+		t.Errorf("Trail() = %+v, want one shortcut step", got)
+	}
+}
@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"github.com/andev0x/gitmit/internal/codeowners"
+)
+
+// SpecialFile is the kind of a file known by its name or location rather than
+// its extension, such as a Dockerfile, a Makefile or a CI workflow
+type SpecialFile struct {
+	Type    string // Commit type of changes to such files alone, ci or build
+	Scope   string // Scope of changes to such files, such as docker or github
+	Purpose string // Purpose of changes to such files
+}
+
+var (
+	githubWorkflows = SpecialFile{Type: "ci", Scope: "github", Purpose: "update CI workflows"}
+	gitlabPipeline  = SpecialFile{Type: "ci", Scope: "gitlab", Purpose: "update CI pipeline"}
+	jenkinsPipeline = SpecialFile{Type: "ci", Scope: "jenkins", Purpose: "update CI pipeline"}
+	ciPipeline      = SpecialFile{Type: "ci", Scope: "ci", Purpose: "update CI pipeline"}
+	containerBuild  = SpecialFile{Type: "build", Scope: "docker", Purpose: "update container build"}
+	makeBuild       = SpecialFile{Type: "build", Scope: "make", Purpose: "update build targets"}
+	releaseBuild    = SpecialFile{Type: "build", Scope: "release", Purpose: "update release build"}
+)
+
+// specialFiles are gitignore-style patterns of special files, the first
+// matching pattern deciding
+var specialFiles = []struct {
+	pattern string
+	file    SpecialFile
+}{
+	{".github/workflows/", githubWorkflows},
+	{".github/actions/", githubWorkflows},
+	{".github/dependabot.yml", githubWorkflows},
+	{".gitlab-ci.yml", gitlabPipeline},
+	{".gitlab-ci/", gitlabPipeline},
+	{"Jenkinsfile", jenkinsPipeline},
+	{"Jenkinsfile.*", jenkinsPipeline},
+	{"*.jenkinsfile", jenkinsPipeline},
+	{".circleci/", ciPipeline},
+	{".buildkite/", ciPipeline},
+	{".travis.yml", ciPipeline},
+	{"azure-pipelines.yml", ciPipeline},
+	{"bitbucket-pipelines.yml", ciPipeline},
+	{"Dockerfile", containerBuild},
+	{"Dockerfile.*", containerBuild},
+	{"*.Dockerfile", containerBuild},
+	{"*.dockerfile", containerBuild},
+	{"Containerfile", containerBuild},
+	{".dockerignore", containerBuild},
+	{"docker-compose*.yml", containerBuild},
+	{"docker-compose*.yaml", containerBuild},
+	{"compose.yml", containerBuild},
+	{"compose.yaml", containerBuild},
+	{"Makefile", makeBuild},
+	{"makefile", makeBuild},
+	{"GNUmakefile", makeBuild},
+	{"*.mk", makeBuild},
+	{"Justfile", makeBuild},
+	{"justfile", makeBuild},
+	{"Taskfile.yml", makeBuild},
+	{"Taskfile.yaml", makeBuild},
+	{"CMakeLists.txt", makeBuild},
+	{".goreleaser.yml", releaseBuild},
+	{".goreleaser.yaml", releaseBuild},
+}
+
+// SpecialFileOf returns the kind of a special file, or nil for other files
+func SpecialFileOf(file string) *SpecialFile {
+	for _, special := range specialFiles {
+		if codeowners.Match(special.pattern, file) {
+			kind := special.file
+			return &kind
+		}
+	}
+	return nil
+}
+
+// specialChanges returns the kind of the changed files when all of them are
+// special files of the same type, with the scope of the heaviest, or nil
+func (a *Analyzer) specialChanges(weights []FileWeight) (*SpecialFile, string) {
+	var kind *SpecialFile
+	for _, change := range a.changes {
+		special := SpecialFileOf(change.File)
+		if special == nil || kind != nil && special.Type != kind.Type {
+			return nil, ""
+		}
+		kind = special
+	}
+	file := a.changes[0].File
+	if len(weights) > 0 {
+		file = weights[0].File
+	}
+	return SpecialFileOf(file), file
+}
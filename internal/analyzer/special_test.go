@@ -0,0 +1,75 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestSpecialFileOf(t *testing.T) {
+	tests := []struct {
+		file       string
+		typ, scope string
+	}{
+		{"Dockerfile", "build", "docker"},
+		{"deploy/Dockerfile.prod", "build", "docker"},
+		{"docker-compose.override.yml", "build", "docker"},
+		{"Makefile", "build", "make"},
+		{"scripts/rules.mk", "build", "make"},
+		{"Jenkinsfile", "ci", "jenkins"},
+		{".github/workflows/release.yml", "ci", "github"},
+		{".gitlab-ci.yml", "ci", "gitlab"},
+		{".circleci/config.yml", "ci", "ci"},
+		{".goreleaser.yaml", "build", "release"},
+		{"main.go", "", ""},
+		{"docs/Makefile.md", "", ""},
+		{"config/app.yml", "", ""},
+	}
+	for _, tt := range tests {
+		typ, scope := "", ""
+		if special := SpecialFileOf(tt.file); special != nil {
+			typ, scope = special.Type, special.Scope
+		}
+		if typ != tt.typ || scope != tt.scope {
+			t.Errorf("SpecialFileOf(%q) = %q, %q, want %q, %q", tt.file, typ, scope, tt.typ, tt.scope)
+		}
+	}
+}
+
+func TestSpecialFileFallback(t *testing.T) {
+	tests := []struct {
+		name                string
+		changes             []*parser.Change
+		action, scope, item string
+	}{
+		{"Dockerfile", []*parser.Change{
+			{File: "Dockerfile", Action: "M", Added: 2, Removed: 1, Diff: "+FROM golang:1.23\n-FROM golang:1.22"},
+		}, "build", "docker", "Dockerfile"},
+		{"new Makefile", []*parser.Change{
+			{File: "Makefile", Action: "A", Added: 2, Diff: "+all:\n+\tgo build"},
+		}, "build", "make", "Makefile"},
+		{"Makefile and Dockerfile", []*parser.Change{
+			{File: "Makefile", Action: "M", Added: 8, Removed: 2, Diff: "+lint:\n+\tgolangci-lint run"},
+			{File: "Dockerfile", Action: "M", Added: 1, Removed: 1, Diff: "+RUN make build"},
+		}, "build", "make", "Makefile"},
+		{"workflows", []*parser.Change{
+			{File: ".github/workflows/test.yml", Action: "M", Added: 3, Removed: 1, Diff: "+      - run: go test ./..."},
+			{File: "Jenkinsfile", Action: "M", Added: 1, Removed: 1, Diff: "+    sh 'go test ./...'"},
+		}, "ci", "github", "test.yml"},
+		{"Dockerfile with code", []*parser.Change{
+			{File: "Dockerfile", Action: "M", Added: 1, Removed: 1, Diff: "+EXPOSE 8080"},
+			{File: "internal/api/server.go", Action: "M", Added: 1, Removed: 1, Diff: "+\taddr := \":8080\""},
+		}, "ci", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Analyzer{config: &config.Config{}, changes: tt.changes}
+			msg := a.AnalyzeChanges(context.Background(), 0, 0, "")
+			if msg.Action != tt.action || msg.Scope != tt.scope || msg.Item != tt.item {
+				t.Errorf("action, scope, item = %q, %q, %q, want %q, %q, %q", msg.Action, msg.Scope, msg.Item, tt.action, tt.scope, tt.item)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/codeowners"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+// defaultTestMappings are the usual locations of tests per language
+var defaultTestMappings = []config.TestMapping{
+	{Source: "{dir}/{name}.go", Tests: []string{"{dir}/{name}_test.go"}},
+	{Source: "src/main/java/{dir}/{name}.java", Tests: []string{"src/test/java/{dir}/{name}Test.java"}},
+	{Source: "src/main/kotlin/{dir}/{name}.kt", Tests: []string{"src/test/kotlin/{dir}/{name}Test.kt"}},
+	{Source: "lib/{dir}/{name}.rb", Tests: []string{"spec/{dir}/{name}_spec.rb", "test/{dir}/{name}_test.rb"}},
+	{Source: "app/{dir}/{name}.rb", Tests: []string{"spec/{dir}/{name}_spec.rb", "test/{dir}/{name}_test.rb"}},
+	{Source: "{dir}/{name}.py", Tests: []string{"{dir}/test_{name}.py", "{dir}/{name}_test.py", "tests/test_{name}.py", "tests/{dir}/test_{name}.py"}},
+}
+
+// untestedNames are entry points and package boilerplate, which rarely have
+// tests of their own
+var untestedNames = map[string]bool{"main": true, "doc": true, "index": true, "__init__": true, "conftest": true, "setup": true}
+
+func init() {
+	for _, ext := range []string{"ts", "tsx", "js", "jsx", "mjs", "cjs"} {
+		defaultTestMappings = append(defaultTestMappings, config.TestMapping{
+			Source: "{dir}/{name}." + ext,
+			Tests: []string{
+				"{dir}/{name}.test." + ext,
+				"{dir}/{name}.spec." + ext,
+				"{dir}/__tests__/{name}." + ext,
+				"{dir}/__tests__/{name}.test." + ext,
+			},
+		})
+	}
+}
+
+// neverMatches is a regex matching no path
+var neverMatches = regexp.MustCompile(`^\b$`)
+
+// MissingTest is a changed source file whose tests were not changed with it
+type MissingTest struct {
+	File     string
+	Expected []string // Test files that would cover the source file
+}
+
+// MissingTests returns the added or modified source files none of whose test
+// files are among the changes. The mappings are tried before the built-in ones;
+// files no mapping matches, such as docs, are not source files.
+func MissingTests(changes []*parser.Change, mappings []config.TestMapping) []MissingTest {
+	mappings = append(append([]config.TestMapping(nil), mappings...), defaultTestMappings...)
+
+	changed := make(map[string]bool)
+	for _, change := range changes {
+		changed[change.File] = true
+	}
+
+	var missing []MissingTest
+	for _, change := range changes {
+		if change.Action == "D" || isTestPath(change.File, mappings) {
+			continue
+		}
+		expected := testPaths(change.File, mappings)
+		if len(expected) == 0 {
+			continue
+		}
+		covered := false
+		for _, test := range expected {
+			if changed[test] {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			missing = append(missing, MissingTest{File: change.File, Expected: expected})
+		}
+	}
+	return missing
+}
+
+// testPaths returns the test files of a source file per the first mapping matching it
+func testPaths(file string, mappings []config.TestMapping) []string {
+	for _, mapping := range mappings {
+		re := mappingRegex(mapping.Source)
+		m := re.FindStringSubmatch(file)
+		if m == nil {
+			continue
+		}
+		dir, name := "", ""
+		for i, group := range re.SubexpNames() {
+			switch group {
+			case "dir":
+				dir = m[i]
+			case "name":
+				name = m[i]
+			}
+		}
+
+		if untestedNames[name] {
+			return nil
+		}
+
+		var paths []string
+		for _, test := range mapping.Tests {
+			if dir == "" {
+				test = strings.ReplaceAll(test, "{dir}/", "")
+			}
+			test = strings.ReplaceAll(test, "{dir}", dir)
+			paths = append(paths, strings.ReplaceAll(test, "{name}", name))
+		}
+		return paths
+	}
+	return nil
+}
+
+// testDeclarations start the added lines that declare a test in common
+// ecosystems
+var testDeclarations = []string{
+	"func Test", "def test_", "@Test", "#[test]", "[Fact]", "[Test]",
+	"it(", "test(", "describe(", "it \"", "it '", "describe \"", "describe '",
+}
+
+// addsTestDeclaration reports whether a diff adds a test declaration
+func addsTestDeclaration(diff string) bool {
+	for _, line := range strings.Split(diff, "\n") {
+		if !strings.HasPrefix(line, "+") || strings.HasPrefix(line, "+++") {
+			continue
+		}
+		line = strings.TrimSpace(line[1:])
+		for _, declaration := range testDeclarations {
+			if strings.HasPrefix(line, declaration) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsTestFile reports whether a file is a test by the given gitignore-style
+// patterns or the default ones of common ecosystems
+func IsTestFile(file string, patterns []string) bool {
+	for _, list := range [][]string{patterns, config.DefaultTestPatterns} {
+		for _, pattern := range list {
+			if pattern != "" && codeowners.Match(pattern, file) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isTestFile reports whether a changed file is a test, by the configured and
+// default test patterns
+func (a *Analyzer) isTestFile(file string) bool {
+	var patterns []string
+	if a.config != nil {
+		patterns = a.config.Tests.Patterns
+	}
+	return IsTestFile(file, patterns)
+}
+
+// isTestPath reports whether a file matches the test pattern of any mapping
+func isTestPath(file string, mappings []config.TestMapping) bool {
+	for _, mapping := range mappings {
+		for _, test := range mapping.Tests {
+			if mappingRegex(test).MatchString(file) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mappingRegex converts a mapping pattern to a regex. {dir} matches a directory
+// path, possibly empty, and {name} a file name without its extension. Invalid
+// patterns, which config validation reports, match nothing.
+func mappingRegex(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for pattern != "" {
+		switch {
+		case strings.HasPrefix(pattern, "{dir}/"):
+			b.WriteString("(?:(?P<dir>.+)/)?")
+			pattern = pattern[len("{dir}/"):]
+		case strings.HasPrefix(pattern, "{dir}"):
+			b.WriteString("(?P<dir>.*)")
+			pattern = pattern[len("{dir}"):]
+		case strings.HasPrefix(pattern, "{name}"):
+			b.WriteString("(?P<name>[^/]+?)")
+			pattern = pattern[len("{name}"):]
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[:1]))
+			pattern = pattern[1:]
+		}
+	}
+	b.WriteString("$")
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return neverMatches
+	}
+	return re
+}
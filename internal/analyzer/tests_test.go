@@ -0,0 +1,87 @@
+package analyzer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestMissingTests(t *testing.T) {
+	changes := []*parser.Change{
+		{File: "internal/api/user.go", Action: "M"},
+		{File: "internal/api/user_test.go", Action: "M"},
+		{File: "internal/api/export.go", Action: "A"},
+		{File: "cmd/main.go", Action: "M"},
+		{File: "web/src/cart.ts", Action: "M"},
+		{File: "pkg/legacy.go", Action: "D"},
+		{File: "README.md", Action: "M"},
+		{File: "app/models/user.rb", Action: "M"},
+	}
+	mappings := []config.TestMapping{
+		{Source: "web/src/{dir}/{name}.ts", Tests: []string{"web/test/{dir}/{name}.spec.ts"}},
+	}
+
+	want := []MissingTest{
+		{File: "internal/api/export.go", Expected: []string{"internal/api/export_test.go"}},
+		{File: "web/src/cart.ts", Expected: []string{"web/test/cart.spec.ts"}},
+		{File: "app/models/user.rb", Expected: []string{"spec/models/user_spec.rb", "test/models/user_test.rb"}},
+	}
+	if got := MissingTests(changes, mappings); !reflect.DeepEqual(got, want) {
+		t.Errorf("MissingTests() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	changes = append(changes, &parser.Change{File: "web/test/cart.spec.ts", Action: "A"})
+	if got := MissingTests(changes, mappings); len(got) != 2 {
+		t.Errorf("MissingTests() with the mapped test staged = %+v, want 2 files", got)
+	}
+}
+
+func TestIsTestFile(t *testing.T) {
+	patterns := []string{"qa/", "*.check.lua"}
+	for file, want := range map[string]bool{
+		"internal/api/user_test.go":        true,
+		"web/src/user.spec.ts":             true,
+		"web/src/__tests__/user.ts":        true,
+		"tests/test_user.py":               true,
+		"app/test_user.py":                 true,
+		"spec/models/user_spec.rb":         true,
+		"src/test/java/app/UserTest.java":  true,
+		"src/main/java/app/UserTests.java": true,
+		"qa/smoke.sh":                      true,
+		"lua/user.check.lua":               true,
+		"internal/api/user.go":             false,
+		"src/main/java/app/Testing.java":   false,
+		"docs/testing.md":                  false,
+		"web/src/latest.ts":                false,
+	} {
+		if got := IsTestFile(file, patterns); got != want {
+			t.Errorf("IsTestFile(%q) = %v, want %v", file, got, want)
+		}
+	}
+}
+
+func TestTestOnlyChangesOfEveryEcosystem(t *testing.T) {
+	tests := []struct {
+		name   string
+		change *parser.Change
+	}{
+		{"typescript", &parser.Change{File: "web/src/user.spec.ts", Action: "M", FileExtension: "ts", Added: 6, Diff: "+  it(\"rejects an empty name\", () => {"}},
+		{"python", &parser.Change{File: "tests/test_user.py", Action: "M", FileExtension: "py", Added: 4, Diff: "+def test_rejects_empty_name():"}},
+		{"java", &parser.Change{File: "src/test/java/app/UserTest.java", Action: "M", FileExtension: "java", Added: 5, Diff: "+    @Test\n+    void rejectsEmptyName() {"}},
+		{"ruby", &parser.Change{File: "spec/models/user_spec.rb", Action: "M", FileExtension: "rb", Added: 3, Diff: "+  it \"rejects an empty name\" do"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Analyzer{config: &config.Config{}, changes: []*parser.Change{tt.change}}
+			if msg := a.AnalyzeChanges(context.Background(), tt.change.Added, 0, ""); msg.Action != "test" {
+				t.Errorf("Action = %q, want test", msg.Action)
+			}
+			if patterns := a.detectChangePatterns(tt.change); !contains(patterns, "test-addition") {
+				t.Errorf("patterns = %v, want test-addition", patterns)
+			}
+		})
+	}
+}
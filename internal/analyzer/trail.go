@@ -0,0 +1,105 @@
+package analyzer
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// Step is one step of the decision trail behind the action of an analysis
+type Step struct {
+	Stage  string // The rule or signal considered, such as "branch" or "keywords"
+	Detail string
+}
+
+// Trail returns the steps that decided the action of the last analysis: the
+// shortcut rules, the files and keywords behind each signal, the final scores
+// and any override
+func (a *Analyzer) Trail() []Step {
+	return a.trail
+}
+
+// note adds a step to the decision trail
+func (a *Analyzer) note(stage, format string, args ...interface{}) {
+	step := Step{Stage: stage, Detail: fmt.Sprintf(format, args...)}
+	slog.Debug("decision", "stage", step.Stage, "detail", step.Detail)
+	a.trail = append(a.trail, step)
+}
+
+// maxTrailFiles caps the files listed one by one in the decision trail
+const maxTrailFiles = 10
+
+// noteSignals records the changed files with the action each suggests on its
+// own, and the files behind the keyword and multi-file signals
+func (a *Analyzer) noteSignals() {
+	for i, change := range a.changes {
+		if i == maxTrailFiles {
+			a.note("file", "%d more files", len(a.changes)-maxTrailFiles)
+			break
+		}
+		a.note("file", "%s %s (+%d −%d) → %s", actionVerbs[change.Action], change.File, change.Added, change.Removed, a.files[i].action)
+	}
+
+	if a.config != nil {
+		actions := make([]string, 0, len(a.config.Keywords))
+		for action := range a.config.Keywords {
+			actions = append(actions, action)
+		}
+		sort.Strings(actions)
+		for i, change := range a.changes {
+			for _, action := range actions {
+				keywords := a.config.Keywords[action]
+				names := make([]string, 0, len(keywords))
+				for keyword := range keywords {
+					names = append(names, keyword)
+				}
+				sort.Strings(names)
+				for _, keyword := range names {
+					if n := a.files[i].keywordHits[action][keyword]; n > 0 {
+						a.note("keywords", "%q ×%d in %s → %s %+d", keyword, n, change.File, action, n*keywords[keyword])
+					}
+				}
+			}
+		}
+	}
+
+	for _, pattern := range a.detectMultiFilePatterns() {
+		a.note("pattern", "%s: %s", strings.ReplaceAll(pattern, "-", " "), strings.Join(limitNames(a.patternFiles(pattern), 5), ", "))
+	}
+}
+
+// patternFiles returns the files making up a multi-file pattern
+func (a *Analyzer) patternFiles(pattern string) []string {
+	var files []string
+	for _, change := range a.changes {
+		var matches bool
+		switch pattern {
+		case "feature-addition":
+			matches = change.Action == "A"
+		case "bug-fix-cascade":
+			matches = change.Action == "M"
+		case "test-suite-update":
+			matches = a.isTestFile(change.File)
+		case "config-update":
+			matches = strings.Contains(change.File, "config") || change.FileExtension == "json" ||
+				change.FileExtension == "yaml" || change.FileExtension == "yml"
+		default:
+			matches = true
+		}
+		if matches {
+			files = append(files, change.File)
+		}
+	}
+	return files
+}
+
+// noteScores records the final score of each action and the signals behind it
+func (a *Analyzer) noteScores(scores *actionScores) {
+	for _, action := range scores.ranked() {
+		if len(scores.reasons[action]) == 0 {
+			continue
+		}
+		a.note("score", "%s %.2f: %s", action, scores.scores[action], strings.Join(scores.reasons[action], ", "))
+	}
+}
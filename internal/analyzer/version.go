@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+// semverPattern matches a semantic version such as 1.2.3 or 2.0.0-rc.1+build.5
+const semverPattern = `v?(\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?)`
+
+// bareVersionRegex matches the line of a VERSION file
+var bareVersionRegex = regexp.MustCompile(`^\s*` + semverPattern + `\s*$`)
+
+// versionFieldRegex matches the version field of a manifest, such as
+// "version": "1.2.3", in package.json or version = "1.2.3" in Cargo.toml
+var versionFieldRegex = regexp.MustCompile(`^\s*"?version"?\s*[:=]\s*"` + semverPattern + `"\s*,?\s*$`)
+
+// versionFiles are the files a version bump changes, with the lines it may change
+var versionFiles = map[string]*regexp.Regexp{
+	"VERSION":           bareVersionRegex,
+	"VERSION.txt":       bareVersionRegex,
+	"package.json":      versionFieldRegex,
+	"package-lock.json": versionFieldRegex,
+	"Cargo.toml":        versionFieldRegex,
+	"Cargo.lock":        versionFieldRegex,
+	"pyproject.toml":    versionFieldRegex,
+}
+
+// DetectVersionBump returns the version changes bump to, such as v1.2.3, when
+// they change nothing but the version fields of VERSION files and manifests
+// like package.json and Cargo.toml, all to the same version. It returns "" for
+// any other changes.
+func DetectVersionBump(changes []*parser.Change) string {
+	version := ""
+	for _, change := range changes {
+		re := versionFiles[path.Base(change.File)]
+		if re == nil || change.Action != "M" {
+			return ""
+		}
+		added, removed := 0, 0
+		for _, line := range strings.Split(change.Diff, "\n") {
+			switch {
+			case strings.HasPrefix(line, "+++ "), strings.HasPrefix(line, "--- "):
+				continue
+			case strings.HasPrefix(line, "+"):
+				m := re.FindStringSubmatch(line[1:])
+				if m == nil || version != "" && m[1] != version {
+					return ""
+				}
+				version = m[1]
+				added++
+			case strings.HasPrefix(line, "-"):
+				if !re.MatchString(line[1:]) {
+					return ""
+				}
+				removed++
+			}
+		}
+		if added == 0 || added != removed {
+			return ""
+		}
+	}
+	if version == "" {
+		return ""
+	}
+	return "v" + version
+}
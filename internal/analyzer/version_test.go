@@ -0,0 +1,37 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestDetectVersionBump(t *testing.T) {
+	packageJSON := &parser.Change{File: "web/package.json", Action: "M", Diff: "--- a/web/package.json\n+++ b/web/package.json\n@@ -3 +3 @@\n-  \"version\": \"1.2.2\",\n+  \"version\": \"1.2.3\",\n"}
+	lockfile := &parser.Change{File: "web/package-lock.json", Action: "M", Diff: "@@ -3 +3 @@\n-  \"version\": \"1.2.2\",\n+  \"version\": \"1.2.3\",\n@@ -9 +9 @@\n-      \"version\": \"1.2.2\",\n+      \"version\": \"1.2.3\",\n"}
+	cargo := &parser.Change{File: "Cargo.toml", Action: "M", Diff: "@@ -3 +3 @@\n-version = \"0.9.0\"\n+version = \"1.0.0-rc.1\"\n"}
+	versionFile := &parser.Change{File: "VERSION", Action: "M", Diff: "@@ -1 +1 @@\n-1.2.2\n+1.2.3\n"}
+	withDependency := &parser.Change{File: "package.json", Action: "M", Diff: "@@ -3 +3 @@\n-  \"version\": \"1.2.2\",\n+  \"version\": \"1.2.3\",\n@@ -12,0 +13 @@\n+    \"left-pad\": \"^1.3.0\",\n"}
+	code := &parser.Change{File: "main.go", Action: "M", Diff: "@@ -1 +1 @@\n-const version = \"1.2.2\"\n+const version = \"1.2.3\"\n"}
+	newVersionFile := &parser.Change{File: "VERSION", Action: "A", Diff: "@@ -0,0 +1 @@\n+1.2.3\n"}
+
+	tests := []struct {
+		name    string
+		changes []*parser.Change
+		want    string
+	}{
+		{"package.json and its lockfile", []*parser.Change{packageJSON, lockfile}, "v1.2.3"},
+		{"VERSION file", []*parser.Change{versionFile}, "v1.2.3"},
+		{"Cargo.toml", []*parser.Change{cargo}, "v1.0.0-rc.1"},
+		{"different versions", []*parser.Change{packageJSON, cargo}, ""},
+		{"dependency added", []*parser.Change{withDependency}, ""},
+		{"source file", []*parser.Change{versionFile, code}, ""},
+		{"new VERSION file", []*parser.Change{newVersionFile}, ""},
+		{"no changes", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := DetectVersionBump(tt.changes); got != tt.want {
+			t.Errorf("DetectVersionBump() of %s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// Importance of a changed file next to a source file, by what it holds
+const (
+	testImportance      = 0.5
+	docsImportance      = 0.5
+	generatedImportance = 0.1
+)
+
+// generatedNames are lock files and other files written by tools, which say
+// little about what a change is for however many lines they change
+var generatedNames = map[string]bool{
+	"go.sum": true, "package-lock.json": true, "yarn.lock": true, "pnpm-lock.yaml": true, "Cargo.lock": true,
+	"Gemfile.lock": true, "composer.lock": true, "poetry.lock": true, "Pipfile.lock": true, "Package.resolved": true,
+}
+
+// generatedSuffixes and generatedDirs mark generated and vendored files
+var (
+	generatedSuffixes = []string{".pb.go", "_generated.go", ".gen.go", ".min.js", ".min.css", ".snap", ".lock"}
+	generatedDirs     = []string{"vendor/", "node_modules/", "dist/", "third_party/"}
+)
+
+// FileWeight is how much a changed file counts toward the message: the lines
+// it changes times its importance, so that a large source change outweighs a
+// lock file or a one-line fix elsewhere
+type FileWeight struct {
+	File    string
+	Action  string  // Action the file suggests on its own
+	Type    string  // Commit type of the file on its own, by what it holds before what it changes
+	Topic   string  // Topic of the file
+	Item    string  // Item of the file
+	Purpose string  // Purpose of the file's changes
+	Weight  float64 // Lines changed times the importance of the file
+	Share   float64 // Share of the weight of all the changes, from 0 to 1
+	index   int     // Index of the change
+}
+
+// fileWeights returns the weight of each changed file, heaviest first and in
+// path order among equals
+func (a *Analyzer) fileWeights() []FileWeight {
+	weights := make([]FileWeight, len(a.changes))
+	total := 0.0
+	for i, change := range a.changes {
+		file := a.files[i]
+		weights[i] = FileWeight{
+			File:    change.File,
+			Action:  file.action,
+			Type:    a.fileType(change, file.action),
+			Topic:   file.topic,
+			Item:    file.item,
+			Purpose: file.purpose,
+			// Renames and binary files change no lines but still count
+			Weight: float64(max(change.Added+change.Removed, 1)) * a.fileImportance(change.File),
+			index:  i,
+		}
+		total += weights[i].Weight
+	}
+	for i := range weights {
+		weights[i].Share = weights[i].Weight / total
+	}
+	sort.SliceStable(weights, func(i, j int) bool {
+		if weights[i].Weight != weights[j].Weight {
+			return weights[i].Weight > weights[j].Weight
+		}
+		return weights[i].File < weights[j].File
+	})
+	return weights
+}
+
+// fileImportance returns how much a file's lines count next to those of a
+// source file: less for tests and docs, and little for generated files
+func (a *Analyzer) fileImportance(file string) float64 {
+	base := path.Base(file)
+	if generatedNames[base] {
+		return generatedImportance
+	}
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			return generatedImportance
+		}
+	}
+	for _, dir := range generatedDirs {
+		if strings.HasPrefix(file, dir) || strings.Contains(file, "/"+dir) {
+			return generatedImportance
+		}
+	}
+
+	if a.isTestFile(file) {
+		return testImportance
+	}
+	if ext := path.Ext(base); ext == ".md" || ext == ".txt" || ext == ".rst" || strings.HasPrefix(file, "docs/") {
+		return docsImportance
+	}
+	return 1
+}
+
+// dominant returns the value of the files with the most weight in total, in
+// lexical order among equals; files without a value are left out
+func dominant(weights []FileWeight, value func(FileWeight) string) string {
+	totals := make(map[string]float64)
+	for _, w := range weights {
+		if v := value(w); v != "" {
+			totals[v] += w.Weight
+		}
+	}
+	best := ""
+	for v, total := range totals {
+		if best == "" || total > totals[best] || total == totals[best] && v < best {
+			best = v
+		}
+	}
+	return best
+}
+
+// heaviest returns the heaviest file with a value, which is the first since
+// weights are sorted
+func heaviest(weights []FileWeight, value func(FileWeight) string, want string) FileWeight {
+	for _, w := range weights {
+		if value(w) == want {
+			return w
+		}
+	}
+	return weights[0]
+}
@@ -0,0 +1,77 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestAnalyzeChangesIgnoresStagingOrder(t *testing.T) {
+	changes := []*parser.Change{
+		{File: "README.md", Action: "M", FileExtension: "md", Added: 2, Removed: 1, Diff: "+Mention the cache\n-Old text"},
+		{File: "go.sum", Action: "M", FileExtension: "sum", Added: 40, Removed: 12, Diff: "+example.com/x v1.0.0 h1:abc"},
+		{File: "internal/store/cache.go", Action: "M", FileExtension: "go", Added: 30, Removed: 4, Diff: "+func (c *Cache) Evict(key string) {\n+\tc.cache.Delete(key)\n-\treturn"},
+		{File: "internal/store/cache_test.go", Action: "M", FileExtension: "go", Added: 12, Removed: 0, Diff: "+func TestEvict(t *testing.T) {"},
+	}
+	reversed := make([]*parser.Change, len(changes))
+	for i, change := range changes {
+		reversed[len(changes)-1-i] = change
+	}
+
+	var got []*CommitMessage
+	for _, order := range [][]*parser.Change{changes, reversed} {
+		a := &Analyzer{config: &config.Config{}, changes: order}
+		got = append(got, a.AnalyzeChanges(context.Background(), 84, 17, ""))
+	}
+	for _, msg := range got {
+		if msg.Topic != "store" || msg.Item != "cache" || msg.Purpose != "caching" {
+			t.Errorf("topic, item, purpose = %q, %q, %q, want store, cache, caching", msg.Topic, msg.Item, msg.Purpose)
+		}
+		if msg.Breakdown[0].File != "internal/store/cache.go" {
+			t.Errorf("heaviest file = %q, want internal/store/cache.go", msg.Breakdown[0].File)
+		}
+	}
+	if got[0].Action != got[1].Action {
+		t.Errorf("action depends on staging order: %q and %q", got[0].Action, got[1].Action)
+	}
+}
+
+func TestFileImportance(t *testing.T) {
+	a := &Analyzer{config: &config.Config{}}
+	tests := []struct {
+		file string
+		want float64
+	}{
+		{"internal/store/cache.go", 1},
+		{"internal/store/cache_test.go", testImportance},
+		{"docs/guide.md", docsImportance},
+		{"go.sum", generatedImportance},
+		{"web/package-lock.json", generatedImportance},
+		{"api/v1/user.pb.go", generatedImportance},
+		{"vendor/github.com/x/y.go", generatedImportance},
+	}
+	for _, tt := range tests {
+		if got := a.fileImportance(tt.file); got != tt.want {
+			t.Errorf("fileImportance(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestDominant(t *testing.T) {
+	weights := []FileWeight{
+		{File: "a.go", Topic: "api", Weight: 3},
+		{File: "b.go", Topic: "db", Weight: 2},
+		{File: "c.go", Topic: "db", Weight: 2},
+		{File: "d.go", Topic: "auth", Weight: 4},
+		{File: "e.go", Topic: "cli", Weight: 4},
+	}
+	topic := func(w FileWeight) string { return w.Topic }
+	if got := dominant(weights, topic); got != "auth" {
+		t.Errorf("dominant() = %q, want auth, lexically first of the heaviest", got)
+	}
+	if got := heaviest(weights, topic, "db").File; got != "b.go" {
+		t.Errorf("heaviest() = %q, want b.go", got)
+	}
+}
@@ -0,0 +1,68 @@
+package analyzer
+
+import (
+	"path"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+// IsWhitespaceOnly reports whether changes only change whitespace, as when
+// git diff -w shows nothing: every change modifies a file, and each hunk
+// removes the same text it adds once whitespace and blank lines are left out.
+// Lines rewrapped within a hunk count as whitespace changes too.
+func IsWhitespaceOnly(changes []*parser.Change) bool {
+	if len(changes) == 0 {
+		return false
+	}
+	for _, change := range changes {
+		if change.Action != "M" || change.Added+change.Removed == 0 || !whitespaceOnlyDiff(change.Diff) {
+			return false
+		}
+	}
+	return true
+}
+
+// whitespaceOnlyDiff reports whether every hunk of a diff removes the same
+// text it adds, apart from whitespace
+func whitespaceOnlyDiff(diff string) bool {
+	var removed, added strings.Builder
+	inHunk := false
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			if removed.String() != added.String() {
+				return false
+			}
+			removed.Reset()
+			added.Reset()
+			inHunk = true
+		case !inHunk:
+			// File headers before the first hunk
+		case strings.HasPrefix(line, "-"):
+			removed.WriteString(strings.Join(strings.Fields(line[1:]), ""))
+		case strings.HasPrefix(line, "+"):
+			added.WriteString(strings.Join(strings.Fields(line[1:]), ""))
+		}
+	}
+	return inHunk && removed.String() == added.String()
+}
+
+// whitespaceMessage returns the style message of whitespace-only changes,
+// naming the changed files heaviest first
+func (a *Analyzer) whitespaceMessage(weights []FileWeight) *CommitMessage {
+	var files []string
+	for _, weight := range weights {
+		files = append(files, weight.File)
+	}
+	if len(files) == 0 {
+		for _, change := range a.changes {
+			files = append(files, change.File)
+		}
+	}
+	names := make([]string, len(files))
+	for i, file := range files {
+		names[i] = path.Base(file)
+	}
+	return &CommitMessage{Action: "style", Topic: a.determineTopic(files[0]), Item: joinWords(limitNames(names, 3)), Purpose: "fix whitespace"}
+}
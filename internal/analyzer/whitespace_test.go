@@ -0,0 +1,60 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestIsWhitespaceOnly(t *testing.T) {
+	modify := func(file, diff string) *parser.Change {
+		return &parser.Change{File: file, Action: "M", Added: 1, Removed: 1, Diff: diff}
+	}
+	tests := []struct {
+		name    string
+		changes []*parser.Change
+		want    bool
+	}{
+		{"reindented", []*parser.Change{
+			modify("parser/git.go", "--- a/parser/git.go\n+++ b/parser/git.go\n@@ -3 +3 @@\n-  return fix(issue)\n+\treturn fix(issue)\n"),
+		}, true},
+		{"trailing spaces and blank lines", []*parser.Change{
+			modify("parser/git.go", "@@ -3,2 +3 @@\n-x := 1   \n-\n+x := 1\n@@ -9,0 +9 @@\n+\n"),
+		}, true},
+		{"rewrapped", []*parser.Change{
+			modify("web/app.ts", "@@ -1 +1,3 @@\n-call(a, b)\n+call(\n+  a, b\n+)\n"),
+		}, true},
+		{"code changed", []*parser.Change{
+			modify("parser/git.go", "@@ -3 +3 @@\n-  return 1\n+\treturn 2\n"),
+		}, false},
+		{"line moved between hunks", []*parser.Change{
+			modify("parser/git.go", "@@ -3 +2,0 @@\n-a()\n@@ -9,0 +9 @@\n+a()\n"),
+		}, false},
+		{"new file", []*parser.Change{
+			{File: "parser/git.go", Action: "A", Added: 1, Diff: "@@ -0,0 +1 @@\n+\n"},
+		}, false},
+		{"binary or mode change", []*parser.Change{modify("logo.png", "")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsWhitespaceOnly(tt.changes); got != tt.want {
+				t.Errorf("IsWhitespaceOnly() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWhitespaceFallback(t *testing.T) {
+	// The keywords of the reindented lines do not make it a fix
+	changes := []*parser.Change{
+		{File: "internal/parser/git.go", Action: "M", Added: 2, Removed: 2, Diff: "@@ -3,2 +3,2 @@\n-  // fix the bug\n-  return fix(issue)\n+\t// fix the bug\n+\treturn fix(issue)\n"},
+		{File: "internal/parser/patch.go", Action: "M", Added: 1, Removed: 1, Diff: "@@ -8 +8 @@\n-x := 1 \n+x := 1\n"},
+	}
+	a := NewAnalyzer(changes, config.DefaultConfig())
+	msg := a.AnalyzeChanges(context.Background(), 3, 3, "")
+	if msg.Action != "style" || msg.Topic != "parser" || msg.Item != "git.go and patch.go" {
+		t.Errorf("AnalyzeChanges() = %s(%s) %s, want style(parser) git.go and patch.go", msg.Action, msg.Topic, msg.Item)
+	}
+}
@@ -0,0 +1,209 @@
+package changelog
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/style"
+)
+
+// breakingFooterRegex matches a BREAKING CHANGE footer and captures its text
+var breakingFooterRegex = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: *(.*)$`)
+
+// trailerRegex matches a git trailer such as "Refs: ABC-1" or "Closes #12"
+var trailerRegex = regexp.MustCompile(`^([A-Za-z][A-Za-z-]*: .+|[A-Za-z][A-Za-z-]* #\w+)$`)
+
+// typeTitles are the section titles of the conventional commit types, in display order
+var typeTitles = []struct{ Type, Title string }{
+	{"feat", "Features"},
+	{"fix", "Bug Fixes"},
+	{"perf", "Performance"},
+	{"security", "Security"},
+	{"refactor", "Refactoring"},
+	{"docs", "Documentation"},
+	{"test", "Tests"},
+	{"build", "Build"},
+	{"ci", "CI"},
+	{"style", "Style"},
+	{"chore", "Chores"},
+	{"revert", "Reverts"},
+}
+
+// Entry is a commit read as a conventional commit
+type Entry struct {
+	Commit       *parser.Commit
+	Type         string // Conventional type, empty when the subject has none
+	Scope        string
+	Description  string
+	Breaking     bool
+	BreakingNote string   // Text of the BREAKING CHANGE footer, if any
+	Footers      []string // Other trailers of the message, e.g. Closes #12
+}
+
+// Parse reads commits as conventional commits
+func Parse(commits []*parser.Commit) []*Entry {
+	entries := make([]*Entry, 0, len(commits))
+	for _, commit := range commits {
+		entries = append(entries, ParseCommit(commit))
+	}
+	return entries
+}
+
+// ParseCommit reads a commit as a conventional commit
+func ParseCommit(commit *parser.Commit) *Entry {
+	commitType, scope, breaking, description := style.ParseSubject(commit.Subject)
+	entry := &Entry{Commit: commit, Type: commitType, Scope: scope, Description: description, Breaking: breaking}
+	if m := breakingFooterRegex.FindStringSubmatch(commit.Body); m != nil {
+		entry.Breaking = true
+		entry.BreakingNote = strings.TrimSpace(m[1])
+	}
+	entry.Footers = trailers(commit.Body)
+	return entry
+}
+
+// Area returns the part of the project a commit changed: its scope, or else the
+// directory most of its files are in. Commits touching only root files are "general".
+func (e *Entry) Area() string {
+	if e.Scope != "" {
+		return e.Scope
+	}
+	counts := make(map[string]int)
+	best := ""
+	for _, file := range e.Commit.Files {
+		dir := path.Dir(file)
+		if dir == "." {
+			continue
+		}
+		dir = path.Base(dir)
+		counts[dir]++
+		if counts[dir] > counts[best] || (counts[dir] == counts[best] && dir < best) {
+			best = dir
+		}
+	}
+	if best == "" {
+		return "general"
+	}
+	return best
+}
+
+// MostSignificant returns the entry that best represents a set of commits:
+// breaking changes first, then by type in the order of TypeTitle. Entries
+// without a conventional type are never chosen; nil is returned if all lack one.
+func MostSignificant(entries []*Entry) *Entry {
+	var best *Entry
+	for _, entry := range entries {
+		if TypeTitle(entry.Type) == "" {
+			continue
+		}
+		if best == nil || (entry.Breaking && !best.Breaking) ||
+			(entry.Breaking == best.Breaking && typeRank(entry.Type) < typeRank(best.Type)) {
+			best = entry
+		}
+	}
+	return best
+}
+
+// Group is a set of entries sharing a key
+type Group struct {
+	Key     string
+	Entries []*Entry
+}
+
+// GroupByArea groups entries by Area, largest group first
+func GroupByArea(entries []*Entry) []Group {
+	groups := groupBy(entries, (*Entry).Area)
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].Entries) != len(groups[j].Entries) {
+			return len(groups[i].Entries) > len(groups[j].Entries)
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
+
+// GroupByType groups entries by conventional type in the order of TypeTitle;
+// entries without a known type come last under the key "other"
+func GroupByType(entries []*Entry) []Group {
+	groups := groupBy(entries, func(e *Entry) string {
+		if TypeTitle(e.Type) == "" {
+			return "other"
+		}
+		return e.Type
+	})
+	sort.SliceStable(groups, func(i, j int) bool {
+		return typeRank(groups[i].Key) < typeRank(groups[j].Key)
+	})
+	return groups
+}
+
+// Breaking returns the entries that introduce breaking changes
+func Breaking(entries []*Entry) []*Entry {
+	var breaking []*Entry
+	for _, entry := range entries {
+		if entry.Breaking {
+			breaking = append(breaking, entry)
+		}
+	}
+	return breaking
+}
+
+// TypeTitle returns the section title of a conventional type, or "" for unknown types
+func TypeTitle(commitType string) string {
+	for _, t := range typeTitles {
+		if t.Type == commitType {
+			return t.Title
+		}
+	}
+	if commitType == "other" {
+		return "Other Changes"
+	}
+	return ""
+}
+
+// trailers returns the trailers in the last paragraph of a message body, other
+// than BREAKING CHANGE. The paragraph only counts if every line is a trailer.
+func trailers(body string) []string {
+	paragraphs := strings.Split(strings.TrimSpace(body), "\n\n")
+	var footers []string
+	for _, line := range strings.Split(paragraphs[len(paragraphs)-1], "\n") {
+		line = strings.TrimSpace(line)
+		if breakingFooterRegex.MatchString(line) {
+			continue
+		}
+		if !trailerRegex.MatchString(line) {
+			return nil
+		}
+		footers = append(footers, line)
+	}
+	return footers
+}
+
+// typeRank orders types as in typeTitles, with unknown types last
+func typeRank(commitType string) int {
+	for i, t := range typeTitles {
+		if t.Type == commitType {
+			return i
+		}
+	}
+	return len(typeTitles)
+}
+
+// groupBy groups entries by a key, keeping the order in which keys first appear
+func groupBy(entries []*Entry, key func(*Entry) string) []Group {
+	var groups []Group
+	index := make(map[string]int)
+	for _, entry := range entries {
+		k := key(entry)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, Group{Key: k})
+		}
+		groups[i].Entries = append(groups[i].Entries, entry)
+	}
+	return groups
+}
@@ -0,0 +1,56 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func testEntries() []*Entry {
+	return Parse([]*parser.Commit{
+		{Hash: "aaaaaaaaa", Subject: "fix(api): handle nil user", Files: []string{"internal/api/user.go"}},
+		{Hash: "bbbbbbbbb", Subject: "feat: add export command", Files: []string{"cmd/export.go", "cmd/root.go"}},
+		{Hash: "ccccccccc", Subject: "refactor(db)!: rename tables", Body: "BREAKING CHANGE: tables use plural names"},
+		{Hash: "ddddddddd", Subject: "Update README", Files: []string{"README.md"}},
+	})
+}
+
+func TestParse(t *testing.T) {
+	entries := testEntries()
+	if e := entries[0]; e.Type != "fix" || e.Scope != "api" || e.Description != "handle nil user" || e.Breaking {
+		t.Errorf("entry 0 = %+v", e)
+	}
+	if e := entries[2]; !e.Breaking || e.BreakingNote != "tables use plural names" {
+		t.Errorf("entry 2 = %+v", e)
+	}
+	if e := entries[3]; e.Type != "" || e.Description != "Update README" {
+		t.Errorf("entry 3 = %+v", e)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	entries := testEntries()
+
+	var areas []string
+	for _, g := range GroupByArea(entries) {
+		areas = append(areas, g.Key)
+	}
+	if got := len(areas); got != 4 || areas[0] != "api" || areas[1] != "cmd" {
+		t.Errorf("GroupByArea keys = %v", areas)
+	}
+
+	var types []string
+	for _, g := range GroupByType(entries) {
+		types = append(types, g.Key)
+	}
+	if want := []string{"feat", "fix", "refactor", "other"}; len(types) != len(want) || types[0] != want[0] || types[3] != want[3] {
+		t.Errorf("GroupByType keys = %v, want %v", types, want)
+	}
+
+	if best := MostSignificant(entries); best == nil || best.Commit.Hash != "ccccccccc" {
+		t.Errorf("MostSignificant() = %+v, want the breaking refactor", best)
+	}
+	if best := MostSignificant(entries[:2]); best == nil || best.Type != "feat" {
+		t.Errorf("MostSignificant() = %+v, want the feature", best)
+	}
+}
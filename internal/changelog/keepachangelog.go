@@ -0,0 +1,155 @@
+package changelog
+
+import (
+	"regexp"
+	"strings"
+)
+
+// unreleasedRegex matches the heading of the Unreleased section of a Keep a
+// Changelog file, such as "## [Unreleased]" or "## Unreleased"
+var unreleasedRegex = regexp.MustCompile(`(?i)^##\s+\[?unreleased\b`)
+
+// keepSections are the sections of a Keep a Changelog release, in their order
+var keepSections = []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
+
+// UnreleasedEntry returns the Keep a Changelog section and text of the entry
+// for a commit: Changed for breaking changes, Added for features and Fixed for
+// fixes. Both are empty for other commits, which changelogs leave out.
+func UnreleasedEntry(entry *Entry) (section, text string) {
+	text = entry.Description
+	switch {
+	case entry.Breaking:
+		section = "Changed"
+		if entry.BreakingNote != "" {
+			text = entry.BreakingNote
+		}
+	case entry.Type == "feat":
+		section = "Added"
+	case entry.Type == "fix":
+		section = "Fixed"
+	default:
+		return "", ""
+	}
+	if entry.Scope != "" {
+		text = entry.Scope + ": " + text
+	}
+	if entry.Breaking {
+		text = "**Breaking:** " + text
+	}
+	return section, text
+}
+
+// AddUnreleased adds an entry to a section of the Unreleased changes of a Keep
+// a Changelog file, creating the section, and the Unreleased heading above the
+// latest release, when missing. It reports false, with the content unchanged,
+// when the Unreleased changes already list the entry.
+func AddUnreleased(content, section, text string) (string, bool) {
+	crlf := strings.Contains(content, "\r\n")
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	bullet := "- " + text
+
+	start := -1
+	for i, line := range lines {
+		if unreleasedRegex.MatchString(strings.TrimSpace(line)) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		// The Unreleased heading goes above the latest release, or at the end
+		for i, line := range lines {
+			if strings.HasPrefix(line, "## ") {
+				start = i
+				break
+			}
+		}
+		if start >= 0 {
+			lines = insertLines(lines, start, []string{"## [Unreleased]", ""})
+		} else {
+			for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+				lines = lines[:len(lines)-1]
+			}
+			if len(lines) > 0 {
+				lines = append(lines, "")
+			}
+			start = len(lines)
+			lines = append(lines, "## [Unreleased]", "")
+		}
+	}
+
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "## ") {
+			end = i
+			break
+		}
+	}
+	for _, line := range lines[start+1 : end] {
+		if strings.TrimSpace(line) == bullet {
+			return content, false
+		}
+	}
+
+	heading := -1
+	for i := start + 1; i < end; i++ {
+		if strings.EqualFold(strings.TrimSpace(lines[i]), "### "+section) {
+			heading = i
+			break
+		}
+	}
+	if heading >= 0 {
+		// The entry goes after the last one of the section
+		last := heading
+		for i := heading + 1; i < end && !strings.HasPrefix(lines[i], "### "); i++ {
+			if strings.TrimSpace(lines[i]) != "" {
+				last = i
+			}
+		}
+		lines = insertLines(lines, last+1, []string{bullet})
+	} else {
+		// A new section goes before the first section that follows it in order
+		at := end
+		for i := start + 1; i < end; i++ {
+			if strings.HasPrefix(lines[i], "### ") && sectionRank(strings.TrimSpace(lines[i][4:])) > sectionRank(section) {
+				at = i
+				break
+			}
+		}
+		for at > start+1 && strings.TrimSpace(lines[at-1]) == "" {
+			at--
+		}
+		block := []string{"", "### " + section, bullet}
+		if at == len(lines) || strings.TrimSpace(lines[at]) != "" {
+			block = append(block, "")
+		}
+		lines = insertLines(lines, at, block)
+	}
+
+	updated := strings.Join(lines, "\n")
+	if !strings.HasSuffix(updated, "\n") {
+		updated += "\n"
+	}
+	if crlf {
+		updated = strings.ReplaceAll(updated, "\n", "\r\n")
+	}
+	return updated, true
+}
+
+// sectionRank returns the position of a section in a Keep a Changelog release,
+// after the known sections for others
+func sectionRank(section string) int {
+	for i, known := range keepSections {
+		if strings.EqualFold(known, section) {
+			return i
+		}
+	}
+	return len(keepSections)
+}
+
+// insertLines inserts lines into others at index i
+func insertLines(others []string, i int, lines []string) []string {
+	result := make([]string, 0, len(others)+len(lines))
+	result = append(result, others[:i]...)
+	result = append(result, lines...)
+	return append(result, others[i:]...)
+}
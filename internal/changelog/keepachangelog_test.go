@@ -0,0 +1,82 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestUnreleasedEntry(t *testing.T) {
+	tests := []struct {
+		subject, body, section, text string
+	}{
+		{"feat(api): add user export", "", "Added", "api: add user export"},
+		{"fix: handle nil user", "", "Fixed", "handle nil user"},
+		{"refactor(db)!: rename tables", "BREAKING CHANGE: tables use plural names", "Changed", "**Breaking:** db: tables use plural names"},
+		{"feat!: drop v1 endpoints", "", "Changed", "**Breaking:** drop v1 endpoints"},
+		{"chore: update deps", "", "", ""},
+		{"Update README", "", "", ""},
+	}
+	for _, tt := range tests {
+		section, text := UnreleasedEntry(ParseCommit(&parser.Commit{Subject: tt.subject, Body: tt.body}))
+		if section != tt.section || text != tt.text {
+			t.Errorf("UnreleasedEntry(%q) = %q, %q, want %q, %q", tt.subject, section, text, tt.section, tt.text)
+		}
+	}
+}
+
+func TestAddUnreleased(t *testing.T) {
+	const intro = "# Changelog\n\nAll notable changes are documented here.\n\n"
+	const release = "## [1.0.0] - 2024-06-30\n\n### Added\n- First release\n"
+	tests := []struct {
+		name, content, section, want string
+	}{
+		{
+			"existing section",
+			intro + "## [Unreleased]\n\n### Added\n- Dark mode\n\n### Fixed\n- Crash on start\n\n" + release,
+			"Added",
+			intro + "## [Unreleased]\n\n### Added\n- Dark mode\n- Export\n\n### Fixed\n- Crash on start\n\n" + release,
+		},
+		{
+			"new section in order",
+			intro + "## [Unreleased]\n\n### Added\n- Dark mode\n\n### Fixed\n- Crash on start\n\n" + release,
+			"Changed",
+			intro + "## [Unreleased]\n\n### Added\n- Dark mode\n\n### Changed\n- Export\n\n### Fixed\n- Crash on start\n\n" + release,
+		},
+		{
+			"empty Unreleased",
+			intro + "## [Unreleased]\n\n" + release,
+			"Fixed",
+			intro + "## [Unreleased]\n\n### Fixed\n- Export\n\n" + release,
+		},
+		{
+			"no Unreleased heading",
+			intro + release,
+			"Added",
+			intro + "## [Unreleased]\n\n### Added\n- Export\n\n" + release,
+		},
+		{
+			"no releases",
+			"# Changelog\n",
+			"Added",
+			"# Changelog\n\n## [Unreleased]\n\n### Added\n- Export\n",
+		},
+		{
+			"Windows line endings",
+			"# Changelog\r\n\r\n## Unreleased\r\n\r\n### Added\r\n- Dark mode\r\n",
+			"Added",
+			"# Changelog\r\n\r\n## Unreleased\r\n\r\n### Added\r\n- Dark mode\r\n- Export\r\n",
+		},
+	}
+	for _, tt := range tests {
+		got, added := AddUnreleased(tt.content, tt.section, "Export")
+		if got != tt.want || !added {
+			t.Errorf("AddUnreleased() with %s = %v\n%s\nwant\n%s", tt.name, added, got, tt.want)
+		}
+	}
+
+	listed := intro + "## [Unreleased]\n\n### Added\n- Export\n\n" + release
+	if got, added := AddUnreleased(listed, "Added", "Export"); added || got != listed {
+		t.Errorf("AddUnreleased() of a listed entry = %v, want the changelog unchanged", added)
+	}
+}
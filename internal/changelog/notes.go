@@ -0,0 +1,125 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// notesTypes are the commit types release notes list unless all types are asked for
+var notesTypes = map[string]bool{"feat": true, "fix": true, "perf": true, "security": true, "revert": true}
+
+// issueRefRegex matches issue references such as #12, GH-12 or tracker keys like ABC-123
+var issueRefRegex = regexp.MustCompile(`#\d+\b|\b[A-Z][A-Z0-9]+-\d+\b`)
+
+// Issues returns the issue references in the footers of the entry
+func (e *Entry) Issues() []string {
+	var issues []string
+	for _, footer := range e.Footers {
+		for _, ref := range issueRefRegex.FindAllString(footer, -1) {
+			issues = appendUnique(issues, ref)
+		}
+	}
+	return issues
+}
+
+// NotesOptions controls release notes
+type NotesOptions struct {
+	Title   string // Heading of the notes, e.g. the version
+	RepoURL string // Web URL of the repository for links, empty for no links
+	All     bool   // List every commit type, not only features, fixes, performance, security and reverts
+}
+
+// ReleaseNotes renders markdown release notes with a section for breaking changes
+// followed by a section per commit type. Commit hashes and issue references are
+// linked when the repository URL is known.
+func ReleaseNotes(entries []*Entry, opts NotesOptions) string {
+	var b strings.Builder
+	if opts.Title != "" {
+		fmt.Fprintf(&b, "## %s\n\n", opts.Title)
+	}
+
+	sections := 0
+	if breaking := Breaking(entries); len(breaking) > 0 {
+		b.WriteString("### ⚠ Breaking Changes\n\n")
+		for _, entry := range breaking {
+			text := entry.BreakingNote
+			if text == "" {
+				text = entry.Description
+			}
+			b.WriteString(noteLine(entry, text, opts.RepoURL))
+		}
+		sections++
+	}
+
+	for _, group := range GroupByType(entries) {
+		if !opts.All && !notesTypes[group.Key] {
+			continue
+		}
+		var lines []string
+		for _, entry := range group.Entries {
+			if !entry.IsNoise() {
+				lines = append(lines, noteLine(entry, entry.Description, opts.RepoURL))
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		if sections > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "### %s\n\n%s", TypeTitle(group.Key), strings.Join(lines, ""))
+		sections++
+	}
+
+	if sections == 0 {
+		b.WriteString("_No notable changes._\n")
+	}
+	return b.String()
+}
+
+// noteLine formats an entry as a list item with its scope, commit and issues
+func noteLine(entry *Entry, text, repoURL string) string {
+	line := "- "
+	if entry.Scope != "" {
+		line += "**" + entry.Scope + ":** "
+	}
+	line += text + " (" + commitLink(entry.Commit.Hash, repoURL) + ")"
+
+	if issues := entry.Issues(); len(issues) > 0 {
+		for i, issue := range issues {
+			issues[i] = issueLink(issue, repoURL)
+		}
+		line += ", " + strings.Join(issues, ", ")
+	}
+	return line + "\n"
+}
+
+// commitLink links a commit hash to the repository's web page for it
+func commitLink(hash, repoURL string) string {
+	short := hash
+	if len(short) > 7 {
+		short = short[:7]
+	}
+	if repoURL == "" {
+		return short
+	}
+	return fmt.Sprintf("[%s](%s%s/commit/%s)", short, repoURL, gitlabPath(repoURL), hash)
+}
+
+// issueLink links a #number reference to the repository's issue; other
+// references belong to external trackers and are left as they are
+func issueLink(ref, repoURL string) string {
+	if repoURL == "" || !strings.HasPrefix(ref, "#") {
+		return ref
+	}
+	return fmt.Sprintf("[%s](%s%s/issues/%s)", ref, repoURL, gitlabPath(repoURL), ref[1:])
+}
+
+// gitlabPath returns the "/-" GitLab puts before commit and issue paths
+func gitlabPath(repoURL string) string {
+	if strings.Contains(repoURL, "gitlab") {
+		return "/-"
+	}
+	return ""
+}
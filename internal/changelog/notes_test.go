@@ -0,0 +1,42 @@
+package changelog
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestReleaseNotes(t *testing.T) {
+	entries := Parse([]*parser.Commit{
+		{Hash: "aaaaaaaaa", Subject: "feat(api): add user export", Body: "Closes #12\nRefs: API-7"},
+		{Hash: "bbbbbbbbb", Subject: "fix: handle nil user"},
+		{Hash: "ccccccccc", Subject: "chore: bump deps"},
+		{Hash: "ddddddddd", Subject: "perf(db)!: batch inserts", Body: "BREAKING CHANGE: Insert takes a slice"},
+	})
+
+	if got, want := entries[0].Issues(), []string{"#12", "API-7"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Issues() = %v, want %v", got, want)
+	}
+
+	want := "## v1.2.0\n\n" +
+		"### ⚠ Breaking Changes\n\n" +
+		"- **db:** Insert takes a slice ([ddddddd](https://github.com/o/r/commit/ddddddddd))\n\n" +
+		"### Features\n\n" +
+		"- **api:** add user export ([aaaaaaa](https://github.com/o/r/commit/aaaaaaaaa)), [#12](https://github.com/o/r/issues/12), API-7\n\n" +
+		"### Bug Fixes\n\n" +
+		"- handle nil user ([bbbbbbb](https://github.com/o/r/commit/bbbbbbbbb))\n\n" +
+		"### Performance\n\n" +
+		"- **db:** batch inserts ([ddddddd](https://github.com/o/r/commit/ddddddddd))\n"
+	if got := ReleaseNotes(entries, NotesOptions{Title: "v1.2.0", RepoURL: "https://github.com/o/r"}); got != want {
+		t.Errorf("ReleaseNotes() =\n%s\nwant\n%s", got, want)
+	}
+
+	got := ReleaseNotes(entries[2:3], NotesOptions{RepoURL: "https://gitlab.com/o/r", All: true})
+	if want := "### Chores\n\n- bump deps ([ccccccc](https://gitlab.com/o/r/-/commit/ccccccccc))\n"; got != want {
+		t.Errorf("ReleaseNotes(All) = %q, want %q", got, want)
+	}
+	if got := ReleaseNotes(entries[2:3], NotesOptions{}); got != "_No notable changes._\n" {
+		t.Errorf("ReleaseNotes() without notable changes = %q", got)
+	}
+}
@@ -0,0 +1,106 @@
+package changelog
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// noiseRegex matches subjects of commits that only touch up earlier ones and
+// are left out of a squashed message
+var noiseRegex = regexp.MustCompile(`(?i)^(fixup!|squash!|amend!|wip\b|oops\b|(fix )?typos?\b|address(ed|es)? (review|comments|feedback)|review (comments|feedback)|apply suggestions? from code review)`)
+
+// Squash combines entries into one conventional commit message. The most
+// significant change becomes the subject, the other changes become bullet points
+// and the breaking changes and trailers of every commit are kept as footers.
+// Commits that only touch up others, such as fixups, are left out. The fallback
+// subject is used when no commit follows the convention.
+func Squash(entries []*Entry, fallback string) string {
+	subject := fallback
+	main := MostSignificant(entries)
+	if main != nil {
+		subject = main.Type
+		if scope := commonScope(entries); scope != "" {
+			subject += "(" + scope + ")"
+		}
+		if len(Breaking(entries)) > 0 {
+			subject += "!"
+		}
+		subject += ": " + main.Description
+	}
+
+	ordered := append([]*Entry(nil), entries...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return typeRank(ordered[i].Type) < typeRank(ordered[j].Type)
+	})
+
+	seen := map[string]bool{}
+	if main != nil {
+		seen[strings.ToLower(main.Description)] = true
+	}
+	var bullets []string
+	for _, entry := range ordered {
+		text := entry.Description
+		if entry.Type == "" {
+			text = entry.Commit.Subject
+		}
+		key := strings.ToLower(text)
+		if entry == main || seen[key] || entry.IsNoise() {
+			continue
+		}
+		seen[key] = true
+		bullets = append(bullets, "- "+text)
+	}
+
+	var footers []string
+	for _, entry := range entries {
+		if entry.BreakingNote != "" {
+			footers = appendUnique(footers, "BREAKING CHANGE: "+entry.BreakingNote)
+		}
+	}
+	for _, entry := range entries {
+		for _, footer := range entry.Footers {
+			footers = appendUnique(footers, footer)
+		}
+	}
+
+	sections := []string{subject}
+	if len(bullets) > 0 {
+		sections = append(sections, strings.Join(bullets, "\n"))
+	}
+	if len(footers) > 0 {
+		sections = append(sections, strings.Join(footers, "\n"))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// IsNoise reports whether the commit only touches up others, e.g. a fixup or a
+// "wip" commit, or merges branches, and says nothing about the change itself
+func (e *Entry) IsNoise() bool {
+	return noiseRegex.MatchString(e.Commit.Subject) || strings.HasPrefix(e.Commit.Subject, "Merge ")
+}
+
+// commonScope returns the scope shared by every scoped entry, or "" when they differ
+func commonScope(entries []*Entry) string {
+	scope := ""
+	for _, entry := range entries {
+		if entry.Scope == "" {
+			continue
+		}
+		if scope != "" && !strings.EqualFold(scope, entry.Scope) {
+			return ""
+		}
+		scope = entry.Scope
+	}
+	return scope
+}
+
+// appendUnique appends a line unless the list already contains it
+func appendUnique(lines []string, line string) []string {
+	for _, l := range lines {
+		if strings.EqualFold(l, line) {
+			return lines
+		}
+	}
+	return append(lines, line)
+}
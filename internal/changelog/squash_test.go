@@ -0,0 +1,43 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestSquash(t *testing.T) {
+	entries := Parse([]*parser.Commit{
+		{Hash: "aaaaaaaaa", Subject: "fix(api): handle nil user"},
+		{Hash: "bbbbbbbbb", Subject: "feat(api): add user export", Body: "Streams users as CSV.\n\nRefs: API-12"},
+		{Hash: "ccccccccc", Subject: "fixup! feat(api): add user export"},
+		{Hash: "ddddddddd", Subject: "test(api): cover export", Body: "Refs: API-12\nCloses #40"},
+		{Hash: "eeeeeeeee", Subject: "address review comments"},
+	})
+
+	want := "feat(api): add user export\n\n" +
+		"- handle nil user\n- cover export\n\n" +
+		"Refs: API-12\nCloses #40"
+	if got := Squash(entries, "fallback"); got != want {
+		t.Errorf("Squash() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSquashBreakingAndFallback(t *testing.T) {
+	entries := Parse([]*parser.Commit{
+		{Hash: "aaaaaaaaa", Subject: "feat(api): add user export"},
+		{Hash: "bbbbbbbbb", Subject: "refactor(db): rename tables", Body: "BREAKING CHANGE: tables use plural names"},
+	})
+	want := "refactor!: rename tables\n\n- add user export\n\nBREAKING CHANGE: tables use plural names"
+	if got := Squash(entries, "fallback"); got != want {
+		t.Errorf("Squash() =\n%s\nwant\n%s", got, want)
+	}
+
+	entries = Parse([]*parser.Commit{
+		{Hash: "aaaaaaaaa", Subject: "Update README"},
+		{Hash: "bbbbbbbbb", Subject: "WIP"},
+	})
+	if got, want := Squash(entries, "docs: update readme"), "docs: update readme\n\n- Update README"; got != want {
+		t.Errorf("Squash() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// releaseWordRegex matches the words of release and version-bump subjects
+var releaseWordRegex = regexp.MustCompile(`(?i)\b(release|bump|version)\b`)
+
+// versionRegex matches a semantic version such as 1.2.3 or v2.0.0-rc.1
+var versionRegex = regexp.MustCompile(`\bv?\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?\b`)
+
+// VersionBump returns the version a release or version-bump subject names, such
+// as v1.3.0 in "chore(release): v1.3.0" or "bump version to 1.3.0", or "" for
+// other subjects. Versions without a v prefix get one when the latest tag has it.
+func VersionBump(subject, latestTag string) string {
+	if !releaseWordRegex.MatchString(subject) {
+		return ""
+	}
+	version := versionRegex.FindString(subject)
+	if version != "" && !strings.HasPrefix(version, "v") && strings.HasPrefix(latestTag, "v") {
+		version = "v" + version
+	}
+	return version
+}
+
+// TagMessage renders the message of an annotated tag: the tag name, then the
+// changes since the previous tag as ReleaseBody lists them
+func TagMessage(name, previous string, entries []*Entry) string {
+	message := name + "\n"
+	if body := ReleaseBody(previous, entries); body != "" {
+		message += "\n" + body
+	}
+	return message
+}
+
+// ReleaseBody lists the changes since the previous tag by type, breaking
+// changes first, for the message of a release commit or tag. Commits that only
+// touch up others are left out.
+func ReleaseBody(previous string, entries []*Entry) string {
+	var b strings.Builder
+	if previous != "" {
+		fmt.Fprintf(&b, "Changes since %s:\n", previous)
+	}
+
+	if breaking := Breaking(entries); len(breaking) > 0 {
+		b.WriteString("\nBreaking Changes:\n")
+		for _, entry := range breaking {
+			text := entry.BreakingNote
+			if text == "" {
+				text = entry.Description
+			}
+			b.WriteString(tagLine(entry, text))
+		}
+	}
+
+	for _, group := range GroupByType(entries) {
+		var lines []string
+		for _, entry := range group.Entries {
+			if entry.IsNoise() {
+				continue
+			}
+			text := entry.Description
+			if entry.Type == "" {
+				text = entry.Commit.Subject
+			}
+			lines = append(lines, tagLine(entry, text))
+		}
+		if len(lines) > 0 {
+			fmt.Fprintf(&b, "\n%s:\n%s", TypeTitle(group.Key), strings.Join(lines, ""))
+		}
+	}
+	return strings.TrimPrefix(b.String(), "\n")
+}
+
+// tagLine renders an entry as a line of a tag message
+func tagLine(entry *Entry, text string) string {
+	if entry.Scope != "" {
+		text = entry.Scope + ": " + text
+	}
+	return fmt.Sprintf("- %s (%s)\n", text, entry.Commit.ShortHash())
+}
@@ -0,0 +1,49 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestVersionBump(t *testing.T) {
+	tests := []struct {
+		subject, latest, want string
+	}{
+		{"chore(release): v1.3.0", "v1.2.0", "v1.3.0"},
+		{"chore: bump version to 1.3.0", "v1.2.0", "v1.3.0"},
+		{"chore: bump version to 1.3.0", "1.2.0", "1.3.0"},
+		{"build: release 2.0.0-rc.1", "", "2.0.0-rc.1"},
+		{"fix(api): handle 1.2.3 clients", "v1.2.0", ""},
+		{"chore(release): prepare next version", "v1.2.0", ""},
+	}
+	for _, tt := range tests {
+		if got := VersionBump(tt.subject, tt.latest); got != tt.want {
+			t.Errorf("VersionBump(%q, %q) = %q, want %q", tt.subject, tt.latest, got, tt.want)
+		}
+	}
+}
+
+func TestTagMessage(t *testing.T) {
+	entries := Parse([]*parser.Commit{
+		{Hash: "aaaaaaaaa", Subject: "feat(api): add user export"},
+		{Hash: "bbbbbbbbb", Subject: "fix: handle nil user"},
+		{Hash: "ccccccccc", Subject: "fixup! fix: handle nil user"},
+		{Hash: "ddddddddd", Subject: "refactor(db)!: rename tables", Body: "BREAKING CHANGE: tables use plural names"},
+		{Hash: "eeeeeeeee", Subject: "Update README"},
+	})
+
+	want := "v1.3.0\n\nChanges since v1.2.0:\n" +
+		"\nBreaking Changes:\n- db: tables use plural names (ddddddd)\n" +
+		"\nFeatures:\n- api: add user export (aaaaaaa)\n" +
+		"\nBug Fixes:\n- handle nil user (bbbbbbb)\n" +
+		"\nRefactoring:\n- db: rename tables (ddddddd)\n" +
+		"\nOther Changes:\n- Update README (eeeeeee)\n"
+	if got := TagMessage("v1.3.0", "v1.2.0", entries); got != want {
+		t.Errorf("TagMessage() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got, want := TagMessage("v0.1.0", "", nil), "v0.1.0\n"; got != want {
+		t.Errorf("TagMessage() without changes = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,205 @@
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
+)
+
+// Locations lists where GitHub and GitLab look for a CODEOWNERS file, in order
+var Locations = []string{"CODEOWNERS", ".github/CODEOWNERS", ".gitlab/CODEOWNERS", "docs/CODEOWNERS"}
+
+// Rule is a single CODEOWNERS line: a path pattern and its owners
+type Rule struct {
+	Pattern string
+	Owners  []string
+	regex   *regexp.Regexp
+}
+
+// File is a parsed CODEOWNERS file
+type File struct {
+	Path  string
+	Rules []Rule
+}
+
+// Find loads the CODEOWNERS file of the current repository. It returns nil
+// without an error when the repository has none.
+func Find(ctx context.Context) (*File, error) {
+	out, err := gitcmd.Output(ctx, "", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, nil
+	}
+	root := strings.TrimSpace(out)
+
+	for _, location := range Locations {
+		path := filepath.Join(root, filepath.FromSlash(location))
+		if _, err := os.Stat(path); err == nil {
+			return Load(path)
+		}
+	}
+	return nil, nil
+}
+
+// Load parses the CODEOWNERS file at path
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading CODEOWNERS file %s: %w", path, err)
+	}
+	return Parse(path, data), nil
+}
+
+// sectionRegex matches GitLab section headers such as "[Docs]", "^[Docs]" or
+// "[Docs][2] @org/docs", but not bracket patterns such as "[abc]*.go"
+var sectionRegex = regexp.MustCompile(`^\^?\[[^\]]+\](?:\[[0-9]+\])?(?:\s|$)`)
+
+// Parse parses CODEOWNERS content. Comments, blank lines and GitLab section
+// headers are skipped.
+func Parse(path string, data []byte) *File {
+	f := &File{Path: path}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" || strings.HasPrefix(line, "#") || sectionRegex.MatchString(line) {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		f.Rules = append(f.Rules, Rule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+			regex:   patternRegex(fields[0]),
+		})
+	}
+	return f
+}
+
+// Owners returns the owners of a repository-relative path. As in GitHub, the
+// last matching rule wins; a matching rule without owners unassigns the path.
+func (f *File) Owners(path string) []string {
+	if f == nil {
+		return nil
+	}
+	path = strings.TrimPrefix(filepath.ToSlash(path), "/")
+	for i := len(f.Rules) - 1; i >= 0; i-- {
+		if f.Rules[i].regex.MatchString(path) {
+			return f.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+var teamSuffixRegex = regexp.MustCompile(`[-_](team|squad|owners|maintainers|devs|group)$`)
+
+// Area derives a scope from the owners of a path: the first team owner such as
+// @org/payments-team becomes "payments". Individual users and e-mail owners do
+// not name an area, so an empty string is returned for them.
+func Area(owners []string) string {
+	for _, owner := range owners {
+		owner = strings.TrimPrefix(owner, "@")
+		slash := strings.LastIndex(owner, "/")
+		if slash < 0 || strings.Contains(owner, "@") {
+			continue
+		}
+		team := strings.ToLower(owner[slash+1:])
+		team = teamSuffixRegex.ReplaceAllString(team, "")
+		if team != "" {
+			return team
+		}
+	}
+	return ""
+}
+
+// matchRegexes caches the regexes of the patterns given to Match, which are
+// matched against every changed file
+var matchRegexes sync.Map
+
+// Match reports whether a repository-relative path matches a gitignore-style
+// pattern, with the same rules as CODEOWNERS patterns
+func Match(pattern, path string) bool {
+	re, ok := matchRegexes.Load(pattern)
+	if !ok {
+		re, _ = matchRegexes.LoadOrStore(pattern, patternRegex(pattern))
+	}
+	return re.(*regexp.Regexp).MatchString(strings.TrimPrefix(filepath.ToSlash(path), "/"))
+}
+
+// patternRegex converts a gitignore-style CODEOWNERS pattern into a regex matching
+// repository-relative paths. Patterns containing a slash (other than a trailing
+// one) are anchored to the repository root; others match at any depth.
+func patternRegex(pattern string) *regexp.Regexp {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+		case pattern[i] == '[':
+			class, n := bracketClass(pattern[i:])
+			if n == 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			b.WriteString(class)
+			i += n - 1
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	if dirOnly {
+		b.WriteString("/.*$")
+	} else {
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.MustCompile(b.String())
+}
+
+// bracketClass converts a leading "[...]" character class of a pattern, with
+// "!" or "^" negating it, into a regex class that never matches a slash. It
+// returns the class and the length of pattern it consumed, or 0 when the
+// pattern does not start with a valid class.
+func bracketClass(pattern string) (string, int) {
+	end := strings.IndexByte(pattern[1:], ']') + 1
+	if end <= 1 {
+		return "", 0
+	}
+	body := pattern[1:end]
+	class := "["
+	if body[0] == '!' || body[0] == '^' {
+		class = "[^/"
+		body = body[1:]
+	}
+	class += strings.NewReplacer(`\`, `\\`, "[", `\[`).Replace(body) + "]"
+	if _, err := regexp.Compile(class); err != nil {
+		return "", 0
+	}
+	return class, end + 1
+}
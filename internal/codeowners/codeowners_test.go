@@ -0,0 +1,62 @@
+package codeowners
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOwners(t *testing.T) {
+	f := Parse("CODEOWNERS", []byte(`# Default owners
+*                   @org/platform-team
+*.md                @org/docs
+/internal/payments/ @org/payments-team @alice
+apps/**/api         @org/api-squad
+/vendor/
+
+[Section]
+/build/ @bob # release tooling
+^[Optional section] @carol
+[Docs][2] @org/docs
+[bq]*.go            @org/abc-team
+/lib/[!t]*.rb       @dave
+`))
+
+	tests := []struct {
+		path   string
+		owners []string
+	}{
+		{"main.go", []string{"@org/platform-team"}},
+		{"internal/payments/charge.go", []string{"@org/payments-team", "@alice"}},
+		{"docs/guide/README.md", []string{"@org/docs"}},
+		{"apps/web/v2/api/routes.go", []string{"@org/api-squad"}},
+		{"vendor/lib/x.go", []string{}},
+		{"build/release.sh", []string{"@bob"}},
+		{"src/internal/payments/x.go", []string{"@org/platform-team"}},
+		{"cmd/beta.go", []string{"@org/abc-team"}},
+		{"delta.go", []string{"@org/platform-team"}},
+		{"lib/parser.rb", []string{"@dave"}},
+		{"lib/test.rb", []string{"@org/platform-team"}},
+	}
+	for _, tt := range tests {
+		got := f.Owners(tt.path)
+		if len(got) == 0 && len(tt.owners) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.owners) {
+			t.Errorf("Owners(%q) = %v, want %v", tt.path, got, tt.owners)
+		}
+	}
+}
+
+func TestArea(t *testing.T) {
+	tests := map[string][]string{
+		"payments": {"@org/payments-team", "@alice"},
+		"api":      {"@alice", "@org/API_squad"},
+		"":         {"@alice", "bob@example.com"},
+	}
+	for want, owners := range tests {
+		if got := Area(owners); got != want {
+			t.Errorf("Area(%v) = %q, want %q", owners, got, want)
+		}
+	}
+}
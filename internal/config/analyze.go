@@ -0,0 +1,33 @@
+package config
+
+import "strings"
+
+// AnalyzeConfig controls the commit statistics of gitmit analyze
+type AnalyzeConfig struct {
+	IgnoreAuthors []string `json:"ignoreAuthors,omitempty" yaml:"ignoreAuthors,omitempty" toml:"ignoreAuthors,omitempty"` // Bots and automated authors left out of the statistics
+}
+
+// defaultIgnoredAuthors match the usual dependency and CI bots
+var defaultIgnoredAuthors = []string{"[bot]", "dependabot", "renovate", "github-actions"}
+
+// IgnoresAuthor reports whether an author is left out of the statistics: when
+// their name or email contains one of the ignored authors, ignoring case
+func (a AnalyzeConfig) IgnoresAuthor(name, email string) bool {
+	name, email = strings.ToLower(name), strings.ToLower(email)
+	for _, author := range a.IgnoreAuthors {
+		author = strings.ToLower(strings.TrimSpace(author))
+		if author != "" && (strings.Contains(name, author) || strings.Contains(email, author)) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateAnalyze checks the ignored authors
+func validateAnalyze(analyze AnalyzeConfig, add func(key, format string, args ...interface{})) {
+	for _, author := range analyze.IgnoreAuthors {
+		if strings.TrimSpace(author) == "" {
+			add("analyze.ignoreAuthors", "empty author would ignore every commit")
+		}
+	}
+}
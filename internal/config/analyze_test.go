@@ -0,0 +1,31 @@
+package config
+
+import "testing"
+
+func TestAnalyzeIgnoresAuthor(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := mergeConfigData(cfg, []byte(`{"analyze": {"ignoreAuthors": ["Jenkins"]}}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, email string
+		want        bool
+	}{
+		{"dependabot[bot]", "49699333+dependabot[bot]@users.noreply.github.com", true},
+		{"Renovate Bot", "bot@renovateapp.com", true},
+		{"jenkins", "ci@example.com", true},
+		{"Ada Lovelace", "ada@example.com", false},
+	}
+	for _, tt := range tests {
+		if got := cfg.Analyze.IgnoresAuthor(tt.name, tt.email); got != tt.want {
+			t.Errorf("IgnoresAuthor(%q, %q) = %v, want %v", tt.name, tt.email, got, tt.want)
+		}
+	}
+
+	cfg.Analyze.IgnoreAuthors = append(cfg.Analyze.IgnoreAuthors, " ")
+	issues := Validate(cfg)
+	if len(issues) != 1 || issues[0].Key != "analyze.ignoreAuthors" {
+		t.Errorf("Validate() = %v, want an empty author", issues)
+	}
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// ChangelogConfig controls the entries added to a Keep a Changelog file when
+// feat, fix and breaking changes are committed
+type ChangelogConfig struct {
+	Enabled bool   `json:"enabled" yaml:"enabled" toml:"enabled"`                      // Offer to add an Unreleased entry for each such commit
+	File    string `json:"file,omitempty" yaml:"file,omitempty" toml:"file,omitempty"` // Changelog path relative to the repository root
+}
+
+// defaultChangelogFile is the changelog the entries are added to
+const defaultChangelogFile = "CHANGELOG.md"
+
+func mergeChangelog(cfg *ChangelogConfig, fileChangelog ChangelogConfig) {
+	if fileChangelog.File != "" {
+		cfg.File = fileChangelog.File
+	}
+}
+
+// validateChangelog checks that the changelog is a file of the repository
+func validateChangelog(changelog ChangelogConfig, add func(key, format string, args ...interface{})) {
+	file := strings.TrimSpace(changelog.File)
+	switch {
+	case file == "":
+		add("changelog.file", "must name the changelog, e.g. %s", defaultChangelogFile)
+	case filepath.IsAbs(file) || strings.HasPrefix(filepath.ToSlash(filepath.Clean(file)), "../"):
+		add("changelog.file", "%q must be a path inside the repository", file)
+	}
+}
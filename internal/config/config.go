@@ -1,88 +1,124 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 )
 
-// Config represents the structure of .gitmit.json
+// Config represents the structure of .gitmit.json (or .gitmit.yaml / .gitmit.toml)
 type Config struct {
-	Engine            string                       `json:"engine"` // heuristic or ollama
-	Ollama            OllamaConfig                 `json:"ollama"` // Ollama specific config
-	TopicMappings     map[string]string            `json:"topicMappings"`
-	KeywordMappings   map[string]string            `json:"keywordMappings"`
-	ProjectType       string                       `json:"projectType"`       // go, nodejs, python, etc.
-	Keywords          map[string]map[string]int    `json:"keywords"`          // action -> keyword -> score
-	Templates         map[string]map[string]string `json:"templates"`         // Custom templates
-	DiffStatThreshold float64                      `json:"diffStatThreshold"` // Threshold for add/delete ratio
-	NormalizeScoring  bool                         `json:"normalizeScoring"`  // Whether to use normalized confidence weights
-	SignalWeights     map[string]float64           `json:"signalWeights"`     // Weights for different signal sources
-	MaxSubjectLength  int                          `json:"maxSubjectLength"`  // Max length for the first line
-	MaxBodyLength     int                          `json:"maxBodyLength"`     // Max length for body lines
+	Engine            string                             `json:"engine" yaml:"engine" toml:"engine"` // heuristic or ollama
+	Ollama            OllamaConfig                       `json:"ollama" yaml:"ollama" toml:"ollama"` // Ollama specific config
+	TopicMappings     map[string]string                  `json:"topicMappings" yaml:"topicMappings" toml:"topicMappings"`
+	KeywordMappings   map[string]string                  `json:"keywordMappings" yaml:"keywordMappings" toml:"keywordMappings"`
+	ProjectType       string                             `json:"projectType" yaml:"projectType" toml:"projectType"`                                        // Language pack: go, nodejs, python, etc.
+	Languages         map[string]LanguageConfig          `json:"languages,omitempty" yaml:"languages,omitempty" toml:"languages,omitempty"`                // Language packs added to or extending the built-in ones
+	Keywords          map[string]map[string]int          `json:"keywords" yaml:"keywords" toml:"keywords"`                                                 // action -> keyword -> score
+	Templates         map[string]map[string]TemplateList `json:"templates" yaml:"templates" toml:"templates"`                                              // Custom templates added to or replacing the template pack
+	DiffStatThreshold float64                            `json:"diffStatThreshold" yaml:"diffStatThreshold" toml:"diffStatThreshold"`                      // Threshold for add/delete ratio
+	NormalizeScoring  bool                               `json:"normalizeScoring" yaml:"normalizeScoring" toml:"normalizeScoring"`                         // Whether to use normalized confidence weights
+	SignalWeights     map[string]float64                 `json:"signalWeights" yaml:"signalWeights" toml:"signalWeights"`                                  // Weights for different signal sources
+	MaxSubjectLength  int                                `json:"maxSubjectLength" yaml:"maxSubjectLength" toml:"maxSubjectLength"`                         // Max length for the first line
+	MaxBodyLength     int                                `json:"maxBodyLength" yaml:"maxBodyLength" toml:"maxBodyLength"`                                  // Max length for body lines
+	SubjectOverflow   string                             `json:"subjectOverflow" yaml:"subjectOverflow" toml:"subjectOverflow"`                            // shorten or wrap subjects longer than maxSubjectLength
+	LearnStyle        bool                               `json:"learnStyle" yaml:"learnStyle" toml:"learnStyle"`                                           // Match the style of the repository's commit log
+	ImperativeMood    bool                               `json:"imperativeMood" yaml:"imperativeMood" toml:"imperativeMood"`                               // Normalize the leading verb of suggestions to imperative mood
+	ScopeAliases      map[string]string                  `json:"scopeAliases,omitempty" yaml:"scopeAliases,omitempty" toml:"scopeAliases,omitempty"`       // Raw scope -> canonical scope
+	Scopes            []string                           `json:"scopes,omitempty" yaml:"scopes,omitempty" toml:"scopes,omitempty"`                         // Known scopes; learned from the log when empty
+	StrictScopes      bool                               `json:"strictScopes" yaml:"strictScopes" toml:"strictScopes"`                                     // Only allow known scopes
+	Types             []string                           `json:"types,omitempty" yaml:"types,omitempty" toml:"types,omitempty"`                            // Commit types suggestions may use; every type when empty
+	Codeowners        CodeownersConfig                   `json:"codeowners" yaml:"codeowners" toml:"codeowners"`                                           // CODEOWNERS scope inference
+	TemplateFile      string                             `json:"templateFile,omitempty" yaml:"templateFile,omitempty" toml:"templateFile,omitempty"`       // Template pack used instead of templates.json
+	Paths             map[string]PathConfig              `json:"paths,omitempty" yaml:"paths,omitempty" toml:"paths,omitempty"`                            // Overrides per monorepo directory
+	Rules             RulesConfig                        `json:"rules" yaml:"rules" toml:"rules"`                                                          // Rules commit messages must follow
+	Spellcheck        SpellcheckConfig                   `json:"spellcheck" yaml:"spellcheck" toml:"spellcheck"`                                           // Typo check of suggested subjects
+	Safety            SafetyConfig                       `json:"safety" yaml:"safety" toml:"safety"`                                                       // Leftover debug statements and conflict markers
+	Tests             TestsConfig                        `json:"tests" yaml:"tests" toml:"tests"`                                                          // Nudge when sources change without their tests
+	Risk              RiskConfig                         `json:"risk" yaml:"risk" toml:"risk"`                                                             // Risk score of staged changes
+	Analyze           AnalyzeConfig                      `json:"analyze" yaml:"analyze" toml:"analyze"`                                                    // Commit statistics
+	Tickets           TicketsConfig                      `json:"tickets" yaml:"tickets" toml:"tickets"`                                                    // Jira or Linear ticket of the branch
+	Theme             ThemeConfig                        `json:"theme" yaml:"theme" toml:"theme"`                                                          // Colors and emoji of terminal output
+	Timeouts          TimeoutsConfig                     `json:"timeouts" yaml:"timeouts" toml:"timeouts"`                                                 // How long git and the AI model may take
+	History           HistoryConfig                      `json:"history" yaml:"history" toml:"history"`                                                    // Retention of the suggestion history
+	Plugins           PluginsConfig                      `json:"plugins" yaml:"plugins" toml:"plugins"`                                                    // Executables adding diff hints, scopes and message rules
+	Hooks             HooksConfig                        `json:"hooks" yaml:"hooks" toml:"hooks"`                                                          // Shell commands run around suggestions and commits
+	Changelog         ChangelogConfig                    `json:"changelog" yaml:"changelog" toml:"changelog"`                                              // Keep a Changelog entries added when committing
+	Seed              int64                              `json:"seed,omitempty" yaml:"seed,omitempty" toml:"seed,omitempty"`                               // Seed of the variety in template choices; random when 0
+	Deterministic     bool                               `json:"deterministic" yaml:"deterministic" toml:"deterministic"`                                  // Leave the variety out, breaking ties lexically
+	UpdateCheck       bool                               `json:"updateCheck" yaml:"updateCheck" toml:"updateCheck"`                                        // Check for new releases once a day and print a notice
+	CommitTemplate    bool                               `json:"commitTemplate" yaml:"commitTemplate" toml:"commitTemplate"`                               // Add the sections of git's commit.template to messages
+	BranchPolicies    []BranchPolicy                     `json:"branchPolicies,omitempty" yaml:"branchPolicies,omitempty" toml:"branchPolicies,omitempty"` // Rules applied per branch name pattern
+	Fallbacks         []FallbackRule                     `json:"fallbacks,omitempty" yaml:"fallbacks,omitempty" toml:"fallbacks,omitempty"`                // Rules deciding clear-cut changes before scoring
 }
 
 // OllamaConfig represents the structure of the ollama configuration block
 type OllamaConfig struct {
-	Model       string  `json:"model"`
-	URL         string  `json:"url"`
-	Temperature float64 `json:"temperature"`
+	Model       string  `json:"model" yaml:"model" toml:"model"`
+	URL         string  `json:"url" yaml:"url" toml:"url"`
+	Temperature float64 `json:"temperature" yaml:"temperature" toml:"temperature"`
 }
 
-// LoadConfig loads the configuration with hierarchy: Local (.gitmit.json) → Global (~/.gitmit.json) → Default (embedded)
-func LoadConfig() (*Config, error) {
-	// Initialize with default empty config
-	cfg := &Config{
-		Engine: "heuristic",
-		Ollama: OllamaConfig{
-			Model:       "qwen2.5-coder:7b",
-			URL:         "http://localhost:11434",
-			Temperature: 0.2,
-		},
-		TopicMappings:     make(map[string]string),
-		KeywordMappings:   make(map[string]string),
-		Keywords:          make(map[string]map[string]int),
-		Templates:         make(map[string]map[string]string),
-		DiffStatThreshold: 0.5,
-		NormalizeScoring:  true,
-		SignalWeights: map[string]float64{
-			"branch":   0.35,
-			"diffStat": 0.25,
-			"keywords": 0.25,
-			"patterns": 0.15,
-		},
-		MaxSubjectLength: 50,
-		MaxBodyLength:    72,
-	}
+// CodeownersConfig controls how the repository's CODEOWNERS file is used
+type CodeownersConfig struct {
+	Enabled       bool `json:"enabled" yaml:"enabled" toml:"enabled"`                   // Use the owning team as the scope
+	MentionOwners bool `json:"mentionOwners" yaml:"mentionOwners" toml:"mentionOwners"` // List the owners in the message body
+}
+
+// LoadConfig loads the configuration with hierarchy: Environment (GITMIT_*) → Local (.gitmit.json) → Global ($XDG_CONFIG_HOME/gitmit/config.json) → Default (embedded)
+// Each file level may use JSON, YAML or TOML; the format is detected from the file extension.
+func LoadConfig(ctx context.Context) (*Config, error) {
+	// Initialize with default config
+	cfg := DefaultConfig()
 
 	// 1. Try to load embedded default config (optional)
-	// For now, we'll use the hardcoded defaults above
+	// For now, we use the hardcoded defaults from DefaultConfig
 
-	// 2. Try to load global config from ~/.gitmit.json
-	homeDir, err := os.UserHomeDir()
-	if err == nil {
-		globalConfigPath := filepath.Join(homeDir, ".gitmit.json")
-		if err := mergeConfigFromFile(cfg, globalConfigPath); err == nil {
-			// Successfully loaded global config
+	// 2. Try to load global config from $XDG_CONFIG_HOME/gitmit/config.* or ~/.gitmit.*
+	if globalConfigPath := GlobalConfigFile(); globalConfigPath != "" {
+		if err := mergeConfigFromFile(cfg, globalConfigPath); err != nil {
+			return nil, err
 		}
 	}
 
-	// 3. Try to load local config from .gitmit.json in current working directory
-	localConfigPath := ".gitmit.json"
-	if err := mergeConfigFromFile(cfg, localConfigPath); err == nil {
-		// Successfully loaded local config
+	// 3. Try to load local config from .gitmit.{json,yaml,yml,toml} at the top of the working tree
+	globalHooks := cfg.Hooks
+	globalTickets := cfg.Tickets
+	localDir := LocalDir(ctx)
+	if localConfigPath := FindConfigFile(localDir); localConfigPath != "" {
+		if err := mergeConfigFromFile(cfg, localConfigPath); err != nil {
+			return nil, err
+		}
 	}
 
 	// Also support legacy .commit_suggest.json for backward compatibility
-	legacyConfigPath := ".commit_suggest.json"
+	legacyConfigPath := filepath.Join(localDir, ".commit_suggest.json")
 	if err := mergeConfigFromFile(cfg, legacyConfigPath); err == nil {
 		// Successfully loaded legacy config
 	}
 
+	// Hooks run any command, so a cloned repository may only set them when
+	// the global config allows it
+	if cfg.Hooks != globalHooks && !globalHooks.AllowRepository {
+		slog.Warn("hooks of the repository config ignored; set hooks.allowRepository in the global config to run them")
+		cfg.Hooks = globalHooks
+	}
+	if guardTickets(&cfg.Tickets, globalTickets) {
+		slog.Warn("tickets.url, tickets.user and tickets.token of the repository config ignored; set tickets.allowRepository in the global config to use them")
+	}
+
+	// 4. Apply GITMIT_* environment variable overrides
+	if err := applyEnvOverrides(cfg); err != nil {
+		return nil, err
+	}
+	normalizePaths(cfg)
+
 	// Auto-detect project type if not specified
 	if cfg.ProjectType == "" {
-		cfg.ProjectType = DetectProjectType()
+		cfg.ProjectType = detectProjectType(localDir, cfg.Languages)
 	}
 
 	// Load language-specific defaults based on project type
@@ -91,107 +127,86 @@ func LoadConfig() (*Config, error) {
 	return cfg, nil
 }
 
-// DetectProjectType automatically detects the project type by checking for characteristic files
-func DetectProjectType() string {
-	// Check for Go project
-	if _, err := os.Stat("go.mod"); err == nil {
-		return "go"
-	}
-
-	// Check for Node.js project
-	if _, err := os.Stat("package.json"); err == nil {
-		return "nodejs"
-	}
-
-	// Check for Python project
-	if _, err := os.Stat("requirements.txt"); err == nil {
-		return "python"
+// ConfigFiles returns the existing config files in the order LoadConfig merges them
+func ConfigFiles(ctx context.Context) []string {
+	var files []string
+	if path := GlobalConfigFile(); path != "" {
+		files = append(files, path)
 	}
-	if _, err := os.Stat("setup.py"); err == nil {
-		return "python"
+	localDir := LocalDir(ctx)
+	if path := FindConfigFile(localDir); path != "" {
+		files = append(files, path)
 	}
-	if _, err := os.Stat("pyproject.toml"); err == nil {
-		return "python"
+	legacy := filepath.Join(localDir, ".commit_suggest.json")
+	if _, err := os.Stat(legacy); err == nil {
+		files = append(files, legacy)
 	}
-
-	// Check for Java project
-	if _, err := os.Stat("pom.xml"); err == nil {
-		return "java"
-	}
-	if _, err := os.Stat("build.gradle"); err == nil {
-		return "java"
-	}
-
-	// Check for Ruby project
-	if _, err := os.Stat("Gemfile"); err == nil {
-		return "ruby"
-	}
-
-	// Check for Rust project
-	if _, err := os.Stat("Cargo.toml"); err == nil {
-		return "rust"
-	}
-
-	// Check for PHP project
-	if _, err := os.Stat("composer.json"); err == nil {
-		return "php"
-	}
-
-	return "generic"
+	return files
 }
 
-// loadLanguageDefaults loads language-specific keyword mappings and scoring
-func loadLanguageDefaults(cfg *Config) {
-	switch cfg.ProjectType {
-	case "go":
-		// Go-specific keywords
-		if cfg.Keywords["feat"] == nil {
-			cfg.Keywords["feat"] = make(map[string]int)
-		}
-		cfg.Keywords["feat"]["func"] = 3
-		cfg.Keywords["feat"]["type"] = 2
-		cfg.Keywords["feat"]["struct"] = 2
-		cfg.Keywords["feat"]["interface"] = 2
-
-		if cfg.Keywords["fix"] == nil {
-			cfg.Keywords["fix"] = make(map[string]int)
-		}
-		cfg.Keywords["fix"]["if err != nil"] = 3
-		cfg.Keywords["fix"]["error"] = 2
-		cfg.Keywords["fix"]["panic"] = 2
-
-	case "nodejs":
-		// Node.js-specific keywords
-		if cfg.Keywords["feat"] == nil {
-			cfg.Keywords["feat"] = make(map[string]int)
-		}
-		cfg.Keywords["feat"]["function"] = 3
-		cfg.Keywords["feat"]["class"] = 2
-		cfg.Keywords["feat"]["const"] = 1
-		cfg.Keywords["feat"]["export"] = 2
-
-		if cfg.Keywords["fix"] == nil {
-			cfg.Keywords["fix"] = make(map[string]int)
-		}
-		cfg.Keywords["fix"]["try"] = 2
-		cfg.Keywords["fix"]["catch"] = 2
-		cfg.Keywords["fix"]["throw"] = 2
-
-	case "python":
-		// Python-specific keywords
-		if cfg.Keywords["feat"] == nil {
-			cfg.Keywords["feat"] = make(map[string]int)
-		}
-		cfg.Keywords["feat"]["def"] = 3
-		cfg.Keywords["feat"]["class"] = 2
-		cfg.Keywords["feat"]["async def"] = 3
-
-		if cfg.Keywords["fix"] == nil {
-			cfg.Keywords["fix"] = make(map[string]int)
-		}
-		cfg.Keywords["fix"]["try"] = 2
-		cfg.Keywords["fix"]["except"] = 2
-		cfg.Keywords["fix"]["raise"] = 2
+// DefaultConfig returns the built-in default configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Engine: "heuristic",
+		Ollama: OllamaConfig{
+			Model:       "qwen2.5-coder:7b",
+			URL:         "http://localhost:11434",
+			Temperature: 0.2,
+		},
+		TopicMappings:     make(map[string]string),
+		KeywordMappings:   make(map[string]string),
+		Keywords:          make(map[string]map[string]int),
+		Templates:         make(map[string]map[string]TemplateList),
+		DiffStatThreshold: 0.5,
+		NormalizeScoring:  true,
+		SignalWeights: map[string]float64{
+			"branch":   0.35,
+			"diffStat": 0.25,
+			"keywords": 0.25,
+			"patterns": 0.15,
+		},
+		MaxSubjectLength: 50,
+		MaxBodyLength:    72,
+		SubjectOverflow:  "shorten",
+		LearnStyle:       true,
+		ImperativeMood:   true,
+		CommitTemplate:   true,
+		Codeowners: CodeownersConfig{
+			Enabled: true,
+		},
+		Rules: RulesConfig{
+			AutoFix: true,
+		},
+		Spellcheck: SpellcheckConfig{
+			Enabled: true,
+		},
+		Safety: SafetyConfig{
+			Enabled: true,
+		},
+		Tests: TestsConfig{
+			Nudge: true,
+		},
+		Risk: RiskConfig{
+			Enabled:       true,
+			CriticalPaths: append([]string(nil), defaultCriticalPaths...),
+			HighThreshold: 60,
+		},
+		Analyze: AnalyzeConfig{
+			IgnoreAuthors: append([]string(nil), defaultIgnoredAuthors...),
+		},
+		Tickets: TicketsConfig{
+			GitHubIssues: true,
+		},
+		Theme:    defaultTheme,
+		Timeouts: defaultTimeouts,
+		History:  defaultHistory,
+		Plugins: PluginsConfig{
+			Enabled: true,
+		},
+		Changelog: ChangelogConfig{
+			File: defaultChangelogFile,
+		},
+		Fallbacks: append([]FallbackRule(nil), DefaultFallbackRules...),
 	}
 }
 
@@ -202,16 +217,23 @@ func mergeConfigFromFile(cfg *Config, path string) error {
 		return fmt.Errorf("config file does not exist: %s", path)
 	}
 
-	data, err := os.ReadFile(path)
+	data, err := readConfigData(path)
 	if err != nil {
-		return fmt.Errorf("error reading config file %s: %w", path, err)
+		return err
 	}
 
-	var fileCfg Config
-	err = json.Unmarshal(data, &fileCfg)
-	if err != nil {
+	if err := mergeConfigData(cfg, data); err != nil {
 		return fmt.Errorf("error unmarshaling config file %s: %w", path, err)
 	}
+	return nil
+}
+
+// mergeConfigData merges JSON-encoded config data into the existing config
+func mergeConfigData(cfg *Config, data []byte) error {
+	var fileCfg Config
+	if err := json.Unmarshal(data, &fileCfg); err != nil {
+		return err
+	}
 
 	// Merge the loaded config into the existing config
 	// Engine
@@ -249,6 +271,14 @@ func mergeConfigFromFile(cfg *Config, path string) error {
 		cfg.ProjectType = fileCfg.ProjectType
 	}
 
+	// Language packs
+	if fileCfg.Languages != nil {
+		if cfg.Languages == nil {
+			cfg.Languages = make(map[string]LanguageConfig)
+		}
+		mergeLanguages(cfg.Languages, fileCfg.Languages)
+	}
+
 	// Keywords
 	if fileCfg.Keywords != nil {
 		for action, keywords := range fileCfg.Keywords {
@@ -263,9 +293,15 @@ func mergeConfigFromFile(cfg *Config, path string) error {
 
 	// Templates
 	if fileCfg.Templates != nil {
-		for k, v := range fileCfg.Templates {
-			cfg.Templates[k] = v
+		if cfg.Templates == nil {
+			cfg.Templates = make(map[string]map[string]TemplateList)
 		}
+		mergeTemplates(cfg.Templates, fileCfg.Templates)
+	}
+
+	// Seed of template choices
+	if fileCfg.Seed != 0 {
+		cfg.Seed = fileCfg.Seed
 	}
 
 	// Diff stat threshold
@@ -274,13 +310,109 @@ func mergeConfigFromFile(cfg *Config, path string) error {
 	}
 
 	// Normalize scoring
-	if data, err := os.ReadFile(path); err == nil {
-		var raw map[string]interface{}
-		if err := json.Unmarshal(data, &raw); err == nil {
-			if val, ok := raw["normalizeScoring"]; ok {
-				if b, ok := val.(bool); ok {
-					cfg.NormalizeScoring = b
-				}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err == nil {
+		if val, ok := raw["normalizeScoring"]; ok {
+			if b, ok := val.(bool); ok {
+				cfg.NormalizeScoring = b
+			}
+		}
+		if val, ok := raw["learnStyle"]; ok {
+			if b, ok := val.(bool); ok {
+				cfg.LearnStyle = b
+			}
+		}
+		if val, ok := raw["imperativeMood"]; ok {
+			if b, ok := val.(bool); ok {
+				cfg.ImperativeMood = b
+			}
+		}
+		if val, ok := raw["deterministic"]; ok {
+			if b, ok := val.(bool); ok {
+				cfg.Deterministic = b
+			}
+		}
+		if val, ok := raw["updateCheck"]; ok {
+			if b, ok := val.(bool); ok {
+				cfg.UpdateCheck = b
+			}
+		}
+		if val, ok := raw["commitTemplate"]; ok {
+			if b, ok := val.(bool); ok {
+				cfg.CommitTemplate = b
+			}
+		}
+		if val, ok := raw["strictScopes"]; ok {
+			if b, ok := val.(bool); ok {
+				cfg.StrictScopes = b
+			}
+		}
+		if codeowners, ok := raw["codeowners"].(map[string]interface{}); ok {
+			if b, ok := codeowners["enabled"].(bool); ok {
+				cfg.Codeowners.Enabled = b
+			}
+			if b, ok := codeowners["mentionOwners"].(bool); ok {
+				cfg.Codeowners.MentionOwners = b
+			}
+		}
+		if rules, ok := raw["rules"].(map[string]interface{}); ok {
+			mergeRules(&cfg.Rules, fileCfg.Rules, rules)
+		}
+		if spellcheck, ok := raw["spellcheck"].(map[string]interface{}); ok {
+			if b, ok := spellcheck["enabled"].(bool); ok {
+				cfg.Spellcheck.Enabled = b
+			}
+		}
+		if safety, ok := raw["safety"].(map[string]interface{}); ok {
+			if b, ok := safety["enabled"].(bool); ok {
+				cfg.Safety.Enabled = b
+			}
+		}
+		if tests, ok := raw["tests"].(map[string]interface{}); ok {
+			if b, ok := tests["nudge"].(bool); ok {
+				cfg.Tests.Nudge = b
+			}
+			if b, ok := tests["addNote"].(bool); ok {
+				cfg.Tests.AddNote = b
+			}
+		}
+		if tickets, ok := raw["tickets"].(map[string]interface{}); ok {
+			if b, ok := tickets["githubIssues"].(bool); ok {
+				cfg.Tickets.GitHubIssues = b
+			}
+			if b, ok := tickets["allowRepository"].(bool); ok {
+				cfg.Tickets.AllowRepository = b
+			}
+		}
+		if theme, ok := raw["theme"].(map[string]interface{}); ok {
+			if b, ok := theme["emoji"].(bool); ok {
+				cfg.Theme.Emoji = b
+			}
+			if b, ok := theme["ascii"].(bool); ok {
+				cfg.Theme.ASCII = b
+			}
+		}
+		if hooks, ok := raw["hooks"].(map[string]interface{}); ok {
+			if b, ok := hooks["allowRepository"].(bool); ok {
+				cfg.Hooks.AllowRepository = b
+			}
+		}
+		if changelog, ok := raw["changelog"].(map[string]interface{}); ok {
+			if b, ok := changelog["enabled"].(bool); ok {
+				cfg.Changelog.Enabled = b
+			}
+		}
+		if plugins, ok := raw["plugins"].(map[string]interface{}); ok {
+			if b, ok := plugins["enabled"].(bool); ok {
+				cfg.Plugins.Enabled = b
+			}
+		}
+		if risk, ok := raw["risk"].(map[string]interface{}); ok {
+			if b, ok := risk["enabled"].(bool); ok {
+				cfg.Risk.Enabled = b
+			}
+			if b, ok := risk["confirmAuto"].(bool); ok {
+				cfg.Risk.ConfirmAuto = b
 			}
 		}
 	}
@@ -302,6 +434,101 @@ func mergeConfigFromFile(cfg *Config, path string) error {
 	if fileCfg.MaxBodyLength > 0 {
 		cfg.MaxBodyLength = fileCfg.MaxBodyLength
 	}
+	if fileCfg.SubjectOverflow != "" {
+		cfg.SubjectOverflow = fileCfg.SubjectOverflow
+	}
+
+	// Scope aliases
+	if fileCfg.ScopeAliases != nil {
+		if cfg.ScopeAliases == nil {
+			cfg.ScopeAliases = make(map[string]string)
+		}
+		for k, v := range fileCfg.ScopeAliases {
+			cfg.ScopeAliases[k] = v
+		}
+	}
+
+	// Dictionary words extend the lower levels, so global jargon stays known in every repository
+	for _, word := range fileCfg.Spellcheck.Words {
+		if !containsString(cfg.Spellcheck.Words, word) {
+			cfg.Spellcheck.Words = append(cfg.Spellcheck.Words, word)
+		}
+	}
+
+	// Debug patterns and ignored files extend the lower levels like dictionary words
+	for _, pattern := range fileCfg.Safety.DebugPatterns {
+		if !containsString(cfg.Safety.DebugPatterns, pattern) {
+			cfg.Safety.DebugPatterns = append(cfg.Safety.DebugPatterns, pattern)
+		}
+	}
+	for _, pattern := range fileCfg.Safety.Ignore {
+		if !containsString(cfg.Safety.Ignore, pattern) {
+			cfg.Safety.Ignore = append(cfg.Safety.Ignore, pattern)
+		}
+	}
+
+	// Test mappings of the more specific level are tried first
+	if len(fileCfg.Tests.Mappings) > 0 {
+		cfg.Tests.Mappings = append(append([]TestMapping(nil), fileCfg.Tests.Mappings...), cfg.Tests.Mappings...)
+	}
+	for _, pattern := range fileCfg.Tests.Patterns {
+		if !containsString(cfg.Tests.Patterns, pattern) {
+			cfg.Tests.Patterns = append(cfg.Tests.Patterns, pattern)
+		}
+	}
+
+	// Ignored authors extend the defaults, so bots stay out of every repository's statistics
+	for _, author := range fileCfg.Analyze.IgnoreAuthors {
+		if !containsString(cfg.Analyze.IgnoreAuthors, author) {
+			cfg.Analyze.IgnoreAuthors = append(cfg.Analyze.IgnoreAuthors, author)
+		}
+	}
+
+	// Critical paths replace the defaults, so a project can drop paths it does not consider risky
+	if fileCfg.Risk.CriticalPaths != nil {
+		cfg.Risk.CriticalPaths = fileCfg.Risk.CriticalPaths
+	}
+	if fileCfg.Risk.HighThreshold > 0 {
+		cfg.Risk.HighThreshold = fileCfg.Risk.HighThreshold
+	}
+
+	// Scopes replace rather than extend, so a local list can narrow a global one
+	if fileCfg.Scopes != nil {
+		cfg.Scopes = fileCfg.Scopes
+	}
+
+	// Types narrow like scopes do
+	if fileCfg.Types != nil {
+		cfg.Types = fileCfg.Types
+	}
+
+	if fileCfg.TemplateFile != "" {
+		cfg.TemplateFile = fileCfg.TemplateFile
+	}
+
+	mergeTickets(&cfg.Tickets, fileCfg.Tickets)
+	mergeTheme(&cfg.Theme, fileCfg.Theme)
+	mergeTimeouts(&cfg.Timeouts, fileCfg.Timeouts)
+	mergeHistory(&cfg.History, fileCfg.History)
+	mergePlugins(&cfg.Plugins, fileCfg.Plugins)
+	mergeHooks(&cfg.Hooks, fileCfg.Hooks)
+	mergeChangelog(&cfg.Changelog, fileCfg.Changelog)
+
+	// Path overrides
+	if fileCfg.Paths != nil {
+		if cfg.Paths == nil {
+			cfg.Paths = make(map[string]PathConfig)
+		}
+		for k, v := range fileCfg.Paths {
+			cfg.Paths[k] = v
+		}
+	}
+
+	// Branch policies from later levels take precedence, as the first match wins
+	if fileCfg.BranchPolicies != nil {
+		cfg.BranchPolicies = append(fileCfg.BranchPolicies, cfg.BranchPolicies...)
+	}
+	mergeFallbacks(cfg, fileCfg.Fallbacks)
 
 	return nil
 }
@@ -0,0 +1,325 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// errNotEditable is the error of config files a key cannot be edited in place
+// in, such as TOML with the key in an inline table; they are rewritten instead
+var errNotEditable = errors.New("config file cannot be edited in place")
+
+// writeFileValue sets the key at a path of segments in the config file at path,
+// or removes it when value is nil. YAML and TOML files are edited in place, so
+// their comments and key order survive; JSON has no comments and is rewritten.
+func writeFileValue(path string, segments []string, value interface{}) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		if value == nil {
+			return nil
+		}
+		raw := map[string]interface{}{}
+		setRawValue(raw, segments, value)
+		return writeRawConfig(path, raw)
+	}
+	if err != nil {
+		return fmt.Errorf("error reading config file %s: %w", path, err)
+	}
+
+	var edited []byte
+	switch FormatFromPath(path) {
+	case FormatYAML:
+		edited, err = editYAML(data, segments, value)
+	case FormatTOML:
+		edited, err = editTOML(data, segments, value)
+	default:
+		err = errNotEditable
+	}
+	if err == nil {
+		if err := os.WriteFile(path, edited, 0644); err != nil {
+			return fmt.Errorf("error writing config file %s: %w", path, err)
+		}
+		return nil
+	}
+	if !errors.Is(err, errNotEditable) {
+		return fmt.Errorf("error editing config file %s: %w", path, err)
+	}
+
+	raw, err := readRawConfig(path)
+	if err != nil {
+		return err
+	}
+	setRawValue(raw, segments, value)
+	if FormatFromPath(path) != FormatJSON && bytes.Contains(data, []byte("#")) {
+		slog.Warn("config file rewritten; its comments are lost", "path", path, "key", strings.Join(segments, "."))
+	}
+	return writeRawConfig(path, raw)
+}
+
+// setRawValue sets the key at a path of segments in a generic config map, or
+// removes it and the maps it leaves empty when value is nil
+func setRawValue(raw map[string]interface{}, segments []string, value interface{}) {
+	last := segments[len(segments)-1]
+	if len(segments) == 1 {
+		if value == nil {
+			delete(raw, last)
+		} else {
+			raw[last] = value
+		}
+		return
+	}
+	next, ok := raw[segments[0]].(map[string]interface{})
+	if !ok {
+		if value == nil {
+			return
+		}
+		next = map[string]interface{}{}
+		raw[segments[0]] = next
+	}
+	setRawValue(next, segments[1:], value)
+	if len(next) == 0 {
+		delete(raw, segments[0])
+	}
+}
+
+// editYAML sets or removes a key in the node tree of a YAML config, keeping its
+// comments
+func editYAML(data []byte, segments []string, value interface{}) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind == 0 {
+		doc.Kind = yaml.DocumentNode
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("the document is not a mapping")
+	}
+	if err := editYAMLMapping(doc.Content[0], segments, value); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	err := enc.Close()
+	return buf.Bytes(), err
+}
+
+// editYAMLMapping sets or removes a key below a mapping node, removing mappings
+// the removal leaves empty
+func editYAMLMapping(mapping *yaml.Node, segments []string, value interface{}) error {
+	index := -1
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == segments[0] {
+			index = i
+		}
+	}
+	remove := func() {
+		mapping.Content = append(mapping.Content[:index], mapping.Content[index+2:]...)
+	}
+
+	if len(segments) == 1 {
+		if value == nil {
+			if index >= 0 {
+				remove()
+			}
+			return nil
+		}
+		var node yaml.Node
+		if err := node.Encode(value); err != nil {
+			return err
+		}
+		if index < 0 {
+			mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: segments[0]}, &node)
+			return nil
+		}
+		old := mapping.Content[index+1]
+		node.HeadComment, node.LineComment, node.FootComment = old.HeadComment, old.LineComment, old.FootComment
+		mapping.Content[index+1] = &node
+		return nil
+	}
+
+	if index < 0 {
+		if value == nil {
+			return nil
+		}
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: segments[0]}, &yaml.Node{Kind: yaml.MappingNode})
+		index = len(mapping.Content) - 2
+	}
+	child := mapping.Content[index+1]
+	if child.Kind != yaml.MappingNode {
+		if value == nil {
+			return nil
+		}
+		child = &yaml.Node{Kind: yaml.MappingNode, HeadComment: child.HeadComment, LineComment: child.LineComment}
+		mapping.Content[index+1] = child
+	}
+	if err := editYAMLMapping(child, segments[1:], value); err != nil {
+		return err
+	}
+	if value == nil && len(child.Content) == 0 {
+		remove()
+	}
+	return nil
+}
+
+// editTOML sets or removes a key in the lines of a TOML config, keeping its
+// comments. Keys of a table are looked up under its [table] header; files that
+// set them otherwise, in inline tables, dotted keys or values spanning lines,
+// return errNotEditable.
+func editTOML(data []byte, segments []string, value interface{}) ([]byte, error) {
+	if len(segments) > 2 {
+		return nil, errNotEditable
+	}
+	table, key := "", segments[0]
+	if len(segments) == 2 {
+		table, key = segments[0], segments[1]
+	}
+
+	line := ""
+	if value != nil {
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(map[string]interface{}{key: value}); err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(buf.String(), "\n")
+		if strings.Contains(line, "\n") {
+			return nil, errNotEditable
+		}
+	}
+
+	lines := strings.Split(string(data), "\n")
+	current := ""
+	headerAt, lastKeyAt, keyAt, keys := -1, -1, -1, 0
+	comment := ""
+	firstHeader := len(lines)
+	for i, l := range lines {
+		trimmed := strings.TrimSpace(l)
+		if strings.HasPrefix(trimmed, "[") {
+			header, _, _ := strings.Cut(trimmed, "#")
+			current = strings.TrimSpace(strings.Trim(strings.TrimSpace(header), "[]"))
+			if strings.HasPrefix(trimmed, "[[") {
+				current = "[[" + current
+			}
+			if firstHeader == len(lines) {
+				firstHeader = i
+			}
+			if current == table {
+				headerAt, lastKeyAt = i, i
+			}
+			continue
+		}
+		name, rest, ok := strings.Cut(trimmed, "=")
+		if !ok || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		name = strings.Trim(strings.TrimSpace(name), `"'`)
+		rest = strings.TrimSpace(rest)
+		if current == "" && table != "" && (name == table || strings.HasPrefix(name, table+".")) {
+			return nil, errNotEditable
+		}
+		if current != table {
+			continue
+		}
+		lastKeyAt = i
+		keys++
+		if name == key {
+			if strings.HasPrefix(rest, `"""`) || strings.HasPrefix(rest, "'''") || strings.Count(rest, "[") != strings.Count(rest, "]") {
+				return nil, errNotEditable
+			}
+			keyAt, comment = i, inlineComment(rest)
+		}
+	}
+
+	switch {
+	case keyAt >= 0 && value == nil:
+		lines = append(lines[:keyAt], lines[keyAt+1:]...)
+		// A table left empty goes too
+		if keys == 1 && headerAt >= 0 {
+			lines = append(lines[:headerAt], lines[headerAt+1:]...)
+		}
+	case keyAt >= 0:
+		lines[keyAt] = strings.TrimSpace(line + " " + comment)
+	case value == nil:
+		return data, nil
+	case table == "":
+		// Top-level keys go after the others, before the first table
+		at := lastKeyAt + 1
+		if lastKeyAt < 0 && firstHeader < len(lines) {
+			line += "\n"
+		} else if lastKeyAt < 0 {
+			at = len(lines)
+			if lines[at-1] == "" {
+				at--
+			}
+		}
+		lines = append(lines[:at], append([]string{line}, lines[at:]...)...)
+	case headerAt >= 0:
+		lines = append(lines[:lastKeyAt+1], append([]string{line}, lines[lastKeyAt+1:]...)...)
+	default:
+		content := strings.TrimRight(strings.Join(lines, "\n"), "\n")
+		if content != "" {
+			content += "\n\n"
+		}
+		lines = strings.Split(content+"["+table+"]\n"+line+"\n", "\n")
+	}
+
+	// The lines of values spanning lines or of odd layouts may be misread, so
+	// the edit only stands if it changed nothing but the key
+	edited := []byte(strings.Join(lines, "\n"))
+	var before, after, want map[string]interface{}
+	if err := toml.Unmarshal(data, &before); err != nil {
+		return nil, err
+	}
+	if before == nil {
+		before = map[string]interface{}{}
+	}
+	if err := toml.Unmarshal(edited, &after); err != nil {
+		return nil, errNotEditable
+	}
+	setRawValue(before, segments, value)
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(before); err != nil {
+		return nil, err
+	}
+	if err := toml.Unmarshal(buf.Bytes(), &want); err != nil || !reflect.DeepEqual(after, want) {
+		return nil, errNotEditable
+	}
+	return edited, nil
+}
+
+// inlineComment returns the comment after a TOML value, such as # in seconds
+func inlineComment(value string) string {
+	var quote rune
+	escaped := false
+	for i, r := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote == 0 && r == '#':
+			return value[i:]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// envPrefix is prepended to every environment variable that overrides a config key
+const envPrefix = "GITMIT_"
+
+// EnvVarName returns the environment variable that overrides a config key,
+// e.g. maxSubjectLength -> GITMIT_MAX_SUBJECT_LENGTH, ollama.model -> GITMIT_OLLAMA_MODEL.
+// Wildcard keys such as topicMappings.* map to a single variable holding a JSON object.
+func EnvVarName(key string) string {
+	key = strings.TrimSuffix(key, ".*")
+
+	var b strings.Builder
+	b.WriteString(envPrefix)
+	for i, r := range key {
+		switch {
+		case r == '.':
+			b.WriteRune('_')
+		case unicode.IsUpper(r) && i > 0 && key[i-1] != '.':
+			b.WriteRune('_')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return b.String()
+}
+
+// applyEnvOverrides overrides config values from GITMIT_* environment variables.
+// GITMIT_OFFLINE=true forces the heuristic engine regardless of other settings, and
+// GITMIT_TEMPLATES layers custom templates over those of the config files.
+func applyEnvOverrides(cfg *Config) error {
+	overrides := map[string]interface{}{}
+
+	for _, spec := range Keys {
+		name := EnvVarName(spec.Name)
+		value, ok := os.LookupEnv(name)
+		if !ok || value == "" {
+			continue
+		}
+
+		var parsed interface{}
+		if strings.HasSuffix(spec.Name, ".*") {
+			var entries map[string]interface{}
+			if err := json.Unmarshal([]byte(value), &entries); err != nil {
+				return fmt.Errorf("invalid %s: expected a JSON object: %w", name, err)
+			}
+			for _, v := range entries {
+				if _, err := ParseValue(spec, FormatValue(v)); err != nil {
+					return fmt.Errorf("invalid %s: %w", name, err)
+				}
+			}
+			parsed = entries
+		} else {
+			v, err := ParseValue(spec, value)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %w", name, err)
+			}
+			parsed = v
+		}
+
+		segments := strings.Split(strings.TrimSuffix(spec.Name, ".*"), ".")
+		current := overrides
+		for _, segment := range segments[:len(segments)-1] {
+			next, ok := current[segment].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				current[segment] = next
+			}
+			current = next
+		}
+		current[segments[len(segments)-1]] = parsed
+	}
+
+	// Custom templates are nested too deeply for a KeySpec, so they take one JSON object
+	if value, ok := os.LookupEnv(envPrefix + "TEMPLATES"); ok && value != "" {
+		var templates map[string]map[string]TemplateList
+		if err := json.Unmarshal([]byte(value), &templates); err != nil {
+			return fmt.Errorf("invalid %sTEMPLATES: expected a JSON object of action -> topic -> templates: %w", envPrefix, err)
+		}
+		overrides["templates"] = templates
+	}
+
+	if len(overrides) > 0 {
+		data, err := json.Marshal(overrides)
+		if err != nil {
+			return fmt.Errorf("error encoding environment overrides: %w", err)
+		}
+		if err := mergeConfigData(cfg, data); err != nil {
+			return fmt.Errorf("error applying environment overrides: %w", err)
+		}
+	}
+
+	if value, ok := os.LookupEnv(envPrefix + "OFFLINE"); ok && value != "" {
+		offline, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid %sOFFLINE: %q is not a boolean (true/false)", envPrefix, value)
+		}
+		if offline {
+			cfg.Engine = "heuristic"
+		}
+	}
+
+	return nil
+}
+
+// EnvOverrides returns the GITMIT_* environment variables that are set and
+// override the config, sorted
+func EnvOverrides() []string {
+	var names []string
+	for _, spec := range Keys {
+		if value, ok := os.LookupEnv(EnvVarName(spec.Name)); ok && value != "" {
+			names = append(names, EnvVarName(spec.Name))
+		}
+	}
+	for _, name := range []string{envPrefix + "OFFLINE", envPrefix + "TEMPLATES"} {
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
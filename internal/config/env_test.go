@@ -0,0 +1,74 @@
+package config
+
+import "testing"
+
+func TestEnvVarName(t *testing.T) {
+	tests := map[string]string{
+		"engine":           "GITMIT_ENGINE",
+		"maxSubjectLength": "GITMIT_MAX_SUBJECT_LENGTH",
+		"ollama.model":     "GITMIT_OLLAMA_MODEL",
+		"topicMappings.*":  "GITMIT_TOPIC_MAPPINGS",
+	}
+	for key, want := range tests {
+		if got := EnvVarName(key); got != want {
+			t.Errorf("EnvVarName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestApplyEnvOverrides(t *testing.T) {
+	t.Setenv("GITMIT_MAX_SUBJECT_LENGTH", "60")
+	t.Setenv("GITMIT_NORMALIZE_SCORING", "false")
+	t.Setenv("GITMIT_OLLAMA_MODEL", "llama3")
+	t.Setenv("GITMIT_TOPIC_MAPPINGS", `{"cmd": "cli"}`)
+	t.Setenv("GITMIT_ENGINE", "ollama")
+	t.Setenv("GITMIT_OFFLINE", "1")
+
+	cfg := DefaultConfig()
+	if err := applyEnvOverrides(cfg); err != nil {
+		t.Fatalf("applyEnvOverrides() failed: %v", err)
+	}
+	if cfg.MaxSubjectLength != 60 {
+		t.Errorf("MaxSubjectLength = %d, want 60", cfg.MaxSubjectLength)
+	}
+	if cfg.NormalizeScoring {
+		t.Errorf("NormalizeScoring = true, want false")
+	}
+	if cfg.Ollama.Model != "llama3" {
+		t.Errorf("Ollama.Model = %q, want llama3", cfg.Ollama.Model)
+	}
+	if cfg.TopicMappings["cmd"] != "cli" {
+		t.Errorf("TopicMappings[cmd] = %q, want cli", cfg.TopicMappings["cmd"])
+	}
+	if cfg.Engine != "heuristic" {
+		t.Errorf("Engine = %q, want heuristic when GITMIT_OFFLINE is set", cfg.Engine)
+	}
+
+	t.Setenv("GITMIT_MAX_BODY_LENGTH", "wide")
+	if err := applyEnvOverrides(DefaultConfig()); err == nil {
+		t.Errorf("expected error for invalid GITMIT_MAX_BODY_LENGTH")
+	}
+}
+
+func TestApplyEnvOverridesSeed(t *testing.T) {
+	t.Setenv("GITMIT_SEED", "42")
+	t.Setenv("GITMIT_DETERMINISTIC", "true")
+
+	cfg := DefaultConfig()
+	if err := applyEnvOverrides(cfg); err != nil {
+		t.Fatalf("applyEnvOverrides() failed: %v", err)
+	}
+	if cfg.Seed != 42 || !cfg.Deterministic {
+		t.Errorf("Seed = %d, Deterministic = %t, want 42 and true", cfg.Seed, cfg.Deterministic)
+	}
+}
+
+func TestEnvOverrides(t *testing.T) {
+	t.Setenv("GITMIT_OLLAMA_MODEL", "llama3")
+	t.Setenv("GITMIT_OFFLINE", "1")
+	t.Setenv("GITMIT_ENGINE", "")
+	got := EnvOverrides()
+	if len(got) != 2 || got[0] != "GITMIT_OFFLINE" || got[1] != "GITMIT_OLLAMA_MODEL" {
+		t.Errorf("EnvOverrides() = %v, want the two set variables sorted", got)
+	}
+}
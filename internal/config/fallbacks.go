@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FallbackRule decides the type, topic and purpose of changes before scoring
+// when all of its conditions hold. Topic and Item may be {topic} and {item},
+// the topic and item of the heaviest changed file.
+type FallbackRule struct {
+	Name         string   `json:"name" yaml:"name" toml:"name"`                                                       // Name later config levels replace or disable the rule by
+	Disabled     bool     `json:"disabled,omitempty" yaml:"disabled,omitempty" toml:"disabled,omitempty"`             // Skip the rule
+	Files        []string `json:"files,omitempty" yaml:"files,omitempty" toml:"files,omitempty"`                      // Changed files the rule is about, gitignore-style
+	Tests        bool     `json:"tests,omitempty" yaml:"tests,omitempty" toml:"tests,omitempty"`                      // Test files count as matching files
+	Match        string   `json:"match,omitempty" yaml:"match,omitempty" toml:"match,omitempty"`                      // "any" changed file must match (default) or "all"
+	Status       string   `json:"status,omitempty" yaml:"status,omitempty" toml:"status,omitempty"`                   // Git status every change must have, such as A or D
+	MinFiles     int      `json:"minFiles,omitempty" yaml:"minFiles,omitempty" toml:"minFiles,omitempty"`             // Fewest changed files
+	MaxFiles     int      `json:"maxFiles,omitempty" yaml:"maxFiles,omitempty" toml:"maxFiles,omitempty"`             // Most changed files
+	MinAdded     int      `json:"minAdded,omitempty" yaml:"minAdded,omitempty" toml:"minAdded,omitempty"`             // Fewest lines added in total
+	MinRemoved   int      `json:"minRemoved,omitempty" yaml:"minRemoved,omitempty" toml:"minRemoved,omitempty"`       // Fewest lines removed in total
+	LinesPerFile float64  `json:"linesPerFile,omitempty" yaml:"linesPerFile,omitempty" toml:"linesPerFile,omitempty"` // Changed lines per file on average must exceed it
+	Action       string   `json:"action" yaml:"action" toml:"action"`                                                 // Commit type
+	Topic        string   `json:"topic,omitempty" yaml:"topic,omitempty" toml:"topic,omitempty"`                      // Topic
+	Item         string   `json:"item,omitempty" yaml:"item,omitempty" toml:"item,omitempty"`                         // Item
+	Purpose      string   `json:"purpose,omitempty" yaml:"purpose,omitempty" toml:"purpose,omitempty"`                // Purpose
+}
+
+// DefaultFallbackRules are the built-in fallback rules, tried in order
+var DefaultFallbackRules = []FallbackRule{
+	{Name: "test-file", Tests: true, Match: "all", MaxFiles: 1, Action: "test", Topic: "{topic}", Item: "{item}", Purpose: "update tests"},
+	{Name: "new-file", Status: "A", MaxFiles: 1, Action: "feat", Topic: "{topic}", Item: "{item}", Purpose: "initial implementation"},
+	{Name: "deleted-file", Status: "D", MaxFiles: 1, Action: "chore", Topic: "{topic}", Item: "{item}", Purpose: "remove unused file"},
+	{Name: "restructure", MinFiles: 6, MinAdded: 1, MinRemoved: 1, LinesPerFile: 10, Action: "refactor", Topic: "core", Purpose: "restructure project"},
+	{Name: "build-config", Files: []string{"*.env", "*.yml", "*.yaml", "Dockerfile", "Dockerfile.*", "*.Dockerfile", "*.dockerfile", "Containerfile", ".dockerignore"}, Action: "ci", Topic: "config", Purpose: "update build configuration"},
+	{Name: "docs", Files: []string{"/docs/", "/wiki/", "*.md", "*.txt"}, Match: "all", Action: "docs", Purpose: "update documentation"},
+	{Name: "go-deps", Files: []string{"/go.mod"}, Action: "chore", Topic: "deps", Purpose: "update dependencies"},
+}
+
+// mergeFallbacks applies the fallback rules of a higher config level: a rule
+// named like an earlier one replaces it in place, and new rules are tried
+// before the earlier ones
+func mergeFallbacks(cfg *Config, rules []FallbackRule) {
+	var added []FallbackRule
+	for _, rule := range rules {
+		replaced := false
+		for i := range cfg.Fallbacks {
+			if cfg.Fallbacks[i].Name == rule.Name {
+				cfg.Fallbacks[i] = rule
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			added = append(added, rule)
+		}
+	}
+	cfg.Fallbacks = append(added, cfg.Fallbacks...)
+}
+
+// validateFallbacks checks fallback rules for values that can never apply
+func validateFallbacks(rules []FallbackRule, add func(key, format string, args ...interface{})) {
+	names := make(map[string]bool)
+	for i, rule := range rules {
+		key := fmt.Sprintf("fallbacks[%d]", i)
+		switch {
+		case rule.Name == "":
+			add(key+".name", "name is required")
+		case names[rule.Name]:
+			add(key+".name", "duplicate rule %q", rule.Name)
+		}
+		names[rule.Name] = true
+		if rule.Disabled {
+			continue
+		}
+
+		if !containsString(CommitTypes, rule.Action) {
+			add(key+".action", "unknown commit type %q (expected one of %s)", rule.Action, strings.Join(CommitTypes, ", "))
+		}
+		if rule.Match != "" && rule.Match != "any" && rule.Match != "all" {
+			add(key+".match", "unknown match %q (expected any or all)", rule.Match)
+		}
+		if rule.Status != "" && !strings.Contains("AMDRC", rule.Status) || len(rule.Status) > 1 {
+			add(key+".status", "unknown git status %q (expected one of A, M, D, R, C)", rule.Status)
+		}
+		if rule.MaxFiles > 0 && rule.MinFiles > rule.MaxFiles {
+			add(key+".minFiles", "minFiles %d exceeds maxFiles %d", rule.MinFiles, rule.MaxFiles)
+		}
+		for _, pattern := range rule.Files {
+			if strings.TrimSpace(pattern) == "" {
+				add(key+".files", "empty pattern never matches a file")
+			}
+		}
+		if len(rule.Files) == 0 && !rule.Tests && rule.Status == "" && rule.MinFiles == 0 && rule.MaxFiles == 0 &&
+			rule.MinAdded == 0 && rule.MinRemoved == 0 && rule.LinesPerFile == 0 {
+			add(key, "rule has no condition and decides every commit")
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeFallbacks(t *testing.T) {
+	cfg := DefaultConfig()
+	data := `{"fallbacks": [
+		{"name": "restructure", "disabled": true},
+		{"name": "migrations", "files": ["migrations/"], "match": "all", "action": "chore", "topic": "db", "purpose": "add migration"}
+	]}`
+	if err := mergeConfigData(cfg, []byte(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, rule := range cfg.Fallbacks {
+		names = append(names, rule.Name)
+		if rule.Name == "restructure" && !rule.Disabled {
+			t.Error("restructure rule is not disabled")
+		}
+	}
+	want := "migrations,test-file,new-file,deleted-file,restructure,build-config,docs,go-deps"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("rules = %s, want %s", got, want)
+	}
+	if len(DefaultFallbackRules) != 7 || DefaultFallbackRules[3].Disabled {
+		t.Error("merging changed the built-in rules")
+	}
+}
+
+func TestValidateFallbacks(t *testing.T) {
+	rules := []FallbackRule{
+		{Name: "ok", Files: []string{"*.proto"}, Action: "feat"},
+		{Name: "ok", Files: []string{"*.sql"}, Action: "chore"},
+		{Name: "bad", Files: []string{" "}, Match: "most", Status: "X", MinFiles: 3, MaxFiles: 2, Action: "feature"},
+		{Name: "everything", Action: "chore"},
+		{Name: "off", Disabled: true},
+	}
+	var keys []string
+	validateFallbacks(rules, func(key, format string, args ...interface{}) { keys = append(keys, key) })
+
+	want := "fallbacks[1].name,fallbacks[2].action,fallbacks[2].match,fallbacks[2].status,fallbacks[2].minFiles,fallbacks[2].files,fallbacks[3]"
+	if got := strings.Join(keys, ","); got != want {
+		t.Errorf("issues at %s, want %s", got, want)
+	}
+}
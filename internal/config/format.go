@@ -0,0 +1,304 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
+	"github.com/andev0x/gitmit/internal/xdg"
+)
+
+// Supported config file formats
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// configFileNames lists the recognized config file names in lookup order
+var configFileNames = []string{".gitmit.json", ".gitmit.yaml", ".gitmit.yml", ".gitmit.toml"}
+
+// globalConfigFileNames lists the recognized file names inside the XDG config directory
+var globalConfigFileNames = []string{"config.json", "config.yaml", "config.yml", "config.toml"}
+
+// fieldComments documents each top-level key when writing commented formats (YAML/TOML)
+var fieldComments = map[string]string{
+	"engine":            "Suggestion engine: \"heuristic\" (offline) or \"ollama\" (local AI)",
+	"ollama":            "Ollama settings, used when engine is \"ollama\"",
+	"topicMappings":     "Maps path fragments to topics/scopes, e.g. internal/api -> api",
+	"keywordMappings":   "Maps diff keywords to purposes used in {purpose} placeholders",
+	"projectType":       "Language pack (go, rust, nodejs, python, kotlin, java, csharp, ruby, php, swift, terraform, generic or one in languages); auto-detected when empty",
+	"languages":         "Language packs added or extended: markers identifying the project, keywords and topicMappings",
+	"keywords":          "Keyword scoring per commit type: type -> keyword -> weight",
+	"templates":         "Custom templates: action -> topic -> templates (\"...\" keeps the built-in ones)",
+	"diffStatThreshold": "Threshold for the added/deleted line ratio analysis",
+	"normalizeScoring":  "Use normalized confidence weights instead of additive scores",
+	"signalWeights":     "Weights for each signal source when normalizeScoring is enabled",
+	"maxSubjectLength":  "Maximum length of the subject line",
+	"maxBodyLength":     "Maximum length of each body line",
+	"subjectOverflow":   "Long subjects: \"shorten\" (drop purpose phrase, then scope, then truncate with …) or \"wrap\" into the body",
+	"learnStyle":        "Match capitalization, tense, scopes and emoji of the repository's commit log",
+	"imperativeMood":    "Normalize the leading verb of suggestions to imperative mood before the learned style is applied",
+	"scopeAliases":      "Renames raw scopes to the team's vocabulary, e.g. internal/analyzer -> analyzer, db -> database",
+	"scopes":            "Known commit scopes offered in interactive mode; learned from the commit log when empty",
+	"strictScopes":      "Only allow known scopes; other scopes are replaced or dropped",
+	"types":             "Commit types suggestions may use; other types are replaced by the nearest allowed one",
+	"codeowners":        "CODEOWNERS integration: use the owning team as the scope and optionally mention owners",
+	"templateFile":      "Template pack file used instead of templates.json",
+	"paths":             "Overrides per monorepo directory: scope, projectType, templateFile, mappings, keywords, maxSubjectLength",
+	"rules":             "Rules commit messages must follow: imperative, lowercase, noTrailingPeriod, blockedWords, ticketPattern, requireTicket, autoFix",
+	"spellcheck":        "Typo check of suggested subjects: enabled, and words of project jargon to accept",
+	"safety":            "Check of staged changes for debug statements and conflict markers: enabled, extra debugPatterns and files to ignore",
+	"risk":              "Risk score of staged changes: enabled, criticalPaths, highThreshold, and confirmAuto to confirm high-risk --auto commits",
+	"analyze":           "Commit statistics of gitmit analyze: ignoreAuthors, bots and automated authors left out",
+	"tests":             "Nudge when source files change without their tests: nudge, addNote, mappings from source to test paths, and patterns of test files",
+	"tickets":           "Jira or Linear ticket of the branch: provider, pattern, url, user, token, footer and transitionTo; githubIssues looks up referenced GitHub issues with gh",
+	"theme":             "Colors of terminal output (accent, success, warning, error, heading, muted: e.g. \"bold blue\" or none), emoji to decorate it, and ascii for plain ASCII output",
+	"history":           "Suggestion history kept per repository: the newest maxEntries entries, dropping those older than maxAge (e.g. \"90d\")",
+	"plugins":           "Executables in $XDG_CONFIG_HOME/gitmit/plugins adding diff hints, scopes and message rules: enabled, and disabled plugin names",
+	"hooks":             "Shell commands run around suggestions: preAnalyze, postSuggest, preCommit and postCommit read JSON on stdin, may print it back changed and veto with a non-zero exit",
+	"changelog":         "Entries added to the Unreleased section of a Keep a Changelog file when committing feat, fix and breaking changes: enabled, and the file (default CHANGELOG.md)",
+	"commitTemplate":    "Add the section headings of git's commit.template, such as \"Why:\", to messages lacking them; comment lines and trailers are left out",
+	"updateCheck":       "Check GitHub for a new gitmit release once a day in the background and print a one-line notice; off by default",
+	"seed":              "Seed of the variety in template choices, so the same changes get the same suggestions; random when unset",
+	"deterministic":     "Leave the variety out of template choices, breaking ties lexically, for snapshot tests and scripts",
+	"timeouts":          "How long each git command (git), AI request (ai), plugin run (plugin) and hook run (hook) may take, e.g. \"30s\" or \"2m\"; \"0\" for no limit",
+	"branchPolicies":    "Rules per branch pattern: force a commit type, require a ticket prefix or use another template pack",
+	"fallbacks":         "Rules deciding clear-cut changes before scoring, tried in order: conditions (files, tests, match, status, minFiles, maxFiles, minAdded, minRemoved, linesPerFile) and the action, topic, item and purpose they decide; name a built-in rule to replace it or set disabled",
+}
+
+// FindConfigFile returns the first config file found in dir, or an empty string if none exists
+func FindConfigFile(dir string) string {
+	return findFile(dir, configFileNames)
+}
+
+// LocalDir returns the directory holding the repository's config files: the
+// top of the working tree, which linked worktrees and GIT_WORK_TREE keep apart
+// from the git directory, or the working directory outside a working tree
+func LocalDir(ctx context.Context) string {
+	return localDir(ctx, "")
+}
+
+// localDir returns the top of the working tree containing dir, or dir itself
+// ("." for the working directory) outside a working tree
+func localDir(ctx context.Context, dir string) string {
+	if dir == "" {
+		dir = gitcmd.Dir(ctx)
+	}
+	if out, err := gitcmd.Output(ctx, dir, "rev-parse", "--show-toplevel"); err == nil {
+		if root := strings.TrimSpace(out); root != "" {
+			return filepath.Clean(root)
+		}
+	}
+	if dir == "" {
+		return "."
+	}
+	return dir
+}
+
+// LocalConfigFile returns the repository's config file, or where a new one
+// should be written when it has none
+func LocalConfigFile(ctx context.Context) string {
+	dir := LocalDir(ctx)
+	if path := FindConfigFile(dir); path != "" {
+		return path
+	}
+	return filepath.Join(dir, ".gitmit.json")
+}
+
+// GlobalConfigFile returns the existing global config file, preferring
+// $XDG_CONFIG_HOME/gitmit/config.* over the legacy ~/.gitmit.* location
+func GlobalConfigFile() string {
+	return xdg.ConfigFile(globalConfigFileNames, configFileNames)
+}
+
+// GlobalConfigPath returns where a new global config file of the given format should be written
+func GlobalConfigPath(format string) (string, error) {
+	if _, err := FileNameForFormat(format); err != nil {
+		return "", err
+	}
+	dir, err := xdg.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config."+format), nil
+}
+
+// findFile returns the first of names that exists in dir
+func findFile(dir string, names []string) string {
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// FormatFromPath detects the config format from a file extension, defaulting to JSON
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	default:
+		return FormatJSON
+	}
+}
+
+// FileNameForFormat returns the config file name used for the given format
+func FileNameForFormat(format string) (string, error) {
+	switch format {
+	case FormatJSON, FormatYAML, FormatTOML:
+		return ".gitmit." + format, nil
+	default:
+		return "", fmt.Errorf("unsupported config format: %s (expected json, yaml or toml)", format)
+	}
+}
+
+// readConfigData reads a config file and returns its content as JSON,
+// converting from YAML or TOML when needed so all formats share one merge path
+func readConfigData(path string) ([]byte, error) {
+	if FormatFromPath(path) == FormatJSON {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading config file %s: %w", path, err)
+		}
+		return data, nil
+	}
+
+	raw, err := readRawConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error converting config file %s: %w", path, err)
+	}
+	return converted, nil
+}
+
+// readRawConfig decodes a config file of any supported format into a generic map
+func readRawConfig(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %w", path, err)
+	}
+
+	var raw map[string]interface{}
+	switch FormatFromPath(path) {
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("error parsing YAML config file %s: %w", path, err)
+		}
+	case FormatTOML:
+		if err := toml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("error parsing TOML config file %s: %w", path, err)
+		}
+	default:
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("error unmarshaling config file %s: %w", path, err)
+		}
+	}
+
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+	return raw, nil
+}
+
+// Marshal encodes the config in the given format. YAML and TOML output
+// includes a comment above each key explaining what it does.
+func Marshal(cfg *Config, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(cfg, "", "  ")
+	case FormatYAML:
+		return marshalCommented(cfg, func(key string, value interface{}) ([]byte, error) {
+			var buf bytes.Buffer
+			enc := yaml.NewEncoder(&buf)
+			enc.SetIndent(2)
+			if err := enc.Encode(map[string]interface{}{key: value}); err != nil {
+				return nil, err
+			}
+			err := enc.Close()
+			return buf.Bytes(), err
+		}, false)
+	case FormatTOML:
+		// TOML requires plain keys to come before any table
+		return marshalCommented(cfg, func(key string, value interface{}) ([]byte, error) {
+			var buf bytes.Buffer
+			err := toml.NewEncoder(&buf).Encode(map[string]interface{}{key: value})
+			return buf.Bytes(), err
+		}, true)
+	default:
+		return nil, fmt.Errorf("unsupported config format: %s (expected json, yaml or toml)", format)
+	}
+}
+
+// marshalCommented encodes each top-level field separately so a comment can precede it
+func marshalCommented(cfg *Config, encode func(key string, value interface{}) ([]byte, error), scalarsFirst bool) ([]byte, error) {
+	type field struct {
+		key   string
+		value interface{}
+		table bool
+	}
+
+	var fields []field
+	v := reflect.ValueOf(*cfg)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		kind := t.Field(i).Type.Kind()
+		fields = append(fields, field{
+			key:   key,
+			value: v.Field(i).Interface(),
+			table: kind == reflect.Map || kind == reflect.Struct || (kind == reflect.Slice && t.Field(i).Type.Elem().Kind() == reflect.Struct),
+		})
+	}
+
+	if scalarsFirst {
+		var ordered []field
+		for _, f := range fields {
+			if !f.table {
+				ordered = append(ordered, f)
+			}
+		}
+		for _, f := range fields {
+			if f.table {
+				ordered = append(ordered, f)
+			}
+		}
+		fields = ordered
+	}
+
+	var buf bytes.Buffer
+	for _, f := range fields {
+		out, err := encode(f.key, f.value)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding config key %s: %w", f.key, err)
+		}
+		// Unset optional lists encode to nothing in TOML; skip their comment too
+		if len(bytes.TrimSpace(out)) == 0 {
+			continue
+		}
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		if comment := fieldComments[f.key]; comment != "" {
+			buf.WriteString("# " + comment + "\n")
+		}
+		buf.Write(out)
+	}
+	return buf.Bytes(), nil
+}
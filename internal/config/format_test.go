@@ -0,0 +1,119 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestMarshalRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatYAML, FormatTOML} {
+		t.Run(format, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.MaxSubjectLength = 60
+			cfg.NormalizeScoring = false
+			cfg.TopicMappings["internal/api"] = "api"
+			cfg.Keywords["fix"] = map[string]int{"if err != nil": 3}
+			cfg.Scopes = []string{"api", "cli"}
+			cfg.BranchPolicies = []BranchPolicy{{Pattern: "hotfix/*", Type: "fix", RequireTicket: true}}
+
+			data, err := Marshal(cfg, format)
+			if err != nil {
+				t.Fatalf("Marshal(%s) failed: %v", format, err)
+			}
+
+			name, _ := FileNameForFormat(format)
+			path := filepath.Join(t.TempDir(), name)
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			loaded := DefaultConfig()
+			if err := mergeConfigFromFile(loaded, path); err != nil {
+				t.Fatalf("mergeConfigFromFile(%s) failed: %v", path, err)
+			}
+			if loaded.MaxSubjectLength != 60 {
+				t.Errorf("MaxSubjectLength = %d, want 60", loaded.MaxSubjectLength)
+			}
+			if loaded.NormalizeScoring {
+				t.Errorf("NormalizeScoring = true, want false")
+			}
+			if loaded.TopicMappings["internal/api"] != "api" {
+				t.Errorf("TopicMappings[internal/api] = %q, want api", loaded.TopicMappings["internal/api"])
+			}
+			if loaded.Keywords["fix"]["if err != nil"] != 3 {
+				t.Errorf("Keywords[fix][if err != nil] = %d, want 3", loaded.Keywords["fix"]["if err != nil"])
+			}
+			if len(loaded.Scopes) != 2 || loaded.Scopes[1] != "cli" {
+				t.Errorf("Scopes = %v, want [api cli]", loaded.Scopes)
+			}
+			if len(loaded.BranchPolicies) != 1 || loaded.BranchPolicies[0].Type != "fix" || !loaded.BranchPolicies[0].RequireTicket {
+				t.Errorf("BranchPolicies = %+v, want one hotfix/* policy", loaded.BranchPolicies)
+			}
+		})
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := map[string]string{
+		".gitmit.json": FormatJSON,
+		".gitmit.yaml": FormatYAML,
+		".gitmit.yml":  FormatYAML,
+		".gitmit.toml": FormatTOML,
+		"config":       FormatJSON,
+	}
+	for path, want := range tests {
+		if got := FormatFromPath(path); got != want {
+			t.Errorf("FormatFromPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestLocalDir(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	tmp, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL="+os.DevNull, "GIT_CONFIG_NOSYSTEM=1",
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	main := filepath.Join(tmp, "main")
+	git(tmp, "init", "--quiet", "main")
+	git(main, "commit", "--quiet", "--allow-empty", "--message", "init")
+	linked := filepath.Join(tmp, "linked")
+	git(main, "worktree", "add", "--quiet", linked)
+	if err := os.MkdirAll(filepath.Join(linked, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(tmp, "outside")
+	if err := os.Mkdir(outside, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		main:                         main,
+		linked:                       linked,
+		filepath.Join(linked, "sub"): linked,
+		filepath.Join(main, ".git"):  filepath.Join(main, ".git"),
+		outside:                      outside,
+	}
+	for dir, want := range tests {
+		if got := localDir(context.Background(), dir); filepath.Clean(got) != want {
+			t.Errorf("localDir(%s) = %q, want %q", dir, got, want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HistoryConfig sets how much of the suggestion history is kept per repository
+type HistoryConfig struct {
+	MaxEntries int    `json:"maxEntries,omitempty" yaml:"maxEntries,omitempty" toml:"maxEntries,omitempty"` // Entries kept, newest first
+	MaxAge     string `json:"maxAge,omitempty" yaml:"maxAge,omitempty" toml:"maxAge,omitempty"`             // Age after which entries are dropped, e.g. 90d; none when empty
+}
+
+// defaultHistory keeps the last few suggestions, however old
+var defaultHistory = HistoryConfig{
+	MaxEntries: 10,
+}
+
+// MaxAgeDuration returns how long history entries are kept, or 0 to keep them
+// however old
+func (h HistoryConfig) MaxAgeDuration() time.Duration {
+	d, err := ParseAge(h.MaxAge)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// ParseAge parses an age such as "90d", "2w" or "36h"; days and weeks are
+// accepted besides Go durations. An empty age is 0.
+func ParseAge(age string) (time.Duration, error) {
+	age = strings.TrimSpace(age)
+	if age == "" {
+		return 0, nil
+	}
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, err := strconv.Atoi(strings.TrimSuffix(age, suffix)); err == nil && strings.HasSuffix(age, suffix) {
+			if n < 0 {
+				return 0, fmt.Errorf("age %q is negative", age)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(age)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not an age such as 90d, 2w or 36h", age)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("age %q is negative", age)
+	}
+	return d, nil
+}
+
+func mergeHistory(cfg *HistoryConfig, fileHistory HistoryConfig) {
+	if fileHistory.MaxEntries > 0 {
+		cfg.MaxEntries = fileHistory.MaxEntries
+	}
+	if fileHistory.MaxAge != "" {
+		cfg.MaxAge = fileHistory.MaxAge
+	}
+}
+
+// validateHistory checks that the retention is a positive count and a valid age
+func validateHistory(history HistoryConfig, add func(key, format string, args ...interface{})) {
+	if history.MaxEntries <= 0 {
+		add("history.maxEntries", "must keep at least one entry, got %d", history.MaxEntries)
+	}
+	if _, err := ParseAge(history.MaxAge); err != nil {
+		add("history.maxAge", "%s", err)
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		age     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"90d", 90 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"36h", 36 * time.Hour, false},
+		{"-1d", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAge(tt.age)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseAge(%q) = %s, %v; want %s, error %t", tt.age, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestMergeHistory(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := mergeConfigData(cfg, []byte(`{"history": {"maxAge": "30d"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.History.MaxEntries != 10 || cfg.History.MaxAgeDuration() != 30*24*time.Hour {
+		t.Errorf("history = %+v, want the default count and a 30 day age", cfg.History)
+	}
+
+	cfg.History.MaxEntries = 0
+	cfg.History.MaxAge = "a while"
+	issues := Validate(cfg)
+	if len(issues) != 2 || issues[0].Key != "history.maxAge" || issues[1].Key != "history.maxEntries" {
+		t.Errorf("Validate() = %v, want an invalid history.maxAge and history.maxEntries", issues)
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+// HooksConfig sets the shell commands run around a suggestion and its commit.
+// Each reads the analysis or message as JSON on stdin, may print it back
+// changed, and vetoes by exiting with a non-zero status.
+type HooksConfig struct {
+	PreAnalyze      string `json:"preAnalyze,omitempty" yaml:"preAnalyze,omitempty" toml:"preAnalyze,omitempty"`    // Before the staged files are analyzed; may narrow them
+	PostSuggest     string `json:"postSuggest,omitempty" yaml:"postSuggest,omitempty" toml:"postSuggest,omitempty"` // After each suggestion; may change it
+	PreCommit       string `json:"preCommit,omitempty" yaml:"preCommit,omitempty" toml:"preCommit,omitempty"`       // Before committing; may change the message
+	PostCommit      string `json:"postCommit,omitempty" yaml:"postCommit,omitempty" toml:"postCommit,omitempty"`    // After committing; cannot veto
+	AllowRepository bool   `json:"allowRepository" yaml:"allowRepository" toml:"allowRepository"`                   // Run hooks set by repository configs; only read from the global config
+}
+
+func mergeHooks(cfg *HooksConfig, fileHooks HooksConfig) {
+	for _, field := range []struct {
+		target *string
+		value  string
+	}{
+		{&cfg.PreAnalyze, fileHooks.PreAnalyze},
+		{&cfg.PostSuggest, fileHooks.PostSuggest},
+		{&cfg.PreCommit, fileHooks.PreCommit},
+		{&cfg.PostCommit, fileHooks.PostCommit},
+	} {
+		if field.value != "" {
+			*field.target = field.value
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigIgnoresRepositoryHooks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	global := filepath.Join(home, ".config", "gitmit")
+	if err := os.MkdirAll(global, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	writeConfig := func(path, data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig(filepath.Join(global, "config.json"), `{"hooks": {"postCommit": "notify"}}`)
+	writeConfig(filepath.Join(repo, ".gitmit.json"), `{"hooks": {"preCommit": "curl evil", "allowRepository": true}}`)
+
+	cfg, err := LoadConfig(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (HooksConfig{PostCommit: "notify"}); cfg.Hooks != want {
+		t.Errorf("Hooks = %+v, want only the global hooks", cfg.Hooks)
+	}
+
+	writeConfig(filepath.Join(global, "config.json"), `{"hooks": {"postCommit": "notify", "allowRepository": true}}`)
+	if cfg, err = LoadConfig(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Hooks.PreCommit != "curl evil" || cfg.Hooks.PostCommit != "notify" {
+		t.Errorf("Hooks = %+v, want the repository hooks once the global config allows them", cfg.Hooks)
+	}
+}
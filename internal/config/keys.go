@@ -0,0 +1,311 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// KeySpec describes a configuration key that can be read or written with `gitmit config`.
+// Names ending in ".*" accept any sub-key, e.g. topicMappings.internal/api
+type KeySpec struct {
+	Name        string
+	Type        string // string, int, float, bool or list
+	Description string
+	Allowed     []string
+}
+
+// Keys lists every configuration key known to gitmit
+var Keys = []KeySpec{
+	{Name: "engine", Type: "string", Description: "Suggestion engine", Allowed: []string{"heuristic", "ollama"}},
+	{Name: "ollama.model", Type: "string", Description: "Ollama model name"},
+	{Name: "ollama.url", Type: "string", Description: "Ollama daemon URL"},
+	{Name: "ollama.temperature", Type: "float", Description: "Ollama sampling temperature"},
+	{Name: "projectType", Type: "string", Description: "Project language: a built-in or configured language pack, or generic"},
+	{Name: "diffStatThreshold", Type: "float", Description: "Threshold for the added/deleted line ratio analysis"},
+	{Name: "normalizeScoring", Type: "bool", Description: "Use normalized confidence weights"},
+	{Name: "maxSubjectLength", Type: "int", Description: "Maximum length of the subject line"},
+	{Name: "maxBodyLength", Type: "int", Description: "Maximum length of each body line"},
+	{Name: "subjectOverflow", Type: "string", Description: "How subjects over maxSubjectLength are handled", Allowed: []string{"shorten", "wrap"}},
+	{Name: "learnStyle", Type: "bool", Description: "Match capitalization, tense, scopes and emoji of the repository's commit log"},
+	{Name: "imperativeMood", Type: "bool", Description: "Normalize the leading verb of suggestions to imperative mood (added -> add)"},
+	{Name: "scopes", Type: "list", Description: "Known commit scopes (comma-separated); learned from the commit log when empty"},
+	{Name: "strictScopes", Type: "bool", Description: "Only allow known scopes in suggestions"},
+	{Name: "types", Type: "list", Description: "Commit types suggestions may use (comma-separated), e.g. feat,fix,chore,docs; every type when empty"},
+	{Name: "templateFile", Type: "string", Description: "Template pack file used instead of templates.json"},
+	{Name: "codeowners.enabled", Type: "bool", Description: "Use the CODEOWNERS team owning the staged files as the scope"},
+	{Name: "codeowners.mentionOwners", Type: "bool", Description: "List the code owners of the staged files in the message body"},
+	{Name: "rules.imperative", Type: "bool", Description: "Require an imperative verb at the start of the description"},
+	{Name: "rules.lowercase", Type: "bool", Description: "Require a lowercase description after the type"},
+	{Name: "rules.noTrailingPeriod", Type: "bool", Description: "Forbid a period at the end of the subject"},
+	{Name: "rules.blockedWords", Type: "list", Description: "Words that may not appear in commit messages (comma-separated)"},
+	{Name: "rules.ticketPattern", Type: "string", Description: "Regex the subject must start with, e.g. [A-Z]+-[0-9]+"},
+	{Name: "rules.requireTicket", Type: "bool", Description: "Prefix the ticket matching rules.ticketPattern found in the branch, asking for it when the branch has none, and refuse to commit without one"},
+	{Name: "rules.autoFix", Type: "bool", Description: "Fix rule violations automatically where possible"},
+	{Name: "spellcheck.enabled", Type: "bool", Description: "Flag likely typos in suggested subjects before committing"},
+	{Name: "spellcheck.words", Type: "list", Description: "Project words accepted by the spellchecker (comma-separated)"},
+	{Name: "safety.enabled", Type: "bool", Description: "Ask for confirmation before committing debug statements or conflict markers"},
+	{Name: "safety.debugPatterns", Type: "list", Description: "Extra regexes flagged as debug statements (comma-separated)"},
+	{Name: "safety.ignore", Type: "list", Description: "Files where debug statements are expected, gitignore-style (comma-separated)"},
+	{Name: "tests.nudge", Type: "bool", Description: "Warn in propose --context when source files change without their tests"},
+	{Name: "tests.patterns", Type: "list", Description: "Test files, gitignore-style, added to the built-in ones such as *.spec.* and test_*.py (comma-separated)"},
+	{Name: "risk.enabled", Type: "bool", Description: "Show the risk score of the staged changes in propose"},
+	{Name: "risk.criticalPaths", Type: "list", Description: "Files that make changes risky, gitignore-style (comma-separated)"},
+	{Name: "risk.highThreshold", Type: "int", Description: "Risk score from which changes are high risk (1-100)"},
+	{Name: "risk.confirmAuto", Type: "bool", Description: "Require typing \"yes\" before --auto commits high-risk changes"},
+	{Name: "tests.addNote", Type: "bool", Description: "Add \"Note: no tests updated\" to messages when source files change without their tests"},
+	{Name: "analyze.ignoreAuthors", Type: "list", Description: "Authors left out of gitmit analyze, matched in names and emails (comma-separated)"},
+	{Name: "tickets.provider", Type: "string", Description: "Ticket tracker of the branch's ticket: jira or linear"},
+	{Name: "tickets.pattern", Type: "string", Description: "Regex finding the ticket in the branch name (default [A-Z][A-Z0-9]+-[0-9]+)"},
+	{Name: "tickets.url", Type: "string", Description: "Jira site URL, e.g. https://acme.atlassian.net"},
+	{Name: "tickets.user", Type: "string", Description: "Jira account email the token belongs to"},
+	{Name: "tickets.token", Type: "string", Description: "Tracker API token; prefer GITMIT_TICKETS_TOKEN, JIRA_API_TOKEN or LINEAR_API_KEY"},
+	{Name: "tickets.footer", Type: "string", Description: "Footer referencing the ticket, containing {ticket} (default \"Refs: {ticket}\"; none to omit)"},
+	{Name: "tickets.transitionTo", Type: "string", Description: "State the ticket is moved to after committing, e.g. In Progress"},
+	{Name: "tickets.allowRepository", Type: "bool", Description: "Use the tracker URL, user and token of repository configs; only read from the global config"},
+	{Name: "tickets.githubIssues", Type: "bool", Description: "Look up the GitHub issues the branch or diff refer to with gh, for context and a Closes #N footer"},
+	{Name: "theme.accent", Type: "string", Description: "Color of suggested messages, prompts and highlights, e.g. cyan or \"bold blue\""},
+	{Name: "theme.success", Type: "string", Description: "Color of completed actions (default green)"},
+	{Name: "theme.warning", Type: "string", Description: "Color of warnings (default yellow)"},
+	{Name: "theme.error", Type: "string", Description: "Color of failures (default red)"},
+	{Name: "theme.heading", Type: "string", Description: "Color of section headings (default blue)"},
+	{Name: "theme.muted", Type: "string", Description: "Color of hints and details (default hiblack; try black on light terminals)"},
+	{Name: "theme.emoji", Type: "bool", Description: "Decorate output with emoji"},
+	{Name: "theme.ascii", Type: "bool", Description: "Plain ASCII output for terminals and screen readers: [OK] and [WARN] labels instead of emoji, no box drawing"},
+	{Name: "timeouts.git", Type: "string", Description: "How long each git command may run, e.g. 30s; 0 for no limit"},
+	{Name: "history.maxEntries", Type: "int", Description: "Suggestion history entries kept per repository, newest first"},
+	{Name: "history.maxAge", Type: "string", Description: "Age after which history entries are dropped, e.g. 90d or 2w; empty to keep them however old"},
+	{Name: "plugins.enabled", Type: "bool", Description: "Run the executables in $XDG_CONFIG_HOME/gitmit/plugins for extra diff hints, scopes and message rules"},
+	{Name: "plugins.disabled", Type: "list", Description: "Plugins not run, by file name without extension (comma-separated)"},
+	{Name: "timeouts.plugin", Type: "string", Description: "How long each run of a plugin may take, e.g. 5s; 0 for no limit"},
+	{Name: "hooks.preAnalyze", Type: "string", Description: "Shell command run before the staged files are analyzed; may narrow them or veto"},
+	{Name: "hooks.postSuggest", Type: "string", Description: "Shell command run after each suggestion; may change it or veto"},
+	{Name: "hooks.preCommit", Type: "string", Description: "Shell command run before committing; may change the message or veto the commit"},
+	{Name: "hooks.postCommit", Type: "string", Description: "Shell command run after committing, with the new commit's hash"},
+	{Name: "hooks.allowRepository", Type: "bool", Description: "Run hooks set by repository configs; only read from the global config"},
+	{Name: "timeouts.hook", Type: "string", Description: "How long each run of a hook may take, e.g. 30s; 0 for no limit"},
+	{Name: "changelog.enabled", Type: "bool", Description: "Offer to add an entry to the Unreleased section of the changelog when committing a feat, fix or breaking change"},
+	{Name: "changelog.file", Type: "string", Description: "Keep a Changelog file the entries are added to, relative to the repository root (default CHANGELOG.md)"},
+	{Name: "commitTemplate", Type: "bool", Description: "Add the sections of git's commit.template, such as Why:, to messages lacking them"},
+	{Name: "updateCheck", Type: "bool", Description: "Check for a new gitmit release once a day and print a one-line notice"},
+	{Name: "seed", Type: "int", Description: "Seed of the variety in template choices, so the same changes get the same suggestions"},
+	{Name: "deterministic", Type: "bool", Description: "Leave the variety out of template choices, breaking ties lexically"},
+	{Name: "timeouts.ai", Type: "string", Description: "How long each request to the AI model may take, e.g. 60s; 0 for no limit"},
+	{Name: "signalWeights.*", Type: "float", Description: "Weight of a signal source (branch, diffStat, keywords, patterns)"},
+	{Name: "topicMappings.*", Type: "string", Description: "Topic for files whose path contains the sub-key"},
+	{Name: "scopeAliases.*", Type: "string", Description: "Canonical scope for a raw scope such as a directory name"},
+	{Name: "keywordMappings.*", Type: "string", Description: "Purpose for diffs containing the sub-key"},
+}
+
+// LookupKey finds the spec for a key, matching wildcard specs by prefix
+func LookupKey(key string) (KeySpec, bool) {
+	for _, spec := range Keys {
+		if spec.Name == key {
+			return spec, true
+		}
+		if prefix, ok := strings.CutSuffix(spec.Name, "*"); ok {
+			if strings.HasPrefix(key, prefix) && len(key) > len(prefix) {
+				return spec, true
+			}
+		}
+	}
+	return KeySpec{}, false
+}
+
+// ParseValue converts a raw string into the typed value expected by the key
+func ParseValue(spec KeySpec, value string) (interface{}, error) {
+	var parsed interface{}
+	switch spec.Type {
+	case "int":
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid value for %s: %q is not a positive integer", spec.Name, value)
+		}
+		parsed = n
+	case "float":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f < 0 {
+			return nil, fmt.Errorf("invalid value for %s: %q is not a non-negative number", spec.Name, value)
+		}
+		parsed = f
+	case "bool":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %q is not a boolean (true/false)", spec.Name, value)
+		}
+		parsed = b
+	case "list":
+		var items []string
+		if strings.HasPrefix(strings.TrimSpace(value), "[") {
+			if err := json.Unmarshal([]byte(value), &items); err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %q is not a list of strings", spec.Name, value)
+			}
+		} else {
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		}
+		parsed = items
+	default:
+		parsed = value
+	}
+
+	if len(spec.Allowed) > 0 {
+		for _, allowed := range spec.Allowed {
+			if value == allowed {
+				return parsed, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid value for %s: %q (allowed: %s)", spec.Name, value, strings.Join(spec.Allowed, ", "))
+	}
+	return parsed, nil
+}
+
+// keyPath splits a key into its path segments. Sub-keys of wildcard specs are kept
+// intact since map keys like topicMappings.src/app.js may contain dots.
+func keyPath(spec KeySpec, key string) []string {
+	if prefix, ok := strings.CutSuffix(spec.Name, ".*"); ok {
+		return []string{prefix, strings.TrimPrefix(key, prefix+".")}
+	}
+	return strings.Split(key, ".")
+}
+
+// GetValue returns the value of a key in the given config
+func GetValue(cfg *Config, key string) (interface{}, error) {
+	spec, ok := LookupKey(key)
+	if !ok {
+		return nil, fmt.Errorf("unknown config key: %s", key)
+	}
+
+	raw, err := toRawMap(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var current interface{} = raw
+	for _, segment := range keyPath(spec, key) {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("config key not set: %s", key)
+		}
+		if current, ok = m[segment]; !ok {
+			return nil, fmt.Errorf("config key not set: %s", key)
+		}
+	}
+	return current, nil
+}
+
+// ListValues returns every known key with its value in the given config, sorted by key
+func ListValues(cfg *Config) ([][2]string, error) {
+	var entries [][2]string
+	for _, spec := range Keys {
+		if prefix, ok := strings.CutSuffix(spec.Name, ".*"); ok {
+			raw, err := toRawMap(cfg)
+			if err != nil {
+				return nil, err
+			}
+			sub, _ := raw[prefix].(map[string]interface{})
+			for k, v := range sub {
+				entries = append(entries, [2]string{prefix + "." + k, FormatValue(v)})
+			}
+			continue
+		}
+		v, err := GetValue(cfg, spec.Name)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, [2]string{spec.Name, FormatValue(v)})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i][0] < entries[j][0]
+	})
+	return entries, nil
+}
+
+// FormatValue renders a config value for display
+func FormatValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(val)
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// SetFileValue writes a single key into the config file at path, keeping all other
+// keys untouched, and the comments of YAML and TOML files. The file is created if
+// it does not exist.
+func SetFileValue(path, key, value string) error {
+	spec, ok := LookupKey(key)
+	if !ok {
+		return fmt.Errorf("unknown config key: %s", key)
+	}
+	parsed, err := ParseValue(spec, value)
+	if err != nil {
+		return err
+	}
+	return writeFileValue(path, keyPath(spec, key), parsed)
+}
+
+// UnsetFileValue removes a single key from the config file at path, so the
+// value of a lower config level or the default applies again
+func UnsetFileValue(path, key string) error {
+	spec, ok := LookupKey(key)
+	if !ok {
+		return fmt.Errorf("unknown config key: %s", key)
+	}
+	return writeFileValue(path, keyPath(spec, key), nil)
+}
+
+// toRawMap converts a config into a generic map keyed by the JSON field names
+func toRawMap(cfg *Config) (map[string]interface{}, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling config: %w", err)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("error unmarshaling config: %w", err)
+	}
+	return raw, nil
+}
+
+// writeRawConfig encodes a generic config map in the format implied by the path
+func writeRawConfig(path string, raw map[string]interface{}) error {
+	var data []byte
+	var err error
+	switch FormatFromPath(path) {
+	case FormatYAML:
+		data, err = yaml.Marshal(raw)
+	case FormatTOML:
+		var buf strings.Builder
+		err = toml.NewEncoder(&buf).Encode(raw)
+		data = []byte(buf.String())
+	default:
+		data, err = json.MarshalIndent(raw, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("error encoding config file %s: %w", path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creating config directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("error writing config file %s: %w", path, err)
+	}
+	return nil
+}
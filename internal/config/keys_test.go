@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetAndUnsetFileValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		comments []string // Comments the edits must keep
+	}{
+		{
+			name: "yaml",
+			file: ".gitmit.yaml",
+			content: `# Team config
+maxSubjectLength: 72 # keep it short
+tickets:
+  # Our Jira
+  provider: jira
+`,
+			comments: []string{"# Team config", "# keep it short"},
+		},
+		{
+			name: "toml",
+			file: ".gitmit.toml",
+			content: `# Team config
+maxSubjectLength = 72 # keep it short
+
+# Our Jira
+[tickets]
+provider = "jira"
+`,
+			comments: []string{"# Team config", "# keep it short"},
+		},
+		{
+			name:    "toml inline table, rewritten",
+			file:    ".gitmit.toml",
+			content: "maxSubjectLength = 72\ntickets = { provider = \"jira\" }\n",
+		},
+		{
+			name:    "json",
+			file:    ".gitmit.json",
+			content: `{"maxSubjectLength": 72, "tickets": {"provider": "jira"}}`,
+		},
+		{
+			name: "new file",
+			file: ".gitmit.yaml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, kv := range [][2]string{
+				{"maxSubjectLength", "60"},
+				{"engine", "ollama"},
+				{"scopes", "api, cli"},
+				{"tickets.provider", "linear"},
+				{"topicMappings.src/app.js", "app"},
+			} {
+				if err := SetFileValue(path, kv[0], kv[1]); err != nil {
+					t.Fatalf("SetFileValue(%s) = %v", kv[0], err)
+				}
+			}
+			cfg := DefaultConfig()
+			if err := mergeConfigFromFile(cfg, path); err != nil {
+				t.Fatal(err)
+			}
+			if cfg.MaxSubjectLength != 60 || cfg.Engine != "ollama" || cfg.Tickets.Provider != "linear" || cfg.TopicMappings["src/app.js"] != "app" {
+				t.Errorf("after set: maxSubjectLength=%d engine=%q provider=%q topicMappings=%v", cfg.MaxSubjectLength, cfg.Engine, cfg.Tickets.Provider, cfg.TopicMappings)
+			}
+			if want := []string{"api", "cli"}; !reflect.DeepEqual(cfg.Scopes, want) {
+				t.Errorf("after set: scopes = %v, want %v", cfg.Scopes, want)
+			}
+
+			for _, key := range []string{"engine", "tickets.provider", "tickets.url"} {
+				if err := UnsetFileValue(path, key); err != nil {
+					t.Fatalf("UnsetFileValue(%s) = %v", key, err)
+				}
+			}
+			raw, err := readRawConfig(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := raw["engine"]; ok {
+				t.Errorf("engine still set after unset: %v", raw)
+			}
+			if _, ok := raw["tickets"]; ok {
+				t.Errorf("tickets left after unsetting its only key: %v", raw)
+			}
+			if _, ok := raw["maxSubjectLength"]; !ok {
+				t.Errorf("unset removed other keys: %v", raw)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, comment := range tt.comments {
+				if !strings.Contains(string(data), comment) {
+					t.Errorf("comment %q lost:\n%s", comment, data)
+				}
+			}
+		})
+	}
+}
+
+func TestUnsetFileValueUnknownKey(t *testing.T) {
+	if err := UnsetFileValue(filepath.Join(t.TempDir(), ".gitmit.json"), "nope"); err == nil {
+		t.Error("UnsetFileValue() of an unknown key succeeded")
+	}
+}
@@ -0,0 +1,362 @@
+package config
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// LanguageConfig is a language pack: the files identifying a project in the
+// language, and the keywords, topic mappings and purposes tailored to its code. Packs in
+// the config extend the built-in pack of the same name or add a language.
+type LanguageConfig struct {
+	Markers       []string                  `json:"markers,omitempty" yaml:"markers,omitempty" toml:"markers,omitempty"`                   // File names or glob patterns at the top of the tree identifying the language
+	Keywords      map[string]map[string]int `json:"keywords,omitempty" yaml:"keywords,omitempty" toml:"keywords,omitempty"`                // action -> keyword -> score
+	TopicMappings map[string]string         `json:"topicMappings,omitempty" yaml:"topicMappings,omitempty" toml:"topicMappings,omitempty"` // Path pattern -> topic
+	Purposes      map[string]string         `json:"purposes,omitempty" yaml:"purposes,omitempty" toml:"purposes,omitempty"`                // Diff keyword -> purpose, tried before the generic ones
+}
+
+// languagePack is a built-in language pack
+type languagePack struct {
+	Name string
+	LanguageConfig
+}
+
+// languagePacks are the built-in language packs, in the order projects are
+// detected: Kotlin before Java since both may use Gradle, and Terraform last
+// since its files often sit next to the code they deploy
+var languagePacks = []languagePack{
+	{Name: "go", LanguageConfig: LanguageConfig{
+		Markers: []string{"go.mod"},
+		Keywords: map[string]map[string]int{
+			"feat": {"func": 3, "type": 2, "struct": 2, "interface": 2},
+			"fix":  {"if err != nil": 3, "error": 2, "panic": 2},
+		},
+		Purposes: map[string]string{
+			"goroutine": "concurrency", "sync.mutex": "concurrency", "errgroup": "concurrency",
+			"context.context": "context propagation", "http.handler": "api endpoints", "grpc": "grpc services",
+			"cobra.command": "cli commands", "errors.is": "error handling", "sql.db": "database logic",
+		},
+	}},
+	{Name: "rust", LanguageConfig: LanguageConfig{
+		Markers: []string{"Cargo.toml"},
+		Keywords: map[string]map[string]int{
+			"feat": {"pub fn": 3, "impl": 2, "struct": 2, "enum": 2, "trait": 2},
+			"fix":  {"unwrap": 2, "panic!": 2, "err(": 2, ".expect(": 1},
+			"test": {"#[test]": 3, "assert_eq!": 2},
+		},
+		TopicMappings: map[string]string{"src/bin/": "cli", "benches/": "bench"},
+		Purposes: map[string]string{
+			"tokio": "asynchronous operations", "async fn": "asynchronous operations", "serde": "serialization",
+			"unsafe": "unsafe code", "clap": "cli arguments", "diesel": "database logic", "sqlx": "database logic",
+			"arc<mutex": "concurrency", "thiserror": "error handling", "anyhow": "error handling",
+		},
+	}},
+	{Name: "nodejs", LanguageConfig: LanguageConfig{
+		Markers: []string{"package.json"},
+		Keywords: map[string]map[string]int{
+			"feat": {"function": 3, "class": 2, "const": 1, "export": 2},
+			"fix":  {"try": 2, "catch": 2, "throw": 2},
+		},
+		TopicMappings: map[string]string{"hooks/": "hooks", "reducers/": "state", "store/": "state", "components/": "ui"},
+		Purposes: map[string]string{
+			"reducer": "state management", "redux": "state management", "usestate": "state management",
+			"useeffect": "react hooks", "usecallback": "react hooks", "hook": "react hooks", "component": "ui components",
+			"express": "api endpoints", "prisma": "database logic", "mongoose": "database logic",
+			"webpack": "build system", "vite": "build system", "jest": "testing", "eslint": "code style",
+		},
+	}},
+	{Name: "python", LanguageConfig: LanguageConfig{
+		Markers: []string{"pyproject.toml", "requirements.txt", "setup.py", "Pipfile"},
+		Keywords: map[string]map[string]int{
+			"feat": {"def": 3, "class": 2, "async def": 3},
+			"fix":  {"try": 2, "except": 2, "raise": 2},
+		},
+		TopicMappings: map[string]string{"serializers": "api", "migrations/": "db"},
+		Purposes: map[string]string{
+			"migration": "database migration", "serializer": "serialization", "models.model": "data models",
+			"pydantic": "data validation", "fastapi": "api endpoints", "viewset": "api endpoints", "celery": "background tasks",
+			"sqlalchemy": "database logic", "pandas": "data processing", "pytest": "testing", "asyncio": "asynchronous operations",
+		},
+	}},
+	{Name: "kotlin", LanguageConfig: LanguageConfig{
+		Markers: []string{"build.gradle.kts", "settings.gradle.kts"},
+		Keywords: map[string]map[string]int{
+			"feat": {"fun ": 3, "class ": 2, "data class": 2, "interface ": 2},
+			"fix":  {"catch (": 2, "throw ": 2, "!!": 2},
+			"test": {"@test": 3, "assertequals": 2},
+		},
+		TopicMappings: map[string]string{"viewmodel/": "ui", "src/main/resources/": "config"},
+		Purposes: map[string]string{
+			"viewmodel": "view models", "suspend fun": "asynchronous operations", "coroutine": "concurrency",
+			"@composable": "ui components", "@entity": "data models", "retrofit": "api client", "@inject": "dependency injection",
+		},
+	}},
+	{Name: "java", LanguageConfig: LanguageConfig{
+		Markers: []string{"pom.xml", "build.gradle", "settings.gradle"},
+		Keywords: map[string]map[string]int{
+			"feat": {"public class": 3, "interface ": 2, "public ": 1},
+			"fix":  {"catch (": 2, "throw new": 2, "nullpointerexception": 3},
+			"test": {"@test": 3, "assertequals": 2},
+		},
+		TopicMappings: map[string]string{"src/main/resources/": "config"},
+		Purposes: map[string]string{
+			"@entity": "data models", "@restcontroller": "api endpoints", "@service": "business logic",
+			"@autowired": "dependency injection", "@transactional": "transactions", "completablefuture": "asynchronous operations",
+			"flyway": "database migration", "liquibase": "database migration", "jpa": "database logic",
+		},
+	}},
+	{Name: "csharp", LanguageConfig: LanguageConfig{
+		Markers: []string{"*.sln", "*.csproj"},
+		Keywords: map[string]map[string]int{
+			"feat": {"public class": 3, "interface ": 2, "async task": 2},
+			"fix":  {"catch (": 2, "throw new": 2, "nullreferenceexception": 3},
+			"test": {"[fact]": 3, "[test]": 3, "assert.": 2},
+		},
+		TopicMappings: map[string]string{"Migrations/": "db", "Controllers/": "api"},
+		Purposes: map[string]string{
+			"viewmodel": "view models", "migration": "database migration", "dbcontext": "database logic",
+			"[apicontroller]": "api endpoints", "iservicecollection": "dependency injection", "async task": "asynchronous operations",
+			"blazor": "user interface", "linq": "data queries",
+		},
+	}},
+	{Name: "ruby", LanguageConfig: LanguageConfig{
+		Markers: []string{"Gemfile", "*.gemspec"},
+		Keywords: map[string]map[string]int{
+			"feat": {"def ": 3, "class ": 2, "module ": 2},
+			"fix":  {"rescue": 2, "raise": 2},
+			"test": {"describe ": 2, "expect(": 2},
+		},
+		TopicMappings: map[string]string{"db/migrate/": "db", "app/views/": "ui"},
+		Purposes: map[string]string{
+			"migration": "database migration", "activerecord": "database logic", "has_many": "model associations",
+			"belongs_to": "model associations", "validates": "validation", "before_action": "controller filters",
+			"serializer": "serialization", "sidekiq": "background jobs", "rspec": "testing",
+		},
+	}},
+	{Name: "php", LanguageConfig: LanguageConfig{
+		Markers: []string{"composer.json"},
+		Keywords: map[string]map[string]int{
+			"feat": {"public function": 3, "class ": 2, "function ": 1},
+			"fix":  {"catch (": 2, "throw new": 2},
+			"test": {"phpunit": 2, "$this->assert": 2},
+		},
+		TopicMappings: map[string]string{"database/migrations/": "db", "resources/views/": "ui"},
+		Purposes: map[string]string{
+			"migration": "database migration", "eloquent": "database logic", "artisan": "cli commands",
+			"blade": "templates", "twig": "templates", "route::": "routing", "phpunit": "testing",
+		},
+	}},
+	{Name: "swift", LanguageConfig: LanguageConfig{
+		Markers: []string{"Package.swift", "*.xcodeproj", "*.xcworkspace"},
+		Keywords: map[string]map[string]int{
+			"feat": {"func ": 3, "struct ": 2, "protocol ": 2, "extension ": 2},
+			"fix":  {"guard ": 2, "catch": 2, "fatalerror": 2},
+			"test": {"xctassert": 3, "func test": 3},
+		},
+		TopicMappings: map[string]string{"ViewModels/": "ui", "Resources/": "assets"},
+		Purposes: map[string]string{
+			"viewmodel": "view models", "swiftui": "user interface", "uikit": "user interface", "@published": "state management",
+			"combine": "reactive streams", "coredata": "data persistence", "urlsession": "networking",
+		},
+	}},
+	{Name: "terraform", LanguageConfig: LanguageConfig{
+		Markers: []string{"*.tf", ".terraform.lock.hcl"},
+		Keywords: map[string]map[string]int{
+			"feat":  {"resource \"": 3, "module \"": 2, "output \"": 1},
+			"fix":   {"depends_on": 2, "lifecycle": 1},
+			"build": {"required_providers": 3, "required_version": 2, "version =": 1},
+		},
+		TopicMappings: map[string]string{"modules/": "infra", "environments/": "env"},
+		Purposes: map[string]string{
+			"aws_": "aws infrastructure", "google_": "gcp infrastructure", "azurerm_": "azure infrastructure",
+			"iam": "access control", "vpc": "networking", "backend \"": "state backend", "variable \"": "configuration",
+		},
+	}},
+}
+
+// LanguageNames returns the names of the built-in language packs, in the
+// order projects are detected
+func LanguageNames() []string {
+	names := make([]string, len(languagePacks))
+	for i, pack := range languagePacks {
+		names[i] = pack.Name
+	}
+	return names
+}
+
+// DetectProjectType detects the language of the project at the top of the
+// working tree from the files identifying each built-in language pack. It
+// returns "generic" when none matches.
+func DetectProjectType(ctx context.Context) string {
+	return detectProjectType(LocalDir(ctx), nil)
+}
+
+// detectProjectType returns the first language whose markers are in dir:
+// languages only defined in the config first, then the built-in ones, with
+// the markers the config adds to them
+func detectProjectType(dir string, languages map[string]LanguageConfig) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "generic"
+	}
+	found := func(markers []string) bool {
+		for _, marker := range markers {
+			for _, entry := range entries {
+				if ok, _ := filepath.Match(marker, entry.Name()); ok {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	custom := make([]string, 0, len(languages))
+	for name := range languages {
+		if _, ok := builtinPack(name); !ok {
+			custom = append(custom, name)
+		}
+	}
+	sort.Strings(custom)
+	for _, name := range custom {
+		if found(languages[name].Markers) {
+			return name
+		}
+	}
+	for _, pack := range languagePacks {
+		if found(pack.Markers) || found(languages[pack.Name].Markers) {
+			return pack.Name
+		}
+	}
+	return "generic"
+}
+
+// builtinPack returns the built-in language pack of a name
+func builtinPack(name string) (LanguageConfig, bool) {
+	for _, pack := range languagePacks {
+		if pack.Name == name {
+			return pack.LanguageConfig, true
+		}
+	}
+	return LanguageConfig{}, false
+}
+
+// knownLanguage reports whether a project type names a built-in or configured
+// language pack, or is generic
+func (c *Config) knownLanguage(name string) bool {
+	_, builtin := builtinPack(name)
+	_, configured := c.Languages[name]
+	return builtin || configured || name == "generic"
+}
+
+// loadLanguageDefaults adds the keywords and topic mappings of the language
+// pack of the project type, built-in and configured, to those of the config.
+// Keywords and mappings set in the config win over the pack's.
+func loadLanguageDefaults(cfg *Config) {
+	builtin, _ := builtinPack(cfg.ProjectType)
+	for _, pack := range []LanguageConfig{cfg.Languages[cfg.ProjectType], builtin} {
+		for action, keywords := range pack.Keywords {
+			if cfg.Keywords[action] == nil {
+				cfg.Keywords[action] = make(map[string]int)
+			}
+			for keyword, score := range keywords {
+				if _, ok := cfg.Keywords[action][keyword]; !ok {
+					cfg.Keywords[action][keyword] = score
+				}
+			}
+		}
+		for pattern, topic := range pack.TopicMappings {
+			if _, ok := cfg.TopicMappings[pattern]; !ok {
+				cfg.TopicMappings[pattern] = topic
+			}
+		}
+	}
+}
+
+// mergeLanguages merges the language packs of a config file into those of
+// the config: markers are added to, and keywords, mappings and purposes
+// override, the ones of the same language
+func mergeLanguages(dst map[string]LanguageConfig, src map[string]LanguageConfig) {
+	for name, pack := range src {
+		merged := dst[name]
+		for _, marker := range pack.Markers {
+			if !containsString(merged.Markers, marker) {
+				merged.Markers = append(merged.Markers, marker)
+			}
+		}
+		for action, keywords := range pack.Keywords {
+			if merged.Keywords == nil {
+				merged.Keywords = make(map[string]map[string]int)
+			}
+			if merged.Keywords[action] == nil {
+				merged.Keywords[action] = make(map[string]int)
+			}
+			for keyword, score := range keywords {
+				merged.Keywords[action][keyword] = score
+			}
+		}
+		for pattern, topic := range pack.TopicMappings {
+			if merged.TopicMappings == nil {
+				merged.TopicMappings = make(map[string]string)
+			}
+			merged.TopicMappings[pattern] = topic
+		}
+		for keyword, purpose := range pack.Purposes {
+			if merged.Purposes == nil {
+				merged.Purposes = make(map[string]string)
+			}
+			merged.Purposes[keyword] = purpose
+		}
+		dst[name] = merged
+	}
+}
+
+// validateLanguages checks that language packs are named, can be detected,
+// score known commit types and map keywords to purposes
+func validateLanguages(cfg *Config, add func(key, format string, args ...interface{})) {
+	if cfg.ProjectType != "" && !cfg.knownLanguage(cfg.ProjectType) {
+		add("projectType", "unknown project type %q (expected a language pack: %s)", cfg.ProjectType, strings.Join(cfg.languageNames(), ", "))
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.Languages)) {
+		pack := cfg.Languages[name]
+		key := "languages." + name
+		if strings.TrimSpace(name) == "" || name == "generic" {
+			add(key, "invalid language name %q", name)
+		}
+		if _, ok := builtinPack(name); !ok && len(pack.Markers) == 0 {
+			add(key+".markers", "no markers; the language is only used when projectType selects it")
+		}
+		for _, marker := range pack.Markers {
+			if _, err := filepath.Match(marker, ""); err != nil || strings.Contains(marker, "/") {
+				add(key+".markers", "invalid marker %q (expected a file name or glob pattern at the top of the tree)", marker)
+			}
+		}
+		for _, action := range slices.Sorted(maps.Keys(pack.Keywords)) {
+			if !containsString(CommitTypes, action) {
+				add(key+".keywords."+action, "unknown commit type (expected one of %s)", strings.Join(CommitTypes, ", "))
+			}
+		}
+		for _, keyword := range slices.Sorted(maps.Keys(pack.Purposes)) {
+			if strings.TrimSpace(keyword) == "" {
+				add(key+".purposes", "empty keyword matches every diff")
+			} else if strings.TrimSpace(pack.Purposes[keyword]) == "" {
+				add(key+".purposes."+keyword, "purpose is empty")
+			}
+		}
+	}
+}
+
+// languageNames returns the names of the built-in and configured language packs
+func (c *Config) languageNames() []string {
+	names := LanguageNames()
+	for _, name := range slices.Sorted(maps.Keys(c.Languages)) {
+		if !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+	return append(names, "generic")
+}
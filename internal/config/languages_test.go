@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectProjectType(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     []string
+		languages map[string]LanguageConfig
+		want      string
+	}{
+		{"go", []string{"go.mod", "main.tf"}, nil, "go"},
+		{"rust", []string{"Cargo.toml"}, nil, "rust"},
+		{"kotlin before java", []string{"build.gradle.kts", "settings.gradle"}, nil, "kotlin"},
+		{"java", []string{"pom.xml"}, nil, "java"},
+		{"csharp glob", []string{"Shop.sln"}, nil, "csharp"},
+		{"ruby gemspec", []string{"widget.gemspec"}, nil, "ruby"},
+		{"swift", []string{"Package.swift"}, nil, "swift"},
+		{"terraform", []string{"main.tf", "variables.tf"}, nil, "terraform"},
+		{"generic", []string{"README.md"}, nil, "generic"},
+		{"configured language", []string{"build.zig", "main.tf"}, map[string]LanguageConfig{"zig": {Markers: []string{"build.zig"}}}, "zig"},
+		{"extended marker", []string{"deno.json"}, map[string]LanguageConfig{"nodejs": {Markers: []string{"deno.json"}}}, "nodejs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, file := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, file), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := detectProjectType(dir, tt.languages); got != tt.want {
+				t.Errorf("detectProjectType(%v) = %q, want %q", tt.files, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadLanguageDefaults(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ProjectType = "rust"
+	cfg.Keywords["fix"] = map[string]int{"unwrap": 5}
+	cfg.TopicMappings["benches/"] = "perf"
+	cfg.Languages = map[string]LanguageConfig{"rust": {
+		Keywords:      map[string]map[string]int{"fix": {"unwrap": 1, "todo!": 2}},
+		TopicMappings: map[string]string{"xtask/": "tooling"},
+	}}
+	loadLanguageDefaults(cfg)
+
+	if got := cfg.Keywords["fix"]["unwrap"]; got != 5 {
+		t.Errorf("fix.unwrap = %d, want the configured 5", got)
+	}
+	if got := cfg.Keywords["fix"]["todo!"]; got != 2 {
+		t.Errorf("fix.todo! = %d, want 2 from the configured pack", got)
+	}
+	if got := cfg.Keywords["test"]["#[test]"]; got != 3 {
+		t.Errorf("test.#[test] = %d, want 3 from the built-in pack", got)
+	}
+	if cfg.TopicMappings["benches/"] != "perf" || cfg.TopicMappings["src/bin/"] != "cli" || cfg.TopicMappings["xtask/"] != "tooling" {
+		t.Errorf("TopicMappings = %v", cfg.TopicMappings)
+	}
+}
+
+func TestMergeLanguages(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := mergeConfigData(cfg, []byte(`{"languages": {"nodejs": {"markers": ["deno.json"], "keywords": {"feat": {"export": 1}}}}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mergeConfigData(cfg, []byte(`{"languages": {"nodejs": {"markers": ["deno.json", "bun.lockb"], "keywords": {"feat": {"export": 4}}}}}`)); err != nil {
+		t.Fatal(err)
+	}
+	pack := cfg.Languages["nodejs"]
+	if strings.Join(pack.Markers, ",") != "deno.json,bun.lockb" {
+		t.Errorf("Markers = %v, want deno.json,bun.lockb", pack.Markers)
+	}
+	if pack.Keywords["feat"]["export"] != 4 {
+		t.Errorf("feat.export = %d, want 4", pack.Keywords["feat"]["export"])
+	}
+}
+
+func TestValidateLanguages(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ProjectType = "cobol"
+	cfg.Languages = map[string]LanguageConfig{
+		"zig":  {Markers: []string{"build.zig", "src/main.zig"}, Keywords: map[string]map[string]int{"feature": {"pub fn": 2}}},
+		"nim":  {},
+		"rust": {Keywords: map[string]map[string]int{"fix": {"unwrap": 1}}},
+	}
+	var keys []string
+	validateLanguages(cfg, func(key, format string, args ...interface{}) { keys = append(keys, key) })
+
+	want := "projectType,languages.nim.markers,languages.zig.markers,languages.zig.keywords.feature"
+	if got := strings.Join(keys, ","); got != want {
+		t.Errorf("issues at %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// PathConfig overrides configuration for commits touching one area of a monorepo
+type PathConfig struct {
+	Scope            string                    `json:"scope,omitempty" yaml:"scope,omitempty" toml:"scope,omitempty"`                                  // Scope for changes below the path
+	ProjectType      string                    `json:"projectType,omitempty" yaml:"projectType,omitempty" toml:"projectType,omitempty"`                // Language of this area
+	TemplateFile     string                    `json:"templateFile,omitempty" yaml:"templateFile,omitempty" toml:"templateFile,omitempty"`             // Template pack for this area
+	TopicMappings    map[string]string         `json:"topicMappings,omitempty" yaml:"topicMappings,omitempty" toml:"topicMappings,omitempty"`          // Extra topic mappings
+	KeywordMappings  map[string]string         `json:"keywordMappings,omitempty" yaml:"keywordMappings,omitempty" toml:"keywordMappings,omitempty"`    // Extra keyword mappings
+	Keywords         map[string]map[string]int `json:"keywords,omitempty" yaml:"keywords,omitempty" toml:"keywords,omitempty"`                         // Extra keyword scoring
+	MaxSubjectLength int                       `json:"maxSubjectLength,omitempty" yaml:"maxSubjectLength,omitempty" toml:"maxSubjectLength,omitempty"` // Subject length limit
+}
+
+// ForFiles returns the configuration for a commit touching the given files. When
+// most files fall below one configured path (the deepest matching path per file),
+// that path's overrides are merged on top of a copy of the config; otherwise the
+// config is returned unchanged. The matched path is returned as well.
+func (c *Config) ForFiles(files []string) (*Config, string, error) {
+	if len(c.Paths) == 0 || len(files) == 0 {
+		return c, "", nil
+	}
+
+	counts := make(map[string]int)
+	for _, file := range files {
+		if path := c.matchPath(file); path != "" {
+			counts[path]++
+		}
+	}
+
+	best := ""
+	for path, count := range counts {
+		if count > counts[best] || (count == counts[best] && path < best) {
+			best = path
+		}
+	}
+	if counts[best]*2 <= len(files) {
+		return c, "", nil
+	}
+
+	override := c.Paths[best]
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, "", fmt.Errorf("error copying config: %w", err)
+	}
+	merged := &Config{}
+	if err := json.Unmarshal(data, merged); err != nil {
+		return nil, "", fmt.Errorf("error copying config: %w", err)
+	}
+
+	data, err = json.Marshal(override)
+	if err != nil {
+		return nil, "", fmt.Errorf("error encoding path overrides for %s: %w", best, err)
+	}
+	if err := mergeConfigData(merged, data); err != nil {
+		return nil, "", fmt.Errorf("error applying path overrides for %s: %w", best, err)
+	}
+	normalizePaths(merged)
+	if override.Scope != "" {
+		// Mapping the path itself to the scope makes it win over directory names and CODEOWNERS
+		if merged.TopicMappings == nil {
+			merged.TopicMappings = make(map[string]string)
+		}
+		merged.TopicMappings[strings.Trim(best, "/")+"/"] = override.Scope
+	}
+	if override.ProjectType != "" {
+		loadLanguageDefaults(merged)
+	}
+	return merged, best, nil
+}
+
+// matchPath returns the deepest configured path containing the file
+func (c *Config) matchPath(file string) string {
+	best := ""
+	for path := range c.Paths {
+		prefix := strings.Trim(path, "/")
+		if (file == prefix || strings.HasPrefix(file, prefix+"/")) && len(path) > len(best) {
+			best = path
+		}
+	}
+	return best
+}
+
+// normalizePaths rewrites the repository paths of a config with forward
+// slashes, the separator git reports paths with on every platform, so paths
+// written with Windows backslashes still match. Gitignore-style patterns are
+// left alone since a backslash escapes a character there.
+func normalizePaths(c *Config) {
+	c.TopicMappings = slashKeys(c.TopicMappings)
+	c.ScopeAliases = slashKeys(c.ScopeAliases)
+	if len(c.Paths) > 0 {
+		paths := make(map[string]PathConfig, len(c.Paths))
+		for dir, override := range c.Paths {
+			override.TopicMappings = slashKeys(override.TopicMappings)
+			paths[slashPath(dir)] = override
+		}
+		c.Paths = paths
+	}
+	for i, mapping := range c.Tests.Mappings {
+		c.Tests.Mappings[i].Source = slashPath(mapping.Source)
+		for j, test := range mapping.Tests {
+			c.Tests.Mappings[i].Tests[j] = slashPath(test)
+		}
+	}
+}
+
+// slashKeys returns a map with its keys rewritten by slashPath
+func slashKeys(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	slashed := make(map[string]string, len(m))
+	for key, value := range m {
+		slashed[slashPath(key)] = value
+	}
+	return slashed
+}
+
+// slashPath replaces the backslashes of a path with forward slashes
+func slashPath(p string) string {
+	return strings.ReplaceAll(p, `\`, "/")
+}
+
+// validatePaths checks path overrides for values that can never apply
+func validatePaths(cfg *Config, add func(key, format string, args ...interface{})) {
+	for _, path := range slices.Sorted(maps.Keys(cfg.Paths)) {
+		override := cfg.Paths[path]
+		key := "paths." + path
+		if strings.Trim(path, "/") == "" {
+			add(key, "path must name a directory")
+		}
+		if override.ProjectType != "" && !cfg.knownLanguage(override.ProjectType) {
+			add(key+".projectType", "unknown project type %q (expected one of %s)", override.ProjectType, strings.Join(cfg.languageNames(), ", "))
+		}
+		if override.Scope != "" && strings.ContainsAny(override.Scope, "()\n") {
+			add(key+".scope", "invalid scope %q", override.Scope)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package config
+
+import "testing"
+
+func TestForFiles(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Paths = map[string]PathConfig{
+		"services":          {MaxSubjectLength: 60},
+		"services/payments": {Scope: "payments", TemplateFile: "payments.json"},
+	}
+
+	merged, path, err := cfg.ForFiles([]string{"services/payments/charge.go", "services/payments/refund.go", "README.md"})
+	if err != nil {
+		t.Fatalf("ForFiles failed: %v", err)
+	}
+	if path != "services/payments" {
+		t.Errorf("matched path = %q, want services/payments", path)
+	}
+	if merged.TopicMappings["services/payments/"] != "payments" {
+		t.Errorf("scope mapping missing: %v", merged.TopicMappings)
+	}
+	if merged.TemplateFile != "payments.json" {
+		t.Errorf("TemplateFile = %q, want payments.json", merged.TemplateFile)
+	}
+	if _, ok := cfg.TopicMappings["services/payments/"]; ok {
+		t.Error("ForFiles modified the original config")
+	}
+
+	if merged, path, _ := cfg.ForFiles([]string{"services/api/x.go", "docs/a.md", "docs/b.md"}); path != "" || merged != cfg {
+		t.Errorf("expected no override when no path owns most files, got %q", path)
+	}
+}
+
+func TestNormalizePaths(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TopicMappings = map[string]string{`internal\api`: "api"}
+	cfg.ScopeAliases = map[string]string{`internal\analyzer`: "analyzer"}
+	cfg.Paths = map[string]PathConfig{`services\payments`: {Scope: "payments"}}
+	cfg.Tests.Mappings = []TestMapping{{Source: `src\{dir}\{name}.ts`, Tests: []string{`test\{dir}\{name}.test.ts`}}}
+	normalizePaths(cfg)
+
+	if cfg.TopicMappings["internal/api"] != "api" {
+		t.Errorf("TopicMappings = %v, want internal/api", cfg.TopicMappings)
+	}
+	if cfg.ScopeAliases["internal/analyzer"] != "analyzer" {
+		t.Errorf("ScopeAliases = %v, want internal/analyzer", cfg.ScopeAliases)
+	}
+	if _, path, _ := cfg.ForFiles([]string{"services/payments/charge.go"}); path != "services/payments" {
+		t.Errorf("matched path = %q, want services/payments", path)
+	}
+	mapping := cfg.Tests.Mappings[0]
+	if mapping.Source != "src/{dir}/{name}.ts" || mapping.Tests[0] != "test/{dir}/{name}.test.ts" {
+		t.Errorf("test mapping = %+v, want forward slashes", mapping)
+	}
+}
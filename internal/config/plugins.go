@@ -0,0 +1,37 @@
+package config
+
+import "strings"
+
+// PluginsConfig controls the executables in the plugins directory that add
+// diff hints, scopes and message rules to gitmit's own
+type PluginsConfig struct {
+	Enabled  bool     `json:"enabled" yaml:"enabled" toml:"enabled"`                                  // Run the plugins in the plugins directory
+	Disabled []string `json:"disabled,omitempty" yaml:"disabled,omitempty" toml:"disabled,omitempty"` // Names of plugins not run, e.g. jira-rules
+}
+
+// IsDisabled reports whether the plugin of the given name is turned off
+func (p PluginsConfig) IsDisabled(name string) bool {
+	for _, disabled := range p.Disabled {
+		if strings.EqualFold(strings.TrimSpace(disabled), name) {
+			return true
+		}
+	}
+	return false
+}
+
+func mergePlugins(cfg *PluginsConfig, filePlugins PluginsConfig) {
+	for _, name := range filePlugins.Disabled {
+		if !containsString(cfg.Disabled, name) {
+			cfg.Disabled = append(cfg.Disabled, name)
+		}
+	}
+}
+
+// validatePlugins checks that every disabled plugin is named
+func validatePlugins(plugins PluginsConfig, add func(key, format string, args ...interface{})) {
+	for _, name := range plugins.Disabled {
+		if strings.TrimSpace(name) == "" {
+			add("plugins.disabled", "empty name never matches a plugin")
+		}
+	}
+}
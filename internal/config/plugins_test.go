@@ -0,0 +1,26 @@
+package config
+
+import "testing"
+
+func TestMergePlugins(t *testing.T) {
+	cfg := DefaultConfig()
+	if !cfg.Plugins.Enabled {
+		t.Fatal("plugins are disabled by default, want them enabled")
+	}
+
+	if err := mergeConfigData(cfg, []byte(`{"plugins": {"disabled": ["jira"]}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mergeConfigData(cfg, []byte(`{"plugins": {"enabled": false, "disabled": ["Billing", "jira"]}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Plugins.Enabled {
+		t.Error("Plugins.Enabled = true, want it turned off by the later file")
+	}
+	if len(cfg.Plugins.Disabled) != 2 || !cfg.Plugins.IsDisabled("billing") || cfg.Plugins.IsDisabled("scopes") {
+		t.Errorf("Plugins.Disabled = %v, want jira and Billing, matched without case", cfg.Plugins.Disabled)
+	}
+	if got := cfg.Timeouts.PluginTimeout().String(); got != "5s" {
+		t.Errorf("PluginTimeout() = %s, want 5s by default", got)
+	}
+}
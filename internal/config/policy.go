@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// defaultTicketPattern matches issue keys such as ABC-123 in branch names
+const defaultTicketPattern = `[A-Z][A-Z0-9]+-[0-9]+`
+
+// defaultTicketFormat is how a ticket is prefixed to the subject line
+const defaultTicketFormat = "[{ticket}] "
+
+// BranchPolicy adjusts suggestions for commits made on branches matching Pattern
+type BranchPolicy struct {
+	Pattern       string `json:"pattern" yaml:"pattern" toml:"pattern"`                                                 // Branch glob, e.g. hotfix/*
+	Type          string `json:"type,omitempty" yaml:"type,omitempty" toml:"type,omitempty"`                            // Commit type to force
+	RequireTicket bool   `json:"requireTicket,omitempty" yaml:"requireTicket,omitempty" toml:"requireTicket,omitempty"` // Ask for a ticket when the branch name has none
+	TicketPattern string `json:"ticketPattern,omitempty" yaml:"ticketPattern,omitempty" toml:"ticketPattern,omitempty"` // Regex finding the ticket in the branch name
+	TicketFormat  string `json:"ticketFormat,omitempty" yaml:"ticketFormat,omitempty" toml:"ticketFormat,omitempty"`    // Subject prefix containing {ticket}
+	Templates     string `json:"templates,omitempty" yaml:"templates,omitempty" toml:"templates,omitempty"`             // Template pack file to use instead of templates.json
+}
+
+// BranchPolicy returns the first policy whose pattern matches the branch, or nil
+func (c *Config) BranchPolicy(branch string) *BranchPolicy {
+	if c == nil || branch == "" {
+		return nil
+	}
+	for i := range c.BranchPolicies {
+		if c.BranchPolicies[i].Matches(branch) {
+			return &c.BranchPolicies[i]
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the policy applies to a branch. Patterns use glob
+// syntax; a trailing /* also matches nested branches like feature/team/x.
+func (p *BranchPolicy) Matches(branch string) bool {
+	if ok, err := path.Match(p.Pattern, branch); err == nil && ok {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(p.Pattern, "/*"); ok {
+		return strings.HasPrefix(branch, prefix+"/")
+	}
+	return false
+}
+
+// Ticket extracts the ticket reference from a branch name, or returns an empty string
+func (p *BranchPolicy) Ticket(branch string) (string, error) {
+	pattern := p.TicketPattern
+	if pattern == "" {
+		pattern = defaultTicketPattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid ticketPattern for branch policy %q: %w", p.Pattern, err)
+	}
+	return findTicket(re, branch), nil
+}
+
+// TicketPrefix returns the subject prefix for a ticket in the policy's format
+func (p *BranchPolicy) TicketPrefix(ticket string) string {
+	return TicketPrefix(p.TicketFormat, ticket)
+}
+
+// findTicket finds a ticket in a branch name, or returns an empty string.
+// Branch names are often lowercase, so the upper-cased name is tried too:
+// feature/proj-123-login finds PROJ-123.
+func findTicket(re *regexp.Regexp, branch string) string {
+	if ticket := re.FindString(branch); ticket != "" {
+		return ticket
+	}
+	return re.FindString(strings.ToUpper(branch))
+}
+
+// TicketPrefix returns the subject prefix for a ticket in a format containing
+// {ticket}, or in the default format "[{ticket}] " when format is empty
+func TicketPrefix(format, ticket string) string {
+	if format == "" {
+		format = defaultTicketFormat
+	}
+	return strings.ReplaceAll(format, "{ticket}", ticket)
+}
+
+// TicketPrefixRegex matches the prefix TicketPrefix gives a ticket matching
+// pattern, and the spaces after it, at the start of a subject
+func TicketPrefixRegex(format, pattern string) (*regexp.Regexp, error) {
+	if format == "" {
+		format = defaultTicketFormat
+	}
+	before, after, _ := strings.Cut(format, "{ticket}")
+	return regexp.Compile(`^` + regexp.QuoteMeta(before) + `(?:` + pattern + `)` + regexp.QuoteMeta(strings.TrimRight(after, " ")) + `\s*`)
+}
+
+// validateBranchPolicies checks branch policies for patterns and values that can never apply
+func validateBranchPolicies(policies []BranchPolicy, add func(key, format string, args ...interface{})) {
+	for i, p := range policies {
+		key := fmt.Sprintf("branchPolicies[%d]", i)
+		if p.Pattern == "" {
+			add(key+".pattern", "pattern is required")
+		} else if _, err := path.Match(p.Pattern, ""); err != nil {
+			add(key+".pattern", "invalid glob %q: %v", p.Pattern, err)
+		}
+		if p.Type != "" && !containsString(CommitTypes, p.Type) {
+			add(key+".type", "unknown commit type %q (expected one of %s)", p.Type, strings.Join(CommitTypes, ", "))
+		}
+		if p.TicketPattern != "" {
+			if _, err := regexp.Compile(p.TicketPattern); err != nil {
+				add(key+".ticketPattern", "invalid regex: %v", err)
+			}
+		}
+		if p.TicketFormat != "" && !strings.Contains(p.TicketFormat, "{ticket}") {
+			add(key+".ticketFormat", "format must contain {ticket}")
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+import "testing"
+
+func TestBranchPolicy(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.BranchPolicies = []BranchPolicy{
+		{Pattern: "hotfix/*", Type: "fix"},
+		{Pattern: "feature/*", TicketFormat: "{ticket}: "},
+	}
+
+	if p := cfg.BranchPolicy("hotfix/login-crash"); p == nil || p.Type != "fix" {
+		t.Errorf("BranchPolicy(hotfix/login-crash) = %+v, want hotfix policy", p)
+	}
+	if p := cfg.BranchPolicy("feature/team/ABC-12-login"); p == nil || p.Pattern != "feature/*" {
+		t.Errorf("BranchPolicy(feature/team/...) = %+v, want feature policy", p)
+	}
+	if p := cfg.BranchPolicy("main"); p != nil {
+		t.Errorf("BranchPolicy(main) = %+v, want nil", p)
+	}
+
+	p := cfg.BranchPolicy("feature/ABC-12-login")
+	ticket, err := p.Ticket("feature/ABC-12-login")
+	if err != nil || ticket != "ABC-12" {
+		t.Fatalf("Ticket = %q, %v, want ABC-12", ticket, err)
+	}
+	if got := p.TicketPrefix(ticket); got != "ABC-12: " {
+		t.Errorf("TicketPrefix = %q, want %q", got, "ABC-12: ")
+	}
+}
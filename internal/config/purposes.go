@@ -0,0 +1,79 @@
+package config
+
+// genericPurposes maps words of a diff to the purpose of the changes, for
+// projects in any language; language packs add words of their ecosystem
+var genericPurposes = map[string]string{
+	"login":       "authentication",
+	"auth":        "authentication",
+	"user":        "user management",
+	"validate":    "validation",
+	"validation":  "validation",
+	"query":       "database query",
+	"database":    "database operations",
+	"cache":       "caching",
+	"caching":     "caching",
+	"refactor":    "code restructuring",
+	"logging":     "logging",
+	"logger":      "logging",
+	"docs":        "documentation",
+	"readme":      "documentation",
+	"middleware":  "middleware",
+	"test":        "testing",
+	"tests":       "testing",
+	"config":      "configuration",
+	"ci":          "ci/cd",
+	"log":         "logging",
+	"sql":         "database logic",
+	"gorm":        "database logic",
+	"feat":        "new feature",
+	"bug":         "bug fix",
+	"fix":         "bug fix",
+	"hotfix":      "bug fix",
+	"cleanup":     "code cleanup",
+	"perf":        "performance improvement",
+	"performance": "performance improvement",
+	"security":    "security update",
+	"dep":         "dependency update",
+	"dependency":  "dependency update",
+	"build":       "build system",
+	"style":       "code style",
+	"serialize":   "serialization",
+	"deserialize": "deserialization",
+	"json":        "data handling",
+	"xml":         "data handling",
+	"async":       "asynchronous operations",
+	"await":       "asynchronous operations",
+	"concurrent":  "concurrency",
+	"parallel":    "parallel processing",
+	"api":         "api endpoints",
+	"endpoint":    "api endpoints",
+	"route":       "routing",
+	"ui":          "user interface",
+	"frontend":    "user interface",
+	"backend":     "backend logic",
+	"server":      "server logic",
+	"client":      "client logic",
+	"docker":      "docker configuration",
+	"kubernetes":  "kubernetes configuration",
+	"k8s":         "kubernetes configuration",
+	"aws":         "aws integration",
+	"gcp":         "gcp integration",
+	"azure":       "azure integration",
+	"error":       "error handling",
+	"exception":   "error handling",
+}
+
+// PurposeVocabularies returns the vocabularies mapping words of a diff to the
+// purpose of the changes, tried in order: the purposes of the project type's
+// language pack, configured over built-in, then the generic ones
+func (c *Config) PurposeVocabularies() []map[string]string {
+	builtin, _ := builtinPack(c.ProjectType)
+	language := make(map[string]string, len(builtin.Purposes))
+	for keyword, purpose := range builtin.Purposes {
+		language[keyword] = purpose
+	}
+	for keyword, purpose := range c.Languages[c.ProjectType].Purposes {
+		language[keyword] = purpose
+	}
+	return []map[string]string{language, genericPurposes}
+}
@@ -0,0 +1,26 @@
+package config
+
+import "strings"
+
+// RiskConfig controls the risk score of staged changes
+type RiskConfig struct {
+	Enabled       bool     `json:"enabled" yaml:"enabled" toml:"enabled"`                                                 // Show the risk score in propose
+	CriticalPaths []string `json:"criticalPaths,omitempty" yaml:"criticalPaths,omitempty" toml:"criticalPaths,omitempty"` // Files that make changes risky, gitignore-style
+	HighThreshold int      `json:"highThreshold" yaml:"highThreshold" toml:"highThreshold"`                               // Score from which changes are high risk
+	ConfirmAuto   bool     `json:"confirmAuto" yaml:"confirmAuto" toml:"confirmAuto"`                                     // Require typing "yes" before --auto commits high-risk changes
+}
+
+// defaultCriticalPaths are paths whose changes are risky in most projects
+var defaultCriticalPaths = []string{"migrations/", ".github/workflows/", "Dockerfile", "*.tf", "*.sql"}
+
+// validateRisk checks the threshold and critical paths
+func validateRisk(risk RiskConfig, add func(key, format string, args ...interface{})) {
+	if risk.HighThreshold < 1 || risk.HighThreshold > 100 {
+		add("risk.highThreshold", "threshold %d is outside the score range 1-100", risk.HighThreshold)
+	}
+	for _, pattern := range risk.CriticalPaths {
+		if strings.TrimSpace(pattern) == "" {
+			add("risk.criticalPaths", "empty pattern never matches a file")
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package config
+
+import "testing"
+
+func TestMergeRisk(t *testing.T) {
+	cfg := DefaultConfig()
+	if !cfg.Risk.Enabled || cfg.Risk.HighThreshold != 60 || len(cfg.Risk.CriticalPaths) == 0 {
+		t.Fatalf("default risk = %+v, want enabled with threshold 60 and critical paths", cfg.Risk)
+	}
+
+	if err := mergeConfigData(cfg, []byte(`{"risk": {"criticalPaths": ["deploy/"], "highThreshold": 50, "confirmAuto": true}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mergeConfigData(cfg, []byte(`{"risk": {"enabled": false}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Risk.Enabled || !cfg.Risk.ConfirmAuto {
+		t.Errorf("risk = %+v, want disabled with confirmAuto kept", cfg.Risk)
+	}
+	if got := cfg.Risk.CriticalPaths; len(got) != 1 || got[0] != "deploy/" {
+		t.Errorf("risk.criticalPaths = %v, want the defaults replaced by [deploy/]", got)
+	}
+	if cfg.Risk.HighThreshold != 50 {
+		t.Errorf("risk.highThreshold = %d, want 50", cfg.Risk.HighThreshold)
+	}
+
+	cfg.Risk.HighThreshold = 150
+	issues := Validate(cfg)
+	if len(issues) != 1 || issues[0].Key != "risk.highThreshold" {
+		t.Errorf("Validate() = %v, want an out-of-range threshold", issues)
+	}
+}
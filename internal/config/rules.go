@@ -0,0 +1,73 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RulesConfig holds the rules every commit message must follow. Violations are
+// fixed automatically where possible, or shown before committing.
+type RulesConfig struct {
+	Imperative       bool     `json:"imperative" yaml:"imperative" toml:"imperative"`                                        // Description must start with an imperative verb
+	Lowercase        bool     `json:"lowercase" yaml:"lowercase" toml:"lowercase"`                                           // Description must start lowercase after the type
+	NoTrailingPeriod bool     `json:"noTrailingPeriod" yaml:"noTrailingPeriod" toml:"noTrailingPeriod"`                      // Subject must not end with a period
+	BlockedWords     []string `json:"blockedWords,omitempty" yaml:"blockedWords,omitempty" toml:"blockedWords,omitempty"`    // Words that may not appear in the message
+	TicketPattern    string   `json:"ticketPattern,omitempty" yaml:"ticketPattern,omitempty" toml:"ticketPattern,omitempty"` // Regex of the ticket the subject must start with, as in [PAY-42]
+	RequireTicket    bool     `json:"requireTicket" yaml:"requireTicket" toml:"requireTicket"`                               // Prefix the ticket of the branch and refuse to commit without one
+	AutoFix          bool     `json:"autoFix" yaml:"autoFix" toml:"autoFix"`                                                 // Fix violations automatically where possible
+}
+
+// mergeRules applies the rules of a higher config level; raw holds the decoded
+// "rules" object so that booleans are only changed when present
+func mergeRules(cfg *RulesConfig, fileRules RulesConfig, raw map[string]interface{}) {
+	for key, target := range map[string]*bool{
+		"imperative":       &cfg.Imperative,
+		"lowercase":        &cfg.Lowercase,
+		"noTrailingPeriod": &cfg.NoTrailingPeriod,
+		"requireTicket":    &cfg.RequireTicket,
+		"autoFix":          &cfg.AutoFix,
+	} {
+		if b, ok := raw[key].(bool); ok {
+			*target = b
+		}
+	}
+	if fileRules.BlockedWords != nil {
+		cfg.BlockedWords = fileRules.BlockedWords
+	}
+	if fileRules.TicketPattern != "" {
+		cfg.TicketPattern = fileRules.TicketPattern
+	}
+}
+
+// Ticket finds the ticket subjects must start with in a branch name, or
+// returns an empty string
+func (r RulesConfig) Ticket(branch string) string {
+	re, err := regexp.Compile(r.TicketPattern)
+	if r.TicketPattern == "" || err != nil {
+		return ""
+	}
+	return findTicket(re, branch)
+}
+
+// IsTicket reports whether the whole of a ticket entered by hand matches
+// the ticket pattern
+func (r RulesConfig) IsTicket(ticket string) bool {
+	re, err := regexp.Compile(`^(?:` + r.TicketPattern + `)$`)
+	return r.TicketPattern != "" && err == nil && re.MatchString(ticket)
+}
+
+// validateRules checks the rules for values that can never match
+func validateRules(rules RulesConfig, add func(key, format string, args ...interface{})) {
+	if rules.TicketPattern != "" {
+		if _, err := regexp.Compile(rules.TicketPattern); err != nil {
+			add("rules.ticketPattern", "invalid regex: %v", err)
+		}
+	} else if rules.RequireTicket {
+		add("rules.requireTicket", "needs rules.ticketPattern to find the ticket")
+	}
+	for _, word := range rules.BlockedWords {
+		if strings.TrimSpace(word) == "" {
+			add("rules.blockedWords", "empty blocked word")
+		}
+	}
+}
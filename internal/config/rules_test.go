@@ -0,0 +1,46 @@
+package config
+
+import "testing"
+
+func TestRequireTicket(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := mergeConfigData(cfg, []byte(`{"rules": {"ticketPattern": "[A-Z]+-[0-9]+", "requireTicket": true}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Rules.RequireTicket || !cfg.Rules.AutoFix {
+		t.Fatalf("rules = %+v, want requireTicket set and autoFix kept", cfg.Rules)
+	}
+	if issues := Validate(cfg); len(issues) != 0 {
+		t.Errorf("Validate() = %v, want no issues", issues)
+	}
+
+	for branch, want := range map[string]string{
+		"feature/PAY-42-login": "PAY-42",
+		"feature/pay-42-login": "PAY-42",
+		"main":                 "",
+	} {
+		if got := cfg.Rules.Ticket(branch); got != want {
+			t.Errorf("Ticket(%q) = %q, want %q", branch, got, want)
+		}
+	}
+	if got := TicketPrefix("", "PAY-42"); got != "[PAY-42] " {
+		t.Errorf("TicketPrefix() = %q, want the ticket in brackets", got)
+	}
+	for format, subject := range map[string]string{"": "[PAY-42] feat: add login", "{ticket}: ": "PAY-42: feat: add login"} {
+		re, err := TicketPrefixRegex(format, cfg.Rules.TicketPattern)
+		if err != nil || re.FindString(subject) != TicketPrefix(format, "PAY-42") {
+			t.Errorf("TicketPrefixRegex(%q) = %v, %v, want it to match the prefix of %q", format, re, err, subject)
+		}
+	}
+	for ticket, want := range map[string]bool{"PAY-42": true, "pay-42": false, "PAY-42 login": false, "": false} {
+		if got := cfg.Rules.IsTicket(ticket); got != want {
+			t.Errorf("IsTicket(%q) = %v, want %v", ticket, got, want)
+		}
+	}
+
+	cfg.Rules.TicketPattern = ""
+	issues := Validate(cfg)
+	if len(issues) != 1 || issues[0].Key != "rules.requireTicket" {
+		t.Errorf("Validate() = %v, want an issue for requireTicket without a pattern", issues)
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// SafetyConfig controls the scan of staged changes for leftover debug statements
+// and conflict markers before committing
+type SafetyConfig struct {
+	Enabled       bool     `json:"enabled" yaml:"enabled" toml:"enabled"`                                                 // Ask for confirmation before committing leftovers
+	DebugPatterns []string `json:"debugPatterns,omitempty" yaml:"debugPatterns,omitempty" toml:"debugPatterns,omitempty"` // Extra regexes flagged as debug statements
+	Ignore        []string `json:"ignore,omitempty" yaml:"ignore,omitempty" toml:"ignore,omitempty"`                      // Files where debug statements are expected, gitignore-style
+}
+
+// validateSafety checks that the debug patterns compile
+func validateSafety(safety SafetyConfig, add func(key, format string, args ...interface{})) {
+	for _, pattern := range safety.DebugPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			add("safety.debugPatterns", "invalid pattern %q: %v", pattern, err)
+		}
+	}
+	for _, pattern := range safety.Ignore {
+		if strings.TrimSpace(pattern) == "" {
+			add("safety.ignore", "empty pattern never matches a file")
+		}
+	}
+}
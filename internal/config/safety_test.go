@@ -0,0 +1,32 @@
+package config
+
+import "testing"
+
+func TestMergeSafety(t *testing.T) {
+	cfg := DefaultConfig()
+	if !cfg.Safety.Enabled {
+		t.Fatal("safety check should be enabled by default")
+	}
+
+	if err := mergeConfigData(cfg, []byte(`{"safety": {"debugPatterns": ["\\bdump\\("], "ignore": ["cmd/"]}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mergeConfigData(cfg, []byte(`{"safety": {"enabled": false, "ignore": ["cmd/", "scripts/"]}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Safety.Enabled {
+		t.Error("safety.enabled = true, want false from the second level")
+	}
+	if got := cfg.Safety.Ignore; len(got) != 2 || got[0] != "cmd/" || got[1] != "scripts/" {
+		t.Errorf("safety.ignore = %v, want [cmd/ scripts/]", got)
+	}
+	if got := cfg.Safety.DebugPatterns; len(got) != 1 {
+		t.Errorf("safety.debugPatterns = %v, want the first level's pattern", got)
+	}
+
+	cfg.Safety.DebugPatterns = append(cfg.Safety.DebugPatterns, "(")
+	issues := Validate(cfg)
+	if len(issues) != 1 || issues[0].Key != "safety.debugPatterns" {
+		t.Errorf("Validate() = %v, want an invalid debug pattern", issues)
+	}
+}
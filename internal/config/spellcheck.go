@@ -0,0 +1,41 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// SpellcheckConfig controls the typo check of suggested subjects
+type SpellcheckConfig struct {
+	Enabled bool     `json:"enabled" yaml:"enabled" toml:"enabled"`                         // Flag likely typos before committing
+	Words   []string `json:"words,omitempty" yaml:"words,omitempty" toml:"words,omitempty"` // Project jargon accepted as correctly spelled
+}
+
+// AddSpellingWord adds a word to the spellcheck dictionary of the config file at
+// path, keeping all other keys untouched. The file is created if it does not exist.
+func AddSpellingWord(path, word string) error {
+	raw := map[string]interface{}{}
+	if _, err := os.Stat(path); err == nil {
+		if raw, err = readRawConfig(path); err != nil {
+			return err
+		}
+	}
+
+	spellcheck, _ := raw["spellcheck"].(map[string]interface{})
+	words, _ := spellcheck["words"].([]interface{})
+	for _, w := range words {
+		if s, ok := w.(string); ok && strings.EqualFold(s, word) {
+			return nil
+		}
+	}
+	return writeFileValue(path, []string{"spellcheck", "words"}, append(words, word))
+}
+
+// validateSpellcheck checks the dictionary for entries that can never match a word
+func validateSpellcheck(spellcheck SpellcheckConfig, add func(key, format string, args ...interface{})) {
+	for _, word := range spellcheck.Words {
+		if strings.TrimSpace(word) == "" || strings.ContainsAny(strings.TrimSpace(word), " \t\n") {
+			add("spellcheck.words", "invalid word %q: use single words", word)
+		}
+	}
+}
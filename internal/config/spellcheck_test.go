@@ -0,0 +1,32 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAddSpellingWord(t *testing.T) {
+	for _, name := range []string{".gitmit.json", ".gitmit.yaml", ".gitmit.toml"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := SetFileValue(path, "maxSubjectLength", "60"); err != nil {
+			t.Fatal(err)
+		}
+		for _, word := range []string{"kubelet", "gitmit", "Kubelet"} {
+			if err := AddSpellingWord(path, word); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+		}
+
+		cfg := DefaultConfig()
+		if err := mergeConfigFromFile(cfg, path); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if want := []string{"kubelet", "gitmit"}; !reflect.DeepEqual(cfg.Spellcheck.Words, want) {
+			t.Errorf("%s: words = %v, want %v", name, cfg.Spellcheck.Words, want)
+		}
+		if cfg.MaxSubjectLength != 60 || !cfg.Spellcheck.Enabled {
+			t.Errorf("%s: other keys changed: maxSubjectLength=%d enabled=%v", name, cfg.MaxSubjectLength, cfg.Spellcheck.Enabled)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// InheritTemplates is a template list entry that stands for the templates of the
+// level below: lower config levels, then the template pack
+const InheritTemplates = "..."
+
+// TemplateList holds the custom templates for one action and topic. In config
+// files it may be written as a single string or as a list.
+type TemplateList []string
+
+// UnmarshalJSON accepts a single template string as well as a list
+func (l *TemplateList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = TemplateList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("expected a template string or a list of templates")
+	}
+	*l = list
+	return nil
+}
+
+// Extend resolves the list on top of base: a "..." entry is replaced by the base
+// templates, and a list without one replaces base entirely. When base is empty the
+// "..." entry is kept so a lower level can still be inherited later.
+func (l TemplateList) Extend(base []string) TemplateList {
+	var out TemplateList
+	for _, tmpl := range l {
+		if tmpl == InheritTemplates && len(base) > 0 {
+			out = append(out, base...)
+			continue
+		}
+		out = append(out, tmpl)
+	}
+	return out
+}
+
+// mergeTemplates layers custom templates from a higher config level over a lower one
+func mergeTemplates(dst, src map[string]map[string]TemplateList) {
+	for action, topics := range src {
+		if dst[action] == nil {
+			dst[action] = make(map[string]TemplateList)
+		}
+		for topic, list := range topics {
+			dst[action][topic] = list.Extend(dst[action][topic])
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeTemplates(t *testing.T) {
+	cfg := DefaultConfig()
+	levels := []string{
+		`{"templates": {"A": {"api": "feat(api): add {item}", "auth": ["feat(auth): add {item}", "..."]}}}`,
+		`{"templates": {"A": {"api": ["...", "feat(api): expose {item}"], "auth": ["feat(auth): support {item}"]}}}`,
+	}
+	for _, data := range levels {
+		if err := mergeConfigData(cfg, []byte(data)); err != nil {
+			t.Fatalf("mergeConfigData() = %v", err)
+		}
+	}
+
+	want := map[string]TemplateList{
+		"api":  {"feat(api): add {item}", "feat(api): expose {item}"},
+		"auth": {"feat(auth): support {item}"},
+	}
+	if !reflect.DeepEqual(cfg.Templates["A"], want) {
+		t.Errorf("Templates[A] = %v, want %v", cfg.Templates["A"], want)
+	}
+}
+
+func TestTemplatesEnvOverride(t *testing.T) {
+	t.Setenv("GITMIT_TEMPLATES", `{"M": {"_default": ["...", "fix: correct {item}"]}}`)
+
+	cfg := DefaultConfig()
+	if err := mergeConfigData(cfg, []byte(`{"templates": {"M": {"_default": "fix: repair {item}"}}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyEnvOverrides(cfg); err != nil {
+		t.Fatalf("applyEnvOverrides() = %v", err)
+	}
+
+	want := TemplateList{"fix: repair {item}", "fix: correct {item}"}
+	if got := cfg.Templates["M"]["_default"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Templates[M][_default] = %v, want %v", got, want)
+	}
+}
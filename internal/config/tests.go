@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TestsConfig controls the nudge shown when source files change without their tests
+type TestsConfig struct {
+	Nudge    bool          `json:"nudge" yaml:"nudge" toml:"nudge"`                                        // Warn in propose --context when sources change without tests
+	AddNote  bool          `json:"addNote" yaml:"addNote" toml:"addNote"`                                  // Add "Note: no tests updated" to the message
+	Mappings []TestMapping `json:"mappings,omitempty" yaml:"mappings,omitempty" toml:"mappings,omitempty"` // Source to test paths, tried before the built-in mappings
+	Patterns []string      `json:"patterns,omitempty" yaml:"patterns,omitempty" toml:"patterns,omitempty"` // Test files, gitignore-style, added to DefaultTestPatterns
+}
+
+// DefaultTestPatterns are the test files of common ecosystems, gitignore-style
+var DefaultTestPatterns = []string{
+	"*_test.go", "*.test.*", "*.spec.*", "test_*.py", "*_test.py", "*_spec.rb", "*_test.rb",
+	"*Test.java", "*Tests.java", "*Test.kt", "*Tests.kt", "*Test.php", "*Tests.cs", "*Tests.swift",
+	"__tests__/", "test/", "tests/", "spec/", "testdata/",
+}
+
+// TestMapping maps source files to the test files covering them. In both
+// patterns {dir} stands for a directory path, possibly empty, and {name} for a
+// file name without its extension, e.g. "src/{dir}/{name}.ts" ->
+// "test/{dir}/{name}.spec.ts".
+type TestMapping struct {
+	Source string   `json:"source" yaml:"source" toml:"source"`
+	Tests  []string `json:"tests" yaml:"tests" toml:"tests"`
+}
+
+// validateTests checks that every mapping can match a source and name its
+// tests, and that no test pattern is empty
+func validateTests(tests TestsConfig, add func(key, format string, args ...interface{})) {
+	for _, pattern := range tests.Patterns {
+		if strings.TrimSpace(pattern) == "" {
+			add("tests.patterns", "empty pattern never matches a file")
+		}
+	}
+	for i, mapping := range tests.Mappings {
+		key := fmt.Sprintf("tests.mappings[%d]", i)
+		if !strings.Contains(mapping.Source, "{name}") {
+			add(key, "source %q has no {name} placeholder", mapping.Source)
+		}
+		for _, placeholder := range []string{"{dir}", "{name}"} {
+			if strings.Count(mapping.Source, placeholder) > 1 {
+				add(key, "source %q uses %s more than once", mapping.Source, placeholder)
+			}
+		}
+		if len(mapping.Tests) == 0 {
+			add(key, "no test patterns for %q", mapping.Source)
+		}
+		for _, test := range mapping.Tests {
+			if strings.Contains(test, "{dir}") && !strings.Contains(mapping.Source, "{dir}") {
+				add(key, "test pattern %q uses {dir}, which source %q does not capture", test, mapping.Source)
+			}
+		}
+	}
+}
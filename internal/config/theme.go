@@ -0,0 +1,81 @@
+package config
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ThemeConfig sets the colors and icons of gitmit's terminal output. Each
+// color is a space-separated list of ThemeAttributes, e.g. "bold blue", or
+// "none" for plain text.
+type ThemeConfig struct {
+	Accent  string `json:"accent,omitempty" yaml:"accent,omitempty" toml:"accent,omitempty"`    // Suggested messages, prompts and highlights
+	Success string `json:"success,omitempty" yaml:"success,omitempty" toml:"success,omitempty"` // Completed actions
+	Warning string `json:"warning,omitempty" yaml:"warning,omitempty" toml:"warning,omitempty"` // Warnings and questions needing attention
+	Error   string `json:"error,omitempty" yaml:"error,omitempty" toml:"error,omitempty"`       // Failures
+	Heading string `json:"heading,omitempty" yaml:"heading,omitempty" toml:"heading,omitempty"` // Section headings
+	Muted   string `json:"muted,omitempty" yaml:"muted,omitempty" toml:"muted,omitempty"`       // Hints and details
+	Emoji   bool   `json:"emoji" yaml:"emoji" toml:"emoji"`                                     // Decorate output with emoji
+	ASCII   bool   `json:"ascii" yaml:"ascii" toml:"ascii"`                                     // Plain ASCII output: [OK] and [WARN] labels instead of emoji, no box drawing
+}
+
+// ThemeAttributes are the words theme colors are made of
+var ThemeAttributes = []string{
+	"none", "bold", "faint", "italic", "underline",
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"hiblack", "hired", "higreen", "hiyellow", "hiblue", "himagenta", "hicyan", "hiwhite",
+}
+
+// defaultTheme suits dark and light terminals alike
+var defaultTheme = ThemeConfig{
+	Accent:  "cyan",
+	Success: "green",
+	Warning: "yellow",
+	Error:   "red",
+	Heading: "blue",
+	Muted:   "hiblack",
+	Emoji:   true,
+}
+
+// Colors returns the theme colors by key
+func (t ThemeConfig) Colors() map[string]string {
+	return map[string]string{
+		"accent":  t.Accent,
+		"success": t.Success,
+		"warning": t.Warning,
+		"error":   t.Error,
+		"heading": t.Heading,
+		"muted":   t.Muted,
+	}
+}
+
+func mergeTheme(cfg *ThemeConfig, fileTheme ThemeConfig) {
+	for _, field := range []struct {
+		target *string
+		value  string
+	}{
+		{&cfg.Accent, fileTheme.Accent},
+		{&cfg.Success, fileTheme.Success},
+		{&cfg.Warning, fileTheme.Warning},
+		{&cfg.Error, fileTheme.Error},
+		{&cfg.Heading, fileTheme.Heading},
+		{&cfg.Muted, fileTheme.Muted},
+	} {
+		if field.value != "" {
+			*field.target = field.value
+		}
+	}
+}
+
+// validateTheme checks that every theme color is made of known attributes
+func validateTheme(theme ThemeConfig, add func(key, format string, args ...interface{})) {
+	colors := theme.Colors()
+	for _, key := range slices.Sorted(maps.Keys(colors)) {
+		for _, word := range strings.Fields(strings.ToLower(colors[key])) {
+			if !containsString(ThemeAttributes, word) {
+				add("theme."+key, "unknown color %q (expected %s)", word, strings.Join(ThemeAttributes, ", "))
+			}
+		}
+	}
+}
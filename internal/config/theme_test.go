@@ -0,0 +1,32 @@
+package config
+
+import "testing"
+
+func TestMergeTheme(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Theme.Accent != "cyan" || !cfg.Theme.Emoji {
+		t.Fatalf("default theme = %+v, want cyan accents with emoji", cfg.Theme)
+	}
+
+	if err := mergeConfigData(cfg, []byte(`{"theme": {"accent": "bold blue", "muted": "black"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mergeConfigData(cfg, []byte(`{"theme": {"emoji": false, "ascii": true}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Theme.Accent != "bold blue" || cfg.Theme.Muted != "black" || cfg.Theme.Success != "green" {
+		t.Errorf("theme = %+v, want accent and muted replaced and the other colors kept", cfg.Theme)
+	}
+	if cfg.Theme.Emoji {
+		t.Error("theme.emoji = true, want false")
+	}
+	if !cfg.Theme.ASCII {
+		t.Error("theme.ascii = false, want true")
+	}
+
+	cfg.Theme.Error = "bright red"
+	issues := Validate(cfg)
+	if len(issues) != 1 || issues[0].Key != "theme.error" {
+		t.Errorf("Validate() = %v, want an unknown color in theme.error", issues)
+	}
+}
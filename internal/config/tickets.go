@@ -0,0 +1,119 @@
+package config
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// defaultTicketFooter is the footer referencing the ticket of the branch
+const defaultTicketFooter = "Refs: {ticket}"
+
+// TicketsConfig connects commits to the Jira or Linear ticket named in the
+// branch: its title is given to the AI as context, the message gets a footer
+// referencing it, and the ticket can be moved to another state on commit.
+// GitHub issues referenced by the branch or the diff are looked up with gh.
+type TicketsConfig struct {
+	Provider        string `json:"provider,omitempty" yaml:"provider,omitempty" toml:"provider,omitempty"`             // jira or linear; empty disables the integration
+	Pattern         string `json:"pattern,omitempty" yaml:"pattern,omitempty" toml:"pattern,omitempty"`                // Regex finding the ticket in the branch name
+	URL             string `json:"url,omitempty" yaml:"url,omitempty" toml:"url,omitempty"`                            // Jira site, e.g. https://acme.atlassian.net
+	User            string `json:"user,omitempty" yaml:"user,omitempty" toml:"user,omitempty"`                         // Jira account email
+	Token           string `json:"token,omitempty" yaml:"token,omitempty" toml:"token,omitempty"`                      // API token; JIRA_API_TOKEN or LINEAR_API_KEY when empty
+	Footer          string `json:"footer,omitempty" yaml:"footer,omitempty" toml:"footer,omitempty"`                   // Footer containing {ticket}; "none" adds no footer
+	TransitionTo    string `json:"transitionTo,omitempty" yaml:"transitionTo,omitempty" toml:"transitionTo,omitempty"` // State the ticket is moved to after committing
+	GitHubIssues    bool   `json:"githubIssues" yaml:"githubIssues" toml:"githubIssues"`                               // Look up the GitHub issues the branch and diff refer to with gh
+	AllowRepository bool   `json:"allowRepository" yaml:"allowRepository" toml:"allowRepository"`                      // Use the URL, user and token of repository configs; only read from the global config
+}
+
+// Enabled reports whether a ticket provider is configured
+func (t TicketsConfig) Enabled() bool {
+	return t.Provider != ""
+}
+
+// Ticket extracts the ticket from a branch name, or returns an empty string
+func (t TicketsConfig) Ticket(branch string) string {
+	pattern := t.Pattern
+	if pattern == "" {
+		pattern = defaultTicketPattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return ""
+	}
+	return findTicket(re, branch)
+}
+
+// FooterFor returns the footer referencing a ticket, or an empty string when
+// footers are turned off
+func (t TicketsConfig) FooterFor(ticket string) string {
+	footer := t.Footer
+	if footer == "" {
+		footer = defaultTicketFooter
+	}
+	if footer == "none" {
+		return ""
+	}
+	return strings.ReplaceAll(footer, "{ticket}", ticket)
+}
+
+// guardTickets keeps the site and account of the global config when a
+// repository config changes them: the tracker gets the user's API token, so
+// a cloned repository could otherwise send it to a host of its choosing. It
+// reports whether repository settings were dropped.
+func guardTickets(cfg *TicketsConfig, global TicketsConfig) bool {
+	cfg.AllowRepository = global.AllowRepository
+	if global.AllowRepository || cfg.URL == global.URL && cfg.User == global.User && cfg.Token == global.Token {
+		return false
+	}
+	cfg.URL, cfg.User, cfg.Token = global.URL, global.User, global.Token
+	return true
+}
+
+// mergeTickets applies the ticket settings of a higher config level
+func mergeTickets(cfg *TicketsConfig, fileTickets TicketsConfig) {
+	for _, field := range []struct {
+		target *string
+		value  string
+	}{
+		{&cfg.Provider, fileTickets.Provider},
+		{&cfg.Pattern, fileTickets.Pattern},
+		{&cfg.URL, fileTickets.URL},
+		{&cfg.User, fileTickets.User},
+		{&cfg.Token, fileTickets.Token},
+		{&cfg.Footer, fileTickets.Footer},
+		{&cfg.TransitionTo, fileTickets.TransitionTo},
+	} {
+		if field.value != "" {
+			*field.target = field.value
+		}
+	}
+}
+
+// validateTickets checks the provider, the pattern and the settings each
+// provider needs
+func validateTickets(tickets TicketsConfig, add func(key, format string, args ...interface{})) {
+	switch tickets.Provider {
+	case "":
+		return
+	case "jira":
+		if tickets.URL == "" {
+			add("tickets.url", "jira needs the URL of the site, e.g. https://acme.atlassian.net")
+		} else if u, err := url.Parse(tickets.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			add("tickets.url", "invalid URL %q", tickets.URL)
+		}
+		if tickets.User == "" {
+			add("tickets.user", "jira needs the email of the account the token belongs to")
+		}
+	case "linear":
+	default:
+		add("tickets.provider", "unknown provider %q (use jira or linear)", tickets.Provider)
+	}
+	if tickets.Pattern != "" {
+		if _, err := regexp.Compile(tickets.Pattern); err != nil {
+			add("tickets.pattern", "invalid regex: %v", err)
+		}
+	}
+	if tickets.Footer != "" && tickets.Footer != "none" && !strings.Contains(tickets.Footer, "{ticket}") {
+		add("tickets.footer", "footer %q does not contain {ticket}", tickets.Footer)
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTicketsConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Tickets.Enabled() || !cfg.Tickets.GitHubIssues {
+		t.Fatalf("default tickets = %+v, want disabled with GitHub issues looked up", cfg.Tickets)
+	}
+
+	if err := mergeConfigData(cfg, []byte(`{"tickets": {"provider": "jira", "url": "https://acme.atlassian.net", "user": "me@acme.dev"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mergeConfigData(cfg, []byte(`{"tickets": {"transitionTo": "In Review", "githubIssues": false}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Tickets.Provider != "jira" || cfg.Tickets.User != "me@acme.dev" || cfg.Tickets.TransitionTo != "In Review" || cfg.Tickets.GitHubIssues {
+		t.Errorf("tickets = %+v, want jira settings kept, transitionTo added and GitHub issues off", cfg.Tickets)
+	}
+	if issues := Validate(cfg); len(issues) != 0 {
+		t.Errorf("Validate() = %v, want no issues", issues)
+	}
+
+	for branch, want := range map[string]string{
+		"feature/PROJ-123-login": "PROJ-123",
+		"feature/proj-42-login":  "PROJ-42",
+		"main":                   "",
+	} {
+		if got := cfg.Tickets.Ticket(branch); got != want {
+			t.Errorf("Ticket(%q) = %q, want %q", branch, got, want)
+		}
+	}
+	if got := cfg.Tickets.FooterFor("PROJ-123"); got != "Refs: PROJ-123" {
+		t.Errorf("FooterFor() = %q, want the default footer", got)
+	}
+	cfg.Tickets.Footer = "none"
+	if got := cfg.Tickets.FooterFor("PROJ-123"); got != "" {
+		t.Errorf("FooterFor() = %q, want no footer", got)
+	}
+
+	cfg.Tickets = TicketsConfig{Provider: "jira", Footer: "Jira"}
+	keys := map[string]bool{}
+	for _, issue := range Validate(cfg) {
+		keys[issue.Key] = true
+	}
+	for _, key := range []string{"tickets.url", "tickets.user", "tickets.footer"} {
+		if !keys[key] {
+			t.Errorf("Validate() reported no issue for %s", key)
+		}
+	}
+}
+
+func TestLoadConfigIgnoresRepositoryTicketSite(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	global := filepath.Join(home, ".config", "gitmit")
+	if err := os.MkdirAll(global, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	repo := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	writeConfig := func(path, data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig(filepath.Join(global, "config.json"), `{"tickets": {"provider": "jira", "url": "https://acme.atlassian.net", "user": "me@acme.dev"}}`)
+	writeConfig(filepath.Join(repo, ".gitmit.json"), `{"tickets": {"url": "https://evil.example", "user": "me@evil.example", "token": "stolen", "allowRepository": true, "transitionTo": "Done"}}`)
+
+	cfg, err := LoadConfig(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Tickets.URL != "https://acme.atlassian.net" || cfg.Tickets.User != "me@acme.dev" || cfg.Tickets.Token != "" || cfg.Tickets.AllowRepository {
+		t.Errorf("tickets = %+v, want the site and account of the global config", cfg.Tickets)
+	}
+	if cfg.Tickets.TransitionTo != "Done" {
+		t.Errorf("TransitionTo = %q, want the repository's other ticket settings kept", cfg.Tickets.TransitionTo)
+	}
+
+	writeConfig(filepath.Join(global, "config.json"), `{"tickets": {"provider": "jira", "allowRepository": true}}`)
+	if cfg, err = LoadConfig(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Tickets.URL != "https://evil.example" || cfg.Tickets.Token != "stolen" {
+		t.Errorf("tickets = %+v, want the repository's site once the global config allows it", cfg.Tickets)
+	}
+}
@@ -0,0 +1,82 @@
+package config
+
+import "time"
+
+// TimeoutsConfig bounds how long gitmit waits on other programs, as durations
+// such as "30s" or "2m"; "0" waits as long as it takes
+type TimeoutsConfig struct {
+	Git    string `json:"git,omitempty" yaml:"git,omitempty" toml:"git,omitempty"`          // Each git command
+	AI     string `json:"ai,omitempty" yaml:"ai,omitempty" toml:"ai,omitempty"`             // Each request to the AI model
+	Plugin string `json:"plugin,omitempty" yaml:"plugin,omitempty" toml:"plugin,omitempty"` // Each run of a plugin
+	Hook   string `json:"hook,omitempty" yaml:"hook,omitempty" toml:"hook,omitempty"`       // Each run of a hook
+}
+
+// defaultTimeouts leave slow repositories and local models room to answer
+var defaultTimeouts = TimeoutsConfig{
+	Git:    "30s",
+	AI:     "60s",
+	Plugin: "5s",
+	Hook:   "30s",
+}
+
+// GitTimeout returns how long a git command may run, or 0 for no limit
+func (t TimeoutsConfig) GitTimeout() time.Duration {
+	return parseTimeout(t.Git, defaultTimeouts.Git)
+}
+
+// AITimeout returns how long a request to the AI model may take, or 0 for no limit
+func (t TimeoutsConfig) AITimeout() time.Duration {
+	return parseTimeout(t.AI, defaultTimeouts.AI)
+}
+
+// PluginTimeout returns how long a plugin may run, or 0 for no limit
+func (t TimeoutsConfig) PluginTimeout() time.Duration {
+	return parseTimeout(t.Plugin, defaultTimeouts.Plugin)
+}
+
+// HookTimeout returns how long a hook may run, or 0 for no limit
+func (t TimeoutsConfig) HookTimeout() time.Duration {
+	return parseTimeout(t.Hook, defaultTimeouts.Hook)
+}
+
+// parseTimeout parses a timeout, or the fallback when it is not valid
+func parseTimeout(value, fallback string) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		d, _ = time.ParseDuration(fallback)
+	}
+	return d
+}
+
+func mergeTimeouts(cfg *TimeoutsConfig, fileTimeouts TimeoutsConfig) {
+	if fileTimeouts.Git != "" {
+		cfg.Git = fileTimeouts.Git
+	}
+	if fileTimeouts.AI != "" {
+		cfg.AI = fileTimeouts.AI
+	}
+	if fileTimeouts.Plugin != "" {
+		cfg.Plugin = fileTimeouts.Plugin
+	}
+	if fileTimeouts.Hook != "" {
+		cfg.Hook = fileTimeouts.Hook
+	}
+}
+
+// validateTimeouts checks that every timeout is a duration that is not negative
+func validateTimeouts(timeouts TimeoutsConfig, add func(key, format string, args ...interface{})) {
+	for _, timeout := range []struct{ key, value string }{
+		{"timeouts.git", timeouts.Git},
+		{"timeouts.ai", timeouts.AI},
+		{"timeouts.plugin", timeouts.Plugin},
+		{"timeouts.hook", timeouts.Hook},
+	} {
+		d, err := time.ParseDuration(timeout.value)
+		switch {
+		case err != nil:
+			add(timeout.key, "%q is not a duration such as 30s or 2m", timeout.value)
+		case d < 0:
+			add(timeout.key, "timeout %s is negative", timeout.value)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeTimeouts(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Timeouts.GitTimeout() != 30*time.Second || cfg.Timeouts.AITimeout() != time.Minute {
+		t.Fatalf("default timeouts = %+v, want 30s for git and 60s for AI", cfg.Timeouts)
+	}
+
+	if err := mergeConfigData(cfg, []byte(`{"timeouts": {"git": "2m"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Timeouts.GitTimeout() != 2*time.Minute || cfg.Timeouts.AITimeout() != time.Minute {
+		t.Errorf("timeouts = %+v, want git replaced and the AI timeout kept", cfg.Timeouts)
+	}
+
+	cfg.Timeouts.AI = "0"
+	if got := cfg.Timeouts.AITimeout(); got != 0 {
+		t.Errorf("AITimeout() = %s, want 0 for no limit", got)
+	}
+
+	cfg.Timeouts.Git = "soon"
+	if got := cfg.Timeouts.GitTimeout(); got != 30*time.Second {
+		t.Errorf("GitTimeout() = %s, want the default for an invalid timeout", got)
+	}
+	cfg.Timeouts.AI = "-5s"
+	issues := Validate(cfg)
+	if len(issues) != 2 || issues[0].Key != "timeouts.ai" || issues[1].Key != "timeouts.git" {
+		t.Errorf("Validate() = %v, want a negative timeouts.ai and an invalid timeouts.git", issues)
+	}
+}
@@ -0,0 +1,276 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Issue describes a single configuration problem found during validation
+type Issue struct {
+	Source  string // Config file path, or "merged" for checks on the effective config
+	Key     string
+	Message string
+}
+
+func (i Issue) String() string {
+	if i.Key == "" {
+		return fmt.Sprintf("%s: %s", i.Source, i.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.Source, i.Key, i.Message)
+}
+
+// TemplateGroups lists the action groups that templates can be registered under
+var TemplateGroups = []string{"A", "M", "D", "R", "DOC", "TEST", "CI", "BUILD", "STYLE", "MISC", "LICENSE", "SECURITY"}
+
+// TemplatePlaceholders lists the placeholders that templates may reference
+var TemplatePlaceholders = []string{"{topic}", "{item}", "{purpose}", "{source}", "{target}", "{issue}"}
+
+// CommitTypes lists the conventional commit types gitmit knows how to score
+var CommitTypes = []string{"feat", "fix", "refactor", "chore", "docs", "test", "style", "perf", "ci", "build", "security"}
+
+// specialTypes are the commit types of reverts and merges, which are
+// recognized rather than scored
+var specialTypes = []string{"revert", "merge"}
+
+// signalNames lists the signal sources used by normalized scoring
+var signalNames = []string{"branch", "diffStat", "keywords", "patterns"}
+
+// nestedKeys are top-level keys whose content is validated by Validate rather than by KeySpec
+var nestedKeys = map[string]bool{"keywords": true, "templates": true, "paths": true, "languages": true}
+
+// listKeys are top-level keys holding a list of objects, validated by Validate
+var listKeys = map[string]bool{"branchPolicies": true, "fallbacks": true}
+
+var placeholderRegex = regexp.MustCompile(`\{[^{}]*\}`)
+
+// ValidateFile checks a single config file for parse errors, unknown keys and badly typed values
+func ValidateFile(path string) []Issue {
+	raw, err := readRawConfig(path)
+	if err != nil {
+		return []Issue{{Source: path, Message: err.Error()}}
+	}
+
+	var issues []Issue
+	for _, key := range slices.Sorted(maps.Keys(raw)) {
+		if nestedKeys[key] {
+			if _, ok := raw[key].(map[string]interface{}); !ok {
+				issues = append(issues, Issue{Source: path, Key: key, Message: "expected a nested object"})
+			}
+			continue
+		}
+		if listKeys[key] {
+			if _, ok := raw[key].([]interface{}); !ok {
+				issues = append(issues, Issue{Source: path, Key: key, Message: "expected a list"})
+			}
+			continue
+		}
+		issues = append(issues, validateRawValue(path, key, raw[key])...)
+	}
+	return issues
+}
+
+// validateRawValue walks a raw value and checks every leaf against the known key specs
+func validateRawValue(path, key string, value interface{}) []Issue {
+	if sub, ok := value.(map[string]interface{}); ok && !isExactKey(key) {
+		var issues []Issue
+		for _, k := range slices.Sorted(maps.Keys(sub)) {
+			issues = append(issues, validateRawValue(path, key+"."+k, sub[k])...)
+		}
+		return issues
+	}
+
+	spec, ok := LookupKey(key)
+	if !ok {
+		msg := "unknown key"
+		if suggestion := suggestKey(key); suggestion != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+		}
+		return []Issue{{Source: path, Key: key, Message: msg}}
+	}
+	if _, err := ParseValue(spec, FormatValue(value)); err != nil {
+		return []Issue{{Source: path, Key: key, Message: err.Error()}}
+	}
+	return nil
+}
+
+// isExactKey reports whether a key matches a non-wildcard spec
+func isExactKey(key string) bool {
+	for _, spec := range Keys {
+		if spec.Name == key {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestKey finds a known key that differs from the given one only by case
+func suggestKey(key string) string {
+	for _, spec := range Keys {
+		name := strings.TrimSuffix(spec.Name, ".*")
+		if strings.EqualFold(name, key) || strings.HasPrefix(strings.ToLower(key), strings.ToLower(name)+".") {
+			return name
+		}
+	}
+	for name := range nestedKeys {
+		if strings.EqualFold(name, key) {
+			return name
+		}
+	}
+	for name := range listKeys {
+		if strings.EqualFold(name, key) {
+			return name
+		}
+	}
+	return ""
+}
+
+// Validate checks the merged configuration for semantic problems that would
+// silently degrade suggestions
+func Validate(cfg *Config) []Issue {
+	var issues []Issue
+	add := func(key, format string, args ...interface{}) {
+		issues = append(issues, Issue{Source: "merged", Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if cfg.Engine == "ollama" {
+		if u, err := url.Parse(cfg.Ollama.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			add("ollama.url", "%q is not a valid URL (e.g. http://localhost:11434)", cfg.Ollama.URL)
+		}
+		if cfg.Ollama.Model == "" {
+			add("ollama.model", "a model is required when engine is \"ollama\"")
+		}
+	}
+
+	// Mapping keys are matched as plain substrings, so regex syntax never matches
+	for _, mapping := range []struct {
+		key string
+		m   map[string]string
+	}{{"topicMappings", cfg.TopicMappings}, {"keywordMappings", cfg.KeywordMappings}} {
+		for _, pattern := range slices.Sorted(maps.Keys(mapping.m)) {
+			key := mapping.key + "." + pattern
+			if strings.TrimSpace(pattern) == "" {
+				add(mapping.key, "empty pattern never matches meaningfully")
+				continue
+			}
+			if strings.ContainsAny(pattern, "*^$[]()|\\") {
+				if _, err := regexp.Compile(pattern); err != nil {
+					add(key, "invalid pattern: %v; patterns are plain substrings, not regexes", err)
+				} else {
+					add(key, "pattern looks like a regex, but mappings match plain substrings")
+				}
+			}
+			if strings.TrimSpace(mapping.m[pattern]) == "" {
+				add(key, "mapped value is empty")
+			}
+		}
+	}
+
+	validateBranchPolicies(cfg.BranchPolicies, add)
+	validateFallbacks(cfg.Fallbacks, add)
+	validatePaths(cfg, add)
+	validateLanguages(cfg, add)
+	validateRules(cfg.Rules, add)
+	validateSpellcheck(cfg.Spellcheck, add)
+	validateSafety(cfg.Safety, add)
+	validateTests(cfg.Tests, add)
+	validateRisk(cfg.Risk, add)
+	validateTheme(cfg.Theme, add)
+	validateTimeouts(cfg.Timeouts, add)
+	validateHistory(cfg.History, add)
+	validatePlugins(cfg.Plugins, add)
+	validateChangelog(cfg.Changelog, add)
+	validateAnalyze(cfg.Analyze, add)
+	validateTickets(cfg.Tickets, add)
+
+	for _, alias := range slices.Sorted(maps.Keys(cfg.ScopeAliases)) {
+		if scope := cfg.ScopeAliases[alias]; strings.TrimSpace(scope) == "" || strings.ContainsAny(scope, "()\n") {
+			add("scopeAliases."+alias, "invalid scope %q", scope)
+		}
+	}
+
+	for _, scope := range cfg.Scopes {
+		if strings.TrimSpace(scope) == "" || strings.ContainsAny(scope, "()\n") {
+			add("scopes", "invalid scope %q", scope)
+		}
+	}
+
+	for _, commitType := range cfg.Types {
+		if !containsString(CommitTypes, commitType) && !containsString(specialTypes, commitType) {
+			add("types", "unknown commit type %q (expected one of %s)", commitType, strings.Join(append(append([]string(nil), CommitTypes...), specialTypes...), ", "))
+		}
+	}
+
+	total := 0.0
+	for _, name := range slices.Sorted(maps.Keys(cfg.SignalWeights)) {
+		if !containsString(signalNames, name) {
+			add("signalWeights."+name, "unknown signal (expected one of %s)", strings.Join(signalNames, ", "))
+		}
+		total += cfg.SignalWeights[name]
+	}
+	if cfg.NormalizeScoring && total <= 0 {
+		add("signalWeights", "weights must add up to more than 0 when normalizeScoring is enabled")
+	}
+
+	for action, keywords := range cfg.Keywords {
+		if !containsString(CommitTypes, action) {
+			add("keywords."+action, "unknown commit type (expected one of %s)", strings.Join(CommitTypes, ", "))
+		}
+		for keyword, weight := range keywords {
+			if strings.TrimSpace(keyword) == "" {
+				add("keywords."+action, "empty keyword matches every diff")
+			}
+			if weight == 0 {
+				add("keywords."+action+"."+keyword, "weight 0 has no effect")
+			}
+		}
+	}
+
+	for group, topics := range cfg.Templates {
+		if !containsString(TemplateGroups, group) {
+			add("templates."+group, "unknown template action (expected one of %s)", strings.Join(TemplateGroups, ", "))
+		}
+		for topic, list := range topics {
+			key := "templates." + group + "." + topic
+			if len(list) == 0 {
+				add(key, "template list is empty")
+			}
+			for _, tmpl := range list {
+				if tmpl == InheritTemplates {
+					continue
+				}
+				if strings.TrimSpace(tmpl) == "" {
+					add(key, "template is empty")
+					continue
+				}
+				if strings.Count(tmpl, "{") != strings.Count(tmpl, "}") {
+					add(key, "mismatched placeholder braces in %q", tmpl)
+				}
+				for _, placeholder := range placeholderRegex.FindAllString(tmpl, -1) {
+					if !containsString(TemplatePlaceholders, placeholder) {
+						add(key, "unknown placeholder %s (expected one of %s)", placeholder, strings.Join(TemplatePlaceholders, ", "))
+					}
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Key < issues[j].Key
+	})
+	return issues
+}
+
+// containsString checks if a slice contains a string
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".gitmit.json")
+	data := `{"engine": "gpt", "maxsubjectlength": 40, "maxBodyLength": 72, "topicMappings": {"cmd": "cli"}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := ValidateFile(path)
+	if len(issues) != 2 {
+		t.Fatalf("ValidateFile() returned %d issues, want 2: %v", len(issues), issues)
+	}
+	if issues[0].Key != "engine" || !strings.Contains(issues[0].Message, "allowed") {
+		t.Errorf("unexpected issue for engine: %v", issues[0])
+	}
+	if issues[1].Key != "maxsubjectlength" || !strings.Contains(issues[1].Message, "maxSubjectLength") {
+		t.Errorf("expected suggestion for maxsubjectlength, got %v", issues[1])
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := DefaultConfig()
+	if issues := Validate(cfg); len(issues) != 0 {
+		t.Fatalf("Validate(DefaultConfig()) = %v, want no issues", issues)
+	}
+
+	cfg.TopicMappings["^internal/.*"] = "api"
+	cfg.Keywords["feature"] = map[string]int{"new": 2}
+	cfg.Templates["A"] = map[string]TemplateList{"api": {"feat(api): add {thing}"}}
+	cfg.Templates["ADD"] = map[string]TemplateList{"_default": {"feat: add {item}"}}
+	cfg.Types = []string{"feat", "revert", "feature"}
+
+	want := []string{"keywords.feature", "templates.A.api", "templates.ADD", "topicMappings.^internal/.*", "types"}
+	issues := Validate(cfg)
+	if len(issues) != len(want) {
+		t.Fatalf("Validate() returned %d issues, want %d: %v", len(issues), len(want), issues)
+	}
+	for i, key := range want {
+		if issues[i].Key != key {
+			t.Errorf("issue[%d].Key = %q, want %q", i, issues[i].Key, key)
+		}
+	}
+}
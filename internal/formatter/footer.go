@@ -0,0 +1,80 @@
+package formatter
+
+import (
+	"regexp"
+	"strings"
+)
+
+// footerRegex matches a git trailer style footer line: "Token: value" or "Token #value"
+var footerRegex = regexp.MustCompile(`^(BREAKING[ -]CHANGES?|[A-Za-z][A-Za-z0-9-]*(?: by)?)(: | #)(.*)$`)
+
+// footerKeys maps lowercased footer keys to their canonical spelling
+var footerKeys = map[string]string{
+	"close":            "Closes",
+	"closes":           "Closes",
+	"closed":           "Closes",
+	"fix":              "Fixes",
+	"fixes":            "Fixes",
+	"fixed":            "Fixes",
+	"resolve":          "Resolves",
+	"resolves":         "Resolves",
+	"resolved":         "Resolves",
+	"ref":              "Refs",
+	"refs":             "Refs",
+	"references":       "Refs",
+	"see":              "See",
+	"breaking change":  "BREAKING CHANGE",
+	"breaking-change":  "BREAKING CHANGE",
+	"breaking changes": "BREAKING CHANGE",
+	"breaking-changes": "BREAKING CHANGE",
+	"co-authored-by":   "Co-authored-by",
+	"co-authored by":   "Co-authored-by",
+	"coauthored-by":    "Co-authored-by",
+	"signed-off-by":    "Signed-off-by",
+	"reviewed-by":      "Reviewed-by",
+	"owners":           "Owners",
+}
+
+// splitFooters separates the trailing footer paragraph of a body from its text.
+// The last paragraph counts as footers when every line is a footer or an indented
+// continuation of one.
+func splitFooters(body string) (string, []string) {
+	paragraphs := strings.Split(body, "\n\n")
+	last := strings.TrimSpace(paragraphs[len(paragraphs)-1])
+	if last == "" {
+		return body, nil
+	}
+
+	var footers []string
+	for _, line := range strings.Split(last, "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(footers) > 0 {
+			footers[len(footers)-1] += "\n" + line
+			continue
+		}
+		if !footerRegex.MatchString(strings.TrimSpace(line)) {
+			return body, nil
+		}
+		footers = append(footers, normalizeFooter(strings.TrimSpace(line)))
+	}
+
+	text := strings.TrimSpace(strings.Join(paragraphs[:len(paragraphs)-1], "\n\n"))
+	return text, footers
+}
+
+// normalizeFooter rewrites a footer key to its canonical spelling, e.g. "closes #1" -> "Closes #1"
+func normalizeFooter(line string) string {
+	m := footerRegex.FindStringSubmatch(line)
+	if m == nil {
+		return line
+	}
+	key := m[1]
+	if canonical, ok := footerKeys[strings.ToLower(key)]; ok {
+		key = canonical
+	}
+	separator := m[2]
+	if key == "BREAKING CHANGE" {
+		// Only the colon form is valid for breaking changes
+		separator = ": "
+	}
+	return key + separator + m[3]
+}
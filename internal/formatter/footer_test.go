@@ -0,0 +1,56 @@
+package formatter
+
+import "testing"
+
+func TestFormatMessageFooters(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      string
+		footer   string
+		expected string
+	}{
+		{
+			name:     "footer keys are normalized",
+			msg:      "fix: handle nil config\n\nThe loader crashed when the file was empty.\n\ncloses #12\nco-authored-by: Jane <jane@example.com>\nbreaking-change: config is required",
+			expected: "fix: handle nil config\n\nThe loader crashed when the file was\nempty.\n\nCloses #12\nCo-authored-by: Jane <jane@example.com>\nBREAKING CHANGE: config is required",
+		},
+		{
+			name:     "footers without body",
+			msg:      "fix: handle nil config\nRefs #7",
+			expected: "fix: handle nil config\n\nRefs #7",
+		},
+		{
+			name:     "long footers are not wrapped",
+			msg:      "feat: add export\n\nCo-authored-by: Someone With A Very Long Name <someone.with.a.long.name@example.com>",
+			expected: "feat: add export\n\nCo-authored-by: Someone With A Very Long Name <someone.with.a.long.name@example.com>",
+		},
+		{
+			name:     "extra footer joins the footer paragraph",
+			msg:      "feat: add export\n\n\n\nExports reports as CSV.\n\nCloses #3",
+			footer:   "Owners: @acme/reports",
+			expected: "feat: add export\n\nExports reports as CSV.\n\nCloses #3\nOwners: @acme/reports",
+		},
+		{
+			name:     "extra footer is not repeated",
+			msg:      "feat: add export\n\nOwners: @acme/reports",
+			footer:   "Owners: @acme/reports",
+			expected: "feat: add export\n\nOwners: @acme/reports",
+		},
+		{
+			name:     "extra footer lines are added one by one",
+			msg:      "feat: add export\n\nOwners: @acme/reports",
+			footer:   "Owners: @acme/reports\nNote: no tests updated",
+			expected: "feat: add export\n\nOwners: @acme/reports\nNote: no tests updated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFormatter(50, 40)
+			f.Footer = tt.footer
+			if got := f.FormatMessage(tt.msg, false); got != tt.expected {
+				t.Errorf("FormatMessage() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
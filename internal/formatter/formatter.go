@@ -4,12 +4,28 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/style"
 )
 
+// blankLinesRegex matches a line break followed by one or more blank lines
+var blankLinesRegex = regexp.MustCompile(`\n([ \t]*\n)+`)
+
 // Formatter is responsible for applying final formatting to commit messages
 type Formatter struct {
 	MaxSubjectLength int
 	MaxBodyLength    int
+	ShortenSubject   bool                               // Shorten long subjects instead of wrapping the overflow into the body
+	Imperative       bool                               // Normalize the leading verb of the subject to imperative mood
+	ScopeAliases     map[string]string                  // Optional raw scope -> canonical scope translations
+	Style            *style.Style                       // Optional repository style the subject is adapted to
+	TicketPrefix     string                             // Optional prefix such as "[ABC-123] " required by a branch policy
+	Footer           string                             // Optional last body lines, one per line, such as a code owner mention
+	Rules            *config.RulesConfig                // Optional rules checked by Check and applied when AutoFix is set
+	Checks           []func(message string) []Violation // Optional extra rules checked by Check, such as those of plugins
+	Template         *CommitTemplate                    // Optional commit.template whose sections are added to messages lacking them
 }
 
 // NewFormatter creates a new Formatter
@@ -33,6 +49,8 @@ func (f *Formatter) FormatMessage(msg string, isMajor bool) string {
 	if len(parts) > 1 {
 		body = strings.TrimLeft(parts[1], "\n\r")
 		body = strings.TrimRight(body, "\n\r\t ")
+		// Collapse runs of blank lines so sections are separated by exactly one
+		body = blankLinesRegex.ReplaceAllString(body, "\n\n")
 	}
 
 	// Remove redundant phrases from subject
@@ -40,13 +58,39 @@ func (f *Formatter) FormatMessage(msg string, isMajor bool) string {
 	subject = strings.ReplaceAll(subject, "feat feat", "feat")
 	subject = strings.ReplaceAll(subject, "fix fix", "fix")
 
+	// Translate raw scopes such as directory names to the team's vocabulary
+	subject = style.AliasScope(subject, f.ScopeAliases)
+
+	// Normalize "added" / "fixes" / "updating" to the conventional imperative
+	// mood first, so the learned style and rules start from the same baseline
+	if f.Imperative {
+		prefix, description := f.splitSubject(subject)
+		subject = prefix + style.Imperative(description)
+	}
+
+	// Match the conventions of the repository's commit log
+	if f.Style != nil {
+		subject = f.Style.Apply(subject)
+	}
+
+	if f.TicketPrefix != "" && !strings.HasPrefix(subject, f.TicketPrefix) {
+		subject = f.TicketPrefix + subject
+	}
+
+	// Configured rules take precedence over the learned style
+	if f.Rules != nil && f.Rules.AutoFix {
+		subject = f.fixSubject(subject)
+	}
+
 	// Add optional suffixes to subject
 	if isMajor {
 		subject = fmt.Sprintf("%s (massive refactor)", subject)
 	}
 
-	// Wrap subject if too long
-	if f.MaxSubjectLength > 0 && len(subject) > f.MaxSubjectLength {
+	// Shorten the subject if too long, or wrap the overflow into the body
+	if f.MaxSubjectLength > 0 && f.ShortenSubject {
+		subject = f.shortenSubject(subject, f.MaxSubjectLength)
+	} else if f.MaxSubjectLength > 0 && runeLen(subject) > f.MaxSubjectLength {
 		wrapped := f.wrapString(subject, f.MaxSubjectLength)
 		subjectParts := strings.SplitN(wrapped, "\n", 2)
 		subject = subjectParts[0]
@@ -60,15 +104,35 @@ func (f *Formatter) FormatMessage(msg string, isMajor bool) string {
 		}
 	}
 
+	// Footers are kept one per line in their own paragraph, after the body text
+	body, footers := splitFooters(body)
+
+	// Compare without line breaks, since a footer may already have been wrapped
+	for _, footer := range strings.Split(f.Footer, "\n") {
+		if footer != "" && !strings.Contains(strings.Join(strings.Fields(body+" "+strings.Join(footers, " ")), " "), footer) {
+			footers = append(footers, footer)
+		}
+	}
+
 	// Wrap body if exists
 	if body != "" && f.MaxBodyLength > 0 {
 		body = f.wrapString(body, f.MaxBodyLength)
 	}
 
+	// Sections the repository's commit.template asks for are added last, so
+	// wrapping cannot join their headings
+	if f.Template != nil {
+		body = f.Template.complete(subject, body, footers)
+	}
+
+	sections := []string{subject}
 	if body != "" {
-		return subject + "\n\n" + body
+		sections = append(sections, body)
+	}
+	if len(footers) > 0 {
+		sections = append(sections, strings.Join(footers, "\n"))
 	}
-	return subject
+	return strings.Join(sections, "\n\n")
 }
 
 // wrapString wraps a string at the specified limit, preserving paragraphs and structures
@@ -166,7 +230,7 @@ func (f *Formatter) reflow(s string, limit int) string {
 	currLen := 0
 	for i, w := range words {
 		if i > 0 {
-			if currLen+1+len(w) > limit {
+			if currLen+1+runeLen(w) > limit {
 				res.WriteString("\n")
 				currLen = 0
 			} else {
@@ -175,14 +239,14 @@ func (f *Formatter) reflow(s string, limit int) string {
 			}
 		}
 		res.WriteString(w)
-		currLen += len(w)
+		currLen += runeLen(w)
 	}
 	return res.String()
 }
 
 // wrapLine wraps a single structural line, attempting to preserve indentation
 func (f *Formatter) wrapLine(line string, limit int) string {
-	if len(line) <= limit {
+	if runeLen(line) <= limit {
 		return line
 	}
 
@@ -223,18 +287,18 @@ func (f *Formatter) wrapLine(line string, limit int) string {
 	var res strings.Builder
 	res.WriteString(indent)
 	res.WriteString(prefix)
-	currLen := len(indent) + len(prefix)
+	currLen := runeLen(indent) + runeLen(prefix)
 
 	for i, w := range words {
 		if i > 0 {
-			if currLen+1+len(w) > limit {
+			if currLen+1+runeLen(w) > limit {
 				res.WriteString("\n")
 				res.WriteString(indent)
 				// Extra indentation for wrapped list items
 				if prefix != "" {
 					res.WriteString("  ")
 				}
-				currLen = len(indent)
+				currLen = runeLen(indent)
 				if prefix != "" {
 					currLen += 2
 				}
@@ -244,8 +308,13 @@ func (f *Formatter) wrapLine(line string, limit int) string {
 			}
 		}
 		res.WriteString(w)
-		currLen += len(w)
+		currLen += runeLen(w)
 	}
 
 	return res.String()
 }
+
+// runeLen returns the length of a string in characters rather than bytes
+func runeLen(s string) int {
+	return utf8.RuneCountInString(s)
+}
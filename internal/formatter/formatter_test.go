@@ -87,3 +87,19 @@ func TestFormatMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatMessageImperative(t *testing.T) {
+	f := NewFormatter(72, 72)
+	f.Imperative = true
+
+	tests := map[string]string{
+		"feat(api): added user endpoint": "feat(api): add user endpoint",
+		"✨ fix: fixes nil pointer":       "✨ fix: fix nil pointer",
+		"docs: updating install steps":   "docs: update install steps",
+	}
+	for input, want := range tests {
+		if got := f.FormatMessage(input, false); got != want {
+			t.Errorf("FormatMessage(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package formatter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/style"
+)
+
+// Violation is a commit rule broken by a message
+type Violation struct {
+	Rule    string // Config key of the rule, e.g. noTrailingPeriod
+	Message string
+	Fixable bool // Fix can correct the message
+}
+
+// String formats the violation for display
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Rule, v.Message)
+}
+
+// Check returns the configured rules and extra checks a message violates
+func (f *Formatter) Check(message string) []Violation {
+	if message == "" {
+		return nil
+	}
+	violations := f.checkRules(message)
+	for _, check := range f.Checks {
+		violations = append(violations, check(message)...)
+	}
+	return violations
+}
+
+// checkRules returns the configured rules a message violates
+func (f *Formatter) checkRules(message string) []Violation {
+	if f.Rules == nil {
+		return nil
+	}
+
+	var violations []Violation
+	subject := strings.SplitN(message, "\n", 2)[0]
+	_, description := f.splitSubject(subject)
+
+	if f.Rules.Imperative && style.Imperative(description) != description {
+		violations = append(violations, Violation{"imperative", fmt.Sprintf("start with an imperative verb (%q, not %q)", firstWordOf(style.Imperative(description)), firstWordOf(description)), true})
+	}
+	if f.Rules.Lowercase && lowercaseFirst(description) != description {
+		violations = append(violations, Violation{"lowercase", "start the description with a lowercase letter", true})
+	}
+	if f.Rules.NoTrailingPeriod && strings.HasSuffix(subject, ".") {
+		violations = append(violations, Violation{"noTrailingPeriod", "remove the period at the end of the subject", true})
+	}
+	for _, word := range f.Rules.BlockedWords {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		if regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(word) + `\b`).MatchString(message) {
+			violations = append(violations, Violation{"blockedWords", fmt.Sprintf("remove the blocked word %q", word), false})
+		}
+	}
+	if f.Rules.TicketPattern != "" && (f.TicketPrefix == "" || !strings.HasPrefix(subject, f.TicketPrefix)) {
+		if re := f.ticketRegex(); re != nil && !re.MatchString(subject) {
+			violations = append(violations, Violation{"ticketPattern", fmt.Sprintf("start the subject with a ticket matching %s", f.Rules.TicketPattern), false})
+		}
+	}
+	return violations
+}
+
+// Fix corrects the violations of the configured rules that can be fixed
+// automatically: mood, case and trailing period of the subject
+func (f *Formatter) Fix(message string) string {
+	parts := strings.SplitN(message, "\n", 2)
+	parts[0] = f.fixSubject(parts[0])
+	return strings.Join(parts, "\n")
+}
+
+// fixSubject applies the fixable rules to a subject line
+func (f *Formatter) fixSubject(subject string) string {
+	if f.Rules == nil {
+		return subject
+	}
+	prefix, description := f.splitSubject(subject)
+	if f.Rules.Imperative {
+		description = style.Imperative(description)
+	}
+	if f.Rules.Lowercase {
+		description = lowercaseFirst(description)
+	}
+	if f.Rules.NoTrailingPeriod {
+		description = strings.TrimRight(description, ".")
+	}
+	return prefix + description
+}
+
+// splitSubject separates the ticket, emoji and type prefix of a subject from its description
+func (f *Formatter) splitSubject(subject string) (string, string) {
+	ticket := ""
+	if f.TicketPrefix != "" && strings.HasPrefix(subject, f.TicketPrefix) {
+		ticket = f.TicketPrefix
+	} else if re := f.ticketRegex(); re != nil {
+		ticket = re.FindString(subject)
+	}
+	prefix, description := style.SplitSubject(subject[len(ticket):])
+	return ticket + prefix, description
+}
+
+// ticketRegex matches the required ticket, in the prefix format tickets are
+// added in, and following spaces at the start of a subject, or is nil when no
+// valid ticket pattern is configured
+func (f *Formatter) ticketRegex() *regexp.Regexp {
+	if f.Rules == nil || f.Rules.TicketPattern == "" {
+		return nil
+	}
+	re, err := config.TicketPrefixRegex("", f.Rules.TicketPattern)
+	if err != nil {
+		return nil
+	}
+	return re
+}
+
+// lowercaseFirst lowercases the first letter of a description, keeping acronyms such as API intact
+func lowercaseFirst(description string) string {
+	r, size := utf8.DecodeRuneInString(description)
+	if r == utf8.RuneError || !unicode.IsUpper(r) {
+		return description
+	}
+	if next, _ := utf8.DecodeRuneInString(description[size:]); unicode.IsUpper(next) {
+		return description
+	}
+	return string(unicode.ToLower(r)) + description[size:]
+}
+
+// firstWordOf returns the first word of a description
+func firstWordOf(description string) string {
+	if fields := strings.Fields(description); len(fields) > 0 {
+		return fields[0]
+	}
+	return description
+}
@@ -0,0 +1,101 @@
+package formatter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/config"
+)
+
+func TestCheck(t *testing.T) {
+	f := NewFormatter(72, 72)
+	f.Rules = &config.RulesConfig{
+		Imperative:       true,
+		Lowercase:        true,
+		NoTrailingPeriod: true,
+		BlockedWords:     []string{"wip"},
+		TicketPattern:    `[A-Z]+-[0-9]+`,
+	}
+
+	tests := []struct {
+		message string
+		rules   []string
+	}{
+		{"[ABC-12] feat(api): add endpoint", nil},
+		{"[ABC-12] feat(api): Added endpoint.", []string{"imperative", "lowercase", "noTrailingPeriod"}},
+		{"fix: handle API errors\n\nWIP, needs tests", []string{"blockedWords", "ticketPattern"}},
+		{"[ABC-12] ✨ feat: API client for users", nil},
+		{"ABC-12 feat(api): add endpoint", []string{"ticketPattern"}},
+	}
+
+	for _, tt := range tests {
+		var rules []string
+		for _, v := range f.Check(tt.message) {
+			rules = append(rules, v.Rule)
+		}
+		if !reflect.DeepEqual(rules, tt.rules) {
+			t.Errorf("Check(%q) = %v, want %v", tt.message, rules, tt.rules)
+		}
+	}
+}
+
+func TestCheckTicketPrefix(t *testing.T) {
+	// A branch policy prefixes the ticket, in its own format, that rules.requireTicket asks for
+	f := NewFormatter(72, 72)
+	f.Rules = &config.RulesConfig{TicketPattern: `[A-Z]+-[0-9]+`, RequireTicket: true}
+	tests := []struct {
+		prefix  string
+		message string
+		rules   []string
+	}{
+		{"[PAY-42] ", "[PAY-42] feat(auth): add login", nil},
+		{"PAY-42: ", "PAY-42: feat(auth): add login", nil},
+		{"[PAY-42] ", "feat(auth): add login", []string{"ticketPattern"}},
+	}
+	for _, tt := range tests {
+		f.TicketPrefix = tt.prefix
+		var rules []string
+		for _, v := range f.Check(tt.message) {
+			rules = append(rules, v.Rule)
+		}
+		if !reflect.DeepEqual(rules, tt.rules) {
+			t.Errorf("Check(%q) with prefix %q = %v, want %v", tt.message, tt.prefix, rules, tt.rules)
+		}
+		if got := f.FormatMessage(tt.message, false); len(f.Check(got)) != 0 {
+			t.Errorf("Check(FormatMessage(%q)) = %v, want the formatted message accepted", tt.message, f.Check(got))
+		}
+	}
+}
+
+func TestCheckExtraChecks(t *testing.T) {
+	f := NewFormatter(72, 72)
+	f.Checks = []func(string) []Violation{func(message string) []Violation {
+		return []Violation{{Rule: "plugin/length", Message: "too short"}}
+	}}
+	if got := f.Check("fix: typo"); len(got) != 1 || got[0].Rule != "plugin/length" {
+		t.Errorf("Check() = %v, want the extra check without configured rules", got)
+	}
+	if got := f.Check(""); got != nil {
+		t.Errorf("Check() of an empty message = %v, want none", got)
+	}
+}
+
+func TestFormatMessageAutoFix(t *testing.T) {
+	f := NewFormatter(72, 72)
+	f.TicketPrefix = "[ABC-12] "
+	f.Rules = &config.RulesConfig{Imperative: true, Lowercase: true, NoTrailingPeriod: true, AutoFix: true}
+
+	got := f.FormatMessage("feat(api): Added endpoint.\n\nDetails.", false)
+	want := "[ABC-12] feat(api): add endpoint\n\nDetails."
+	if got != want {
+		t.Errorf("FormatMessage() = %q, want %q", got, want)
+	}
+
+	f.Rules.AutoFix = false
+	if got := f.FormatMessage("feat: Added endpoint.", false); got != "[ABC-12] feat: Added endpoint." {
+		t.Errorf("FormatMessage() without AutoFix = %q, want the message unchanged", got)
+	}
+	if got := f.Fix("[ABC-12] feat: Added endpoint."); got != "[ABC-12] feat: add endpoint" {
+		t.Errorf("Fix() = %q", got)
+	}
+}
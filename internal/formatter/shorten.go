@@ -0,0 +1,79 @@
+package formatter
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ellipsis marks a subject that had to be truncated
+const ellipsis = "…"
+
+// purposeConnectors start trailing phrases that can be dropped to shorten a subject,
+// e.g. "add retry to client for flaky networks" -> "add retry to client"
+var purposeConnectors = []string{" for ", " to ", " so that ", " when ", " because ", " with ", " by ", " via ", " in ", " on "}
+
+// scopeRegex matches the scope of a conventional prefix such as "feat(api)!: "
+var scopeRegex = regexp.MustCompile(`\([^)]*\)(!?: )$`)
+
+// shortenSubject fits a subject into limit characters. It prefers dropping a
+// trailing purpose phrase, then the scope, and only then truncates the
+// description at a word boundary with an ellipsis.
+func (f *Formatter) shortenSubject(subject string, limit int) string {
+	if runeLen(subject) <= limit {
+		return subject
+	}
+
+	prefix, description := f.splitSubject(subject)
+	for _, p := range []string{prefix, scopeRegex.ReplaceAllString(prefix, "$1")} {
+		if runeLen(p+description) <= limit {
+			return p + description
+		}
+		if shortened := dropPurpose(description, limit-runeLen(p)); shortened != "" {
+			return p + shortened
+		}
+	}
+
+	prefix = scopeRegex.ReplaceAllString(prefix, "$1")
+	if runeLen(prefix) >= limit {
+		prefix, description = "", subject
+	}
+	return prefix + truncate(description, limit-runeLen(prefix))
+}
+
+// dropPurpose cuts the description before the connector that leaves the longest
+// text of at most limit characters, keeping at least a verb and an object
+func dropPurpose(description string, limit int) string {
+	best := ""
+	for _, connector := range purposeConnectors {
+		for offset := 0; ; {
+			i := strings.Index(description[offset:], connector)
+			if i < 0 {
+				break
+			}
+			candidate := description[:offset+i]
+			if strings.Contains(candidate, " ") && runeLen(candidate) <= limit && len(candidate) > len(best) {
+				best = candidate
+			}
+			offset += i + 1
+		}
+	}
+	return best
+}
+
+// truncate shortens text to at most limit characters, cutting at a word boundary
+// when possible and ending with an ellipsis
+func truncate(text string, limit int) string {
+	if runeLen(text) <= limit {
+		return text
+	}
+	if limit <= 0 {
+		return ""
+	}
+
+	runes := []rune(text)
+	cut := string(runes[:limit-1])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,;:-") + ellipsis
+}
@@ -0,0 +1,35 @@
+package formatter
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestShortenSubject(t *testing.T) {
+	tests := []struct {
+		name    string
+		subject string
+		limit   int
+		want    string
+	}{
+		{"fits", "feat(api): add endpoint", 50, "feat(api): add endpoint"},
+		{"drops purpose phrase", "feat(api): add retry to client for flaky networks", 40, "feat(api): add retry to client"},
+		{"drops scope", "feat(analyzer): implement dependency scanner", 40, "feat: implement dependency scanner"},
+		{"drops scope and purpose", "feat(analyzer): add scanner for dependency manifests", 25, "feat: add scanner"},
+		{"truncates at word boundary", "fix: handle unexpectedly long configuration values", 30, "fix: handle unexpectedly…"},
+		{"keeps multi-byte runes intact", "docs: übersetze Änderungsprotokoll vollständig", 30, "docs: übersetze…"},
+	}
+
+	f := NewFormatter(0, 0)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := f.shortenSubject(tt.subject, tt.limit)
+			if got != tt.want {
+				t.Errorf("shortenSubject(%q, %d) = %q, want %q", tt.subject, tt.limit, got, tt.want)
+			}
+			if !utf8.ValidString(got) || utf8.RuneCountInString(got) > tt.limit {
+				t.Errorf("shortenSubject(%q, %d) = %q is invalid or too long", tt.subject, tt.limit, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package formatter
+
+import (
+	"regexp"
+	"strings"
+)
+
+// sectionRegex matches a section heading of a commit template, such as "Why:"
+// or "Testing: <how it was tested>"; the placeholder after the colon is not
+// part of the heading
+var sectionRegex = regexp.MustCompile(`^([A-Z][A-Za-z0-9 /-]{0,29}):(?:\s.*)?$`)
+
+// CommitTemplate is the structure of git's commit.template, whose sections
+// messages are completed with
+type CommitTemplate struct {
+	Sections []string // Headings of the template, such as "Why", in its order
+}
+
+// ParseCommitTemplate finds the section headings of a commit template, leaving
+// out its comment lines, which start with commentChar, and trailers. It
+// returns nil when the template has no sections, such as one holding only
+// guidance in comments.
+func ParseCommitTemplate(content, commentChar string) *CommitTemplate {
+	t := &CommitTemplate{}
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if commentChar != "" && strings.HasPrefix(line, commentChar) {
+			continue
+		}
+		m := sectionRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil || seen[strings.ToLower(m[1])] || isTrailer(m[1]) {
+			continue
+		}
+		seen[strings.ToLower(m[1])] = true
+		t.Sections = append(t.Sections, m[1])
+	}
+	if len(t.Sections) == 0 {
+		return nil
+	}
+	return t
+}
+
+// complete adds the sections of the template a message lacks to the end of its
+// body, each heading in its own paragraph so wrapping keeps it on its own line
+func (t *CommitTemplate) complete(subject, body string, footers []string) string {
+	message := strings.Join(append([]string{subject, body}, footers...), "\n")
+	for _, section := range t.Sections {
+		if hasSection(message, section) {
+			continue
+		}
+		if body != "" {
+			body += "\n\n"
+		}
+		body += section + ":"
+	}
+	return body
+}
+
+// hasSection reports whether a message has the heading, anywhere in a line
+// since wrapping may have joined it to the text before
+func hasSection(message, section string) bool {
+	return regexp.MustCompile(`(?i)(^|\W)` + regexp.QuoteMeta(section) + `:`).MatchString(message)
+}
+
+// isTrailer reports whether a heading is a trailer such as "Signed-off-by" or
+// "Refs", filled per commit rather than part of the structure
+func isTrailer(heading string) bool {
+	lower := strings.ToLower(heading)
+	_, known := footerKeys[lower]
+	return known || strings.HasSuffix(lower, "-by")
+}
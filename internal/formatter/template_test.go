@@ -0,0 +1,45 @@
+package formatter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCommitTemplate(t *testing.T) {
+	content := `# <type>(<scope>): <subject>
+
+Why: <what problem does this solve>
+
+; Testing: commented out with core.commentChar
+How to test:
+Signed-off-by:
+Refs: <ticket>
+`
+	template := ParseCommitTemplate(content, ";")
+	if template == nil {
+		t.Fatal("ParseCommitTemplate() = nil, want sections")
+	}
+	// "#" is not the comment character here, but its line is no heading either
+	if want := []string{"Why", "How to test"}; !reflect.DeepEqual(template.Sections, want) {
+		t.Errorf("Sections = %v, want %v without comments and trailers", template.Sections, want)
+	}
+
+	if got := ParseCommitTemplate("# Explain why, not what.\n\n# Wrap at 72 characters.\n", "#"); got != nil {
+		t.Errorf("ParseCommitTemplate() of guidance only = %+v, want nil", got)
+	}
+}
+
+func TestFormatMessageCommitTemplate(t *testing.T) {
+	f := NewFormatter(50, 72)
+	f.Footer = "Refs: ABC-1"
+	f.Template = &CommitTemplate{Sections: []string{"Why", "How to test"}}
+
+	got := f.FormatMessage("feat: add export\n\nAdds a CSV button.\nwhy: customers asked for it", false)
+	want := "feat: add export\n\nAdds a CSV button. why: customers asked for it\n\nHow to test:\n\nRefs: ABC-1"
+	if got != want {
+		t.Errorf("FormatMessage() = %q, want %q", got, want)
+	}
+	if again := f.FormatMessage(got, false); again != got {
+		t.Errorf("FormatMessage() of its own output = %q, want it unchanged", again)
+	}
+}
@@ -0,0 +1,92 @@
+package gitcmd
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// maxStderr bounds how much of what git writes to stderr an Error keeps. The
+// end is kept, since git explains a failure last.
+const maxStderr = 8 * 1024
+
+// maxStderrLines bounds the lines of stderr an Error message shows
+const maxStderrLines = 10
+
+// ErrNotARepo is the error of git run outside a repository; errors.Is
+// matches it against an *Error that says so
+var ErrNotARepo = errors.New("not a git repository")
+
+// Error is a git command that failed, with what git wrote to stderr, such as
+// a hook rejecting a commit, a missing identity or a locked index
+type Error struct {
+	Args   []string // Arguments of git, the subcommand first
+	Stderr string   // What git wrote to stderr, trimmed
+	Err    error    // Error of the command, usually an *exec.ExitError
+}
+
+// Error returns git's own explanation of the failure when it gave one
+func (e *Error) Error() string {
+	detail := e.Stderr
+	if detail == "" {
+		detail = e.Err.Error()
+	} else if lines := strings.Split(detail, "\n"); len(lines) > maxStderrLines {
+		detail = "...\n" + strings.Join(lines[len(lines)-maxStderrLines:], "\n")
+	}
+	return fmt.Sprintf("git %s: %s", e.Subcommand(), detail)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether git failed for the reason of target, such as ErrNotARepo
+func (e *Error) Is(target error) bool {
+	return target == ErrNotARepo && strings.Contains(e.Stderr, "not a git repository")
+}
+
+// Subcommand returns the git subcommand that failed, such as "commit"
+func (e *Error) Subcommand() string {
+	if len(e.Args) == 0 {
+		return ""
+	}
+	return e.Args[0]
+}
+
+// ExitCode returns the exit status of git, or -1 when it did not exit, for
+// example because it could not be started
+func (e *Error) ExitCode() int {
+	var exitErr *exec.ExitError
+	if errors.As(e.Err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// ExitCode returns the exit status of a failed git command, or -1 when err is
+// not a git command that exited
+func ExitCode(err error) int {
+	var gitErr *Error
+	if errors.As(err, &gitErr) {
+		return gitErr.ExitCode()
+	}
+	return -1
+}
+
+// stderrTail keeps the end of what a command writes to stderr
+type stderrTail struct {
+	buf []byte
+}
+
+func (t *stderrTail) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > maxStderr {
+		t.buf = t.buf[len(t.buf)-maxStderr:]
+	}
+	return len(p), nil
+}
+
+func (t *stderrTail) String() string {
+	return strings.TrimSpace(string(t.buf))
+}
@@ -0,0 +1,168 @@
+// Package gitcmd runs git for the other packages, bounded by a context and a
+// timeout, so a hung git, such as one waiting on a network filesystem or a
+// credential helper, cannot hang gitmit and Ctrl+C stops it.
+package gitcmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds each git command until SetTimeout changes it
+const DefaultTimeout = 30 * time.Second
+
+// waitDelay is how long a stopped git may keep its output open, for example
+// through a hook it started, before gitmit stops waiting for it
+const waitDelay = time.Second
+
+var timeout = DefaultTimeout
+
+// SetTimeout sets how long each git command may run; 0 means no limit
+func SetTimeout(d time.Duration) {
+	timeout = d
+}
+
+// dirKey is the context key of the directory git runs in
+type dirKey struct{}
+
+// WithDir returns a context git runs in dir with, rather than the working
+// directory, for servers handling the repositories of several requests
+func WithDir(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, dirKey{}, dir)
+}
+
+// Dir returns the directory git runs in with ctx, "" for the working directory
+func Dir(ctx context.Context) string {
+	dir, _ := ctx.Value(dirKey{}).(string)
+	return dir
+}
+
+// WorkDir returns the directory git runs in with ctx as an absolute path
+func WorkDir(ctx context.Context) (string, error) {
+	if dir := Dir(ctx); dir != "" {
+		return filepath.Abs(dir)
+	}
+	return os.Getwd()
+}
+
+// Path returns a path git printed, such as that of rev-parse --git-path,
+// relative to the directory it ran in, as seen from the working directory
+func Path(ctx context.Context, path string) string {
+	if dir := Dir(ctx); dir != "" && !filepath.IsAbs(path) {
+		return filepath.Join(dir, path)
+	}
+	return path
+}
+
+// Cmd is a git command that stops when its context is done or the timeout
+// passes. Use its Start and Wait or Run methods, which report a timeout or
+// cancellation as such and a failure as an *Error, rather than those of
+// exec.Cmd.
+type Cmd struct {
+	*exec.Cmd
+	parent  context.Context // Context of the caller
+	ctx     context.Context // parent, bounded by the timeout
+	cancel  context.CancelFunc
+	timeout time.Duration
+	stderr  stderrTail // End of stderr, also written to Stderr when set
+}
+
+// Command returns git with the given arguments, bounded by ctx and the
+// timeout, run in the directory of ctx
+func Command(ctx context.Context, args ...string) *Cmd {
+	return command(ctx, timeout, args)
+}
+
+// LongCommand returns git with the given arguments, bounded by ctx only, for
+// commands whose run time grows with the repository, such as reading all of
+// its history
+func LongCommand(ctx context.Context, args ...string) *Cmd {
+	return command(ctx, 0, args)
+}
+
+func command(ctx context.Context, timeout time.Duration, args []string) *Cmd {
+	c := &Cmd{parent: ctx, timeout: timeout}
+	if timeout > 0 {
+		c.ctx, c.cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		c.ctx, c.cancel = context.WithCancel(ctx)
+	}
+	c.Cmd = exec.CommandContext(c.ctx, "git", args...)
+	c.Dir = Dir(ctx)
+	c.WaitDelay = waitDelay
+	return c
+}
+
+// Start starts the command
+func (c *Cmd) Start() error {
+	if c.Stderr == nil {
+		c.Stderr = &c.stderr
+	} else {
+		c.Stderr = io.MultiWriter(c.Stderr, &c.stderr)
+	}
+	if err := c.Cmd.Start(); err != nil {
+		c.cancel()
+		return c.err(err)
+	}
+	return nil
+}
+
+// Wait waits for the command to exit and releases its timeout
+func (c *Cmd) Wait() error {
+	defer c.cancel()
+	return c.err(c.Cmd.Wait())
+}
+
+// Run starts the command and waits for it
+func (c *Cmd) Run() error {
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return c.Wait()
+}
+
+// err explains the error of a command: stopped by its context, or failed with
+// what git wrote to stderr
+func (c *Cmd) err(err error) error {
+	if err == nil {
+		return nil
+	}
+	name := strings.Join(c.Args[1:min(len(c.Args), 2)], " ")
+	switch {
+	case c.parent.Err() != nil:
+		return fmt.Errorf("git %s stopped: %w", name, c.parent.Err())
+	case errors.Is(c.ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("git %s timed out after %s (set timeouts.git to allow longer): %w", name, c.timeout, context.DeadlineExceeded)
+	}
+	return &Error{Args: c.Args[1:], Stderr: c.stderr.String(), Err: err}
+}
+
+// Output runs git in dir, or the directory of ctx when dir is empty, and
+// returns its output. A failure is an *Error with what git wrote to stderr.
+// Git fails rather than prompts for credentials, as for a remote repository
+// that does not exist, since its output is read by gitmit.
+func Output(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := Command(ctx, args...)
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	start := time.Now()
+	err := cmd.Run()
+	slog.Debug("ran git", "args", strings.Join(args, " "), "dir", cmd.Dir, "took", time.Since(start).Round(time.Millisecond), "error", err)
+	if err != nil {
+		return "", err
+	}
+	return stdout.String(), nil
+}
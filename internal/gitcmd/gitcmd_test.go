@@ -0,0 +1,138 @@
+package gitcmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOutput(t *testing.T) {
+	out, err := Output(context.Background(), "", "--version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, "git version") {
+		t.Errorf("Output() = %q, want the git version", out)
+	}
+
+	dir := t.TempDir()
+	if _, err := Output(context.Background(), dir, "rev-parse", "--show-toplevel"); err == nil || !strings.Contains(err.Error(), "not a git repository") {
+		t.Errorf("Output() outside a repository = %v, want git's explanation", err)
+	}
+}
+
+func TestOutputCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Output(ctx, "", "--version")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Output() with a canceled context = %v, want context.Canceled", err)
+	}
+}
+
+func TestOutputTimeout(t *testing.T) {
+	defer SetTimeout(DefaultTimeout)
+	SetTimeout(100 * time.Millisecond)
+
+	// git hash-object --stdin waits for input that never comes
+	cmd := Command(context.Background(), "hash-object", "--stdin")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+	err = cmd.Run()
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Run() past the timeout = %v, want a timeout", err)
+	}
+}
+
+func TestRunError(t *testing.T) {
+	dir := t.TempDir()
+	var stderr bytes.Buffer
+	cmd := Command(context.Background(), "rev-parse", "--show-toplevel")
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	var gitErr *Error
+	if !errors.As(err, &gitErr) {
+		t.Fatalf("Run() outside a repository = %v, want an *Error", err)
+	}
+	if gitErr.Subcommand() != "rev-parse" || gitErr.ExitCode() != 128 || ExitCode(err) != 128 {
+		t.Errorf("error = %+v with exit code %d, want rev-parse exiting with 128", gitErr, gitErr.ExitCode())
+	}
+	if !strings.HasPrefix(err.Error(), "git rev-parse: fatal: not a git repository") {
+		t.Errorf("Error() = %q, want git's explanation", err)
+	}
+	if !strings.Contains(stderr.String(), "not a git repository") {
+		t.Errorf("stderr = %q, want it still written to the command's Stderr", stderr.String())
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	exitErr := errors.New("exit status 1")
+	if got := (&Error{Args: []string{"commit"}, Err: exitErr}).Error(); got != "git commit: exit status 1" {
+		t.Errorf("Error() without stderr = %q, want the exit error", got)
+	}
+
+	var lines []string
+	for i := 1; i <= 12; i++ {
+		lines = append(lines, fmt.Sprintf("hook line %d", i))
+	}
+	got := (&Error{Args: []string{"commit"}, Stderr: strings.Join(lines, "\n"), Err: exitErr}).Error()
+	if strings.Contains(got, "hook line 2\n") || !strings.HasSuffix(got, "hook line 12") || !strings.HasPrefix(got, "git commit: ...\nhook line 3") {
+		t.Errorf("Error() = %q, want the last %d lines of stderr", got, maxStderrLines)
+	}
+	if ExitCode(exitErr) != -1 {
+		t.Errorf("ExitCode() of another error = %d, want -1", ExitCode(exitErr))
+	}
+}
+
+func TestStderrTail(t *testing.T) {
+	var tail stderrTail
+	tail.Write([]byte(strings.Repeat("a", maxStderr)))
+	tail.Write([]byte("fatal: index.lock exists\n"))
+	if got := tail.String(); len(got) > maxStderr || !strings.HasSuffix(got, "fatal: index.lock exists") {
+		t.Errorf("tail = %d bytes ending %q, want at most %d bytes ending with the last line", len(got), got[len(got)-30:], maxStderr)
+	}
+}
+
+func TestErrNotARepo(t *testing.T) {
+	_, err := Output(context.Background(), t.TempDir(), "status")
+	if !errors.Is(err, ErrNotARepo) {
+		t.Errorf("Output() outside a repository = %v, want ErrNotARepo", err)
+	}
+	if _, err := Output(context.Background(), "", "rev-parse", "--verify", "--quiet", "no-such-revision"); errors.Is(err, ErrNotARepo) {
+		t.Errorf("Output() with a missing revision = %v, want another error", err)
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version, min string
+		want         bool
+	}{
+		{"2.43.0", "2.13", true},
+		{"2.13", "2.13", true},
+		{"2.9.5", "2.13", false},
+		{"1.8.3.1", "2.13", false},
+		{"2.39.3 (Apple Git-146)", "2.13", true},
+		{"2.45.1.windows.1", "2.13", true},
+		{"", "2.13", false},
+	}
+	for _, tt := range tests {
+		if got := VersionAtLeast(tt.version, tt.min); got != tt.want {
+			t.Errorf("VersionAtLeast(%q, %q) = %t, want %t", tt.version, tt.min, got, tt.want)
+		}
+	}
+
+	version, err := Version(context.Background())
+	if err != nil || !VersionAtLeast(version, "1") {
+		t.Errorf("Version() = %q, %v", version, err)
+	}
+}
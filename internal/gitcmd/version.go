@@ -0,0 +1,58 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MinVersion is the oldest git gitmit supports; git stash push, the newest
+// subcommand it runs, came with 2.13
+const MinVersion = "2.13"
+
+// Version returns the version of the installed git, such as "2.43.0" or
+// "2.39.3 (Apple Git-146)"
+func Version(ctx context.Context) (string, error) {
+	out, err := Output(ctx, "", "--version")
+	if err != nil {
+		return "", err
+	}
+	version, ok := strings.CutPrefix(strings.TrimSpace(out), "git version ")
+	if !ok {
+		return "", fmt.Errorf("unexpected output of git --version: %q", strings.TrimSpace(out))
+	}
+	return version, nil
+}
+
+// VersionAtLeast reports whether a git version is min or newer, comparing the
+// numbers before any suffix such as ".windows.1" or " (Apple Git-146)"
+func VersionAtLeast(version, min string) bool {
+	have, want := versionNumbers(version), versionNumbers(min)
+	for i := range want {
+		if i >= len(have) || have[i] < want[i] {
+			return false
+		}
+		if have[i] > want[i] {
+			return true
+		}
+	}
+	return true
+}
+
+// versionNumbers returns the leading dot-separated numbers of a version
+func versionNumbers(version string) []int {
+	fields := strings.Fields(version)
+	if len(fields) == 0 {
+		return nil
+	}
+	var numbers []int
+	for _, part := range strings.Split(fields[0], ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			break
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
@@ -0,0 +1,90 @@
+// Package githooks generates the configuration that wires gitmit into git's
+// prepare-commit-msg and commit-msg hooks for repositories whose hooks are
+// managed by husky, lefthook or pre-commit rather than kept in .git/hooks.
+package githooks
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Frameworks are the hook managers configuration can be generated for
+var Frameworks = []string{"husky", "lefthook", "pre-commit"}
+
+// The commands the hooks run: the first fills in a suggested message, the
+// second checks the message against the commit rules
+const (
+	PrepareCommand = "gitmit hook prepare-commit-msg"
+	CheckCommand   = "gitmit hook commit-msg"
+)
+
+// File is a file of a hook manager's configuration
+type File struct {
+	Path       string // Relative to the top of the working tree
+	Content    string
+	Executable bool
+	Snippet    bool // Content is merged into the file rather than being all of it
+}
+
+// Generate returns the files wiring gitmit into the hooks of a framework
+func Generate(framework string) ([]File, error) {
+	switch framework {
+	case "husky":
+		// Husky 9 runs each file of .husky with sh and the hook's arguments
+		return []File{
+			{Path: ".husky/prepare-commit-msg", Content: PrepareCommand + ` "$1" "$2" "$3"` + "\n", Executable: true},
+			{Path: ".husky/commit-msg", Content: CheckCommand + ` "$1"` + "\n", Executable: true},
+		}, nil
+	case "lefthook":
+		return []File{{Path: "lefthook.yml", Snippet: true, Content: `prepare-commit-msg:
+  commands:
+    gitmit:
+      run: ` + PrepareCommand + ` {1} {2} {3}
+commit-msg:
+  commands:
+    gitmit:
+      run: ` + CheckCommand + ` {1}
+`}}, nil
+	case "pre-commit":
+		// pre-commit passes the message file as the only filename, and the
+		// source and commit of prepare-commit-msg in environment variables
+		return []File{{Path: ".pre-commit-config.yaml", Snippet: true, Content: `default_install_hook_types: [pre-commit, prepare-commit-msg, commit-msg]
+repos:
+  - repo: local
+    hooks:
+      - id: gitmit-prepare-commit-msg
+        name: gitmit suggest commit message
+        entry: ` + PrepareCommand + `
+        language: system
+        stages: [prepare-commit-msg]
+      - id: gitmit-commit-msg
+        name: gitmit check commit message
+        entry: ` + CheckCommand + `
+        language: system
+        stages: [commit-msg]
+`}}, nil
+	}
+	return nil, fmt.Errorf("unknown framework %q, expected %s", framework, strings.Join(Frameworks, ", "))
+}
+
+// IsWired reports whether the content of a hook manager's file runs gitmit's
+// hooks already
+func IsWired(content string) bool {
+	return strings.Contains(content, PrepareCommand) || strings.Contains(content, CheckCommand)
+}
+
+// Detect returns the framework whose files exist, judged by exists, or ""
+func Detect(exists func(path string) bool) string {
+	markers := map[string][]string{
+		"husky":      {".husky"},
+		"lefthook":   {"lefthook.yml", "lefthook.yaml", ".lefthook.yml", ".lefthook.yaml"},
+		"pre-commit": {".pre-commit-config.yaml", ".pre-commit-config.yml"},
+	}
+	for _, framework := range Frameworks {
+		if slices.ContainsFunc(markers[framework], exists) {
+			return framework
+		}
+	}
+	return ""
+}
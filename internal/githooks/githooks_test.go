@@ -0,0 +1,61 @@
+package githooks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	for _, framework := range Frameworks {
+		files, err := Generate(framework)
+		if err != nil {
+			t.Fatalf("Generate(%q): %v", framework, err)
+		}
+		var content strings.Builder
+		for _, file := range files {
+			content.WriteString(file.Content)
+		}
+		for _, command := range []string{PrepareCommand, CheckCommand} {
+			if !strings.Contains(content.String(), command) {
+				t.Errorf("Generate(%q) does not run %q", framework, command)
+			}
+		}
+		if !IsWired(content.String()) {
+			t.Errorf("IsWired(Generate(%q)) = false", framework)
+		}
+	}
+
+	files, _ := Generate("husky")
+	if len(files) != 2 || files[0].Path != ".husky/prepare-commit-msg" || !files[0].Executable || files[0].Snippet {
+		t.Errorf("Generate(husky) = %+v", files)
+	}
+	if _, err := Generate("overcommit"); err == nil {
+		t.Error("Generate(overcommit) should fail")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		paths []string
+		want  string
+	}{
+		{[]string{".husky"}, "husky"},
+		{[]string{"lefthook.yaml"}, "lefthook"},
+		{[]string{".pre-commit-config.yaml"}, "pre-commit"},
+		{[]string{".pre-commit-config.yaml", ".husky"}, "husky"},
+		{[]string{"package.json"}, ""},
+	}
+	for _, tt := range tests {
+		exists := func(path string) bool {
+			for _, p := range tt.paths {
+				if p == path {
+					return true
+				}
+			}
+			return false
+		}
+		if got := Detect(exists); got != tt.want {
+			t.Errorf("Detect(%v) = %q, want %q", tt.paths, got, tt.want)
+		}
+	}
+}
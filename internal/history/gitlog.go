@@ -0,0 +1,98 @@
+package history
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/xdg"
+)
+
+// recentSubjectCount is how many real commit subjects are checked for duplicates
+const recentSubjectCount = 50
+
+// styleSubjectCount is how many commit subjects are read to learn the repository's style
+const styleSubjectCount = 300
+
+// subjectCache is the cached list of recent commit subjects of one repository,
+// valid as long as HEAD has not moved
+type subjectCache struct {
+	Head     string   `json:"head"`
+	Subjects []string `json:"subjects"`
+}
+
+// IsRecentCommit reports whether a message has the same subject as one of the
+// last recentSubjectCount commits in the repository, including commits made
+// without gitmit
+func (h *CommitHistory) IsRecentCommit(message string) bool {
+	if h == nil || len(h.recentSubjects) == 0 {
+		return false
+	}
+	return h.recentSubjects[normalizeSubject(message)]
+}
+
+// RecentSubjects returns the subjects of the latest commits in the repository,
+// newest first, for learning the repository's commit style
+func (h *CommitHistory) RecentSubjects() []string {
+	if h == nil {
+		return nil
+	}
+	return h.subjects
+}
+
+// loadSubjects returns the subjects of the latest styleSubjectCount commits, read
+// from the cache in $XDG_CACHE_HOME/gitmit when HEAD is unchanged
+func loadSubjects(ctx context.Context, key string) []string {
+	head := gitOutput(ctx, "rev-parse", "HEAD")
+	if head == "" {
+		return nil
+	}
+
+	cachePath := ""
+	if cacheDir, err := xdg.CacheDir(); err == nil {
+		sum := sha256.Sum256([]byte(key))
+		cachePath = filepath.Join(cacheDir, "subjects", hex.EncodeToString(sum[:])[:16]+".json")
+	}
+
+	var cache subjectCache
+	if data, err := os.ReadFile(cachePath); err != nil || json.Unmarshal(data, &cache) != nil || cache.Head != head {
+		cache = subjectCache{Head: head}
+		if out := gitOutput(ctx, "log", "-n", strconv.Itoa(styleSubjectCount), "--pretty=%s"); out != "" {
+			cache.Subjects = strings.Split(out, "\n")
+		}
+		// The cache is only an optimization, so failing to write it is not an error
+		if cachePath != "" {
+			if data, err := json.Marshal(cache); err == nil && os.MkdirAll(filepath.Dir(cachePath), 0755) == nil {
+				_ = os.WriteFile(cachePath, data, 0644)
+			}
+		}
+	}
+
+	return cache.Subjects
+}
+
+// recentSubjectSet returns the normalized form of the first recentSubjectCount subjects
+func recentSubjectSet(subjects []string) map[string]bool {
+	if len(subjects) > recentSubjectCount {
+		subjects = subjects[:recentSubjectCount]
+	}
+	set := make(map[string]bool, len(subjects))
+	for _, subject := range subjects {
+		if s := normalizeSubject(subject); s != "" {
+			set[s] = true
+		}
+	}
+	return set
+}
+
+// normalizeSubject reduces a message to its lowercased first line so that
+// formatting differences do not hide a duplicate
+func normalizeSubject(message string) string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	return strings.ToLower(strings.TrimSpace(subject))
+}
@@ -0,0 +1,55 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// StoreHealth describes the global history store for diagnostics
+type StoreHealth struct {
+	Path         string   // Location of the store
+	Exists       bool     // Whether anything was saved yet
+	Corrupt      bool     // Whether the store cannot be parsed; the next load resets it
+	Repositories int      // Repositories with a history
+	Backups      []string // Corrupt stores that were moved aside
+}
+
+// CheckStore reads the global store without changing it. It fails when the
+// store cannot be parsed or its directory cannot be written.
+func CheckStore() (*StoreHealth, error) {
+	path, err := storePath()
+	if err != nil {
+		return nil, err
+	}
+	health := &StoreHealth{Path: path}
+	health.Backups, _ = filepath.Glob(path + ".corrupt-*")
+
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return health, fmt.Errorf("error reading commit history file %s: %w", path, err)
+	default:
+		health.Exists = true
+		var store Store
+		if err := json.Unmarshal(data, &store); err != nil {
+			health.Corrupt = true
+			return health, fmt.Errorf("error unmarshaling commit history file %s: %w", path, err)
+		}
+		health.Repositories = len(store.Repositories)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return health, fmt.Errorf("error creating history directory: %w", err)
+	}
+	probe, err := os.CreateTemp(dir, storeFileName+".*.tmp")
+	if err != nil {
+		return health, fmt.Errorf("history directory %s is not writable: %w", dir, err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	return health, nil
+}
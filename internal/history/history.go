@@ -1,104 +1,185 @@
 package history
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
 )
 
-const historyFileName = ".commit_suggest_history.json"
-const maxHistoryEntries = 10
+// Outcomes of a suggestion, recorded to learn which templates the user prefers
+const (
+	OutcomeAccepted    = "accepted"    // Committed as suggested
+	OutcomeEdited      = "edited"      // Committed after manual editing
+	OutcomeRejected    = "rejected"    // Dismissed without committing
+	OutcomeRegenerated = "regenerated" // Replaced by asking for another suggestion
+)
+
+// Sources of a suggestion, recorded to compare how well each works
+const (
+	SourceTemplate = "template" // Filled in from a template
+	SourceAI       = "ai"       // Generated by the AI model
+)
 
 // HistoryEntry represents a single entry in the commit history
 type HistoryEntry struct {
 	Message   string    `json:"message"`
 	Timestamp time.Time `json:"timestamp"`
 	Template  string    `json:"template,omitempty"` // Optional: store which template was used
+	Outcome   string    `json:"outcome,omitempty"`  // accepted or edited
 }
 
-// CommitHistory represents the list of past commit suggestions
+// TemplateStats counts the outcomes of suggestions produced by one template
+type TemplateStats struct {
+	Accepted    int `json:"accepted"`
+	Edited      int `json:"edited"`
+	Rejected    int `json:"rejected"`
+	Regenerated int `json:"regenerated"`
+}
+
+// CommitHistory represents the list of past commit suggestions for one repository
 type CommitHistory struct {
-	Entries []HistoryEntry `json:"entries"`
+	Entries       []HistoryEntry            `json:"entries"`
+	TemplateStats map[string]*TemplateStats `json:"templateStats,omitempty"` // template -> outcome counts
+	RuleStats     map[string]*TemplateStats `json:"ruleStats,omitempty"`     // smart suggestion rule -> outcome counts
+	SourceStats   map[string]*TemplateStats `json:"sourceStats,omitempty"`   // suggestion source -> outcome counts
+	WIP           []string                  `json:"wip,omitempty"`           // Checkpoint commits made by gitmit wip, oldest first
+
+	key            string          // Repository key within the global store
+	loaded         *CommitHistory  // The history as last loaded or saved, to save only what changed since
+	subjects       []string        // Subjects of the latest real commits, newest first
+	recentSubjects map[string]bool // Normalized subjects of the latest real commits
 }
 
-// LoadHistory loads the commit history from .commit_suggest_history.json
-func LoadHistory() (*CommitHistory, error) {
-	data, err := os.ReadFile(historyFileName)
-	if os.IsNotExist(err) {
-		return &CommitHistory{Entries: []HistoryEntry{}}, nil // Return empty history if file doesn't exist
-	}
-	if err != nil {
-		return nil, fmt.Errorf("error reading commit history file %s: %w", historyFileName, err)
+// AddEntry adds a new entry to the commit history, dropping the entries beyond
+// the configured retention
+func (h *CommitHistory) AddEntry(message, template string) {
+	newEntry := HistoryEntry{
+		Message:   message,
+		Timestamp: time.Now(),
+		Template:  template,
 	}
 
-	var history CommitHistory
-	err = json.Unmarshal(data, &history)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshaling commit history file %s: %w", historyFileName, err)
+	h.Entries = append([]HistoryEntry{newEntry}, h.Entries...)
+	h.Prune()
+}
+
+// RecordOutcome records what the user did with a suggestion. Committed messages
+// (accepted or edited) are also added as history entries; every outcome updates
+// the statistics of the template that produced the suggestion.
+func (h *CommitHistory) RecordOutcome(message, template, outcome string) {
+	if outcome == OutcomeAccepted || outcome == OutcomeEdited {
+		h.AddEntry(message, template)
+		h.Entries[0].Outcome = outcome
 	}
 
-	return &history, nil
+	if template == "" {
+		return
+	}
+	if h.TemplateStats == nil {
+		h.TemplateStats = make(map[string]*TemplateStats)
+	}
+	countOutcome(h.TemplateStats, template, outcome)
 }
 
-// SaveHistory saves the commit history to .commit_suggest_history.json
-func (h *CommitHistory) SaveHistory() error {
-	data, err := json.MarshalIndent(h, "", "  ")
-	if err != nil {
-		return fmt.Errorf("error marshaling commit history: %w", err)
+// RecordRuleOutcome records what the user did with a suggestion of a smart rule
+func (h *CommitHistory) RecordRuleOutcome(rule, outcome string) {
+	if h.RuleStats == nil {
+		h.RuleStats = make(map[string]*TemplateStats)
 	}
+	countOutcome(h.RuleStats, rule, outcome)
+}
 
-	err = os.WriteFile(historyFileName, data, 0644)
-	if err != nil {
-		return fmt.Errorf("error writing commit history file %s: %w", historyFileName, err)
+// RecordSourceOutcome records what the user did with a suggestion of a source,
+// SourceTemplate or SourceAI
+func (h *CommitHistory) RecordSourceOutcome(source, outcome string) {
+	if h.SourceStats == nil {
+		h.SourceStats = make(map[string]*TemplateStats)
 	}
+	countOutcome(h.SourceStats, source, outcome)
+}
 
-	return nil
+// RuleAcceptance returns the chance that a suggestion of a smart rule is committed
+// as-is, from 0 to 1, estimated from the recorded outcomes. Edits count as half an
+// acceptance. The prior, the rule's expected acceptance, stands for a few outcomes,
+// so the estimate moves from the prior to the observed rate as outcomes are
+// recorded. The number of recorded outcomes is returned with it.
+func (h *CommitHistory) RuleAcceptance(rule string, prior float64) (float64, int) {
+	const priorWeight = 4
+	if h == nil || h.RuleStats[rule] == nil {
+		return prior, 0
+	}
+	stats := h.RuleStats[rule]
+	total := stats.Accepted + stats.Edited + stats.Rejected + stats.Regenerated
+	accepted := float64(stats.Accepted) + 0.5*float64(stats.Edited)
+	return (accepted + prior*priorWeight) / (float64(total) + priorWeight), total
 }
 
-// AddEntry adds a new entry to the commit history, keeping only the latest maxHistoryEntries
-func (h *CommitHistory) AddEntry(message, template string) {
-	newEntry := HistoryEntry{
-		Message:   message,
-		Timestamp: time.Now(),
-		Template:  template,
+// countOutcome adds an outcome to the counts of a key
+func countOutcome(counts map[string]*TemplateStats, key, outcome string) {
+	stats, ok := counts[key]
+	if !ok {
+		stats = &TemplateStats{}
+		counts[key] = stats
 	}
 
-	h.Entries = append([]HistoryEntry{newEntry}, h.Entries...)
+	switch outcome {
+	case OutcomeAccepted:
+		stats.Accepted++
+	case OutcomeEdited:
+		stats.Edited++
+	case OutcomeRejected:
+		stats.Rejected++
+	case OutcomeRegenerated:
+		stats.Regenerated++
+	}
+}
 
-	// Keep only the latest N entries
-	if len(h.Entries) > maxHistoryEntries {
-		h.Entries = h.Entries[:maxHistoryEntries]
+// TemplatePreference returns how much the user likes a template, from -1 (always
+// edited away or rejected) to 1 (always accepted as-is). Templates without
+// recorded outcomes score 0. The score is smoothed so a single outcome has limited weight.
+func (h *CommitHistory) TemplatePreference(template string) float64 {
+	if h == nil || h.TemplateStats == nil {
+		return 0
 	}
+	stats, ok := h.TemplateStats[template]
+	if !ok {
+		return 0
+	}
+
+	positive := float64(stats.Accepted)
+	negative := float64(stats.Edited+stats.Rejected) + 0.5*float64(stats.Regenerated)
+	total := float64(stats.Accepted + stats.Edited + stats.Rejected + stats.Regenerated)
+	return (positive - negative) / (total + 2)
 }
 
-// Contains checks if the history contains a given message
+// Contains checks if the history contains a given message, either as a gitmit
+// entry within the configured retention or as the subject of a recent commit
+// in the repository
 func (h *CommitHistory) Contains(message string) bool {
+	_, age := retention()
+	now := time.Now()
 	for _, entry := range h.Entries {
-		if entry.Message == message {
+		if entry.Message == message && !expired(entry, age, now) {
 			return true
 		}
 	}
-	return false
+	return h.IsRecentCommit(message)
 }
 
 // GetRecentCommitContext retrieves the most recent commit message from git history
 // This helps maintain consistency by suggesting similar topics/scopes
-func GetRecentCommitContext() (string, string, error) {
+func GetRecentCommitContext(ctx context.Context) (string, string, error) {
 	// Get the last commit message on the current branch
-	cmd := exec.Command("git", "log", "-1", "--pretty=%B")
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	err := cmd.Run()
+	out, err := gitcmd.Output(ctx, "", "log", "-1", "--pretty=%B")
 	if err != nil {
 		return "", "", fmt.Errorf("error getting recent commit: %w", err)
 	}
 
-	commitMsg := strings.TrimSpace(out.String())
+	commitMsg := strings.TrimSpace(out)
 	if commitMsg == "" {
 		return "", "", nil
 	}
@@ -116,17 +197,14 @@ func GetRecentCommitContext() (string, string, error) {
 }
 
 // GetRecentCommits retrieves the last N commit messages from git history
-func GetRecentCommits(count int) ([]string, error) {
-	cmd := exec.Command("git", "log", fmt.Sprintf("-%d", count), "--pretty=%B")
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	err := cmd.Run()
+func GetRecentCommits(ctx context.Context, count int) ([]string, error) {
+	out, err := gitcmd.Output(ctx, "", "log", fmt.Sprintf("-%d", count), "--pretty=%B")
 	if err != nil {
 		return nil, fmt.Errorf("error getting recent commits: %w", err)
 	}
 
 	commits := []string{}
-	lines := strings.Split(out.String(), "\n")
+	lines := strings.Split(out, "\n")
 	currentCommit := ""
 	for _, line := range lines {
 		if line == "" {
@@ -0,0 +1,74 @@
+package history
+
+import "testing"
+
+func TestTemplatePreference(t *testing.T) {
+	h := &CommitHistory{}
+	accepted := "feat({topic}): add support for {item}"
+	edited := "feat({topic}): introduce new feature"
+
+	for i := 0; i < 4; i++ {
+		h.RecordOutcome("feat(api): add support for users", accepted, OutcomeAccepted)
+		h.RecordOutcome("feat(api): add user listing endpoint", edited, OutcomeEdited)
+	}
+	h.RecordOutcome("", edited, OutcomeRejected)
+
+	if got := h.TemplatePreference(accepted); got <= 0 {
+		t.Errorf("TemplatePreference(accepted) = %v, want > 0", got)
+	}
+	if got := h.TemplatePreference(edited); got >= 0 {
+		t.Errorf("TemplatePreference(edited) = %v, want < 0", got)
+	}
+	if got := h.TemplatePreference("unknown"); got != 0 {
+		t.Errorf("TemplatePreference(unknown) = %v, want 0", got)
+	}
+
+	if len(h.Entries) != 8 {
+		t.Errorf("expected 8 committed entries, got %d", len(h.Entries))
+	}
+	if h.Entries[0].Outcome != OutcomeEdited {
+		t.Errorf("Entries[0].Outcome = %q, want %q", h.Entries[0].Outcome, OutcomeEdited)
+	}
+}
+
+func TestRuleAcceptance(t *testing.T) {
+	h := &CommitHistory{}
+	if got, n := h.RuleAcceptance("branch", 0.65); got != 0.65 || n != 0 {
+		t.Errorf("RuleAcceptance() without outcomes = %v, %d, want the prior", got, n)
+	}
+
+	for i := 0; i < 36; i++ {
+		h.RecordRuleOutcome("branch", OutcomeAccepted)
+	}
+	for i := 0; i < 4; i++ {
+		h.RecordRuleOutcome("branch", OutcomeRejected)
+	}
+	got, n := h.RuleAcceptance("branch", 0.65)
+	if n != 40 || got < 0.85 || got > 0.9 {
+		t.Errorf("RuleAcceptance() after 36 of 40 accepted = %v, %d, want close to 0.9", got, n)
+	}
+
+	h.RecordRuleOutcome("template", OutcomeEdited)
+	h.RecordRuleOutcome("template", OutcomeEdited)
+	if got, _ := h.RuleAcceptance("template", 0.8); got >= 0.8 {
+		t.Errorf("RuleAcceptance() after edits = %v, want below the prior", got)
+	}
+}
+
+func TestContainsRecentCommit(t *testing.T) {
+	h := &CommitHistory{recentSubjects: map[string]bool{
+		normalizeSubject("feat(api): add user endpoint"): true,
+	}}
+
+	if !h.Contains("Feat(api): add user endpoint\n\nBody text") {
+		t.Error("expected a message with the subject of a recent commit to be contained")
+	}
+	if h.Contains("fix(api): handle empty user") {
+		t.Error("did not expect an unrelated message to be contained")
+	}
+
+	var empty *CommitHistory
+	if empty.IsRecentCommit("feat(api): add user endpoint") {
+		t.Error("nil history should not report recent commits")
+	}
+}
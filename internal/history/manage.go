@@ -0,0 +1,183 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// PruneStore applies the configured retention to the history of the current
+// repository, or of every repository when all is set, and returns how many
+// entries were dropped
+func PruneStore(ctx context.Context, all bool) (int, error) {
+	path, err := storePath()
+	if err != nil {
+		return 0, err
+	}
+	key := RepositoryKey(ctx)
+	dropped := 0
+	err = updateStore(path, func(store *Store) error {
+		for k, h := range store.Repositories {
+			if all || k == key {
+				dropped += h.Prune()
+			}
+		}
+		return nil
+	})
+	return dropped, err
+}
+
+// ClearStore removes the history and template statistics of the current
+// repository, or of every repository when all is set, and returns how many
+// entries were removed
+func ClearStore(ctx context.Context, all bool) (int, error) {
+	path, err := storePath()
+	if err != nil {
+		return 0, err
+	}
+	key := RepositoryKey(ctx)
+	removed := 0
+	err = updateStore(path, func(store *Store) error {
+		for k, h := range store.Repositories {
+			if all || k == key {
+				removed += len(h.Entries)
+				delete(store.Repositories, k)
+			}
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// ExportStore writes the history of the current repository, or of every
+// repository when all is set, as a store file that ImportStore reads back
+func ExportStore(ctx context.Context, w io.Writer, all bool) error {
+	path, err := storePath()
+	if err != nil {
+		return err
+	}
+	key := RepositoryKey(ctx)
+	var store *Store
+	err = withStoreLock(path, func() error {
+		store, err = readStore(path)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if !all {
+		exported := &Store{Repositories: make(map[string]*CommitHistory)}
+		if h, ok := store.Repositories[key]; ok {
+			exported.Repositories[key] = h
+		}
+		store = exported
+	}
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling commit history: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// ImportStore merges a file written by ExportStore into the store and returns
+// how many entries were added. Entries already present are skipped, and of the
+// outcome counts of a template the larger is kept, so importing a file twice
+// changes nothing.
+func ImportStore(r io.Reader) (int, error) {
+	var imported Store
+	if err := json.NewDecoder(r).Decode(&imported); err != nil {
+		return 0, fmt.Errorf("error reading commit history export: %w", err)
+	}
+
+	path, err := storePath()
+	if err != nil {
+		return 0, err
+	}
+	added := 0
+	err = updateStore(path, func(store *Store) error {
+		for key, h := range imported.Repositories {
+			if h == nil {
+				continue
+			}
+			existing, ok := store.Repositories[key]
+			if !ok {
+				existing = &CommitHistory{Entries: []HistoryEntry{}}
+				store.Repositories[key] = existing
+			}
+			added += existing.merge(h)
+		}
+		return nil
+	})
+	return added, err
+}
+
+// merge adds the entries of other that h lacks, keeping h newest first and
+// within the retention, and returns how many of them were kept
+func (h *CommitHistory) merge(other *CommitHistory) int {
+	seen := make(map[string]bool, len(h.Entries))
+	for _, entry := range h.Entries {
+		seen[entryKey(entry)] = true
+	}
+	added := make(map[string]bool)
+	for _, entry := range other.Entries {
+		if key := entryKey(entry); !seen[key] {
+			seen[key] = true
+			added[key] = true
+			h.Entries = append(h.Entries, entry)
+		}
+	}
+	sort.SliceStable(h.Entries, func(i, j int) bool {
+		return h.Entries[i].Timestamp.After(h.Entries[j].Timestamp)
+	})
+	h.Prune()
+
+	kept := 0
+	for _, entry := range h.Entries {
+		if added[entryKey(entry)] {
+			kept++
+		}
+	}
+
+	h.TemplateStats = mergeStats(h.TemplateStats, other.TemplateStats)
+	h.RuleStats = mergeStats(h.RuleStats, other.RuleStats)
+	h.SourceStats = mergeStats(h.SourceStats, other.SourceStats)
+	for _, hash := range other.WIP {
+		h.AddWIP(hash)
+	}
+	return kept
+}
+
+// entryKey identifies an entry, whatever time zone its timestamp was read in
+func entryKey(entry HistoryEntry) string {
+	return fmt.Sprintf("%d\x00%s\x00%s\x00%s", entry.Timestamp.UnixNano(), entry.Message, entry.Template, entry.Outcome)
+}
+
+// mergeStats keeps the larger of each outcome count of both statistics
+func mergeStats(into, from map[string]*TemplateStats) map[string]*TemplateStats {
+	if len(from) == 0 {
+		return into
+	}
+	if into == nil {
+		into = make(map[string]*TemplateStats, len(from))
+	}
+	for key, stats := range from {
+		if stats == nil {
+			continue
+		}
+		existing, ok := into[key]
+		if !ok {
+			copied := *stats
+			into[key] = &copied
+			continue
+		}
+		existing.Accepted = max(existing.Accepted, stats.Accepted)
+		existing.Edited = max(existing.Edited, stats.Edited)
+		existing.Rejected = max(existing.Rejected, stats.Rejected)
+		existing.Regenerated = max(existing.Regenerated, stats.Regenerated)
+	}
+	return into
+}
@@ -0,0 +1,63 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestExportImportStore(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	defer SetRetention(defaultMaxEntries, 0)
+
+	key := RepositoryKey(context.Background())
+	h := &CommitHistory{key: key}
+	h.RecordOutcome("feat: add export", "feat({topic}): add {item}", OutcomeAccepted)
+	if err := h.SaveHistory(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var exported bytes.Buffer
+	if err := ExportStore(context.Background(), &exported, false); err != nil {
+		t.Fatalf("ExportStore() = %v", err)
+	}
+
+	if removed, err := ClearStore(context.Background(), false); err != nil || removed != 1 {
+		t.Fatalf("ClearStore() = %d, %v, want 1 entry removed", removed, err)
+	}
+	for i, want := range []int{1, 0} {
+		added, err := ImportStore(bytes.NewReader(exported.Bytes()))
+		if err != nil || added != want {
+			t.Fatalf("ImportStore() #%d = %d, %v, want %d entries added", i+1, added, err, want)
+		}
+	}
+
+	loaded, err := LoadHistory(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Entries) != 1 || loaded.Entries[0].Message != "feat: add export" {
+		t.Errorf("entries = %v, want the exported entry once", loaded.Entries)
+	}
+	if stats := loaded.TemplateStats["feat({topic}): add {item}"]; stats == nil || stats.Accepted != 1 {
+		t.Errorf("template stats = %+v, want the exported counts, not summed", stats)
+	}
+}
+
+func TestMergeKeepsNewestFirst(t *testing.T) {
+	defer SetRetention(defaultMaxEntries, 0)
+	SetRetention(2, 0)
+	now := time.Now()
+	h := &CommitHistory{Entries: []HistoryEntry{{Message: "b", Timestamp: now.Add(-2 * time.Hour)}}}
+	other := &CommitHistory{Entries: []HistoryEntry{
+		{Message: "a", Timestamp: now.Add(-time.Hour)},
+		{Message: "c", Timestamp: now.Add(-3 * time.Hour)},
+	}}
+
+	if kept := h.merge(other); kept != 1 {
+		t.Errorf("merge() = %d, want 1 entry kept within maxEntries", kept)
+	}
+	if len(h.Entries) != 2 || h.Entries[0].Message != "a" || h.Entries[1].Message != "b" {
+		t.Errorf("entries = %v, want a and b, newest first", h.Entries)
+	}
+}
@@ -0,0 +1,129 @@
+package history
+
+import (
+	"context"
+	"regexp"
+	"sort"
+)
+
+// OutcomeRow is the outcome counts of one source, template group, template or
+// smart rule
+type OutcomeRow struct {
+	Name string `json:"name"`
+	TemplateStats
+	Total      int     `json:"total"`
+	Acceptance float64 `json:"acceptance"` // Share committed as suggested, from 0 to 1
+}
+
+// OutcomeReport shows how often suggestions were accepted unmodified, edited,
+// rejected or regenerated, by source, template group, template and smart rule
+type OutcomeReport struct {
+	Repositories int          `json:"repositories"`
+	Sources      []OutcomeRow `json:"sources"`
+	Groups       []OutcomeRow `json:"groups"`    // Templates by conventional type
+	Templates    []OutcomeRow `json:"templates"` // Most used first
+	Rules        []OutcomeRow `json:"rules"`
+}
+
+// Empty reports whether no outcome was recorded
+func (r *OutcomeReport) Empty() bool {
+	return len(r.Sources) == 0 && len(r.Templates) == 0 && len(r.Rules) == 0
+}
+
+// templateGroupPattern finds the conventional type a template produces
+var templateGroupPattern = regexp.MustCompile(`^([a-z]+)[(!:]`)
+
+// templateGroup returns the conventional type of a template, such as "feat",
+// or "other" for templates without one
+func templateGroup(template string) string {
+	if m := templateGroupPattern.FindStringSubmatch(template); m != nil {
+		return m[1]
+	}
+	return "other"
+}
+
+// LoadReport reports the recorded outcomes of the current repository, or of
+// every repository together when all is set
+func LoadReport(ctx context.Context, all bool) (*OutcomeReport, error) {
+	path, err := storePath()
+	if err != nil {
+		return nil, err
+	}
+	key := RepositoryKey(ctx)
+	var store *Store
+	err = withStoreLock(path, func() error {
+		store, err = readStore(path)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var histories []*CommitHistory
+	for k, h := range store.Repositories {
+		if all || k == key {
+			histories = append(histories, h)
+		}
+	}
+	return Report(histories...), nil
+}
+
+// Report adds up the recorded outcomes of histories
+func Report(histories ...*CommitHistory) *OutcomeReport {
+	sources := make(map[string]*TemplateStats)
+	groups := make(map[string]*TemplateStats)
+	templates := make(map[string]*TemplateStats)
+	rules := make(map[string]*TemplateStats)
+	for _, h := range histories {
+		addStats(sources, h.SourceStats, func(source string) string { return source })
+		addStats(templates, h.TemplateStats, func(template string) string { return template })
+		addStats(groups, h.TemplateStats, templateGroup)
+		addStats(rules, h.RuleStats, func(rule string) string { return rule })
+	}
+	return &OutcomeReport{
+		Repositories: len(histories),
+		Sources:      outcomeRows(sources),
+		Groups:       outcomeRows(groups),
+		Templates:    outcomeRows(templates),
+		Rules:        outcomeRows(rules),
+	}
+}
+
+// addStats adds the counts of from to into, under the name each key maps to
+func addStats(into, from map[string]*TemplateStats, name func(string) string) {
+	for key, stats := range from {
+		if stats == nil {
+			continue
+		}
+		sum, ok := into[name(key)]
+		if !ok {
+			sum = &TemplateStats{}
+			into[name(key)] = sum
+		}
+		sum.Accepted += stats.Accepted
+		sum.Edited += stats.Edited
+		sum.Rejected += stats.Rejected
+		sum.Regenerated += stats.Regenerated
+	}
+}
+
+// outcomeRows lists counts by name, the most used first and then by name
+func outcomeRows(counts map[string]*TemplateStats) []OutcomeRow {
+	rows := make([]OutcomeRow, 0, len(counts))
+	for name, stats := range counts {
+		row := OutcomeRow{Name: name, TemplateStats: *stats}
+		row.Total = stats.Accepted + stats.Edited + stats.Rejected + stats.Regenerated
+		if row.Total == 0 {
+			continue
+		}
+		row.Acceptance = float64(stats.Accepted) / float64(row.Total)
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Total != rows[j].Total {
+			return rows[i].Total > rows[j].Total
+		}
+		return rows[i].Name < rows[j].Name
+	})
+	return rows
+}
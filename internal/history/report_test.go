@@ -0,0 +1,43 @@
+package history
+
+import "testing"
+
+func TestReport(t *testing.T) {
+	a := &CommitHistory{}
+	a.RecordOutcome("feat(api): add client", "feat({topic}): add {item}", OutcomeAccepted)
+	a.RecordOutcome("feat(api): add retries", "feat({topic}): add {item}", OutcomeEdited)
+	a.RecordOutcome("fix(api): correct client", "fix({topic}): correct {item}", OutcomeRejected)
+	a.RecordSourceOutcome(SourceTemplate, OutcomeAccepted)
+	a.RecordSourceOutcome(SourceAI, OutcomeRegenerated)
+	b := &CommitHistory{}
+	b.RecordOutcome("feat!: drop v1", "feat!: drop {item}", OutcomeAccepted)
+	b.RecordRuleOutcome("docs-only", OutcomeAccepted)
+
+	report := Report(a, b)
+	if report.Repositories != 2 || len(report.Sources) != 2 || len(report.Rules) != 1 {
+		t.Fatalf("Report() = %+v", report)
+	}
+	if len(report.Groups) != 2 || report.Groups[0].Name != "feat" || report.Groups[0].Total != 3 || report.Groups[0].Accepted != 2 {
+		t.Errorf("groups = %+v, want the feat templates of both histories together first", report.Groups)
+	}
+	if got := report.Templates[0]; got.Name != "feat({topic}): add {item}" || got.Acceptance != 0.5 {
+		t.Errorf("top template = %+v, want the most used one with half accepted", got)
+	}
+	if report.Empty() || !Report().Empty() {
+		t.Errorf("Empty() is wrong")
+	}
+}
+
+func TestTemplateGroup(t *testing.T) {
+	tests := map[string]string{
+		"feat({topic}): add {item}": "feat",
+		"fix: correct {item}":       "fix",
+		"refactor!: drop {item}":    "refactor",
+		"Update {item}":             "other",
+	}
+	for template, want := range tests {
+		if got := templateGroup(template); got != want {
+			t.Errorf("templateGroup(%q) = %q, want %q", template, got, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package history
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultMaxEntries is how many entries are kept per repository unless
+// SetRetention says otherwise
+const defaultMaxEntries = 10
+
+var (
+	retentionMu sync.Mutex
+	maxEntries  = defaultMaxEntries
+	maxAge      time.Duration
+)
+
+// SetRetention sets how many entries are kept per repository, newest first,
+// and the age after which entries are dropped; 0 keeps entries however old
+func SetRetention(entries int, age time.Duration) {
+	retentionMu.Lock()
+	defer retentionMu.Unlock()
+	if entries <= 0 {
+		entries = defaultMaxEntries
+	}
+	maxEntries = entries
+	maxAge = age
+}
+
+// retention returns the configured number of entries and maximum age
+func retention() (int, time.Duration) {
+	retentionMu.Lock()
+	defer retentionMu.Unlock()
+	return maxEntries, maxAge
+}
+
+// expired reports whether an entry is older than the configured maximum age
+func expired(entry HistoryEntry, age time.Duration, now time.Time) bool {
+	return age > 0 && !entry.Timestamp.IsZero() && now.Sub(entry.Timestamp) > age
+}
+
+// Prune drops the entries older than the maximum age and those beyond the
+// maximum number of entries, returning how many were dropped. Entries are
+// kept newest first.
+func (h *CommitHistory) Prune() int {
+	entries, age := retention()
+	now := time.Now()
+	kept := h.Entries[:0]
+	for _, entry := range h.Entries {
+		if !expired(entry, age, now) {
+			kept = append(kept, entry)
+		}
+	}
+	if len(kept) > entries {
+		kept = kept[:entries]
+	}
+	dropped := len(h.Entries) - len(kept)
+	h.Entries = kept
+	return dropped
+}
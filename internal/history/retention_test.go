@@ -0,0 +1,34 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPrune(t *testing.T) {
+	defer SetRetention(defaultMaxEntries, 0)
+	now := time.Now()
+	h := &CommitHistory{Entries: []HistoryEntry{
+		{Message: "feat: new", Timestamp: now.Add(-time.Hour)},
+		{Message: "fix: recent", Timestamp: now.Add(-24 * time.Hour)},
+		{Message: "chore: old", Timestamp: now.Add(-60 * 24 * time.Hour)},
+	}}
+
+	SetRetention(10, 30*24*time.Hour)
+	if !h.Contains("feat: new") || h.Contains("chore: old") {
+		t.Errorf("Contains() should only see entries within maxAge")
+	}
+	if dropped := h.Prune(); dropped != 1 || len(h.Entries) != 2 {
+		t.Fatalf("Prune() = %d leaving %v, want the old entry dropped", dropped, h.Entries)
+	}
+
+	SetRetention(1, 0)
+	if dropped := h.Prune(); dropped != 1 || h.Entries[0].Message != "feat: new" {
+		t.Errorf("Prune() = %d leaving %v, want only the newest entry", dropped, h.Entries)
+	}
+
+	h.AddEntry("docs: newest", "")
+	if len(h.Entries) != 1 || h.Entries[0].Message != "docs: newest" {
+		t.Errorf("AddEntry() left %v, want maxEntries respected", h.Entries)
+	}
+}
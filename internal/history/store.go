@@ -0,0 +1,484 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
+	"github.com/andev0x/gitmit/internal/xdg"
+)
+
+// legacyHistoryFileName is the history file older versions wrote into the working directory
+const legacyHistoryFileName = ".commit_suggest_history.json"
+
+// storeFileName is the global history store inside the XDG state directory
+const storeFileName = "history.json"
+
+// storeLockTimeout bounds how long a run waits for another to finish writing
+// the store
+const storeLockTimeout = 5 * time.Second
+
+// Store is the global history file holding the history of every repository,
+// keyed by the repository's normalized remote URL or path
+type Store struct {
+	Repositories map[string]*CommitHistory `json:"repositories"`
+}
+
+// LoadHistory loads the commit history of the current repository from the global
+// store in $XDG_STATE_HOME/gitmit, migrating history files left by older versions.
+// A corrupt store is backed up and replaced by an empty one.
+func LoadHistory(ctx context.Context) (*CommitHistory, error) {
+	path, err := storePath()
+	if err != nil {
+		return nil, err
+	}
+
+	key := RepositoryKey(ctx)
+	var store *Store
+	err = withStoreLock(path, func() error {
+		if store, err = readStore(path); err != nil {
+			return err
+		}
+		return migrateLegacyHistory(path, store, key, legacyHistoryPaths(ctx))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history, ok := store.Repositories[key]
+	if !ok {
+		history = &CommitHistory{Entries: []HistoryEntry{}}
+	}
+	history.key = key
+	history.loaded = history.clone()
+	history.subjects = loadSubjects(ctx, key)
+	history.recentSubjects = recentSubjectSet(history.subjects)
+	return history, nil
+}
+
+// SaveHistory saves the changes made to the commit history since it was
+// loaded into the global store, leaving the entries of other repositories
+// untouched. The store is locked while it is read and rewritten, and the
+// changes are merged into the history on disk, so concurrent runs in the same
+// repository do not lose each other's updates. The history then holds the
+// merged entries.
+func (h *CommitHistory) SaveHistory(ctx context.Context) error {
+	if h.key == "" {
+		h.key = RepositoryKey(ctx)
+	}
+
+	path, err := storePath()
+	if err != nil {
+		return err
+	}
+
+	return updateStore(path, func(store *Store) error {
+		current, ok := store.Repositories[h.key]
+		if !ok {
+			current = &CommitHistory{Entries: []HistoryEntry{}}
+			store.Repositories[h.key] = current
+		}
+		h.applyChanges(current)
+		h.Entries, h.TemplateStats, h.RuleStats, h.SourceStats, h.WIP = current.Entries, current.TemplateStats, current.RuleStats, current.SourceStats, current.WIP
+		h.loaded = current.clone()
+		return nil
+	})
+}
+
+// applyChanges applies the changes made to h since it was loaded to another
+// version of the same history: the entries added, the outcomes counted and
+// the checkpoints flagged or forgotten
+func (h *CommitHistory) applyChanges(to *CommitHistory) {
+	loaded := h.loaded
+	if loaded == nil {
+		loaded = &CommitHistory{}
+	}
+
+	seen := make(map[string]bool, len(loaded.Entries))
+	for _, entry := range loaded.Entries {
+		seen[entryKey(entry)] = true
+	}
+	added := &CommitHistory{}
+	for _, entry := range h.Entries {
+		if !seen[entryKey(entry)] {
+			added.Entries = append(added.Entries, entry)
+		}
+	}
+	to.merge(added)
+
+	to.TemplateStats = addStatsDelta(to.TemplateStats, h.TemplateStats, loaded.TemplateStats)
+	to.RuleStats = addStatsDelta(to.RuleStats, h.RuleStats, loaded.RuleStats)
+	to.SourceStats = addStatsDelta(to.SourceStats, h.SourceStats, loaded.SourceStats)
+
+	for _, hash := range h.WIP {
+		if !loaded.IsWIP(hash) {
+			to.AddWIP(hash)
+		}
+	}
+	var forgotten []string
+	for _, hash := range loaded.WIP {
+		if !h.IsWIP(hash) {
+			forgotten = append(forgotten, hash)
+		}
+	}
+	to.ForgetWIP(forgotten...)
+}
+
+// addStatsDelta adds the outcomes counted in now but not in before to into
+func addStatsDelta(into, now, before map[string]*TemplateStats) map[string]*TemplateStats {
+	for key, stats := range now {
+		if stats == nil {
+			continue
+		}
+		delta := *stats
+		if old := before[key]; old != nil {
+			delta.Accepted -= old.Accepted
+			delta.Edited -= old.Edited
+			delta.Rejected -= old.Rejected
+			delta.Regenerated -= old.Regenerated
+		}
+		if delta == (TemplateStats{}) {
+			continue
+		}
+		if into == nil {
+			into = make(map[string]*TemplateStats)
+		}
+		existing, ok := into[key]
+		if !ok {
+			existing = &TemplateStats{}
+			into[key] = existing
+		}
+		existing.Accepted += delta.Accepted
+		existing.Edited += delta.Edited
+		existing.Rejected += delta.Rejected
+		existing.Regenerated += delta.Regenerated
+	}
+	return into
+}
+
+// clone returns a copy of the stored fields of the history
+func (h *CommitHistory) clone() *CommitHistory {
+	return &CommitHistory{
+		Entries:       slices.Clone(h.Entries),
+		TemplateStats: cloneStats(h.TemplateStats),
+		RuleStats:     cloneStats(h.RuleStats),
+		SourceStats:   cloneStats(h.SourceStats),
+		WIP:           slices.Clone(h.WIP),
+	}
+}
+
+// cloneStats copies outcome counts
+func cloneStats(stats map[string]*TemplateStats) map[string]*TemplateStats {
+	if stats == nil {
+		return nil
+	}
+	c := make(map[string]*TemplateStats, len(stats))
+	for key, s := range stats {
+		if s != nil {
+			copied := *s
+			c[key] = &copied
+		}
+	}
+	return c
+}
+
+// updateStore changes the global store with fn while holding its lock, and
+// writes it back unless fn fails
+func updateStore(path string, fn func(*Store) error) error {
+	return withStoreLock(path, func() error {
+		store, err := readStore(path)
+		if err != nil {
+			return err
+		}
+		if err := fn(store); err != nil {
+			return err
+		}
+		return writeStore(path, store)
+	})
+}
+
+// RepositoryKey identifies the current repository in the global store. The
+// normalized origin URL is preferred so clones share history; otherwise the
+// common git directory is used so all worktrees of a repository share it.
+func RepositoryKey(ctx context.Context) string {
+	return repositoryKey(ctx, "")
+}
+
+// repositoryKey returns the key of the repository containing dir, or the
+// working directory when dir is empty
+func repositoryKey(ctx context.Context, dir string) string {
+	if remote := gitOutputIn(ctx, dir, "config", "--get", "remote.origin.url"); remote != "" {
+		return normalizeRemoteURL(remote)
+	}
+	if common := commonDir(ctx, dir); common != "" {
+		return filepath.ToSlash(common)
+	}
+	return filepath.ToSlash(repositoryRoot(ctx))
+}
+
+// commonDir returns the repository all worktrees of the repository containing
+// dir share: the directory holding the main .git directory, or the git
+// directory itself for bare repositories and GIT_DIR setups. It returns ""
+// outside a repository.
+func commonDir(ctx context.Context, dir string) string {
+	common := gitOutputIn(ctx, dir, "rev-parse", "--git-common-dir")
+	if common == "" {
+		return ""
+	}
+	// A relative common dir is relative to where git ran
+	if !filepath.IsAbs(common) {
+		base := dir
+		if base == "" {
+			wd, err := gitcmd.WorkDir(ctx)
+			if err != nil {
+				return ""
+			}
+			base = wd
+		}
+		common = filepath.Join(base, common)
+	}
+	common = filepath.Clean(common)
+	if filepath.Base(common) == ".git" {
+		common = filepath.Dir(common)
+	}
+	return common
+}
+
+var scpLikeRemote = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// normalizeRemoteURL reduces the different spellings of a remote to host/path,
+// e.g. git@github.com:a/b.git and https://github.com/a/b both become github.com/a/b
+func normalizeRemoteURL(remote string) string {
+	remote = strings.TrimSpace(remote)
+	if i := strings.Index(remote, "://"); i >= 0 {
+		remote = remote[i+3:]
+		if at := strings.Index(remote, "@"); at >= 0 && at < strings.Index(remote+"/", "/") {
+			remote = remote[at+1:]
+		}
+		// Drop an explicit port from the host
+		if slash := strings.Index(remote, "/"); slash >= 0 {
+			host := remote[:slash]
+			if colon := strings.Index(host, ":"); colon >= 0 {
+				host = host[:colon]
+			}
+			remote = host + remote[slash:]
+		}
+	} else if m := scpLikeRemote.FindStringSubmatch(remote); m != nil {
+		remote = m[1] + "/" + m[2]
+	}
+
+	remote = strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+	return strings.ToLower(remote)
+}
+
+// storePath returns the location of the global history store
+func storePath() (string, error) {
+	stateDir, err := xdg.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, storeFileName), nil
+}
+
+// withStoreLock runs fn holding the lock of the global store, a lock file next
+// to it, waiting up to storeLockTimeout for another run to release it
+func withStoreLock(path string, fn func() error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creating history directory: %w", err)
+	}
+	lockPath := path + ".lock"
+	lock, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening history lock %s: %w", lockPath, err)
+	}
+	defer lock.Close()
+
+	deadline := time.Now().Add(storeLockTimeout)
+	for {
+		locked, err := tryLock(lock)
+		if err != nil {
+			return fmt.Errorf("error locking history %s: %w", lockPath, err)
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("error locking history %s: another gitmit still holds it after %s", lockPath, storeLockTimeout)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return fn()
+}
+
+// readStore reads the global store, returning an empty store if it does not
+// exist. A store that cannot be parsed is moved aside to a backup and replaced
+// by an empty one, so a damaged file does not stop every command.
+func readStore(path string) (*Store, error) {
+	store := &Store{}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading commit history file %s: %w", path, err)
+	}
+	if err == nil && len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, store); err != nil {
+			backup, backupErr := backupStore(path)
+			if backupErr != nil {
+				return nil, fmt.Errorf("error unmarshaling commit history file %s: %w", path, err)
+			}
+			slog.Warn("commit history was corrupt and has been reset", "file", path, "backup", backup, "error", err)
+			store = &Store{}
+		}
+	}
+	if store.Repositories == nil {
+		store.Repositories = make(map[string]*CommitHistory)
+	}
+	return store, nil
+}
+
+// backupStore moves a corrupt store aside to a timestamped file next to it
+// and returns the path of the backup
+func backupStore(path string) (string, error) {
+	backup := fmt.Sprintf("%s.corrupt-%s", path, time.Now().Format("20060102T150405"))
+	if err := os.Rename(path, backup); err != nil {
+		return "", fmt.Errorf("error backing up commit history file %s: %w", path, err)
+	}
+	return backup, nil
+}
+
+// writeStore writes the global store, creating its directory if needed. The
+// store is written to a temporary file that replaces it, so a crash or a full
+// disk never leaves it half written.
+func writeStore(path string, store *Store) error {
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling commit history: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creating history directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), storeFileName+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error writing commit history file %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing commit history file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// legacyHistoryPaths returns the history files older versions wrote for the
+// current repository: the working-tree .commit_suggest_history.json and the
+// per-repository file under $XDG_STATE_HOME/gitmit/history
+func legacyHistoryPaths(ctx context.Context) []string {
+	repoRoot := repositoryRoot(ctx)
+	paths := []string{filepath.Join(repoRoot, legacyHistoryFileName)}
+	if stateDir, err := xdg.StateDir(); err == nil {
+		sum := sha256.Sum256([]byte(repoRoot))
+		name := fmt.Sprintf("%s-%s.json", filepath.Base(repoRoot), hex.EncodeToString(sum[:])[:12])
+		paths = append(paths, filepath.Join(stateDir, "history", name))
+	}
+	return paths
+}
+
+// migrateLegacyHistory imports legacy history files into the history of key
+// and writes the store. The files are removed only once the store holding
+// their entries is written, and files that cannot be parsed are left alone
+// with a warning.
+func migrateLegacyHistory(path string, store *Store, key string, legacyPaths []string) error {
+	var migrated []string
+	for _, legacyPath := range legacyPaths {
+		data, err := os.ReadFile(legacyPath)
+		if err != nil {
+			continue
+		}
+
+		var legacy CommitHistory
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			slog.Warn("legacy commit history could not be read and was not migrated", "file", legacyPath, "error", err)
+			continue
+		}
+
+		existing, ok := store.Repositories[key]
+		if !ok {
+			existing = &CommitHistory{Entries: []HistoryEntry{}}
+			store.Repositories[key] = existing
+		}
+		for _, entry := range legacy.Entries {
+			if !existing.Contains(entry.Message) {
+				existing.Entries = append(existing.Entries, entry)
+			}
+		}
+		existing.Prune()
+		migrated = append(migrated, legacyPath)
+	}
+	if len(migrated) == 0 {
+		return nil
+	}
+
+	if err := writeStore(path, store); err != nil {
+		return err
+	}
+	for _, legacyPath := range migrated {
+		if err := os.Remove(legacyPath); err != nil {
+			slog.Warn("migrated history file could not be removed", "file", legacyPath, "error", err)
+		}
+	}
+	return nil
+}
+
+// repositoryRoot returns the top-level directory of the current git repository,
+// falling back to the absolute working directory outside a repository
+func repositoryRoot(ctx context.Context) string {
+	if root := gitOutput(ctx, "rev-parse", "--show-toplevel"); root != "" {
+		return filepath.Clean(root)
+	}
+	if wd, err := gitcmd.WorkDir(ctx); err == nil {
+		return wd
+	}
+	return "."
+}
+
+// gitOutput runs a git command and returns its trimmed output, or "" on failure
+func gitOutput(ctx context.Context, args ...string) string {
+	return gitOutputIn(ctx, "", args...)
+}
+
+// gitOutputIn runs a git command in dir, or the working directory when dir is
+// empty, and returns its trimmed output, or "" on failure. The git timeout
+// bounds these quick lookups.
+func gitOutputIn(ctx context.Context, dir string, args ...string) string {
+	out, err := gitcmd.Output(ctx, dir, args...)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
@@ -0,0 +1,30 @@
+package history
+
+import "slices"
+
+// maxWIPCommits caps the checkpoint commits remembered per repository, so
+// checkpoints pushed without being folded do not pile up
+const maxWIPCommits = 100
+
+// AddWIP flags a commit as a checkpoint made by gitmit wip
+func (h *CommitHistory) AddWIP(hash string) {
+	if hash == "" || h.IsWIP(hash) {
+		return
+	}
+	h.WIP = append(h.WIP, hash)
+	if len(h.WIP) > maxWIPCommits {
+		h.WIP = h.WIP[len(h.WIP)-maxWIPCommits:]
+	}
+}
+
+// IsWIP reports whether a commit is flagged as a checkpoint
+func (h *CommitHistory) IsWIP(hash string) bool {
+	return slices.Contains(h.WIP, hash)
+}
+
+// ForgetWIP drops the flags of checkpoints that were folded or reworded
+func (h *CommitHistory) ForgetWIP(hashes ...string) {
+	h.WIP = slices.DeleteFunc(h.WIP, func(hash string) bool {
+		return slices.Contains(hashes, hash)
+	})
+}
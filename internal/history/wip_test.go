@@ -0,0 +1,35 @@
+package history
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestWIP(t *testing.T) {
+	h := &CommitHistory{}
+	h.AddWIP("a1")
+	h.AddWIP("b2")
+	h.AddWIP("a1")
+	if !slices.Equal(h.WIP, []string{"a1", "b2"}) || !h.IsWIP("b2") || h.IsWIP("c3") {
+		t.Fatalf("WIP = %v, want a1 and b2 once", h.WIP)
+	}
+
+	h.ForgetWIP("a1", "c3")
+	if !slices.Equal(h.WIP, []string{"b2"}) {
+		t.Errorf("WIP after ForgetWIP() = %v, want b2", h.WIP)
+	}
+
+	for i := range maxWIPCommits + 5 {
+		h.AddWIP(fmt.Sprintf("hash-%d", i))
+	}
+	if len(h.WIP) != maxWIPCommits || h.WIP[0] != "hash-5" {
+		t.Errorf("WIP holds %d commits from %s, want the latest %d", len(h.WIP), h.WIP[0], maxWIPCommits)
+	}
+
+	other := &CommitHistory{WIP: []string{"hash-104", "d4"}}
+	h.merge(other)
+	if len(h.WIP) != maxWIPCommits || h.WIP[len(h.WIP)-1] != "d4" {
+		t.Errorf("WIP after merge() = %v, want d4 added", h.WIP[len(h.WIP)-3:])
+	}
+}
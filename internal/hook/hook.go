@@ -0,0 +1,148 @@
+// Package hook runs the user's shell commands around a suggestion and its
+// commit. A hook reads a JSON Payload on stdin and may print it back changed
+// on stdout; a non-zero exit status vetoes what gitmit was about to do, with
+// what the hook wrote to stderr as the reason.
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
+)
+
+// Hooks, in the order they run for a commit
+const (
+	PreAnalyze  = "pre-analyze"  // Before the staged files are analyzed; may narrow the files
+	PostSuggest = "post-suggest" // After each suggestion; may change the message
+	PreCommit   = "pre-commit"   // Before committing; may change the message
+	PostCommit  = "post-commit"  // After committing; what it prints and its exit status are only reported
+)
+
+// maxReason bounds how much of what a hook writes to stderr a veto keeps
+const maxReason = 2 * 1024
+
+// waitDelay is how long a stopped hook may keep its output open, for example
+// through a process it started
+const waitDelay = time.Second
+
+// File is a staged file as hooks see it
+type File struct {
+	Path    string `json:"path"`
+	Action  string `json:"action"` // A, M, D or R
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+}
+
+// Analysis is what the analysis of the staged changes found
+type Analysis struct {
+	Action   string   `json:"action"`
+	Topic    string   `json:"topic,omitempty"`
+	Scope    string   `json:"scope,omitempty"`
+	Item     string   `json:"item,omitempty"`
+	Purpose  string   `json:"purpose,omitempty"`
+	Patterns []string `json:"patterns,omitempty"`
+	Breaking bool     `json:"breaking,omitempty"`
+}
+
+// Payload is what a hook reads on stdin and may print back changed. Only the
+// files (pre-analyze) and the message (post-suggest, pre-commit) are read back.
+type Payload struct {
+	Hook     string    `json:"hook"`
+	Branch   string    `json:"branch,omitempty"`
+	Files    []File    `json:"files,omitempty"`
+	Analysis *Analysis `json:"analysis,omitempty"`
+	Message  string    `json:"message,omitempty"`
+	Source   string    `json:"source,omitempty"` // Where the suggestion came from: template or ai
+	Commit   string    `json:"commit,omitempty"` // Hash of the new commit, in post-commit
+}
+
+// VetoError is a hook that exited with a non-zero status
+type VetoError struct {
+	Hook   string
+	Reason string // What the hook wrote to stderr, or its exit status
+}
+
+func (e *VetoError) Error() string {
+	return fmt.Sprintf("%s hook vetoed: %s", e.Hook, e.Reason)
+}
+
+// IsVeto reports whether err is a hook's veto
+func IsVeto(err error) bool {
+	var veto *VetoError
+	return errors.As(err, &veto)
+}
+
+// Run runs command with the shell on a payload, stopping it after timeout; 0
+// means no limit. It returns the payload with the hook's changes, which is the
+// payload unchanged when the hook prints nothing.
+func Run(ctx context.Context, command string, timeout time.Duration, in Payload) (Payload, error) {
+	input, err := json.Marshal(in)
+	if err != nil {
+		return in, fmt.Errorf("error encoding %s hook payload: %w", in.Hook, err)
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	cmd := shell(ctx, command)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(), "GITMIT_HOOK="+in.Hook)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = waitDelay
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		switch {
+		case ctx.Err() == context.DeadlineExceeded:
+			return in, fmt.Errorf("%s hook timed out after %s", in.Hook, timeout)
+		case errors.As(err, &exitErr):
+			reason := strings.TrimSpace(stderr.String())
+			if len(reason) > maxReason {
+				reason = "..." + reason[len(reason)-maxReason:]
+			}
+			if reason == "" {
+				reason = exitErr.Error()
+			}
+			return in, &VetoError{Hook: in.Hook, Reason: reason}
+		}
+		return in, fmt.Errorf("error running %s hook: %w", in.Hook, err)
+	}
+
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return in, nil
+	}
+	var printed Payload
+	if err := json.Unmarshal(stdout.Bytes(), &printed); err != nil {
+		return in, fmt.Errorf("error reading the output of %s hook: %w", in.Hook, err)
+	}
+	out := in
+	if printed.Files != nil {
+		out.Files = printed.Files
+	}
+	if printed.Message != "" {
+		out.Message = printed.Message
+	}
+	return out, nil
+}
+
+// shell returns the command run by the platform's shell
+func shell(ctx context.Context, command string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	cmd.Dir = gitcmd.Dir(ctx)
+	return cmd
+}
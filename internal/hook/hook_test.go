@@ -0,0 +1,53 @@
+package hook
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test hooks are sh commands")
+	}
+	ctx := context.Background()
+	in := Payload{Hook: PreCommit, Branch: "main", Message: "feat: add invoices"}
+
+	out, err := Run(ctx, "cat >/dev/null", time.Second, in)
+	if err != nil || !reflect.DeepEqual(out, in) {
+		t.Errorf("Run() of a silent hook = %+v, %v, want the payload unchanged", out, err)
+	}
+
+	// The hook may print back the message with changes, but not rename itself
+	out, err = Run(ctx, `sed -e 's/"message":"\([^"]*\)"/"message":"\1\\n\\nBuild: 42"/' -e 's/"hook":"pre-commit"/"hook":"other"/'`, time.Second, in)
+	if err != nil || out.Message != "feat: add invoices\n\nBuild: 42" || out.Hook != PreCommit {
+		t.Errorf("Run() of a changing hook = %+v, %v, want the build footer added", out, err)
+	}
+
+	out, err = Run(ctx, `echo "$GITMIT_HOOK: code freeze until Monday" >&2; exit 1`, time.Second, in)
+	if !IsVeto(err) || !strings.Contains(err.Error(), "pre-commit: code freeze until Monday") || out.Message != in.Message {
+		t.Errorf("Run() of a vetoing hook = %+v, %v, want a veto with the hook's reason", out, err)
+	}
+
+	if _, err := Run(ctx, "echo not json", time.Second, in); err == nil || IsVeto(err) {
+		t.Errorf("Run() of a hook printing text = %v, want an error that is not a veto", err)
+	}
+
+	if _, err := Run(ctx, "exec sleep 5", 100*time.Millisecond, in); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Run() of a slow hook = %v, want a timeout", err)
+	}
+}
+
+func TestRunNarrowsFiles(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test hooks are sh commands")
+	}
+	in := Payload{Hook: PreAnalyze, Files: []File{{Path: "a.go", Action: "M"}, {Path: "gen/b.pb.go", Action: "A"}}}
+	out, err := Run(context.Background(), `cat >/dev/null; echo '{"files": [{"path": "a.go", "action": "M"}]}'`, time.Second, in)
+	if err != nil || len(out.Files) != 1 || out.Files[0].Path != "a.go" {
+		t.Errorf("Run() = %+v, %v, want only a.go left", out, err)
+	}
+}
@@ -0,0 +1,36 @@
+// Package logging sets up the structured logger the other packages write to
+// with log/slog, so --verbose shows why gitmit suggested what it did.
+package logging
+
+import (
+	"io"
+	"log/slog"
+)
+
+// Level returns the lowest level logged at a verbosity: warnings by default,
+// the steps of a run with -v and every detail, such as git commands and
+// template scores, with -vv
+func Level(verbosity int) slog.Level {
+	switch {
+	case verbosity <= 0:
+		return slog.LevelWarn
+	case verbosity == 1:
+		return slog.LevelInfo
+	}
+	return slog.LevelDebug
+}
+
+// Setup makes the default slog logger write to w at the level of a
+// verbosity, without timestamps, which mean little for a single run
+func Setup(w io.Writer, verbosity int) {
+	handler := slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: Level(verbosity),
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	})
+	slog.SetDefault(slog.New(handler))
+}
@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSetup(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+
+	tests := []struct {
+		verbosity int
+		want      []string
+		skipped   []string
+	}{
+		{0, []string{"level=WARN"}, []string{"level=INFO", "level=DEBUG"}},
+		{1, []string{"level=WARN", "level=INFO"}, []string{"level=DEBUG"}},
+		{2, []string{"level=WARN", "level=INFO", "level=DEBUG"}, nil},
+		{5, []string{"level=DEBUG"}, nil},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		Setup(&out, tt.verbosity)
+		slog.Warn("warning")
+		slog.Info("step", "files", 2)
+		slog.Debug("detail")
+
+		log := out.String()
+		for _, want := range tt.want {
+			if !strings.Contains(log, want) {
+				t.Errorf("verbosity %d: log %q has no %s", tt.verbosity, log, want)
+			}
+		}
+		for _, skipped := range tt.skipped {
+			if strings.Contains(log, skipped) {
+				t.Errorf("verbosity %d: log %q has %s", tt.verbosity, log, skipped)
+			}
+		}
+		if strings.Contains(log, "time=") {
+			t.Errorf("verbosity %d: log %q has timestamps", tt.verbosity, log)
+		}
+	}
+}
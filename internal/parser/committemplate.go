@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
+)
+
+// DefaultCommentChar starts the comment lines of the messages git prepares
+// unless core.commentChar says otherwise
+const DefaultCommentChar = "#"
+
+// CommentChar returns what comment lines start with in the messages git
+// prepares for editors and hooks: core.commentString or core.commentChar, or
+// "#". With "auto" git picks a character no line of the message starts with,
+// which cannot be told from the configuration, so "#" is assumed.
+func CommentChar(ctx context.Context) string {
+	for _, key := range []string{"core.commentString", "core.commentChar"} {
+		out, err := gitcmd.Output(ctx, "", "config", "--get", key)
+		if value := strings.TrimRight(out, "\r\n"); err == nil && value != "" && value != "auto" {
+			return value
+		}
+	}
+	return DefaultCommentChar
+}
+
+// CommitTemplate returns the content of the file commit.template names, or ""
+// when it is not set. As for git, a relative path is relative to the top of
+// the working tree.
+func CommitTemplate(ctx context.Context) (string, error) {
+	out, err := gitcmd.Output(ctx, "", "config", "--path", "--get", "commit.template")
+	path := strings.TrimSpace(out)
+	if err != nil || path == "" {
+		return "", nil
+	}
+	if !filepath.IsAbs(path) {
+		if top, err := gitcmd.Output(ctx, "", "rev-parse", "--show-toplevel"); err == nil {
+			path = filepath.Join(strings.TrimSpace(top), path)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading commit.template: %w", err)
+	}
+	return string(data), nil
+}
@@ -2,12 +2,20 @@ package parser
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
-	"os/exec"
-	"path/filepath"
+	"log/slog"
+	"path"
 	"strings"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
 )
 
+// ErrNoStagedChanges is the error of commands that need staged changes when
+// nothing is staged
+var ErrNoStagedChanges = errors.New("no staged changes")
+
 // Change represents a single file change
 type Change struct {
 	File          string
@@ -35,9 +43,9 @@ func NewGitParser() *GitParser {
 }
 
 // ParseStagedChanges parses the staged changes from git using git status --porcelain
-func (p *GitParser) ParseStagedChanges() ([]*Change, error) {
+func (p *GitParser) ParseStagedChanges(ctx context.Context) ([]*Change, error) {
 	// Use git status --porcelain for more accurate file state detection
-	cmd := exec.Command("git", "status", "--porcelain")
+	cmd := gitcmd.Command(ctx, "status", "--porcelain")
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return nil, fmt.Errorf("error creating stdout pipe for git status: %w", err)
@@ -84,33 +92,15 @@ func (p *GitParser) ParseStagedChanges() ([]*Change, error) {
 			}
 		}
 
-		// Get the diff for the file using streaming
-		diffCmd := exec.Command("git", "diff", "--cached", "-U0", "--", change.File)
-		diffStdout, err := diffCmd.StdoutPipe()
-		if err == nil {
-			if err := diffCmd.Start(); err == nil {
-				diffScanner := bufio.NewScanner(diffStdout)
-				var diffBuilder strings.Builder
-				for diffScanner.Scan() {
-					diffLine := diffScanner.Text()
-					if strings.HasPrefix(diffLine, "+") && !strings.HasPrefix(diffLine, "+++") {
-						change.Added++
-					} else if strings.HasPrefix(diffLine, "-") && !strings.HasPrefix(diffLine, "---") {
-						change.Removed++
-					}
-					diffBuilder.WriteString(diffLine)
-					diffBuilder.WriteString("\n")
-				}
-				change.Diff = diffBuilder.String()
-				diffCmd.Wait()
-			}
+		// Get the diff for the file using streaming; a rename is diffed
+		// against its source, so that only the lines it changes count
+		args := []string{"diff", "--cached", "-U0", "--", change.File}
+		if change.IsRename {
+			args = []string{"diff", "--cached", "-U0", "-M", "--", change.Source, change.Target}
 		}
-
-		p.TotalAdded += change.Added
-		p.TotalRemoved += change.Removed
-
-		if (change.Added + change.Removed) >= 500 {
-			change.IsMajor = true
+		if err := p.readDiff(ctx, change, args...); err != nil {
+			cmd.Wait()
+			return nil, err
 		}
 
 		changes = append(changes, change)
@@ -119,13 +109,56 @@ func (p *GitParser) ParseStagedChanges() ([]*Change, error) {
 	if err := cmd.Wait(); err != nil {
 		return nil, fmt.Errorf("error waiting for git status: %w", err)
 	}
+	// Diffs cut short by Ctrl+C would make a partial analysis
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error reading staged changes: %w", err)
+	}
 
+	slog.Info("parsed staged changes", "files", len(changes), "added", p.TotalAdded, "removed", p.TotalRemoved)
 	return changes, nil
 }
 
+// readDiff streams the diff of a change from git, counting its added and removed lines
+func (p *GitParser) readDiff(ctx context.Context, change *Change, args ...string) error {
+	diffCmd := gitcmd.Command(ctx, args...)
+	diffStdout, err := diffCmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("error creating stdout pipe for git diff: %w", err)
+	}
+	if err := diffCmd.Start(); err != nil {
+		return fmt.Errorf("error starting git diff: %w", err)
+	}
+	diffScanner := bufio.NewScanner(diffStdout)
+	var diffBuilder strings.Builder
+	for diffScanner.Scan() {
+		diffLine := diffScanner.Text()
+		if strings.HasPrefix(diffLine, "+") && !strings.HasPrefix(diffLine, "+++") {
+			change.Added++
+		} else if strings.HasPrefix(diffLine, "-") && !strings.HasPrefix(diffLine, "---") {
+			change.Removed++
+		}
+		diffBuilder.WriteString(diffLine)
+		diffBuilder.WriteString("\n")
+	}
+	change.Diff = diffBuilder.String()
+	waitErr := diffCmd.Wait()
+
+	slog.Debug("read diff", "file", change.File, "action", change.Action, "added", change.Added, "removed", change.Removed)
+	p.TotalAdded += change.Added
+	p.TotalRemoved += change.Removed
+
+	if (change.Added + change.Removed) >= 500 {
+		change.IsMajor = true
+	}
+	if waitErr != nil {
+		return fmt.Errorf("error reading the diff of %s: %w", change.File, waitErr)
+	}
+	return nil
+}
+
 // GetCurrentBranch returns the name of the current git branch
-func (p *GitParser) GetCurrentBranch() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+func (p *GitParser) GetCurrentBranch(ctx context.Context) (string, error) {
+	cmd := gitcmd.Command(ctx, "rev-parse", "--abbrev-ref", "HEAD")
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return "", fmt.Errorf("error creating stdout pipe for rev-parse: %w", err)
@@ -150,5 +183,82 @@ func (p *GitParser) GetCurrentBranch() (string, error) {
 
 // getFileExtension returns the file extension of a given file path
 func getFileExtension(filename string) string {
-	return strings.TrimPrefix(filepath.Ext(filename), ".")
+	return strings.TrimPrefix(path.Ext(filename), ".")
+}
+
+// UnstagedFiles returns the tracked files with changes that are not staged and
+// the untracked files, as git status lists them
+func UnstagedFiles(ctx context.Context) (modified, untracked []string, err error) {
+	out, err := gitcmd.Output(ctx, "", "status", "--porcelain")
+	if err != nil {
+		return nil, nil, fmt.Errorf("error running git status: %w", err)
+	}
+	modified, untracked = parseUnstaged(out)
+	return modified, untracked, nil
+}
+
+// parseUnstaged splits git status --porcelain output into the tracked files
+// changed in the working tree and the untracked files
+func parseUnstaged(out string) (modified, untracked []string) {
+	for _, line := range strings.Split(out, "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		file := line[3:]
+		switch {
+		case line[:2] == "??":
+			untracked = append(untracked, file)
+		case line[1] != ' ':
+			modified = append(modified, file)
+		}
+	}
+	return modified, untracked
+}
+
+// StageFiles stages the changes to tracked files, and the untracked files too
+// when all is set
+func StageFiles(ctx context.Context, all bool) error {
+	args := []string{"add", "--update"}
+	if all {
+		args = []string{"add", "--all"}
+	}
+	if _, err := gitcmd.Output(ctx, "", args...); err != nil {
+		return fmt.Errorf("error staging files: %w", err)
+	}
+	return nil
+}
+
+// IndexTree writes the staged changes as a tree and returns its hash, so that
+// they can be staged again in parts with StageTreePaths or whole with
+// RestoreIndex
+func IndexTree(ctx context.Context) (string, error) {
+	out, err := gitcmd.Output(ctx, "", "write-tree")
+	if err != nil {
+		return "", fmt.Errorf("error writing the index: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// StageTreePaths stages the changes a tree makes to the given paths alone,
+// unstaging every other change
+func StageTreePaths(ctx context.Context, tree string, paths []string) error {
+	args := []string{"read-tree", "HEAD"}
+	if _, err := ResolveRevision(ctx, "HEAD"); err != nil {
+		args = []string{"read-tree", "--empty"}
+	}
+	if _, err := gitcmd.Output(ctx, "", args...); err != nil {
+		return fmt.Errorf("error unstaging changes: %w", err)
+	}
+	if _, err := gitcmd.Output(ctx, "", append([]string{"reset", "--quiet", tree, "--"}, paths...)...); err != nil {
+		return fmt.Errorf("error staging %s: %w", strings.Join(paths, ", "), err)
+	}
+	return nil
+}
+
+// RestoreIndex stages the changes of a tree IndexTree wrote again
+func RestoreIndex(ctx context.Context, tree string) error {
+	if _, err := gitcmd.Output(ctx, "", "read-tree", tree); err != nil {
+		return fmt.Errorf("error restoring the staged changes (git read-tree %s restores them): %w", tree, err)
+	}
+	return nil
 }
@@ -0,0 +1,17 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseUnstaged(t *testing.T) {
+	out := "M  staged.go\n M changed.go\nMM both.go\n D removed.go\nA  added.go\n?? new.go\n?? docs/\n"
+	modified, untracked := parseUnstaged(out)
+	if want := []string{"changed.go", "both.go", "removed.go"}; !reflect.DeepEqual(modified, want) {
+		t.Errorf("modified = %v, want %v", modified, want)
+	}
+	if want := []string{"new.go", "docs/"}; !reflect.DeepEqual(untracked, want) {
+		t.Errorf("untracked = %v, want %v", untracked, want)
+	}
+}
@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
+)
+
+// hunkHeaderRegex captures the old and new line ranges of a hunk header
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// Hunk is a range of lines a diff changes
+type Hunk struct {
+	Start int // First line, or the line after which lines are inserted when empty
+	Lines int
+}
+
+// Near reports whether two ranges overlap or are at most distance lines apart
+func (h Hunk) Near(other Hunk, distance int) bool {
+	return h.Start <= other.Start+max(other.Lines, 1)-1+distance &&
+		other.Start <= h.Start+max(h.Lines, 1)-1+distance
+}
+
+// OldHunks returns the ranges of the original file the diff of one file changes
+func OldHunks(diff string) []Hunk {
+	var hunks []Hunk
+	for _, line := range strings.Split(diff, "\n") {
+		if m := hunkHeaderRegex.FindStringSubmatch(line); m != nil {
+			hunks = append(hunks, hunk(m[1], m[2]))
+		}
+	}
+	return hunks
+}
+
+// CommitHunks returns the ranges each file has changed in its version after a
+// commit, by path
+func CommitHunks(ctx context.Context, hash string) (map[string][]Hunk, error) {
+	out, err := gitcmd.Output(ctx, "", "show", "-U0", "--format=", "--no-renames", hash)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the diff of %s: %w", hash, err)
+	}
+	return newHunks(out), nil
+}
+
+// newHunks returns the ranges of the new version of each file a diff changes
+func newHunks(diff string) map[string][]Hunk {
+	hunks := make(map[string][]Hunk)
+	file := ""
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+		case strings.HasPrefix(line, "@@"):
+			if m := hunkHeaderRegex.FindStringSubmatch(line); m != nil && file != "/dev/null" {
+				hunks[file] = append(hunks[file], hunk(m[3], m[4]))
+			}
+		}
+	}
+	return hunks
+}
+
+// hunk converts the start and optional length of a hunk header range
+func hunk(start, lines string) Hunk {
+	h := Hunk{Lines: 1}
+	h.Start, _ = strconv.Atoi(start)
+	if lines != "" {
+		h.Lines, _ = strconv.Atoi(lines)
+	}
+	return h
+}
+
+// UnpushedCommits returns the latest commits on HEAD that no remote branch
+// contains, newest first and at most limit. Merge commits are skipped.
+func UnpushedCommits(ctx context.Context, limit int) ([]*Commit, error) {
+	commits, err := parseLog(ctx, "--no-merges", fmt.Sprintf("--max-count=%d", limit), "HEAD", "--not", "--remotes")
+	if err != nil {
+		return nil, fmt.Errorf("error reading unpushed commits: %w", err)
+	}
+	return commits, nil
+}
+
+// RecentCommits returns the latest commits on HEAD, newest first and at most
+// limit. Merge commits are skipped.
+func RecentCommits(ctx context.Context, limit int) ([]*Commit, error) {
+	commits, err := parseLog(ctx, "--no-merges", fmt.Sprintf("--max-count=%d", limit), "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("error reading recent commits: %w", err)
+	}
+	return commits, nil
+}
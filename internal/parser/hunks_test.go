@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHunks(t *testing.T) {
+	diff := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -3 +3,2 @@ func A() {\n-x\n+y\n+z\n" +
+		"@@ -10,0 +11,4 @@\n+a\n" +
+		"diff --git a/gone.go b/gone.go\n--- a/gone.go\n+++ /dev/null\n@@ -1,2 +0,0 @@\n-x\n-y\n"
+
+	if got, want := OldHunks(diff), []Hunk{{3, 1}, {10, 0}, {1, 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("OldHunks() = %v, want %v", got, want)
+	}
+	if got, want := newHunks(diff), map[string][]Hunk{"a.go": {{3, 2}, {11, 4}}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("newHunks() = %v, want %v", got, want)
+	}
+}
+
+func TestHunkNear(t *testing.T) {
+	tests := []struct {
+		a, b     Hunk
+		distance int
+		want     bool
+	}{
+		{Hunk{10, 5}, Hunk{12, 1}, 0, true},
+		{Hunk{10, 5}, Hunk{15, 1}, 0, false},
+		{Hunk{10, 5}, Hunk{17, 1}, 3, true},
+		{Hunk{20, 0}, Hunk{10, 5}, 3, false},
+		{Hunk{16, 0}, Hunk{10, 5}, 3, true},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Near(tt.b, tt.distance); got != tt.want {
+			t.Errorf("%v.Near(%v, %d) = %v, want %v", tt.a, tt.b, tt.distance, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
+)
+
+// Merge is a merge in progress, as git merge leaves it when it stops for
+// conflicts or --no-commit
+type Merge struct {
+	Head      string   // Commit being merged, MERGE_HEAD
+	Subject   string   // Message git prepared, e.g. "Merge branch 'feature/login' into main"
+	Source    string   // What is merged, e.g. feature/login
+	Target    string   // Branch merged into, "" when git leaves it out
+	Conflicts []string // Files that had conflicts
+}
+
+// mergeSubjectRegex matches the subject git prepares for a merge, capturing
+// what is merged and the branch it is merged into
+var mergeSubjectRegex = regexp.MustCompile(`^Merge (?:remote-tracking branch|branch|branches|tag|commit) '([^']+)'(?:.*? into (\S+))?`)
+
+// CurrentMerge returns the merge in progress, or nil when there is none
+func CurrentMerge(ctx context.Context) (*Merge, error) {
+	head, err := ResolveRevision(ctx, "MERGE_HEAD")
+	if err != nil {
+		return nil, nil
+	}
+	merge := &Merge{Head: head}
+
+	out, err := gitcmd.Output(ctx, "", "rev-parse", "--git-path", "MERGE_MSG")
+	if err != nil {
+		return nil, fmt.Errorf("error locating the merge message: %w", err)
+	}
+	data, err := os.ReadFile(gitcmd.Path(ctx, strings.TrimSpace(out)))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading the merge message: %w", err)
+	}
+	parseMergeMessage(merge, string(data), CommentChar(ctx))
+	if merge.Source == "" {
+		merge.Source = head[:min(len(head), 7)]
+	}
+	return merge, nil
+}
+
+// parseMergeMessage reads what is merged and the files that had conflicts
+// from the message git prepared, MERGE_MSG. Git lists conflicts below a
+// "Conflicts:" line, commented out since git 2.x.
+func parseMergeMessage(merge *Merge, message, commentChar string) {
+	inConflicts := false
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimRight(line, "\r")
+		uncommented := strings.TrimPrefix(line, commentChar)
+		switch {
+		case merge.Subject == "" && line != "" && !strings.HasPrefix(line, commentChar):
+			merge.Subject = line
+			if m := mergeSubjectRegex.FindStringSubmatch(line); m != nil {
+				merge.Source, merge.Target = m[1], m[2]
+			}
+		case strings.TrimSpace(uncommented) == "Conflicts:":
+			inConflicts = true
+		case inConflicts && strings.HasPrefix(uncommented, "\t"):
+			merge.Conflicts = append(merge.Conflicts, strings.TrimSpace(uncommented))
+		default:
+			inConflicts = false
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMergeMessage(t *testing.T) {
+	tests := []struct {
+		name, message, commentChar string
+		want                       Merge
+	}{
+		{"commented conflicts",
+			"Merge branch 'feature/login'\n\n# Conflicts:\n#\tinternal/parser/git.go\n#\tinternal/templater/templater.go\n",
+			"#", Merge{Subject: "Merge branch 'feature/login'", Source: "feature/login",
+				Conflicts: []string{"internal/parser/git.go", "internal/templater/templater.go"}}},
+		{"conflicts of older git",
+			"Merge remote-tracking branch 'origin/main' into release/1.2\n\nConflicts:\n\tgo.mod\n",
+			"#", Merge{Subject: "Merge remote-tracking branch 'origin/main' into release/1.2", Source: "origin/main", Target: "release/1.2",
+				Conflicts: []string{"go.mod"}}},
+		{"custom comment char",
+			"; leading comment\nMerge tag 'v1.3.0' into develop\n\n; Conflicts:\n;\tCHANGELOG.md\n; trailing comment\n",
+			";", Merge{Subject: "Merge tag 'v1.3.0' into develop", Source: "v1.3.0", Target: "develop",
+				Conflicts: []string{"CHANGELOG.md"}}},
+		{"no conflicts", "Merge branch 'fix/typo' into main\n", "#",
+			Merge{Subject: "Merge branch 'fix/typo' into main", Source: "fix/typo", Target: "main"}},
+		{"custom message", "Bring in the new parser\n", "#", Merge{Subject: "Bring in the new parser"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Merge
+			parseMergeMessage(&got, tt.message, tt.commentChar)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseMergeMessage() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package parser
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// maxPatchLine bounds the length of a line of a patch, such as a minified file
+const maxPatchLine = 10 * 1024 * 1024
+
+// ErrEmptyPatch is the error of a patch without any file changes
+var ErrEmptyPatch = errors.New("the patch has no file changes")
+
+// ParsePatch parses the changes of a unified diff, as git diff, git show or
+// diff -u write it, the way ParseStagedChanges parses the index. Context lines
+// are left out of each change's diff, which then reads like the diffs of the
+// index, so the same analysis applies without a repository.
+func (p *GitParser) ParsePatch(r io.Reader) ([]*Change, error) {
+	var changes []*Change
+	var change *Change
+	var diff strings.Builder
+	hunks := 0
+	oldLeft, newLeft := 0, 0 // Lines of the current hunk still to read
+
+	finish := func() {
+		if change == nil || change.File == "" && change.Source == "" {
+			change = nil
+			diff.Reset()
+			return
+		}
+		change.Diff = diff.String()
+		if change.File == "" {
+			change.File = change.Source
+		}
+		if !change.IsRename && !change.IsCopy {
+			change.Source = "" // Only renames and copies have a source, as in the index
+		}
+		change.FileExtension = getFileExtension(change.File)
+		if change.Added+change.Removed >= 500 {
+			change.IsMajor = true
+		}
+		p.TotalAdded += change.Added
+		p.TotalRemoved += change.Removed
+		changes = append(changes, change)
+		change = nil
+		diff.Reset()
+	}
+	start := func(source, target string) {
+		finish()
+		change = &Change{Action: "M", Source: source, File: target}
+		hunks = 0
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxPatchLine)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+
+		if change != nil && (oldLeft > 0 || newLeft > 0) {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				change.Added++
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				change.Removed++
+				oldLeft--
+			case strings.HasPrefix(line, `\`):
+				continue
+			default:
+				// Context, left out as in the -U0 diffs of the index
+				oldLeft--
+				newLeft--
+				continue
+			}
+			diff.WriteString(line + "\n")
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			start(splitGitHeader(strings.TrimPrefix(line, "diff --git ")))
+		case strings.HasPrefix(line, "--- "):
+			path := patchPath(strings.TrimPrefix(line, "--- "))
+			// A plain unified diff starts each file with its --- line
+			if change == nil || hunks > 0 {
+				start(path, "")
+			}
+			if path == "" {
+				change.Action = "A"
+			} else {
+				change.Source = path
+			}
+		case change == nil:
+			continue // Text before the first file, such as a commit message
+		case strings.HasPrefix(line, "+++ "):
+			if path := patchPath(strings.TrimPrefix(line, "+++ ")); path == "" {
+				change.Action = "D"
+				change.File = change.Source
+			} else {
+				change.File = path
+			}
+		case strings.HasPrefix(line, "@@ "):
+			if m := hunkHeaderRegex.FindStringSubmatch(line); m != nil {
+				oldLeft, newLeft = hunk(m[1], m[2]).Lines, hunk(m[3], m[4]).Lines
+			}
+			hunks++
+		case strings.HasPrefix(line, `\`):
+			continue
+		case strings.HasPrefix(line, "new file mode"):
+			change.Action = "A"
+		case strings.HasPrefix(line, "deleted file mode"):
+			change.Action = "D"
+		case strings.HasPrefix(line, "rename from "), strings.HasPrefix(line, "copy from "):
+			change.Source = unquotePath(line[strings.Index(line, " from ")+6:])
+		case strings.HasPrefix(line, "rename to "), strings.HasPrefix(line, "copy to "):
+			change.Target = unquotePath(line[strings.Index(line, " to ")+4:])
+			change.File = change.Target
+			change.IsRename = strings.HasPrefix(line, "rename")
+			change.IsCopy = !change.IsRename
+			change.Action = "C"
+			if change.IsRename {
+				change.Action = "R"
+			}
+		}
+		diff.WriteString(line + "\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading patch: %w", err)
+	}
+	finish()
+
+	if len(changes) == 0 {
+		return nil, ErrEmptyPatch
+	}
+	slog.Info("parsed patch", "files", len(changes), "added", p.TotalAdded, "removed", p.TotalRemoved)
+	return changes, nil
+}
+
+// splitGitHeader returns the paths of a "diff --git a/x b/y" header, which are
+// only ambiguous when they contain " b/"; the ---, +++ and rename lines that
+// follow correct them
+func splitGitHeader(paths string) (string, string) {
+	if strings.HasPrefix(paths, `"`) {
+		if source, rest, ok := cutQuoted(paths); ok {
+			return stripPrefix(source), stripPrefix(unquotePath(strings.TrimSpace(rest)))
+		}
+	}
+	if i := strings.Index(paths, " b/"); i >= 0 {
+		return stripPrefix(paths[:i]), stripPrefix(paths[i+1:])
+	}
+	return "", ""
+}
+
+// cutQuoted splits a leading quoted path from the rest of s
+func cutQuoted(s string) (string, string, bool) {
+	prefix, err := strconv.QuotedPrefix(s)
+	if err != nil {
+		return "", "", false
+	}
+	path, err := strconv.Unquote(prefix)
+	return path, s[len(prefix):], err == nil
+}
+
+// patchPath returns the path of a --- or +++ line without its a/ or b/ prefix
+// and timestamp, or "" for /dev/null
+func patchPath(value string) string {
+	if i := strings.Index(value, "\t"); i >= 0 {
+		value = value[:i] // diff -u appends a timestamp
+	}
+	value = unquotePath(strings.TrimSpace(value))
+	if value == "/dev/null" {
+		return ""
+	}
+	return stripPrefix(value)
+}
+
+// unquotePath unquotes a path git quoted for its special characters
+func unquotePath(path string) string {
+	if strings.HasPrefix(path, `"`) {
+		if unquoted, err := strconv.Unquote(path); err == nil {
+			return unquoted
+		}
+	}
+	return path
+}
+
+// stripPrefix removes the a/ or b/ prefix git gives the paths of a diff
+func stripPrefix(path string) string {
+	if strings.HasPrefix(path, "a/") || strings.HasPrefix(path, "b/") {
+		return path[2:]
+	}
+	return path
+}
@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParsePatch(t *testing.T) {
+	patch := "From abc123 Mon Sep 17 00:00:00 2001\nSubject: [PATCH] Add invoices\n\n---\n" +
+		"diff --git a/api/user.go b/api/user.go\nindex 1111111..2222222 100644\n--- a/api/user.go\n+++ b/api/user.go\n" +
+		"@@ -1,4 +1,3 @@\n package api\n-func Old() {}\n+func New() {}\n \n-- kept\n" +
+		"diff --git a/db/schema.sql b/db/schema.sql\nnew file mode 100644\n--- /dev/null\n+++ b/db/schema.sql\n" +
+		"@@ -0,0 +1,2 @@\n+-- invoices\n+CREATE TABLE invoices (id int);\n\\ No newline at end of file\n" +
+		"diff --git a/old.go b/old.go\ndeleted file mode 100644\n--- a/old.go\n+++ /dev/null\n@@ -1 +0,0 @@\n-package old\n" +
+		"diff --git a/a b.go b/c.go\nsimilarity index 90%\nrename from a b.go\nrename to c.go\n" +
+		"diff --git a/logo.png b/logo.png\nnew file mode 100644\nBinary files /dev/null and b/logo.png differ\n"
+
+	p := &GitParser{}
+	changes, err := p.ParsePatch(strings.NewReader(patch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		file, action   string
+		added, removed int
+	}{
+		{"api/user.go", "M", 1, 2},
+		{"db/schema.sql", "A", 2, 0},
+		{"old.go", "D", 0, 1},
+		{"c.go", "R", 0, 0},
+		{"logo.png", "A", 0, 0},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("ParsePatch() = %d changes, want %d", len(changes), len(want))
+	}
+	for i, w := range want {
+		c := changes[i]
+		if c.File != w.file || c.Action != w.action || c.Added != w.added || c.Removed != w.removed {
+			t.Errorf("change %d = %s %s +%d -%d, want %s %s +%d -%d", i, c.Action, c.File, c.Added, c.Removed, w.action, w.file, w.added, w.removed)
+		}
+	}
+	if changes[3].Source != "a b.go" || !changes[3].IsRename {
+		t.Errorf("rename = %+v, want a rename of a b.go", changes[3])
+	}
+	if strings.Contains(changes[0].Diff, "package api") || !strings.Contains(changes[0].Diff, "+func New() {}") {
+		t.Errorf("Diff = %q, want the changed lines without context", changes[0].Diff)
+	}
+	if p.TotalAdded != 3 || p.TotalRemoved != 3 {
+		t.Errorf("totals = +%d -%d, want +3 -3", p.TotalAdded, p.TotalRemoved)
+	}
+}
+
+func TestParsePatchPlainDiff(t *testing.T) {
+	patch := "--- a.go.orig\t2024-06-30 12:00:00\n+++ a.go\t2024-06-30 12:01:00\n@@ -1 +1 @@\n-x\n+y\n" +
+		"--- docs/b.md\n+++ docs/b.md\n@@ -2,0 +3 @@\n+more\n"
+	changes, err := (&GitParser{}).ParsePatch(strings.NewReader(patch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 || changes[0].File != "a.go" || changes[1].File != "docs/b.md" || changes[1].Added != 1 {
+		t.Errorf("ParsePatch() = %+v, want a.go and docs/b.md", changes)
+	}
+
+	if _, err := (&GitParser{}).ParsePatch(strings.NewReader("no diff here\n")); !errors.Is(err, ErrEmptyPatch) {
+		t.Errorf("ParsePatch() of text = %v, want ErrEmptyPatch", err)
+	}
+}
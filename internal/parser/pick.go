@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
+)
+
+// Pick is a commit git cherry-pick or git rebase stopped applying, for
+// conflicts or --no-commit, whose changes are staged to commit again
+type Pick struct {
+	Kind      string  // "cherry-pick" or "rebase"
+	Commit    *Commit // Commit being applied
+	Conflicts []string
+}
+
+// CurrentPick returns the commit a cherry-pick or rebase in progress is
+// applying, or nil when there is none. A rebase stopped at a commit it has
+// already applied, such as for edit, is not applying one.
+func CurrentPick(ctx context.Context) (*Pick, error) {
+	pick := &Pick{Kind: "cherry-pick"}
+	hash, err := ResolveRevision(ctx, "CHERRY_PICK_HEAD")
+	if err != nil {
+		pick.Kind = "rebase"
+		if hash, err = ResolveRevision(ctx, "REBASE_HEAD"); err != nil {
+			return nil, nil
+		}
+		if head, err := ResolveRevision(ctx, "HEAD"); err == nil && head == hash {
+			return nil, nil
+		}
+	}
+
+	commits, err := ParseCommitList(ctx, []string{hash})
+	if err != nil {
+		return nil, fmt.Errorf("error reading the commit the %s is applying: %w", pick.Kind, err)
+	}
+	if len(commits) != 1 {
+		return nil, fmt.Errorf("error reading the commit the %s is applying: %s not found", pick.Kind, hash)
+	}
+	pick.Commit = commits[0]
+
+	// Git lists the conflicts below the original message in MERGE_MSG
+	out, err := gitcmd.Output(ctx, "", "rev-parse", "--git-path", "MERGE_MSG")
+	if err != nil {
+		return nil, fmt.Errorf("error locating the %s message: %w", pick.Kind, err)
+	}
+	data, err := os.ReadFile(gitcmd.Path(ctx, strings.TrimSpace(out)))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading the %s message: %w", pick.Kind, err)
+	}
+	var merge Merge
+	parseMergeMessage(&merge, string(data), CommentChar(ctx))
+	pick.Conflicts = merge.Conflicts
+	return pick, nil
+}
+
+// Body returns the body of the message the commit is applied again with: the
+// body of the original message followed by the files whose conflicts the
+// commit resolves
+func (p *Pick) Body() string {
+	notes := ConflictNotes(p.Conflicts)
+	switch {
+	case p.Commit.Body == "":
+		return notes
+	case notes == "":
+		return p.Commit.Body
+	}
+	return p.Commit.Body + "\n\n" + notes
+}
+
+// ConflictNotes lists the files whose conflicts a commit resolves, or returns
+// "" when there are none
+func ConflictNotes(files []string) string {
+	if len(files) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Conflicts resolved in:")
+	for _, file := range files {
+		b.WriteString("\n- " + file)
+	}
+	return b.String()
+}
@@ -0,0 +1,26 @@
+package parser
+
+import "testing"
+
+func TestPickBody(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		conflicts []string
+		want      string
+	}{
+		{"body and conflicts", "Longer explanation.", []string{"f.txt", "internal/parser/git.go"},
+			"Longer explanation.\n\nConflicts resolved in:\n- f.txt\n- internal/parser/git.go"},
+		{"conflicts only", "", []string{"f.txt"}, "Conflicts resolved in:\n- f.txt"},
+		{"body only", "Longer explanation.", nil, "Longer explanation."},
+		{"neither", "", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pick := &Pick{Kind: "cherry-pick", Commit: &Commit{Subject: "Fix the b line", Body: tt.body}, Conflicts: tt.conflicts}
+			if got := pick.Body(); got != tt.want {
+				t.Errorf("Body() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
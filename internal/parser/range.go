@@ -0,0 +1,439 @@
+package parser
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
+)
+
+// Separators of the fields and records of git log output
+const (
+	fieldSeparator  = "\x1f"
+	recordSeparator = "\x1e"
+)
+
+// logFormat is the git log format parseRecord reads, followed by the changed files
+var logFormat = "--format=" + recordSeparator + strings.Join([]string{"%H", "%an", "%ae", "%aI", "%s", "%b", ""}, fieldSeparator)
+
+// Commit is a commit in a range of history
+type Commit struct {
+	Hash    string
+	Author  string
+	Email   string
+	Date    time.Time // Author date
+	Subject string
+	Body    string
+	Files   []string          // Files changed by the commit
+	Renamed map[string]string // New path of each file the commit renamed, by old path, when renames are detected
+}
+
+// ShortHash returns the abbreviated commit hash
+func (c *Commit) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// ParseCommits returns the commits reachable from head but not from base, oldest first,
+// or all commits reachable from head when base is empty. Merge commits are skipped.
+func ParseCommits(ctx context.Context, base, head string) ([]*Commit, error) {
+	rev := head
+	if base != "" {
+		rev = base + ".." + head
+	}
+	commits, err := parseLog(ctx, "--reverse", "--no-merges", rev)
+	if err != nil {
+		return nil, fmt.Errorf("error reading commits %s..%s: %w", base, head, err)
+	}
+	return commits, nil
+}
+
+// ParseCommitList returns the given commits in the given order
+func ParseCommitList(ctx context.Context, revs []string) ([]*Commit, error) {
+	if len(revs) == 0 {
+		return nil, nil
+	}
+	commits, err := parseLog(ctx, append([]string{"--no-walk=unsorted"}, revs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("error reading commits: %w", err)
+	}
+	return commits, nil
+}
+
+// ParseAuthorCommits returns the commits of an author on any local branch of the
+// repository in dir since a date git understands, such as "yesterday", oldest first
+func ParseAuthorCommits(ctx context.Context, dir, since, author string) ([]*Commit, error) {
+	commits, err := parseLogIn(ctx, dir, "--reverse", "--no-merges", "--branches", "--since="+since, "--author="+author)
+	if err != nil {
+		return nil, fmt.Errorf("error reading commits since %s: %w", since, err)
+	}
+	return commits, nil
+}
+
+// HistoryOptions selects the commits StreamHistory reads
+type HistoryOptions struct {
+	Since      string // Date git understands, such as "2024-01-01" or "3 months ago"
+	Until      string
+	Author     string // Pattern matched against author names and emails
+	MaxCommits int    // Number of most recent commits read, or all when 0
+	Renames    bool   // Detect renamed files, listed in Commit.Renamed
+	NoMerges   bool   // Skip merge commits
+}
+
+// StreamHistory calls fn with each commit reachable from HEAD the options select,
+// newest first, as git lists them. Commits are not kept in memory, so histories of
+// any size can be read; an error from fn stops git and is returned.
+func StreamHistory(ctx context.Context, opts HistoryOptions, fn func(*Commit) error) error {
+	args := []string{"log", "--name-only", logFormat, "HEAD"}
+	if opts.Renames {
+		args = []string{"log", "--name-status", "-M", logFormat, "HEAD"}
+	}
+	if opts.Since != "" {
+		args = append(args, "--since="+opts.Since)
+	}
+	if opts.Until != "" {
+		args = append(args, "--until="+opts.Until)
+	}
+	if opts.Author != "" {
+		args = append(args, "--author="+opts.Author)
+	}
+	if opts.NoMerges {
+		args = append(args, "--no-merges")
+	}
+	if opts.MaxCommits > 0 {
+		args = append(args, fmt.Sprintf("--max-count=%d", opts.MaxCommits))
+	}
+
+	// The time git log takes grows with the history, so only ctx bounds it
+	cmd := gitcmd.LongCommand(ctx, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("error reading history: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("error reading history: %w", err)
+	}
+
+	reader := bufio.NewReaderSize(stdout, 64*1024)
+	for {
+		record, readErr := reader.ReadString(recordSeparator[0])
+		if commit := parseRecord(strings.TrimSuffix(record, recordSeparator)); commit != nil {
+			if err := fn(commit); err != nil {
+				_ = cmd.Process.Kill()
+				_ = cmd.Wait()
+				return err
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+			return fmt.Errorf("error reading history: %w", readErr)
+		}
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("error reading history: %w", err)
+	}
+	return nil
+}
+
+// parseLog runs git log with the given arguments and parses the commits it lists
+func parseLog(ctx context.Context, args ...string) ([]*Commit, error) {
+	return parseLogIn(ctx, "", args...)
+}
+
+// parseLogIn runs git log in dir, or the working directory when dir is empty
+func parseLogIn(ctx context.Context, dir string, args ...string) ([]*Commit, error) {
+	out, err := gitcmd.Output(ctx, dir, append([]string{"log", "--name-only", logFormat}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []*Commit
+	for _, record := range strings.Split(out, recordSeparator) {
+		if commit := parseRecord(record); commit != nil {
+			commits = append(commits, commit)
+		}
+	}
+	return commits, nil
+}
+
+// parseRecord parses a commit listed in logFormat, or returns nil when the record
+// is incomplete, such as the empty text before the first separator
+func parseRecord(record string) *Commit {
+	fields := strings.SplitN(record, fieldSeparator, 7)
+	if len(fields) < 7 {
+		return nil
+	}
+	commit := &Commit{
+		Hash:    strings.TrimSpace(fields[0]),
+		Author:  fields[1],
+		Email:   fields[2],
+		Subject: strings.TrimSpace(fields[4]),
+		Body:    strings.TrimSpace(fields[5]),
+	}
+	commit.Date, _ = time.Parse(time.RFC3339, fields[3])
+	for _, line := range strings.Split(fields[6], "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		// With --name-status, files follow their status, and renames list both paths
+		status := strings.Split(line, "\t")
+		if len(status) == 1 {
+			commit.Files = append(commit.Files, line)
+			continue
+		}
+		file := status[len(status)-1]
+		if strings.HasPrefix(status[0], "R") && len(status) == 3 {
+			if commit.Renamed == nil {
+				commit.Renamed = make(map[string]string)
+			}
+			commit.Renamed[status[1]] = file
+		}
+		commit.Files = append(commit.Files, file)
+	}
+	return commit
+}
+
+// ResolveRevision returns the commit hash a revision names
+func ResolveRevision(ctx context.Context, rev string) (string, error) {
+	out, err := gitcmd.Output(ctx, "", "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %s", rev)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// LatestTag returns the most recent tag reachable from a revision, or "" when
+// there is none
+func LatestTag(ctx context.Context, rev string) string {
+	out, err := gitcmd.Output(ctx, "", "describe", "--tags", "--abbrev=0", rev)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// CheckNewTag returns an error if name is not a valid tag name or the tag exists
+func CheckNewTag(ctx context.Context, name string) error {
+	if _, err := gitcmd.Output(ctx, "", "check-ref-format", "refs/tags/"+name); err != nil {
+		return fmt.Errorf("invalid tag name %q", name)
+	}
+	if _, err := gitcmd.Output(ctx, "", "rev-parse", "--verify", "--quiet", "refs/tags/"+name); err == nil {
+		return fmt.Errorf("tag %s already exists", name)
+	}
+	return nil
+}
+
+// CreateTag creates an annotated tag on HEAD
+func CreateTag(ctx context.Context, name, message string) error {
+	if _, err := gitcmd.Output(ctx, "", "tag", "--annotate", name, "--message", message); err != nil {
+		return fmt.Errorf("error creating tag %s: %w", name, err)
+	}
+	return nil
+}
+
+// ParseRangeChanges parses the cumulative changes between two revisions, the way
+// ParseStagedChanges parses the index
+func (p *GitParser) ParseRangeChanges(ctx context.Context, base, head string) ([]*Change, error) {
+	changes, err := p.parseDiffChanges(ctx, base, head)
+	if err != nil {
+		return nil, fmt.Errorf("error diffing %s and %s: %w", base, head, err)
+	}
+	return changes, nil
+}
+
+// ParseAmendChanges parses the changes HEAD and the staged changes make together,
+// i.e. the changes of HEAD once amended with the index
+func (p *GitParser) ParseAmendChanges(ctx context.Context) ([]*Change, error) {
+	parent := emptyTree
+	if out, err := gitcmd.Output(ctx, "", "rev-parse", "--verify", "--quiet", "HEAD^"); err == nil {
+		parent = strings.TrimSpace(out)
+	}
+	changes, err := p.parseDiffChanges(ctx, "--cached", parent)
+	if err != nil {
+		return nil, fmt.Errorf("error diffing the index and %s: %w", parent, err)
+	}
+	return changes, nil
+}
+
+// parseDiffChanges parses the changes git diff lists for the given revisions
+func (p *GitParser) parseDiffChanges(ctx context.Context, revs ...string) ([]*Change, error) {
+	out, err := gitcmd.Output(ctx, "", append([]string{"diff", "--name-status", "-M"}, revs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []*Change
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 || fields[0] == "" {
+			continue
+		}
+
+		// Renames and copies carry a similarity score, e.g. R097
+		action := fields[0][:1]
+		change := &Change{
+			File:          fields[len(fields)-1],
+			Action:        action,
+			FileExtension: getFileExtension(fields[len(fields)-1]),
+		}
+		paths := []string{change.File}
+		if (action == "R" || action == "C") && len(fields) == 3 {
+			change.IsRename = action == "R"
+			change.IsCopy = action == "C"
+			change.Source = fields[1]
+			change.Target = fields[2]
+			paths = []string{change.Source, change.Target}
+		}
+
+		args := append(append([]string{"diff", "-U0", "-M"}, revs...), "--")
+		if err := p.readDiff(ctx, change, append(args, paths...)...); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// IsPushed reports whether a remote-tracking branch contains a revision
+func IsPushed(ctx context.Context, rev string) (bool, error) {
+	out, err := gitcmd.Output(ctx, "", "branch", "--remotes", "--contains", rev)
+	if err != nil {
+		return false, fmt.Errorf("error finding the branches containing %s: %w", rev, err)
+	}
+	return strings.TrimSpace(out) != "", nil
+}
+
+// emptyTree is the hash of git's empty tree, which root commits are diffed against
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// ParseCommitChanges parses the changes a commit made to its first parent
+func (p *GitParser) ParseCommitChanges(ctx context.Context, hash string) ([]*Change, error) {
+	parent := emptyTree
+	if out, err := gitcmd.Output(ctx, "", "rev-parse", "--verify", "--quiet", hash+"^"); err == nil {
+		parent = strings.TrimSpace(out)
+	}
+	return p.ParseRangeChanges(ctx, parent, hash)
+}
+
+// ParseShowChanges parses the changes of a commit from the single diff git show
+// prints, rather than a diff per file as ParseCommitChanges does, which suits
+// walking many commits. Merge commits are diffed against their first parent.
+func (p *GitParser) ParseShowChanges(ctx context.Context, hash string) ([]*Change, error) {
+	// The prefixes are set since diff.noprefix and diff.mnemonicPrefix change them
+	out, err := gitcmd.Output(ctx, "", "show", "--format=", "-U0", "-M", "-m", "--first-parent", "--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/", hash)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the diff of %s: %w", hash, err)
+	}
+	changes, err := p.ParsePatch(strings.NewReader(out))
+	if errors.Is(err, ErrEmptyPatch) {
+		return nil, nil
+	}
+	return changes, err
+}
+
+// MergeBase returns the best common ancestor of two revisions
+func MergeBase(ctx context.Context, a, b string) (string, error) {
+	out, err := gitcmd.Output(ctx, "", "merge-base", a, b)
+	if err != nil {
+		return "", fmt.Errorf("error finding the merge base of %s and %s: %w", a, b, err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// DefaultBase returns the branch changes are usually merged into: the default
+// branch of origin, or a local or remote main or master branch
+func DefaultBase(ctx context.Context) (string, error) {
+	if out, err := gitcmd.Output(ctx, "", "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		return strings.TrimSpace(out), nil
+	}
+	for _, candidate := range []string{"main", "master", "origin/main", "origin/master"} {
+		if _, err := gitcmd.Output(ctx, "", "rev-parse", "--verify", "--quiet", candidate+"^{commit}"); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("could not find a base branch (tried origin/HEAD, main and master)")
+}
+
+// RepoRoot returns the top-level directory of the repository containing dir
+func RepoRoot(ctx context.Context, dir string) (string, error) {
+	out, err := gitcmd.Output(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("%s is not in a git repository: %w", dir, err)
+	}
+	return filepath.Clean(strings.TrimSpace(out)), nil
+}
+
+// IndexFile returns the path of the index of the repository containing dir,
+// which linked worktrees keep outside the working tree
+func IndexFile(ctx context.Context, dir string) (string, error) {
+	out, err := gitcmd.Output(ctx, dir, "rev-parse", "--git-path", "index")
+	if err != nil {
+		return "", fmt.Errorf("%s is not in a git repository: %w", dir, err)
+	}
+	path := filepath.FromSlash(strings.TrimSpace(out))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// RemoteWebURL returns the web URL of the repository a remote points to, e.g.
+// https://github.com/owner/repo for git@github.com:owner/repo.git
+func RemoteWebURL(ctx context.Context, remote string) (string, error) {
+	out, err := gitcmd.Output(ctx, "", "remote", "get-url", remote)
+	if err != nil {
+		return "", fmt.Errorf("error reading the URL of remote %s: %w", remote, err)
+	}
+	return webURL(strings.TrimSpace(out)), nil
+}
+
+// webURL converts an ssh or https clone URL to the repository's web URL, or
+// returns "" for URLs that are not hosted, such as local paths
+func webURL(remote string) string {
+	url := strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+	switch {
+	case strings.HasPrefix(url, "https://"), strings.HasPrefix(url, "http://"):
+		// Drop credentials, e.g. https://user@host/owner/repo
+		scheme, rest, _ := strings.Cut(url, "://")
+		if at := strings.Index(rest, "@"); at >= 0 && at < strings.Index(rest+"/", "/") {
+			rest = rest[at+1:]
+		}
+		return scheme + "://" + rest
+	case strings.HasPrefix(url, "ssh://"):
+		rest := strings.TrimPrefix(url, "ssh://")
+		if _, host, ok := strings.Cut(rest, "@"); ok {
+			rest = host
+		}
+		host, path, _ := strings.Cut(rest, "/")
+		host, _, _ = strings.Cut(host, ":")
+		return "https://" + host + "/" + path
+	case strings.Contains(url, "@") && strings.Contains(url, ":"):
+		_, rest, _ := strings.Cut(url, "@")
+		host, path, _ := strings.Cut(rest, ":")
+		return "https://" + host + "/" + path
+	}
+	return ""
+}
+
+// UserEmail returns the email git records as the author in the repository in dir
+func UserEmail(ctx context.Context, dir string) (string, error) {
+	out, err := gitcmd.Output(ctx, dir, "config", "user.email")
+	if err != nil || strings.TrimSpace(out) == "" {
+		return "", fmt.Errorf("user.email is not set in git config")
+	}
+	return strings.TrimSpace(out), nil
+}
@@ -0,0 +1,146 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
+)
+
+func TestParseRecord(t *testing.T) {
+	record := strings.Join([]string{"abc123", "Ada Lovelace", "ada@example.com", "2024-06-30T12:00:00+02:00", "fix(api): handle nil", "Closes #1\n", "\n\napi/user.go\napi/user_test.go\n"}, fieldSeparator)
+	commit := parseRecord(record)
+	if commit == nil {
+		t.Fatal("parseRecord() = nil")
+	}
+	if commit.Author != "Ada Lovelace" || commit.Email != "ada@example.com" || commit.Subject != "fix(api): handle nil" || commit.Body != "Closes #1" {
+		t.Errorf("parseRecord() = %+v", commit)
+	}
+	if want := time.Date(2024, 6, 30, 10, 0, 0, 0, time.UTC); !commit.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", commit.Date, want)
+	}
+	if len(commit.Files) != 2 || commit.Files[1] != "api/user_test.go" {
+		t.Errorf("Files = %v", commit.Files)
+	}
+
+	record = strings.Join([]string{"def456", "Bob", "bob@example.com", "2024-06-30T12:00:00Z", "refactor: move user", "", "\n\nR097\tapi/user.go\tinternal/user.go\nM\tgo.mod\n"}, fieldSeparator)
+	commit = parseRecord(record)
+	if len(commit.Files) != 2 || commit.Files[0] != "internal/user.go" || commit.Files[1] != "go.mod" {
+		t.Errorf("Files with status = %v", commit.Files)
+	}
+	if commit.Renamed["api/user.go"] != "internal/user.go" || len(commit.Renamed) != 1 {
+		t.Errorf("Renamed = %v", commit.Renamed)
+	}
+
+	if parseRecord("") != nil {
+		t.Error("parseRecord(\"\") should be nil")
+	}
+}
+
+func TestWebURL(t *testing.T) {
+	tests := map[string]string{
+		"git@github.com:andev0x/gitmit.git":            "https://github.com/andev0x/gitmit",
+		"https://github.com/andev0x/gitmit.git":        "https://github.com/andev0x/gitmit",
+		"https://token@gitlab.com/group/sub/repo":      "https://gitlab.com/group/sub/repo",
+		"ssh://git@gitlab.example.com:2222/group/repo": "https://gitlab.example.com/group/repo",
+		"/srv/git/repo.git":                            "",
+	}
+	for remote, want := range tests {
+		if got := webURL(remote); got != want {
+			t.Errorf("webURL(%q) = %q, want %q", remote, got, want)
+		}
+	}
+}
+
+func TestParseShowChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	dir := t.TempDir()
+	ctx := gitcmd.WithDir(context.Background(), dir)
+	git := func(args ...string) string {
+		t.Helper()
+		out, err := gitcmd.Output(ctx, "", args...)
+		if err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+		return strings.TrimSpace(out)
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Prefix settings must not change how the diff is read
+	git("init", "-q", "-b", "main")
+	git("config", "diff.noprefix", "true")
+
+	write("user.go", "package user\n\nfunc Name() string {\n\treturn \"ada\"\n}\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "feat: add user")
+	added := git("rev-parse", "HEAD")
+
+	write("user.go", "package user\n\nfunc Name() string {\n\treturn \"bob\"\n}\n")
+	write("notes.txt", "notes\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "fix: rename user")
+	modified := git("rev-parse", "HEAD")
+
+	git("mv", "user.go", "person.go")
+	git("rm", "-q", "notes.txt")
+	git("commit", "-q", "-m", "refactor: move user")
+	moved := git("rev-parse", "HEAD")
+
+	git("commit", "-q", "--allow-empty", "-m", "chore: nothing")
+	empty := git("rev-parse", "HEAD")
+
+	git("checkout", "-q", "-b", "side", added)
+	write("side.go", "package user\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "feat: add side")
+	git("checkout", "-q", "main")
+	git("merge", "-q", "--no-edit", "side")
+	merge := git("rev-parse", "HEAD")
+
+	tests := []struct {
+		name string
+		rev  string
+		want []string // Action, file and line counts of each change
+	}{
+		{name: "added", rev: added, want: []string{"A user.go +5 -0"}},
+		{name: "modified and added", rev: modified, want: []string{"A notes.txt +1 -0", "M user.go +1 -1"}},
+		{name: "renamed and deleted", rev: moved, want: []string{"D notes.txt +0 -1", "R person.go +0 -0"}},
+		{name: "empty", rev: empty},
+		{name: "merge against its first parent", rev: merge, want: []string{"A side.go +1 -0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := NewGitParser().ParseShowChanges(ctx, tt.rev)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, change := range changes {
+				got = append(got, fmt.Sprintf("%s %s +%d -%d", change.Action, change.File, change.Added, change.Removed))
+			}
+			sort.Strings(got)
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("ParseShowChanges(%s) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
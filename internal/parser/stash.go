@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
+)
+
+// Stash is an entry of the stash list
+type Stash struct {
+	Ref     string // e.g. stash@{0}
+	Hash    string
+	Message string // e.g. "WIP on main: 1d09baa chore: init"
+}
+
+// wipStashRegex matches the message git gives stashes pushed without one,
+// capturing the branch
+var wipStashRegex = regexp.MustCompile(`^WIP on (.+?): [0-9a-f]+ `)
+
+// StashBranch returns the branch a stash was made on, from its message
+func (s *Stash) StashBranch() string {
+	if m := wipStashRegex.FindStringSubmatch(s.Message); m != nil {
+		return m[1]
+	}
+	if rest, ok := strings.CutPrefix(s.Message, "On "); ok {
+		if branch, _, ok := strings.Cut(rest, ": "); ok {
+			return branch
+		}
+	}
+	return ""
+}
+
+// IsWIP reports whether the stash still has the message git gives stashes
+// pushed without one
+func (s *Stash) IsWIP() bool {
+	return wipStashRegex.MatchString(s.Message)
+}
+
+// ListStashes returns the stash list, newest first
+func ListStashes(ctx context.Context) ([]*Stash, error) {
+	out, err := gitcmd.Output(ctx, "", "stash", "list", "--format=%gd"+fieldSeparator+"%H"+fieldSeparator+"%gs")
+	if err != nil {
+		return nil, fmt.Errorf("error listing stashes: %w", err)
+	}
+	return parseStashList(out), nil
+}
+
+// parseStashList parses git stash list output in the format ListStashes uses
+func parseStashList(out string) []*Stash {
+	var stashes []*Stash
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, fieldSeparator, 3)
+		if len(fields) < 3 {
+			continue
+		}
+		stashes = append(stashes, &Stash{Ref: fields[0], Hash: fields[1], Message: fields[2]})
+	}
+	return stashes
+}
+
+// ParseStashChanges parses the changes a stash holds: its working tree changes
+// against the commit it was made on, and its untracked files, if any
+func (p *GitParser) ParseStashChanges(ctx context.Context, ref string) ([]*Change, error) {
+	changes, err := p.parseDiffChanges(ctx, ref+"^1", ref)
+	if err != nil {
+		return nil, fmt.Errorf("error diffing %s: %w", ref, err)
+	}
+	// Stashes pushed with --include-untracked keep those files in a third parent
+	if _, err := gitcmd.Output(ctx, "", "rev-parse", "--verify", "--quiet", ref+"^3"); err == nil {
+		untracked, err := p.parseDiffChanges(ctx, emptyTree, ref+"^3")
+		if err != nil {
+			return nil, fmt.Errorf("error diffing the untracked files of %s: %w", ref, err)
+		}
+		changes = append(changes, untracked...)
+	}
+	return changes, nil
+}
+
+// ParseWorkingTreeChanges parses the staged and unstaged changes against HEAD,
+// the changes git stash would save, and the untracked files when untracked is set
+func (p *GitParser) ParseWorkingTreeChanges(ctx context.Context, untracked bool) ([]*Change, error) {
+	head := emptyTree
+	if out, err := gitcmd.Output(ctx, "", "rev-parse", "--verify", "--quiet", "HEAD"); err == nil {
+		head = strings.TrimSpace(out)
+	}
+	changes, err := p.parseDiffChanges(ctx, head)
+	if err != nil {
+		return nil, fmt.Errorf("error diffing the working tree: %w", err)
+	}
+	if !untracked {
+		return changes, nil
+	}
+
+	out, err := gitcmd.Output(ctx, "", "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fmt.Errorf("error listing untracked files: %w", err)
+	}
+	for _, file := range strings.Split(strings.TrimSpace(out), "\n") {
+		if file == "" {
+			continue
+		}
+		change := &Change{File: file, Action: "A", FileExtension: getFileExtension(file)}
+		// git diff --no-index exits with 1 when the files differ, as they do here
+		if err := p.readDiff(ctx, change, "diff", "--no-index", "-U0", "--", "/dev/null", file); err != nil && gitcmd.ExitCode(err) != 1 {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// PushStash stashes the working tree changes with a message
+func PushStash(ctx context.Context, message string, untracked bool) error {
+	args := []string{"stash", "push", "--message", message}
+	if untracked {
+		args = append(args, "--include-untracked")
+	}
+	if _, err := gitcmd.Output(ctx, "", args...); err != nil {
+		return fmt.Errorf("error stashing changes: %w", err)
+	}
+	return nil
+}
+
+// RelabelStashes gives stashes new messages, by hash. git cannot change the
+// message of a stash in place, so the stashes down to the deepest relabeled one
+// are dropped and stored again in the same order.
+func RelabelStashes(ctx context.Context, stashes []*Stash, messages map[string]string) error {
+	deepest := -1
+	for i, stash := range stashes {
+		if _, ok := messages[stash.Hash]; ok {
+			deepest = i
+		}
+	}
+	for i := 0; i <= deepest; i++ {
+		if _, err := gitcmd.Output(ctx, "", "stash", "drop", "--quiet", "stash@{0}"); err != nil {
+			return fmt.Errorf("error dropping %s (git stash store %s restores it): %w", stashes[i].Ref, stashes[i].Hash, err)
+		}
+	}
+	for i := deepest; i >= 0; i-- {
+		message, ok := messages[stashes[i].Hash]
+		if !ok {
+			message = stashes[i].Message
+		}
+		if _, err := gitcmd.Output(ctx, "", "stash", "store", "--message", message, stashes[i].Hash); err != nil {
+			return fmt.Errorf("error storing %s again (git stash store %s restores it): %w", stashes[i].Ref, stashes[i].Hash, err)
+		}
+	}
+	return nil
+}
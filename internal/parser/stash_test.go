@@ -0,0 +1,28 @@
+package parser
+
+import "testing"
+
+func TestParseStashList(t *testing.T) {
+	out := "stash@{0}\x1fabc\x1fWIP on main: 1d09baa chore: init\n" +
+		"stash@{1}\x1fdef\x1fOn fix/login: feat(auth): add token refresh\n" +
+		"stash@{2}\x1f123\x1fWIP on (no branch): 5b58c8f docs: add notes\n"
+	stashes := parseStashList(out)
+	if len(stashes) != 3 {
+		t.Fatalf("parseStashList() returned %d stashes, want 3", len(stashes))
+	}
+
+	tests := []struct {
+		ref, branch string
+		wip         bool
+	}{
+		{"stash@{0}", "main", true},
+		{"stash@{1}", "fix/login", false},
+		{"stash@{2}", "(no branch)", true},
+	}
+	for i, tt := range tests {
+		stash := stashes[i]
+		if stash.Ref != tt.ref || stash.StashBranch() != tt.branch || stash.IsWIP() != tt.wip {
+			t.Errorf("stash %d = %s on %q (WIP %v), want %s on %q (WIP %v)", i, stash.Ref, stash.StashBranch(), stash.IsWIP(), tt.ref, tt.branch, tt.wip)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Package plugin runs the executables in gitmit's plugins directory, which
+// encode conventions of their own: diff hints, scopes for paths and rules for
+// commit messages. A plugin reads one JSON Request on stdin and writes one JSON
+// Response on stdout, so it can be written in any language.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
+	"github.com/andev0x/gitmit/internal/xdg"
+)
+
+// Version is the version of the protocol, sent in every request so plugins
+// can refuse one they do not speak
+const Version = 1
+
+// Phases a plugin is run for
+const (
+	PhaseAnalyze = "analyze" // Before the staged changes are analyzed; hints and scopes are used
+	PhaseCheck   = "check"   // When a message is checked against the rules; violations are used
+)
+
+// maxStderr bounds how much of what a plugin writes to stderr an error keeps
+const maxStderr = 2 * 1024
+
+// waitDelay is how long a stopped plugin may keep its output open
+const waitDelay = time.Second
+
+// File is a staged file as plugins see it
+type File struct {
+	Path    string `json:"path"`
+	Action  string `json:"action"` // A, M, D or R
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	Diff    string `json:"diff,omitempty"`
+}
+
+// Request is what a plugin reads on stdin
+type Request struct {
+	Version int    `json:"version"`
+	Phase   string `json:"phase"`
+	Branch  string `json:"branch,omitempty"`
+	Files   []File `json:"files,omitempty"`   // Staged files, in the analyze phase
+	Message string `json:"message,omitempty"` // Commit message, in the check phase
+}
+
+// Violation is a rule of a plugin broken by a message
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Response is what a plugin writes on stdout; every field is optional, and
+// no output at all means nothing to add
+type Response struct {
+	Hints      []string          `json:"hints,omitempty"`      // Change patterns for the templates and the AI, e.g. "feature flag"
+	Scopes     map[string]string `json:"scopes,omitempty"`     // Scope of the files whose path contains the key, like topicMappings
+	Violations []Violation       `json:"violations,omitempty"` // Rules the message breaks
+}
+
+// Plugin is an executable in the plugins directory
+type Plugin struct {
+	Name string // File name without extension
+	Path string
+}
+
+// Dir returns the plugins directory: $XDG_CONFIG_HOME/gitmit/plugins. Only
+// this directory is searched, never the repository, so cloning a repository
+// cannot make gitmit run its code.
+func Dir() (string, error) {
+	dir, err := xdg.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "plugins"), nil
+}
+
+// Discover returns the executables in dir, sorted by name. Hidden files and
+// files that are not executable, such as a README, are skipped; a missing
+// directory has no plugins. WebAssembly modules cannot be run and are skipped
+// with a warning.
+func Discover(dir string) ([]Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading plugins directory: %w", err)
+	}
+
+	var plugins []Plugin
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if strings.EqualFold(filepath.Ext(entry.Name()), ".wasm") {
+			slog.Warn("WebAssembly plugin skipped; wrap it in an executable that runs it", "path", path)
+			continue
+		}
+		info, err := os.Stat(path) // Follows links to plugins kept elsewhere
+		if err != nil || !info.Mode().IsRegular() || !executable(entry.Name(), info.Mode()) {
+			continue
+		}
+		plugins = append(plugins, Plugin{
+			Name: strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())),
+			Path: path,
+		})
+	}
+	return plugins, nil
+}
+
+// executable reports whether a file can be run: by its mode, or on Windows,
+// which has no executable bit, by its extension
+func executable(name string, mode os.FileMode) bool {
+	if runtime.GOOS == "windows" {
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".exe", ".bat", ".cmd", ".com":
+			return true
+		}
+		return false
+	}
+	return mode&0o111 != 0
+}
+
+// Run runs the plugin on a request, stopping it after timeout; 0 means no
+// limit. A plugin that exits with an error or writes something other than a
+// Response fails.
+func (p Plugin) Run(ctx context.Context, timeout time.Duration, req Request) (*Response, error) {
+	req.Version = Version
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding plugin request: %w", err)
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, p.Path)
+	cmd.Dir = gitcmd.Dir(ctx)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = waitDelay
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("plugin %s timed out after %s", p.Name, timeout)
+		}
+		if detail := tail(stderr.String()); detail != "" {
+			return nil, fmt.Errorf("plugin %s failed: %w: %s", p.Name, err, detail)
+		}
+		return nil, fmt.Errorf("plugin %s failed: %w", p.Name, err)
+	}
+
+	resp := &Response{}
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return resp, nil
+	}
+	if err := json.Unmarshal(stdout.Bytes(), resp); err != nil {
+		return nil, fmt.Errorf("error reading the response of plugin %s: %w", p.Name, err)
+	}
+	return resp, nil
+}
+
+// tail returns the end of what a plugin wrote to stderr, trimmed
+func tail(stderr string) string {
+	stderr = strings.TrimSpace(stderr)
+	if len(stderr) > maxStderr {
+		stderr = "..." + stderr[len(stderr)-maxStderr:]
+	}
+	return stderr
+}
@@ -0,0 +1,105 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writePlugin writes a shell script plugin to dir
+func writePlugin(t *testing.T, dir, name, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are found by extension on Windows")
+	}
+	dir := t.TempDir()
+	writePlugin(t, dir, "b-scopes.sh", "cat >/dev/null\n")
+	writePlugin(t, dir, "a-rules", "cat >/dev/null\n")
+	writePlugin(t, dir, ".hidden", "cat >/dev/null\n")
+	writePlugin(t, dir, "c-lint.wasm", "\x00asm")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("docs"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "lib"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	plugins, err := Discover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range plugins {
+		names = append(names, p.Name)
+	}
+	if want := []string{"a-rules", "b-scopes"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Discover() = %v, want %v", names, want)
+	}
+
+	if plugins, err := Discover(filepath.Join(dir, "missing")); err != nil || plugins != nil {
+		t.Errorf("Discover() of a missing directory = %v, %v, want no plugins", plugins, err)
+	}
+}
+
+func TestSet(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	// The first plugin answers by phase, the second always fails
+	writePlugin(t, dir, "billing", `input=$(cat)
+case "$input" in
+*'"phase":"analyze"'*)
+	echo '{"hints": ["feature flag"], "scopes": {"services/billing": "billing"}}' ;;
+*WIP*)
+	echo '{"violations": [{"rule": "wip", "message": "finish the work first"}, {"message": "no rule"}]}' ;;
+esac
+`)
+	writePlugin(t, dir, "broken", "cat >/dev/null\necho 'no config' >&2\nexit 3\n")
+	plugins, err := Discover(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := &Set{Plugins: plugins, Timeout: 5 * time.Second}
+	ctx := context.Background()
+
+	resp, err := set.Analyze(ctx, "main", []File{{Path: "services/billing/invoice.go", Action: "M", Added: 1}})
+	if err == nil || !strings.Contains(err.Error(), "plugin broken failed") || !strings.Contains(err.Error(), "no config") {
+		t.Errorf("Analyze() error = %v, want the failure of the broken plugin with its stderr", err)
+	}
+	if !reflect.DeepEqual(resp.Hints, []string{"feature flag"}) || resp.Scopes["services/billing"] != "billing" {
+		t.Errorf("Analyze() = %+v, want the hints and scopes of the working plugin", resp)
+	}
+
+	violations, _ := set.Check(ctx, "", "feat: WIP invoices")
+	want := []Violation{{Rule: "billing/wip", Message: "finish the work first"}, {Rule: "billing", Message: "no rule"}}
+	if !reflect.DeepEqual(violations, want) {
+		t.Errorf("Check() = %+v, want %+v", violations, want)
+	}
+	if violations, _ := set.Check(ctx, "", "feat: add invoices"); len(violations) != 0 {
+		t.Errorf("Check() of a valid message = %+v, want none", violations)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	writePlugin(t, dir, "slow", "exec sleep 5\n")
+	p := Plugin{Name: "slow", Path: filepath.Join(dir, "slow")}
+	if _, err := p.Run(context.Background(), 100*time.Millisecond, Request{Phase: PhaseCheck}); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Run() = %v, want a timeout", err)
+	}
+}
@@ -0,0 +1,72 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Set is the plugins run for a command, in order
+type Set struct {
+	Plugins []Plugin
+	Timeout time.Duration // How long each plugin may run; 0 for no limit
+}
+
+// Analyze runs every plugin on the staged files and combines their hints and
+// scopes. Where plugins map the same path to different scopes, the first
+// plugin wins. A plugin that fails adds nothing; the error reports every
+// failure, with whatever the other plugins contributed.
+func (s *Set) Analyze(ctx context.Context, branch string, files []File) (*Response, error) {
+	combined := &Response{}
+	seen := make(map[string]bool)
+	var errs []error
+	for _, p := range s.Plugins {
+		resp, err := p.Run(ctx, s.Timeout, Request{Phase: PhaseAnalyze, Branch: branch, Files: files})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, hint := range resp.Hints {
+			if hint != "" && !seen[hint] {
+				seen[hint] = true
+				combined.Hints = append(combined.Hints, hint)
+			}
+		}
+		for path, scope := range resp.Scopes {
+			if combined.Scopes == nil {
+				combined.Scopes = make(map[string]string)
+			}
+			if _, ok := combined.Scopes[path]; !ok && path != "" && scope != "" {
+				combined.Scopes[path] = scope
+			}
+		}
+	}
+	return combined, errors.Join(errs...)
+}
+
+// Check runs every plugin on a commit message and returns the rules it breaks.
+// Each rule is named after its plugin, e.g. "jira/summary" for the rule
+// "summary" of the plugin "jira". Failures are handled as in Analyze.
+func (s *Set) Check(ctx context.Context, branch, message string) ([]Violation, error) {
+	var violations []Violation
+	var errs []error
+	for _, p := range s.Plugins {
+		resp, err := p.Run(ctx, s.Timeout, Request{Phase: PhaseCheck, Branch: branch, Message: message})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, v := range resp.Violations {
+			if v.Message == "" {
+				continue
+			}
+			if v.Rule == "" {
+				v.Rule = p.Name
+			} else {
+				v.Rule = p.Name + "/" + v.Rule
+			}
+			violations = append(violations, v)
+		}
+	}
+	return violations, errors.Join(errs...)
+}
@@ -0,0 +1,142 @@
+package pr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/changelog"
+	"github.com/andev0x/gitmit/internal/parser"
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+// maxListedTestFiles limits how many changed test files the description lists
+const maxListedTestFiles = 10
+
+// PullRequest is a generated pull request title and markdown description
+type PullRequest struct {
+	Title string
+	Body  string
+}
+
+// Describe writes the description of a pull request from its commits and the
+// cumulative changes of the branch: a summary, the changes by area, breaking
+// changes and notes on testing
+func Describe(title string, commits []*parser.Commit, changes []*parser.Change, testPatterns []string) *PullRequest {
+	entries := changelog.Parse(commits)
+
+	var b strings.Builder
+	b.WriteString("## Summary\n\n")
+	b.WriteString(summary(entries, changes) + "\n")
+
+	b.WriteString("\n## Changes\n")
+	for _, group := range changelog.GroupByArea(entries) {
+		fmt.Fprintf(&b, "\n### %s\n\n", group.Key)
+		for _, entry := range group.Entries {
+			fmt.Fprintf(&b, "- %s (%s)\n", describeEntry(entry), entry.Commit.ShortHash())
+		}
+	}
+
+	if breaking := changelog.Breaking(entries); len(breaking) > 0 {
+		b.WriteString("\n## Breaking Changes\n\n")
+		for _, entry := range breaking {
+			note := entry.BreakingNote
+			if note == "" {
+				note = entry.Description
+			}
+			fmt.Fprintf(&b, "- %s (%s)\n", note, entry.Commit.ShortHash())
+		}
+	}
+
+	b.WriteString("\n## Testing\n\n")
+	b.WriteString(testNotes(changes, testPatterns))
+
+	return &PullRequest{Title: title, Body: b.String()}
+}
+
+// summary describes the kinds of commits and the size of the change in one sentence
+func summary(entries []*changelog.Entry, changes []*parser.Change) string {
+	var kinds []string
+	for _, group := range changelog.GroupByType(entries) {
+		kinds = append(kinds, kindOf(group.Key, len(group.Entries)))
+	}
+
+	added, removed := 0, 0
+	for _, change := range changes {
+		added += change.Added
+		removed += change.Removed
+	}
+	return fmt.Sprintf("%s in %s, changing %s (+%d −%d).",
+		capitalize(joinList(kinds)), ui.Plural(len(entries), "commit", "commits"), ui.Plural(len(changes), "file", "files"), added, removed)
+}
+
+// describeEntry formats a commit for the changes list, keeping its type visible
+func describeEntry(entry *changelog.Entry) string {
+	if entry.Type == "" {
+		return entry.Commit.Subject
+	}
+	marker := ""
+	if entry.Breaking {
+		marker = "!"
+	}
+	return fmt.Sprintf("**%s%s**: %s", entry.Type, marker, entry.Description)
+}
+
+// testNotes lists the test files the branch changes, by the configured and
+// default test patterns, or says that it changes none
+func testNotes(changes []*parser.Change, testPatterns []string) string {
+	var tests []string
+	for _, change := range changes {
+		if analyzer.IsTestFile(change.File, testPatterns) && change.Action != "D" {
+			tests = append(tests, change.File)
+		}
+	}
+	if len(tests) == 0 {
+		return "No tests were added or changed in this branch.\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s added or changed:\n\n", capitalize(ui.Plural(len(tests), "test file", "test files")))
+	for i, file := range tests {
+		if i == maxListedTestFiles {
+			fmt.Fprintf(&b, "- …and %d more\n", len(tests)-maxListedTestFiles)
+			break
+		}
+		fmt.Fprintf(&b, "- `%s`\n", file)
+	}
+	return b.String()
+}
+
+// IsTestFile reports whether a path looks like a test file in common ecosystems
+func IsTestFile(file string) bool {
+	return analyzer.IsTestFile(file, nil)
+}
+
+// kindOf names the commits of a conventional type in the summary
+func kindOf(commitType string, n int) string {
+	switch commitType {
+	case "feat":
+		return ui.Plural(n, "feature", "features")
+	case "fix":
+		return ui.Plural(n, "fix", "fixes")
+	case "other":
+		return ui.Plural(n, "other change", "other changes")
+	}
+	return ui.Plural(n, commitType+" change", commitType+" changes")
+}
+
+// joinList joins items as "a, b and c"
+func joinList(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// capitalize uppercases the first letter of a sentence
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
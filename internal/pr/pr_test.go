@@ -0,0 +1,52 @@
+package pr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestDescribe(t *testing.T) {
+	commits := []*parser.Commit{
+		{Hash: "1111111aaa", Subject: "feat(api): add user endpoint", Files: []string{"internal/api/user.go"}},
+		{Hash: "2222222bbb", Subject: "fix(api)!: reject empty names", Body: "BREAKING CHANGE: empty names now return 400"},
+	}
+	changes := []*parser.Change{
+		{File: "internal/api/user.go", Action: "A", Added: 40},
+		{File: "internal/api/user_test.go", Action: "A", Added: 25, Removed: 2},
+	}
+
+	request := Describe("feat(api): add user endpoint", commits, changes, nil)
+	for _, want := range []string{
+		"1 feature and 1 fix in 2 commits, changing 2 files (+65 −2).",
+		"### api\n\n- **feat**: add user endpoint (1111111)\n- **fix!**: reject empty names (2222222)",
+		"## Breaking Changes\n\n- empty names now return 400 (2222222)",
+		"1 test file added or changed:\n\n- `internal/api/user_test.go`",
+	} {
+		if !strings.Contains(request.Body, want) {
+			t.Errorf("body is missing %q:\n%s", want, request.Body)
+		}
+	}
+
+	request = Describe("docs: fix typo", []*parser.Commit{{Hash: "3333333", Subject: "docs: fix typo"}}, []*parser.Change{{File: "README.md", Action: "M", Added: 1, Removed: 1}}, nil)
+	if !strings.Contains(request.Body, "No tests were added or changed") || strings.Contains(request.Body, "Breaking") {
+		t.Errorf("unexpected body:\n%s", request.Body)
+	}
+}
+
+func TestIsTestFile(t *testing.T) {
+	for file, want := range map[string]bool{
+		"internal/api/user_test.go": true,
+		"src/user.spec.ts":          true,
+		"tests/test_user.py":        true,
+		"spec/user_spec.rb":         true,
+		"src/UserTest.java":         true,
+		"internal/api/user.go":      false,
+		"docs/testing.md":           false,
+	} {
+		if got := IsTestFile(file); got != want {
+			t.Errorf("IsTestFile(%q) = %v, want %v", file, got, want)
+		}
+	}
+}
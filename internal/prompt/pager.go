@@ -0,0 +1,138 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+// Default terminal size, for terminals whose size cannot be read
+const (
+	defaultRows    = 24
+	defaultColumns = 80
+)
+
+// Page is a titled block of text shown by ShowPages, such as the diff of a file
+type Page struct {
+	Title string
+	Lines []string
+}
+
+// ShowPages shows pages of text a screen at a time on the terminal's alternate
+// screen, so the output before it is left as it was, until q or Ctrl-C. The
+// arrow keys, j and k scroll, space and b move a screen, n and p (or → and ←)
+// switch pages, and g and G jump to the start and end of a page. Each line is
+// cut to the terminal's width, then passed through style for colors. When the
+// input is not a terminal the pages are printed one after another.
+func (p *Prompter) ShowPages(pages []Page, style func(line string) string) error {
+	if len(pages) == 0 {
+		return nil
+	}
+	if style == nil {
+		style = func(line string) string { return line }
+	}
+
+	restore, ok := p.startKeys()
+	if !ok {
+		for _, page := range pages {
+			fmt.Fprintln(p.out, ui.HeadingString("%s", page.Title))
+			for _, line := range page.Lines {
+				fmt.Fprintln(p.out, style(line))
+			}
+			fmt.Fprintln(p.out)
+		}
+		return nil
+	}
+	defer restore()
+	fmt.Fprint(p.out, "\x1b[?1049h\x1b[?25l") // Alternate screen, hidden cursor
+	defer fmt.Fprint(p.out, "\x1b[?25h\x1b[?1049l")
+
+	current, top := 0, 0
+	for {
+		rows, columns := terminalSize(p.fd)
+		if rows <= 0 || columns <= 0 {
+			rows, columns = defaultRows, defaultColumns
+		}
+		height := max(rows-2, 1) // Below the title and above the status line
+		last := max(len(pages[current].Lines)-height, 0)
+		top = min(max(top, 0), last)
+		fmt.Fprint(p.out, renderPage(pages, current, top, height, columns, style))
+
+		k, r, err := p.readKey()
+		if err != nil {
+			return err
+		}
+		switch k {
+		case keyUp:
+			top--
+		case keyDown, keyEnter:
+			top++
+		case keyPageUp:
+			top -= height
+		case keyPageDown:
+			top += height
+		case keyLeft:
+			current, top = max(current-1, 0), 0
+		case keyRight:
+			current, top = min(current+1, len(pages)-1), 0
+		case keyInterrupt:
+			return nil
+		case keyRune:
+			switch r {
+			case 'q', 'Q':
+				return nil
+			case 'k':
+				top--
+			case 'j':
+				top++
+			case 'b':
+				top -= height
+			case ' ', 'f':
+				top += height
+			case 'g':
+				top = 0
+			case 'G':
+				top = last
+			case 'p':
+				current, top = max(current-1, 0), 0
+			case 'n':
+				current, top = min(current+1, len(pages)-1), 0
+			}
+		}
+	}
+}
+
+// renderPage draws a screen of a page: its title, the lines from top and a
+// status line with the position and keys
+func renderPage(pages []Page, current, top, height, columns int, style func(string) string) string {
+	page := pages[current]
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	b.WriteString(ui.HeadingString("%s", cut(page.Title, columns-8)) + ui.MutedString(" (%d/%d)", current+1, len(pages)))
+
+	end := min(top+height, len(page.Lines))
+	for _, line := range page.Lines[top:end] {
+		b.WriteString("\r\n" + style(cut(line, columns)))
+	}
+	for i := end - top; i < height; i++ {
+		b.WriteString("\r\n" + ui.MutedString("~"))
+	}
+
+	position := "empty"
+	if len(page.Lines) > 0 {
+		position = fmt.Sprintf("lines %d-%d of %d", top+1, end, len(page.Lines))
+	}
+	status := position + " · ↑/↓ scroll · space/b screen · n/p file · q back"
+	b.WriteString("\r\n" + ui.MutedString("%s", cut(status, columns)))
+	return b.String()
+}
+
+// cut expands the tabs of a line and cuts it to a width
+func cut(line string, width int) string {
+	runes := []rune(strings.ReplaceAll(line, "\t", "    "))
+	if width > 0 && len(runes) > width {
+		runes = runes[:width]
+	}
+	return string(runes)
+}
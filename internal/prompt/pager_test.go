@@ -0,0 +1,46 @@
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestShowPages(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 30; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	pages := []Page{{Title: "a.go", Lines: lines}, {Title: "b.go", Lines: []string{"+added"}}}
+
+	var out strings.Builder
+	p := &Prompter{in: bufio.NewReader(strings.NewReader(" G")), out: &out, fd: -1, keys: true}
+	if err := p.ShowPages(pages, nil); err == nil {
+		t.Fatal("ShowPages() at the end of the input = nil, want the read error")
+	}
+	frames := strings.Split(out.String(), "\x1b[H\x1b[2J")
+	// A screen is 22 lines, so space and G both end on the last screen
+	if last := frames[len(frames)-1]; !strings.Contains(last, "lines 9-30 of 30") || strings.Contains(last, "line 8\r") {
+		t.Errorf("last screen = %q, want lines 9-30", last)
+	}
+
+	out.Reset()
+	p = &Prompter{in: bufio.NewReader(strings.NewReader("jnq")), out: &out, fd: -1, keys: true}
+	if err := p.ShowPages(pages, func(line string) string { return "<" + line + ">" }); err != nil {
+		t.Fatalf("ShowPages() = %v", err)
+	}
+	frames = strings.Split(out.String(), "\x1b[H\x1b[2J")
+	if last := frames[len(frames)-1]; !strings.Contains(last, "b.go") || !strings.Contains(last, "(2/2)") || !strings.Contains(last, "<+added>") {
+		t.Errorf("screen after n = %q, want the second page", last)
+	}
+
+	// Without a terminal the pages are printed in full
+	out.Reset()
+	if err := NewReader(strings.NewReader(""), &out).ShowPages(pages, nil); err != nil {
+		t.Fatalf("ShowPages() = %v", err)
+	}
+	if text := out.String(); !strings.Contains(text, "line 30\n") || !strings.Contains(text, "b.go\n+added\n") {
+		t.Errorf("ShowPages() printed %q, want every line", text)
+	}
+}
@@ -0,0 +1,430 @@
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/andev0x/gitmit/internal/ui"
+)
+
+// ErrInterrupted is returned when a prompt is cancelled with Ctrl-C
+var ErrInterrupted = errors.New("interrupted")
+
+// pageSize is the number of options Select shows at once
+const pageSize = 10
+
+// Option is a choice offered by Select
+type Option struct {
+	Value       string
+	Description string
+}
+
+// Prompter asks questions on the terminal. When the input is a terminal, options
+// are chosen with the arrow keys and previews update as answers are typed;
+// otherwise whole lines are read, so answers can be piped in.
+type Prompter struct {
+	in   *bufio.Reader
+	out  io.Writer
+	fd   int  // Input file descriptor, -1 when the input is not a file
+	keys bool // Read single keys without switching the terminal mode
+}
+
+// New creates a prompter on standard input and output
+func New() *Prompter {
+	return &Prompter{in: bufio.NewReader(os.Stdin), out: os.Stdout, fd: int(os.Stdin.Fd())}
+}
+
+// NewStdin creates a prompter on standard input, read through in, and standard
+// output, for prompts between answers read from in directly
+func NewStdin(in *bufio.Reader) *Prompter {
+	return &Prompter{in: in, out: os.Stdout, fd: int(os.Stdin.Fd())}
+}
+
+// NewReader creates a prompter reading whole lines from in
+func NewReader(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{in: bufio.NewReader(in), out: out, fd: -1}
+}
+
+// Reader returns the buffered input, for reading answers outside the prompter
+// without losing input it has already buffered
+func (p *Prompter) Reader() *bufio.Reader {
+	return p.in
+}
+
+// Select asks for one of the options, starting at initial, and returns its index.
+// The preview, when not nil, is shown below the options for the highlighted one.
+func (p *Prompter) Select(label string, options []Option, initial int, preview func(int) string) (int, error) {
+	if len(options) == 0 {
+		return -1, fmt.Errorf("no options for %s", label)
+	}
+	if initial < 0 || initial >= len(options) {
+		initial = 0
+	}
+
+	restore, ok := p.startKeys()
+	if !ok {
+		return p.selectLine(label, options, initial)
+	}
+	defer restore()
+
+	s := &screen{out: p.out}
+	current := initial
+	for {
+		s.draw(renderOptions(label, options, current, preview))
+		k, r, err := p.readKey()
+		if err != nil {
+			return -1, err
+		}
+		switch k {
+		case keyUp:
+			current = (current + len(options) - 1) % len(options)
+		case keyDown:
+			current = (current + 1) % len(options)
+		case keyEnter:
+			s.draw(answered(label, options[current].Value) + "\n")
+			return current, nil
+		case keyInterrupt:
+			s.draw(answered(label, "") + "\n")
+			return -1, ErrInterrupted
+		case keyRune:
+			if r == 'k' {
+				current = (current + len(options) - 1) % len(options)
+			} else if r == 'j' {
+				current = (current + 1) % len(options)
+			} else if n := int(r - '1'); n >= 0 && n < len(options) && n < 9 {
+				current = n
+			}
+		}
+	}
+}
+
+// Input asks for a line of text, pre-filled with initial. The preview, when not
+// nil, is shown above the input and updated as the answer is typed.
+func (p *Prompter) Input(label, initial string, preview func(string) string) (string, error) {
+	restore, ok := p.startKeys()
+	if !ok {
+		return p.inputLine(label, initial)
+	}
+	defer restore()
+
+	s := &screen{out: p.out}
+	text := []rune(initial)
+	for {
+		view := ""
+		if preview != nil {
+			view = ui.MutedString("%s", preview(string(text))) + "\n"
+		}
+		s.draw(view + ui.AccentString("? ") + label + ": " + string(text))
+
+		k, r, err := p.readKey()
+		if err != nil {
+			return "", err
+		}
+		switch k {
+		case keyEnter:
+			s.draw(answered(label, string(text)) + "\n")
+			return strings.TrimSpace(string(text)), nil
+		case keyInterrupt:
+			s.draw(answered(label, "") + "\n")
+			return "", ErrInterrupted
+		case keyBackspace:
+			if len(text) > 0 {
+				text = text[:len(text)-1]
+			}
+		case keyClear:
+			text = nil
+		case keyRune:
+			if unicode.IsPrint(r) {
+				text = append(text, r)
+			}
+		}
+	}
+}
+
+// Confirm asks a yes/no question; an empty answer returns def
+func (p *Prompter) Confirm(label string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+
+	restore, ok := p.startKeys()
+	if !ok {
+		fmt.Fprintf(p.out, "%s %s (%s): ", ui.AccentString("?"), label, hint)
+		line, err := p.readLine()
+		if err != nil && err != io.EOF {
+			return false, err
+		}
+		return parseYes(line, def), nil
+	}
+	defer restore()
+
+	s := &screen{out: p.out}
+	s.draw(ui.AccentString("? ") + fmt.Sprintf("%s (%s) ", label, hint))
+	for {
+		k, r, err := p.readKey()
+		if err != nil {
+			return false, err
+		}
+		var yes bool
+		switch {
+		case k == keyInterrupt:
+			s.draw(answered(label, "") + "\n")
+			return false, ErrInterrupted
+		case k == keyEnter:
+			yes = def
+		case k == keyRune && strings.ContainsRune("yYnN", r):
+			yes = parseYes(string(r), def)
+		default:
+			continue
+		}
+		s.draw(answered(label, map[bool]string{true: "yes", false: "no"}[yes]) + "\n")
+		return yes, nil
+	}
+}
+
+// Lines asks for several lines of text, ended by an empty line
+func (p *Prompter) Lines(label string) ([]string, error) {
+	fmt.Fprintf(p.out, "%s %s\n", ui.AccentString("?"), label)
+	fmt.Fprintln(p.out, ui.MutedString("  (empty line to finish)"))
+
+	var lines []string
+	for {
+		line, err := p.readLine()
+		if err != nil && err != io.EOF {
+			return lines, err
+		}
+		if strings.TrimSpace(line) == "" {
+			return lines, nil
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+		if err == io.EOF {
+			return lines, nil
+		}
+	}
+}
+
+// selectLine asks for an option by number or name when keys cannot be read
+func (p *Prompter) selectLine(label string, options []Option, initial int) (int, error) {
+	fmt.Fprintf(p.out, "%s %s\n", ui.AccentString("?"), label)
+	for i, option := range options {
+		fmt.Fprintf(p.out, "  %2d. %-12s %s\n", i+1, option.Value, ui.MutedString("%s", option.Description))
+	}
+
+	for {
+		fmt.Fprintf(p.out, "Choice [%s]: ", options[initial].Value)
+		line, err := p.readLine()
+		if err != nil && err != io.EOF {
+			return -1, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return initial, nil
+		}
+		if n, err := strconv.Atoi(line); err == nil && n >= 1 && n <= len(options) {
+			return n - 1, nil
+		}
+		if i := matchOption(options, line); i >= 0 {
+			return i, nil
+		}
+		if err == io.EOF {
+			return -1, fmt.Errorf("%q is not one of the options for %s", line, label)
+		}
+		fmt.Fprintf(p.out, ui.Text("⚠ %q is not one of the options.\n"), line)
+	}
+}
+
+// inputLine asks for a line of text when keys cannot be read; an empty answer keeps initial
+func (p *Prompter) inputLine(label, initial string) (string, error) {
+	if initial != "" {
+		fmt.Fprintf(p.out, "%s %s [%s]: ", ui.AccentString("?"), label, initial)
+	} else {
+		fmt.Fprintf(p.out, "%s %s: ", ui.AccentString("?"), label)
+	}
+	line, err := p.readLine()
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	if line = strings.TrimSpace(line); line == "" {
+		return initial, nil
+	}
+	return line, nil
+}
+
+// readLine reads a line without its line ending
+func (p *Prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// startKeys switches to reading single keys if the input is a terminal
+func (p *Prompter) startKeys() (func(), bool) {
+	if p.keys {
+		return func() {}, true
+	}
+	if p.fd < 0 || p.in.Buffered() > 0 {
+		return nil, false
+	}
+	restore, err := enableKeys(p.fd)
+	if err != nil {
+		return nil, false
+	}
+	return restore, true
+}
+
+// Keys recognized in key mode
+type key int
+
+const (
+	keyNone key = iota
+	keyRune
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyPageUp
+	keyPageDown
+	keyEnter
+	keyBackspace
+	keyClear
+	keyInterrupt
+)
+
+// readKey reads one key press, decoding arrow key escape sequences
+func (p *Prompter) readKey() (key, rune, error) {
+	r, _, err := p.in.ReadRune()
+	if err != nil {
+		return keyNone, 0, err
+	}
+	switch r {
+	case 3: // Ctrl-C
+		return keyInterrupt, r, nil
+	case '\r', '\n':
+		return keyEnter, r, nil
+	case 127, 8: // Backspace, Ctrl-H
+		return keyBackspace, r, nil
+	case 21: // Ctrl-U
+		return keyClear, r, nil
+	case 16: // Ctrl-P
+		return keyUp, r, nil
+	case 14: // Ctrl-N
+		return keyDown, r, nil
+	case 27: // Escape sequence: ESC [ A or ESC O A for arrow keys, ESC [ 5 ~ for pages
+		if b, err := p.in.ReadByte(); err != nil || (b != '[' && b != 'O') {
+			return keyNone, r, nil
+		}
+		b, err := p.in.ReadByte()
+		if err != nil {
+			return keyNone, r, nil
+		}
+		switch b {
+		case 'A':
+			return keyUp, r, nil
+		case 'B':
+			return keyDown, r, nil
+		case 'C':
+			return keyRight, r, nil
+		case 'D':
+			return keyLeft, r, nil
+		case '5', '6': // Page Up and Page Down: ESC [ 5 ~ and ESC [ 6 ~
+			if t, err := p.in.ReadByte(); err != nil || t != '~' {
+				return keyNone, r, nil
+			}
+			if b == '5' {
+				return keyPageUp, r, nil
+			}
+			return keyPageDown, r, nil
+		}
+		return keyNone, r, nil
+	}
+	return keyRune, r, nil
+}
+
+// screen redraws a block of text in place
+type screen struct {
+	out   io.Writer
+	lines int // Line breaks in the last drawn text
+}
+
+// draw replaces the previously drawn text; the cursor is left at the end of the text
+func (s *screen) draw(text string) {
+	if s.lines > 0 {
+		fmt.Fprintf(s.out, "\x1b[%dA", s.lines)
+	}
+	fmt.Fprint(s.out, "\r\x1b[J", text)
+	s.lines = strings.Count(text, "\n")
+}
+
+// renderOptions draws the page of options around the current one, with its preview
+func renderOptions(label string, options []Option, current int, preview func(int) string) string {
+	var b strings.Builder
+	b.WriteString(ui.AccentString("? ") + label + ui.MutedString("  (↑/↓ to move, enter to select)"))
+
+	start := 0
+	if current >= pageSize {
+		start = current - pageSize + 1
+	}
+	end := min(start+pageSize, len(options))
+	width := 0
+	for _, option := range options[start:end] {
+		width = max(width, utf8.RuneCountInString(option.Value))
+	}
+	for i := start; i < end; i++ {
+		line := fmt.Sprintf("%-*s  %s", width, options[i].Value, options[i].Description)
+		if i == current {
+			b.WriteString("\n" + ui.AccentString("❯ %s", line))
+		} else {
+			b.WriteString("\n  " + line)
+		}
+	}
+	if end < len(options) || start > 0 {
+		b.WriteString("\n" + ui.MutedString("  (%d/%d)", current+1, len(options)))
+	}
+	if preview != nil {
+		b.WriteString("\n\n" + ui.MutedString("%s", preview(current)))
+	}
+	return b.String()
+}
+
+// answered formats a prompt after it was answered
+func answered(label, value string) string {
+	if !strings.HasSuffix(label, "?") {
+		label += ":"
+	}
+	return ui.SuccessString("✔ ") + label + " " + ui.AccentString("%s", value)
+}
+
+// matchOption finds the option named by an answer, or the single option it is a prefix of
+func matchOption(options []Option, answer string) int {
+	found := -1
+	for i, option := range options {
+		if strings.EqualFold(option.Value, answer) {
+			return i
+		}
+		if strings.HasPrefix(strings.ToLower(option.Value), strings.ToLower(answer)) {
+			if found >= 0 {
+				return -1
+			}
+			found = i
+		}
+	}
+	return found
+}
+
+// parseYes interprets a yes/no answer; an empty answer returns def
+func parseYes(answer string, def bool) bool {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "":
+		return def
+	case "y", "yes":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package prompt
+
+import (
+	"bufio"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// keyPrompter reads keys from input as if it were typed on a terminal
+func keyPrompter(input string) *Prompter {
+	return &Prompter{in: bufio.NewReader(strings.NewReader(input)), out: io.Discard, fd: -1, keys: true}
+}
+
+var testOptions = []Option{{"feat", "A new feature"}, {"fix", "A bug fix"}, {"docs", "Documentation only"}}
+
+func TestSelectKeys(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"\r", 1},
+		{"\x1b[B\r", 2},
+		{"\x1b[B\x1b[B\r", 0},
+		{"\x1b[A\x1b[A\r", 2},
+		{"kk\n", 2},
+		{"3\r", 2},
+	}
+	for _, tt := range tests {
+		var previewed []int
+		got, err := keyPrompter(tt.input).Select("Type", testOptions, 1, func(i int) string {
+			previewed = append(previewed, i)
+			return testOptions[i].Value + ": add thing"
+		})
+		if err != nil || got != tt.want {
+			t.Errorf("Select(%q) = %d, %v; want %d", tt.input, got, err, tt.want)
+		}
+		if len(previewed) == 0 || previewed[len(previewed)-1] != tt.want {
+			t.Errorf("Select(%q) previewed %v, want last %d", tt.input, previewed, tt.want)
+		}
+	}
+
+	if _, err := keyPrompter("\x1b[B\x03").Select("Type", testOptions, 0, nil); err != ErrInterrupted {
+		t.Errorf("Select(Ctrl-C) error = %v, want ErrInterrupted", err)
+	}
+}
+
+func TestInputKeys(t *testing.T) {
+	var last string
+	got, err := keyPrompter("\x7f\x7f\x7f\x7fbug\r").Input("Subject", "fix typo", func(text string) string {
+		last = text
+		return "fix: " + text
+	})
+	if err != nil || got != "fix bug" {
+		t.Errorf("Input() = %q, %v; want %q", got, err, "fix bug")
+	}
+	if last != "fix bug" {
+		t.Errorf("last preview = %q, want %q", last, "fix bug")
+	}
+
+	got, _ = keyPrompter("\x15add näive\r").Input("Subject", "old", nil)
+	if got != "add näive" {
+		t.Errorf("Input() after Ctrl-U = %q", got)
+	}
+}
+
+func TestConfirmKeys(t *testing.T) {
+	for input, want := range map[string]bool{"\r": true, "n": false, "xY": true} {
+		if got, err := keyPrompter(input).Confirm("Commit?", true); err != nil || got != want {
+			t.Errorf("Confirm(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+}
+
+func TestLinePrompts(t *testing.T) {
+	p := NewReader(strings.NewReader("\nfi\nscope\n\nbody line\nsecond\n\ny\n"), io.Discard)
+
+	if i, err := p.Select("Type", testOptions, 2, nil); err != nil || i != 2 {
+		t.Errorf("Select(empty) = %d, %v; want 2", i, err)
+	}
+	if i, err := p.Select("Type", testOptions, 0, nil); err != nil || i != 1 {
+		t.Errorf("Select(prefix) = %d, %v; want 1", i, err)
+	}
+	if s, err := p.Input("Scope", "api", nil); err != nil || s != "scope" {
+		t.Errorf("Input() = %q, %v", s, err)
+	}
+	if s, err := p.Input("Subject", "keep me", nil); err != nil || s != "keep me" {
+		t.Errorf("Input(empty) = %q, %v", s, err)
+	}
+	if lines, err := p.Lines("Body"); err != nil || !reflect.DeepEqual(lines, []string{"body line", "second"}) {
+		t.Errorf("Lines() = %q, %v", lines, err)
+	}
+	if yes, err := p.Confirm("Commit?", false); err != nil || !yes {
+		t.Errorf("Confirm() = %v, %v", yes, err)
+	}
+	if yes, err := p.Confirm("Again?", true); err != nil || !yes {
+		t.Errorf("Confirm(EOF) = %v, %v; want default", yes, err)
+	}
+}
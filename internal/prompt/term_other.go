@@ -0,0 +1,15 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package prompt
+
+import "errors"
+
+// enableKeys is not supported on this platform; prompts read whole lines instead
+func enableKeys(fd int) (func(), error) {
+	return nil, errors.New("key input is not supported on this platform")
+}
+
+// terminalSize is not supported on this platform; pages assume a default size
+func terminalSize(fd int) (int, int) {
+	return 0, 0
+}
@@ -0,0 +1,36 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package prompt
+
+import "golang.org/x/sys/unix"
+
+// enableKeys switches the terminal to read single key presses without echo, so
+// arrow keys and edits can be handled as they are typed. It fails when fd is
+// not a terminal. The returned function restores the previous state.
+func enableKeys(fd int) (func(), error) {
+	state, err := unix.IoctlGetTermios(fd, ioctlReadTermios)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := *state
+	// Ctrl-C is read as a key so the terminal is always restored before exiting
+	keys.Lflag &^= unix.ECHO | unix.ICANON | unix.ISIG | unix.IEXTEN
+	keys.Iflag &^= unix.IXON | unix.ICRNL
+	keys.Cc[unix.VMIN] = 1
+	keys.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlWriteTermios, &keys); err != nil {
+		return nil, err
+	}
+	return func() { unix.IoctlSetTermios(fd, ioctlWriteTermios, state) }, nil
+}
+
+// terminalSize returns the rows and columns of the terminal on fd, or zeros
+// when fd is not a terminal
+func terminalSize(fd int) (int, int) {
+	size, err := unix.IoctlGetWinsize(fd, unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0
+	}
+	return int(size.Row), int(size.Col)
+}
@@ -0,0 +1,154 @@
+package safety
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/codeowners"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+// Kinds of findings
+const (
+	KindConflict = "conflict marker"
+	KindDebug    = "debug statement"
+)
+
+// debugPatterns are the debug statements flagged per file extension
+var debugPatterns = map[string][]*regexp.Regexp{
+	"go": {
+		regexp.MustCompile(`\bfmt\.Println\(`),
+		regexp.MustCompile(`^\s*println\(`),
+		regexp.MustCompile(`\bspew\.(Dump|Printf)\(`),
+	},
+	"js": {
+		regexp.MustCompile(`\bconsole\.(log|debug|trace)\(`),
+		regexp.MustCompile(`^\s*debugger\b`),
+	},
+	"py": {
+		regexp.MustCompile(`\bbreakpoint\(\)`),
+		regexp.MustCompile(`\bi?pdb\.set_trace\(\)`),
+		regexp.MustCompile(`^\s*import i?pdb\b`),
+	},
+	"rb": {
+		regexp.MustCompile(`\bbinding\.pry\b`),
+		regexp.MustCompile(`^\s*byebug\b`),
+	},
+	"php": {
+		regexp.MustCompile(`\b(var_dump|dd)\(`),
+	},
+	"java": {
+		regexp.MustCompile(`\bSystem\.out\.println\(`),
+	},
+	"rs": {
+		regexp.MustCompile(`\bdbg!\(`),
+	},
+}
+
+// extensionLanguages maps extensions to the language key of debugPatterns
+var extensionLanguages = map[string]string{
+	"go": "go",
+	"js": "js", "jsx": "js", "ts": "js", "tsx": "js", "mjs": "js", "cjs": "js", "vue": "js", "svelte": "js",
+	"py":   "py",
+	"rb":   "rb",
+	"php":  "php",
+	"java": "java", "kt": "java",
+	"rs": "rs",
+}
+
+// conflictRegex matches the markers git leaves in files with unresolved conflicts
+var conflictRegex = regexp.MustCompile(`^(<{7}|>{7}|\|{7})( |$)`)
+
+// hunkRegex matches a diff hunk header and captures the first line of the new side
+var hunkRegex = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// Finding is an added line that should probably not be committed
+type Finding struct {
+	File string
+	Line int // Line number in the new version of the file, 0 if unknown
+	Kind string
+	Text string
+}
+
+// String formats the finding as "file:line: kind: text"
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", f.File, f.Line, f.Kind, f.Text)
+}
+
+// Scanner finds leftover debug statements and conflict markers in added lines
+type Scanner struct {
+	extra  []*regexp.Regexp
+	ignore []string
+}
+
+// NewScanner creates a scanner flagging the built-in debug statements plus the
+// extra regular expressions. Files matching an ignore pattern are only checked
+// for conflict markers.
+func NewScanner(extra, ignore []string) (*Scanner, error) {
+	s := &Scanner{ignore: ignore}
+	for _, pattern := range extra {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid debug pattern %q: %w", pattern, err)
+		}
+		s.extra = append(s.extra, re)
+	}
+	return s, nil
+}
+
+// Scan returns the findings in the lines the changes add
+func (s *Scanner) Scan(changes []*parser.Change) []Finding {
+	var findings []Finding
+	for _, change := range changes {
+		patterns := slices.Concat(debugPatterns[extensionLanguages[strings.ToLower(change.FileExtension)]], s.extra)
+		if s.ignored(change.File) {
+			patterns = nil
+		}
+
+		line := 0
+		scanner := bufio.NewScanner(strings.NewReader(change.Diff))
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			text := scanner.Text()
+			if m := hunkRegex.FindStringSubmatch(text); m != nil {
+				line, _ = strconv.Atoi(m[1])
+				continue
+			}
+			if strings.HasPrefix(text, "+++") || strings.HasPrefix(text, "---") || strings.HasPrefix(text, "-") {
+				continue
+			}
+			if !strings.HasPrefix(text, "+") {
+				line++
+				continue
+			}
+
+			added := text[1:]
+			if conflictRegex.MatchString(added) {
+				findings = append(findings, Finding{File: change.File, Line: line, Kind: KindConflict, Text: strings.TrimSpace(added)})
+			} else {
+				for _, re := range patterns {
+					if re.MatchString(added) {
+						findings = append(findings, Finding{File: change.File, Line: line, Kind: KindDebug, Text: strings.TrimSpace(added)})
+						break
+					}
+				}
+			}
+			line++
+		}
+	}
+	return findings
+}
+
+// ignored reports whether debug statements are allowed in a file
+func (s *Scanner) ignored(file string) bool {
+	for _, pattern := range s.ignore {
+		if codeowners.Match(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package safety
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestScan(t *testing.T) {
+	changes := []*parser.Change{
+		{File: "main.go", FileExtension: "go", Diff: "diff --git a/main.go b/main.go\n" +
+			"--- a/main.go\n+++ b/main.go\n" +
+			"@@ -10,0 +11,3 @@ func main() {\n" +
+			"+\tfmt.Println(\"here\")\n" +
+			"+\tlog.Println(\"started\")\n" +
+			"+\tTODO(\"later\")\n" +
+			"@@ -20 +23 @@\n" +
+			"-\told()\n" +
+			"+<<<<<<< HEAD\n"},
+		{File: "web/app.ts", FileExtension: "ts", Diff: "@@ -1,0 +1 @@\n+  console.log(user)\n"},
+		{File: "cmd/root.go", FileExtension: "go", Diff: "@@ -1,0 +5 @@\n+\tfmt.Println(version)\n"},
+	}
+
+	s, err := NewScanner([]string{`\bTODO\(`}, []string{"cmd/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Finding{
+		{File: "main.go", Line: 11, Kind: KindDebug, Text: `fmt.Println("here")`},
+		{File: "main.go", Line: 13, Kind: KindDebug, Text: `TODO("later")`},
+		{File: "main.go", Line: 23, Kind: KindConflict, Text: "<<<<<<< HEAD"},
+		{File: "web/app.ts", Line: 1, Kind: KindDebug, Text: "console.log(user)"},
+	}
+	if got := s.Scan(changes); !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() =\n%v\nwant\n%v", got, want)
+	}
+
+	if _, err := NewScanner([]string{"("}, nil); err == nil {
+		t.Error("NewScanner() accepted an invalid pattern")
+	}
+}
@@ -0,0 +1,254 @@
+package spell
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed words.txt misspellings.txt
+var files embed.FS
+
+// minFuzzyLength is the shortest word checked against the vocabulary by edit
+// distance; shorter words are only checked against known misspellings
+const minFuzzyLength = 6
+
+// minDistance2Length is the shortest word allowed two edits from a vocabulary word
+const minDistance2Length = 9
+
+// inflections are suffixes accepted on vocabulary words
+var inflections = []string{"s", "es", "ed", "d", "ing", "ly", "er", "ers", "ion", "ions", "ation", "ations", "ment", "ments", "al", "ic", "ive", "ity"}
+
+// prefixes are accepted in front of vocabulary words, e.g. regenerate or unavailable
+var prefixes = []string{"re", "un", "in", "de", "pre", "sub", "non", "multi", "over", "under", "auto", "co"}
+
+// skipChars mark tokens that are code, paths or references rather than prose
+const skipChars = "_./\\`#@{}[]<>=:0123456789"
+
+// wordRegex matches the letters of a token, ignoring surrounding punctuation
+var wordRegex = regexp.MustCompile(`^[^\p{L}]*([\p{L}']+)[^\p{L}]*$`)
+
+// Typo is a likely misspelled word and its suggested correction
+type Typo struct {
+	Word       string
+	Suggestion string
+}
+
+// Checker finds likely typos using a small built-in vocabulary of commit
+// message words, a list of common misspellings and a project dictionary
+type Checker struct {
+	words        map[string]bool
+	vocabulary   []string
+	misspellings map[string]string
+}
+
+// NewChecker creates a checker that also accepts the given words, such as project jargon
+func NewChecker(dictionary []string) *Checker {
+	c := &Checker{words: make(map[string]bool), misspellings: make(map[string]string)}
+	for _, line := range readLines("words.txt") {
+		c.words[line] = true
+		c.vocabulary = append(c.vocabulary, line)
+	}
+	for _, line := range readLines("misspellings.txt") {
+		if typo, correction, ok := strings.Cut(line, " "); ok {
+			c.misspellings[typo] = correction
+		}
+	}
+	for _, word := range dictionary {
+		c.Add(word)
+	}
+	return c
+}
+
+// Add accepts a word from now on
+func (c *Checker) Add(word string) {
+	word = strings.ToLower(strings.TrimSpace(word))
+	if word != "" {
+		c.words[word] = true
+		delete(c.misspellings, word)
+	}
+}
+
+// Check returns the likely typos in prose text such as a commit description.
+// Identifiers, paths, acronyms, names and words with digits are skipped.
+func (c *Checker) Check(text string) []Typo {
+	var typos []Typo
+	seen := make(map[string]bool)
+	for i, token := range strings.Fields(text) {
+		m := wordRegex.FindStringSubmatch(token)
+		if m == nil || strings.ContainsAny(token, skipChars) || !isProse(m[1]) {
+			continue
+		}
+		// Capitalized words after the first are most likely names
+		if r, _ := utf8.DecodeRuneInString(m[1]); i > 0 && unicode.IsUpper(r) {
+			continue
+		}
+		word := strings.Trim(m[1], "'")
+		lower := strings.ToLower(word)
+		if seen[lower] {
+			continue
+		}
+		seen[lower] = true
+
+		suggestion, ok := c.misspellings[lower]
+		if !ok {
+			if c.known(lower) {
+				continue
+			}
+			if suggestion = c.nearest(lower); suggestion == "" {
+				continue
+			}
+		}
+		typos = append(typos, Typo{Word: word, Suggestion: matchCase(word, suggestion)})
+	}
+	return typos
+}
+
+// Correct replaces every whole-word occurrence of the typo in text with its suggestion
+func Correct(text string, typo Typo) string {
+	re := regexp.MustCompile(`\b` + regexp.QuoteMeta(typo.Word) + `\b`)
+	return re.ReplaceAllLiteralString(text, typo.Suggestion)
+}
+
+// known reports whether a lowercase word, or the word without a common prefix,
+// is accepted
+func (c *Checker) known(word string) bool {
+	word = strings.TrimSuffix(word, "'s")
+	if c.inflected(word) {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if base, ok := strings.CutPrefix(word, prefix); ok && len(base) > 3 && c.inflected(strings.TrimPrefix(base, "-")) {
+			return true
+		}
+	}
+	return false
+}
+
+// inflected reports whether a lowercase word or one of its inflections is accepted
+func (c *Checker) inflected(word string) bool {
+	if c.words[word] {
+		return true
+	}
+	for _, suffix := range inflections {
+		base, ok := strings.CutSuffix(word, suffix)
+		if !ok || base == "" {
+			continue
+		}
+		if c.words[base] || c.words[base+"e"] {
+			return true
+		}
+		// Doubled final consonant: running -> run, dropped -> drop
+		if n := len(base); n > 2 && base[n-1] == base[n-2] && c.words[base[:n-1]] {
+			return true
+		}
+	}
+	for _, suffix := range []string{"ies", "ied", "ier"} {
+		if base, ok := strings.CutSuffix(word, suffix); ok && (c.words[base+"y"] || c.words[base+"ify"]) {
+			return true
+		}
+	}
+	return false
+}
+
+// nearest returns the closest vocabulary word within the allowed edit distance, or "".
+// Typos rarely change the first letter or only the ending of a word, so candidates
+// that differ there are more likely other real words and are skipped.
+func (c *Checker) nearest(word string) string {
+	length := utf8.RuneCountInString(word)
+	if length < minFuzzyLength {
+		return ""
+	}
+	maxDistance := 1
+	if length >= minDistance2Length {
+		maxDistance = 2
+	}
+
+	best, bestDistance := "", maxDistance+1
+	for _, candidate := range c.vocabulary {
+		if abs(utf8.RuneCountInString(candidate)-length) > maxDistance || candidate[0] != word[0] ||
+			strings.HasPrefix(word, candidate) || strings.HasPrefix(candidate, word) {
+			continue
+		}
+		if d := distance(word, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// isProse reports whether a word looks like prose rather than an acronym or identifier
+func isProse(word string) bool {
+	for i, r := range word {
+		if i > 0 && unicode.IsUpper(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchCase capitalizes the suggestion when the original word was capitalized
+func matchCase(word, suggestion string) string {
+	if r, _ := utf8.DecodeRuneInString(word); unicode.IsUpper(r) {
+		s, size := utf8.DecodeRuneInString(suggestion)
+		return string(unicode.ToUpper(s)) + suggestion[size:]
+	}
+	return suggestion
+}
+
+// distance returns the optimal string alignment distance between two words:
+// insertions, deletions, substitutions and transpositions of adjacent letters
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
+
+// readLines returns the non-comment lines of an embedded file
+func readLines(name string) []string {
+	data, err := files.ReadFile(name)
+	if err != nil {
+		return nil
+	}
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,50 @@
+package spell
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	c := NewChecker([]string{"gitmit"})
+
+	tests := []struct {
+		text string
+		want []Typo
+	}{
+		{"add retry to client", nil},
+		{"Implment retrying of failed uploads", []Typo{{"Implment", "Implement"}}},
+		{"handle recieve errors in parser", []Typo{{"recieve", "receive"}}},
+		{"update configuation for gitmit", []Typo{{"configuation", "configuration"}}},
+		{"rename parseHTTP in internal/api_v2.go", nil},
+		{"support OAuth and JWT tokens", nil},
+		{"running dropped batches later", nil},
+		{"regenerate unavailable entries for Kubernetes", nil},
+	}
+
+	for _, tt := range tests {
+		if got := c.Check(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Check(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCorrect(t *testing.T) {
+	got := Correct("fix: handle recieve errors", Typo{"recieve", "receive"})
+	if got != "fix: handle receive errors" {
+		t.Errorf("Correct() = %q", got)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := map[[2]string]int{
+		{"implment", "implement"}: 1,
+		{"udpate", "update"}:      1,
+		{"kitten", "sitting"}:     3,
+	}
+	for words, want := range tests {
+		if got := distance(words[0], words[1]); got != want {
+			t.Errorf("distance(%q, %q) = %d, want %d", words[0], words[1], got, want)
+		}
+	}
+}
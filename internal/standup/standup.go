@@ -0,0 +1,155 @@
+package standup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/changelog"
+	"github.com/andev0x/gitmit/internal/style"
+)
+
+// maxItems is the number of changes listed per group in the text summary
+const maxItems = 4
+
+// Repo is the work done in one repository
+type Repo struct {
+	Name    string
+	Entries []*changelog.Entry
+}
+
+// Text summarizes the work in sentences, one line per repository, e.g.
+// "gitmit (3 commits): In api, added user export and fixed nil user."
+// Changes are grouped by area, or by commit type when byType is set, e.g.
+// "Features: added user export."
+func Text(repos []*Repo, byType bool) string {
+	var lines []string
+	for _, repo := range repos {
+		entries := relevant(repo.Entries)
+		if len(entries) == 0 {
+			continue
+		}
+
+		var sentences []string
+		for _, group := range groups(entries, byType) {
+			var items []string
+			for _, entry := range group.Entries {
+				items = append(items, describe(entry, true))
+			}
+			if len(items) > maxItems {
+				items = append(items[:maxItems], fmt.Sprintf("%d more changes", len(items)-maxItems))
+			}
+			if byType {
+				sentences = append(sentences, fmt.Sprintf("%s: %s.", capitalize(strings.ToLower(changelog.TypeTitle(group.Key))), joinList(items)))
+			} else {
+				sentences = append(sentences, fmt.Sprintf("In %s, %s.", group.Key, joinList(items)))
+			}
+		}
+
+		line := strings.Join(sentences, " ")
+		if len(repos) > 1 {
+			line = fmt.Sprintf("%s (%s): %s", repo.Name, commits(len(repo.Entries)), line)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return "No commits."
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Markdown lists the work as markdown, with a section per repository and the
+// changes grouped by area, or by commit type when byType is set
+func Markdown(repos []*Repo, byType bool) string {
+	var b strings.Builder
+	for _, repo := range repos {
+		entries := relevant(repo.Entries)
+		if len(entries) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "### %s\n", repo.Name)
+		for _, group := range groups(entries, byType) {
+			title := group.Key
+			if byType {
+				title = changelog.TypeTitle(group.Key)
+			}
+			fmt.Fprintf(&b, "\n**%s**\n\n", title)
+			for _, entry := range group.Entries {
+				fmt.Fprintf(&b, "- %s (`%s`)\n", capitalize(describe(entry, false)), entry.Commit.ShortHash())
+			}
+		}
+	}
+	if b.Len() == 0 {
+		return "_No commits._\n"
+	}
+	return b.String()
+}
+
+// Log lists the commit subjects of every repository, as input for the LLM
+func Log(repos []*Repo) string {
+	var b strings.Builder
+	for _, repo := range repos {
+		for _, entry := range relevant(repo.Entries) {
+			fmt.Fprintf(&b, "- [%s] %s\n", repo.Name, entry.Commit.Subject)
+		}
+	}
+	return b.String()
+}
+
+// relevant drops fixups and other commits that say nothing about the work
+func relevant(entries []*changelog.Entry) []*changelog.Entry {
+	var kept []*changelog.Entry
+	for _, entry := range entries {
+		if !entry.IsNoise() {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// groups groups entries by area or by type
+func groups(entries []*changelog.Entry, byType bool) []changelog.Group {
+	if byType {
+		return changelog.GroupByType(entries)
+	}
+	return changelog.GroupByArea(entries)
+}
+
+// describe returns the description of a change, in past tense for sentences.
+// Breaking changes are marked.
+func describe(entry *changelog.Entry, past bool) string {
+	description := entry.Description
+	if past {
+		description = style.Past(description)
+	}
+	if entry.Breaking {
+		description += " (breaking)"
+	}
+	return description
+}
+
+// commits formats a number of commits
+func commits(n int) string {
+	if n == 1 {
+		return "1 commit"
+	}
+	return fmt.Sprintf("%d commits", n)
+}
+
+// joinList joins items as in "a, b and c"
+func joinList(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// capitalize upper-cases the first letter
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
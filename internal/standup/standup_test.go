@@ -0,0 +1,46 @@
+package standup
+
+import (
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/changelog"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func testRepos() []*Repo {
+	return []*Repo{
+		{Name: "gitmit", Entries: changelog.Parse([]*parser.Commit{
+			{Hash: "aaaaaaaaa", Subject: "feat(api): add user export"},
+			{Hash: "bbbbbbbbb", Subject: "fix(api): handle nil user"},
+			{Hash: "ccccccccc", Subject: "fixup! fix(api): handle nil user"},
+			{Hash: "ddddddddd", Subject: "docs: update install guide", Files: []string{"docs/guide/INSTALL.md"}},
+		})},
+		{Name: "website", Entries: changelog.Parse([]*parser.Commit{
+			{Hash: "eeeeeeeee", Subject: "refactor(nav)!: drop legacy menu", Body: "BREAKING CHANGE: menu links moved"},
+		})},
+	}
+}
+
+func TestText(t *testing.T) {
+	want := "gitmit (4 commits): In api, added user export and handled nil user. In guide, updated install guide.\n" +
+		"website (1 commit): In nav, dropped legacy menu (breaking)."
+	if got := Text(testRepos(), false); got != want {
+		t.Errorf("Text() =\n%s\nwant\n%s", got, want)
+	}
+
+	want = "Features: added user export. Bug fixes: handled nil user. Documentation: updated install guide."
+	if got := Text(testRepos()[:1], true); got != want {
+		t.Errorf("Text(byType) =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := Text(nil, false); got != "No commits." {
+		t.Errorf("Text(nil) = %q", got)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	want := "### website\n\n**Refactoring**\n\n- Drop legacy menu (breaking) (`eeeeeee`)\n"
+	if got := Markdown(testRepos()[1:], true); got != want {
+		t.Errorf("Markdown() =\n%s\nwant\n%s", got, want)
+	}
+}
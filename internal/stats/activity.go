@@ -0,0 +1,95 @@
+package stats
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// velocityWindow is the number of weeks the rolling velocity averages
+const velocityWindow = 4
+
+// sparkBlocks are the bars of a sparkline, from lowest to highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Weekday is the activity on a day of the week
+type Weekday struct {
+	Day     string  `json:"day"`
+	Commits int     `json:"commits"`
+	Average float64 `json:"average"` // Commits per week on that day
+}
+
+// weekStart returns the Monday starting the week of a date, as 2006-01-02
+func weekStart(date time.Time) string {
+	offset := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -offset).Format("2006-01-02")
+}
+
+// addActivity counts a commit in its week and on its weekday
+func (c *Collector) addActivity(date time.Time) {
+	c.weeks[weekStart(date)]++
+	c.weekdays[(int(date.Weekday())+6)%7]++
+}
+
+// activity sets the weekly series, velocity and weekday averages of the stats
+func (c *Collector) activity(s *Stats) {
+	if s.First.IsZero() {
+		return
+	}
+
+	if c.opts.Weeks > 0 {
+		// The series ends with the week of Now, or of the newest commit for a range ending earlier
+		end := c.opts.Now
+		if s.Last.Before(end.AddDate(0, 0, -7)) {
+			end = s.Last
+		}
+		for i := c.opts.Weeks - 1; i >= 0; i-- {
+			week := weekStart(end.AddDate(0, 0, -7*i))
+			s.Weekly = append(s.Weekly, Count{Name: week, Commits: c.weeks[week]})
+
+			sum := 0
+			for j := 0; j < velocityWindow; j++ {
+				sum += c.weeks[weekStart(end.AddDate(0, 0, -7*(i+j)))]
+			}
+			s.Velocity = append(s.Velocity, math.Round(float64(sum)/velocityWindow*10)/10)
+		}
+	}
+
+	// Averages are over the weeks the history spans
+	weeks := math.Max(math.Ceil(s.Last.Sub(s.First).Hours()/(24*7)), 1)
+	for i, n := range c.weekdays {
+		day := time.Weekday((i + 1) % 7).String()[:3]
+		s.Weekdays = append(s.Weekdays, Weekday{Day: day, Commits: n, Average: math.Round(float64(n)/weeks*10) / 10})
+	}
+}
+
+// VelocityChange returns the change in percent of the latest velocity over the
+// velocity a window earlier, and false when there is nothing to compare
+func (s *Stats) VelocityChange() (int, bool) {
+	if len(s.Velocity) <= velocityWindow {
+		return 0, false
+	}
+	current, previous := s.Velocity[len(s.Velocity)-1], s.Velocity[len(s.Velocity)-1-velocityWindow]
+	if previous == 0 {
+		return 0, false
+	}
+	return int(math.Round((current - previous) / previous * 100)), true
+}
+
+// Sparkline draws counts as a line of bars scaled to the largest count. Zero
+// counts are drawn as spaces so that gaps in activity stand out.
+func Sparkline(counts []Count) string {
+	peak := 0
+	for _, c := range counts {
+		peak = max(peak, c.Commits)
+	}
+	var b strings.Builder
+	for _, c := range counts {
+		if c.Commits == 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		b.WriteRune(sparkBlocks[c.Commits*(len(sparkBlocks)-1)/peak])
+	}
+	return b.String()
+}
@@ -0,0 +1,83 @@
+package stats
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/andev0x/gitmit/internal/changelog"
+)
+
+// maxTrendPeriods is the number of most recent months a text trend shows
+const maxTrendPeriods = 12
+
+// Compliance is how well commits follow the commit conventions
+type Compliance struct {
+	Compliant  int      `json:"compliant"`  // Commits breaking no rule
+	Violations []Count  `json:"violations"` // Commits breaking each rule
+	Trend      []Period `json:"trend"`      // Compliance per month, oldest first
+}
+
+// Period is the compliance of the commits of a month
+type Period struct {
+	Name      string `json:"period"` // Month, as 2006-01
+	Commits   int    `json:"commits"`
+	Compliant int    `json:"compliant"`
+}
+
+// Percent returns the share of compliant commits of the period, from 0 to 100
+func (p Period) Percent() int {
+	if p.Commits == 0 {
+		return 0
+	}
+	return p.Compliant * 100 / p.Commits
+}
+
+// Percent returns the share of compliant commits among total commits, from 0 to 100
+func (c *Compliance) Percent(total int) int {
+	if total == 0 {
+		return 0
+	}
+	return c.Compliant * 100 / total
+}
+
+// RecentTrend returns the trend of the last months
+func (c *Compliance) RecentTrend() []Period {
+	if len(c.Trend) > maxTrendPeriods {
+		return c.Trend[len(c.Trend)-maxTrendPeriods:]
+	}
+	return c.Trend
+}
+
+// addCompliance checks a commit against the rules of the options
+func (c *Collector) addCompliance(entry *changelog.Entry) {
+	rules := c.opts.Check(entry)
+	if len(rules) == 0 {
+		c.compliant++
+	}
+	for _, rule := range rules {
+		c.violations[rule]++
+	}
+
+	if entry.Commit.Date.IsZero() {
+		return
+	}
+	month := entry.Commit.Date.Format("2006-01")
+	period := c.periods[month]
+	if period == nil {
+		period = &Period{Name: month}
+		c.periods[month] = period
+	}
+	period.Commits++
+	if len(rules) == 0 {
+		period.Compliant++
+	}
+}
+
+// compliance returns the compliance of the commits added so far
+func (c *Collector) compliance() *Compliance {
+	compliance := &Compliance{Compliant: c.compliant, Violations: sortedCounts(c.violations, 0)}
+	for _, month := range slices.Sorted(maps.Keys(c.periods)) {
+		compliance.Trend = append(compliance.Trend, *c.periods[month])
+	}
+	return compliance
+}
@@ -0,0 +1,341 @@
+package stats
+
+import (
+	"container/heap"
+	"encoding/csv"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/andev0x/gitmit/internal/changelog"
+)
+
+// Count is a number of commits for a name, such as a type, author or file
+type Count struct {
+	Name    string `json:"name"`
+	Commits int    `json:"commits"`
+}
+
+// Breakdown is the activity of an author or scope, with its commits per type
+type Breakdown struct {
+	Name    string  `json:"name"`
+	Commits int     `json:"commits"`
+	Types   []Count `json:"types"`
+}
+
+// TypeCommits returns the number of commits of a type
+func (b *Breakdown) TypeCommits(commitType string) int {
+	for _, c := range b.Types {
+		if c.Name == commitType {
+			return c.Commits
+		}
+	}
+	return 0
+}
+
+// Options controls which statistics are kept
+type Options struct {
+	Top   int       // Number of authors, scopes and files kept, or all when 0
+	Now   time.Time // Reference time of recent activity
+	Weeks int       // Number of weeks in the weekly activity series, or none when 0
+
+	// Directories counts the commits per directory instead of per file
+	Directories bool
+
+	// Check returns the rules a commit breaks, for the compliance report, which is
+	// left out when Check is nil
+	Check func(*changelog.Entry) []string
+}
+
+// Stats are statistics of a range of commits
+type Stats struct {
+	Since        string      `json:"since,omitempty"`
+	Until        string      `json:"until,omitempty"`
+	Author       string      `json:"author,omitempty"`
+	Ignored      int         `json:"ignored"` // Commits of ignored authors, not counted
+	Commits      int         `json:"commits"`
+	Conventional int         `json:"conventional"` // Commits with a conventional type
+	First        time.Time   `json:"first"`        // Date of the oldest commit
+	Last         time.Time   `json:"last"`         // Date of the newest commit
+	LastWeek     int         `json:"lastWeek"`     // Commits in the 7 days before Options.Now
+	LastMonth    int         `json:"lastMonth"`    // Commits in the 30 days before Options.Now
+	Types        []Count     `json:"types"`
+	Authors      []Breakdown `json:"authors"`
+	Scopes       []Breakdown `json:"scopes"` // Conventional scopes
+	Files        []Count     `json:"files"`
+	Weekly       []Count     `json:"weekly,omitempty"`   // Commits per week, named by its Monday, oldest first
+	Velocity     []float64   `json:"velocity,omitempty"` // Rolling average of weekly commits over 4 weeks, per week of Weekly
+	Weekdays     []Weekday   `json:"weekdays"`           // Activity per day of the week, Monday first
+	Compliance   *Compliance `json:"compliance,omitempty"`
+}
+
+// Compute gathers statistics of commits. Counts are sorted by decreasing number
+// of commits, then by name.
+func Compute(entries []*changelog.Entry, opts Options) *Stats {
+	c := NewCollector(opts)
+	for _, entry := range entries {
+		c.Add(entry)
+	}
+	return c.Stats()
+}
+
+// Collector gathers statistics one commit at a time, so that commits streamed
+// from a large history need not be kept
+type Collector struct {
+	opts    Options
+	stats   Stats
+	types   map[string]int
+	authors map[string]map[string]int
+	scopes  map[string]map[string]int
+	files   map[string]int
+	renames map[string]string // Current path of renamed files, by old path
+
+	compliant  int
+	violations map[string]int
+	periods    map[string]*Period
+
+	weeks    map[string]int
+	weekdays [7]int // Commits per day of the week, Monday first
+}
+
+// NewCollector returns an empty collector
+func NewCollector(opts Options) *Collector {
+	return &Collector{
+		opts:    opts,
+		types:   map[string]int{},
+		authors: map[string]map[string]int{},
+		scopes:  map[string]map[string]int{},
+		files:   map[string]int{},
+		renames: map[string]string{},
+
+		violations: map[string]int{},
+		periods:    map[string]*Period{},
+
+		weeks: map[string]int{},
+	}
+}
+
+// Add counts a commit
+func (c *Collector) Add(entry *changelog.Entry) {
+	s := &c.stats
+	commit := entry.Commit
+	s.Commits++
+	commitType := entry.Type
+	if commitType == "" {
+		commitType = "other"
+	} else {
+		s.Conventional++
+	}
+	c.types[commitType]++
+	addBreakdown(c.authors, commit.Author, commitType)
+	if entry.Scope != "" {
+		addBreakdown(c.scopes, entry.Scope, commitType)
+	}
+	counted := map[string]bool{}
+	for _, file := range commit.Files {
+		file = c.currentPath(file)
+		if c.opts.Directories {
+			file = path.Dir(file)
+		}
+		if !counted[file] {
+			counted[file] = true
+			c.files[file]++
+		}
+	}
+	// Commits arrive newest first, so older commits count renamed files under their current path
+	for old, renamed := range commit.Renamed {
+		if current := c.currentPath(renamed); current != old {
+			c.renames[old] = current
+		}
+	}
+	if c.opts.Check != nil {
+		c.addCompliance(entry)
+	}
+
+	if !commit.Date.IsZero() {
+		if s.First.IsZero() || commit.Date.Before(s.First) {
+			s.First = commit.Date
+		}
+		if commit.Date.After(s.Last) {
+			s.Last = commit.Date
+		}
+		if age := c.opts.Now.Sub(commit.Date); age >= 0 && age < 30*24*time.Hour {
+			s.LastMonth++
+			if age < 7*24*time.Hour {
+				s.LastWeek++
+			}
+		}
+		c.addActivity(commit.Date)
+	}
+}
+
+// currentPath returns the path a file was last renamed to, or the file itself
+func (c *Collector) currentPath(file string) string {
+	for {
+		renamed, ok := c.renames[file]
+		if !ok {
+			return file
+		}
+		file = renamed
+	}
+}
+
+// Stats returns the statistics of the commits added so far
+func (c *Collector) Stats() *Stats {
+	s := c.stats
+	s.Types = sortedCounts(c.types, 0)
+	s.Authors = breakdowns(c.authors, c.opts.Top)
+	s.Scopes = breakdowns(c.scopes, c.opts.Top)
+	s.Files = sortedCounts(c.files, c.opts.Top)
+	if c.opts.Check != nil {
+		s.Compliance = c.compliance()
+	}
+	c.activity(&s)
+	return &s
+}
+
+// ConventionalPercent returns the share of conventional commits, from 0 to 100
+func (s *Stats) ConventionalPercent() int {
+	if s.Commits == 0 {
+		return 0
+	}
+	return s.Conventional * 100 / s.Commits
+}
+
+// WriteCSV writes the statistics as section,name,type,commits rows. The type is
+// set in the per-type rows of authors and scopes, which follow their total row,
+// in the velocity rows of weeks and in the compliant rows of months.
+func (s *Stats) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{
+		{"section", "name", "type", "commits"},
+		{"total", "commits", "", strconv.Itoa(s.Commits)},
+		{"total", "conventional", "", strconv.Itoa(s.Conventional)},
+		{"total", "ignored", "", strconv.Itoa(s.Ignored)},
+		{"total", "last week", "", strconv.Itoa(s.LastWeek)},
+		{"total", "last month", "", strconv.Itoa(s.LastMonth)},
+	}
+	for _, c := range s.Types {
+		rows = append(rows, []string{"type", c.Name, "", strconv.Itoa(c.Commits)})
+	}
+	for _, section := range []struct {
+		name       string
+		breakdowns []Breakdown
+	}{
+		{"author", s.Authors},
+		{"scope", s.Scopes},
+	} {
+		for _, b := range section.breakdowns {
+			rows = append(rows, []string{section.name, b.Name, "", strconv.Itoa(b.Commits)})
+			for _, c := range b.Types {
+				rows = append(rows, []string{section.name, b.Name, c.Name, strconv.Itoa(c.Commits)})
+			}
+		}
+	}
+	for _, c := range s.Files {
+		rows = append(rows, []string{"file", c.Name, "", strconv.Itoa(c.Commits)})
+	}
+	for i, c := range s.Weekly {
+		rows = append(rows,
+			[]string{"week", c.Name, "", strconv.Itoa(c.Commits)},
+			[]string{"week", c.Name, "velocity", strconv.FormatFloat(s.Velocity[i], 'f', 1, 64)})
+	}
+	for _, d := range s.Weekdays {
+		rows = append(rows, []string{"weekday", d.Day, "", strconv.Itoa(d.Commits)})
+	}
+	if s.Compliance != nil {
+		rows = append(rows, []string{"total", "compliant", "", strconv.Itoa(s.Compliance.Compliant)})
+		for _, c := range s.Compliance.Violations {
+			rows = append(rows, []string{"violation", c.Name, "", strconv.Itoa(c.Commits)})
+		}
+		for _, p := range s.Compliance.Trend {
+			rows = append(rows,
+				[]string{"month", p.Name, "", strconv.Itoa(p.Commits)},
+				[]string{"month", p.Name, "compliant", strconv.Itoa(p.Compliant)})
+		}
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// addBreakdown counts a commit of a type for a name
+func addBreakdown(breakdowns map[string]map[string]int, name, commitType string) {
+	if breakdowns[name] == nil {
+		breakdowns[name] = map[string]int{}
+	}
+	breakdowns[name][commitType]++
+}
+
+// breakdowns returns the breakdowns sorted by decreasing number of commits,
+// keeping the first top ones when top is positive
+func breakdowns(types map[string]map[string]int, top int) []Breakdown {
+	totals := map[string]int{}
+	for name, counts := range types {
+		for _, n := range counts {
+			totals[name] += n
+		}
+	}
+	var list []Breakdown
+	for _, c := range sortedCounts(totals, top) {
+		list = append(list, Breakdown{Name: c.Name, Commits: c.Commits, Types: sortedCounts(types[c.Name], 0)})
+	}
+	return list
+}
+
+// sortedCounts returns the counts sorted by decreasing number of commits, keeping
+// the first top ones when top is positive. Only the kept counts are sorted, so
+// selecting the top files of a large history stays cheap.
+func sortedCounts(counts map[string]int, top int) []Count {
+	if top <= 0 || top >= len(counts) {
+		list := make([]Count, 0, len(counts))
+		for name, n := range counts {
+			list = append(list, Count{Name: name, Commits: n})
+		}
+		sort.Slice(list, func(i, j int) bool { return ranksBefore(list[i], list[j]) })
+		return list
+	}
+
+	// A min-heap of the best counts seen so far, whose root is the worst of them
+	h := make(countHeap, 0, top)
+	for name, n := range counts {
+		c := Count{Name: name, Commits: n}
+		if len(h) < top {
+			heap.Push(&h, c)
+		} else if ranksBefore(c, h[0]) {
+			h[0] = c
+			heap.Fix(&h, 0)
+		}
+	}
+	list := make([]Count, len(h))
+	for i := len(h) - 1; i >= 0; i-- {
+		list[i] = heap.Pop(&h).(Count)
+	}
+	return list
+}
+
+// ranksBefore reports whether a count is listed before another: with more commits,
+// or as many and a smaller name
+func ranksBefore(a, b Count) bool {
+	if a.Commits != b.Commits {
+		return a.Commits > b.Commits
+	}
+	return a.Name < b.Name
+}
+
+// countHeap is a heap of counts whose root ranks last
+type countHeap []Count
+
+func (h countHeap) Len() int            { return len(h) }
+func (h countHeap) Less(i, j int) bool  { return ranksBefore(h[j], h[i]) }
+func (h countHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *countHeap) Push(x interface{}) { *h = append(*h, x.(Count)) }
+func (h *countHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,179 @@
+package stats
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andev0x/gitmit/internal/changelog"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+var now = time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+
+func testEntries() []*changelog.Entry {
+	return changelog.Parse([]*parser.Commit{
+		{Author: "Ada", Date: now.AddDate(0, 0, -1), Subject: "fix(api): handle nil user", Files: []string{"api/user.go"}},
+		{Author: "Ada Lovelace", Date: now.AddDate(0, 0, -10), Subject: "feat: add export", Files: []string{"api/user.go", "cmd/export.go"}},
+		{Author: "Bob", Date: now.AddDate(0, -3, 0), Subject: "Update README", Files: []string{"README.md"}},
+		{Author: "Ada", Date: now.AddDate(0, 0, -2), Subject: "fix: typo", Files: []string{"api/user.go"}},
+	})
+}
+
+func TestCompute(t *testing.T) {
+	s := Compute(testEntries(), Options{Top: 2, Now: now})
+
+	if s.Commits != 4 || s.Conventional != 3 || s.ConventionalPercent() != 75 {
+		t.Errorf("totals = %d commits, %d conventional", s.Commits, s.Conventional)
+	}
+	if s.LastWeek != 2 || s.LastMonth != 3 {
+		t.Errorf("recent activity = %d last week, %d last month, want 2 and 3", s.LastWeek, s.LastMonth)
+	}
+	if !s.First.Equal(now.AddDate(0, -3, 0)) || !s.Last.Equal(now.AddDate(0, 0, -1)) {
+		t.Errorf("dates = %v to %v", s.First, s.Last)
+	}
+	wantTypes := []Count{{"fix", 2}, {"feat", 1}, {"other", 1}}
+	if !reflect.DeepEqual(s.Types, wantTypes) {
+		t.Errorf("Types = %v, want %v", s.Types, wantTypes)
+	}
+	wantAuthors := []Breakdown{
+		{Name: "Ada", Commits: 2, Types: []Count{{"fix", 2}}},
+		{Name: "Ada Lovelace", Commits: 1, Types: []Count{{"feat", 1}}},
+	}
+	if !reflect.DeepEqual(s.Authors, wantAuthors) {
+		t.Errorf("Authors = %+v, want %+v (names with spaces kept, top 2)", s.Authors, wantAuthors)
+	}
+	wantScopes := []Breakdown{{Name: "api", Commits: 1, Types: []Count{{"fix", 1}}}}
+	if !reflect.DeepEqual(s.Scopes, wantScopes) {
+		t.Errorf("Scopes = %+v, want %+v", s.Scopes, wantScopes)
+	}
+	if n := s.Scopes[0].TypeCommits("fix"); n != 1 {
+		t.Errorf("TypeCommits(fix) = %d, want 1", n)
+	}
+	if len(s.Files) != 2 || s.Files[0] != (Count{"api/user.go", 3}) {
+		t.Errorf("Files = %v", s.Files)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Compute(testEntries(), Options{Now: now}).WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"section,name,type,commits\n", "total,commits,,4\n", "type,fix,,2\n", "author,Ada Lovelace,,1\n", "author,Ada Lovelace,feat,1\n", "scope,api,fix,1\n", "file,README.md,,1\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("CSV missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestSortedCounts(t *testing.T) {
+	counts := map[string]int{}
+	for i := 0; i < 500; i++ {
+		counts[fmt.Sprintf("file%03d.go", i)] = i % 50
+	}
+	all := sortedCounts(counts, 0)
+	if len(all) != 500 {
+		t.Fatalf("sortedCounts(0) kept %d counts, want 500", len(all))
+	}
+	for _, top := range []int{1, 7, 30} {
+		if got := sortedCounts(counts, top); !reflect.DeepEqual(got, all[:top]) {
+			t.Errorf("sortedCounts(%d) = %v, want %v", top, got, all[:top])
+		}
+	}
+}
+
+func TestCompliance(t *testing.T) {
+	check := func(entry *changelog.Entry) []string {
+		if entry.Type == "" {
+			return []string{"conventional"}
+		}
+		if len(entry.Description) < 5 {
+			return []string{"short"}
+		}
+		return nil
+	}
+	s := Compute(testEntries(), Options{Now: now, Check: check})
+	c := s.Compliance
+	if c == nil {
+		t.Fatal("Compliance = nil with a check")
+	}
+	if c.Compliant != 2 || c.Percent(s.Commits) != 50 {
+		t.Errorf("Compliant = %d (%d%%), want 2 (50%%)", c.Compliant, c.Percent(s.Commits))
+	}
+	if want := []Count{{"conventional", 1}, {"short", 1}}; !reflect.DeepEqual(c.Violations, want) {
+		t.Errorf("Violations = %v, want %v", c.Violations, want)
+	}
+	want := []Period{{Name: "2024-03", Commits: 1}, {Name: "2024-06", Commits: 3, Compliant: 2}}
+	if !reflect.DeepEqual(c.Trend, want) {
+		t.Errorf("Trend = %+v, want %+v", c.Trend, want)
+	}
+	if p := c.Trend[1].Percent(); p != 66 {
+		t.Errorf("Percent() = %d, want 66", p)
+	}
+
+	if s := Compute(testEntries(), Options{Now: now}); s.Compliance != nil {
+		t.Error("Compliance should be nil without a check")
+	}
+}
+
+func TestActivity(t *testing.T) {
+	// now is a Sunday, so its week starts on 2024-06-24
+	s := Compute(testEntries(), Options{Now: now, Weeks: 6})
+
+	want := []Count{{"2024-05-20", 0}, {"2024-05-27", 0}, {"2024-06-03", 0}, {"2024-06-10", 0}, {"2024-06-17", 1}, {"2024-06-24", 2}}
+	if !reflect.DeepEqual(s.Weekly, want) {
+		t.Errorf("Weekly = %v, want %v", s.Weekly, want)
+	}
+	if wantVelocity := []float64{0, 0, 0, 0, 0.3, 0.8}; !reflect.DeepEqual(s.Velocity, wantVelocity) {
+		t.Errorf("Velocity = %v, want %v", s.Velocity, wantVelocity)
+	}
+	if _, ok := s.VelocityChange(); ok {
+		t.Error("VelocityChange() should have nothing to compare with a zero velocity")
+	}
+	if len(s.Weekdays) != 7 || s.Weekdays[0].Day != "Mon" || s.Weekdays[5] != (Weekday{Day: "Sat", Commits: 2, Average: 0.2}) {
+		t.Errorf("Weekdays = %+v", s.Weekdays)
+	}
+
+	s.Velocity = []float64{2, 2, 2, 2, 2.5}
+	if change, ok := s.VelocityChange(); !ok || change != 25 {
+		t.Errorf("VelocityChange() = %d, %v, want 25", change, ok)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	counts := []Count{{"a", 1}, {"b", 0}, {"c", 4}, {"d", 8}}
+	if got := Sparkline(counts); got != "▁ ▄█" {
+		t.Errorf("Sparkline() = %q, want %q", got, "▁ ▄█")
+	}
+	if got := Sparkline(nil); got != "" {
+		t.Errorf("Sparkline(nil) = %q", got)
+	}
+}
+
+func TestRenamedFiles(t *testing.T) {
+	// Newest first, as git log lists them: user.go moved to internal/, then back
+	entries := changelog.Parse([]*parser.Commit{
+		{Subject: "fix: nil user", Files: []string{"api/user.go"}},
+		{Subject: "refactor: move user back", Files: []string{"api/user.go"}, Renamed: map[string]string{"internal/user.go": "api/user.go"}},
+		{Subject: "fix: user name", Files: []string{"internal/user.go", "internal/db.go"}},
+		{Subject: "refactor: move user", Files: []string{"internal/user.go"}, Renamed: map[string]string{"api/user.go": "internal/user.go"}},
+		{Subject: "feat: add user", Files: []string{"api/user.go"}},
+	})
+
+	s := Compute(entries, Options{Now: now})
+	want := []Count{{"api/user.go", 5}, {"internal/db.go", 1}}
+	if !reflect.DeepEqual(s.Files, want) {
+		t.Errorf("Files = %v, want %v", s.Files, want)
+	}
+
+	s = Compute(entries, Options{Now: now, Directories: true})
+	want = []Count{{"api", 5}, {"internal", 1}}
+	if !reflect.DeepEqual(s.Files, want) {
+		t.Errorf("Files by directory = %v, want %v", s.Files, want)
+	}
+}
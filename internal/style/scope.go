@@ -0,0 +1,120 @@
+package style
+
+import (
+	"regexp"
+	"strings"
+)
+
+// maxPromptScopes limits how many known scopes are listed in the LLM prompt
+const maxPromptScopes = 30
+
+// ticketPrefixRegex matches the ticket prefix branch policies put before a
+// subject, such as "[PAY-42] ", "PAY-42: " or "#42 "
+var ticketPrefixRegex = regexp.MustCompile(`^(?:\[[^\]]+\]|[A-Z][A-Z0-9_]*-[0-9]+:?|#[0-9]+:?)\s+`)
+
+// splitTicket splits the ticket prefix off a subject
+func splitTicket(subject string) (string, string) {
+	prefix := ticketPrefixRegex.FindString(subject)
+	return prefix, subject[len(prefix):]
+}
+
+// SubjectScope returns the scope of a conventional commit subject, or an empty string
+func SubjectScope(subject string) string {
+	_, rest := splitTicket(Subject(subject))
+	_, rest = splitEmoji(rest)
+	m := conventionalRegex.FindStringSubmatch(rest)
+	if m == nil {
+		return ""
+	}
+	return m[3]
+}
+
+// SetScope replaces the scope of a conventional commit message; an empty scope
+// removes it. A ticket prefix before the type is kept; messages without a type
+// prefix are returned unchanged.
+func SetScope(message, scope string) string {
+	subject, rest, hasRest := strings.Cut(message, "\n")
+	ticket, subject := splitTicket(subject)
+	emoji, subject := splitEmoji(subject)
+	m := conventionalRegex.FindStringSubmatch(subject)
+	if m == nil {
+		return message
+	}
+
+	prefix := ticket + emoji + m[1]
+	if scope != "" {
+		prefix += "(" + scope + ")"
+	}
+	subject = prefix + m[4] + ": " + m[5]
+	if hasRest {
+		return subject + "\n" + rest
+	}
+	return subject
+}
+
+// MatchScope finds the known scope that best matches a scope: an exact
+// case-insensitive match, then a known scope containing it or contained in it.
+// It returns an empty string when nothing matches.
+func MatchScope(scope string, known []string) string {
+	lower := strings.ToLower(strings.TrimSpace(scope))
+	if lower == "" {
+		return ""
+	}
+	for _, k := range known {
+		if strings.ToLower(k) == lower {
+			return k
+		}
+	}
+	for _, k := range known {
+		kl := strings.ToLower(k)
+		if strings.Contains(kl, lower) || strings.Contains(lower, kl) {
+			return k
+		}
+	}
+	return ""
+}
+
+// CompleteScope autocompletes typed input against known scopes: an exact match
+// or the single known scope starting with the input. It returns the input
+// unchanged and false when the input is ambiguous or unknown.
+func CompleteScope(input string, known []string) (string, bool) {
+	lower := strings.ToLower(strings.TrimSpace(input))
+	var matches []string
+	for _, k := range known {
+		kl := strings.ToLower(k)
+		if kl == lower {
+			return k, true
+		}
+		if strings.HasPrefix(kl, lower) {
+			matches = append(matches, k)
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], true
+	}
+	return input, false
+}
+
+// Subject returns the subject of a commit message, its first line
+func Subject(message string) string {
+	subject, _, _ := strings.Cut(message, "\n")
+	return subject
+}
+
+// AliasScope translates the scope of a subject to its canonical name from an
+// alias map, matching keys case-insensitively. Unmapped scopes are kept.
+func AliasScope(subject string, aliases map[string]string) string {
+	scope := SubjectScope(subject)
+	if scope == "" || len(aliases) == 0 {
+		return subject
+	}
+	if canonical, ok := aliases[scope]; ok {
+		return SetScope(subject, canonical)
+	}
+	for alias, canonical := range aliases {
+		if strings.EqualFold(alias, scope) {
+			return SetScope(subject, canonical)
+		}
+	}
+	return subject
+}
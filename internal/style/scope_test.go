@@ -0,0 +1,83 @@
+package style
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetScope(t *testing.T) {
+	tests := []struct {
+		message  string
+		scope    string
+		expected string
+	}{
+		{"feat(api): add users", "cli", "feat(cli): add users"},
+		{"feat: add users\n\nBody", "api", "feat(api): add users\n\nBody"},
+		{"fix(api)!: drop v1", "", "fix!: drop v1"},
+		{"✨ feat(ui): add toggle", "theme", "✨ feat(theme): add toggle"},
+		{"Update README", "docs", "Update README"},
+		{"[PAY-42] feat: add refunds", "billing", "[PAY-42] feat(billing): add refunds"},
+		{"PAY-42: fix(api): handle timeouts\n\nBody", "", "PAY-42: fix: handle timeouts\n\nBody"},
+		{"#42 ✨ feat(ui): add toggle", "theme", "#42 ✨ feat(theme): add toggle"},
+		{"[PAY-42] Update README", "docs", "[PAY-42] Update README"},
+	}
+
+	for _, tt := range tests {
+		if got := SetScope(tt.message, tt.scope); got != tt.expected {
+			t.Errorf("SetScope(%q, %q) = %q, want %q", tt.message, tt.scope, got, tt.expected)
+		}
+	}
+}
+
+func TestMatchAndCompleteScope(t *testing.T) {
+	known := []string{"api", "cli", "config", "parser"}
+
+	if got := MatchScope("API", known); got != "api" {
+		t.Errorf("MatchScope(API) = %q, want api", got)
+	}
+	if got := MatchScope("configuration", known); got != "config" {
+		t.Errorf("MatchScope(configuration) = %q, want config", got)
+	}
+	if got := MatchScope("db", known); got != "" {
+		t.Errorf("MatchScope(db) = %q, want empty", got)
+	}
+
+	if got, ok := CompleteScope("pa", known); !ok || got != "parser" {
+		t.Errorf("CompleteScope(pa) = %q, %v, want parser, true", got, ok)
+	}
+	if _, ok := CompleteScope("c", known); ok {
+		t.Error("CompleteScope(c) should be ambiguous")
+	}
+}
+
+func TestStrictScopes(t *testing.T) {
+	s := &Style{Scopes: []string{"api", "cli"}, StrictScopes: true}
+
+	if got := s.Apply("feat(handlers/api): add users"); got != "feat(api): add users" {
+		t.Errorf("Apply mapped scope = %q, want feat(api): add users", got)
+	}
+	if got := s.Apply("feat(db): add index"); got != "feat: add index" {
+		t.Errorf("Apply unknown scope = %q, want feat: add index", got)
+	}
+
+	guidelines := strings.Join(s.Guidelines(), "\n")
+	if !strings.Contains(guidelines, "MUST be one of: api, cli") {
+		t.Errorf("Guidelines missing strict scope list: %q", guidelines)
+	}
+}
+
+func TestAliasScope(t *testing.T) {
+	aliases := map[string]string{"internal/analyzer": "analyzer", "DB": "database"}
+	tests := map[string]string{
+		"feat(internal/analyzer): add scorer": "feat(analyzer): add scorer",
+		"✨ fix(db): close connections":        "✨ fix(database): close connections",
+		"fix(api): handle timeouts":           "fix(api): handle timeouts",
+		"[PAY-42] fix(DB): close connections": "[PAY-42] fix(database): close connections",
+		"Update README":                       "Update README",
+	}
+	for subject, want := range tests {
+		if got := AliasScope(subject, aliases); got != want {
+			t.Errorf("AliasScope(%q) = %q, want %q", subject, got, want)
+		}
+	}
+}
@@ -0,0 +1,444 @@
+package style
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minSamples is the number of commit subjects needed before a style is trusted
+const minSamples = 10
+
+// Tenses of the first verb of a commit description
+const (
+	TenseImperative = "imperative" // add, fix
+	TensePast       = "past"       // added, fixed
+	TensePresent    = "present"    // adds, fixes
+)
+
+// Style describes the commit subject conventions of a repository, learned from its log
+type Style struct {
+	Samples         int     // Number of subjects analyzed
+	Conventional    float64 // Share of subjects starting with a conventional type prefix
+	Scoped          float64 // Share of conventional subjects with a (scope)
+	Capitalized     float64 // Share of descriptions starting with an uppercase letter
+	TrailingPeriod  float64 // Share of subjects ending with a period
+	Emoji           float64 // Share of subjects starting with an emoji
+	EmojiShortcodes bool    // Emoji are written as :shortcode: rather than unicode
+	Tense           string  // Dominant tense of the first verb, empty if unknown
+	AverageLength   int     // Average subject length in characters
+
+	Scopes       []string // Known scopes, most used first
+	StrictScopes bool     // Only Scopes may be used; set from config rather than learned
+	Types        []string // Commit types that may be used, every type when empty; set from config
+}
+
+var conventionalRegex = regexp.MustCompile(`^([a-z]+)(\(([^)]*)\))?(!)?: (.*)$`)
+var shortcodeRegex = regexp.MustCompile(`^:[a-z0-9_+-]+:\s*`)
+
+// commitTypes lists the conventional commit types recognized as subject prefixes
+var commitTypes = map[string]bool{
+	"feat": true, "fix": true, "refactor": true, "chore": true, "docs": true, "test": true,
+	"style": true, "perf": true, "ci": true, "build": true, "security": true, "revert": true, "merge": true,
+}
+
+// typeEmoji maps commit types to the gitmoji conventionally used for them
+var typeEmoji = map[string][2]string{
+	"feat":     {"✨", ":sparkles:"},
+	"fix":      {"🐛", ":bug:"},
+	"docs":     {"📝", ":memo:"},
+	"refactor": {"♻️", ":recycle:"},
+	"test":     {"✅", ":white_check_mark:"},
+	"chore":    {"🔧", ":wrench:"},
+	"style":    {"🎨", ":art:"},
+	"perf":     {"⚡️", ":zap:"},
+	"ci":       {"👷", ":construction_worker:"},
+	"build":    {"📦", ":package:"},
+	"security": {"🔒", ":lock:"},
+	"revert":   {"⏪", ":rewind:"},
+	"merge":    {"🔀", ":twisted_rightwards_arrows:"},
+}
+
+// verbs are the base forms of verbs commonly starting a commit description;
+// tense is only detected and converted for these to avoid mangling nouns
+var verbs = []string{
+	"add", "adjust", "allow", "avoid", "build", "bump", "change", "check", "clean",
+	"configure", "convert", "correct", "create", "delete", "disable", "document", "drop",
+	"enable", "enhance", "ensure", "expose", "extract", "fix", "format", "generate",
+	"handle", "ignore", "implement", "improve", "initialize", "integrate", "introduce",
+	"load", "make", "merge", "migrate", "move", "optimize", "parse", "prevent", "reduce",
+	"refactor", "remove", "rename", "reorganize", "replace", "resolve", "restructure",
+	"revert", "rewrite", "run", "set", "simplify", "split", "support", "tweak", "update",
+	"upgrade", "use", "validate", "wrap", "write",
+}
+
+// irregularPast holds past forms that do not follow the regular -ed rules
+var irregularPast = map[string]string{
+	"build": "built", "make": "made", "run": "ran", "set": "set", "split": "split",
+	"write": "wrote", "rewrite": "rewrote", "drop": "dropped", "wrap": "wrapped",
+}
+
+// irregularGerund holds -ing forms that double the final consonant
+var irregularGerund = map[string]string{
+	"run": "running", "set": "setting", "split": "splitting", "drop": "dropping", "wrap": "wrapping",
+}
+
+// Analyze learns the style of a repository from its commit subjects
+func Analyze(subjects []string) *Style {
+	s := &Style{}
+	var conventional, scoped, capitalized, period, emoji, shortcodes, length int
+	tenses := map[string]int{}
+	scopeCounts := map[string]int{}
+
+	for _, subject := range subjects {
+		subject = strings.TrimSpace(subject)
+		if subject == "" || strings.HasPrefix(subject, "Merge ") {
+			continue
+		}
+		s.Samples++
+		length += utf8.RuneCountInString(subject)
+
+		lead, rest := splitEmoji(subject)
+		if lead != "" {
+			emoji++
+			if strings.HasPrefix(lead, ":") {
+				shortcodes++
+			}
+		}
+
+		description := rest
+		if m := conventionalRegex.FindStringSubmatch(rest); m != nil && commitTypes[m[1]] {
+			conventional++
+			if m[2] != "" {
+				scoped++
+				if scope := strings.TrimSpace(m[3]); scope != "" {
+					scopeCounts[scope]++
+				}
+			}
+			description = m[5]
+		}
+
+		if r, _ := utf8.DecodeRuneInString(description); unicode.IsUpper(r) {
+			capitalized++
+		}
+		if strings.HasSuffix(subject, ".") {
+			period++
+		}
+		if tense := verbTense(firstWord(description)); tense != "" {
+			tenses[tense]++
+		}
+	}
+
+	for scope := range scopeCounts {
+		s.Scopes = append(s.Scopes, scope)
+	}
+	sort.Slice(s.Scopes, func(i, j int) bool {
+		if scopeCounts[s.Scopes[i]] != scopeCounts[s.Scopes[j]] {
+			return scopeCounts[s.Scopes[i]] > scopeCounts[s.Scopes[j]]
+		}
+		return s.Scopes[i] < s.Scopes[j]
+	})
+
+	if s.Samples == 0 {
+		return s
+	}
+	total := float64(s.Samples)
+	s.Conventional = float64(conventional) / total
+	if conventional > 0 {
+		s.Scoped = float64(scoped) / float64(conventional)
+	}
+	s.Capitalized = float64(capitalized) / total
+	s.TrailingPeriod = float64(period) / total
+	s.Emoji = float64(emoji) / total
+	s.EmojiShortcodes = shortcodes*2 > emoji
+	s.AverageLength = length / s.Samples
+
+	best := 0
+	for _, tense := range []string{TenseImperative, TensePast, TensePresent} {
+		if tenses[tense] > best {
+			best = tenses[tense]
+			s.Tense = tense
+		}
+	}
+	return s
+}
+
+// Known reports whether enough commits were analyzed to trust the style
+func (s *Style) Known() bool {
+	return s != nil && s.Samples >= minSamples
+}
+
+// Apply rewrites a conventional commit subject to match the repository's style:
+// emoji, scope usage, type prefix, capitalization, tense and trailing period.
+// With StrictScopes, unknown scopes are replaced by a matching known scope or dropped,
+// and types left out of Types are replaced by the nearest allowed type.
+func (s *Style) Apply(subject string) string {
+	if s == nil || subject == "" {
+		return subject
+	}
+	subject = RestrictType(subject, s.Types)
+	if s.StrictScopes && len(s.Scopes) > 0 {
+		if scope := SubjectScope(subject); scope != "" {
+			subject = SetScope(subject, MatchScope(scope, s.Scopes))
+		}
+	}
+	if !s.Known() {
+		return subject
+	}
+
+	commitType, scope, breaking, description := "", "", "", subject
+	if m := conventionalRegex.FindStringSubmatch(subject); m != nil {
+		commitType, scope, breaking, description = m[1], m[3], m[4], m[5]
+	}
+
+	description = s.applyTense(description)
+	description = s.applyCapitalization(description)
+	if s.TrailingPeriod > 0.5 {
+		if !strings.HasSuffix(description, ".") {
+			description += "."
+		}
+	} else {
+		description = strings.TrimSuffix(description, ".")
+	}
+
+	result := description
+	if commitType != "" && s.Conventional >= 0.2 {
+		prefix := commitType
+		if scope != "" && s.Scoped >= 0.1 {
+			prefix += "(" + scope + ")"
+		}
+		result = prefix + breaking + ": " + description
+	}
+
+	if s.Emoji >= 0.5 && commitType != "" {
+		if e, ok := typeEmoji[commitType]; ok {
+			if s.EmojiShortcodes {
+				result = e[1] + " " + result
+			} else {
+				result = e[0] + " " + result
+			}
+		}
+	}
+	return result
+}
+
+// Imperative converts a leading verb in another tense or a gerund to imperative
+// mood, e.g. "added flag" -> "add flag", "updating docs" -> "update docs"
+func Imperative(description string) string {
+	return (&Style{Tense: TenseImperative}).applyTense(description)
+}
+
+// Past converts a leading verb to past tense, e.g. "add flag" -> "added flag"
+func Past(description string) string {
+	return (&Style{Tense: TensePast}).applyTense(description)
+}
+
+// SplitSubject separates the leading emoji and conventional type prefix of a
+// subject from its description
+func SplitSubject(subject string) (string, string) {
+	emoji, rest := splitEmoji(subject)
+	if m := conventionalRegex.FindStringSubmatch(rest); m != nil {
+		return subject[:len(subject)-len(m[5])], m[5]
+	}
+	return emoji, rest
+}
+
+// ParseSubject splits a conventional commit subject into its type, scope, breaking
+// change marker and description. Subjects without a type prefix only have a description.
+func ParseSubject(subject string) (commitType, scope string, breaking bool, description string) {
+	_, rest := splitEmoji(subject)
+	if m := conventionalRegex.FindStringSubmatch(rest); m != nil {
+		return m[1], m[3], m[4] == "!", m[5]
+	}
+	return "", "", false, rest
+}
+
+// Guidelines describes the style as instructions for the LLM prompt. The type
+// prefix and emoji are left to Apply, since model output must stay conventional
+// to pass validation.
+func (s *Style) Guidelines() []string {
+	if s == nil {
+		return nil
+	}
+
+	var lines []string
+	if len(s.Types) > 0 {
+		lines = append(lines, fmt.Sprintf("The type MUST be one of: %s", strings.Join(s.Types, ", ")))
+	}
+	if len(s.Scopes) > 0 {
+		scopes := s.Scopes
+		if len(scopes) > maxPromptScopes {
+			scopes = scopes[:maxPromptScopes]
+		}
+		if s.StrictScopes {
+			lines = append(lines, fmt.Sprintf("The scope MUST be one of: %s (or omit the scope)", strings.Join(scopes, ", ")))
+		} else {
+			lines = append(lines, fmt.Sprintf("Prefer scopes already used in this repository: %s", strings.Join(scopes, ", ")))
+		}
+	}
+	if !s.Known() {
+		return lines
+	}
+
+	if s.Conventional >= 0.2 {
+		if s.Scoped < 0.1 {
+			lines = append(lines, "Use a type prefix without a scope, e.g. \"fix: ...\"")
+		} else if s.Scoped >= 0.5 {
+			lines = append(lines, "Always include a scope, e.g. \"fix(parser): ...\"")
+		}
+	}
+	if s.Capitalized >= 0.5 {
+		lines = append(lines, "Start the description with an uppercase letter")
+	} else {
+		lines = append(lines, "Start the description with a lowercase letter")
+	}
+	switch s.Tense {
+	case TensePast:
+		lines = append(lines, "Use past tense (\"added\", \"fixed\")")
+	case TensePresent:
+		lines = append(lines, "Use third person present tense (\"adds\", \"fixes\")")
+	case TenseImperative:
+		lines = append(lines, "Use imperative mood (\"add\", \"fix\")")
+	}
+	if s.TrailingPeriod > 0.5 {
+		lines = append(lines, "End the subject with a period")
+	}
+	lines = append(lines, fmt.Sprintf("Keep the subject around %d characters", s.AverageLength))
+	return lines
+}
+
+// applyCapitalization changes the case of the first letter of the description
+func (s *Style) applyCapitalization(description string) string {
+	r, size := utf8.DecodeRuneInString(description)
+	if r == utf8.RuneError {
+		return description
+	}
+	if s.Capitalized >= 0.5 {
+		return string(unicode.ToUpper(r)) + description[size:]
+	}
+	// Keep acronyms such as API or CLI intact
+	if next, _ := utf8.DecodeRuneInString(description[size:]); unicode.IsUpper(next) {
+		return description
+	}
+	return string(unicode.ToLower(r)) + description[size:]
+}
+
+// applyTense converts the leading verb of the description to the repository's tense
+func (s *Style) applyTense(description string) string {
+	word := firstWord(description)
+	base := baseVerb(word)
+	if base == "" || s.Tense == "" {
+		return description
+	}
+
+	var converted string
+	switch s.Tense {
+	case TensePast:
+		converted = pastTense(base)
+	case TensePresent:
+		converted = presentTense(base)
+	default:
+		converted = base
+	}
+	if r, _ := utf8.DecodeRuneInString(word); unicode.IsUpper(r) {
+		converted = strings.ToUpper(converted[:1]) + converted[1:]
+	}
+	return converted + description[len(word):]
+}
+
+// verbTense returns the tense of a known verb, or an empty string
+func verbTense(word string) string {
+	lower := strings.ToLower(word)
+	for _, verb := range verbs {
+		switch lower {
+		case verb:
+			return TenseImperative
+		case pastTense(verb):
+			return TensePast
+		case presentTense(verb):
+			return TensePresent
+		}
+	}
+	return ""
+}
+
+// baseVerb returns the base form of a known verb in any tense or as a gerund, or an empty string
+func baseVerb(word string) string {
+	lower := strings.ToLower(word)
+	for _, verb := range verbs {
+		if lower == verb || lower == pastTense(verb) || lower == presentTense(verb) || lower == gerund(verb) {
+			return verb
+		}
+	}
+	return ""
+}
+
+// pastTense returns the simple past of a verb
+func pastTense(verb string) string {
+	if past, ok := irregularPast[verb]; ok {
+		return past
+	}
+	switch {
+	case strings.HasSuffix(verb, "e"):
+		return verb + "d"
+	case strings.HasSuffix(verb, "y") && !strings.ContainsAny(verb[len(verb)-2:len(verb)-1], "aeiou"):
+		return verb[:len(verb)-1] + "ied"
+	default:
+		return verb + "ed"
+	}
+}
+
+// gerund returns the -ing form of a verb
+func gerund(verb string) string {
+	if form, ok := irregularGerund[verb]; ok {
+		return form
+	}
+	if strings.HasSuffix(verb, "e") && !strings.HasSuffix(verb, "ee") {
+		return verb[:len(verb)-1] + "ing"
+	}
+	return verb + "ing"
+}
+
+// presentTense returns the third person singular present of a verb
+func presentTense(verb string) string {
+	switch {
+	case strings.HasSuffix(verb, "s"), strings.HasSuffix(verb, "x"), strings.HasSuffix(verb, "z"),
+		strings.HasSuffix(verb, "ch"), strings.HasSuffix(verb, "sh"):
+		return verb + "es"
+	case strings.HasSuffix(verb, "y") && !strings.ContainsAny(verb[len(verb)-2:len(verb)-1], "aeiou"):
+		return verb[:len(verb)-1] + "ies"
+	default:
+		return verb + "s"
+	}
+}
+
+// firstWord returns the leading run of letters of a description
+func firstWord(description string) string {
+	end := strings.IndexFunc(description, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end < 0 {
+		return description
+	}
+	return description[:end]
+}
+
+// splitEmoji separates a leading emoji or :shortcode: (with trailing space) from a subject
+func splitEmoji(subject string) (string, string) {
+	if m := shortcodeRegex.FindString(subject); m != "" {
+		return m, subject[len(m):]
+	}
+	if r, size := utf8.DecodeRuneInString(subject); isEmoji(r) {
+		rest := strings.TrimLeftFunc(subject[size:], func(r rune) bool {
+			return unicode.IsSpace(r) || r == '\uFE0F'
+		})
+		return subject[:len(subject)-len(rest)], rest
+	}
+	return "", subject
+}
+
+// isEmoji reports whether a rune is in one of the common emoji blocks
+func isEmoji(r rune) bool {
+	return (r >= 0x1F300 && r <= 0x1FAFF) || (r >= 0x2600 && r <= 0x27BF) || (r >= 0x2B00 && r <= 0x2BFF)
+}
@@ -0,0 +1,127 @@
+package style
+
+import "testing"
+
+func repeat(subjects []string, n int) []string {
+	var out []string
+	for i := 0; i < n; i++ {
+		out = append(out, subjects...)
+	}
+	return out
+}
+
+func TestAnalyze(t *testing.T) {
+	s := Analyze(repeat([]string{
+		"✨ feat(api): Added user listing",
+		"🐛 fix: Fixed nil pointer in parser",
+		"Merge branch 'main' into dev",
+	}, 5))
+
+	if s.Samples != 10 {
+		t.Fatalf("Samples = %d, want 10 (merge commits are skipped)", s.Samples)
+	}
+	if s.Conventional != 1 {
+		t.Errorf("Conventional = %v, want 1", s.Conventional)
+	}
+	if s.Scoped != 0.5 {
+		t.Errorf("Scoped = %v, want 0.5", s.Scoped)
+	}
+	if s.Capitalized != 1 {
+		t.Errorf("Capitalized = %v, want 1", s.Capitalized)
+	}
+	if s.Emoji != 1 || s.EmojiShortcodes {
+		t.Errorf("Emoji = %v, EmojiShortcodes = %v, want 1 and false", s.Emoji, s.EmojiShortcodes)
+	}
+	if s.Tense != TensePast {
+		t.Errorf("Tense = %q, want %q", s.Tense, TensePast)
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		subjects []string
+		input    string
+		expected string
+	}{
+		{
+			name:     "unknown style leaves subject untouched",
+			subjects: []string{"Added something"},
+			input:    "feat(api): add user endpoint",
+			expected: "feat(api): add user endpoint",
+		},
+		{
+			name:     "capitalized past tense",
+			subjects: repeat([]string{"feat(cli): Added flag", "fix(db): Fixed query."}, 5),
+			input:    "feat(api): add user endpoint",
+			expected: "feat(api): Added user endpoint",
+		},
+		{
+			name:     "no scopes and shortcode emoji",
+			subjects: repeat([]string{":sparkles: feat: adds flag", ":bug: fix: fixes query"}, 5),
+			input:    "fix(parser): handle empty input",
+			expected: ":bug: fix: handles empty input",
+		},
+		{
+			name:     "plain descriptions without type prefix",
+			subjects: repeat([]string{"Update README", "Remove unused helper"}, 5),
+			input:    "docs(readme): update install steps",
+			expected: "Update install steps",
+		},
+		{
+			name:     "acronyms stay uppercase",
+			subjects: repeat([]string{"feat: add flag", "fix: remove query"}, 5),
+			input:    "feat: API client for users",
+			expected: "feat: API client for users",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Analyze(tt.subjects).Apply(tt.input); got != tt.expected {
+				t.Errorf("Apply(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSplitSubject(t *testing.T) {
+	tests := map[string][2]string{
+		"feat(api): add endpoint":  {"feat(api): ", "add endpoint"},
+		"✨ fix!: Handle nil input": {"✨ fix!: ", "Handle nil input"},
+		"Update README":            {"", "Update README"},
+	}
+	for subject, want := range tests {
+		prefix, description := SplitSubject(subject)
+		if prefix != want[0] || description != want[1] {
+			t.Errorf("SplitSubject(%q) = %q, %q, want %q, %q", subject, prefix, description, want[0], want[1])
+		}
+	}
+}
+
+func TestParseSubject(t *testing.T) {
+	commitType, scope, breaking, description := ParseSubject("✨ feat(api)!: drop v1 routes")
+	if commitType != "feat" || scope != "api" || !breaking || description != "drop v1 routes" {
+		t.Errorf("ParseSubject() = %q, %q, %v, %q", commitType, scope, breaking, description)
+	}
+	commitType, scope, breaking, description = ParseSubject("Update README")
+	if commitType != "" || scope != "" || breaking || description != "Update README" {
+		t.Errorf("ParseSubject() = %q, %q, %v, %q", commitType, scope, breaking, description)
+	}
+}
+
+func TestImperative(t *testing.T) {
+	tests := map[string]string{
+		"added user endpoint": "add user endpoint",
+		"Fixes nil pointer":   "Fix nil pointer",
+		"updating docs":       "update docs",
+		"running migrations":  "run migrations",
+		"wrote tests":         "write tests",
+		"config loader":       "config loader",
+	}
+	for input, want := range tests {
+		if got := Imperative(input); got != want {
+			t.Errorf("Imperative(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package style
+
+import (
+	"slices"
+	"strings"
+)
+
+// nearestTypes lists for each commit type the types closest to it, closest
+// first, used when a repository does not allow the type itself
+var nearestTypes = map[string][]string{
+	"feat":     {"chore"},
+	"fix":      {"chore"},
+	"refactor": {"chore", "fix"},
+	"perf":     {"refactor", "fix", "chore"},
+	"style":    {"refactor", "chore"},
+	"test":     {"chore"},
+	"docs":     {"chore"},
+	"ci":       {"build", "chore"},
+	"build":    {"chore", "ci"},
+	"security": {"fix", "chore"},
+	"revert":   {"fix", "chore"},
+	"merge":    {"chore"},
+	"chore":    {"build", "refactor", "fix"},
+}
+
+// NearestType returns the allowed commit type closest to a type: the type
+// itself when allowed or when every type is, then the first allowed of its
+// nearest types, and the first allowed type otherwise
+func NearestType(commitType string, allowed []string) string {
+	if len(allowed) == 0 || slices.Contains(allowed, commitType) {
+		return commitType
+	}
+	for _, nearest := range nearestTypes[commitType] {
+		if slices.Contains(allowed, nearest) {
+			return nearest
+		}
+	}
+	return allowed[0]
+}
+
+// SetType replaces the type of a conventional commit message, keeping its
+// emoji, scope and breaking change marker. Messages without a type prefix are
+// returned unchanged.
+func SetType(message, commitType string) string {
+	subject, rest, hasRest := strings.Cut(message, "\n")
+	emoji, subject := splitEmoji(subject)
+	m := conventionalRegex.FindStringSubmatch(subject)
+	if m == nil {
+		return message
+	}
+
+	subject = emoji + commitType + m[2] + m[4] + ": " + m[5]
+	if hasRest {
+		return subject + "\n" + rest
+	}
+	return subject
+}
+
+// RestrictType replaces a commit type the allowed types leave out by the
+// nearest allowed one. Every type is allowed when the list is empty.
+func RestrictType(message string, allowed []string) string {
+	commitType, _, _, _ := ParseSubject(Subject(message))
+	if commitType == "" {
+		return message
+	}
+	if nearest := NearestType(commitType, allowed); nearest != commitType {
+		return SetType(message, nearest)
+	}
+	return message
+}
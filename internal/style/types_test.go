@@ -0,0 +1,61 @@
+package style
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNearestType(t *testing.T) {
+	allowed := []string{"feat", "fix", "chore", "docs"}
+	tests := []struct {
+		commitType string
+		expected   string
+	}{
+		{"feat", "feat"},
+		{"perf", "fix"},
+		{"style", "chore"},
+		{"refactor", "chore"},
+		{"ci", "chore"},
+		{"security", "fix"},
+		{"wip", "feat"},
+	}
+
+	for _, tt := range tests {
+		if got := NearestType(tt.commitType, allowed); got != tt.expected {
+			t.Errorf("NearestType(%q) = %q, want %q", tt.commitType, got, tt.expected)
+		}
+	}
+	if got := NearestType("perf", nil); got != "perf" {
+		t.Errorf("NearestType(perf) without allowed types = %q, want perf", got)
+	}
+}
+
+func TestRestrictType(t *testing.T) {
+	allowed := []string{"feat", "fix", "chore", "docs"}
+	tests := []struct {
+		message  string
+		expected string
+	}{
+		{"perf(cache): speed up lookups", "fix(cache): speed up lookups"},
+		{"style!: reformat config\n\nBody", "chore!: reformat config\n\nBody"},
+		{"⚡️ perf: inline hot path", "⚡️ fix: inline hot path"},
+		{"feat(api): add users", "feat(api): add users"},
+		{"Update README", "Update README"},
+	}
+
+	for _, tt := range tests {
+		if got := RestrictType(tt.message, allowed); got != tt.expected {
+			t.Errorf("RestrictType(%q) = %q, want %q", tt.message, got, tt.expected)
+		}
+	}
+}
+
+func TestAllowedTypes(t *testing.T) {
+	s := &Style{Types: []string{"feat", "fix", "chore"}}
+	if got := s.Apply("style(ui): fix whitespace in app.go"); got != "chore(ui): fix whitespace in app.go" {
+		t.Errorf("Apply() = %q, want the nearest allowed type", got)
+	}
+	if lines := s.Guidelines(); len(lines) == 0 || !strings.Contains(lines[0], "feat, fix, chore") {
+		t.Errorf("Guidelines() = %v, want the allowed types", lines)
+	}
+}
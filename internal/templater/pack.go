@@ -0,0 +1,282 @@
+package templater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
+)
+
+// packManifestFile records installed packs inside the user template directory
+const packManifestFile = "packs.json"
+
+// packFile is the template file a git repository must contain at its root
+const packFile = "templates.json"
+
+// maxPackSize limits how much is read when downloading a pack
+const maxPackSize = 5 << 20
+
+// githubShorthand matches org/repo, optionally pinned as org/repo@ref
+var githubShorthand = regexp.MustCompile(`^([\w.-]+/[\w.-]+?)(?:@(.+))?$`)
+
+// packName matches names that are safe to use as a file name
+var packName = regexp.MustCompile(`^[\w.-]+$`)
+
+// Pack describes an installed template pack
+type Pack struct {
+	Name        string    `json:"-"`
+	Source      string    `json:"source"`            // URL, git remote or org/repo as given to install
+	Version     string    `json:"version,omitempty"` // Pinned tag, branch or commit; empty follows the default branch
+	Commit      string    `json:"commit,omitempty"`  // Commit the pack was installed from, for git sources
+	File        string    `json:"file"`              // Template file name in the user template directory
+	InstalledAt time.Time `json:"installedAt"`
+}
+
+// InstallPack downloads a template pack, validates it and writes it to the user
+// template directory as <name>.json, where it can be selected with --template-file
+// or templateFile. Sources are a URL to a JSON file, a git remote, a local git
+// repository or a GitHub org/repo; git sources must contain templates.json at
+// their root. The version pins a tag, branch or commit of a git source; org/repo@ref
+// is shorthand for it. An empty name is derived from the source.
+func InstallPack(ctx context.Context, source, name, version string) (*Pack, error) {
+	if m := githubShorthand.FindStringSubmatch(source); m != nil && m[2] != "" && !isURL(source) {
+		if version != "" && version != m[2] {
+			return nil, fmt.Errorf("conflicting versions %q and %q for %s", m[2], version, m[1])
+		}
+		source, version = m[1], m[2]
+	}
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		// Local repositories are recorded by absolute path so updates work from anywhere
+		if abs, err := filepath.Abs(source); err == nil {
+			source = abs
+		}
+	}
+	if name == "" {
+		name = strings.TrimSuffix(strings.TrimSuffix(path.Base(strings.TrimRight(source, "/")), ".git"), ".json")
+	}
+	if !packName.MatchString(name) {
+		return nil, fmt.Errorf("invalid pack name %q: use letters, digits, '.', '-' or '_'", name)
+	}
+
+	pack := &Pack{Name: name, Source: source, Version: version, File: name + ".json"}
+	if err := pack.checkFile(); err != nil {
+		return nil, err
+	}
+	if err := pack.fetch(ctx); err != nil {
+		return nil, err
+	}
+	return pack, nil
+}
+
+// UpdatePacks re-fetches installed packs at their pinned versions, or the latest
+// version when unpinned. With no names, every installed pack is updated.
+func UpdatePacks(ctx context.Context, names []string) ([]*Pack, error) {
+	packs, err := LoadPacks()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		for name := range packs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	var updated []*Pack
+	for _, name := range names {
+		pack, ok := packs[name]
+		if !ok {
+			return updated, fmt.Errorf("template pack %q is not installed", name)
+		}
+		if err := pack.fetch(ctx); err != nil {
+			return updated, err
+		}
+		updated = append(updated, pack)
+	}
+	return updated, nil
+}
+
+// LoadPacks returns the installed template packs by name
+func LoadPacks() (map[string]*Pack, error) {
+	dir, err := UserTemplateDir()
+	if err != nil {
+		return nil, err
+	}
+
+	packs := make(map[string]*Pack)
+	data, err := os.ReadFile(filepath.Join(dir, packManifestFile))
+	if os.IsNotExist(err) {
+		return packs, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading template packs: %w", err)
+	}
+	if err := json.Unmarshal(data, &packs); err != nil {
+		return nil, fmt.Errorf("error parsing template packs: %w", err)
+	}
+	for name, pack := range packs {
+		pack.Name = name
+	}
+	return packs, nil
+}
+
+// fetch downloads the pack, validates it and registers it in the user template directory
+func (p *Pack) fetch(ctx context.Context) error {
+	var data []byte
+	var err error
+	if isURL(p.Source) && strings.HasSuffix(p.Source, ".json") {
+		if p.Version != "" {
+			return fmt.Errorf("version pinning needs a git source; %s is a plain file", p.Source)
+		}
+		data, err = download(ctx, p.Source)
+	} else {
+		data, p.Commit, err = fetchGit(ctx, gitRemote(p.Source), p.Version)
+	}
+	if err != nil {
+		return err
+	}
+
+	var templates Templates
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return fmt.Errorf("error parsing template pack %s: %w", p.Name, err)
+	}
+	if err := templates.Validate(); err != nil {
+		return fmt.Errorf("invalid template pack %s: %w", p.Name, err)
+	}
+
+	dir, err := UserTemplateDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creating template directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, p.File), data, 0644); err != nil {
+		return fmt.Errorf("error writing template pack: %w", err)
+	}
+
+	p.InstalledAt = time.Now().UTC()
+	packs, err := LoadPacks()
+	if err != nil {
+		return err
+	}
+	packs[p.Name] = p
+	manifest, err := json.MarshalIndent(packs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding template packs: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, packManifestFile), manifest, 0644); err != nil {
+		return fmt.Errorf("error writing template packs: %w", err)
+	}
+	return nil
+}
+
+// checkFile refuses to overwrite a template file that was not installed as a pack
+func (p *Pack) checkFile() error {
+	if p.File == packManifestFile {
+		return fmt.Errorf("invalid pack name %q: reserved", p.Name)
+	}
+	packs, err := LoadPacks()
+	if err != nil {
+		return err
+	}
+	if _, ok := packs[p.Name]; ok {
+		return nil
+	}
+	dir, err := UserTemplateDir()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(dir, p.File)); err == nil {
+		return fmt.Errorf("%s already exists in %s and was not installed as a pack; choose another name", p.File, dir)
+	}
+	return nil
+}
+
+// isURL reports whether a source is a URL rather than a path or org/repo
+func isURL(source string) bool {
+	return strings.Contains(source, "://") || strings.HasPrefix(source, "git@")
+}
+
+// gitRemote expands org/repo to its GitHub URL; other sources are used as given
+func gitRemote(source string) string {
+	if isURL(source) {
+		return source
+	}
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		return source
+	}
+	if githubShorthand.MatchString(source) {
+		return "https://github.com/" + strings.TrimSuffix(source, ".git") + ".git"
+	}
+	return source
+}
+
+// download fetches a template file over HTTP
+func download(ctx context.Context, url string) ([]byte, error) {
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading template pack: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading template pack: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error downloading template pack: %s returned status code %d", url, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPackSize))
+	if err != nil {
+		return nil, fmt.Errorf("error downloading template pack: %w", err)
+	}
+	return data, nil
+}
+
+// fetchGit reads templates.json from a git remote at a ref (default branch when
+// empty) and returns it with the resolved commit
+func fetchGit(ctx context.Context, remote, ref string) ([]byte, string, error) {
+	dir, err := os.MkdirTemp("", "gitmit-pack-")
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if ref == "" {
+		ref = "HEAD"
+	}
+	steps := [][]string{
+		{"init", "--quiet"},
+		{"fetch", "--quiet", "--depth", "1", remote, ref},
+	}
+	for _, args := range steps {
+		if _, err := gitcmd.Output(ctx, dir, args...); err != nil {
+			return nil, "", fmt.Errorf("error fetching %s at %s: %w", remote, ref, err)
+		}
+	}
+
+	commit, err := gitcmd.Output(ctx, dir, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return nil, "", fmt.Errorf("error resolving %s at %s: %w", remote, ref, err)
+	}
+	data, err := gitcmd.Output(ctx, dir, "show", "FETCH_HEAD:"+packFile)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s at %s has no %s at its root", remote, ref, packFile)
+	}
+	return []byte(data), strings.TrimSpace(commit), nil
+}
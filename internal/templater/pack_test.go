@@ -0,0 +1,120 @@
+package templater
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePackRepo commits a valid templates.json whose A/_default template is subject
+func writePackRepo(t *testing.T, dir, subject string) {
+	t.Helper()
+	templates := Templates{}
+	for _, action := range requiredActions {
+		templates[action] = map[string][]string{"_default": {"chore({topic}): update {item}"}}
+	}
+	templates["A"]["_default"] = []string{subject}
+	data, err := json.Marshal(templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "templates.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, dir, "add", "templates.json")
+	git(t, dir, "commit", "--quiet", "-m", subject)
+}
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func installedSubject(t *testing.T, name string) string {
+	t.Helper()
+	dir, err := UserTemplateDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var templates Templates
+	if err := json.Unmarshal(data, &templates); err != nil {
+		t.Fatal(err)
+	}
+	return templates["A"]["_default"][0]
+}
+
+func TestInstallAndUpdatePack(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	repo := filepath.Join(t.TempDir(), "team-pack")
+	if err := os.Mkdir(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	git(t, repo, "init", "--quiet")
+	writePackRepo(t, repo, "feat: add {item} v1")
+	git(t, repo, "tag", "v1")
+
+	if _, err := InstallPack(context.Background(), repo, "", ""); err != nil {
+		t.Fatalf("InstallPack(context.Background()) = %v", err)
+	}
+	if _, err := InstallPack(context.Background(), repo, "pinned", "v1"); err != nil {
+		t.Fatalf("InstallPack(context.Background(), v1) = %v", err)
+	}
+
+	writePackRepo(t, repo, "feat: add {item} v2")
+	if _, err := UpdatePacks(context.Background(), nil); err != nil {
+		t.Fatalf("UpdatePacks(context.Background()) = %v", err)
+	}
+	if got := installedSubject(t, "team-pack"); got != "feat: add {item} v2" {
+		t.Errorf("unpinned pack = %q, want the latest version", got)
+	}
+	if got := installedSubject(t, "pinned"); got != "feat: add {item} v1" {
+		t.Errorf("pinned pack = %q, want v1", got)
+	}
+
+	packs, err := LoadPacks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if packs["pinned"].Version != "v1" || packs["pinned"].Commit == "" {
+		t.Errorf("pinned pack = %+v, want version v1 and a commit", packs["pinned"])
+	}
+	if _, _, err := ReadTemplateFile("pinned.json"); err != nil {
+		t.Errorf("installed pack is not found by name: %v", err)
+	}
+}
+
+func TestInstallPackRejectsInvalidPack(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	repo := t.TempDir()
+	git(t, repo, "init", "--quiet")
+	if err := os.WriteFile(filepath.Join(repo, "templates.json"), []byte(`{"A": {}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, repo, "add", "templates.json")
+	git(t, repo, "commit", "--quiet", "-m", "broken")
+
+	_, err := InstallPack(context.Background(), repo, "broken", "")
+	if err == nil || !strings.Contains(err.Error(), "invalid template pack") {
+		t.Fatalf("InstallPack(context.Background()) = %v, want validation error", err)
+	}
+}
@@ -0,0 +1,41 @@
+package templater
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Templates are scored with a little randomness, so the same changes get some
+// variety in wording. A seed makes the randomness repeat from run to run;
+// deterministic mode leaves it out and breaks ties between templates lexically.
+var (
+	randomMu      sync.Mutex
+	random        = rand.New(rand.NewSource(time.Now().UnixNano()))
+	deterministic bool
+)
+
+// SetSeed seeds the randomness of template choices, so the same changes and
+// history get the same suggestions
+func SetSeed(seed int64) {
+	randomMu.Lock()
+	defer randomMu.Unlock()
+	random = rand.New(rand.NewSource(seed))
+}
+
+// SetDeterministic turns the randomness of template choices off or on
+func SetDeterministic(on bool) {
+	randomMu.Lock()
+	defer randomMu.Unlock()
+	deterministic = on
+}
+
+// jitter returns a random score bonus below max, or 0 in deterministic mode
+func jitter(max float64) float64 {
+	randomMu.Lock()
+	defer randomMu.Unlock()
+	if deterministic {
+		return 0
+	}
+	return random.Float64() * max
+}
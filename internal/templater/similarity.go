@@ -0,0 +1,103 @@
+package templater
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/andev0x/gitmit/internal/style"
+)
+
+const (
+	// duplicateOverlap is the share of words two descriptions have in common
+	// from which they read as the same message
+	duplicateOverlap = 0.7
+
+	// duplicateDistance is the edit distance, relative to the longer
+	// description, up to which two descriptions read as the same message
+	duplicateDistance = 0.2
+)
+
+// fillerWords add nothing to what a description says, as in "add the new client"
+var fillerWords = map[string]bool{"a": true, "an": true, "the": true, "new": true, "some": true, "of": true, "to": true, "in": true, "on": true}
+
+// nearDuplicate reports whether two messages are phrasings of the same
+// message: of the same type, with descriptions sharing most of their words or
+// a few characters apart, such as "add login page" and "adds login pages".
+// Scopes are left out, since they do not change what a message says.
+func nearDuplicate(a, b string) bool {
+	typeA, _, _, descA := style.ParseSubject(style.Subject(a))
+	typeB, _, _, descB := style.ParseSubject(style.Subject(b))
+	if !strings.EqualFold(typeA, typeB) {
+		return false
+	}
+	wordsA, wordsB := descriptionWords(descA), descriptionWords(descB)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return len(wordsA) == len(wordsB)
+	}
+	if wordOverlap(wordsA, wordsB) >= duplicateOverlap {
+		return true
+	}
+
+	textA, textB := []rune(strings.Join(wordsA, " ")), []rune(strings.Join(wordsB, " "))
+	return float64(editDistance(textA, textB)) <= duplicateDistance*float64(max(len(textA), len(textB)))
+}
+
+// descriptionWords returns the lowercase words of a description, without
+// punctuation and filler words
+func descriptionWords(description string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !fillerWords[word] {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// wordOverlap returns the Jaccard index of two sets of words: the words they share
+// over all their words
+func wordOverlap(a, b []string) float64 {
+	set := make(map[string]bool, len(a))
+	for _, w := range a {
+		set[w] = true
+	}
+	union := len(set)
+	shared := 0
+	seen := make(map[string]bool, len(b))
+	for _, w := range b {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		if set[w] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	return float64(shared) / float64(union)
+}
+
+// editDistance returns the Levenshtein distance between two strings: the
+// fewest insertions, deletions and substitutions turning one into the other
+func editDistance(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
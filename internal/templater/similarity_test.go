@@ -0,0 +1,50 @@
+package templater
+
+import (
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/history"
+)
+
+func TestNearDuplicate(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"feat(auth): add login page", "feat(auth): adds login pages", true},
+		{"feat(auth): add login page", "feat(api): add login page", true},
+		{"feat(auth): add the login page", "feat(auth): add login page", true},
+		{"feat(auth): add login page", "feat(auth): introduce login page", false},
+		{"feat(auth): add login page", "fix(auth): add login page", false},
+		{"feat(auth): add login page", "feat(auth): add session management logic", false},
+		{"update docs", "update the docs", true},
+	}
+	for _, tt := range tests {
+		if got := nearDuplicate(tt.a, tt.b); got != tt.want {
+			t.Errorf("nearDuplicate(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestGetSuggestionsSkipsNearDuplicates(t *testing.T) {
+	tp := &Templater{
+		templates: Templates{
+			"A": {"_default": {"feat({topic}): add {item}", "feat({topic}): add new {item}", "feat({topic}): introduce {item}"}},
+		},
+		history:   &history.CommitHistory{},
+		generated: make(map[string]string),
+	}
+	msg := &analyzer.CommitMessage{Action: "feat", Topic: "api", Item: "client"}
+
+	suggestions, err := tp.GetSuggestions(msg, 3)
+	if err != nil {
+		t.Fatalf("GetSuggestions() = %v", err)
+	}
+	if len(suggestions) != 2 {
+		t.Fatalf("GetSuggestions() = %+v, want 2 suggestions without the near duplicate", suggestions)
+	}
+	if nearDuplicate(suggestions[0].Message, suggestions[1].Message) {
+		t.Errorf("suggestions %q and %q are near duplicates", suggestions[0].Message, suggestions[1].Message)
+	}
+}
@@ -3,77 +3,129 @@ package templater
 import (
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math/rand"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/config"
 	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/style"
+	"github.com/andev0x/gitmit/internal/xdg"
 )
 
 //go:embed templates.json
 var embeddedTemplates embed.FS
 
+// ErrTemplateInvalid is the error of templates that cannot be used, such as a
+// template file that is not valid JSON or lacks a required action
+var ErrTemplateInvalid = errors.New("invalid templates")
+
+// ErrNoFreshMessage is the error of changes whose every message repeats a recent
+// commit, even with a detail of the changes added
+var ErrNoFreshMessage = errors.New("every suggestion repeats a recent commit")
+
 // Templates holds the loaded commit message templates
 type Templates map[string]map[string][]string
 
+// preferenceWeight scales the learned template preference (-1..1) into a score bonus
+const preferenceWeight = 2.0
+
 // Templater is responsible for selecting and formatting commit messages
 type Templater struct {
 	templates Templates
 	history   *history.CommitHistory
+	generated map[string]string   // message -> template that produced it
+	types     []string            // Commit types messages may use, every type when empty
+	format    func(string) string // Formats messages as they are committed, nil to leave them as they are
 }
 
-// NewTemplater creates a new Templater
-func NewTemplater(templateFile string, hist *history.CommitHistory) (*Templater, error) {
-	var data []byte
-	var err error
+// UserTemplateDir returns the directory for user template packs: $XDG_CONFIG_HOME/gitmit/templates
+func UserTemplateDir() (string, error) {
+	configDir, err := xdg.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "templates"), nil
+}
 
-	// For offline use, try loading from multiple locations in order:
-	// 1. Current working directory
-	// 2. Executable's directory
-	// 3. Embedded templates
+// ReadTemplateFile reads a template file and returns its content and where it was
+// found. Absolute paths are read directly; other names are looked up, for offline
+// use, in order:
+// 1. Current working directory
+// 2. User template directory ($XDG_CONFIG_HOME/gitmit/templates)
+// 3. Executable's directory
+// 4. Embedded templates
+func ReadTemplateFile(templateFile string) ([]byte, string, error) {
+	if filepath.IsAbs(templateFile) {
+		data, err := os.ReadFile(templateFile)
+		if err != nil {
+			return nil, "", fmt.Errorf("error reading templates: %w", err)
+		}
+		return data, templateFile, nil
+	}
 
-	// Try current working directory first
 	pwd, _ := os.Getwd()
 	localPath := filepath.Join(pwd, templateFile)
-	data, err = os.ReadFile(localPath)
-
-	// If not found in current directory, try executable's directory
-	if err != nil || len(data) == 0 {
-		execPath, execErr := os.Executable()
-		if execErr == nil {
-			execDir := filepath.Dir(execPath)
-			execLocalPath := filepath.Join(execDir, templateFile)
-			data, err = os.ReadFile(execLocalPath)
+	candidates := []string{localPath}
+	if userDir, err := UserTemplateDir(); err == nil {
+		candidates = append(candidates, filepath.Join(userDir, templateFile))
+	}
+	if execPath, err := os.Executable(); err == nil {
+		candidates = append(candidates, filepath.Join(filepath.Dir(execPath), templateFile))
+	}
+	for _, candidate := range candidates {
+		if data, err := os.ReadFile(candidate); err == nil && len(data) > 0 {
+			return data, candidate, nil
 		}
 	}
 
-	// Finally, try embedded templates
-	if err != nil || len(data) == 0 {
-		data, err = embeddedTemplates.ReadFile(templateFile)
-		if err != nil {
-			return nil, fmt.Errorf("error reading templates: tried current directory (%s), executable directory, and embedded templates", localPath)
-		}
-		if len(data) == 0 {
-			return nil, fmt.Errorf("no valid templates found in any location")
-		}
+	// Embedded files are named with forward slashes on every platform
+	data, err := embeddedTemplates.ReadFile(filepath.ToSlash(templateFile))
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading templates: %s not found in current directory (%s), user template directory, executable directory, or embedded templates", templateFile, localPath)
+	}
+	if len(data) == 0 {
+		return nil, "", fmt.Errorf("no valid templates found in any location")
+	}
+	return data, "embedded:" + templateFile, nil
+}
+
+// requiredActions are the actions every template file must define
+var requiredActions = []string{"A", "M", "D", "R", "MISC"}
+
+// LoadTemplates reads, parses and validates a template file, returning the
+// templates and where they were found
+func LoadTemplates(templateFile string) (Templates, string, error) {
+	data, source, err := ReadTemplateFile(templateFile)
+	if err != nil {
+		return nil, "", err
 	}
 
 	var templates Templates
 	err = json.Unmarshal(data, &templates)
 	if err != nil {
-		return nil, fmt.Errorf("error unmarshaling template file: %w", err)
+		return nil, source, fmt.Errorf("%w: error unmarshaling template file: %w", ErrTemplateInvalid, err)
 	}
 
-	// Comprehensive template validation for offline use
-	requiredActions := []string{"A", "M", "D", "R", "MISC"}
+	if err := templates.Validate(); err != nil {
+		return nil, source, err
+	}
+	return templates, source, nil
+}
+
+// Validate checks that the templates can be used offline: every required action
+// is present with _default templates, no topic is empty and placeholders are balanced
+func (t Templates) Validate() error {
 	missingActions := []string{}
 
 	for _, action := range requiredActions {
-		actionTemplates, ok := templates[action]
+		actionTemplates, ok := t[action]
 		if !ok {
 			missingActions = append(missingActions, action)
 			continue
@@ -81,35 +133,148 @@ func NewTemplater(templateFile string, hist *history.CommitHistory) (*Templater,
 
 		// Validate that each action has _default templates
 		if defaultTemplates, ok := actionTemplates["_default"]; !ok || len(defaultTemplates) == 0 {
-			return nil, fmt.Errorf("template validation failed: action '%s' missing required '_default' templates", action)
+			return fmt.Errorf("%w: action '%s' missing required '_default' templates", ErrTemplateInvalid, action)
 		}
 
 		// Validate that templates are properly formatted
 		for topic, messages := range actionTemplates {
 			if len(messages) == 0 {
-				return nil, fmt.Errorf("template validation failed: action '%s', topic '%s' has no templates", action, topic)
+				return fmt.Errorf("%w: action '%s', topic '%s' has no templates", ErrTemplateInvalid, action, topic)
 			}
 
 			// Check for valid placeholder format in each template
 			for _, tmpl := range messages {
 				if strings.Count(tmpl, "{") != strings.Count(tmpl, "}") {
-					return nil, fmt.Errorf("template validation failed: mismatched placeholder braces in template: %s", tmpl)
+					return fmt.Errorf("%w: mismatched placeholder braces in template: %s", ErrTemplateInvalid, tmpl)
 				}
 			}
 		}
 	}
 
 	if len(missingActions) > 0 {
-		return nil, fmt.Errorf("template validation failed: missing required actions: %v", missingActions)
+		return fmt.Errorf("%w: missing required actions: %v", ErrTemplateInvalid, missingActions)
+	}
+	return nil
+}
+
+// NewTemplater creates a new Templater
+func NewTemplater(templateFile string, hist *history.CommitHistory) (*Templater, error) {
+	templates, _, err := LoadTemplates(templateFile)
+	if err != nil {
+		return nil, err
 	}
 
 	// No need to seed in Go 1.20+ as it's automatically handled
 
-	return &Templater{templates: templates, history: hist}, nil
+	return &Templater{templates: templates, history: hist, generated: make(map[string]string)}, nil
+}
+
+// Merge returns a copy of the templates with custom templates from the config
+// layered on top. A custom list replaces the topic's templates unless it contains
+// "...", which stands for the templates it extends.
+func (t Templates) Merge(custom map[string]map[string]config.TemplateList) Templates {
+	merged := make(Templates, len(t))
+	for action, topics := range t {
+		merged[action] = make(map[string][]string, len(topics))
+		for topic, templates := range topics {
+			merged[action][topic] = templates
+		}
+	}
+
+	for action, topics := range custom {
+		if merged[action] == nil {
+			merged[action] = make(map[string][]string)
+		}
+		for topic, list := range topics {
+			var templates []string
+			for _, tmpl := range list.Extend(merged[action][topic]) {
+				if tmpl != config.InheritTemplates {
+					templates = append(templates, tmpl)
+				}
+			}
+			merged[action][topic] = templates
+		}
+	}
+	return merged
+}
+
+// AddTemplates layers custom templates from the config over the loaded ones
+func (t *Templater) AddTemplates(custom map[string]map[string]config.TemplateList) error {
+	if len(custom) == 0 {
+		return nil
+	}
+	merged := t.templates.Merge(custom)
+	if err := merged.Validate(); err != nil {
+		return fmt.Errorf("error applying custom templates from config: %w", err)
+	}
+	t.templates = merged
+	return nil
+}
+
+// RestrictTypes limits the commit types of the messages to the allowed ones:
+// templates of other types are left out while the topic has templates of an
+// allowed type, and otherwise get the nearest allowed type
+func (t *Templater) RestrictTypes(types []string) {
+	t.types = types
+}
+
+// FormatWith sets how messages are formatted when committed, so messages are
+// compared with recent commits as they would be committed
+func (t *Templater) FormatWith(format func(string) string) {
+	t.format = format
+}
+
+// repeats reports whether a message, as it is or formatted as it would be
+// committed, is in gitmit's history or has the subject of a recent commit
+func (t *Templater) repeats(message string) bool {
+	if t.history.Contains(message) {
+		return true
+	}
+	return t.format != nil && t.history.Contains(t.format(message))
+}
+
+// recentCommit reports whether a message, formatted as it would be committed,
+// repeats the subject of a recent commit
+func (t *Templater) recentCommit(message string) bool {
+	if t.format != nil {
+		message = t.format(message)
+	}
+	return t.history.IsRecentCommit(message)
+}
+
+// withDetail tells a message apart from recent commits with the first detail of
+// the changes its subject lacks: the changed item, the target of a move or the purpose
+func (t *Templater) withDetail(message, item, target, purpose string) (string, bool) {
+	for _, detail := range []string{"in " + item, "to " + target, "for " + purpose} {
+		word, value, _ := strings.Cut(detail, " ")
+		if value == "" || strings.Contains(strings.ToLower(message), strings.ToLower(value)) {
+			continue
+		}
+		if detailed := message + " " + word + " " + value; !t.repeats(detailed) {
+			return detailed, true
+		}
+	}
+	return "", false
 }
 
 // GetMessage selects and formats a commit message
 func (t *Templater) GetMessage(msg *analyzer.CommitMessage) (string, error) {
+	if msg.Version != "" {
+		return t.releaseMessage(msg), nil
+	}
+	if msg.Move != nil {
+		return t.moveMessage(msg), nil
+	}
+	if msg.Merge != nil {
+		return t.mergeMessage(msg), nil
+	}
+	if msg.Revert != nil {
+		return t.revertMessage(msg), nil
+	}
+	if msg.Pick != nil {
+		return t.pickMessage(msg), nil
+	}
+
 	// Check if this is a special file that needs dedicated handling
 	specialGroup := resolveSpecialFile(msg)
 	var actionKey string
@@ -119,7 +284,7 @@ func (t *Templater) GetMessage(msg *analyzer.CommitMessage) (string, error) {
 		actionKey = specialGroup
 	} else {
 		// Map analyzer action names (feat, fix, refactor, chore, docs, test, etc.)
-		// to the template groups used in templates.json (A, M, D, R, DOC, MISC)
+		// to the template groups used in templates.json (A, M, D, R, DOC, CI, BUILD, STYLE, MISC)
 		actionMap := map[string]string{
 			"feat":     "A",
 			"add":      "A",
@@ -127,12 +292,12 @@ func (t *Templater) GetMessage(msg *analyzer.CommitMessage) (string, error) {
 			"bugfix":   "M",
 			"refactor": "R",
 			"chore":    "D",
-			"test":     "M",
+			"test":     "TEST",
 			"docs":     "DOC",
-			"ci":       "M",
+			"ci":       "CI",
 			"perf":     "M",
-			"style":    "MISC",
-			"build":    "MISC",
+			"style":    "STYLE",
+			"build":    "BUILD",
 			"security": "SECURITY",
 		}
 
@@ -153,6 +318,9 @@ func (t *Templater) GetMessage(msg *analyzer.CommitMessage) (string, error) {
 	if !ok {
 		// Try fallbacks: specific order prefers DOC then A then M then MISC
 		fallbackActions := []string{"DOC", "A", "M", "R", "D", "MISC"}
+		if group, exists := groupFallbacks[actionKey]; exists {
+			fallbackActions = append([]string{group}, fallbackActions...)
+		}
 		for _, fb := range fallbackActions {
 			if templates, exists := t.templates[fb]; exists {
 				actionTemplates = templates
@@ -165,30 +333,8 @@ func (t *Templater) GetMessage(msg *analyzer.CommitMessage) (string, error) {
 		}
 	}
 
-	// Topic selection with improved matching and weighting
-	normalizedTopic := strings.ToLower(strings.TrimSpace(msg.Topic))
-	var topicTemplates []string
-
-	// exact match
-	if normalizedTopic != "" {
-		if templates, exists := actionTemplates[normalizedTopic]; exists && len(templates) > 0 {
-			topicTemplates = templates
-		}
-	}
-
-	// fuzzy match if exact not found
-	if len(topicTemplates) == 0 {
-		for topic, templates := range actionTemplates {
-			if topic == "_default" {
-				continue
-			}
-			tname := strings.ToLower(topic)
-			if normalizedTopic != "" && (strings.Contains(tname, normalizedTopic) || strings.Contains(normalizedTopic, tname)) {
-				topicTemplates = templates
-				break
-			}
-		}
-	}
+	// Topic selection: the group of the topic, or the most similar one
+	topicTemplates := actionTemplates[matchTopic(msg, actionTemplates)]
 
 	// fall back to _default
 	if len(topicTemplates) == 0 {
@@ -198,24 +344,10 @@ func (t *Templater) GetMessage(msg *analyzer.CommitMessage) (string, error) {
 			return "", fmt.Errorf("no suitable templates found for topic: %s (action: %s)", msg.Topic, actionKey)
 		}
 	}
+	topicTemplates = t.allowedTemplates(topicTemplates)
 
 	// Prepare placeholder values
-	source := ""
-	target := ""
-	if len(msg.RenamedFiles) > 0 {
-		source = msg.RenamedFiles[0].Source
-		target = msg.RenamedFiles[0].Target
-	}
-
-	// Enhanced item selection based on detected structures
-	item := msg.Item
-	if len(msg.DetectedFunctions) > 0 {
-		item = msg.DetectedFunctions[0]
-	} else if len(msg.DetectedStructs) > 0 {
-		item = msg.DetectedStructs[0]
-	} else if len(msg.DetectedMethods) > 0 {
-		item = msg.DetectedMethods[0]
-	}
+	item, source, target := placeholderValues(msg)
 
 	// Scoring-based selection: prefer templates that use available context
 	type scored struct {
@@ -227,273 +359,311 @@ func (t *Templater) GetMessage(msg *analyzer.CommitMessage) (string, error) {
 
 	for _, tmpl := range topicTemplates {
 		score := 0.0
-
-		// Core placeholder rewards
-		if strings.Contains(tmpl, "{item}") && item != "" {
-			score += 3.0
-		}
-		if strings.Contains(tmpl, "{purpose}") && msg.Purpose != "" && msg.Purpose != "general update" {
-			score += 2.5
-		}
-		if strings.Contains(tmpl, "{source}") && source != "" {
-			score += 3.0
-		}
-		if strings.Contains(tmpl, "{target}") && target != "" {
-			score += 3.0
-		}
-		if strings.Contains(tmpl, "{topic}") && msg.Topic != "" {
-			score += 1.5
-		}
-
-		// Context-aware bonuses
-
-		// Pattern matching bonus
-		for _, pattern := range msg.ChangePatterns {
-			patternKeywords := map[string][]string{
-				"error-handling":           {"fix", "error", "handle"},
-				"test-addition":            {"test", "coverage"},
-				"documentation":            {"docs", "document", "comment"},
-				"api-changes":              {"api", "endpoint", "route"},
-				"database":                 {"db", "database", "query", "schema"},
-				"security":                 {"security", "auth", "token"},
-				"performance":              {"perf", "optimize", "speed"},
-				"validation":               {"validat", "check", "verify"},
-				"logging":                  {"log", "trace", "debug"},
-				"middleware":               {"middleware", "chain"},
-				"interface-implementation": {"implement", "interface"},
-				"cli":                      {"command", "flag", "cli"},
-			}
-
-			if keywords, exists := patternKeywords[pattern]; exists {
-				for _, keyword := range keywords {
-					if strings.Contains(strings.ToLower(tmpl), keyword) {
-						score += 2.0
-						break
-					}
-				}
-			}
-		}
-
-		// File type context bonus
-		for _, ext := range msg.FileExtensions {
-			extKeywords := map[string][]string{
-				"go":   {"func", "method", "type"},
-				"json": {"config", "setting", "format"},
-				"yaml": {"config", "setting"},
-				"yml":  {"config", "setting"},
-				"md":   {"docs", "document"},
-				"sql":  {"database", "query"},
-			}
-
-			if keywords, exists := extKeywords[ext]; exists {
-				for _, keyword := range keywords {
-					if strings.Contains(strings.ToLower(tmpl), keyword) {
-						score += 1.0
-						break
-					}
-				}
-			}
-		}
-
-		// Detected structure bonus
-		if len(msg.DetectedFunctions) > 0 && strings.Contains(strings.ToLower(tmpl), "func") {
-			score += 1.5
-		}
-		if len(msg.DetectedStructs) > 0 && strings.Contains(strings.ToLower(tmpl), "type") {
-			score += 1.5
-		}
-		if len(msg.DetectedMethods) > 0 && strings.Contains(strings.ToLower(tmpl), "method") {
-			score += 1.5
-		}
-
-		// Major change bonus
-		if msg.IsMajor && (strings.Contains(strings.ToLower(tmpl), "restructure") ||
-			strings.Contains(strings.ToLower(tmpl), "refactor") ||
-			strings.Contains(strings.ToLower(tmpl), "major")) {
-			score += 2.0
-		}
-
-		// Special case bonuses
-		if msg.IsDocsOnly && strings.Contains(strings.ToLower(tmpl), "doc") {
-			score += 2.5
-		}
-		if msg.IsConfigOnly && strings.Contains(strings.ToLower(tmpl), "config") {
-			score += 2.5
-		}
-		if msg.IsDepsOnly && strings.Contains(strings.ToLower(tmpl), "dep") {
-			score += 2.5
-		}
-
-		// Penalty for generic templates when specific context exists
-		isGeneric := strings.Contains(strings.ToLower(tmpl), "general") ||
-			strings.Contains(strings.ToLower(tmpl), "update")
-		if isGeneric && (len(msg.ChangePatterns) > 0 || msg.Purpose != "general update") {
-			score -= 1.0
-		}
-
-		// Bonus for templates that match project scope
-		projectScope := inferProjectScope(msg)
-		if projectScope != "" {
-			templateLower := strings.ToLower(tmpl)
-			scopeLower := strings.ToLower(projectScope)
-
-			// Direct scope mention in template
-			if strings.Contains(templateLower, scopeLower) {
-				score += 1.5
-			}
-
-			// Topic placeholder with meaningful scope
-			if strings.Contains(tmpl, "{topic}") && msg.Topic != "" && msg.Topic != "core" {
-				score += 1.0
-			}
+		for _, term := range t.messageScoreTerms(tmpl, msg, item, source, target) {
+			score += term.Points
 		}
 
 		// Small randomness for variety (0-0.5)
-		score += rand.Float64() * 0.5
+		score += jitter(0.5)
 
 		candidates = append(candidates, scored{tmpl: tmpl, score: score})
 	}
 
-	// Sort candidates by score descending
+	// Sort candidates by score descending, ties lexically
 	sort.Slice(candidates, func(i, j int) bool {
-		return candidates[i].score > candidates[j].score
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].tmpl < candidates[j].tmpl
 	})
-
-	// Get best candidates (top scorers)
-	bestScore := -1.0
-	var bestCandidates []string
 	for _, c := range candidates {
-		if bestScore < 0 {
-			bestScore = c.score
-			bestCandidates = []string{c.tmpl}
-		} else if c.score >= bestScore-0.5 { // Allow slight variance in "best"
-			bestCandidates = append(bestCandidates, c.tmpl)
-		} else {
-			break // Scores are sorted, no need to continue
-		}
+		slog.Debug("scored template", "template", c.tmpl, "score", fmt.Sprintf("%.2f", c.score))
 	}
 
-	// Prefer a template that is not in recent history
-	replacerForCheck := strings.NewReplacer(
+	replacer := strings.NewReplacer(
 		"{topic}", msg.Topic,
 		"{item}", item,
 		"{purpose}", msg.Purpose,
 		"{source}", source,
 		"{target}", target,
+		"{issue}", msg.IssueTitle,
 	)
+	projectScope := inferProjectScope(msg)
+	render := func(tmpl string) string {
+		message := replacer.Replace(tmpl)
+		// Infer and apply project scope for better context
+		if projectScope != "" {
+			message = strings.Replace(message, "("+msg.Topic+")", "("+projectScope+")", 1)
+		}
+		// If scope exists in message, prefer replacing the topic scope pattern when present
+		if msg.Scope != "" {
+			message = strings.Replace(message, "("+msg.Topic+")", "("+msg.Scope+")", 1)
+		}
+		return t.restrictType(msg, cleanFinalMessage(message))
+	}
 
-	var chosen string
-	for _, tmpl := range bestCandidates {
-		candidateMsg := replacerForCheck.Replace(tmpl)
-		if !t.history.Contains(candidateMsg) {
-			chosen = tmpl
+	// Prefer the best candidate that is not in recent history
+	var chosen, formattedMsg string
+	for _, c := range candidates {
+		if message := render(c.tmpl); !t.repeats(message) {
+			chosen, formattedMsg = c.tmpl, message
 			break
 		}
 	}
 
-	// If all best candidates are in history, pick a random best candidate
+	// If every candidate is in history, the best one gets a detail of the changes
+	// it lacks, as a repeat of a recent commit is never suggested
 	if chosen == "" {
-		if len(bestCandidates) > 0 {
-			chosen = bestCandidates[rand.Intn(len(bestCandidates))]
-		} else {
-			// final fallback: random from topicTemplates
-			chosen = topicTemplates[rand.Intn(len(topicTemplates))]
+		chosen = candidates[0].tmpl
+		var ok bool
+		if formattedMsg, ok = t.withDetail(render(chosen), item, target, msg.Purpose); !ok {
+			return "", ErrNoFreshMessage
 		}
 	}
 
-	// Final replacement
-	replacer := strings.NewReplacer(
-		"{topic}", msg.Topic,
-		"{item}", item,
-		"{purpose}", msg.Purpose,
-		"{source}", source,
-		"{target}", target,
-	)
-
-	formattedMsg := replacer.Replace(chosen)
+	t.generated[formattedMsg] = chosen
+	slog.Info("chose template", "group", actionKey, "topic", msg.Topic, "template", chosen, "candidates", len(candidates))
 
-	// Infer and apply project scope for better context
-	projectScope := inferProjectScope(msg)
-	if projectScope != "" {
-		// Try common scope patterns
-		formattedMsg = strings.Replace(formattedMsg, "("+msg.Topic+")", "("+projectScope+")", 1)
-	}
+	return formattedMsg, nil
+}
 
-	// If scope exists in message, prefer replacing the topic scope pattern when present
-	if msg.Scope != "" {
-		// try common patterns
-		formattedMsg = strings.Replace(formattedMsg, "("+msg.Topic+")", "("+msg.Scope+")", 1)
-	}
+// Suggestion is a suggested commit message and the reasoning behind it
+type Suggestion struct {
+	Message   string
+	Reason    string
+	Type      string
+	Scope     string
+	Score     float64 // Score of the template for the changes, higher fitting them better
+	TopReason string  // Strongest signal behind the suggestion, such as a branch or keywords
+}
 
-	// Clean and normalize the final message
-	formattedMsg = cleanFinalMessage(formattedMsg)
+// groupFallbacks are the template groups used for actions whose own group a
+// template file lacks, as files written before the group existed do
+var groupFallbacks = map[string]string{"TEST": "M", "CI": "M", "BUILD": "MISC", "STYLE": "MISC"}
 
-	return formattedMsg, nil
+// actionTypes are the commit types of actions named differently
+var actionTypes = map[string]string{
+	"add":    "feat",
+	"bugfix": "fix",
 }
 
-// GetSuggestions returns multiple commit message suggestions ranked by context matching
-func (t *Templater) GetSuggestions(msg *analyzer.CommitMessage, maxSuggestions int) ([]string, error) {
+// GetSuggestions returns multiple commit message suggestions ranked by context
+// matching. When the analysis found other plausible actions, the list starts
+// with the best message of each interpretation whose type is not yet listed, so
+// it spans the types the changes could be, and is filled up in score order.
+// Near duplicates of listed messages are left out, even if fewer are left.
+func (t *Templater) GetSuggestions(msg *analyzer.CommitMessage, maxSuggestions int) ([]Suggestion, error) {
+	if msg.Version != "" {
+		return []Suggestion{{Message: t.releaseMessage(msg), Reason: "the changes only bump the version", Type: messageType(t.releaseMessage(msg)), Scope: "release", TopReason: "version bump"}}, nil
+	}
+	if msg.Move != nil {
+		return []Suggestion{{Message: t.moveMessage(msg), Reason: fmt.Sprintf("the changes move %d files with little change", msg.Move.Files), Type: messageType(t.moveMessage(msg)), TopReason: "directory move"}}, nil
+	}
+	if msg.Merge != nil {
+		return []Suggestion{{Message: t.mergeMessage(msg), Reason: "a merge of " + msg.Merge.Source + " is in progress", Type: messageType(t.mergeMessage(msg)), TopReason: "merge in progress"}}, nil
+	}
+	if msg.Revert != nil {
+		return []Suggestion{{Message: t.revertMessage(msg), Reason: "the changes undo commit " + msg.Revert.Commit.ShortHash(), Type: messageType(t.revertMessage(msg)), TopReason: "revert"}}, nil
+	}
+	if msg.Pick != nil {
+		// The original message comes first, then improvements on it
+		improved := *msg
+		improved.Pick = nil
+		suggestions, err := t.GetSuggestions(&improved, maxSuggestions-1)
+		keep := Suggestion{Message: t.pickMessage(msg), Reason: "the " + msg.Pick.Kind + " applies commit " + msg.Pick.Commit.ShortHash() + " again", Type: messageType(t.pickMessage(msg)), TopReason: "original message"}
+		return append([]Suggestion{keep}, suggestions...), err
+	}
+
 	actionKey, candidates := t.DebugInfo(msg)
 	if candidates == nil || len(candidates) == 0 {
 		return nil, fmt.Errorf("no templates found for action: %s", actionKey)
 	}
 
-	// Score all candidates
-	type scoredTemplate struct {
-		template string
-		score    float64
+	primary := t.rankedMessages(msg, candidates)
+	primaryType := msg.Action
+	if mapped, ok := actionTypes[primaryType]; ok {
+		primaryType = mapped
 	}
-
-	var scored []scoredTemplate
-
-	// Prepare placeholder values
-	source := ""
-	target := ""
-	if len(msg.RenamedFiles) > 0 {
-		source = msg.RenamedFiles[0].Source
-		target = msg.RenamedFiles[0].Target
+	primaryType = style.NearestType(primaryType, t.types)
+	// Templates of an action may use neighbouring types, labeled as such
+	primaryReason := func(r rankedMessage) (string, string) {
+		if commitType := messageType(r.message); commitType != primaryType && commitType != "" {
+			return fmt.Sprintf("%s template for changes that look like %s", commitType, msg.Action), commitType + " template"
+		}
+		return interpretationReason("looks like", msg.Action, msg.ActionReasons), topReason(msg.Action, msg.ActionReasons)
 	}
 
-	for _, tmpl := range candidates {
-		score := 0.0
-
-		// Use the comprehensive scoring function
-		score = t.scoreTemplate(tmpl, msg)
+	suggestions := make([]Suggestion, 0, maxSuggestions)
+	usedMessages := make(map[string]bool)
+	usedTypes := make(map[string]bool)
+	add := func(r rankedMessage, reason, top string) {
+		commitType, scope, _, _ := style.ParseSubject(r.message)
+		suggestions = append(suggestions, Suggestion{Message: r.message, Reason: reason, Type: commitType, Scope: scope, Score: r.score, TopReason: top})
+		usedMessages[r.message] = true
+		usedTypes[messageType(r.message)] = true
+		t.generated[r.message] = r.template
+	}
+	addPrimary := func(r rankedMessage) {
+		reason, top := primaryReason(r)
+		add(r, reason, top)
+	}
+	// Phrasings of a listed message are left out, so each one says something else
+	distinct := func(r rankedMessage) bool {
+		for _, s := range suggestions {
+			if nearDuplicate(r.message, s.Message) {
+				return false
+			}
+		}
+		return true
+	}
+	fresh := func(r rankedMessage) bool {
+		return !usedMessages[r.message] && !t.repeats(r.message) && distinct(r)
+	}
 
-		// Core placeholder rewards (additional specific bonuses)
-		if strings.Contains(tmpl, "{item}") && msg.Item != "" {
-			score += 1.0
+	// One message per interpretation, each of a type not listed yet
+	for _, r := range primary {
+		if fresh(r) {
+			addPrimary(r)
+			break
 		}
-		if strings.Contains(tmpl, "{purpose}") && msg.Purpose != "" && msg.Purpose != "general update" {
-			score += 1.0
+	}
+	for _, alt := range msg.Alternatives {
+		if len(suggestions) >= maxSuggestions {
+			break
 		}
-		if strings.Contains(tmpl, "{source}") && source != "" {
-			score += 1.5
+		commitType := alt.Action
+		if mapped, ok := actionTypes[commitType]; ok {
+			commitType = mapped
 		}
-		if strings.Contains(tmpl, "{target}") && target != "" {
-			score += 1.5
+		commitType = style.NearestType(commitType, t.types)
+		if usedTypes[commitType] {
+			continue
 		}
-		if strings.Contains(tmpl, "{topic}") && msg.Topic != "" {
-			score += 0.5
+		altMsg := *msg
+		altMsg.Action = alt.Action
+		_, altCandidates := t.DebugInfo(&altMsg)
+		for _, r := range t.rankedMessages(&altMsg, altCandidates) {
+			if messageType(r.message) == commitType && fresh(r) {
+				add(r, interpretationReason("could also be", alt.Action, alt.Reasons), topReason(alt.Action, alt.Reasons))
+				break
+			}
+		}
+	}
+
+	// Take top scored templates until we have enough unique messages
+	for _, r := range primary {
+		if len(suggestions) >= maxSuggestions {
+			break
+		}
+		if fresh(r) {
+			addPrimary(r)
 		}
+	}
+
+	// If we don't have enough suggestions, include some that might be in gitmit's
+	// history, but never repeat the subject of a recent real commit
+	for _, r := range primary {
+		if len(suggestions) >= maxSuggestions {
+			break
+		}
+		if !usedMessages[r.message] && !t.recentCommit(r.message) && distinct(r) {
+			addPrimary(r)
+		}
+	}
+
+	return suggestions, nil
+}
+
+// rankedMessage is a resolved template, the template it came from and its score
+type rankedMessage struct {
+	message  string
+	template string
+	score    float64
+}
+
+// rankedMessages resolves the candidate templates for a message, best scoring
+// first
+func (t *Templater) rankedMessages(msg *analyzer.CommitMessage, candidates []string) []rankedMessage {
+	type scoredTemplate struct {
+		template string
+		score    float64
+	}
+
+	var scored []scoredTemplate
+	for _, tmpl := range candidates {
+		// Use the comprehensive scoring function plus specific placeholder bonuses
+		score := t.scoreTemplate(tmpl, msg) + placeholderBonus(tmpl, msg)
 
 		// Small randomness for variety (0-1)
-		score += rand.Float64()
+		score += jitter(1)
 
 		scored = append(scored, scoredTemplate{tmpl, score})
 	}
 
-	// Sort by score descending
+	// Sort by score descending, ties lexically
 	sort.Slice(scored, func(i, j int) bool {
-		return scored[i].score > scored[j].score
+		if scored[i].score != scored[j].score {
+			return scored[i].score > scored[j].score
+		}
+		return scored[i].template < scored[j].template
 	})
 
-	// Get top N suggestions
-	suggestions := make([]string, 0, maxSuggestions)
-	usedMessages := make(map[string]bool)
+	replacer := placeholderReplacer(msg)
+	ranked := make([]rankedMessage, 0, len(scored))
+	for _, s := range scored {
+		ranked = append(ranked, rankedMessage{message: t.restrictType(msg, cleanFinalMessage(replacer.Replace(s.template))), template: s.template, score: s.score})
+	}
+	return ranked
+}
+
+// interpretationReason labels the suggestions for an action with the signals
+// pointing to it
+func interpretationReason(lead, action string, reasons []string) string {
+	if len(reasons) == 0 {
+		return fmt.Sprintf("%s %s", lead, action)
+	}
+	return fmt.Sprintf("%s %s: %s", lead, action, strings.Join(reasons, ", "))
+}
+
+// topReason returns the strongest of the reasons for an action, or the action
+// itself when there are none
+func topReason(action string, reasons []string) string {
+	if len(reasons) == 0 {
+		return "looks like " + action
+	}
+	return reasons[0]
+}
+
+// messageType returns the conventional commit type of a message, or "" if it
+// has none
+func messageType(message string) string {
+	colon := strings.Index(message, ":")
+	if colon < 0 {
+		return ""
+	}
+	head := message[:colon]
+	if i := strings.IndexAny(head, "(!"); i >= 0 {
+		head = head[:i]
+	}
+	if strings.ContainsAny(head, " ") {
+		return ""
+	}
+	return head
+}
+
+// placeholderValues returns the rename source and target and the most specific
+// item for a message; a directory move names its directories rather than the
+// first of its files
+func placeholderValues(msg *analyzer.CommitMessage) (item, source, target string) {
+	if msg.Move != nil {
+		source, target = msg.Move.Source+"/", msg.Move.Target+"/"
+	} else if len(msg.RenamedFiles) > 0 {
+		source = msg.RenamedFiles[0].Source
+		target = msg.RenamedFiles[0].Target
+	}
 
 	// Enhanced item selection based on detected structures
-	item := msg.Item
+	item = msg.Item
 	if len(msg.DetectedFunctions) > 0 {
 		item = msg.DetectedFunctions[0]
 	} else if len(msg.DetectedStructs) > 0 {
@@ -501,50 +671,78 @@ func (t *Templater) GetSuggestions(msg *analyzer.CommitMessage, maxSuggestions i
 	} else if len(msg.DetectedMethods) > 0 {
 		item = msg.DetectedMethods[0]
 	}
+	return item, source, target
+}
 
-	replacer := strings.NewReplacer(
+// Placeholders returns the placeholder/value pairs templates are resolved with
+func Placeholders(msg *analyzer.CommitMessage) []string {
+	item, source, target := placeholderValues(msg)
+	return []string{
 		"{topic}", msg.Topic,
 		"{item}", item,
 		"{purpose}", msg.Purpose,
 		"{source}", source,
 		"{target}", target,
-	)
-
-	// Take top scored templates until we have enough unique messages
-	for _, s := range scored {
-		if len(suggestions) >= maxSuggestions {
-			break
-		}
-
-		message := replacer.Replace(s.template)
-		message = cleanFinalMessage(message) // Clean the message
+		"{issue}", msg.IssueTitle,
+	}
+}
 
-		// Skip if we've seen this exact message or it's in history
-		if usedMessages[message] || t.history.Contains(message) {
-			continue
-		}
+// placeholderReplacer resolves template placeholders for a message
+func placeholderReplacer(msg *analyzer.CommitMessage) *strings.Replacer {
+	return strings.NewReplacer(Placeholders(msg)...)
+}
 
-		suggestions = append(suggestions, message)
-		usedMessages[message] = true
+// placeholderBonus rewards templates whose placeholders have values to fill them
+func placeholderBonus(tmpl string, msg *analyzer.CommitMessage) float64 {
+	_, source, target := placeholderValues(msg)
+	score := 0.0
+	if strings.Contains(tmpl, "{item}") && msg.Item != "" {
+		score += 1.0
+	}
+	if strings.Contains(tmpl, "{purpose}") && msg.Purpose != "" && msg.Purpose != "general update" {
+		score += 1.0
+	}
+	if strings.Contains(tmpl, "{source}") && source != "" {
+		score += 1.5
+	}
+	if strings.Contains(tmpl, "{target}") && target != "" {
+		score += 1.5
+	}
+	if strings.Contains(tmpl, "{topic}") && msg.Topic != "" {
+		score += 0.5
 	}
+	if strings.Contains(tmpl, "{issue}") && msg.IssueTitle != "" {
+		score += 1.5
+	}
+	return score
+}
 
-	// If we don't have enough suggestions, include some that might be in history
-	if len(suggestions) < maxSuggestions {
-		for _, s := range scored {
-			if len(suggestions) >= maxSuggestions {
-				break
-			}
+// Preview describes how one candidate template resolves for a message
+type Preview struct {
+	Template string
+	Message  string
+	Score    float64
+}
 
-			message := replacer.Replace(s.template)
-			message = cleanFinalMessage(message) // Clean the message
-			if !usedMessages[message] {
-				suggestions = append(suggestions, message)
-				usedMessages[message] = true
-			}
-		}
-	}
+// Preview resolves every candidate template for a message and returns them ranked
+// by score, without the random variety GetSuggestions adds. The action key the
+// candidates were selected for is returned as well.
+func (t *Templater) Preview(msg *analyzer.CommitMessage) (string, []Preview) {
+	actionKey, candidates := t.DebugInfo(msg)
+	replacer := placeholderReplacer(msg)
 
-	return suggestions, nil
+	previews := make([]Preview, 0, len(candidates))
+	for _, tmpl := range candidates {
+		previews = append(previews, Preview{
+			Template: tmpl,
+			Message:  cleanFinalMessage(replacer.Replace(tmpl)),
+			Score:    t.scoreTemplate(tmpl, msg) + placeholderBonus(tmpl, msg),
+		})
+	}
+	sort.SliceStable(previews, func(i, j int) bool {
+		return previews[i].Score > previews[j].Score
+	})
+	return actionKey, previews
 }
 
 // DebugInfo returns the resolved action key and the candidate templates for a CommitMessage
@@ -557,12 +755,12 @@ func (t *Templater) DebugInfo(msg *analyzer.CommitMessage) (string, []string) {
 		"bugfix":   "M",
 		"refactor": "R",
 		"chore":    "D",
-		"test":     "M",
+		"test":     "TEST",
 		"docs":     "DOC",
-		"ci":       "M",
+		"ci":       "CI",
 		"perf":     "M",
-		"style":    "MISC",
-		"build":    "MISC",
+		"style":    "STYLE",
+		"build":    "BUILD",
 		"security": "SECURITY",
 	}
 
@@ -579,6 +777,9 @@ func (t *Templater) DebugInfo(msg *analyzer.CommitMessage) (string, []string) {
 	actionTemplates, ok := t.templates[actionKey]
 	if !ok {
 		fallbackActions := []string{"DOC", "A", "M", "R", "D", "MISC"}
+		if group, exists := groupFallbacks[actionKey]; exists {
+			fallbackActions = append([]string{group}, fallbackActions...)
+		}
 		for _, fb := range fallbackActions {
 			if templates, exists := t.templates[fb]; exists {
 				actionTemplates = templates
@@ -591,32 +792,14 @@ func (t *Templater) DebugInfo(msg *analyzer.CommitMessage) (string, []string) {
 		}
 	}
 
-	normalizedTopic := strings.ToLower(strings.TrimSpace(msg.Topic))
-	var topicTemplates []string
-	if normalizedTopic != "" {
-		if templates, exists := actionTemplates[normalizedTopic]; exists && len(templates) > 0 {
-			topicTemplates = templates
-		}
-	}
-	if len(topicTemplates) == 0 {
-		for topic, templates := range actionTemplates {
-			if topic == "_default" {
-				continue
-			}
-			tname := strings.ToLower(topic)
-			if normalizedTopic != "" && (strings.Contains(tname, normalizedTopic) || strings.Contains(normalizedTopic, tname)) {
-				topicTemplates = templates
-				break
-			}
-		}
-	}
+	topicTemplates := actionTemplates[matchTopic(msg, actionTemplates)]
 	if len(topicTemplates) == 0 {
 		if defaults, exists := actionTemplates["_default"]; exists && len(defaults) > 0 {
 			topicTemplates = defaults
 		}
 	}
 
-	return actionKey, topicTemplates
+	return actionKey, t.allowedTemplates(topicTemplates)
 }
 
 // scoreTemplate scores a template based on how well it matches the commit message context
@@ -640,6 +823,11 @@ func (t *Templater) scoreTemplate(template string, msg *analyzer.CommitMessage)
 		}
 	}
 
+	// Templates naming the issue are only usable when one is referenced
+	if strings.Contains(template, "{issue}") && msg.IssueTitle == "" {
+		score -= 50.0
+	}
+
 	// Bonus for templates that match detected patterns
 	for _, pattern := range msg.ChangePatterns {
 		if strings.Contains(template, pattern) ||
@@ -686,6 +874,9 @@ func (t *Templater) scoreTemplate(template string, msg *analyzer.CommitMessage)
 		score -= 0.5
 	}
 
+	// Learned preference from accepted/edited/rejected suggestions
+	score += t.history.TemplatePreference(template) * preferenceWeight
+
 	// Bonus for templates matching major changes
 	if msg.IsMajor && (strings.Contains(template, "restructure") ||
 		strings.Contains(template, "refactor") || strings.Contains(template, "major")) {
@@ -719,6 +910,22 @@ func (t *Templater) scoreTemplate(template string, msg *analyzer.CommitMessage)
 // - History tracking to avoid repetition
 // - Weighted randomization for variety
 func (t *Templater) GetAlternativeSuggestion(msg *analyzer.CommitMessage, usedSuggestions map[string]bool) (string, error) {
+	if msg.Version != "" && !usedSuggestions[t.releaseMessage(msg)] {
+		return t.releaseMessage(msg), nil
+	}
+	if msg.Move != nil && !usedSuggestions[t.moveMessage(msg)] {
+		return t.moveMessage(msg), nil
+	}
+	if msg.Merge != nil {
+		return t.mergeMessage(msg), nil
+	}
+	if msg.Revert != nil {
+		return t.revertMessage(msg), nil
+	}
+	if msg.Pick != nil && !usedSuggestions[t.pickMessage(msg)] {
+		return t.pickMessage(msg), nil
+	}
+
 	// Get all candidate templates using the same logic as GetSuggestions
 	actionKey, candidates := t.DebugInfo(msg)
 	if candidates == nil || len(candidates) == 0 {
@@ -726,22 +933,7 @@ func (t *Templater) GetAlternativeSuggestion(msg *analyzer.CommitMessage, usedSu
 	}
 
 	// Prepare placeholder values
-	source := ""
-	target := ""
-	if len(msg.RenamedFiles) > 0 {
-		source = msg.RenamedFiles[0].Source
-		target = msg.RenamedFiles[0].Target
-	}
-
-	// Enhanced item selection based on detected structures
-	item := msg.Item
-	if len(msg.DetectedFunctions) > 0 {
-		item = msg.DetectedFunctions[0]
-	} else if len(msg.DetectedStructs) > 0 {
-		item = msg.DetectedStructs[0]
-	} else if len(msg.DetectedMethods) > 0 {
-		item = msg.DetectedMethods[0]
-	}
+	item, source, target := placeholderValues(msg)
 
 	replacer := strings.NewReplacer(
 		"{topic}", msg.Topic,
@@ -749,6 +941,7 @@ func (t *Templater) GetAlternativeSuggestion(msg *analyzer.CommitMessage, usedSu
 		"{purpose}", msg.Purpose,
 		"{source}", source,
 		"{target}", target,
+		"{issue}", msg.IssueTitle,
 	)
 
 	// Score all candidates and sort by relevance with diversity bonus
@@ -762,10 +955,10 @@ func (t *Templater) GetAlternativeSuggestion(msg *analyzer.CommitMessage, usedSu
 
 	for _, tmpl := range candidates {
 		message := replacer.Replace(tmpl)
-		message = cleanFinalMessage(message) // Clean the message
+		message = t.restrictType(msg, cleanFinalMessage(message)) // Clean the message
 
-		// Skip if already used
-		if usedSuggestions[message] {
+		// Skip if already used or identical to a recent commit
+		if usedSuggestions[message] || t.recentCommit(message) {
 			continue
 		}
 
@@ -786,17 +979,21 @@ func (t *Templater) GetAlternativeSuggestion(msg *analyzer.CommitMessage, usedSu
 		score += diversityBonus
 
 		// Small random factor for variety (0-1)
-		score += rand.Float64()
+		score += jitter(1)
 
 		scored = append(scored, scoredTemplate{tmpl, message, score})
 	}
 
 	if len(scored) == 0 {
-		// If all have been used, reset and try again with lower standards
+		// If all have been used, reset and try again with lower standards, but
+		// still never repeat a recent commit
 		for _, tmpl := range candidates {
 			message := replacer.Replace(tmpl)
-			message = cleanFinalMessage(message) // Clean the message
-			score := t.scoreTemplate(tmpl, msg) + rand.Float64()
+			message = t.restrictType(msg, cleanFinalMessage(message)) // Clean the message
+			if t.recentCommit(message) {
+				continue
+			}
+			score := t.scoreTemplate(tmpl, msg) + jitter(1)
 			scored = append(scored, scoredTemplate{tmpl, message, score})
 		}
 	}
@@ -805,14 +1002,276 @@ func (t *Templater) GetAlternativeSuggestion(msg *analyzer.CommitMessage, usedSu
 		return "", fmt.Errorf("no alternative suggestions available")
 	}
 
-	// Sort by score descending and return the top one
+	// Sort by score descending, ties lexically, and return the top one
 	sort.Slice(scored, func(i, j int) bool {
-		return scored[i].score > scored[j].score
+		if scored[i].score != scored[j].score {
+			return scored[i].score > scored[j].score
+		}
+		return scored[i].message < scored[j].message
 	})
 
+	t.generated[scored[0].message] = scored[0].template
 	return scored[0].message, nil
 }
 
+// ScoreTerm is one part of the score of a template: what it rewards or
+// penalizes and the points it adds
+type ScoreTerm struct {
+	Reason string
+	Points float64
+}
+
+// messageScoreTerms scores a template for GetMessage by how well it uses the
+// context of the changes, term by term
+func (t *Templater) messageScoreTerms(tmpl string, msg *analyzer.CommitMessage, item, source, target string) []ScoreTerm {
+	var terms []ScoreTerm
+	add := func(points float64, format string, args ...interface{}) {
+		terms = append(terms, ScoreTerm{Reason: fmt.Sprintf(format, args...), Points: points})
+	}
+	tmplLower := strings.ToLower(tmpl)
+
+	// Core placeholder rewards
+	if strings.Contains(tmpl, "{item}") && item != "" {
+		add(3.0, "fills {item} with %s", item)
+	}
+	if strings.Contains(tmpl, "{purpose}") && msg.Purpose != "" && msg.Purpose != "general update" {
+		add(2.5, "fills {purpose} with %s", msg.Purpose)
+	}
+	if strings.Contains(tmpl, "{source}") && source != "" {
+		add(3.0, "fills {source} with %s", source)
+	}
+	if strings.Contains(tmpl, "{target}") && target != "" {
+		add(3.0, "fills {target} with %s", target)
+	}
+	if strings.Contains(tmpl, "{topic}") && msg.Topic != "" {
+		add(1.5, "fills {topic} with %s", msg.Topic)
+	}
+	// The title of the ticket or issue says what the change is for in the
+	// team's own words, so it beats the other placeholders
+	if strings.Contains(tmpl, "{issue}") {
+		if msg.IssueTitle != "" {
+			add(4.0, "fills {issue} with %s", msg.IssueTitle)
+		} else {
+			add(-50.0, "needs {issue}, but no ticket or issue is referenced")
+		}
+	}
+
+	// Context-aware bonuses
+
+	// Pattern matching bonus
+	for _, pattern := range msg.ChangePatterns {
+		patternKeywords := map[string][]string{
+			"error-handling":           {"fix", "error", "handle"},
+			"test-addition":            {"test", "coverage"},
+			"documentation":            {"docs", "document", "comment"},
+			"api-changes":              {"api", "endpoint", "route"},
+			"database":                 {"db", "database", "query", "schema"},
+			"security":                 {"security", "auth", "token"},
+			"performance":              {"perf", "optimize", "speed"},
+			"validation":               {"validat", "check", "verify"},
+			"logging":                  {"log", "trace", "debug"},
+			"middleware":               {"middleware", "chain"},
+			"interface-implementation": {"implement", "interface"},
+			"cli":                      {"command", "flag", "cli"},
+		}
+
+		if keywords, exists := patternKeywords[pattern]; exists {
+			for _, keyword := range keywords {
+				if strings.Contains(tmplLower, keyword) {
+					add(2.0, "%q suits the %s change pattern", keyword, pattern)
+					break
+				}
+			}
+		}
+	}
+
+	// File type context bonus
+	for _, ext := range msg.FileExtensions {
+		extKeywords := map[string][]string{
+			"go":   {"func", "method", "type"},
+			"json": {"config", "setting", "format"},
+			"yaml": {"config", "setting"},
+			"yml":  {"config", "setting"},
+			"md":   {"docs", "document"},
+			"sql":  {"database", "query"},
+		}
+
+		if keywords, exists := extKeywords[ext]; exists {
+			for _, keyword := range keywords {
+				if strings.Contains(tmplLower, keyword) {
+					add(1.0, "%q suits .%s files", keyword, ext)
+					break
+				}
+			}
+		}
+	}
+
+	// Detected structure bonus
+	if len(msg.DetectedFunctions) > 0 && strings.Contains(tmplLower, "func") {
+		add(1.5, "mentions functions, which changed")
+	}
+	if len(msg.DetectedStructs) > 0 && strings.Contains(tmplLower, "type") {
+		add(1.5, "mentions types, which changed")
+	}
+	if len(msg.DetectedMethods) > 0 && strings.Contains(tmplLower, "method") {
+		add(1.5, "mentions methods, which changed")
+	}
+
+	// Major change bonus
+	if msg.IsMajor && (strings.Contains(tmplLower, "restructure") ||
+		strings.Contains(tmplLower, "refactor") ||
+		strings.Contains(tmplLower, "major")) {
+		add(2.0, "suits a major change")
+	}
+
+	// Special case bonuses
+	if msg.IsDocsOnly && strings.Contains(tmplLower, "doc") {
+		add(2.5, "suits documentation-only changes")
+	}
+	if msg.IsConfigOnly && strings.Contains(tmplLower, "config") {
+		add(2.5, "suits configuration-only changes")
+	}
+	if msg.IsDepsOnly && strings.Contains(tmplLower, "dep") {
+		add(2.5, "suits dependency-only changes")
+	}
+
+	// Penalty for generic templates when specific context exists
+	isGeneric := strings.Contains(tmplLower, "general") ||
+		strings.Contains(tmplLower, "update")
+	if isGeneric && (len(msg.ChangePatterns) > 0 || msg.Purpose != "general update") {
+		add(-1.0, "generic wording despite specific context")
+	}
+
+	// Bonus for templates that match project scope
+	projectScope := inferProjectScope(msg)
+	if projectScope != "" {
+		scopeLower := strings.ToLower(projectScope)
+
+		// Direct scope mention in template
+		if strings.Contains(tmplLower, scopeLower) {
+			add(1.5, "mentions the %s scope", projectScope)
+		}
+
+		// Topic placeholder with meaningful scope
+		if strings.Contains(tmpl, "{topic}") && msg.Topic != "" && msg.Topic != "core" {
+			add(1.0, "{topic} names a meaningful scope")
+		}
+	}
+
+	// Learned preference from accepted/edited/rejected suggestions
+	if preference := t.history.TemplatePreference(tmpl) * preferenceWeight; preference != 0 {
+		add(preference, "learned preference from past outcomes")
+	}
+	return terms
+}
+
+// TemplateExplanation tells where a template comes from and how GetMessage
+// scored it, before the random variety added to break ties
+type TemplateExplanation struct {
+	Group string // Action group, such as "A" or "DOC"
+	Topic string // Topic within the group, "_default" for the fallbacks
+	Terms []ScoreTerm
+	Score float64
+}
+
+// ExplainTemplate breaks down the score of a template for a message
+func (t *Templater) ExplainTemplate(msg *analyzer.CommitMessage, tmpl string) TemplateExplanation {
+	var explanation TemplateExplanation
+	groups := make([]string, 0, len(t.templates))
+	for group := range t.templates {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+locate:
+	for _, group := range groups {
+		for topic, templates := range t.templates[group] {
+			for _, candidate := range templates {
+				if candidate == tmpl {
+					explanation.Group, explanation.Topic = group, topic
+					break locate
+				}
+			}
+		}
+	}
+
+	item, source, target := placeholderValues(msg)
+	explanation.Terms = t.messageScoreTerms(tmpl, msg, item, source, target)
+	for _, term := range explanation.Terms {
+		explanation.Score += term.Points
+	}
+	return explanation
+}
+
+// TemplateFor returns the template that produced a message generated by this
+// Templater, or an empty string if the message did not come from a template
+func (t *Templater) TemplateFor(message string) string {
+	return t.generated[message]
+}
+
+// releaseMessage is the message of changes that only bump the version, which
+// is the same whatever the templates
+func (t *Templater) releaseMessage(msg *analyzer.CommitMessage) string {
+	return t.restrictType(msg, "chore(release): "+msg.Version)
+}
+
+// moveMessage is the message of changes that mostly move a directory, which
+// names both directories rather than one of the files
+func (t *Templater) moveMessage(msg *analyzer.CommitMessage) string {
+	return t.restrictType(msg, "refactor: "+msg.Move.Phrase())
+}
+
+// revertMessage is the message of changes undoing an earlier commit, named
+// by the subject of that commit
+func (t *Templater) revertMessage(msg *analyzer.CommitMessage) string {
+	return t.restrictType(msg, "revert: "+msg.Purpose)
+}
+
+// pickMessage is the message of a commit a cherry-pick or rebase applies
+// again, kept from the original commit
+func (t *Templater) pickMessage(msg *analyzer.CommitMessage) string {
+	return t.restrictType(msg, msg.Pick.Commit.Subject)
+}
+
+// mergeMessage is the message of a merge in progress, which names the
+// conflicts it resolves or what it merges rather than the combined diff
+func (t *Templater) mergeMessage(msg *analyzer.CommitMessage) string {
+	return t.restrictType(msg, "merge: "+msg.Purpose)
+}
+
+// restrictType gives a message the nearest allowed type when its own is left
+// out. Merges and reverts get the subjects git gives them instead, which
+// commit linters let through whatever types they allow.
+func (t *Templater) restrictType(msg *analyzer.CommitMessage, message string) string {
+	if len(t.types) == 0 {
+		return message
+	}
+	switch commitType := messageType(message); {
+	case commitType == "merge" && !slices.Contains(t.types, commitType) && msg.Merge != nil && msg.Merge.Subject != "":
+		return msg.Merge.Subject
+	case commitType == "revert" && !slices.Contains(t.types, commitType) && msg.Revert != nil:
+		return fmt.Sprintf("Revert %q", msg.Revert.Commit.Subject)
+	}
+	return style.RestrictType(message, t.types)
+}
+
+// allowedTemplates leaves out the templates of types the allowed types leave
+// out, unless no template is left
+func (t *Templater) allowedTemplates(templates []string) []string {
+	if len(t.types) == 0 {
+		return templates
+	}
+	var allowed []string
+	for _, tmpl := range templates {
+		if slices.Contains(t.types, messageType(tmpl)) {
+			allowed = append(allowed, tmpl)
+		}
+	}
+	if len(allowed) == 0 {
+		return templates
+	}
+	return allowed
+}
+
 // resolveSpecialFile detects special files like LICENSE, COPYING, .md docs, etc.
 // Returns the special template group to use, or empty string if not a special file
 func resolveSpecialFile(msg *analyzer.CommitMessage) string {
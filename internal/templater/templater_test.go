@@ -0,0 +1,544 @@
+package templater
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/analyzer"
+	"github.com/andev0x/gitmit/internal/config"
+	"github.com/andev0x/gitmit/internal/history"
+	"github.com/andev0x/gitmit/internal/parser"
+)
+
+func TestTemplatesValidate(t *testing.T) {
+	valid := func() Templates {
+		templates := Templates{}
+		for _, action := range requiredActions {
+			templates[action] = map[string][]string{"_default": {"chore({topic}): update {item}"}}
+		}
+		return templates
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(Templates)
+		wantErr string
+	}{
+		{name: "valid", mutate: func(Templates) {}},
+		{name: "missing action", mutate: func(t Templates) { delete(t, "R") }, wantErr: "missing required actions"},
+		{name: "missing default", mutate: func(t Templates) { t["A"] = map[string][]string{"api": {"feat(api): add {item}"}} }, wantErr: "'_default'"},
+		{name: "empty topic", mutate: func(t Templates) { t["M"]["api"] = nil }, wantErr: "has no templates"},
+		{name: "unbalanced braces", mutate: func(t Templates) { t["D"]["_default"] = []string{"chore: remove {item"} }, wantErr: "mismatched placeholder braces"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates := valid()
+			tt.mutate(templates)
+			err := templates.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Validate() = %v, want nil", err)
+			}
+			if tt.wantErr != "" && (!errors.Is(err, ErrTemplateInvalid) || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Validate() = %v, want ErrTemplateInvalid containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadTemplatesEmbedded(t *testing.T) {
+	if _, _, err := LoadTemplates("templates.json"); err != nil {
+		t.Fatalf("LoadTemplates(templates.json) = %v", err)
+	}
+}
+
+func TestReadTemplateFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	userDir := filepath.Join(home, "config", "gitmit", "templates")
+	work := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(work); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(work, "both.json"), "work")
+	write(filepath.Join(userDir, "both.json"), "user")
+	write(filepath.Join(userDir, "team.json"), "user")
+	write(filepath.Join(userDir, "empty.json"), "")
+	write(filepath.Join(home, "abs.json"), "absolute")
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		source  string
+		wantErr bool
+	}{
+		{name: "absolute path", file: filepath.Join(home, "abs.json"), content: "absolute", source: filepath.Join(home, "abs.json")},
+		{name: "working directory wins", file: "both.json", content: "work", source: filepath.Join(work, "both.json")},
+		{name: "user template directory", file: "team.json", content: "user", source: filepath.Join(userDir, "team.json")},
+		{name: "embedded", file: "templates.json", source: "embedded:templates.json"},
+		{name: "empty file skipped", file: "empty.json", wantErr: true},
+		{name: "missing", file: "missing.json", wantErr: true},
+		{name: "missing absolute path", file: filepath.Join(home, "missing.json"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, source, err := ReadTemplateFile(tt.file)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ReadTemplateFile(%s) = %s, want an error", tt.file, source)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadTemplateFile(%s) = %v", tt.file, err)
+			}
+			if source != tt.source {
+				t.Errorf("source = %q, want %q", source, tt.source)
+			}
+			if tt.content != "" && string(data) != tt.content {
+				t.Errorf("content = %q, want %q", data, tt.content)
+			}
+		})
+	}
+}
+
+func TestTemplatesMerge(t *testing.T) {
+	base := Templates{"A": {"api": {"feat(api): add {item}"}, "_default": {"feat: add {item}"}}}
+	merged := base.Merge(map[string]map[string]config.TemplateList{
+		"A":   {"api": {"feat(api): expose {item}", "..."}, "_default": {"feat: introduce {item}"}},
+		"DOC": {"_default": {"...", "docs: document {item}"}},
+	})
+
+	want := Templates{
+		"A":   {"api": {"feat(api): expose {item}", "feat(api): add {item}"}, "_default": {"feat: introduce {item}"}},
+		"DOC": {"_default": {"docs: document {item}"}},
+	}
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("Merge() = %v, want %v", merged, want)
+	}
+	if got := base["A"]["_default"][0]; got != "feat: add {item}" {
+		t.Errorf("Merge() modified the base templates: %q", got)
+	}
+}
+
+func TestGetSuggestionsSpansAlternatives(t *testing.T) {
+	tp := &Templater{
+		templates: Templates{
+			"A": {"_default": {"feat({topic}): add {item}", "feat({topic}): introduce {item}"}},
+			"M": {"_default": {"fix({topic}): correct {item}", "fix({topic}): repair {item}", "refactor({topic}): tidy {item}"}},
+			"D": {"_default": {"chore({topic}): remove {item}"}},
+			"R": {"_default": {"refactor({topic}): restructure {item}"}},
+		},
+		history:   &history.CommitHistory{},
+		generated: make(map[string]string),
+	}
+	msg := &analyzer.CommitMessage{
+		Action:        "feat",
+		Topic:         "api",
+		Item:          "client",
+		ActionReasons: []string{"branch feat/client"},
+		Alternatives: []analyzer.Interpretation{
+			{Action: "refactor", Reasons: []string{"balanced diff (+10 −9)"}},
+			{Action: "chore"},
+		},
+	}
+
+	suggestions, err := tp.GetSuggestions(msg, 4)
+	if err != nil {
+		t.Fatalf("GetSuggestions() = %v", err)
+	}
+	var types []string
+	for _, s := range suggestions {
+		types = append(types, messageType(s.Message))
+	}
+	if want := []string{"feat", "refactor", "chore", "feat"}; !reflect.DeepEqual(types, want) {
+		t.Fatalf("types = %v, want %v", types, want)
+	}
+	if got := suggestions[0].Reason; got != "looks like feat: branch feat/client" {
+		t.Errorf("primary reason = %q", got)
+	}
+	if got := suggestions[1].Reason; got != "could also be refactor: balanced diff (+10 −9)" {
+		t.Errorf("alternative reason = %q", got)
+	}
+	if got := suggestions[2].Reason; got != "could also be chore" {
+		t.Errorf("alternative reason = %q", got)
+	}
+	if s := suggestions[0]; s.Type != "feat" || s.Scope != "api" || s.TopReason != "branch feat/client" || s.Score <= 0 {
+		t.Errorf("primary suggestion = %+v, want a scored feat(api) for branch feat/client", s)
+	}
+	if s := suggestions[1]; s.Type != "refactor" || s.TopReason != "balanced diff (+10 −9)" {
+		t.Errorf("alternative suggestion = %+v, want a refactor for the balanced diff", s)
+	}
+	if got := suggestions[2].TopReason; got != "looks like chore" {
+		t.Errorf("alternative top reason = %q", got)
+	}
+
+	// Without alternatives the list stays with the analyzed type
+	msg.Alternatives = nil
+	suggestions, _ = tp.GetSuggestions(msg, 2)
+	for _, s := range suggestions {
+		if messageType(s.Message) != "feat" {
+			t.Errorf("suggestion %q without alternatives is not a feat", s.Message)
+		}
+	}
+}
+
+func TestMessageType(t *testing.T) {
+	tests := map[string]string{
+		"feat(api): add client": "feat",
+		"fix!: drop v1":         "fix",
+		"docs: update README":   "docs",
+		"Update the README":     "",
+		"Merge branch: x":       "",
+	}
+	for message, want := range tests {
+		if got := messageType(message); got != want {
+			t.Errorf("messageType(%q) = %q, want %q", message, got, want)
+		}
+	}
+}
+
+func TestExplainTemplate(t *testing.T) {
+	tp := &Templater{
+		templates: Templates{
+			"A": {"_default": {"feat({topic}): add {item}"}},
+			"M": {"api": {"fix({topic}): handle {item} errors"}, "_default": {"fix: update code"}},
+		},
+		history:   &history.CommitHistory{},
+		generated: make(map[string]string),
+	}
+	msg := &analyzer.CommitMessage{Action: "fix", Topic: "api", Item: "client", Purpose: "general update", ChangePatterns: []string{"error-handling"}}
+
+	explanation := tp.ExplainTemplate(msg, "fix({topic}): handle {item} errors")
+	if explanation.Group != "M" || explanation.Topic != "api" {
+		t.Errorf("location = %s/%s, want M/api", explanation.Group, explanation.Topic)
+	}
+	want := []ScoreTerm{
+		{Reason: "fills {item} with client", Points: 3},
+		{Reason: "fills {topic} with api", Points: 1.5},
+		{Reason: `"fix" suits the error-handling change pattern`, Points: 2},
+		{Reason: "{topic} names a meaningful scope", Points: 1},
+	}
+	if !reflect.DeepEqual(explanation.Terms, want) {
+		t.Errorf("Terms = %+v, want %+v", explanation.Terms, want)
+	}
+	if explanation.Score != 7.5 {
+		t.Errorf("Score = %v, want 7.5", explanation.Score)
+	}
+}
+
+func TestIssuePlaceholder(t *testing.T) {
+	tp := &Templater{
+		templates: Templates{
+			"M": {"_default": {"fix({topic}): resolve {issue}", "fix({topic}): correct {item}"}},
+		},
+		history:   &history.CommitHistory{},
+		generated: make(map[string]string),
+	}
+	msg := &analyzer.CommitMessage{Action: "fix", Topic: "auth", Item: "login", Purpose: "general update", IssueTitle: "crash on empty password"}
+
+	got, err := tp.GetMessage(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "fix(auth): resolve crash on empty password" {
+		t.Errorf("GetMessage() with an issue = %q, want the {issue} template", got)
+	}
+
+	msg.IssueTitle = ""
+	if got, _ := tp.GetMessage(msg); strings.Contains(got, "resolve") {
+		t.Errorf("GetMessage() without an issue = %q, want the {item} template", got)
+	}
+}
+
+func TestDeterministicSuggestions(t *testing.T) {
+	defer SetDeterministic(false)
+	tp := &Templater{
+		templates: Templates{
+			"M": {"_default": {"fix({topic}): repair {item}", "fix({topic}): correct {item}", "fix({topic}): mend {item}"}},
+		},
+		history:   &history.CommitHistory{},
+		generated: make(map[string]string),
+	}
+	msg := &analyzer.CommitMessage{Action: "fix", Topic: "api", Item: "client", Purpose: "general update"}
+	suggest := func() []string {
+		suggestions, err := tp.GetSuggestions(msg, 3)
+		if err != nil {
+			t.Fatalf("GetSuggestions() = %v", err)
+		}
+		var messages []string
+		for _, s := range suggestions {
+			messages = append(messages, s.Message)
+		}
+		return messages
+	}
+
+	SetDeterministic(true)
+	first := suggest()
+	want := []string{"fix(api): correct client", "fix(api): mend client", "fix(api): repair client"}
+	if !reflect.DeepEqual(first, want) {
+		t.Fatalf("deterministic suggestions = %v, want ties broken lexically: %v", first, want)
+	}
+	for i := 0; i < 10; i++ {
+		if got := suggest(); !reflect.DeepEqual(got, first) {
+			t.Fatalf("deterministic suggestions changed from %v to %v", first, got)
+		}
+	}
+
+	SetDeterministic(false)
+	SetSeed(42)
+	seeded := suggest()
+	SetSeed(42)
+	if got := suggest(); !reflect.DeepEqual(got, seeded) {
+		t.Errorf("suggestions with the same seed = %v and %v", seeded, got)
+	}
+}
+
+func TestGetMessageAvoidsRecentCommits(t *testing.T) {
+	SetDeterministic(true)
+	defer SetDeterministic(false)
+	prefix := func(message string) string { return "[PAY-1] " + message }
+
+	tests := []struct {
+		name      string
+		committed []string
+		purpose   string
+		want      string
+		wantErr   error
+		suggested string // First suggestion, which is fresh whenever one is left
+	}{
+		{name: "fresh", want: "feat(api): add client", suggested: "feat(api): add client"},
+		{name: "formatted repeat", committed: []string{"[PAY-1] feat(api): add client"}, want: "feat(api): introduce client", suggested: "feat(api): introduce client"},
+		{name: "every candidate repeats", committed: []string{"[PAY-1] feat(api): add client", "[PAY-1] feat(api): introduce client"}, purpose: "retries", want: "feat(api): add client for retries"},
+		{name: "no detail left", committed: []string{"[PAY-1] feat(api): add client", "[PAY-1] feat(api): introduce client"}, wantErr: ErrNoFreshMessage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hist := &history.CommitHistory{}
+			for _, message := range tt.committed {
+				hist.AddEntry(message, "")
+			}
+			tp := &Templater{
+				templates: Templates{"A": {"_default": {"feat({topic}): add {item}", "feat({topic}): introduce {item}"}}},
+				history:   hist,
+				generated: make(map[string]string),
+			}
+			tp.FormatWith(prefix)
+			msg := &analyzer.CommitMessage{Action: "A", Topic: "api", Item: "client", Purpose: tt.purpose}
+
+			got, err := tp.GetMessage(msg)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetMessage() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetMessage() = %q, want %q", got, tt.want)
+			}
+			if tt.suggested == "" {
+				return
+			}
+			suggestions, err := tp.GetSuggestions(msg, 1)
+			if err != nil {
+				t.Fatalf("GetSuggestions() = %v", err)
+			}
+			if len(suggestions) == 0 || suggestions[0].Message != tt.suggested {
+				t.Errorf("GetSuggestions() = %+v, want %q first", suggestions, tt.suggested)
+			}
+		})
+	}
+}
+
+func TestReleaseMessage(t *testing.T) {
+	tp := &Templater{
+		templates: Templates{"D": {"_default": {"chore({topic}): update {item}"}}},
+		history:   &history.CommitHistory{},
+		generated: make(map[string]string),
+	}
+	msg := &analyzer.CommitMessage{Action: "chore", Topic: "release", Scope: "release", Item: "v1.2.3", Version: "v1.2.3"}
+
+	if got, err := tp.GetMessage(msg); err != nil || got != "chore(release): v1.2.3" {
+		t.Errorf("GetMessage() of a version bump = %q, %v, want the release message", got, err)
+	}
+	suggestions, err := tp.GetSuggestions(msg, 3)
+	if err != nil || len(suggestions) != 1 || suggestions[0].Message != "chore(release): v1.2.3" {
+		t.Errorf("GetSuggestions() of a version bump = %+v, %v, want only the release message", suggestions, err)
+	}
+}
+
+func TestMoveMessage(t *testing.T) {
+	tp := &Templater{
+		templates: Templates{"R": {"_default": {"refactor({topic}): rename {source} to {target}"}}},
+		history:   &history.CommitHistory{},
+		generated: make(map[string]string),
+	}
+	move := &analyzer.DirectoryMove{Source: "parser", Target: "internal/parser", Files: 3, Kind: "package"}
+	msg := &analyzer.CommitMessage{Action: "refactor", Topic: "parser", Item: "parser", Move: move}
+
+	if got, err := tp.GetMessage(msg); err != nil || got != "refactor: move parser package under internal/" {
+		t.Errorf("GetMessage() of a directory move = %q, %v, want the move message", got, err)
+	}
+	used := map[string]bool{"refactor: move parser package under internal/": true}
+	if got, err := tp.GetAlternativeSuggestion(msg, used); err != nil || got != "refactor(parser): rename parser/ to internal/parser/" {
+		t.Errorf("GetAlternativeSuggestion() = %q, %v, want the directories as source and target", got, err)
+	}
+}
+
+func TestMergeMessage(t *testing.T) {
+	tp := &Templater{
+		templates: Templates{"M": {"_default": {"fix({topic}): update {item}"}}},
+		history:   &history.CommitHistory{},
+		generated: make(map[string]string),
+	}
+	merge := &analyzer.MergeSummary{Source: "feature/login", Conflicts: []string{"parser/git.go"}, Areas: []string{"parser"}}
+	msg := &analyzer.CommitMessage{Action: "merge", Topic: "parser", Item: "feature/login", Purpose: "resolve conflicts in parser", Merge: merge}
+
+	if got, err := tp.GetMessage(msg); err != nil || got != "merge: resolve conflicts in parser" {
+		t.Errorf("GetMessage() of a merge = %q, %v, want the merge message", got, err)
+	}
+	suggestions, err := tp.GetSuggestions(msg, 3)
+	if err != nil || len(suggestions) != 1 || suggestions[0].Message != "merge: resolve conflicts in parser" {
+		t.Errorf("GetSuggestions() of a merge = %+v, %v, want only the merge message", suggestions, err)
+	}
+}
+
+func TestRevertMessage(t *testing.T) {
+	tp := &Templater{
+		templates: Templates{"M": {"_default": {"feat({topic}): update {item}"}}},
+		history:   &history.CommitHistory{},
+		generated: make(map[string]string),
+	}
+	revert := &analyzer.Revert{Commit: &parser.Commit{Hash: "0788eb289d43e3dfe9ea0c31f02102da69445d11", Subject: "feat(cache): add response caching"}}
+	msg := &analyzer.CommitMessage{Action: "revert", Item: "0788eb2", Purpose: revert.Commit.Subject, Revert: revert}
+
+	if got, err := tp.GetMessage(msg); err != nil || got != "revert: feat(cache): add response caching" {
+		t.Errorf("GetMessage() of a revert = %q, %v, want the revert message", got, err)
+	}
+	if got, err := tp.GetAlternativeSuggestion(msg, map[string]bool{}); err != nil || got != "revert: feat(cache): add response caching" {
+		t.Errorf("GetAlternativeSuggestion() of a revert = %q, %v, want the revert message", got, err)
+	}
+}
+
+func TestPickMessage(t *testing.T) {
+	tp := &Templater{
+		templates: Templates{"M": {"_default": {"fix({topic}): update {item}"}}},
+		history:   &history.CommitHistory{},
+		generated: make(map[string]string),
+	}
+	pick := &parser.Pick{Kind: "rebase", Commit: &parser.Commit{Hash: "c4e59c9528749f4f0ef4cabfbaedd70dc4f39455", Subject: "Fix the b line"}}
+	msg := &analyzer.CommitMessage{Action: "fix", Topic: "parser", Item: "git", Pick: pick}
+
+	if got, err := tp.GetMessage(msg); err != nil || got != "Fix the b line" {
+		t.Errorf("GetMessage() of a picked commit = %q, %v, want its original subject", got, err)
+	}
+	suggestions, err := tp.GetSuggestions(msg, 3)
+	if err != nil || len(suggestions) != 2 || suggestions[0].Message != "Fix the b line" || suggestions[1].Message != "fix(parser): update git" {
+		t.Errorf("GetSuggestions() of a picked commit = %+v, %v, want the original subject, then improvements", suggestions, err)
+	}
+	used := map[string]bool{"Fix the b line": true}
+	if got, err := tp.GetAlternativeSuggestion(msg, used); err != nil || got != "fix(parser): update git" {
+		t.Errorf("GetAlternativeSuggestion() = %q, %v, want an improvement on the original subject", got, err)
+	}
+}
+
+func TestRestrictTypes(t *testing.T) {
+	tp := &Templater{
+		templates: Templates{
+			"M":     {"_default": {"perf({topic}): speed up {item}", "fix({topic}): update {item}"}},
+			"STYLE": {"_default": {"style({topic}): fix whitespace in {item}"}},
+		},
+		history:   &history.CommitHistory{},
+		generated: make(map[string]string),
+	}
+	tp.RestrictTypes([]string{"feat", "fix", "chore", "docs"})
+
+	msg := &analyzer.CommitMessage{Action: "perf", Topic: "cache", Item: "lookups"}
+	if got, err := tp.GetMessage(msg); err != nil || got != "fix(cache): update lookups" {
+		t.Errorf("GetMessage() = %q, %v, want the template of an allowed type", got, err)
+	}
+	msg = &analyzer.CommitMessage{Action: "style", Topic: "ui", Item: "app.go"}
+	suggestions, err := tp.GetSuggestions(msg, 3)
+	if err != nil || len(suggestions) != 1 || suggestions[0].Message != "chore(ui): fix whitespace in app.go" || suggestions[0].Type != "chore" {
+		t.Errorf("GetSuggestions() = %+v, %v, want the nearest allowed type", suggestions, err)
+	}
+
+	revert := &analyzer.Revert{Commit: &parser.Commit{Hash: "0788eb289d43e3dfe9ea0c31f02102da69445d11", Subject: "feat(cache): add response caching"}}
+	msg = &analyzer.CommitMessage{Action: "revert", Purpose: revert.Commit.Subject, Revert: revert}
+	if got, err := tp.GetMessage(msg); err != nil || got != `Revert "feat(cache): add response caching"` {
+		t.Errorf("GetMessage() of a revert = %q, %v, want the subject git gives reverts", got, err)
+	}
+}
+
+func TestSpecialFileTemplates(t *testing.T) {
+	msg := &analyzer.CommitMessage{Action: "build", Topic: "docker", Scope: "docker", Item: "Dockerfile", Purpose: "update container build"}
+	tp := &Templater{
+		templates: Templates{
+			"BUILD": {"_default": {"build({topic}): update {item}"}},
+			"MISC":  {"_default": {"chore: general maintenance"}},
+		},
+		history:   &history.CommitHistory{},
+		generated: make(map[string]string),
+	}
+	if got, err := tp.GetMessage(msg); err != nil || got != "build(docker): update Dockerfile" {
+		t.Errorf("GetMessage() = %q, %v, want build(docker): update Dockerfile", got, err)
+	}
+
+	delete(tp.templates, "BUILD")
+	if got, err := tp.GetMessage(msg); err != nil || got != "chore: general maintenance" {
+		t.Errorf("GetMessage() without BUILD templates = %q, %v, want the MISC template", got, err)
+	}
+}
+
+func TestTestFileMessages(t *testing.T) {
+	templates, _, err := LoadTemplates("templates.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp := &Templater{templates: templates, history: &history.CommitHistory{}, generated: make(map[string]string)}
+	files := []string{
+		"spec/models/user_spec.rb", "test/models/user_test.rb", "src/app.spec.ts", "src/app.test.js",
+		"tests/test_app.py", "app/app_test.py", "src/test/java/UserTest.java", "app/src/UserTests.kt",
+		"tests/UserTest.php", "Tests/UserTests.cs", "Tests/UserTests.swift", "src/__tests__/app.js",
+	}
+	for _, status := range []string{"M", "A"} {
+		for _, file := range files {
+			change := &parser.Change{File: file, Action: status, FileExtension: strings.TrimPrefix(path.Ext(file), "."), Added: 4, Diff: "+\texpect(user.name).toBe(\"ada\")"}
+			if status == "M" {
+				change.Removed = 1
+			}
+			msg := analyzer.NewAnalyzer([]*parser.Change{change}, &config.Config{}).AnalyzeChanges(context.Background(), 0, 0, "")
+			got, err := tp.GetMessage(msg)
+			if err != nil || !strings.HasPrefix(got, "test(") {
+				t.Errorf("GetMessage() of %s %s = %q, %v, want a test: message", status, file, got, err)
+			}
+		}
+	}
+
+	// Template files without a TEST group use the test topic of M
+	delete(tp.templates, "TEST")
+	msg := &analyzer.CommitMessage{Action: "test", Topic: "spec", Item: "user_spec"}
+	if got, err := tp.GetMessage(msg); err != nil || !strings.HasPrefix(got, "test(") && got != "refactor(test): simplify test setup" {
+		t.Errorf("GetMessage() without TEST templates = %q, %v, want an M test template", got, err)
+	}
+}
@@ -0,0 +1,212 @@
+package templater
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/andev0x/gitmit/internal/analyzer"
+)
+
+const (
+	// minTopicSimilarity is the similarity between the changes and a topic
+	// group of templates from which the group is used instead of the defaults
+	minTopicSimilarity = 0.1
+
+	// maxDiffTerms bounds the words of a diff weighed when matching topics
+	maxDiffTerms = 5000
+
+	// diffTermWeight is the weight of a word of the diff next to the words of
+	// the topic, scope and item, so that the diff as a whole weighs about as
+	// much as them but no single line of it does
+	diffTermWeight = 0.2
+)
+
+// placeholderRegex matches the placeholders of a template, such as {item}
+var placeholderRegex = regexp.MustCompile(`\{[a-z_]+\}`)
+
+// stopTerms are words too common in code or commit messages to tell topics apart
+var stopTerms = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true, "into": true, "new": true,
+	"func": true, "return": true, "err": true, "nil": true, "var": true, "const": true, "let": true,
+	"if": true, "else": true, "import": true, "package": true, "type": true, "struct": true,
+	"string": true, "int": true, "bool": true, "true": true, "false": true, "null": true,
+	"go": true, "js": true, "ts": true, "py": true, "src": true, "internal": true, "pkg": true,
+}
+
+// matchTopic returns the topic group of templates for the changes: the group
+// named as the topic or, failing that, the group whose name and templates are
+// most similar to the changes by the TF-IDF cosine similarity of their words.
+// The changes are described by their topic, scope and item, then their files,
+// detected symbols and diff, each weighing less. Words match whole, so an
+// "author" topic does not get the auth templates. Test changes always get the
+// test group when there is one. It returns "" when no group is similar
+// enough, for the defaults.
+func matchTopic(msg *analyzer.CommitMessage, groups map[string][]string) string {
+	topic := strings.ToLower(strings.TrimSpace(msg.Topic))
+	if topic != "" && len(groups[topic]) > 0 {
+		return topic
+	}
+	if strings.EqualFold(msg.Action, "test") && len(groups["test"]) > 0 {
+		return "test"
+	}
+
+	var names []string
+	for name, templates := range groups {
+		if name != "_default" && len(templates) > 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+
+	// Each group is a document of its name and the words of its templates
+	documents := make([]map[string]float64, len(names))
+	frequency := make(map[string]int)
+	for i, name := range names {
+		document := make(map[string]float64)
+		addTerms(document, name, 3)
+		for _, tmpl := range groups[name] {
+			addTerms(document, templateText(tmpl), 1)
+		}
+		for term := range document {
+			frequency[term]++
+		}
+		documents[i] = document
+	}
+	idf := func(term string) float64 {
+		return math.Log(float64(1+len(names))/float64(1+frequency[term])) + 1
+	}
+
+	query := changeTerms(msg)
+	best, bestSimilarity := "", 0.0
+	for i, name := range names {
+		if similarity := cosine(query, documents[i], idf); similarity > bestSimilarity {
+			best, bestSimilarity = name, similarity
+		}
+	}
+	if bestSimilarity < minTopicSimilarity {
+		return ""
+	}
+	return best
+}
+
+// changeTerms returns the weighted words describing changes
+func changeTerms(msg *analyzer.CommitMessage) map[string]float64 {
+	terms := make(map[string]float64)
+	addTerms(terms, msg.Topic, 3)
+	addTerms(terms, msg.Scope, 2)
+	addTerms(terms, msg.Item, 2)
+	for _, file := range msg.Files {
+		addTerms(terms, file, 1)
+	}
+	for _, symbols := range [][]string{msg.DetectedFunctions, msg.DetectedStructs, msg.DetectedMethods} {
+		for _, symbol := range symbols {
+			addTerms(terms, symbol, 1)
+		}
+	}
+
+	// Only the changed lines of the diff, without the file headers
+	diffTerms := make(map[string]float64)
+	count := 0
+	for _, line := range strings.Split(msg.FullDiff, "\n") {
+		if count >= maxDiffTerms {
+			break
+		}
+		if strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---") || !strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "-") {
+			continue
+		}
+		for _, term := range splitTerms(line[1:]) {
+			diffTerms[term]++
+			count++
+		}
+	}
+	// Repeated words count logarithmically, so one identifier does not take over
+	for term, n := range diffTerms {
+		terms[term] += diffTermWeight * (1 + math.Log(n))
+	}
+	return terms
+}
+
+// addTerms adds the words of a text to a document with a weight
+func addTerms(document map[string]float64, text string, weight float64) {
+	for _, term := range splitTerms(text) {
+		document[term] += weight
+	}
+}
+
+// splitTerms splits text into lowercase words, splitting identifiers such as
+// tokenStore and token_store, without stop words and trailing plural s
+func splitTerms(text string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			if term := normalizeTerm(string(word)); term != "" {
+				words = append(words, term)
+			}
+			word = word[:0]
+		}
+	}
+	runes := []rune(text)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+	return words
+}
+
+// normalizeTerm lowercases a word and drops a plural s; it returns "" for
+// words that are too short, numbers or stop words
+func normalizeTerm(word string) string {
+	word = strings.ToLower(word)
+	if len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+		word = strings.TrimSuffix(word, "s")
+	}
+	if len(word) < 2 || stopTerms[word] || strings.IndexFunc(word, unicode.IsLetter) < 0 {
+		return ""
+	}
+	return word
+}
+
+// templateText returns the words of a template without its type, scope and
+// placeholders
+func templateText(tmpl string) string {
+	if i := strings.Index(tmpl, ": "); i >= 0 {
+		tmpl = tmpl[i+2:]
+	}
+	return placeholderRegex.ReplaceAllString(tmpl, " ")
+}
+
+// cosine returns the cosine similarity of two weighted documents, with each
+// word weighted by its inverse document frequency
+func cosine(a, b map[string]float64, idf func(string) float64) float64 {
+	var dot, normA, normB float64
+	for term, weight := range a {
+		w := weight * idf(term)
+		normA += w * w
+		if other, ok := b[term]; ok {
+			dot += w * other * idf(term)
+		}
+	}
+	for term, weight := range b {
+		w := weight * idf(term)
+		normB += w * w
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
@@ -0,0 +1,42 @@
+package templater
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/analyzer"
+)
+
+func TestMatchTopic(t *testing.T) {
+	groups := map[string][]string{
+		"auth":     {"feat(auth): implement {item} strategy", "feat(auth): integrate new authentication flow", "feat(auth): implement role-based access control"},
+		"api":      {"feat(api): expose new endpoint for {item}", "feat(api): implement REST handler for {item}", "feat(api): define request/response schema"},
+		"db":       {"feat(db): define schema for {item} entity", "feat(db): create migration for {item}", "feat(db): add database index for performance"},
+		"_default": {"feat({topic}): add support for {item}"},
+	}
+	tests := []struct {
+		name string
+		msg  *analyzer.CommitMessage
+		want string
+	}{
+		{"exact topic", &analyzer.CommitMessage{Topic: "API"}, "api"},
+		{"plural topic", &analyzer.CommitMessage{Topic: "apis", Files: []string{"apis/client.go"}}, "api"},
+		{"topic in the templates", &analyzer.CommitMessage{Topic: "database", Item: "users", Files: []string{"internal/database/users.go"}}, "db"},
+		{"words of the diff", &analyzer.CommitMessage{Topic: "handlers", Item: "user", FullDiff: "+func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {\n+\t// endpoint returning the user\n"}, "api"},
+		{"word containing a group", &analyzer.CommitMessage{Topic: "author", Item: "authors", Files: []string{"docs/authors.go"}, FullDiff: "+func ListAuthors() []Author {\n"}, ""},
+		{"unrelated", &analyzer.CommitMessage{Topic: "parser", Item: "lexer", FullDiff: "+\treturn token{}, io.EOF\n"}, ""},
+	}
+	for _, tt := range tests {
+		if got := matchTopic(tt.msg, groups); got != tt.want {
+			t.Errorf("matchTopic() of %s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSplitTerms(t *testing.T) {
+	got := splitTerms("func (s *tokenStore) ParseHTTPHeaders(user_id string) error")
+	want := []string{"token", "store", "parse", "http", "header", "user", "id", "error"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitTerms() = %q, want %q", got, want)
+	}
+}
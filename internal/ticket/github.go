@@ -0,0 +1,149 @@
+package ticket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/andev0x/gitmit/internal/gitcmd"
+)
+
+// IssueRef is a GitHub issue the changes refer to
+type IssueRef struct {
+	Number int
+	Closes bool // The changes resolve the issue rather than only mention it
+}
+
+// Issue is a GitHub issue as gh reports it
+type Issue struct {
+	Number int
+	Title  string
+	State  string // OPEN or CLOSED
+	Labels []string
+}
+
+// Branch names refer to an issue with #12, issue-12 or gh-12 anywhere, or with
+// a leading number as in the 12-crash-on-login branches GitHub creates. Dates
+// such as 2024-05 are not issues.
+var (
+	branchIssueRegex       = regexp.MustCompile(`(?i)(?:#|\bissues?[-/]?|\bgh-)(\d+)\b`)
+	branchIssuePrefixRegex = regexp.MustCompile(`(?:^|/)(\d+)-[A-Za-z]`)
+)
+
+// diffIssueRegex finds issues mentioned in added lines, such as "fixes #12" or
+// "see #40"; a bare #12 is too often something else, like a color
+var diffIssueRegex = regexp.MustCompile(`(?i)\b(close[sd]?|fix(?:e[sd])?|resolve[sd]?|refs?|see|issue)\s*:?\s+#(\d+)\b`)
+
+// closingKeywords are the words by which GitHub closes an issue
+var closingKeywords = []string{"close", "fix", "resolve"}
+
+// maxIssueRefs caps the issues looked up for one commit
+const maxIssueRefs = 3
+
+// FindIssueRefs returns the issues the branch name and the added lines of the
+// diffs refer to, in order of appearance. Issues named by the branch or after
+// a closing keyword are closed by the changes.
+func FindIssueRefs(branch string, diffs []string) []IssueRef {
+	var refs []IssueRef
+	add := func(number string, closes bool) {
+		n, err := strconv.Atoi(number)
+		if err != nil || n == 0 {
+			return
+		}
+		for i := range refs {
+			if refs[i].Number == n {
+				refs[i].Closes = refs[i].Closes || closes
+				return
+			}
+		}
+		refs = append(refs, IssueRef{Number: n, Closes: closes})
+	}
+
+	for _, m := range branchIssueRegex.FindAllStringSubmatch(branch, -1) {
+		add(m[1], true)
+	}
+	for _, m := range branchIssuePrefixRegex.FindAllStringSubmatch(branch, -1) {
+		add(m[1], true)
+	}
+	for _, diff := range diffs {
+		for _, line := range strings.Split(diff, "\n") {
+			if !strings.HasPrefix(line, "+") || strings.HasPrefix(line, "+++") {
+				continue
+			}
+			for _, m := range diffIssueRegex.FindAllStringSubmatch(line, -1) {
+				keyword := strings.ToLower(m[1])
+				closes := false
+				for _, closing := range closingKeywords {
+					closes = closes || strings.HasPrefix(keyword, closing)
+				}
+				add(m[2], closes)
+			}
+		}
+	}
+
+	if len(refs) > maxIssueRefs {
+		refs = refs[:maxIssueRefs]
+	}
+	return refs
+}
+
+// FetchIssue returns an issue of the repository with the gh CLI, bounded by
+// ctx and the tracker timeout
+func FetchIssue(ctx context.Context, number int) (*Issue, error) {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return nil, fmt.Errorf("gh is not installed")
+	}
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "gh", "issue", "view", strconv.Itoa(number), "--json", "number,title,state,labels")
+	cmd.Dir = gitcmd.Dir(ctx)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error viewing issue #%d with gh: %w", number, err)
+	}
+	return parseIssue(out)
+}
+
+// parseIssue parses the JSON gh issue view prints
+func parseIssue(data []byte) (*Issue, error) {
+	var raw struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		State  string `json:"state"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("error decoding gh output: %w", err)
+	}
+	issue := &Issue{Number: raw.Number, Title: raw.Title, State: raw.State}
+	for _, label := range raw.Labels {
+		issue.Labels = append(issue.Labels, label.Name)
+	}
+	sort.Strings(issue.Labels)
+	return issue, nil
+}
+
+// Context describes the issue for the AI, e.g. "#12: Crash on login (labels: bug)"
+func (i *Issue) Context() string {
+	context := fmt.Sprintf("#%d: %s", i.Number, i.Title)
+	if len(i.Labels) > 0 {
+		context += " (labels: " + strings.Join(i.Labels, ", ") + ")"
+	}
+	return context
+}
+
+// Footer returns the footer linking the issue: Closes for open issues the
+// changes resolve, Refs otherwise
+func (i *Issue) Footer(closes bool) string {
+	if closes && !strings.EqualFold(i.State, "closed") {
+		return fmt.Sprintf("Closes #%d", i.Number)
+	}
+	return fmt.Sprintf("Refs #%d", i.Number)
+}
@@ -0,0 +1,47 @@
+package ticket
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindIssueRefs(t *testing.T) {
+	tests := []struct {
+		branch string
+		diffs  []string
+		want   []IssueRef
+	}{
+		{"fix/#12-login", nil, []IssueRef{{12, true}}},
+		{"42-crash-on-login", nil, []IssueRef{{42, true}}},
+		{"feature/issue-7", nil, []IssueRef{{7, true}}},
+		{"hotfix/2024-05-patch", nil, nil},
+		{"main", []string{"+// Fixes #3 and see #9\n-// see #4\n+color: #123;\n"}, []IssueRef{{3, true}, {9, false}}},
+		{"fix/#5", []string{"+// see #5\n+// refs #6\n", "+// closes: #6\n"}, []IssueRef{{5, true}, {6, true}}},
+		{"main", []string{"+see #1 #2\n+see #2\n+see #3\n+see #4\n"}, []IssueRef{{1, false}, {2, false}, {3, false}}},
+	}
+	for _, tt := range tests {
+		if got := FindIssueRefs(tt.branch, tt.diffs); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FindIssueRefs(%q, %q) = %v, want %v", tt.branch, tt.diffs, got, tt.want)
+		}
+	}
+}
+
+func TestIssue(t *testing.T) {
+	issue, err := parseIssue([]byte(`{"number": 12, "title": "Crash on login", "state": "OPEN", "labels": [{"name": "ui"}, {"name": "bug"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := issue.Context(); got != "#12: Crash on login (labels: bug, ui)" {
+		t.Errorf("Context() = %q", got)
+	}
+	if got := issue.Footer(true); got != "Closes #12" {
+		t.Errorf("Footer(true) = %q, want Closes #12", got)
+	}
+	if got := issue.Footer(false); got != "Refs #12" {
+		t.Errorf("Footer(false) = %q, want Refs #12", got)
+	}
+	issue.State = "CLOSED"
+	if got := issue.Footer(true); got != "Refs #12" {
+		t.Errorf("Footer(true) of a closed issue = %q, want Refs #12", got)
+	}
+}
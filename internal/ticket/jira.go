@@ -0,0 +1,90 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// jiraClient uses the REST API of a Jira site with basic authentication
+type jiraClient struct {
+	http  *http.Client
+	url   string
+	user  string
+	token string
+}
+
+// jiraIssue is the part of a Jira issue the client reads
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+// jiraTransitions lists the transitions available to an issue
+type jiraTransitions struct {
+	Transitions []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		To   struct {
+			Name string `json:"name"`
+		} `json:"to"`
+	} `json:"transitions"`
+}
+
+func (c *jiraClient) Fetch(ctx context.Context, id string) (*Ticket, error) {
+	req, err := c.request(ctx, http.MethodGet, "/rest/api/2/issue/"+url.PathEscape(id)+"?fields=summary,status")
+	if err != nil {
+		return nil, err
+	}
+	var issue jiraIssue
+	if err := doJSON(c.http, req, nil, &issue); err != nil {
+		return nil, fmt.Errorf("error fetching %s from Jira: %w", id, err)
+	}
+	return &Ticket{ID: issue.Key, Title: issue.Fields.Summary, Status: issue.Fields.Status.Name}, nil
+}
+
+// Transition applies the transition named after the state, or leading to it
+func (c *jiraClient) Transition(ctx context.Context, id, state string) error {
+	path := "/rest/api/2/issue/" + url.PathEscape(id) + "/transitions"
+	req, err := c.request(ctx, http.MethodGet, path)
+	if err != nil {
+		return err
+	}
+	var available jiraTransitions
+	if err := doJSON(c.http, req, nil, &available); err != nil {
+		return fmt.Errorf("error listing the transitions of %s: %w", id, err)
+	}
+
+	for _, transition := range available.Transitions {
+		if !strings.EqualFold(transition.To.Name, state) && !strings.EqualFold(transition.Name, state) {
+			continue
+		}
+		req, err := c.request(ctx, http.MethodPost, path)
+		if err != nil {
+			return err
+		}
+		body := map[string]interface{}{"transition": map[string]string{"id": transition.ID}}
+		if err := doJSON(c.http, req, body, nil); err != nil {
+			return fmt.Errorf("error moving %s to %s: %w", id, state, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s cannot be moved to %q from its current state", id, state)
+}
+
+// request creates an authenticated request to a path of the site
+func (c *jiraClient) request(ctx context.Context, method, path string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.url, "/")+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Jira request: %w", err)
+	}
+	req.SetBasicAuth(c.user, c.token)
+	return req, nil
+}
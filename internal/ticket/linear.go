@@ -0,0 +1,138 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// linearURL is the GraphQL endpoint of Linear
+const linearURL = "https://api.linear.app/graphql"
+
+// linearClient uses the GraphQL API of Linear with a personal API key
+type linearClient struct {
+	http  *http.Client
+	url   string
+	token string
+}
+
+// linearIssueQuery fetches an issue by identifier, with the workflow states
+// of its team for transitions
+const linearIssueQuery = `query Issue($id: String!) {
+  issue(id: $id) {
+    id
+    identifier
+    title
+    state { name }
+    team { states { nodes { id name } } }
+  }
+}`
+
+// linearUpdateMutation moves an issue to another workflow state
+const linearUpdateMutation = `mutation Move($id: String!, $stateId: String!) {
+  issueUpdate(id: $id, input: { stateId: $stateId }) { success }
+}`
+
+// linearIssue is the response to linearIssueQuery
+type linearIssue struct {
+	Data struct {
+		Issue *struct {
+			ID         string `json:"id"`
+			Identifier string `json:"identifier"`
+			Title      string `json:"title"`
+			State      struct {
+				Name string `json:"name"`
+			} `json:"state"`
+			Team struct {
+				States struct {
+					Nodes []struct {
+						ID   string `json:"id"`
+						Name string `json:"name"`
+					} `json:"nodes"`
+				} `json:"states"`
+			} `json:"team"`
+		} `json:"issue"`
+	} `json:"data"`
+	Errors []linearError `json:"errors"`
+}
+
+// linearUpdate is the response to linearUpdateMutation
+type linearUpdate struct {
+	Data struct {
+		IssueUpdate struct {
+			Success bool `json:"success"`
+		} `json:"issueUpdate"`
+	} `json:"data"`
+	Errors []linearError `json:"errors"`
+}
+
+type linearError struct {
+	Message string `json:"message"`
+}
+
+func (c *linearClient) Fetch(ctx context.Context, id string) (*Ticket, error) {
+	issue, err := c.issue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &Ticket{ID: issue.Data.Issue.Identifier, Title: issue.Data.Issue.Title, Status: issue.Data.Issue.State.Name}, nil
+}
+
+// Transition moves the issue to the workflow state of its team with the name
+func (c *linearClient) Transition(ctx context.Context, id, state string) error {
+	issue, err := c.issue(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, node := range issue.Data.Issue.Team.States.Nodes {
+		if !strings.EqualFold(node.Name, state) {
+			continue
+		}
+		var update linearUpdate
+		if err := c.query(ctx, linearUpdateMutation, map[string]string{"id": issue.Data.Issue.ID, "stateId": node.ID}, &update); err != nil {
+			return fmt.Errorf("error moving %s to %s: %w", id, state, err)
+		}
+		if err := firstError(update.Errors); err != nil {
+			return fmt.Errorf("error moving %s to %s: %w", id, state, err)
+		}
+		if !update.Data.IssueUpdate.Success {
+			return fmt.Errorf("Linear did not move %s to %s", id, state)
+		}
+		return nil
+	}
+	return fmt.Errorf("the team of %s has no state %q", id, state)
+}
+
+// issue fetches an issue, failing when Linear does not know it
+func (c *linearClient) issue(ctx context.Context, id string) (*linearIssue, error) {
+	var issue linearIssue
+	if err := c.query(ctx, linearIssueQuery, map[string]string{"id": id}, &issue); err != nil {
+		return nil, fmt.Errorf("error fetching %s from Linear: %w", id, err)
+	}
+	if err := firstError(issue.Errors); err != nil {
+		return nil, fmt.Errorf("error fetching %s from Linear: %w", id, err)
+	}
+	if issue.Data.Issue == nil {
+		return nil, fmt.Errorf("issue %s not found in Linear", id)
+	}
+	return &issue, nil
+}
+
+// query sends a GraphQL query with its variables
+func (c *linearClient) query(ctx context.Context, query string, variables map[string]string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("error creating Linear request: %w", err)
+	}
+	req.Header.Set("Authorization", c.token)
+	return doJSON(c.http, req, map[string]interface{}{"query": query, "variables": variables}, out)
+}
+
+// firstError returns the first GraphQL error, if any
+func firstError(errors []linearError) error {
+	if len(errors) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", errors[0].Message)
+}
@@ -0,0 +1,101 @@
+// Package ticket fetches and transitions the Jira or Linear ticket a branch
+// is for
+package ticket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/andev0x/gitmit/internal/config"
+)
+
+// Ticket is an issue of the tracker
+type Ticket struct {
+	ID     string
+	Title  string
+	Status string
+}
+
+// Client talks to a ticket tracker
+type Client interface {
+	// Fetch returns the ticket with the given ID
+	Fetch(ctx context.Context, id string) (*Ticket, error)
+	// Transition moves the ticket to the state with the given name
+	Transition(ctx context.Context, id, state string) error
+}
+
+// ErrNoAPIKey is the error of a tracker configured without an API token
+var ErrNoAPIKey = errors.New("no API key")
+
+// tokenVariables are the environment variables holding the API token of each
+// provider when the config has none
+var tokenVariables = map[string]string{"jira": "JIRA_API_TOKEN", "linear": "LINEAR_API_KEY"}
+
+// requestTimeout bounds each call to the tracker, so a slow tracker delays a
+// commit by seconds at most
+const requestTimeout = 10 * time.Second
+
+// NewClient returns the client of the configured provider
+func NewClient(cfg config.TicketsConfig) (Client, error) {
+	token := cfg.Token
+	if token == "" {
+		token = os.Getenv(tokenVariables[cfg.Provider])
+	}
+
+	httpClient := &http.Client{Timeout: requestTimeout}
+	switch cfg.Provider {
+	case "jira":
+		if token == "" {
+			return nil, fmt.Errorf("%w for Jira: set tickets.token, GITMIT_TICKETS_TOKEN or JIRA_API_TOKEN", ErrNoAPIKey)
+		}
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("no Jira site: set tickets.url in the global config or GITMIT_TICKETS_URL")
+		}
+		return &jiraClient{http: httpClient, url: cfg.URL, user: cfg.User, token: token}, nil
+	case "linear":
+		if token == "" {
+			return nil, fmt.Errorf("%w for Linear: set tickets.token, GITMIT_TICKETS_TOKEN or LINEAR_API_KEY", ErrNoAPIKey)
+		}
+		return &linearClient{http: httpClient, url: linearURL, token: token}, nil
+	}
+	return nil, fmt.Errorf("unknown ticket provider %q", cfg.Provider)
+}
+
+// doJSON sends a request with an optional JSON body and decodes the JSON
+// response into out, if given
+func doJSON(client *http.Client, req *http.Request, body, out interface{}) error {
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error encoding request: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(data))
+		req.ContentLength = int64(len(data))
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s unreachable: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned status code: %d", req.URL.Host, resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding response of %s: %w", req.URL.Host, err)
+	}
+	return nil
+}
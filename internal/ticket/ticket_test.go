@@ -0,0 +1,111 @@
+package ticket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/config"
+)
+
+func TestJira(t *testing.T) {
+	var moved string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, token, ok := r.BasicAuth(); !ok || user != "me@acme.dev" || token != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/PROJ-1":
+			w.Write([]byte(`{"key": "PROJ-1", "fields": {"summary": "Add login", "status": {"name": "To Do"}}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/PROJ-1/transitions":
+			w.Write([]byte(`{"transitions": [{"id": "11", "name": "Start", "to": {"name": "In Progress"}}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue/PROJ-1/transitions":
+			var body struct {
+				Transition struct{ ID string } `json:"transition"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			moved = body.Transition.ID
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &jiraClient{http: server.Client(), url: server.URL + "/", user: "me@acme.dev", token: "secret"}
+	ticket, err := client.Fetch(context.Background(), "PROJ-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ticket.ID != "PROJ-1" || ticket.Title != "Add login" || ticket.Status != "To Do" {
+		t.Errorf("Fetch() = %+v", ticket)
+	}
+	if err := client.Transition(context.Background(), "PROJ-1", "in progress"); err != nil || moved != "11" {
+		t.Errorf("Transition() = %v, moved with %q, want transition 11", err, moved)
+	}
+	if err := client.Transition(context.Background(), "PROJ-1", "Done"); err == nil {
+		t.Error("Transition() to an unreachable state succeeded")
+	}
+	if _, err := client.Fetch(context.Background(), "PROJ-2"); err == nil {
+		t.Error("Fetch() of an unknown issue succeeded")
+	}
+}
+
+func TestLinear(t *testing.T) {
+	var moved string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body struct {
+			Query     string
+			Variables map[string]string
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case strings.HasPrefix(body.Query, "mutation"):
+			moved = body.Variables["stateId"]
+			w.Write([]byte(`{"data": {"issueUpdate": {"success": true}}}`))
+		case body.Variables["id"] == "ENG-7":
+			w.Write([]byte(`{"data": {"issue": {"id": "uuid", "identifier": "ENG-7", "title": "Fix crash", "state": {"name": "Todo"},
+				"team": {"states": {"nodes": [{"id": "s1", "name": "Todo"}, {"id": "s2", "name": "In Review"}]}}}}}`))
+		default:
+			w.Write([]byte(`{"data": {"issue": null}, "errors": [{"message": "Entity not found"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := &linearClient{http: server.Client(), url: server.URL, token: "key"}
+	ticket, err := client.Fetch(context.Background(), "ENG-7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ticket.ID != "ENG-7" || ticket.Title != "Fix crash" || ticket.Status != "Todo" {
+		t.Errorf("Fetch() = %+v", ticket)
+	}
+	if err := client.Transition(context.Background(), "ENG-7", "In Review"); err != nil || moved != "s2" {
+		t.Errorf("Transition() = %v, moved to %q, want s2", err, moved)
+	}
+	if _, err := client.Fetch(context.Background(), "ENG-8"); err == nil || !strings.Contains(err.Error(), "Entity not found") {
+		t.Errorf("Fetch() of an unknown issue = %v, want the GraphQL error", err)
+	}
+}
+
+func TestNewClientWithoutToken(t *testing.T) {
+	t.Setenv("JIRA_API_TOKEN", "")
+	_, err := NewClient(config.TicketsConfig{Provider: "jira", URL: "https://acme.atlassian.net", User: "me@acme.dev"})
+	if !errors.Is(err, ErrNoAPIKey) || !strings.Contains(err.Error(), "JIRA_API_TOKEN") {
+		t.Errorf("NewClient() without a token = %v, want ErrNoAPIKey naming JIRA_API_TOKEN", err)
+	}
+
+	t.Setenv("JIRA_API_TOKEN", "secret")
+	if _, err := NewClient(config.TicketsConfig{Provider: "jira", URL: "https://acme.atlassian.net", User: "me@acme.dev"}); err != nil {
+		t.Errorf("NewClient() with JIRA_API_TOKEN = %v, want a client", err)
+	}
+}
@@ -0,0 +1,80 @@
+package ui
+
+import "strings"
+
+// asciiLabels replace the emoji that carry meaning in ASCII mode; other emoji
+// only decorate and are dropped
+var asciiLabels = map[rune]string{
+	'✅': "[OK]",
+	'✓': "[OK]",
+	'✔': "[OK]",
+	'⚠': "[WARN]",
+	'❌': "[ERROR]",
+	'💡': "[TIP]",
+	'🎫': "[TICKET]",
+}
+
+// asciiSymbols replace the symbols and box-drawing characters of the output
+var asciiSymbols = map[rune]string{
+	'→': "->",
+	'←': "<-",
+	'↑': "^",
+	'↓': "v",
+	'−': "-",
+	'–': "-",
+	'—': "--",
+	'…': "...",
+	'×': "x",
+	'❯': ">",
+	'•': "*",
+	'·': "-",
+	'“': `"`,
+	'”': `"`,
+	'‘': "'",
+	'’': "'",
+	'─': "-",
+	'━': "-",
+	'═': "=",
+	'│': "|",
+	'┃': "|",
+	'║': "|",
+}
+
+// sparkLevels are the ASCII forms of the eighth blocks ▁ to █ of sparklines and bars
+const sparkLevels = "_.:-=+*#"
+
+// asciiText replaces emoji with labels such as [OK] or drops them, and
+// replaces symbols and box-drawing characters with ASCII look-alikes. Other
+// characters, such as accented letters, are kept.
+func asciiText(text string) string {
+	var b strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if label, ok := asciiLabels[r]; ok {
+			b.WriteString(label)
+			for i+1 < len(runes) && isEmojiModifier(runes[i+1]) {
+				i++
+			}
+			continue
+		}
+		switch symbol, ok := asciiSymbols[r]; {
+		case ok:
+			b.WriteString(symbol)
+		case isEmoji(r):
+			for i+1 < len(runes) && (isEmojiModifier(runes[i+1]) || runes[i+1] == ' ') {
+				i++
+			}
+		case isEmojiModifier(r):
+		case r >= 0x2581 && r <= 0x2588:
+			b.WriteByte(sparkLevels[r-0x2581])
+		case r >= 0x2580 && r <= 0x259F:
+			b.WriteByte('#')
+		case r >= 0x2500 && r <= 0x257F:
+			b.WriteByte('+')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,218 @@
+// Package ui prints gitmit's terminal output in the colors of the configured
+// theme, without emoji when the theme leaves them out, or in plain ASCII.
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/andev0x/gitmit/internal/config"
+)
+
+// attributes maps the words of theme colors to terminal attributes
+var attributes = map[string]color.Attribute{
+	"bold":      color.Bold,
+	"faint":     color.Faint,
+	"italic":    color.Italic,
+	"underline": color.Underline,
+	"black":     color.FgBlack,
+	"red":       color.FgRed,
+	"green":     color.FgGreen,
+	"yellow":    color.FgYellow,
+	"blue":      color.FgBlue,
+	"magenta":   color.FgMagenta,
+	"cyan":      color.FgCyan,
+	"white":     color.FgWhite,
+	"hiblack":   color.FgHiBlack,
+	"hired":     color.FgHiRed,
+	"higreen":   color.FgHiGreen,
+	"hiyellow":  color.FgHiYellow,
+	"hiblue":    color.FgHiBlue,
+	"himagenta": color.FgHiMagenta,
+	"hicyan":    color.FgHiCyan,
+	"hiwhite":   color.FgHiWhite,
+}
+
+// The colors of each role, set by SetTheme
+var (
+	accent  = color.New(color.FgCyan)
+	success = color.New(color.FgGreen)
+	warning = color.New(color.FgYellow)
+	failure = color.New(color.FgRed)
+	heading = color.New(color.FgBlue)
+	muted   = color.New(color.FgHiBlack)
+	emoji   = true
+	ascii   = false
+)
+
+// SetTheme applies a theme to all further output. Unknown color words are
+// ignored; config validation reports them.
+func SetTheme(theme config.ThemeConfig) {
+	accent = themeColor(theme.Accent, color.FgCyan)
+	success = themeColor(theme.Success, color.FgGreen)
+	warning = themeColor(theme.Warning, color.FgYellow)
+	failure = themeColor(theme.Error, color.FgRed)
+	heading = themeColor(theme.Heading, color.FgBlue)
+	muted = themeColor(theme.Muted, color.FgHiBlack)
+	emoji = theme.Emoji
+	ascii = theme.ASCII
+}
+
+// DisableColor turns colors off, as the NO_COLOR environment variable does
+func DisableColor() {
+	color.NoColor = true
+}
+
+// themeColor builds the color a theme names, or the fallback when it names none
+func themeColor(name string, fallback color.Attribute) *color.Color {
+	words := strings.Fields(strings.ToLower(name))
+	if len(words) == 0 {
+		return color.New(fallback)
+	}
+	c := color.New()
+	for _, word := range words {
+		if attribute, ok := attributes[word]; ok {
+			c.Add(attribute)
+		}
+	}
+	return c
+}
+
+// Accent prints a line in the accent color, used for suggested messages
+func Accent(format string, a ...interface{}) { printLine(accent, format, a...) }
+
+// Success prints a line reporting a completed action
+func Success(format string, a ...interface{}) { printLine(success, format, a...) }
+
+// Warn prints a warning line
+func Warn(format string, a ...interface{}) { printLine(warning, format, a...) }
+
+// Error prints a line reporting a failure
+func Error(format string, a ...interface{}) { printLine(failure, format, a...) }
+
+// Heading prints a section heading
+func Heading(format string, a ...interface{}) { printLine(heading, format, a...) }
+
+// Muted prints a hint or detail line
+func Muted(format string, a ...interface{}) { printLine(muted, format, a...) }
+
+// AccentString formats text in the accent color
+func AccentString(format string, a ...interface{}) string { return sprint(accent, format, a...) }
+
+// SuccessString formats text in the success color
+func SuccessString(format string, a ...interface{}) string { return sprint(success, format, a...) }
+
+// WarnString formats text in the warning color
+func WarnString(format string, a ...interface{}) string { return sprint(warning, format, a...) }
+
+// ErrorString formats text in the error color
+func ErrorString(format string, a ...interface{}) string { return sprint(failure, format, a...) }
+
+// HeadingString formats text in the heading color
+func HeadingString(format string, a ...interface{}) string { return sprint(heading, format, a...) }
+
+// MutedString formats text in the muted color
+func MutedString(format string, a ...interface{}) string { return sprint(muted, format, a...) }
+
+// Printf prints formatted output like fmt.Printf, as the theme shows it
+func Printf(format string, a ...interface{}) {
+	fmt.Print(Display(fmt.Sprintf(Text(format), a...)))
+}
+
+// Println prints its operands and a newline like fmt.Println, in plain ASCII
+// in ASCII mode
+func Println(a ...interface{}) {
+	fmt.Print(Display(fmt.Sprintln(a...)))
+}
+
+// Print prints its operands like fmt.Print, in plain ASCII in ASCII mode
+func Print(a ...interface{}) {
+	fmt.Print(Display(fmt.Sprint(a...)))
+}
+
+// printLine prints a line in a color. Like the line functions of fatih/color
+// it adds the newline, and only formats when given arguments.
+func printLine(c *color.Color, format string, a ...interface{}) {
+	text := render(format, a...)
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	c.Print(text)
+}
+
+// sprint formats text in a color, only formatting when given arguments
+func sprint(c *color.Color, format string, a ...interface{}) string {
+	return c.Sprint(render(format, a...))
+}
+
+// render formats output as the theme shows it, only formatting when given arguments
+func render(format string, a ...interface{}) string {
+	text := Text(format)
+	if len(a) > 0 {
+		text = fmt.Sprintf(text, a...)
+	}
+	return Display(text)
+}
+
+// Text returns literal output text as the theme shows it: without its emoji
+// when the theme leaves them out, or in plain ASCII. Only the text gitmit
+// writes itself goes through Text, never commit messages, which may contain
+// emoji on purpose.
+func Text(text string) string {
+	switch {
+	case ascii:
+		return asciiText(text)
+	case !emoji:
+		return stripEmoji(text)
+	}
+	return text
+}
+
+// Display returns any text shown on the terminal, commit messages included,
+// in plain ASCII in ASCII mode. Output meant for other programs, such as a
+// message printed for a script, never goes through Display.
+func Display(text string) string {
+	if ascii {
+		return asciiText(text)
+	}
+	return text
+}
+
+// Plural formats a count with the singular or plural form of a noun
+func Plural(n int, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// stripEmoji removes emoji and the space following each
+func stripEmoji(text string) string {
+	var b strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if !isEmoji(runes[i]) {
+			b.WriteRune(runes[i])
+			continue
+		}
+		// Skip the modifiers of the emoji and the spaces separating it from the text
+		for i+1 < len(runes) && (isEmojiModifier(runes[i+1]) || runes[i+1] == ' ') {
+			i++
+		}
+	}
+	return b.String()
+}
+
+// isEmoji reports whether r is an emoji or a dingbat used as one
+func isEmoji(r rune) bool {
+	return (r >= 0x1F000 && r <= 0x1FAFF) || // Emoticons, pictographs, transport and flags
+		(r >= 0x2600 && r <= 0x27BF && (r < 0x2768 || r > 0x2775)) || // Symbols and dingbats such as ⚠ and ✅, but not brackets such as ❯
+		(r >= 0x2B00 && r <= 0x2BFF) // Arrows and stars, such as ⭐
+}
+
+// isEmojiModifier reports whether r changes how the emoji before it looks
+func isEmojiModifier(r rune) bool {
+	return r == 0xFE0F || r == 0x200D || (r >= 0x1F3FB && r <= 0x1F3FF)
+}
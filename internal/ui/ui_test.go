@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/andev0x/gitmit/internal/config"
+)
+
+func TestStripEmoji(t *testing.T) {
+	tests := map[string]string{
+		"✅ Committed":              "Committed",
+		"⚠️ no staged changes":     "no staged changes",
+		"  💡 Tip: stage files":     "  Tip: stage files",
+		"🛰  serving on %s":         "serving on %s",
+		"Moved 🎫 to Done":          "Moved to Done",
+		"❯ select":                 "❯ select",
+		"+3 −1 → feat (…)":         "+3 −1 → feat (…)",
+		"👩‍💻 pairing":              "pairing",
+		"plain text without emoji": "plain text without emoji",
+	}
+	for text, want := range tests {
+		if got := stripEmoji(text); got != want {
+			t.Errorf("stripEmoji(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestThemeAttributes(t *testing.T) {
+	for _, word := range config.ThemeAttributes {
+		if _, ok := attributes[word]; !ok && word != "none" {
+			t.Errorf("theme attribute %q has no terminal attribute", word)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	defer SetTheme(config.DefaultConfig().Theme)
+
+	SetTheme(config.DefaultConfig().Theme)
+	if got := Text("✅ Done"); got != "✅ Done" {
+		t.Errorf("Text() with emoji = %q, want it unchanged", got)
+	}
+	theme := config.DefaultConfig().Theme
+	theme.Emoji = false
+	SetTheme(theme)
+	if got := Text("✅ Done"); got != "Done" {
+		t.Errorf("Text() without emoji = %q, want %q", got, "Done")
+	}
+}
+
+func TestASCIIText(t *testing.T) {
+	tests := map[string]string{
+		"✅ Committed":              "[OK] Committed",
+		"⚠️ no staged changes":     "[WARN] no staged changes",
+		"❌ error committing":       "[ERROR] error committing",
+		"  💡 Tip: stage files":     "  [TIP] Tip: stage files",
+		"🚀 feat: add login":        "feat: add login",
+		"❯ select (↑/↓ to move)":   "> select (^/v to move)",
+		"+3 −1 → feat (…)":         "+3 -1 -> feat (...)",
+		"Weekly: ▁▄█":              "Weekly: _-#",
+		"│ box ┌──┐":               "| box +--+",
+		"café":                     "café",
+		"plain text without emoji": "plain text without emoji",
+	}
+	for text, want := range tests {
+		if got := asciiText(text); got != want {
+			t.Errorf("asciiText(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestDisplay(t *testing.T) {
+	defer SetTheme(config.DefaultConfig().Theme)
+
+	SetTheme(config.DefaultConfig().Theme)
+	if got := Display("✨ feat: sparkle"); got != "✨ feat: sparkle" {
+		t.Errorf("Display() = %q, want it unchanged", got)
+	}
+	theme := config.DefaultConfig().Theme
+	theme.ASCII = true
+	SetTheme(theme)
+	if got := Display("✨ feat: sparkle"); got != "feat: sparkle" {
+		t.Errorf("Display() in ASCII mode = %q, want %q", got, "feat: sparkle")
+	}
+	if got := Text("✅ Done"); got != "[OK] Done" {
+		t.Errorf("Text() in ASCII mode = %q, want %q", got, "[OK] Done")
+	}
+}
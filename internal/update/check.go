@@ -0,0 +1,72 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/andev0x/gitmit/internal/xdg"
+)
+
+// checkInterval is how often the background check asks GitHub
+const checkInterval = 24 * time.Hour
+
+// checkTimeout bounds the background request, so a slow network never holds
+// up a command
+const checkTimeout = 3 * time.Second
+
+// checkCache is the latest release found by the last background check
+type checkCache struct {
+	CheckedAt time.Time `json:"checkedAt"`
+	Latest    string    `json:"latest"`
+}
+
+// StartCheck looks for a newer release than current in the background, at most
+// once every checkInterval, remembering the answer in $XDG_CACHE_HOME/gitmit.
+// The returned function gives a one-line notice when a newer release is
+// known, or ""; it never waits for the check, whose answer a later run shows
+// when it is not in yet.
+func StartCheck(ctx context.Context, current string) func() string {
+	cachePath := ""
+	if dir, err := xdg.CacheDir(); err == nil {
+		cachePath = filepath.Join(dir, "update.json")
+	}
+	var cache checkCache
+	if data, err := os.ReadFile(cachePath); err == nil {
+		_ = json.Unmarshal(data, &cache)
+	}
+
+	done := make(chan string, 1)
+	if time.Since(cache.CheckedAt) >= checkInterval {
+		go func() {
+			ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+			release, err := Latest(ctx)
+			if err != nil {
+				return
+			}
+			// The cache is only an optimization, so failing to write it is not an error
+			if data, err := json.Marshal(checkCache{CheckedAt: time.Now(), Latest: release.Version()}); err == nil && cachePath != "" {
+				if os.MkdirAll(filepath.Dir(cachePath), 0755) == nil {
+					_ = os.WriteFile(cachePath, data, 0644)
+				}
+			}
+			done <- release.Version()
+		}()
+	}
+
+	return func() string {
+		latest := cache.Latest
+		select {
+		case latest = <-done:
+		default:
+		}
+		if !Newer(latest, current) {
+			return ""
+		}
+		return fmt.Sprintf("gitmit %s is available (you have %s); run gitmit update", latest, current)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package update
+
+import (
+	"fmt"
+	"os"
+)
+
+// replace puts binary in place of exe. Renaming over a running binary is safe:
+// the running process keeps the old file open until it exits.
+func replace(exe string, binary []byte) error {
+	newPath, err := writeNew(exe, binary)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(newPath, exe); err != nil {
+		os.Remove(newPath)
+		return fmt.Errorf("error replacing %s: %w", exe, err)
+	}
+	return nil
+}
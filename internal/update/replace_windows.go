@@ -0,0 +1,31 @@
+//go:build windows
+
+package update
+
+import (
+	"fmt"
+	"os"
+)
+
+// replace puts binary in place of exe. Windows cannot overwrite a running
+// binary but can rename it, so the old binary is moved aside to exe.old,
+// which the next update removes.
+func replace(exe string, binary []byte) error {
+	newPath, err := writeNew(exe, binary)
+	if err != nil {
+		return err
+	}
+	old := exe + ".old"
+	os.Remove(old)
+	if err := os.Rename(exe, old); err != nil {
+		os.Remove(newPath)
+		return fmt.Errorf("error moving %s aside: %w", exe, err)
+	}
+	if err := os.Rename(newPath, exe); err != nil {
+		// Put the old binary back, so gitmit keeps working
+		os.Rename(old, exe)
+		os.Remove(newPath)
+		return fmt.Errorf("error replacing %s: %w", exe, err)
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+// Package update finds newer gitmit releases on GitHub and replaces the
+// running binary with one, after verifying its checksum.
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// repository is the GitHub repository gitmit is released from
+const repository = "andev0x/gitmit"
+
+// apiURL is the GitHub API; tests point it at a local server
+var apiURL = "https://api.github.com"
+
+// maxDownloadSize bounds the archives and checksum files read from a release
+const maxDownloadSize = 100 << 20
+
+// requestTimeout bounds each request to GitHub
+const requestTimeout = 60 * time.Second
+
+// ErrNoAsset is the error of a release without a build for this platform
+var ErrNoAsset = errors.New("no release archive for this platform")
+
+// Release is a published release of gitmit
+type Release struct {
+	Tag    string  `json:"tag_name"`
+	URL    string  `json:"html_url"`
+	Assets []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+	Size int64  `json:"size"`
+}
+
+// Version returns the version of the release without the leading v
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.Tag, "v")
+}
+
+// Latest returns the latest release of gitmit
+func Latest(ctx context.Context) (*Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", apiURL, repository)
+	data, err := get(ctx, url, "application/vnd.github+json")
+	if err != nil {
+		return nil, fmt.Errorf("error checking the latest release: %w", err)
+	}
+	var release Release
+	if err := json.Unmarshal(data, &release); err != nil {
+		return nil, fmt.Errorf("error decoding the latest release: %w", err)
+	}
+	if release.Tag == "" {
+		return nil, fmt.Errorf("error checking the latest release: no tag in the answer of %s", url)
+	}
+	return &release, nil
+}
+
+// Newer reports whether version is a newer release than current. Versions
+// that are not numbered, such as "dev" builds, are never older or newer.
+func Newer(version, current string) bool {
+	v, c := versionNumbers(version), versionNumbers(current)
+	if v == nil || c == nil {
+		return false
+	}
+	for i := 0; i < len(v) || i < len(c); i++ {
+		var a, b int
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(c) {
+			b = c[i]
+		}
+		if a != b {
+			return a > b
+		}
+	}
+	return false
+}
+
+// versionNumbers returns the numbers of a version such as v1.2.3 or 1.2.3-rc1,
+// or nil when it does not start with a number
+func versionNumbers(version string) []int {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	version, _, _ = strings.Cut(version, "-")
+	var numbers []int
+	for _, part := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
+
+// archNames are the names release archives may use for each GOARCH
+var archNames = map[string][]string{
+	"amd64": {"amd64", "x86_64"},
+	"386":   {"386", "i386"},
+	"arm64": {"arm64", "aarch64"},
+}
+
+// Archive returns the release archive of a platform, such as
+// gitmit_1.2.0_linux_amd64.tar.gz
+func (r *Release) Archive(goos, goarch string) (*Asset, error) {
+	arches := archNames[goarch]
+	if arches == nil {
+		arches = []string{goarch}
+	}
+	for i := range r.Assets {
+		name := strings.ToLower(r.Assets[i].Name)
+		if !strings.HasSuffix(name, ".tar.gz") && !strings.HasSuffix(name, ".zip") {
+			continue
+		}
+		for _, arch := range arches {
+			if strings.Contains(name, "_"+goos+"_"+arch+".") || strings.Contains(name, "_"+goos+"_"+arch+"_") {
+				return &r.Assets[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %s/%s in %s", ErrNoAsset, goos, goarch, r.Tag)
+}
+
+// checksums returns the checksum file of the release
+func (r *Release) checksums() (*Asset, error) {
+	for i := range r.Assets {
+		if strings.HasSuffix(strings.ToLower(r.Assets[i].Name), "checksums.txt") {
+			return &r.Assets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("release %s has no checksums.txt to verify the download", r.Tag)
+}
+
+// Install downloads the archive of a platform, verifies it against the
+// checksums of the release and replaces the binary at exe with the one inside
+func Install(ctx context.Context, r *Release, goos, goarch, exe string) error {
+	archive, err := r.Archive(goos, goarch)
+	if err != nil {
+		return err
+	}
+	sums, err := r.checksums()
+	if err != nil {
+		return err
+	}
+
+	data, err := get(ctx, archive.URL, "application/octet-stream")
+	if err != nil {
+		return fmt.Errorf("error downloading %s: %w", archive.Name, err)
+	}
+	sumData, err := get(ctx, sums.URL, "text/plain")
+	if err != nil {
+		return fmt.Errorf("error downloading %s: %w", sums.Name, err)
+	}
+	if err := verifyChecksum(data, archive.Name, sumData); err != nil {
+		return err
+	}
+
+	binaryName := "gitmit"
+	if goos == "windows" {
+		binaryName += ".exe"
+	}
+	binary, err := extract(data, archive.Name, binaryName)
+	if err != nil {
+		return err
+	}
+	return replace(exe, binary)
+}
+
+// verifyChecksum checks data against its SHA-256 line in a checksums file
+func verifyChecksum(data []byte, name string, checksums []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+			return fmt.Errorf("checksum mismatch for %s: the download is corrupt or was tampered with", name)
+		}
+		return nil
+	}
+	return fmt.Errorf("no checksum for %s in the release", name)
+}
+
+// extract returns the file named binary from a .tar.gz or .zip archive
+func extract(data []byte, archiveName, binary string) ([]byte, error) {
+	if strings.HasSuffix(strings.ToLower(archiveName), ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", archiveName, err)
+		}
+		for _, f := range zr.File {
+			if path.Base(f.Name) != binary || f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("error reading %s: %w", archiveName, err)
+			}
+			defer rc.Close()
+			return readAll(rc, archiveName)
+		}
+		return nil, fmt.Errorf("no %s in %s", binary, archiveName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", archiveName, err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no %s in %s", binary, archiveName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", archiveName, err)
+		}
+		if header.Typeflag == tar.TypeReg && path.Base(header.Name) == binary {
+			return readAll(tr, archiveName)
+		}
+	}
+}
+
+// readAll reads a file of an archive, up to maxDownloadSize
+func readAll(r io.Reader, archiveName string) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxDownloadSize))
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", archiveName, err)
+	}
+	return data, nil
+}
+
+// writeNew writes binary as an executable temporary file next to exe, so it
+// can be renamed over it, and returns its path
+func writeNew(exe string, binary []byte) (string, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(exe), "."+filepath.Base(exe)+".*.new")
+	if err != nil {
+		return "", fmt.Errorf("error writing the new binary next to %s: %w", exe, err)
+	}
+	_, err = tmp.Write(binary)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0755)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("error writing the new binary next to %s: %w", exe, err)
+	}
+	return tmp.Name(), nil
+}
+
+// get fetches a URL, failing on statuses other than 200
+func get(ctx context.Context, url, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	resp, err := (&http.Client{Timeout: requestTimeout}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status code %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize))
+}
@@ -0,0 +1,119 @@
+package update
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		version, current string
+		want             bool
+	}{
+		{"1.1.0", "1.0.6", true},
+		{"v1.0.10", "1.0.9", true},
+		{"1.0.6", "1.0.6", false},
+		{"1.0", "1.0.1", false},
+		{"2.0.0-rc1", "1.9.9", true},
+		{"1.1.0", "dev", false},
+		{"", "1.0.6", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.version, tt.current); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %t, want %t", tt.version, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestArchive(t *testing.T) {
+	r := &Release{Tag: "v1.1.0", Assets: []Asset{
+		{Name: "gitmit_1.1.0_checksums.txt"},
+		{Name: "gitmit_1.1.0_darwin_arm64.tar.gz"},
+		{Name: "gitmit_1.1.0_Linux_x86_64.tar.gz"},
+		{Name: "gitmit_1.1.0_windows_amd64.zip"},
+	}}
+	for platform, want := range map[[2]string]string{
+		{"darwin", "arm64"}:  "gitmit_1.1.0_darwin_arm64.tar.gz",
+		{"linux", "amd64"}:   "gitmit_1.1.0_Linux_x86_64.tar.gz",
+		{"windows", "amd64"}: "gitmit_1.1.0_windows_amd64.zip",
+	} {
+		asset, err := r.Archive(platform[0], platform[1])
+		if err != nil || asset.Name != want {
+			t.Errorf("Archive(%s) = %v, %v, want %s", platform, asset, err, want)
+		}
+	}
+	if _, err := r.Archive("freebsd", "amd64"); !errors.Is(err, ErrNoAsset) {
+		t.Errorf("Archive() of a missing platform = %v, want ErrNoAsset", err)
+	}
+}
+
+// tarGz builds a .tar.gz archive holding one file
+func tarGz(t *testing.T, name string, content []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	tw.Write(content)
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestInstall(t *testing.T) {
+	archive := tarGz(t, "gitmit", []byte("new binary"))
+	sum := sha256.Sum256(archive)
+	checksums := fmt.Sprintf("%s  gitmit_1.1.0_linux_amd64.tar.gz\n", hex.EncodeToString(sum[:]))
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/repos/andev0x/gitmit/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"tag_name": "v1.1.0", "assets": [
+			{"name": "gitmit_1.1.0_linux_amd64.tar.gz", "browser_download_url": "%[1]s/archive"},
+			{"name": "gitmit_1.1.0_checksums.txt", "browser_download_url": "%[1]s/checksums"}]}`, server.URL)
+	})
+	mux.HandleFunc("/archive", func(w http.ResponseWriter, r *http.Request) { w.Write(archive) })
+	mux.HandleFunc("/checksums", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(checksums)) })
+	defer func(url string) { apiURL = url }(apiURL)
+	apiURL = server.URL
+
+	release, err := Latest(context.Background())
+	if err != nil || release.Version() != "1.1.0" {
+		t.Fatalf("Latest() = %+v, %v", release, err)
+	}
+	exe := filepath.Join(t.TempDir(), "gitmit")
+	if err := os.WriteFile(exe, []byte("old binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Install(context.Background(), release, "linux", "amd64", exe); err != nil {
+		t.Fatalf("Install() = %v", err)
+	}
+	if data, _ := os.ReadFile(exe); string(data) != "new binary" {
+		t.Errorf("binary = %q, want it replaced", data)
+	}
+
+	// A download that does not match its checksum is not installed
+	archive = tarGz(t, "gitmit", []byte("tampered binary"))
+	err = Install(context.Background(), release, "linux", "amd64", exe)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Install() of a tampered archive = %v, want a checksum mismatch", err)
+	}
+	if data, _ := os.ReadFile(exe); string(data) != "new binary" {
+		t.Errorf("binary = %q after a failed update, want it kept", data)
+	}
+}
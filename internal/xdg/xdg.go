@@ -0,0 +1,66 @@
+package xdg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// appName is the directory name used below each XDG base directory
+const appName = "gitmit"
+
+// ConfigDir returns gitmit's config directory: $XDG_CONFIG_HOME/gitmit (default ~/.config/gitmit)
+func ConfigDir() (string, error) {
+	return appDir("XDG_CONFIG_HOME", ".config")
+}
+
+// StateDir returns gitmit's state directory: $XDG_STATE_HOME/gitmit (default ~/.local/state/gitmit)
+func StateDir() (string, error) {
+	return appDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+}
+
+// CacheDir returns gitmit's cache directory: $XDG_CACHE_HOME/gitmit (default ~/.cache/gitmit)
+func CacheDir() (string, error) {
+	return appDir("XDG_CACHE_HOME", ".cache")
+}
+
+// ConfigFile returns the first of names that exists in gitmit's config
+// directory, or else the first of legacyNames that exists in the home
+// directory, where older versions kept it. It returns an empty string when
+// there is neither.
+func ConfigFile(names, legacyNames []string) string {
+	if dir, err := ConfigDir(); err == nil {
+		if path := findFile(dir, names); path != "" {
+			return path
+		}
+	}
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		return findFile(homeDir, legacyNames)
+	}
+	return ""
+}
+
+// findFile returns the first of names that exists in dir
+func findFile(dir string, names []string) string {
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// appDir resolves an XDG base directory from env, falling back to a path in the home directory.
+// Relative paths in the environment are ignored, as required by the XDG spec.
+func appDir(envVar, homeFallback string) (string, error) {
+	if base := os.Getenv(envVar); base != "" && filepath.IsAbs(base) {
+		return filepath.Join(base, appName), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting home directory: %w", err)
+	}
+	return filepath.Join(homeDir, homeFallback, appName), nil
+}
@@ -0,0 +1,82 @@
+package xdg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigFile(t *testing.T) {
+	names := []string{"config.json", "config.yaml"}
+	legacyNames := []string{".gitmit.json", ".gitmit.yaml"}
+
+	tests := []struct {
+		name   string
+		create []string // Files to create, relative to HOME
+		want   string   // Path returned, relative to HOME
+	}{
+		{name: "neither", want: ""},
+		{name: "old path", create: []string{".gitmit.yaml"}, want: ".gitmit.yaml"},
+		{name: "new path", create: []string{"xdg/gitmit/config.yaml"}, want: "xdg/gitmit/config.yaml"},
+		{name: "both", create: []string{".gitmit.json", "xdg/gitmit/config.yaml"}, want: "xdg/gitmit/config.yaml"},
+		{name: "first name wins", create: []string{"xdg/gitmit/config.yaml", "xdg/gitmit/config.json"}, want: "xdg/gitmit/config.json"},
+		{name: "other file in the config directory", create: []string{"xdg/gitmit/.gitmit.json", ".gitmit.json"}, want: ".gitmit.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg"))
+			for _, name := range tt.create {
+				path := filepath.Join(home, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want := ""
+			if tt.want != "" {
+				want = filepath.Join(home, tt.want)
+			}
+			if got := ConfigFile(names, legacyNames); got != want {
+				t.Errorf("ConfigFile() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestAppDirs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name  string
+		env   string
+		value string
+		dir   func() (string, error)
+		want  string
+	}{
+		{name: "config from env", env: "XDG_CONFIG_HOME", value: "/xdg/config", dir: ConfigDir, want: "/xdg/config/gitmit"},
+		{name: "config default", env: "XDG_CONFIG_HOME", dir: ConfigDir, want: filepath.Join(home, ".config", "gitmit")},
+		{name: "config relative env ignored", env: "XDG_CONFIG_HOME", value: "config", dir: ConfigDir, want: filepath.Join(home, ".config", "gitmit")},
+		{name: "state from env", env: "XDG_STATE_HOME", value: "/xdg/state", dir: StateDir, want: "/xdg/state/gitmit"},
+		{name: "state default", env: "XDG_STATE_HOME", dir: StateDir, want: filepath.Join(home, ".local", "state", "gitmit")},
+		{name: "cache from env", env: "XDG_CACHE_HOME", value: "/xdg/cache", dir: CacheDir, want: "/xdg/cache/gitmit"},
+		{name: "cache default", env: "XDG_CACHE_HOME", dir: CacheDir, want: filepath.Join(home, ".cache", "gitmit")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.env, tt.value)
+			got, err := tt.dir()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != filepath.FromSlash(tt.want) {
+				t.Errorf("dir = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,11 +5,15 @@ import (
 	"os"
 
 	"github.com/andev0x/gitmit/cmd"
+	"github.com/andev0x/gitmit/internal/ui"
 )
 
 func main() {
 	if err := cmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %s\n", ui.Text(err.Error()))
+		if hint := cmd.Hint(err); hint != "" {
+			fmt.Fprintln(os.Stderr, ui.MutedString("%s", hint))
+		}
 		os.Exit(1)
 	}
 }